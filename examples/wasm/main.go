@@ -0,0 +1,67 @@
+//go:build js && wasm
+
+// Command wasm exposes kjvcorpus reference resolution to JavaScript. It registers two globals:
+// kjvOpen(bytes), which opens a canon packed by kjvsrc pack from a Uint8Array, and
+// kjvResolve(ref), which returns the resolved passage as the JSON string kjvcorpus.Resolved
+// marshals to. Both return an object with an error field on failure.
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+var corpus *kjvcorpus.Corpus
+
+func main() {
+	js.Global().Set("kjvOpen", js.FuncOf(open))
+	js.Global().Set("kjvResolve", js.FuncOf(resolve))
+	select {}
+}
+
+// open opens the packed canon in args[0], a Uint8Array
+func open(_ js.Value, args []js.Value) any {
+	if len(args) != 1 {
+		return failure("kjvOpen takes the packed canon as a Uint8Array")
+	}
+	data := make([]byte, args[0].Get("length").Int())
+	js.CopyBytesToGo(data, args[0])
+
+	opened, err := kjvcorpus.OpenPacked(data)
+	if err != nil {
+		return failure(err.Error())
+	}
+	corpus = opened
+	return js.Null()
+}
+
+// resolve resolves the reference in args[0], such as "John 3:16-18"
+func resolve(_ js.Value, args []js.Value) any {
+	if corpus == nil {
+		return failure("call kjvOpen first")
+	}
+	if len(args) != 1 {
+		return failure("kjvResolve takes one reference")
+	}
+
+	ref, err := corpus.ParseRef(args[0].String())
+	if err != nil {
+		return failure(err.Error())
+	}
+	resolved, err := corpus.ResolveRef(ref)
+	if err != nil {
+		return failure(err.Error())
+	}
+	data, err := json.Marshal(resolved)
+	if err != nil {
+		return failure(err.Error())
+	}
+	return string(data)
+}
+
+// failure returns an error to JavaScript as {error: msg}
+func failure(msg string) any {
+	return map[string]any{"error": msg}
+}
@@ -0,0 +1,156 @@
+package analyze
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Kinds of added-word suspect
+const (
+	SuspectEmptyAdd   = "empty"       // an add token without a letter, only whitespace or punctuation
+	SuspectWholeVerse = "whole-verse" // every word of the verse is added, as when an add's closing markup was lost
+)
+
+// AddedWord is an add token: words the translators supplied for sense, set in italics
+type AddedWord struct {
+	OSIS    string `json:"osis"`
+	Chapter int    `json:"chapter"`
+	V       int    `json:"v"`
+	Text    string `json:"text"`
+}
+
+// AddedWordCount is the added words of one scope: a book's OSIS code, or "canon"
+type AddedWordCount struct {
+	Scope    string  `json:"scope"`
+	Tokens   int     `json:"tokens"`   // add tokens
+	Words    int     `json:"words"`    // words in add tokens
+	Share    float64 `json:"share"`    // fraction of the scope's words that are added
+	Verses   int     `json:"verses"`   // verses with at least one add token
+	Suspects int     `json:"suspects"` // verses with at least one suspect
+}
+
+// AddedWordAudit is every add token of the canon, counted per book, and the verses whose added
+// words look wrong
+type AddedWordAudit struct {
+	Books    []AddedWordCount `json:"books"` // in the order the books were first added
+	Canon    AddedWordCount   `json:"canon"`
+	Added    []AddedWord      `json:"added"`
+	Suspects []Suspect        `json:"suspects"`
+}
+
+// String summarizes the audit for the verify canon report, such as
+// "21497 tokens (27077 words, 2.9% of the text) in 14258 verses, 1 suspect verses"
+func (a *AddedWordAudit) String() string {
+	return fmt.Sprintf("%d tokens (%d words, %.1f%% of the text) in %d verses, %d suspect verses",
+		a.Canon.Tokens, a.Canon.Words, a.Canon.Share*100, a.Canon.Verses, a.Canon.Suspects)
+}
+
+// addedWordTally accumulates the counts of one scope
+type addedWordTally struct {
+	tokens, words, total, verses, suspects int
+}
+
+func (t *addedWordTally) count(scope string) AddedWordCount {
+	c := AddedWordCount{Scope: scope, Tokens: t.tokens, Words: t.words, Verses: t.verses, Suspects: t.suspects}
+	if t.total > 0 {
+		c.Share = float64(t.words) / float64(t.total)
+	}
+	return c
+}
+
+// AddedWordAuditor audits chapters one at a time, for callers such as verify canon that read
+// chapter files themselves
+type AddedWordAuditor struct {
+	books    []string
+	tallies  map[string]*addedWordTally
+	canon    addedWordTally
+	added    []AddedWord
+	suspects []Suspect
+}
+
+// NewAddedWordAuditor creates an auditor with no chapters added
+func NewAddedWordAuditor() *AddedWordAuditor {
+	return &AddedWordAuditor{tallies: make(map[string]*addedWordTally)}
+}
+
+// Add records the add tokens of a chapter and returns its suspects, in verse order
+func (a *AddedWordAuditor) Add(ch *model.Chapter) []Suspect {
+	book, exists := a.tallies[ch.OSIS]
+	if !exists {
+		book = &addedWordTally{}
+		a.tallies[ch.OSIS] = book
+		a.books = append(a.books, ch.OSIS)
+	}
+
+	var suspects []Suspect
+	for _, verse := range ch.Verses {
+		tokens, words, total := 0, 0, 0
+		unadded := false // whether any word of the verse is outside an add token
+		var found []Suspect
+		for _, token := range verse.Tokens {
+			if token.Add == "" {
+				n := len(Words(token.Text + token.ND))
+				total += n
+				unadded = unadded || n > 0
+				continue
+			}
+
+			n := len(Words(token.Add))
+			tokens++
+			words += n
+			total += n
+			a.added = append(a.added, AddedWord{OSIS: ch.OSIS, Chapter: ch.Chapter, V: verse.V, Text: token.Add})
+			if strings.IndexFunc(token.Add, unicode.IsLetter) < 0 {
+				found = append(found, Suspect{OSIS: ch.OSIS, Chapter: ch.Chapter, V: verse.V, Kind: SuspectEmptyAdd, Text: token.Add})
+			}
+		}
+		if tokens > 0 && !unadded {
+			found = append(found, Suspect{OSIS: ch.OSIS, Chapter: ch.Chapter, V: verse.V, Kind: SuspectWholeVerse, Text: verse.Plain})
+		}
+
+		for _, t := range []*addedWordTally{book, &a.canon} {
+			t.tokens += tokens
+			t.words += words
+			t.total += total
+			if tokens > 0 {
+				t.verses++
+			}
+			if len(found) > 0 {
+				t.suspects++
+			}
+		}
+		suspects = append(suspects, found...)
+	}
+	a.suspects = append(a.suspects, suspects...)
+	return suspects
+}
+
+// Audit returns the counts, add tokens, and suspects of every chapter added so far
+func (a *AddedWordAuditor) Audit() *AddedWordAudit {
+	audit := &AddedWordAudit{
+		Books:    make([]AddedWordCount, 0, len(a.books)),
+		Canon:    a.canon.count(string(ByCanon)),
+		Added:    append([]AddedWord{}, a.added...),
+		Suspects: append([]Suspect{}, a.suspects...),
+	}
+	for _, osis := range a.books {
+		audit.Books = append(audit.Books, a.tallies[osis].count(osis))
+	}
+	return audit
+}
+
+// AddedWords audits the added words of every chapter in canonical order
+func AddedWords(corpus *kjvcorpus.Corpus) (*AddedWordAudit, error) {
+	auditor := NewAddedWordAuditor()
+	for ch, err := range corpus.Chapters() {
+		if err != nil {
+			return nil, err
+		}
+		auditor.Add(ch)
+	}
+	return auditor.Audit(), nil
+}
@@ -0,0 +1,61 @@
+package analyze
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestAddedWordAuditor(t *testing.T) {
+	auditor := NewAddedWordAuditor()
+	suspects := auditor.Add(&model.Chapter{OSIS: "Gen", Chapter: 1, Verses: []model.Verse{
+		{V: 1, Tokens: []model.Token{{Text: "the light, that "}, {Add: "it was"}, {Text: " good"}}},
+		{V: 2, Tokens: []model.Token{{Text: "and "}, {Add: " , "}, {Text: " void"}}},
+		{V: 3, Plain: "which was", Tokens: []model.Token{{Text: "¶ "}, {Add: "which was"}}},
+	}})
+	if len(suspects) != 2 || suspects[0].Kind != SuspectEmptyAdd || suspects[1].Kind != SuspectWholeVerse || suspects[1].V != 3 {
+		t.Fatalf("unexpected suspects: %v", suspects)
+	}
+
+	audit := auditor.Audit()
+	if c := audit.Canon; c.Tokens != 3 || c.Words != 4 || c.Verses != 3 || c.Suspects != 2 || c.Share != 0.4 {
+		t.Errorf("unexpected counts: %+v", c)
+	}
+	if len(audit.Added) != 3 || audit.Added[0] != (AddedWord{OSIS: "Gen", Chapter: 1, V: 1, Text: "it was"}) {
+		t.Errorf("unexpected added words: %+v", audit.Added)
+	}
+}
+
+func TestAddedWords(t *testing.T) {
+	corpus := openCorpus(t)
+
+	audit, err := AddedWords(corpus)
+	if err != nil {
+		t.Fatalf("AddedWords failed: %v", err)
+	}
+	if audit.Books[0].Scope != "Gen" || audit.Canon.Tokens != len(audit.Added) || audit.Canon.Share <= 0 || audit.Canon.Share >= 0.1 {
+		t.Errorf("unexpected audit: %s", audit)
+	}
+	if first := audit.Added[0]; first.OSIS != "Gen" || first.Chapter != 1 || first.V != 2 || first.Text != "was" {
+		t.Errorf("unexpected first added word: %+v", first)
+	}
+
+	dir := t.TempDir()
+	paths, err := WriteAddedWords(dir, FormatCSV, audit)
+	if err != nil {
+		t.Fatalf("WriteAddedWords failed: %v", err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("expected 3 files, got %v", paths)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "added_word_tokens.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "osis,chapter,verse,text\nGen,1,2,was\n") {
+		t.Errorf("unexpected added_word_tokens.csv: %.80s", data)
+	}
+}
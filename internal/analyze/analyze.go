@@ -0,0 +1,185 @@
+package analyze
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+	"github.com/julianstephens/kjv-sources/pkg/normalize"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+// Grouping is the scope statistics are gathered over
+type Grouping string
+
+const (
+	ByBook      Grouping = "book"      // one table per book
+	ByTestament Grouping = "testament" // one table per testament
+	ByCanon     Grouping = "canon"     // one table for the whole canon
+)
+
+// Groupings lists the groupings
+var Groupings = []Grouping{ByBook, ByTestament, ByCanon}
+
+// Options controls what Analyze counts
+type Options struct {
+	N         int                   // n-gram length, at least 2
+	Top       int                   // keep only the most frequent words and n-grams; 0 keeps all
+	Normalize *normalize.Normalizer // if set, words are counted in their modern forms, so "thou" counts as "you"
+}
+
+// Count is a word or n-gram and how often it occurs
+type Count struct {
+	Item  string `json:"item"`
+	Count int    `json:"count"`
+}
+
+// Table is the statistics for one scope: a book's OSIS code, a testament, or "canon"
+type Table struct {
+	Scope  string   `json:"scope"`
+	Tokens int      `json:"tokens"` // running words
+	Types  int      `json:"types"`  // distinct words
+	Words  []Count  `json:"words"`  // word frequencies, most frequent first
+	NGrams []Count  `json:"ngrams"` // n-gram frequencies, most frequent first
+	Hapax  []string `json:"hapax"`  // words occurring exactly once in the scope, sorted
+}
+
+// Analyze counts word frequencies, n-grams, and hapax legomena over the canon, one table per
+// scope of the grouping in canonical order. N-grams do not cross verse boundaries.
+func Analyze(corpus *kjvcorpus.Corpus, by Grouping, opts Options) ([]Table, error) {
+	if opts.N < 2 {
+		return nil, fmt.Errorf("n-gram length must be at least 2, got %d", opts.N)
+	}
+	switch by {
+	case ByBook, ByTestament, ByCanon:
+	default:
+		return nil, fmt.Errorf("unknown grouping %q", by)
+	}
+
+	var scopes []string
+	counters := make(map[string]*counter)
+	scopeOf := func(ch *model.Chapter) string {
+		switch by {
+		case ByBook:
+			return ch.OSIS
+		case ByTestament:
+			if t, ok := testament.Of(ch.OSIS); ok {
+				return string(t)
+			}
+			return "unknown"
+		default:
+			return string(ByCanon)
+		}
+	}
+
+	for ch, err := range corpus.Chapters() {
+		if err != nil {
+			return nil, err
+		}
+		scope := scopeOf(ch)
+		c, exists := counters[scope]
+		if !exists {
+			c = newCounter()
+			counters[scope] = c
+			scopes = append(scopes, scope)
+		}
+		for _, verse := range ch.Verses {
+			words := Words(verse.Plain)
+			if opts.Normalize != nil {
+				for i, word := range words {
+					words[i] = opts.Normalize.Word(word)
+				}
+			}
+			c.add(words, opts.N)
+		}
+	}
+
+	tables := make([]Table, 0, len(scopes))
+	for _, scope := range scopes {
+		tables = append(tables, counters[scope].table(scope, opts.Top))
+	}
+	return tables, nil
+}
+
+// Words splits text into lowercase words. Apostrophes and hyphens inside a word are kept, as in
+// "man's" and "beer-sheba"; other punctuation and the pilcrow paragraph mark are dropped.
+func Words(text string) []string {
+	var words []string
+	runes := []rune(strings.ToLower(text))
+	start := -1
+	for i, r := range runes {
+		inner := (r == '\'' || r == '’' || r == '-') && start >= 0 && i+1 < len(runes) && unicode.IsLetter(runes[i+1])
+		if unicode.IsLetter(r) || inner {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			words = append(words, string(runes[start:i]))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// counter accumulates word and n-gram counts for one scope
+type counter struct {
+	tokens int
+	words  map[string]int
+	ngrams map[string]int
+}
+
+func newCounter() *counter {
+	return &counter{words: make(map[string]int), ngrams: make(map[string]int)}
+}
+
+// add counts one verse's words and the n-grams within it
+func (c *counter) add(words []string, n int) {
+	c.tokens += len(words)
+	for _, word := range words {
+		c.words[word]++
+	}
+	for i := 0; i+n <= len(words); i++ {
+		c.ngrams[strings.Join(words[i:i+n], " ")]++
+	}
+}
+
+// table sorts the counts into a Table, keeping the top most frequent words and n-grams
+func (c *counter) table(scope string, top int) Table {
+	t := Table{Scope: scope, Tokens: c.tokens, Types: len(c.words), Hapax: []string{}}
+	for word, count := range c.words {
+		if count == 1 {
+			t.Hapax = append(t.Hapax, word)
+		}
+	}
+	sort.Strings(t.Hapax)
+
+	t.Words = sortCounts(c.words, top)
+	t.NGrams = sortCounts(c.ngrams, top)
+	return t
+}
+
+// sortCounts orders counts by frequency, then alphabetically, keeping the first top if top > 0
+func sortCounts(counts map[string]int, top int) []Count {
+	result := make([]Count, 0, len(counts))
+	for item, count := range counts {
+		result = append(result, Count{Item: item, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Item < result[j].Item
+	})
+	if top > 0 && len(result) > top {
+		result = result[:top]
+	}
+	return result
+}
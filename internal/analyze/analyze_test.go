@@ -0,0 +1,150 @@
+package analyze
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/normalize"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"In the beginning God created the heaven and the earth.", []string{"in", "the", "beginning", "god", "created", "the", "heaven", "and", "the", "earth"}},
+		{"¶ And the LORD said, Behold, the man's sin", []string{"and", "the", "lord", "said", "behold", "the", "man's", "sin"}},
+		{"from Dan even to Beer-sheba; the sons'", []string{"from", "dan", "even", "to", "beer-sheba", "the", "sons"}},
+		{"  -- 12 ", nil},
+	}
+
+	for _, tt := range tests {
+		if got := Words(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("Words(%q) = %q; want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+// openCorpus opens the committed canon from the project root
+func openCorpus(t *testing.T) *kjvcorpus.Corpus {
+	t.Helper()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+	corpus, err := kjvcorpus.Open(filepath.Join(cwd, "canon", "kjv"))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	return corpus
+}
+
+func TestAnalyze(t *testing.T) {
+	corpus := openCorpus(t)
+
+	tables, err := Analyze(corpus, ByTestament, Options{N: 2, Top: 10})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(tables) != 3 || tables[0].Scope != "OT" || tables[1].Scope != "AP" || tables[2].Scope != "NT" {
+		t.Fatalf("expected OT, AP, and NT tables, got %d", len(tables))
+	}
+	for _, table := range tables {
+		if len(table.Words) != 10 || len(table.NGrams) != 10 {
+			t.Errorf("%s: expected the top 10 words and n-grams, got %d and %d", table.Scope, len(table.Words), len(table.NGrams))
+		}
+		if table.Words[0].Item != "the" || table.Words[0].Count < table.Words[9].Count {
+			t.Errorf("%s: expected words by descending frequency, got %+v", table.Scope, table.Words)
+		}
+		if table.Types <= len(table.Hapax) || table.Tokens <= table.Types {
+			t.Errorf("%s: inconsistent totals: %d tokens, %d types, %d hapax", table.Scope, table.Tokens, table.Types, len(table.Hapax))
+		}
+	}
+	if !slices.Contains(tables[2].Hapax, "bartimæus") {
+		t.Error("expected bartimæus among the New Testament hapax legomena")
+	}
+
+	books, err := Analyze(corpus, ByBook, Options{N: 3})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if books[0].Scope != "Gen" || !strings.Contains(books[0].NGrams[0].Item, " ") || strings.Count(books[0].NGrams[0].Item, " ") != 2 {
+		t.Errorf("expected Genesis trigrams first, got %s %q", books[0].Scope, books[0].NGrams[0].Item)
+	}
+
+	// Normalized words are counted in their modern forms
+	table, err := corpus.Normalization()
+	if err != nil {
+		t.Fatalf("Normalization failed: %v", err)
+	}
+	plain, err := Analyze(corpus, ByCanon, Options{N: 2})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	normalized, err := Analyze(corpus, ByCanon, Options{N: 2, Normalize: normalize.New(table)})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	count := func(table Table, word string) int {
+		for _, c := range table.Words {
+			if c.Item == word {
+				return c.Count
+			}
+		}
+		return 0
+	}
+	if count(plain[0], "thou") == 0 || count(normalized[0], "thou") != 0 ||
+		count(normalized[0], "you") != count(plain[0], "you")+count(plain[0], "thou")+count(plain[0], "thee")+count(plain[0], "ye") {
+		t.Errorf("expected thou, thee, and ye counted as you, got %d you", count(normalized[0], "you"))
+	}
+	if normalized[0].Tokens != plain[0].Tokens || normalized[0].Types >= plain[0].Types {
+		t.Errorf("expected the same words in fewer types, got %d/%d and %d/%d", normalized[0].Tokens, normalized[0].Types, plain[0].Tokens, plain[0].Types)
+	}
+
+	if _, err := Analyze(corpus, ByBook, Options{N: 1}); err == nil {
+		t.Error("expected n-gram length 1 to fail")
+	}
+	if _, err := Analyze(corpus, "chapter", Options{N: 2}); err == nil {
+		t.Error("expected an unknown grouping to fail")
+	}
+
+	dir := t.TempDir()
+	paths, err := Write(dir, FormatCSV, tables)
+	if err != nil || len(paths) != 3 {
+		t.Fatalf("Write csv failed: %v, %v", paths, err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "frequency.csv")) // nolint: gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "scope,word,count\nOT,the,") {
+		t.Errorf("unexpected frequency.csv: %.60s", data)
+	}
+
+	if _, err := Write(dir, FormatJSON, tables); err != nil {
+		t.Fatalf("Write json failed: %v", err)
+	}
+	data, err = os.ReadFile(filepath.Join(dir, "analysis.json")) // nolint: gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed []Table
+	if err := json.Unmarshal(data, &parsed); err != nil || len(parsed) != 3 || parsed[1].Scope != "AP" {
+		t.Errorf("unexpected analysis.json: %v", err)
+	}
+}
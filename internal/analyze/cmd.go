@@ -0,0 +1,189 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/normalize"
+)
+
+// WordsCmd writes word frequency, n-gram, and hapax legomena tables for the canon
+type WordsCmd struct {
+	Canon     string `type:"existingdir" help:"The canon directory containing index/ and books/"                    default:"./canon/kjv"`
+	Out       string `                   help:"Directory to write the tables to"                                    default:"./analysis"`
+	By        string `                   help:"Scope of each table (book, testament, or canon)"                     default:"book"        enum:"book,testament,canon"`
+	N         int    `                   help:"Length of the n-grams to count"                                      default:"2"`
+	Top       int    `                   help:"Keep only the most frequent words and n-grams per scope (0 for all)" default:"0"`
+	Format    string `                   help:"Output format (csv or json)"                                         default:"csv"         enum:"csv,json"`
+	Normalize bool   `                   help:"Count words in their modern forms from index/normalization.json, so \"thou\" counts as \"you\""`
+}
+
+// ParallelsCmd finds parallel passages across books and writes them to parallels.json
+type ParallelsCmd struct {
+	Canon     string  `type:"existingdir" help:"The canon directory containing index/ and books/"                     default:"./canon/kjv"`
+	Out       string  `                   help:"File to write the parallel passages to"                               default:"./canon/kjv/index/parallels.json"`
+	Shingle   int     `                   help:"Words per shingle"                                                    default:"3"`
+	Hashes    int     `                   help:"MinHash signature length, a multiple of --bands"                      default:"128"`
+	Bands     int     `                   help:"Locality-sensitive hashing bands (more find less similar candidates)" default:"64"`
+	Threshold float64 `                   help:"Minimum Jaccard similarity for two verses to match"                   default:"0.3"`
+	MinWords  int     `                   help:"Skip verses with fewer words, so short formulas do not match"         default:"8"`
+	MinVerses int     `                   help:"Minimum matched verses for a pair of passages to be reported"         default:"2"`
+}
+
+// DivineNamesCmd writes per-book divine-name statistics and the verses whose markup looks lost
+type DivineNamesCmd struct {
+	Canon  string `type:"existingdir" help:"The canon directory containing index/ and books/" default:"./canon/kjv"`
+	Out    string `                   help:"Directory to write the audit to"                 default:"./analysis"`
+	Format string `                   help:"Output format (csv or json)"                     default:"csv"         enum:"csv,json"`
+}
+
+// AddedWordsCmd writes every added word of the canon, its share of each book, and the verses
+// whose added words look wrong
+type AddedWordsCmd struct {
+	Canon  string `type:"existingdir" help:"The canon directory containing index/ and books/" default:"./canon/kjv"`
+	Out    string `                   help:"Directory to write the audit to"                 default:"./analysis"`
+	Format string `                   help:"Output format (csv or json)"                     default:"csv"         enum:"csv,json"`
+}
+
+// Cmd analyzes the text of the canon
+type Cmd struct {
+	Words       WordsCmd       `cmd:"" default:"withargs" help:"Write word frequency, n-gram, and hapax legomena tables"`
+	Parallels   ParallelsCmd   `cmd:""                    help:"Find parallel passages across books and write parallels.json"`
+	DivineNames DivineNamesCmd `cmd:""                    help:"Audit divine-name (nd) markup and count it per book"`
+	AddedWords  AddedWordsCmd  `cmd:""                    help:"List the added words (italics) and audit their markup per book"`
+}
+
+func (c *WordsCmd) Run(ctx context.Context) error {
+	corpus, err := kjvcorpus.Open(c.Canon)
+	if err != nil {
+		util.StopProgress(ctx)
+		return fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	opts := Options{N: c.N, Top: c.Top}
+	if c.Normalize {
+		table, err := corpus.Normalization()
+		if err != nil {
+			util.StopProgress(ctx)
+			return err
+		}
+		opts.Normalize = normalize.New(table)
+	}
+
+	tables, err := Analyze(corpus, Grouping(c.By), opts)
+	if err != nil {
+		util.StopProgress(ctx)
+		return err
+	}
+	paths, err := Write(c.Out, Format(c.Format), tables)
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	tokens := 0
+	for _, t := range tables {
+		tokens += t.Tokens
+	}
+	fmt.Printf("========================================\n")
+	fmt.Printf("Scopes: %d\n", len(tables))
+	fmt.Printf("Words Counted: %d\n", tokens)
+	for _, path := range paths {
+		fmt.Printf("Output: %s\n", path)
+	}
+	fmt.Printf("========================================\n")
+	return nil
+}
+
+func (c *ParallelsCmd) Run(ctx context.Context) error {
+	corpus, err := kjvcorpus.Open(c.Canon)
+	if err != nil {
+		util.StopProgress(ctx)
+		return fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	opts := DefaultParallelOptions
+	opts.Shingle = c.Shingle
+	opts.Hashes = c.Hashes
+	opts.Bands = c.Bands
+	opts.Threshold = c.Threshold
+	opts.MinWords = c.MinWords
+	opts.MinVerses = c.MinVerses
+
+	parallels, err := Parallels(corpus, opts)
+	if err == nil {
+		err = WriteParallels(c.Out, parallels)
+	}
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Parallel Passages: %d\n", len(parallels.Pairs))
+	fmt.Printf("Output: %s\n", c.Out)
+	fmt.Printf("========================================\n")
+	return nil
+}
+
+func (c *DivineNamesCmd) Run(ctx context.Context) error {
+	corpus, err := kjvcorpus.Open(c.Canon)
+	if err != nil {
+		util.StopProgress(ctx)
+		return fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	audit, err := DivineNames(corpus)
+	if err != nil {
+		util.StopProgress(ctx)
+		return err
+	}
+	paths, err := WriteDivineNames(c.Out, Format(c.Format), audit)
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, suspect := range audit.Suspects {
+		fmt.Printf("Suspect: %s\n", suspect)
+	}
+	fmt.Printf("========================================\n")
+	fmt.Printf("Divine Names: %s\n", audit)
+	for _, path := range paths {
+		fmt.Printf("Output: %s\n", path)
+	}
+	fmt.Printf("========================================\n")
+	return nil
+}
+
+func (c *AddedWordsCmd) Run(ctx context.Context) error {
+	corpus, err := kjvcorpus.Open(c.Canon)
+	if err != nil {
+		util.StopProgress(ctx)
+		return fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	audit, err := AddedWords(corpus)
+	if err != nil {
+		util.StopProgress(ctx)
+		return err
+	}
+	paths, err := WriteAddedWords(c.Out, Format(c.Format), audit)
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, suspect := range audit.Suspects {
+		fmt.Printf("Suspect: %s\n", suspect)
+	}
+	fmt.Printf("========================================\n")
+	fmt.Printf("Added Words: %s\n", audit)
+	for _, path := range paths {
+		fmt.Printf("Output: %s\n", path)
+	}
+	fmt.Printf("========================================\n")
+	return nil
+}
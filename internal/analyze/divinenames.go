@@ -0,0 +1,205 @@
+package analyze
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Kinds of divine-name suspect
+const (
+	SuspectText    = "text"    // "LORD" in a text token, where its nd markup was likely lost in parsing
+	SuspectAdd     = "add"     // "LORD" in added words, where an nd nested in the add was flattened
+	SuspectSpacing = "spacing" // a divine-name token with whitespace around the name
+)
+
+// Suspect is a verse whose markup may not have survived ingest, as an audit such as DivineNames
+// finds it
+type Suspect struct {
+	OSIS    string `json:"osis"`
+	Chapter int    `json:"chapter"`
+	V       int    `json:"v"`
+	Kind    string `json:"kind"`
+	Text    string `json:"text"` // the token the suspect was found in
+}
+
+// String describes the suspect as "Exod 33:9 (add): "the LORD""
+func (s Suspect) String() string {
+	return fmt.Sprintf("%s %d:%d (%s): %q", s.OSIS, s.Chapter, s.V, s.Kind, s.Text)
+}
+
+// DivineNameCount is the divine-name tokens of one scope: a book's OSIS code, or "canon"
+type DivineNameCount struct {
+	Scope    string  `json:"scope"`
+	Tokens   int     `json:"tokens"`   // nd tokens
+	Verses   int     `json:"verses"`   // verses with at least one nd token
+	Suspects int     `json:"suspects"` // verses with at least one suspect
+	Forms    []Count `json:"forms"`    // nd tokens by name, such as "LORD" and "LORD’s", most frequent first
+}
+
+// DivineNameAudit is the divine-name statistics of the canon and the verses whose markup looks
+// wrong
+type DivineNameAudit struct {
+	Books    []DivineNameCount `json:"books"` // in the order the books were first added
+	Canon    DivineNameCount   `json:"canon"`
+	Suspects []Suspect         `json:"suspects"`
+}
+
+// String summarizes the audit for the verify canon report, such as
+// "6873 tokens in 5817 verses (LORD 6452, GOD 308), 4 suspect verses"
+func (a *DivineNameAudit) String() string {
+	forms := make([]string, len(a.Canon.Forms))
+	for i, form := range a.Canon.Forms {
+		forms[i] = fmt.Sprintf("%s %d", form.Item, form.Count)
+	}
+	return fmt.Sprintf("%d tokens in %d verses (%s), %d suspect verses",
+		a.Canon.Tokens, a.Canon.Verses, strings.Join(forms, ", "), a.Canon.Suspects)
+}
+
+// divineNameTally accumulates the counts of one scope
+type divineNameTally struct {
+	tokens, verses, suspects int
+	forms                    map[string]int
+}
+
+func (t *divineNameTally) count(scope string) DivineNameCount {
+	return DivineNameCount{Scope: scope, Tokens: t.tokens, Verses: t.verses, Suspects: t.suspects, Forms: sortCounts(t.forms, 0)}
+}
+
+// DivineNameAuditor audits chapters one at a time, for callers such as verify canon that read
+// chapter files themselves
+type DivineNameAuditor struct {
+	books    []string
+	tallies  map[string]*divineNameTally
+	canon    divineNameTally
+	suspects []Suspect
+}
+
+// NewDivineNameAuditor creates an auditor with no chapters added
+func NewDivineNameAuditor() *DivineNameAuditor {
+	return &DivineNameAuditor{tallies: make(map[string]*divineNameTally), canon: divineNameTally{forms: make(map[string]int)}}
+}
+
+// Add counts the divine-name tokens of a chapter and returns its suspects, in verse order
+func (a *DivineNameAuditor) Add(ch *model.Chapter) []Suspect {
+	book, exists := a.tallies[ch.OSIS]
+	if !exists {
+		book = &divineNameTally{forms: make(map[string]int)}
+		a.tallies[ch.OSIS] = book
+		a.books = append(a.books, ch.OSIS)
+	}
+
+	var suspects []Suspect
+	for _, verse := range ch.Verses {
+		names := 0
+		var found []Suspect
+		for _, token := range verse.Tokens {
+			kind := ""
+			switch {
+			case token.ND != "":
+				name := strings.TrimSpace(token.ND)
+				names++
+				book.forms[name]++
+				a.canon.forms[name]++
+				if name != token.ND {
+					kind = SuspectSpacing
+				}
+			case token.Add != "" && hasBareLord(token.Add):
+				kind = SuspectAdd
+			case token.Text != "" && hasBareLord(token.Text):
+				kind = SuspectText
+			}
+			if kind != "" {
+				found = append(found, Suspect{
+					OSIS:    ch.OSIS,
+					Chapter: ch.Chapter,
+					V:       verse.V,
+					Kind:    kind,
+					Text:    token.Text + token.Add + token.ND,
+				})
+			}
+		}
+
+		book.tokens += names
+		a.canon.tokens += names
+		if names > 0 {
+			book.verses++
+			a.canon.verses++
+		}
+		if len(found) > 0 {
+			book.suspects++
+			a.canon.suspects++
+		}
+		suspects = append(suspects, found...)
+	}
+	a.suspects = append(a.suspects, suspects...)
+	return suspects
+}
+
+// Audit returns the counts and suspects of every chapter added so far
+func (a *DivineNameAuditor) Audit() *DivineNameAudit {
+	audit := &DivineNameAudit{
+		Books:    make([]DivineNameCount, 0, len(a.books)),
+		Canon:    a.canon.count(string(ByCanon)),
+		Suspects: append([]Suspect{}, a.suspects...),
+	}
+	for _, osis := range a.books {
+		audit.Books = append(audit.Books, a.tallies[osis].count(osis))
+	}
+	return audit
+}
+
+// DivineNames audits the divine-name markup of every chapter in canonical order
+func DivineNames(corpus *kjvcorpus.Corpus) (*DivineNameAudit, error) {
+	auditor := NewDivineNameAuditor()
+	for ch, err := range corpus.Chapters() {
+		if err != nil {
+			return nil, err
+		}
+		auditor.Add(ch)
+	}
+	return auditor.Audit(), nil
+}
+
+// divineNames are the names the source sets in small capitals
+var divineNames = map[string]bool{"LORD": true, "LORD’s": true, "LORD's": true, "GOD": true, "JEHOVAH": true}
+
+// hasBareLord reports whether text has "LORD", or "LORD’s", as a word of its own. Other capitals
+// beside it mark an inscription, as "KING OF KINGS, AND LORD OF LORDS", which the source never
+// marks as a divine name, so those are not counted.
+func hasBareLord(text string) bool {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\'' && r != '’'
+	})
+	for i, word := range words {
+		if word != "LORD" && word != "LORD’s" && word != "LORD's" {
+			continue
+		}
+		if (i > 0 && capitals(words[i-1])) || (i+1 < len(words) && capitals(words[i+1])) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// capitals reports whether word is two or more letters, all capitals, and not itself a divine name
+func capitals(word string) bool {
+	if divineNames[word] {
+		return false
+	}
+	letters := 0
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if !unicode.IsUpper(r) {
+			return false
+		}
+		letters++
+	}
+	return letters >= 2
+}
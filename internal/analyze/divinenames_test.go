@@ -0,0 +1,85 @@
+package analyze
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestHasBareLord(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"and the LORD said", true},
+		{"the LORD’s passover", true},
+		{"the LORD GOD", true},
+		{"KING OF KINGS, AND LORD OF LORDS.", false},
+		{"the Lord said", false},
+		{"the LORDS of the Philistines", false},
+	}
+	for _, tt := range tests {
+		if got := hasBareLord(tt.text); got != tt.want {
+			t.Errorf("hasBareLord(%q) = %v; want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDivineNameAuditor(t *testing.T) {
+	auditor := NewDivineNameAuditor()
+	suspects := auditor.Add(&model.Chapter{OSIS: "Ps", Chapter: 1, Verses: []model.Verse{
+		{V: 1, Tokens: []model.Token{{Text: "O "}, {ND: "LORD"}, {Text: ", the LORD is good"}}},
+		{V: 2, Tokens: []model.Token{{Text: "praise "}, {ND: " LORD"}, {Add: "of the LORD"}}},
+		{V: 3, Tokens: []model.Token{{Text: "the name of the "}, {ND: "LORD’s"}}},
+	}})
+	if len(suspects) != 3 || suspects[0].Kind != SuspectText || suspects[1].Kind != SuspectSpacing || suspects[2].Kind != SuspectAdd {
+		t.Fatalf("unexpected suspects: %v", suspects)
+	}
+	if got := suspects[2].String(); got != `Ps 1:2 (add): "of the LORD"` {
+		t.Errorf("unexpected description %s", got)
+	}
+
+	audit := auditor.Audit()
+	if c := audit.Canon; c.Tokens != 3 || c.Verses != 3 || c.Suspects != 2 || len(c.Forms) != 2 || c.Forms[0] != (Count{"LORD", 2}) {
+		t.Errorf("unexpected counts: %+v", c)
+	}
+	if len(audit.Books) != 1 || audit.Books[0].Scope != "Ps" {
+		t.Errorf("unexpected books: %+v", audit.Books)
+	}
+}
+
+func TestDivineNames(t *testing.T) {
+	corpus := openCorpus(t)
+
+	audit, err := DivineNames(corpus)
+	if err != nil {
+		t.Fatalf("DivineNames failed: %v", err)
+	}
+	if audit.Books[0].Scope != "Gen" || audit.Canon.Tokens == 0 || audit.Canon.Forms[0].Item != "LORD" {
+		t.Errorf("unexpected audit: %s", audit)
+	}
+	for _, suspect := range audit.Suspects {
+		if suspect.OSIS == "Rev" && suspect.Chapter == 19 {
+			t.Errorf("inscription reported as a suspect: %s", suspect)
+		}
+	}
+
+	dir := t.TempDir()
+	paths, err := WriteDivineNames(dir, FormatCSV, audit)
+	if err != nil {
+		t.Fatalf("WriteDivineNames failed: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 files, got %v", paths)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "divine_names.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "scope,tokens,verses,suspects,form,count\nGen,") {
+		t.Errorf("unexpected divine_names.csv: %.80s", data)
+	}
+}
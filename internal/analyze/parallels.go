@@ -0,0 +1,283 @@
+package analyze
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// ParallelOptions controls parallel passage detection
+type ParallelOptions struct {
+	Shingle   int     // words per shingle
+	Hashes    int     // MinHash signature length, a multiple of Bands
+	Bands     int     // LSH bands; more bands find less similar candidates
+	Threshold float64 // minimum Jaccard similarity for two verses to match
+	MinWords  int     // verses with fewer words are skipped, so short formulas do not match
+	MinVerses int     // minimum matched verses for a pair of passages to be reported
+	MaxBucket int     // LSH buckets larger than this hold formulaic text and are ignored
+}
+
+// DefaultParallelOptions finds Kings/Chronicles and synoptic parallels without matching
+// formulas such as "And the LORD spake unto Moses, saying"
+var DefaultParallelOptions = ParallelOptions{
+	Shingle:   3,
+	Hashes:    128,
+	Bands:     64,
+	Threshold: 0.3,
+	MinWords:  8,
+	MinVerses: 2,
+	MaxBucket: 64,
+}
+
+// parallelVerse is a verse prepared for comparison
+type parallelVerse struct {
+	osis     string
+	chapter  int
+	verse    int
+	shingles []uint64 // sorted, distinct shingle hashes
+}
+
+// versePair is two matching verses, a before b in canonical order
+type versePair struct {
+	a, b       int // indexes into the verses
+	similarity float64
+}
+
+// Parallels finds passages in different books whose verses match in order. Verses are compared
+// by the Jaccard similarity of their word shingles; MinHash signatures with locality-sensitive
+// hashing pick the candidate pairs, so not every pair of verses is compared.
+func Parallels(corpus *kjvcorpus.Corpus, opts ParallelOptions) (*model.Parallels, error) {
+	if opts.Shingle < 1 || opts.Bands < 1 || opts.Hashes < opts.Bands || opts.Hashes%opts.Bands != 0 {
+		return nil, fmt.Errorf("invalid options: hashes (%d) must be a positive multiple of bands (%d)", opts.Hashes, opts.Bands)
+	}
+
+	var verses []parallelVerse
+	for ch, err := range corpus.Chapters() {
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range ch.Verses {
+			words := Words(v.Plain)
+			if len(words) < max(opts.MinWords, opts.Shingle) {
+				continue
+			}
+			verses = append(verses, parallelVerse{
+				osis:     ch.OSIS,
+				chapter:  ch.Chapter,
+				verse:    v.V,
+				shingles: shingles(words, opts.Shingle),
+			})
+		}
+	}
+
+	pairs := matchVerses(verses, opts)
+	result := &model.Parallels{
+		Schema:    model.ParallelsSchema,
+		Shingle:   opts.Shingle,
+		Hashes:    opts.Hashes,
+		Threshold: opts.Threshold,
+		Pairs:     mergePassages(verses, pairs, opts.MinVerses),
+	}
+	return result, nil
+}
+
+// shingles hashes every run of n consecutive words, returning the distinct hashes sorted
+func shingles(words []string, n int) []uint64 {
+	seen := make(map[uint64]bool, len(words))
+	var result []uint64
+	for i := 0; i+n <= len(words); i++ {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(strings.Join(words[i:i+n], " ")))
+		sum := h.Sum64()
+		if !seen[sum] {
+			seen[sum] = true
+			result = append(result, sum)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// matchVerses returns the pairs of verses in different books at or above the threshold
+func matchVerses(verses []parallelVerse, opts ParallelOptions) []versePair {
+	seeds := hashSeeds(opts.Hashes)
+	signatures := make([][]uint64, len(verses))
+	for i, v := range verses {
+		signatures[i] = minHash(v.shingles, seeds)
+	}
+
+	rows := opts.Hashes / opts.Bands
+	candidates := make(map[[2]int]bool)
+	for band := 0; band < opts.Bands; band++ {
+		buckets := make(map[uint64][]int)
+		for i, sig := range signatures {
+			h := fnv.New64a()
+			var buf [8]byte
+			for _, x := range sig[band*rows : (band+1)*rows] {
+				binary.LittleEndian.PutUint64(buf[:], x)
+				_, _ = h.Write(buf[:])
+			}
+			key := h.Sum64()
+			buckets[key] = append(buckets[key], i)
+		}
+		for _, bucket := range buckets {
+			if len(bucket) < 2 || len(bucket) > opts.MaxBucket {
+				continue
+			}
+			for x := 0; x < len(bucket); x++ {
+				for y := x + 1; y < len(bucket); y++ {
+					a, b := bucket[x], bucket[y]
+					if verses[a].osis != verses[b].osis {
+						candidates[[2]int{a, b}] = true
+					}
+				}
+			}
+		}
+	}
+
+	var pairs []versePair
+	for c := range candidates {
+		similarity := jaccard(verses[c[0]].shingles, verses[c[1]].shingles)
+		if similarity < opts.Threshold {
+			continue
+		}
+		a, b := min(c[0], c[1]), max(c[0], c[1])
+		pairs = append(pairs, versePair{a: a, b: b, similarity: similarity})
+	}
+
+	// Verses are in canonical order, so sorting by index sorts by reference
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].a != pairs[j].a {
+			return pairs[i].a < pairs[j].a
+		}
+		return pairs[i].b < pairs[j].b
+	})
+	return pairs
+}
+
+// hashSeeds returns n fixed multiplier and offset pairs for the MinHash permutations, so
+// repeated runs produce the same parallels
+func hashSeeds(n int) [][2]uint64 {
+	seeds := make([][2]uint64, n)
+	state := uint64(0x9e3779b97f4a7c15)
+	next := func() uint64 {
+		// splitmix64
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		return z ^ (z >> 31)
+	}
+	for i := range seeds {
+		seeds[i] = [2]uint64{next() | 1, next()}
+	}
+	return seeds
+}
+
+// minHash returns the MinHash signature of a shingle set
+func minHash(set []uint64, seeds [][2]uint64) []uint64 {
+	sig := make([]uint64, len(seeds))
+	for i, seed := range seeds {
+		sig[i] = math.MaxUint64
+		for _, x := range set {
+			if h := x*seed[0] + seed[1]; h < sig[i] {
+				sig[i] = h
+			}
+		}
+	}
+	return sig
+}
+
+// jaccard returns the Jaccard similarity of two sorted sets
+func jaccard(a, b []uint64) float64 {
+	shared := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			shared++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// maxGap is the furthest apart, in verses, consecutive matches of a passage may be on either side
+const maxGap = 3
+
+// mergePassages joins verse pairs that continue each other within the same two chapters into
+// passage pairs, allowing up to two unmatched verses between matches on either side, and keeps
+// those with at least minVerses matched verses
+func mergePassages(verses []parallelVerse, pairs []versePair, minVerses int) []model.ParallelPair {
+	type run struct {
+		first, last versePair
+		count       int
+		total       float64
+	}
+	continues := func(r *run, p versePair) bool {
+		la, lb := verses[r.last.a], verses[r.last.b]
+		a, b := verses[p.a], verses[p.b]
+		return a.osis == la.osis && a.chapter == la.chapter && b.osis == lb.osis && b.chapter == lb.chapter &&
+			a.verse-la.verse >= 1 && a.verse-la.verse <= maxGap && b.verse-lb.verse >= 1 && b.verse-lb.verse <= maxGap
+	}
+
+	var runs []*run
+	open := []*run{}
+	for _, p := range pairs {
+		var extended *run
+		for _, r := range open {
+			if continues(r, p) {
+				extended = r
+				break
+			}
+		}
+		if extended == nil {
+			extended = &run{first: p}
+			runs = append(runs, extended)
+			open = append(open, extended)
+		}
+		extended.last = p
+		extended.count++
+		extended.total += p.similarity
+
+		// Runs whose A side has fallen more than maxGap verses behind cannot be extended
+		kept := open[:0]
+		for _, r := range open {
+			la, a := verses[r.last.a], verses[p.a]
+			if la.osis == a.osis && la.chapter == a.chapter && a.verse-la.verse <= maxGap {
+				kept = append(kept, r)
+			}
+		}
+		open = kept
+	}
+
+	result := []model.ParallelPair{}
+	for _, r := range runs {
+		if r.count < minVerses {
+			continue
+		}
+		fa, fb := verses[r.first.a], verses[r.first.b]
+		la, lb := verses[r.last.a], verses[r.last.b]
+		result = append(result, model.ParallelPair{
+			A:          model.Passage{OSIS: fa.osis, Chapter: fa.chapter, Start: fa.verse, End: la.verse},
+			B:          model.Passage{OSIS: fb.osis, Chapter: fb.chapter, Start: fb.verse, End: lb.verse},
+			Verses:     r.count,
+			Similarity: math.Round(r.total/float64(r.count)*1000) / 1000,
+		})
+	}
+	return result
+}
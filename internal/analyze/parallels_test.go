@@ -0,0 +1,129 @@
+package analyze
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestJaccard(t *testing.T) {
+	tests := []struct {
+		a, b []uint64
+		want float64
+	}{
+		{[]uint64{1, 2, 3}, []uint64{1, 2, 3}, 1},
+		{[]uint64{1, 2, 3}, []uint64{2, 3, 4}, 0.5},
+		{[]uint64{1, 2}, []uint64{3, 4}, 0},
+		{nil, nil, 0},
+	}
+
+	for _, tt := range tests {
+		if got := jaccard(tt.a, tt.b); got != tt.want {
+			t.Errorf("jaccard(%v, %v) = %v; want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMergePassages(t *testing.T) {
+	verses := []parallelVerse{
+		{osis: "2 Kgs", chapter: 19, verse: 1},
+		{osis: "2 Kgs", chapter: 19, verse: 2},
+		{osis: "2 Kgs", chapter: 19, verse: 5},
+		{osis: "2 Kgs", chapter: 19, verse: 10},
+		{osis: "Isa", chapter: 37, verse: 1},
+		{osis: "Isa", chapter: 37, verse: 2},
+		{osis: "Isa", chapter: 37, verse: 4},
+		{osis: "Isa", chapter: 37, verse: 10},
+	}
+	pairs := []versePair{
+		{a: 0, b: 4, similarity: 0.8},
+		{a: 1, b: 5, similarity: 0.6},
+		{a: 2, b: 6, similarity: 0.7}, // a gap of two unmatched verses continues the passage
+		{a: 3, b: 7, similarity: 0.9}, // a gap of four starts a new one
+	}
+
+	got := mergePassages(verses, pairs, 2)
+	want := []model.ParallelPair{{
+		A:          model.Passage{OSIS: "2 Kgs", Chapter: 19, Start: 1, End: 5},
+		B:          model.Passage{OSIS: "Isa", Chapter: 37, Start: 1, End: 4},
+		Verses:     3,
+		Similarity: 0.7,
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergePassages = %+v; want %+v", got, want)
+	}
+}
+
+func TestParallels(t *testing.T) {
+	corpus := openCorpus(t)
+
+	parallels, err := Parallels(corpus, DefaultParallelOptions)
+	if err != nil {
+		t.Fatalf("Parallels failed: %v", err)
+	}
+
+	found := func(a, b string, chA, chB int) *model.ParallelPair {
+		for i, p := range parallels.Pairs {
+			if p.A.OSIS == a && p.A.Chapter == chA && p.B.OSIS == b && p.B.Chapter == chB {
+				return &parallels.Pairs[i]
+			}
+		}
+		return nil
+	}
+	expected := []struct {
+		a, b     string
+		chA, chB int
+	}{
+		{"2Kgs", "Isa", 19, 37},
+		{"1Kgs", "2Chr", 22, 18},
+		{"Ezra", "Neh", 2, 7},
+		{"Matt", "Luke", 7, 11},
+	}
+	for _, tt := range expected {
+		p := found(tt.a, tt.b, tt.chA, tt.chB)
+		if p == nil {
+			t.Errorf("expected a parallel between %s %d and %s %d", tt.a, tt.chA, tt.b, tt.chB)
+			continue
+		}
+		if p.Verses < DefaultParallelOptions.MinVerses || p.Similarity < DefaultParallelOptions.Threshold {
+			t.Errorf("unexpected parallel %+v", p)
+		}
+	}
+	for _, p := range parallels.Pairs {
+		if p.A.OSIS == p.B.OSIS {
+			t.Errorf("expected parallels across books only, got %+v", p)
+		}
+	}
+
+	// The MinHash permutations are fixed, so the committed parallels.json is reproducible
+	again, err := Parallels(corpus, DefaultParallelOptions)
+	if err != nil {
+		t.Fatalf("Parallels failed: %v", err)
+	}
+	if !reflect.DeepEqual(parallels, again) {
+		t.Error("expected repeated runs to find the same parallels")
+	}
+
+	invalid := DefaultParallelOptions
+	invalid.Bands = 100
+	if _, err := Parallels(corpus, invalid); err == nil {
+		t.Error("expected hashes not divisible by bands to fail")
+	}
+
+	path := filepath.Join(t.TempDir(), "index", "parallels.json")
+	if err := WriteParallels(path, parallels); err != nil {
+		t.Fatalf("WriteParallels failed: %v", err)
+	}
+	data, err := os.ReadFile(path) // nolint: gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed model.Parallels
+	if err := json.Unmarshal(data, &parsed); err != nil || len(parsed.Pairs) != len(parallels.Pairs) {
+		t.Errorf("unexpected parallels.json: %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+package analyze
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Format is an output format for analysis tables
+type Format string
+
+const (
+	FormatCSV  Format = "csv"  // frequency.csv, ngrams.csv, and hapax.csv
+	FormatJSON Format = "json" // analysis.json
+)
+
+// Formats lists the output formats
+var Formats = []Format{FormatCSV, FormatJSON}
+
+// Write writes tables to dir in the given format and returns the paths written
+func Write(dir string, format Format, tables []Table) ([]string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	switch format {
+	case FormatJSON:
+		path := filepath.Join(dir, "analysis.json")
+		data, err := json.MarshalIndent(tables, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal analysis: %w", err)
+		}
+		if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return []string{path}, nil
+	case FormatCSV:
+		files := []struct {
+			name   string
+			header []string
+			rows   func(Table) [][]string
+		}{
+			{"frequency.csv", []string{"scope", "word", "count"}, func(t Table) [][]string { return countRows(t.Scope, t.Words) }},
+			{"ngrams.csv", []string{"scope", "ngram", "count"}, func(t Table) [][]string { return countRows(t.Scope, t.NGrams) }},
+			{"hapax.csv", []string{"scope", "word"}, func(t Table) [][]string {
+				rows := make([][]string, 0, len(t.Hapax))
+				for _, word := range t.Hapax {
+					rows = append(rows, []string{t.Scope, word})
+				}
+				return rows
+			}},
+		}
+
+		var paths []string
+		for _, file := range files {
+			records := [][]string{file.header}
+			for _, t := range tables {
+				records = append(records, file.rows(t)...)
+			}
+			path := filepath.Join(dir, file.name)
+			if err := writeCSV(path, records); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// countRows returns one CSV row per count
+func countRows(scope string, counts []Count) [][]string {
+	rows := make([][]string, 0, len(counts))
+	for _, c := range counts {
+		rows = append(rows, []string{scope, c.Item, strconv.Itoa(c.Count)})
+	}
+	return rows
+}
+
+// writeCSV writes records to a CSV file at path
+func writeCSV(path string, records [][]string) error {
+	var b bytes.Buffer
+	if err := csv.NewWriter(&b).WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := atomicfile.WriteFile(path, b.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// WriteDivineNames writes a divine-name audit to dir in the given format and returns the paths
+// written
+func WriteDivineNames(dir string, format Format, audit *DivineNameAudit) ([]string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	switch format {
+	case FormatJSON:
+		path := filepath.Join(dir, "divine_names.json")
+		data, err := json.MarshalIndent(audit, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal divine names: %w", err)
+		}
+		if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return []string{path}, nil
+	case FormatCSV:
+		counts := [][]string{{"scope", "tokens", "verses", "suspects", "form", "count"}}
+		for _, c := range append(audit.Books, audit.Canon) {
+			for _, form := range c.Forms {
+				counts = append(counts, []string{
+					c.Scope,
+					strconv.Itoa(c.Tokens),
+					strconv.Itoa(c.Verses),
+					strconv.Itoa(c.Suspects),
+					form.Item,
+					strconv.Itoa(form.Count),
+				})
+			}
+		}
+
+		var paths []string
+		for _, file := range []struct {
+			name    string
+			records [][]string
+		}{{"divine_names.csv", counts}, {"divine_name_suspects.csv", suspectRecords(audit.Suspects)}} {
+			path := filepath.Join(dir, file.name)
+			if err := writeCSV(path, file.records); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// WriteAddedWords writes an added-word audit to dir in the given format and returns the paths
+// written
+func WriteAddedWords(dir string, format Format, audit *AddedWordAudit) ([]string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	switch format {
+	case FormatJSON:
+		path := filepath.Join(dir, "added_words.json")
+		data, err := json.MarshalIndent(audit, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal added words: %w", err)
+		}
+		if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		return []string{path}, nil
+	case FormatCSV:
+		counts := [][]string{{"scope", "tokens", "words", "share", "verses", "suspects"}}
+		for _, c := range append(audit.Books, audit.Canon) {
+			counts = append(counts, []string{
+				c.Scope,
+				strconv.Itoa(c.Tokens),
+				strconv.Itoa(c.Words),
+				strconv.FormatFloat(c.Share, 'f', 4, 64),
+				strconv.Itoa(c.Verses),
+				strconv.Itoa(c.Suspects),
+			})
+		}
+		added := [][]string{{"osis", "chapter", "verse", "text"}}
+		for _, w := range audit.Added {
+			added = append(added, []string{w.OSIS, strconv.Itoa(w.Chapter), strconv.Itoa(w.V), w.Text})
+		}
+
+		var paths []string
+		for _, file := range []struct {
+			name    string
+			records [][]string
+		}{{"added_words.csv", counts}, {"added_word_tokens.csv", added}, {"added_word_suspects.csv", suspectRecords(audit.Suspects)}} {
+			path := filepath.Join(dir, file.name)
+			if err := writeCSV(path, file.records); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+		return paths, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// suspectRecords returns a header and one CSV row per suspect
+func suspectRecords(suspects []Suspect) [][]string {
+	records := [][]string{{"osis", "chapter", "verse", "kind", "text"}}
+	for _, s := range suspects {
+		records = append(records, []string{s.OSIS, strconv.Itoa(s.Chapter), strconv.Itoa(s.V), s.Kind, s.Text})
+	}
+	return records
+}
+
+// WriteParallels writes parallels.json to path
+func WriteParallels(path string, parallels *model.Parallels) error {
+	data, err := json.MarshalIndent(parallels, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal parallels: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
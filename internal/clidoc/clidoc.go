@@ -0,0 +1,62 @@
+// Package clidoc generates shell completions and man pages from kong command definitions.
+package clidoc
+
+import (
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// Commands returns the application node and every visible command beneath it, depth first
+func Commands(app *kong.Application) []*kong.Node {
+	var out []*kong.Node
+	var walk func(n *kong.Node)
+	walk = func(n *kong.Node) {
+		out = append(out, n)
+		for _, child := range subcommands(n) {
+			walk(child)
+		}
+	}
+	walk(app.Node)
+	return out
+}
+
+// commandPath returns the subcommand names leading to n, excluding the application name
+func commandPath(n *kong.Node) []string {
+	var path []string
+	for ; n != nil && n.Type == kong.CommandNode; n = n.Parent {
+		path = append([]string{n.Name}, path...)
+	}
+	return path
+}
+
+// subcommands returns the visible command children of n
+func subcommands(n *kong.Node) []*kong.Node {
+	var out []*kong.Node
+	for _, child := range n.Children {
+		if child.Type == kong.CommandNode && !child.Hidden {
+			out = append(out, child)
+		}
+	}
+	return out
+}
+
+// flags returns the visible flags accepted by n, including those inherited from its parents
+func flags(n *kong.Node) []*kong.Flag {
+	var out []*kong.Flag
+	for _, group := range n.AllFlags(true) {
+		out = append(out, group...)
+	}
+	return out
+}
+
+// takesValue reports whether a flag consumes the following argument
+func takesValue(f *kong.Flag) bool {
+	return !f.IsBool() && !f.IsCounter()
+}
+
+// help returns the first line of a help string
+func help(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return s
+}
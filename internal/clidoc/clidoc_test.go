@@ -0,0 +1,139 @@
+package clidoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+)
+
+type testCLI struct {
+	Verbose bool `help:"Enable verbose output" short:"v"`
+
+	Verify struct {
+		Raw struct {
+			Raw       string `help:"The raw directory" default:"./raw"`
+			Structure bool   `help:"Check structure"`
+		} `cmd:"" help:"Validate raw files"`
+		Canon struct {
+			Level string `help:"Report level" enum:"info,warn" default:"info"`
+		} `cmd:"" help:"Validate canon files"`
+	} `cmd:"" help:"Verify things"`
+
+	Completions struct {
+		Shell string `arg:"" enum:"bash,zsh,fish" help:"Shell to complete"`
+	} `cmd:"" help:"Print completions"`
+
+	Secret struct{} `cmd:"" hidden:""`
+}
+
+func newTestApp(t *testing.T) *kong.Application {
+	t.Helper()
+
+	parser, err := kong.New(&testCLI{}, kong.Name("tool"), kong.Description("A test tool"))
+	if err != nil {
+		t.Fatalf("failed to build parser: %v", err)
+	}
+	return parser.Model
+}
+
+func TestCommands(t *testing.T) {
+	var paths []string
+	for _, n := range Commands(newTestApp(t)) {
+		paths = append(paths, strings.Join(commandPath(n), " "))
+	}
+
+	want := []string{"", "verify", "verify raw", "verify canon", "completions"}
+	if strings.Join(paths, "|") != strings.Join(want, "|") {
+		t.Errorf("expected commands %q, got %q", want, paths)
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{
+			shell: "bash",
+			want: []string{
+				`"") words="verify completions --help -h --verbose -v" ;;`,
+				`"verify raw") words="--help -h --verbose -v --raw --structure" ;;`,
+				`"completions") words="bash zsh fish --help -h --verbose -v" ;;`,
+				`COMPREPLY=($(compgen -W "info warn" -- "$cur"))`,
+				"complete -o default -F _tool tool",
+			},
+		},
+		{
+			shell: "zsh",
+			want:  []string{"#compdef tool", "bashcompinit", "complete -o default -F _tool tool"},
+		},
+		{
+			shell: "fish",
+			want: []string{
+				"complete -c tool -n '__fish_use_subcommand' -a verify -d 'Verify things'",
+				"complete -c tool -n '__fish_seen_subcommand_from verify; and not __fish_seen_subcommand_from raw canon' -a raw -d 'Validate raw files'",
+				"complete -c tool -n '__fish_seen_subcommand_from verify; and __fish_seen_subcommand_from raw' -l raw -r -F -d 'The raw directory'",
+				"complete -c tool -n '__fish_seen_subcommand_from verify; and __fish_seen_subcommand_from canon' -l level -r -a 'info warn' -d 'Report level'",
+				"complete -c tool -n '__fish_seen_subcommand_from completions' -a 'bash zsh fish' -d 'Shell to complete'",
+				"complete -c tool -l verbose -s v -d 'Enable verbose output'",
+			},
+		},
+	}
+
+	app := newTestApp(t)
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			var b strings.Builder
+			if err := Completion(&b, app, tt.shell); err != nil {
+				t.Fatalf("Completion failed: %v", err)
+			}
+			out := b.String()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, out)
+				}
+			}
+			if strings.Contains(out, "secret") {
+				t.Errorf("expected hidden command to be omitted, got:\n%s", out)
+			}
+		})
+	}
+
+	if err := Completion(&strings.Builder{}, app, "tcsh"); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
+
+func TestWriteManPages(t *testing.T) {
+	dir := t.TempDir()
+	written, err := WriteManPages(dir, newTestApp(t), "2025-01-02")
+	if err != nil {
+		t.Fatalf("WriteManPages failed: %v", err)
+	}
+	if len(written) != 5 {
+		t.Errorf("expected 5 man pages, got %d: %v", len(written), written)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "tool-verify-canon.1")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read man page: %v", err)
+	}
+	page := string(data)
+	for _, want := range []string{
+		`.TH "TOOL-VERIFY-CANON" 1 "2025-01-02" "tool" "User Commands"`,
+		`tool\-verify\-canon \- Validate canon files`,
+		".B tool verify canon",
+		`\fB\-v\fR, \fB\-\-verbose\fR`,
+		`\fB\-\-level\fR=\fILEVEL\fR`,
+		"One of: info, warn.",
+		"Default: info.",
+		`tool\-verify(1)`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected man page to contain %q, got:\n%s", want, page)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package clidoc
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// Shells lists the shells Completion supports
+var Shells = []string{"bash", "zsh", "fish"}
+
+// Completion writes a completion script for shell covering every command and flag in app
+func Completion(w io.Writer, app *kong.Application, shell string) error {
+	switch shell {
+	case "bash":
+		return writeBash(w, app)
+	case "zsh":
+		return writeZsh(w, app)
+	case "fish":
+		return writeFish(w, app)
+	default:
+		return fmt.Errorf("unsupported shell %q (want one of %s)", shell, strings.Join(Shells, ", "))
+	}
+}
+
+// writeBash writes a bash completion function that completes the subcommands and flags of the
+// command named by the words typed so far
+func writeBash(w io.Writer, app *kong.Application) error {
+	fn := "_" + strings.NewReplacer("-", "_", ".", "_").Replace(app.Name)
+
+	valueFlags := map[string]bool{}
+	enumFlags := map[string]string{}
+	for _, n := range Commands(app) {
+		for _, f := range flags(n) {
+			if !takesValue(f) {
+				continue
+			}
+			names := []string{"--" + f.Name}
+			if f.Short != 0 {
+				names = append(names, fmt.Sprintf("-%c", f.Short))
+			}
+			for _, name := range names {
+				valueFlags[name] = true
+				if f.Enum != "" {
+					enumFlags[name] = strings.Join(f.EnumSlice(), " ")
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", app.Name)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("\tlocal cur prev path word words i\n")
+	b.WriteString("\tcur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("\tprev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	b.WriteString("\tpath=\"\"\n")
+	b.WriteString("\tfor ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("\t\tword=\"${COMP_WORDS[i]}\"\n")
+	b.WriteString("\t\tcase \"$word\" in\n\t\t-*) continue ;;\n\t\tesac\n")
+	if len(valueFlags) > 0 {
+		fmt.Fprintf(&b, "\t\tcase \"${COMP_WORDS[i-1]}\" in\n\t\t%s) continue ;;\n\t\tesac\n", strings.Join(sortedKeys(valueFlags), "|"))
+	}
+	b.WriteString("\t\tpath=\"${path:+$path }$word\"\n")
+	b.WriteString("\tdone\n")
+
+	if len(enumFlags) > 0 {
+		b.WriteString("\tcase \"$prev\" in\n")
+		for _, name := range sortedKeys(enumFlags) {
+			fmt.Fprintf(&b, "\t%s)\n\t\tCOMPREPLY=($(compgen -W %q -- \"$cur\"))\n\t\treturn\n\t\t;;\n", name, enumFlags[name])
+		}
+		b.WriteString("\tesac\n")
+	}
+	if len(valueFlags) > 0 {
+		fmt.Fprintf(&b, "\tcase \"$prev\" in\n\t%s) return ;;\n\tesac\n", strings.Join(sortedKeys(valueFlags), "|"))
+	}
+
+	b.WriteString("\tcase \"$path\" in\n")
+	for _, n := range Commands(app) {
+		fmt.Fprintf(&b, "\t%q) words=%q ;;\n", strings.Join(commandPath(n), " "), strings.Join(words(n), " "))
+	}
+	b.WriteString("\t*) words=\"\" ;;\n")
+	b.WriteString("\tesac\n")
+	b.WriteString("\tCOMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -o default -F %s %s\n", fn, app.Name)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeZsh writes the bash completion function wrapped for zsh's bashcompinit
+func writeZsh(w io.Writer, app *kong.Application) error {
+	if _, err := fmt.Fprintf(w, "#compdef %s\n\nautoload -U +X bashcompinit && bashcompinit\n\n", app.Name); err != nil {
+		return err
+	}
+	return writeBash(w, app)
+}
+
+// writeFish writes fish completions, conditioning each subcommand and flag on the commands
+// already typed
+func writeFish(w io.Writer, app *kong.Application) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", app.Name)
+	fmt.Fprintf(&b, "complete -c %s -f\n", app.Name)
+
+	for _, n := range Commands(app) {
+		seen := fishSeen(commandPath(n))
+
+		if children := subcommands(n); len(children) > 0 {
+			var names []string
+			for _, child := range children {
+				names = append(names, child.Name)
+			}
+			cond := "__fish_use_subcommand"
+			if n.Type == kong.CommandNode {
+				cond = seen + "; and not __fish_seen_subcommand_from " + strings.Join(names, " ")
+			}
+			for _, child := range children {
+				fmt.Fprintf(&b, "complete -c %s -n %s -a %s -d %s\n",
+					app.Name, fishQuote(cond), child.Name, fishQuote(help(child.Help)))
+			}
+		}
+
+		for _, f := range n.Flags {
+			if f.Hidden {
+				continue
+			}
+			fmt.Fprintf(&b, "complete -c %s", app.Name)
+			if n.Type == kong.CommandNode {
+				fmt.Fprintf(&b, " -n %s", fishQuote(seen))
+			}
+			fmt.Fprintf(&b, " -l %s", f.Name)
+			if f.Short != 0 {
+				fmt.Fprintf(&b, " -s %c", f.Short)
+			}
+			if takesValue(f) {
+				b.WriteString(" -r")
+				if f.Enum != "" {
+					fmt.Fprintf(&b, " -a %s", fishQuote(strings.Join(f.EnumSlice(), " ")))
+				} else {
+					b.WriteString(" -F")
+				}
+			}
+			fmt.Fprintf(&b, " -d %s\n", fishQuote(help(f.Help)))
+		}
+
+		for _, arg := range n.Positional {
+			if arg.Enum == "" {
+				continue
+			}
+			fmt.Fprintf(&b, "complete -c %s -n %s -a %s -d %s\n",
+				app.Name, fishQuote(seen), fishQuote(strings.Join(arg.EnumSlice(), " ")), fishQuote(help(arg.Help)))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// words returns the subcommands, positional choices, and flags that may follow the command n
+func words(n *kong.Node) []string {
+	var out []string
+	for _, child := range subcommands(n) {
+		out = append(out, child.Name)
+	}
+	for _, arg := range n.Positional {
+		if arg.Enum != "" {
+			out = append(out, arg.EnumSlice()...)
+		}
+	}
+	for _, f := range flags(n) {
+		out = append(out, "--"+f.Name)
+		if f.Short != 0 {
+			out = append(out, fmt.Sprintf("-%c", f.Short))
+		}
+	}
+	return out
+}
+
+// fishSeen returns a fish condition that holds once every command in path has been typed
+func fishSeen(path []string) string {
+	conds := make([]string, 0, len(path))
+	for _, name := range path {
+		conds = append(conds, "__fish_seen_subcommand_from "+name)
+	}
+	return strings.Join(conds, "; and ")
+}
+
+// fishQuote single-quotes s for fish
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
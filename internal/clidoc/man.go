@@ -0,0 +1,163 @@
+package clidoc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+)
+
+// ManPageName returns the man page file name for n, e.g. kjvsrc-verify-raw.1
+func ManPageName(n *kong.Node) string {
+	return manTitle(n) + ".1"
+}
+
+// WriteManPages writes a section 1 man page for the application and each of its commands into dir,
+// returning the files written
+func WriteManPages(dir string, app *kong.Application, date string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create man page directory: %w", err)
+	}
+
+	var written []string
+	for _, n := range Commands(app) {
+		var b strings.Builder
+		if err := ManPage(&b, n, date); err != nil {
+			return written, err
+		}
+		path := filepath.Join(dir, ManPageName(n))
+		if err := atomicfile.WriteFile(path, []byte(b.String()), 0600); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// ManPage writes the roff man page for the command n
+func ManPage(w io.Writer, n *kong.Node, date string) error {
+	root := n
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	title := manTitle(n)
+	invocation := strings.Join(append([]string{root.Name}, commandPath(n)...), " ")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH %q 1 %q %q \"User Commands\"\n", strings.ToUpper(title), date, root.Name)
+
+	b.WriteString(".SH NAME\n")
+	fmt.Fprintf(&b, "%s \\- %s\n", roffEscape(title), roffEscape(help(n.Help)))
+
+	b.WriteString(".SH SYNOPSIS\n")
+	fmt.Fprintf(&b, ".B %s\n", roffEscape(invocation))
+	children := subcommands(n)
+	if len(children) > 0 {
+		b.WriteString("\\fICOMMAND\\fR\n")
+	}
+	b.WriteString("[\\fIOPTIONS\\fR]")
+	for _, arg := range n.Positional {
+		fmt.Fprintf(&b, " %s", roffEscape(arg.ShortSummary()))
+	}
+	b.WriteString("\n")
+
+	if description := strings.TrimSpace(n.Detail); description != "" || n.Help != "" {
+		if description == "" {
+			description = n.Help
+		}
+		b.WriteString(".SH DESCRIPTION\n")
+		for _, line := range strings.Split(description, "\n") {
+			b.WriteString(roffLine(line) + "\n")
+		}
+	}
+
+	if len(children) > 0 {
+		b.WriteString(".SH COMMANDS\n")
+		for _, child := range children {
+			fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", roffEscape(child.Name), roffLine(help(child.Help)))
+		}
+	}
+
+	if len(n.Positional) > 0 {
+		b.WriteString(".SH ARGUMENTS\n")
+		for _, arg := range n.Positional {
+			fmt.Fprintf(&b, ".TP\n.I %s\n%s\n", roffEscape(arg.Name), roffLine(help(arg.Help)))
+			if arg.Enum != "" {
+				fmt.Fprintf(&b, "One of: %s.\n", roffEscape(strings.Join(arg.EnumSlice(), ", ")))
+			}
+		}
+	}
+
+	if all := flags(n); len(all) > 0 {
+		b.WriteString(".SH OPTIONS\n")
+		for _, f := range all {
+			b.WriteString(".TP\n")
+			if f.Short != 0 {
+				fmt.Fprintf(&b, "\\fB\\-%c\\fR, ", f.Short)
+			}
+			fmt.Fprintf(&b, "\\fB\\-\\-%s\\fR", roffEscape(f.Name))
+			if takesValue(f) {
+				fmt.Fprintf(&b, "=\\fI%s\\fR", roffEscape(placeholder(f)))
+			}
+			b.WriteString("\n")
+			b.WriteString(roffLine(help(f.Help)) + "\n")
+			if f.Enum != "" {
+				fmt.Fprintf(&b, "One of: %s.\n", roffEscape(strings.Join(f.EnumSlice(), ", ")))
+			}
+			if f.HasDefault && f.Default != "" && takesValue(f) {
+				fmt.Fprintf(&b, "Default: %s.\n", roffEscape(f.Default))
+			}
+		}
+	}
+
+	var related []string
+	if n.Parent != nil {
+		related = append(related, manTitle(n.Parent)+"(1)")
+	}
+	for _, child := range children {
+		related = append(related, manTitle(child)+"(1)")
+	}
+	if len(related) > 0 {
+		b.WriteString(".SH SEE ALSO\n")
+		b.WriteString(roffEscape(strings.Join(related, ", ")) + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// placeholder names the value a flag takes; defaults are listed separately rather than in the placeholder
+func placeholder(f *kong.Flag) string {
+	if f.PlaceHolder != "" {
+		return f.PlaceHolder
+	}
+	return strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+}
+
+// manTitle returns the hyphenated command name for n, e.g. kjvsrc-verify-raw
+func manTitle(n *kong.Node) string {
+	root := n
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	return strings.Join(append([]string{root.Name}, commandPath(n)...), "-")
+}
+
+// roffEscape escapes backslashes and hyphens for roff
+func roffEscape(s string) string {
+	return strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+}
+
+// roffLine escapes a line of text, guarding against it being read as a roff request
+func roffLine(s string) string {
+	s = roffEscape(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
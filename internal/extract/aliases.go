@@ -1,4 +1,4 @@
-package main
+package extract
 
 import (
 	"encoding/json"
@@ -7,68 +7,80 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-)
-
-type BookData struct {
-	OSIS      string   `json:"osis"`
-	Abbr      string   `json:"abbr"`
-	Name      string   `json:"name"`
-	Aliases   []string `json:"aliases"`
-	Testament string   `json:"testament"`
-	Order     int      `json:"order"`
-	Chapters  int      `json:"chapters"`
-}
 
-type BooksOutput struct {
-	Schema int        `json:"schema"`
-	Work   string     `json:"work"`
-	Books  []BookData `json:"books"`
-}
-
-type AliasChapters struct {
-	SourceAbbr string            `json:"source_abbr"`
-	Chapters   map[string]string `json:"chapters"`
-}
-
-type AliasesOutput map[string]AliasChapters
-
-func MainAliases(stop chan bool) {
-	cwd, err := os.Getwd()
-	if err != nil {
-		close(stop)
-		fmt.Println("Error getting current working directory:", err)
-		return
-	}
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
 
-	CanonDir := filepath.Join(cwd, "canon", "kjv", "index")
-	RawDir := filepath.Join(cwd, "raw", "html")
+// ExtractAliases maps each book in books.json in indexDir to its chapter files beneath rawDir/html.
+// Paths are recorded relative to the raw root as raw/html/..., the form ingest resolves against its --raw-dir.
+func ExtractAliases(indexDir, rawDir string) (model.AliasesData, error) {
+	htmlDir := filepath.Join(rawDir, "html")
 
 	// Read books.json
-	booksData, err := os.ReadFile(filepath.Join(CanonDir, "books.json")) // nolint: gosec
+	booksData, err := os.ReadFile(filepath.Join(indexDir, "books.json")) // nolint: gosec
 	if err != nil {
-		close(stop)
-		fmt.Println("Error reading books.json:", err)
-		return
+		return nil, fmt.Errorf("failed to read books.json: %w", err)
 	}
 
-	var booksOutput BooksOutput
+	var booksOutput model.BooksData
 	err = json.Unmarshal(booksData, &booksOutput)
 	if err != nil {
-		close(stop)
-		fmt.Println("Error parsing books.json:", err)
-		return
+		return nil, fmt.Errorf("failed to parse books.json: %w", err)
 	}
 
 	// Create aliases map
-	aliases := make(AliasesOutput)
+	aliases := make(model.AliasesData)
 
 	// Build a map of available files from organized directory structure
+	availableFiles := rawChapterFiles(htmlDir)
+	if len(availableFiles) == 0 {
+		return nil, fmt.Errorf("no raw HTML chapter files found in %s", htmlDir)
+	}
+
+	// Process each book
+	for _, book := range booksOutput.Books {
+		chapters := make(map[string]string)
+
+		// Generate expected filenames for each chapter
+		for chapter := 1; chapter <= book.Chapters; chapter++ {
+			// Try 3-digit zero-padded format first (e.g., PSA001.htm for Psalms 1)
+			filename := fmt.Sprintf("%s%03d.htm", book.Abbr, chapter)
+
+			// Check if file exists and get its path
+			if path, exists := availableFiles[filename]; exists {
+				chapters[strconv.Itoa(chapter)] = path
+			} else {
+				// Fall back to 2-digit format for backward compatibility
+				filename = fmt.Sprintf("%s%02d.htm", book.Abbr, chapter)
+				if path, exists := availableFiles[filename]; exists {
+					chapters[strconv.Itoa(chapter)] = path
+				}
+			}
+		}
+
+		// Also check for chapter 0 (intro chapters sometimes use 00)
+		introFilename := fmt.Sprintf("%s00.htm", book.Abbr)
+		if path, exists := availableFiles[introFilename]; exists {
+			chapters["0"] = path
+		}
+
+		aliases[book.OSIS] = model.AliasChapters{
+			SourceAbbr: book.Abbr,
+			Chapters:   chapters,
+		}
+	}
+
+	return aliases, nil
+}
+
+// rawChapterFiles maps each raw HTML file name beneath htmlDir to its path relative to the raw root
+func rawChapterFiles(htmlDir string) map[string]string {
 	// Structure: raw/html/{ot,nt,ap}/<ABBR>/<file>.htm or raw/html/misc/<file>.htm
 	availableFiles := make(map[string]string) // filename -> path
 	testamentDirs := []string{"ot", "nt", "ap"}
 
 	for _, testament := range testamentDirs {
-		testamentPath := filepath.Join(RawDir, testament)
+		testamentPath := filepath.Join(htmlDir, testament)
 		entries, err := os.ReadDir(testamentPath)
 		if err != nil {
 			// Directory might not exist yet, continue
@@ -99,7 +111,7 @@ func MainAliases(stop chan bool) {
 	}
 
 	// Also check misc directory for non-canonical files
-	miscPath := filepath.Join(RawDir, "misc")
+	miscPath := filepath.Join(htmlDir, "misc")
 	if miscEntries, err := os.ReadDir(miscPath); err == nil {
 		for _, entry := range miscEntries {
 			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".htm") {
@@ -109,55 +121,5 @@ func MainAliases(stop chan bool) {
 		}
 	}
 
-	// Process each book
-	for _, book := range booksOutput.Books {
-		chapters := make(map[string]string)
-
-		// Generate expected filenames for each chapter
-		for chapter := 1; chapter <= book.Chapters; chapter++ {
-			// Try 3-digit zero-padded format first (e.g., PSA001.htm for Psalms 1)
-			filename := fmt.Sprintf("%s%03d.htm", book.Abbr, chapter)
-
-			// Check if file exists and get its path
-			if path, exists := availableFiles[filename]; exists {
-				chapters[strconv.Itoa(chapter)] = path
-			} else {
-				// Fall back to 2-digit format for backward compatibility
-				filename = fmt.Sprintf("%s%02d.htm", book.Abbr, chapter)
-				if path, exists := availableFiles[filename]; exists {
-					chapters[strconv.Itoa(chapter)] = path
-				}
-			}
-		}
-
-		// Also check for chapter 0 (intro chapters sometimes use 00)
-		introFilename := fmt.Sprintf("%s00.htm", book.Abbr)
-		if path, exists := availableFiles[introFilename]; exists {
-			chapters["0"] = path
-		}
-
-		aliases[book.OSIS] = AliasChapters{
-			SourceAbbr: book.Abbr,
-			Chapters:   chapters,
-		}
-	}
-
-	// Marshal to JSON
-	jsonData, err := json.MarshalIndent(aliases, "", "  ")
-	if err != nil {
-		close(stop)
-		fmt.Println("Error marshaling JSON:", err)
-		return
-	}
-
-	// Write to file
-	err = os.WriteFile(filepath.Join(CanonDir, "aliases.json"), jsonData, 0600)
-	if err != nil {
-		close(stop)
-		fmt.Println("Error writing aliases.json:", err)
-		return
-	}
-
-	close(stop)
-	fmt.Println("Successfully created aliases.json")
+	return availableFiles
 }
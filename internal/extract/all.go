@@ -0,0 +1,115 @@
+package extract
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+// Sources are the inputs Bootstrap reads
+type Sources struct {
+	MetadataDir string
+	RawDir      string
+	Structure   *util.CanonStructure
+}
+
+// step is one stage of Bootstrap
+type step struct {
+	File string // index file the step writes
+	Run  func(src Sources, indexDir string) (string, error)
+}
+
+// steps lists the extract stages in dependency order: books.json reads osis.json, and aliases.json reads books.json
+var steps = []step{
+	{File: "osis.json", Run: func(src Sources, indexDir string) (string, error) {
+		osis, counts, err := ExtractOSIS(src.MetadataDir, src.RawDir, src.Structure)
+		if err != nil {
+			return "", err
+		}
+		files := 0
+		for _, n := range counts {
+			files += n
+		}
+		return fmt.Sprintf("%d books, %d chapter files", len(osis), files), writeJSON(filepath.Join(indexDir, "osis.json"), osis)
+	}},
+	{File: "books.json", Run: func(src Sources, indexDir string) (string, error) {
+		output, err := ExtractBooks(src.MetadataDir, indexDir, src.Structure)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d books", len(output.Books)), writeJSON(filepath.Join(indexDir, "books.json"), output)
+	}},
+	{File: "aliases.json", Run: func(src Sources, indexDir string) (string, error) {
+		aliases, err := ExtractAliases(indexDir, src.RawDir)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d books", len(aliases)), writeJSON(filepath.Join(indexDir, "aliases.json"), aliases)
+	}},
+}
+
+// Bootstrap runs every extract step against a temporary copy of indexDir and swaps it into place
+// only once all of them succeed, so a failed run leaves the existing index untouched. Files in
+// indexDir that no step writes, such as filemap.json, are carried over. progress is called as
+// each step starts and finishes.
+func Bootstrap(src Sources, indexDir string, progress func(step int, file, summary string, done bool)) error {
+	parent := filepath.Dir(indexDir)
+	if err := os.MkdirAll(parent, 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", parent, err)
+	}
+
+	tmp, err := os.MkdirTemp(parent, ".index-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary index directory: %w", err)
+	}
+	defer os.RemoveAll(tmp) // nolint: errcheck
+	if err := os.Chmod(tmp, 0750); err != nil {
+		return fmt.Errorf("failed to set permissions on %s: %w", tmp, err)
+	}
+
+	if _, err := os.Stat(indexDir); err == nil {
+		if err := os.CopyFS(tmp, os.DirFS(indexDir)); err != nil {
+			return fmt.Errorf("failed to copy existing index: %w", err)
+		}
+	}
+
+	for i, s := range steps {
+		progress(i+1, s.File, "", false)
+		summary, err := s.Run(src, tmp)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.File, err)
+		}
+		progress(i+1, s.File, summary, true)
+	}
+
+	return swapDir(tmp, indexDir)
+}
+
+// swapDir replaces dir with tmp, restoring dir if the swap fails
+func swapDir(tmp, dir string) error {
+	old := fmt.Sprintf("%s.old-%d", dir, time.Now().UnixNano())
+	if _, err := os.Stat(dir); err == nil {
+		if err := os.Rename(dir, old); err != nil {
+			return fmt.Errorf("failed to move aside %s: %w", dir, err)
+		}
+	} else {
+		old = ""
+	}
+
+	if err := os.Rename(tmp, dir); err != nil {
+		if old != "" {
+			_ = os.Rename(old, dir)
+		}
+		return fmt.Errorf("failed to move new index into place: %w", err)
+	}
+
+	if old != "" {
+		if err := os.RemoveAll(old); err != nil {
+			return fmt.Errorf("failed to remove previous index %s: %w", old, err)
+		}
+	}
+	return nil
+}
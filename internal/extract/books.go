@@ -0,0 +1,165 @@
+package extract
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+type ScriptureBook struct {
+	UBS  string `xml:"ubsAbbreviation,attr"`
+	Parm string `xml:"parm,attr"`
+	Text string `xml:",chardata"`
+}
+
+type VernacularParms struct {
+	Books []ScriptureBook `xml:"scriptureBook"`
+}
+
+func loadOSISMapping(indexDir string) (map[string]string, error) {
+	osisData, err := os.ReadFile(filepath.Join(indexDir, "osis.json")) // nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+
+	var osisMap map[string]string
+	err = json.Unmarshal(osisData, &osisMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return osisMap, nil
+}
+
+func getOSISFromName(bookName string, osisMap map[string]string) string {
+	for osis, name := range osisMap {
+		if name == bookName {
+			return osis
+		}
+	}
+	return ""
+}
+
+// Manual mapping for books where XML names don't match OSIS names
+var osisNameOverrides = map[string]string{
+	"Song of Solomon":        "Song",
+	"Esther (Greek)":         "AddEsth",
+	"3 Holy Children's Song": "SgThree",
+	"Prayer of Manasses":     "PrMan",
+}
+
+// readVernacularParms reads eng-kjv-VernacularParms.xml in metadataDir, grouping its parameters by UBS abbreviation
+func readVernacularParms(metadataDir string) (map[string]map[string]string, error) {
+	// Read XML file
+	xmlData, err := os.ReadFile(filepath.Join(metadataDir, "eng-kjv-VernacularParms.xml")) // nolint: gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to read XML file: %w", err)
+	}
+
+	// Parse XML
+	var parms VernacularParms
+	err = xml.Unmarshal(xmlData, &parms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse XML: %w", err)
+	}
+
+	// Group books by abbreviation
+	booksByAbbr := make(map[string]map[string]string)
+	for _, book := range parms.Books {
+		if _, exists := booksByAbbr[book.UBS]; !exists {
+			booksByAbbr[book.UBS] = make(map[string]string)
+		}
+		booksByAbbr[book.UBS][book.Parm] = strings.TrimSpace(book.Text)
+	}
+	return booksByAbbr, nil
+}
+
+// ExtractBooks builds books.json from eng-kjv-VernacularParms.xml in metadataDir, resolving OSIS
+// codes through osis.json in indexDir. Book order, testaments, and chapter counts come from structure.
+func ExtractBooks(metadataDir, indexDir string, structure *util.CanonStructure) (*model.BooksData, error) {
+	// Load OSIS mapping
+	osisMap, err := loadOSISMapping(indexDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OSIS mapping: %w", err)
+	}
+
+	booksByAbbr, err := readVernacularParms(metadataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create output
+	output := &model.BooksData{
+		Schema: model.BooksSchema,
+		Work:   structure.Work,
+		Books:  []model.BookMetadata{},
+	}
+
+	// Process each book in order
+	for i, entry := range structure.Books {
+		abbr := entry.Abbr
+		if info, exists := booksByAbbr[abbr]; exists {
+			fullName := strings.TrimSpace(info["vernacularFullName"])
+			abbrevName := strings.TrimSpace(info["vernacularAbbreviatedName"])
+
+			// Clean up multi-line names (normalize whitespace)
+			fullName = strings.Join(strings.Fields(fullName), " ")
+
+			// Get OSIS code from mapping using abbreviated name
+			osis := getOSISFromName(abbrevName, osisMap)
+			if osis == "" {
+				// Try the overrides map
+				altOsis, exists := osisNameOverrides[abbrevName]
+				if !exists {
+					return nil, fmt.Errorf("could not find OSIS code for %s (%s)", abbrevName, abbr)
+				}
+				osis = altOsis
+			}
+
+			// Create aliases with both names and any from the structure, removing duplicates
+			aliases := make([]string, 0)
+			aliasMap := make(map[string]bool)
+			for _, alias := range append([]string{abbrevName, fullName}, entry.Aliases...) {
+				if alias != "" && !aliasMap[alias] {
+					aliases = append(aliases, alias)
+					aliasMap[alias] = true
+				}
+			}
+
+			book := model.BookMetadata{
+				OSIS:      osis,
+				Abbr:      abbr,
+				Name:      abbrevName,
+				Aliases:   aliases,
+				Testament: entry.Testament,
+				Order:     i + 1,
+				Chapters:  entry.Chapters,
+			}
+			output.Books = append(output.Books, book)
+		}
+	}
+
+	return output, nil
+}
+
+// writeJSON writes v to path as indented JSON
+func writeJSON(path string, v interface{}) error {
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := atomicfile.WriteFile(path, jsonData, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package extract
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// OSISCmd derives the OSIS code to book name mapping into osis.json
+type OSISCmd struct {
+	MetadataDir string `type:"existingdir"  help:"Directory containing eng-kjv-VernacularParms.xml"           default:"raw/metadata"`
+	RawDir      string `type:"existingdir"  help:"Raw source directory containing html/"                      default:"raw"`
+	IndexDir    string `                    help:"Index directory to write osis.json into"                    default:"canon/kjv/index"`
+	Output      string `                    help:"File to write (default: osis.json in --index-dir)"`
+	Structure   string `type:"existingfile" help:"Canon structure file listing book order and chapter counts" default:"metadata/canon-structure.json"`
+	ForceUnlock bool   `                    help:"Take the lock even from a run holding it, such as a hung one"`
+}
+
+// BooksCmd extracts book metadata into books.json
+type BooksCmd struct {
+	MetadataDir string `type:"existingdir"  help:"Directory containing eng-kjv-VernacularParms.xml"           default:"raw/metadata"`
+	IndexDir    string `type:"existingdir"  help:"Index directory containing osis.json"                       default:"canon/kjv/index"`
+	Output      string `                    help:"File to write (default: books.json in --index-dir)"`
+	Structure   string `type:"existingfile" help:"Canon structure file listing book order and chapter counts" default:"metadata/canon-structure.json"`
+	ForceUnlock bool   `                    help:"Take the lock even from a run holding it, such as a hung one"`
+}
+
+// AliasesCmd extracts source abbreviation and chapter mappings into aliases.json
+type AliasesCmd struct {
+	IndexDir    string `type:"existingdir" help:"Index directory containing books.json"                default:"canon/kjv/index"`
+	RawDir      string `type:"existingdir" help:"Raw source directory containing html/"                default:"raw"`
+	Output      string `                   help:"File to write (default: aliases.json in --index-dir)"`
+	ForceUnlock bool   `                   help:"Take the lock even from a run holding it, such as a hung one"`
+}
+
+// AllCmd runs every extract step and replaces the index directory once they all succeed
+type AllCmd struct {
+	MetadataDir string `type:"existingdir"  help:"Directory containing eng-kjv-VernacularParms.xml"           default:"raw/metadata"`
+	RawDir      string `type:"existingdir"  help:"Raw source directory containing html/"                      default:"raw"`
+	IndexDir    string `                    help:"Index directory to build; created if missing"               default:"canon/kjv/index"`
+	Structure   string `type:"existingfile" help:"Canon structure file listing book order and chapter counts" default:"metadata/canon-structure.json"`
+	ForceUnlock bool   `                    help:"Take the lock even from a run holding it, such as a hung one"`
+}
+
+// Cmd extracts index metadata from the raw sources
+type Cmd struct {
+	OSIS    OSISCmd    `cmd:"" help:"Derive OSIS codes and book names into osis.json" name:"osis"`
+	Books   BooksCmd   `cmd:"" help:"Extract book metadata into books.json"`
+	Aliases AliasesCmd `cmd:"" help:"Extract source abbreviations and chapter mappings into aliases.json"`
+	All     AllCmd     `cmd:"" help:"Build osis.json, books.json, and aliases.json in order, replacing the index only if every step succeeds"`
+}
+
+// Run writes osis.json
+func (c *OSISCmd) Run(ctx context.Context) error {
+	lock, err := lockCanon(c.IndexDir, "extract osis", c.ForceUnlock)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	path := c.Output
+	if path == "" {
+		path = filepath.Join(c.IndexDir, "osis.json")
+	}
+
+	structure, err := util.LoadCanonStructure(c.Structure)
+	var osis OSISOutput
+	var counts map[string]int
+	if err == nil {
+		osis, counts, err = ExtractOSIS(c.MetadataDir, c.RawDir, structure)
+	}
+	if err == nil {
+		err = writeJSON(path, osis)
+	}
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	files := 0
+	for _, n := range counts {
+		files += n
+	}
+	fmt.Printf("Successfully created %s (%d books, %d chapter files)\n", path, len(osis), files)
+	return nil
+}
+
+// Run writes books.json
+func (c *BooksCmd) Run(ctx context.Context) error {
+	lock, err := lockCanon(c.IndexDir, "extract books", c.ForceUnlock)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	path := c.Output
+	if path == "" {
+		path = filepath.Join(c.IndexDir, "books.json")
+	}
+
+	structure, err := util.LoadCanonStructure(c.Structure)
+	var output *model.BooksData
+	if err == nil {
+		output, err = ExtractBooks(c.MetadataDir, c.IndexDir, structure)
+	}
+	if err == nil {
+		err = writeJSON(path, output)
+	}
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully created %s (%d books)\n", path, len(output.Books))
+	return nil
+}
+
+// Run writes aliases.json
+func (c *AliasesCmd) Run(ctx context.Context) error {
+	lock, err := lockCanon(c.IndexDir, "extract aliases", c.ForceUnlock)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	path := c.Output
+	if path == "" {
+		path = filepath.Join(c.IndexDir, "aliases.json")
+	}
+
+	aliases, err := ExtractAliases(c.IndexDir, c.RawDir)
+	if err == nil {
+		err = writeJSON(path, aliases)
+	}
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Successfully created %s (%d books)\n", path, len(aliases))
+	return nil
+}
+
+// Run bootstraps the index, reporting each step as it runs
+func (c *AllCmd) Run() error {
+	lock, err := lockCanon(c.IndexDir, "extract all", c.ForceUnlock)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	structure, err := util.LoadCanonStructure(c.Structure)
+	if err != nil {
+		return err
+	}
+
+	src := Sources{MetadataDir: c.MetadataDir, RawDir: c.RawDir, Structure: structure}
+	err = Bootstrap(src, c.IndexDir, func(step int, file, summary string, done bool) {
+		if !done {
+			fmt.Printf("[%d/%d] %s...", step, len(steps), file)
+			return
+		}
+		fmt.Printf(" %s\n", summary)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Index: %s\n", c.IndexDir)
+	fmt.Printf("========================================\n")
+	return nil
+}
+
+// lockCanon takes the lock on the canon directory above indexDir, the same lock ingest takes, so
+// the index is never rebuilt while chapters are being written against it
+func lockCanon(indexDir, command string, force bool) (*util.Lock, error) {
+	canonDir := filepath.Dir(filepath.Clean(indexDir))
+	if err := os.MkdirAll(canonDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", canonDir, err)
+	}
+	return util.AcquireLock(canonDir, command, force)
+}
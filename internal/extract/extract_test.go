@@ -0,0 +1,268 @@
+package extract
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// findRoot returns the project root, which holds raw/ and canon/kjv/index
+func findRoot(t *testing.T) string {
+	t.Helper()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			return cwd
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+}
+
+// loadStructure loads the canon structure shipped with the repository
+func loadStructure(t *testing.T, root string) *util.CanonStructure {
+	t.Helper()
+
+	structure, err := util.LoadCanonStructure(filepath.Join(root, util.DefaultCanonStructure))
+	if err != nil {
+		t.Fatalf("failed to load canon structure: %v", err)
+	}
+	return structure
+}
+
+// marshal returns v as the indented JSON the extract commands write
+func marshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	return data
+}
+
+func TestExtractMatchesIndex(t *testing.T) {
+	root := findRoot(t)
+	indexDir := filepath.Join(root, "canon", "kjv", "index")
+
+	structure := loadStructure(t, root)
+
+	osis, counts, err := ExtractOSIS(filepath.Join(root, "raw", "metadata"), filepath.Join(root, "raw"), structure)
+	if err != nil {
+		t.Fatalf("ExtractOSIS failed: %v", err)
+	}
+	if counts["Ps"] != 150 || counts["AddEsth"] != 7 {
+		t.Errorf("expected 150 Ps and 7 Add Esth chapter files, got %d and %d", counts["Ps"], counts["AddEsth"])
+	}
+
+	books, err := ExtractBooks(filepath.Join(root, "raw", "metadata"), indexDir, structure)
+	if err != nil {
+		t.Fatalf("ExtractBooks failed: %v", err)
+	}
+	aliases, err := ExtractAliases(indexDir, filepath.Join(root, "raw"))
+	if err != nil {
+		t.Fatalf("ExtractAliases failed: %v", err)
+	}
+
+	tests := []struct {
+		file string
+		got  []byte
+	}{
+		{"osis.json", marshal(t, osis)},
+		{"books.json", marshal(t, books)},
+		{"aliases.json", marshal(t, aliases)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			want, err := os.ReadFile(filepath.Join(indexDir, tt.file)) // nolint: gosec
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", tt.file, err)
+			}
+			if string(tt.got) != string(want) {
+				t.Errorf("extracted %s differs from the committed index", tt.file)
+			}
+		})
+	}
+}
+
+func TestCommandsWriteOutput(t *testing.T) {
+	root := findRoot(t)
+	indexDir := filepath.Join(root, "canon", "kjv", "index")
+	out := t.TempDir()
+
+	books := &BooksCmd{
+		MetadataDir: filepath.Join(root, "raw", "metadata"),
+		IndexDir:    indexDir,
+		Output:      filepath.Join(out, "books.json"),
+		Structure:   filepath.Join(root, util.DefaultCanonStructure),
+	}
+	if err := books.Run(context.Background()); err != nil {
+		t.Fatalf("books failed: %v", err)
+	}
+
+	aliases := &AliasesCmd{
+		IndexDir: indexDir,
+		RawDir:   filepath.Join(root, "raw"),
+		Output:   filepath.Join(out, "aliases.json"),
+	}
+	if err := aliases.Run(context.Background()); err != nil {
+		t.Fatalf("aliases failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, "aliases.json")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read aliases.json: %v", err)
+	}
+	var parsed model.AliasesData
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("failed to parse aliases.json: %v", err)
+	}
+	if got := parsed["Gen"].Chapters["1"]; got != filepath.Join("raw", "html", "ot", "GEN", "GEN01.htm") {
+		t.Errorf("expected Gen 1 to map to raw/html/ot/GEN/GEN01.htm, got %q", got)
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	root := findRoot(t)
+	structure := loadStructure(t, root)
+	empty := t.TempDir()
+
+	tests := []struct {
+		name string
+		run  func() error
+		want string
+	}{
+		{
+			name: "books without osis.json",
+			run: func() error {
+				_, err := ExtractBooks(filepath.Join(root, "raw", "metadata"), empty, structure)
+				return err
+			},
+			want: "OSIS mapping",
+		},
+		{
+			name: "books without metadata",
+			run: func() error {
+				_, err := ExtractBooks(empty, filepath.Join(root, "canon", "kjv", "index"), structure)
+				return err
+			},
+			want: "XML file",
+		},
+		{
+			name: "osis without raw files",
+			run: func() error {
+				_, _, err := ExtractOSIS(filepath.Join(root, "raw", "metadata"), empty, structure)
+				return err
+			},
+			want: "no raw HTML chapter files",
+		},
+		{
+			name: "aliases without books.json",
+			run: func() error {
+				_, err := ExtractAliases(empty, filepath.Join(root, "raw"))
+				return err
+			},
+			want: "books.json",
+		},
+		{
+			name: "aliases without raw files",
+			run: func() error {
+				_, err := ExtractAliases(filepath.Join(root, "canon", "kjv", "index"), empty)
+				return err
+			},
+			want: "no raw HTML chapter files",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	root := findRoot(t)
+	src := Sources{
+		MetadataDir: filepath.Join(root, "raw", "metadata"),
+		RawDir:      filepath.Join(root, "raw"),
+		Structure:   loadStructure(t, root),
+	}
+	committed := filepath.Join(root, "canon", "kjv", "index")
+
+	parent := t.TempDir()
+	indexDir := filepath.Join(parent, "index")
+	if err := os.MkdirAll(indexDir, 0750); err != nil {
+		t.Fatalf("failed to create index dir: %v", err)
+	}
+	for name, content := range map[string]string{"filemap.json": `{"kept":true}`, "books.json": "stale"} {
+		if err := os.WriteFile(filepath.Join(indexDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	// A failing step leaves the existing index untouched
+	broken := src
+	broken.MetadataDir = t.TempDir()
+	err := Bootstrap(broken, indexDir, func(int, string, string, bool) {})
+	if err == nil || !strings.Contains(err.Error(), "osis.json") {
+		t.Fatalf("expected osis.json step to fail, got %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(indexDir, "books.json")); string(data) != "stale" { // nolint: gosec
+		t.Errorf("expected failed bootstrap to leave books.json untouched, got %q", data)
+	}
+
+	var done []string
+	err = Bootstrap(src, indexDir, func(_ int, file, _ string, finished bool) {
+		if finished {
+			done = append(done, file)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Bootstrap failed: %v", err)
+	}
+	if strings.Join(done, ",") != "osis.json,books.json,aliases.json" {
+		t.Errorf("expected steps in dependency order, got %v", done)
+	}
+
+	for _, name := range []string{"osis.json", "books.json", "aliases.json"} {
+		got, err := os.ReadFile(filepath.Join(indexDir, name)) // nolint: gosec
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		want, err := os.ReadFile(filepath.Join(committed, name)) // nolint: gosec
+		if err != nil {
+			t.Fatalf("failed to read committed %s: %v", name, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("bootstrapped %s differs from the committed index", name)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(indexDir, "filemap.json")); string(data) != `{"kept":true}` { // nolint: gosec
+		t.Errorf("expected filemap.json to be carried over, got %q", data)
+	}
+
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", parent, err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the index directory to remain, got %d entries", len(entries))
+	}
+}
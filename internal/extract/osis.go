@@ -0,0 +1,57 @@
+package extract
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+// OSISOutput is the structure of osis.json (map of OSIS code -> book name)
+type OSISOutput map[string]string
+
+// chapterFilePattern matches raw chapter file names such as GEN01.htm and PSA119.htm
+var chapterFilePattern = regexp.MustCompile(`^([0-9A-Z]{3})\d{2,3}\.htm$`)
+
+// ExtractOSIS builds osis.json from the vernacular abbreviated names in metadataDir, keeping the
+// books in structure that have chapter files beneath rawDir/html. It also returns the number of
+// chapter files found for each OSIS code.
+func ExtractOSIS(metadataDir, rawDir string, structure *util.CanonStructure) (OSISOutput, map[string]int, error) {
+	booksByAbbr, err := readVernacularParms(metadataDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	htmlDir := filepath.Join(rawDir, "html")
+	chapterFiles := make(map[string]int) // UBS abbreviation -> chapter files
+	for name := range rawChapterFiles(htmlDir) {
+		if m := chapterFilePattern.FindStringSubmatch(name); m != nil {
+			chapterFiles[m[1]]++
+		}
+	}
+	if len(chapterFiles) == 0 {
+		return nil, nil, fmt.Errorf("no raw HTML chapter files found in %s", htmlDir)
+	}
+
+	output := make(OSISOutput)
+	counts := make(map[string]int)
+	for _, book := range structure.Books {
+		abbr, osis := book.Abbr, book.OSIS
+		info, exists := booksByAbbr[abbr]
+		if !exists || chapterFiles[abbr] == 0 {
+			continue
+		}
+
+		name := strings.Join(strings.Fields(info["vernacularAbbreviatedName"]), " ")
+		if name == "" {
+			return nil, nil, fmt.Errorf("no vernacular abbreviated name for %s", abbr)
+		}
+
+		output[osis] = name
+		counts[osis] = chapterFiles[abbr]
+	}
+
+	return output, counts, nil
+}
@@ -0,0 +1,50 @@
+package ingest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// benchRawFile reads a raw chapter file from the project's raw/html directory
+func benchRawFile(b *testing.B, parts ...string) []byte {
+	b.Helper()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		b.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			b.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	data, err := os.ReadFile(filepath.Join(append([]string{cwd, "raw", "html"}, parts...)...)) // nolint: gosec
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+func BenchmarkParse(b *testing.B) {
+	parser := NewParser()
+	for _, file := range [][]string{{"ot", "PSA", "PSA119.htm"}, {"ot", "GEN", "GEN01.htm"}} {
+		content := benchRawFile(b, file...)
+		b.Run(file[2], func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(content)))
+			for b.Loop() {
+				if _, err := parser.Parse(bytes.NewReader(content), file[2]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/export"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Cmd processes raw HTML chapter files into the canon
+type Cmd struct {
+	RawDir              string   `type:"existingdir" help:"Directory containing raw HTML chapter files"                                     default:"raw"`
+	OutputDir           string   `type:"existingdir" help:"Directory to write processed output files"                                       default:"canon/kjv"`
+	Books               string   `                   help:"Books to process: all, ot, ap, nt, a group (e.g. gospels), or a list (GEN,PSA)"  default:"all" aliases:"book"`
+	Chapter             string   `                   help:"Chapter or range of chapters of a single book to process (e.g. 119 or 1-50)"`
+	Work                string   `                   help:"The work identifier"                                                             default:"KJV"`
+	Format              []string `                   help:"Comma-separated output formats (json, usfm, osis, or any registered exporter)"   default:"json"`
+	Compress            string   `                   help:"Compress chapter files written by the json format: none, gzip, or zstd"          default:"none"  enum:"none,gzip,zstd"`
+	Layout              string   `                   help:"Layout of json chapter files: tree, or cas to store them by content hash"        default:"tree"  enum:"tree,cas"`
+	Manifest            bool     `                   help:"Generate SHA256 manifest of raw files"                                           default:"false"`
+	ManifestIncremental bool     `                   help:"Reuse manifest hashes of raw files unmodified since the manifest was written"    default:"false"`
+	Verbose             bool     `                   help:"Enable verbose logging output"                                                   default:"false"`
+	Structure           string   `                   help:"Canon structure file to check books.json against (empty to skip)"                default:"metadata/canon-structure.json"`
+	Report              string   `                   help:"Write a JSON report of per-book results and stage timings to this file"`
+	Fsync               bool     `                   help:"Flush every written file to disk before renaming it into place"                   default:"false"`
+	ForceUnlock         bool     `                   help:"Take the lock on --output-dir even from a run holding it, such as a hung one"     default:"false"`
+	RebuildFilemap      bool     `                   help:"Regenerate filemap.json from this run only instead of merging into it"            default:"false"`
+	Lenient             bool     `                   help:"Recover what can be kept of malformed chapter files, reporting warnings instead"  default:"false"`
+	Sanitize            string   `                   help:"Repair known defects of raw HTML before parsing: none, or ebible"                 default:"none"  enum:"none,ebible"`
+	Watch               bool     `                   help:"Keep running, re-ingesting chapters of --books as their raw files change"         default:"false"`
+	SummaryFormat       string   `                   help:"Format of the summary of a run over several books: table, csv, or tsv"            default:"table" enum:"table,csv,tsv"`
+	WarningsAsErrors    bool     `                   help:"Exit with status 2 when a run has warnings but no errors"                         default:"false"`
+	Ignore              []string `                   help:"Error and warning codes left out of the exit status (e.g. footnotes,coverage)"`
+}
+
+// Run ingests the selected books, writes the filemap and optional manifest, and prints a summary
+func (c *Cmd) Run(ctx context.Context) error {
+	atomicfile.SetSync(c.Fsync)
+	lock, err := util.AcquireLock(c.OutputDir, "ingest", c.ForceUnlock)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	indexDir := filepath.Join(c.OutputDir, "index")
+	// Create processor
+	processor, err := NewProcessor(indexDir, c.RawDir, c.OutputDir, c.Work, c.Format, c.Verbose)
+	if err != nil {
+		return fmt.Errorf("Error: failed to initialize processor: %v\n", err)
+	}
+	if c.Structure != "" {
+		structure, err := util.LoadCanonStructure(c.Structure)
+		if err != nil {
+			return err
+		}
+		processor.UseStructure(structure)
+	}
+	processor.UseLenient(c.Lenient)
+	if c.Chapter != "" {
+		first, last, err := ParseChapterRange(c.Chapter)
+		if err != nil {
+			return err
+		}
+		processor.UseChapters(first, last)
+	}
+	if c.Compress != "none" || c.Layout != "tree" {
+		codec, err := compression.Parse(c.Compress)
+		if err != nil {
+			return err
+		}
+		processor.UseJSON(export.JSONOptions{Compression: codec, Layout: export.Layout(c.Layout)})
+	}
+	if c.Sanitize != "none" {
+		sanitizer, err := NewSanitizer(c.Sanitize)
+		if err != nil {
+			return err
+		}
+		processor.UseSanitizer(sanitizer)
+	}
+
+	// Get list of books to process
+	booksToProcess, err := processor.SelectBooks(c.Books)
+	if err != nil {
+		return err
+	}
+	allBooks, err := processor.GetAllBookAbbreviations()
+	if err != nil {
+		return fmt.Errorf("failed to load book metadata: %v", err)
+	}
+	single := len(booksToProcess) == 1
+
+	// Formats other than json write whole books, which one chapter would overwrite
+	if c.Chapter != "" && (!single || !slices.Equal(c.Format, []string{"json"})) {
+		return fmt.Errorf("--chapter requires a single book in --books and --format=json")
+	}
+
+	if c.Watch {
+		if c.Chapter != "" || !slices.Equal(c.Format, []string{"json"}) {
+			return fmt.Errorf("--watch requires --format=json and cannot be combined with --chapter")
+		}
+		ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+		defer cancel()
+		util.StopProgress(ctx)
+		fmt.Printf("Watching %d book(s) under %s for changes; press Ctrl-C to stop\n", len(booksToProcess), c.RawDir)
+		return processor.Watch(ctx, booksToProcess, processor.PrintResult)
+	}
+
+	// Process books
+	totalProcessed := 0
+	totalSkipped := 0
+	totalErrors := 0
+	totalWarnings := 0
+	totalFixes := make(map[string]int)
+	var allResults []*util.ProcessResult
+	var timings util.StageTimings
+	combinedFileMap := model.NewFileMap()
+	combinedVerses := model.NewVerseIndex()
+	gate := util.NewGate(c.Ignore, c.WarningsAsErrors)
+	// fatal is the first failure that kept the run from completing, which ends it with util.ExitFatal
+	var fatal error
+	fail := func(err error) {
+		fmt.Printf("Error: %v\n", err)
+		if fatal == nil {
+			fatal = err
+		}
+	}
+
+	if err := processor.BeginExport(); err != nil {
+		return fmt.Errorf("failed to start exporters: %w", err)
+	}
+
+	for _, abbr := range booksToProcess {
+		result, err := processor.ProcessBook(abbr)
+		if err != nil {
+			fail(fmt.Errorf("failed to process %s: %w", abbr, err))
+			continue
+		}
+		totalProcessed += result.FilesProcessed
+		totalSkipped += result.FilesSkipped
+		totalErrors += len(result.Errors)
+		totalWarnings += len(result.Warnings)
+		for _, e := range result.Errors {
+			gate.Error(e.Type)
+		}
+		for _, w := range result.Warnings {
+			gate.Warning(w.Type)
+		}
+		for name, n := range result.Fixes {
+			totalFixes[name] += n
+		}
+		timings.Add(result.Timings)
+
+		// Accumulate filemap entries
+		combinedFileMap.Merge(result.FileMap)
+		combinedVerses.Merge(result.Verses)
+
+		if single {
+			processor.PrintResult(result)
+		} else if c.Verbose {
+			// In verbose mode with several books, show results for books with errors
+			if len(result.Errors) > 0 || len(result.Warnings) > 0 {
+				processor.PrintResult(result)
+			}
+		}
+		allResults = append(allResults, result)
+	}
+
+	if err := processor.FinishExport(); err != nil {
+		fail(fmt.Errorf("failed to finish exporters: %w", err))
+	}
+
+	// Write the combined filemap after all books are processed
+	if len(combinedFileMap.Files) > 0 {
+		conflicts, err := processor.WriteFileMap(combinedFileMap, c.RebuildFilemap)
+		if err != nil {
+			fail(fmt.Errorf("failed to write filemap: %w", err))
+		}
+		for _, conflict := range conflicts {
+			gate.Warning("filemap")
+			fmt.Printf(
+				"Warning: filemap conflict: %s is now produced from %s, dropped the entry for %s\n",
+				conflict.Output,
+				conflict.Incoming,
+				conflict.Existing,
+			)
+		}
+	}
+	if len(combinedVerses.Books) > 0 {
+		if err := processor.WriteVerseIndex(combinedVerses); err != nil {
+			fail(fmt.Errorf("failed to write verse index: %w", err))
+		}
+	}
+
+	// Generate the manifest once, scoped to the processed books unless processing all of them
+	if c.Manifest {
+		var scope []string
+		if len(booksToProcess) < len(allBooks) {
+			scope = booksToProcess
+		}
+		if err := processor.GenerateManifest(scope, c.ManifestIncremental); err != nil {
+			fail(fmt.Errorf("failed to generate manifest: %w", err))
+		}
+	}
+
+	if c.Report != "" {
+		if err := NewReport(c.Work, allResults).Write(c.Report); err != nil {
+			fail(err)
+		}
+	}
+
+	util.StopProgress(ctx)
+
+	// Print summary if processing several books
+	if !single {
+		if c.SummaryFormat != "table" {
+			// csv and tsv print the rows alone, so spreadsheets and scripts can read them
+			if err := WriteSummary(os.Stdout, c.SummaryFormat, allResults); err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("\n========================================\n")
+			if err := WriteSummary(os.Stdout, c.SummaryFormat, allResults); err != nil {
+				return err
+			}
+			fmt.Printf("\nTotal Files Processed: %d\n", totalProcessed)
+			fmt.Printf("Total Files Skipped: %d\n", totalSkipped)
+			if c.Lenient {
+				fmt.Printf("Total Warnings: %d\n", totalWarnings)
+			}
+			if c.Sanitize != "none" {
+				fmt.Printf("Total Fixes: %s\n", FormatFixes(totalFixes))
+			}
+			if gate.Ignored > 0 {
+				fmt.Printf("Total Ignored: %d\n", gate.Ignored)
+			}
+			fmt.Printf("Stages: %v\n", timings)
+			fmt.Printf("========================================\n")
+
+			if c.Verbose && totalErrors > 0 {
+				fmt.Printf("\nDetailed Error Report:\n")
+				for _, result := range allResults {
+					if len(result.Errors) > 0 {
+						fmt.Printf("\n%s (%s) - %d error(s):\n", result.Book, result.OSIS, len(result.Errors))
+						for i, err := range result.Errors {
+							fmt.Printf("  %d. [%s] %s", i+1, err.Type, err.Message)
+							if err.File != "" {
+								fmt.Printf(" (%s%s)", err.File, atLocation(err.Location))
+							}
+							fmt.Printf("\n")
+							if err.Suggestion != "" {
+								fmt.Printf("     Suggestion: %s\n", err.Suggestion)
+							}
+						}
+					}
+				}
+				fmt.Printf("========================================\n")
+			}
+		}
+	}
+
+	if fatal != nil {
+		return fatal
+	}
+	return gate.Err("processing")
+}
@@ -0,0 +1,53 @@
+package ingest
+
+import (
+	"fmt"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Hook lets code embedding the ingest library inspect or transform chapters as they pass through
+// a Processor, such as to attach Strong's numbers or rewrite tokens, without forking the pipeline.
+// Hooks may modify the chapter they are given in place. An error skips the chapter and is recorded
+// as a hook error, except from OnChapterWritten, whose chapter has already been written.
+type Hook interface {
+	// OnChapterParsed is called with each chapter as extracted from raw HTML, before it is validated
+	OnChapterParsed(ec *util.ExtractedChapter) error
+	// OnChapterValidated is called with each chapter that passed validation, converted to the
+	// canonical model, before any exporter writes it
+	OnChapterValidated(chapter *model.Chapter) error
+	// OnChapterWritten is called once every exporter has written the chapter
+	OnChapterWritten(chapter *model.Chapter) error
+}
+
+// NopHook implements every Hook method as a no-op; embed it to implement only some of them
+type NopHook struct{}
+
+func (NopHook) OnChapterParsed(*util.ExtractedChapter) error { return nil }
+func (NopHook) OnChapterValidated(*model.Chapter) error      { return nil }
+func (NopHook) OnChapterWritten(*model.Chapter) error        { return nil }
+
+// UseHook adds h to the hooks called for each chapter; hooks are called in the order they were added
+func (proc *Processor) UseHook(h Hook) {
+	proc.hooks = append(proc.hooks, h)
+}
+
+// runHooks calls stage on each hook in turn, stopping at the first error, which it records in
+// result as a hook error of filename. It reports whether every hook succeeded.
+func (proc *Processor) runHooks(result *util.ProcessResult, filename, stage string, call func(Hook) error) bool {
+	for _, h := range proc.hooks {
+		if err := call(h); err != nil {
+			if proc.verbose {
+				fmt.Printf("  Error in %s hook for %s: %v\n", stage, filename, err)
+			}
+			result.Errors = append(result.Errors, util.ValidationError{
+				File:    filename,
+				Type:    "hook",
+				Message: fmt.Sprintf("%s hook failed: %v", stage, err),
+			})
+			return false
+		}
+	}
+	return true
+}
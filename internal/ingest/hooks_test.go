@@ -0,0 +1,95 @@
+package ingest
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// recordingHook records the stages it is called at and marks the first token of each verse
+type recordingHook struct {
+	NopHook
+	calls     []string
+	failParse bool
+}
+
+func (h *recordingHook) OnChapterParsed(ec *util.ExtractedChapter) error {
+	h.calls = append(h.calls, "parsed")
+	if h.failParse {
+		return errors.New("no Strong's data for " + ec.SourceFile)
+	}
+	return nil
+}
+
+func (h *recordingHook) OnChapterValidated(chapter *model.Chapter) error {
+	h.calls = append(h.calls, "validated")
+	for i := range chapter.Verses {
+		chapter.Verses[i].Tokens[0].Text = "H7225 " + chapter.Verses[i].Tokens[0].Text
+	}
+	return nil
+}
+
+func (h *recordingHook) OnChapterWritten(chapter *model.Chapter) error {
+	h.calls = append(h.calls, "written")
+	return nil
+}
+
+func TestProcessChapterHooks(t *testing.T) {
+	indexDir, rawDir, outputDir := genesisFixture(t, map[string]string{"GEN03.htm": chapterHTML})
+	bookMeta := model.BookMetadata{OSIS: "Gen", Abbr: "GEN", Name: "Genesis", Chapters: 50}
+
+	process := func(hooks ...Hook) *util.ProcessResult {
+		proc, err := NewProcessor(indexDir, rawDir, outputDir, "KJV", []string{"json"}, false)
+		if err != nil {
+			t.Fatalf("failed to create processor: %v", err)
+		}
+		for _, h := range hooks {
+			proc.UseHook(h)
+		}
+		if err := proc.BeginExport(); err != nil {
+			t.Fatalf("failed to start exporters: %v", err)
+		}
+		result := &util.ProcessResult{FileMap: model.NewFileMap(), Verses: model.NewVerseIndex()}
+		proc.processChapter(result, "raw/html/ot/GEN/GEN03.htm", bookMeta)
+		if err := proc.FinishExport(); err != nil {
+			t.Fatalf("failed to finish exporters: %v", err)
+		}
+		return result
+	}
+
+	hook := &recordingHook{}
+	result := process(hook)
+	if len(result.Errors) != 0 || result.FilesSkipped != 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	if want := []string{"parsed", "validated", "written"}; !slices.Equal(hook.calls, want) {
+		t.Errorf("hook calls = %v, want %v", hook.calls, want)
+	}
+	var chapter model.Chapter
+	data, err := os.ReadFile(filepath.Join(outputDir, "books", "Gen", "ch03.json")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("chapter was not written: %v", err)
+	}
+	if err := json.Unmarshal(data, &chapter); err != nil {
+		t.Fatalf("failed to unmarshal ch03.json: %v", err)
+	}
+	if got := chapter.Verses[0].Tokens[0].Text; got != "H7225 In the beginning. " {
+		t.Errorf("first token = %q, want the hook's transform written", got)
+	}
+
+	// A failing hook skips the chapter and stops later hooks
+	failing, later := &recordingHook{failParse: true}, &recordingHook{}
+	result = process(failing, later)
+	if result.FilesSkipped != 1 || len(result.Errors) != 1 || result.Errors[0].Type != "hook" {
+		t.Errorf("got %d skipped and errors %v, want 1 hook error", result.FilesSkipped, result.Errors)
+	}
+	if len(later.calls) != 0 {
+		t.Errorf("later hook was called: %v", later.calls)
+	}
+}
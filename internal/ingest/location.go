@@ -0,0 +1,62 @@
+package ingest
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+// locate finds the start tag of element, "#id" or ".class", in raw HTML and returns its 1-based
+// line and byte column, or 0, 0 when no tag has it
+func locate(raw []byte, element string) (int, int) {
+	var pattern string
+	switch {
+	case strings.HasPrefix(element, "#"):
+		pattern = `\bid\s*=\s*["']?` + regexp.QuoteMeta(element[1:]) + `["'\s/>]`
+	case strings.HasPrefix(element, "."):
+		pattern = `\bclass\s*=\s*["'](?:[^"']*\s)?` + regexp.QuoteMeta(element[1:]) + `["'\s]`
+	default:
+		return 0, 0
+	}
+
+	match := regexp.MustCompile(pattern).FindIndex(raw)
+	if match == nil {
+		return 0, 0
+	}
+	start := bytes.LastIndexByte(raw[:match[0]], '<')
+	if start < 0 {
+		start = match[0]
+	}
+	lineStart := bytes.LastIndexByte(raw[:start], '\n') + 1
+	return bytes.Count(raw[:start], []byte{'\n'}) + 1, start - lineStart + 1
+}
+
+// locateErrors fills in the line and column of each error that names an element, from the raw
+// HTML the errors were found in
+func locateErrors(raw []byte, errs []util.ValidationError) {
+	for i := range errs {
+		loc := &errs[i].Location
+		if loc.Element != "" && loc.Line == 0 {
+			loc.Line, loc.Column = locate(raw, loc.Element)
+		}
+	}
+}
+
+// elementLocation returns the location of the element with an id, to be placed in the file by
+// locateErrors; an empty id gives no location
+func elementLocation(id string) util.SourceLocation {
+	if id == "" {
+		return util.SourceLocation{}
+	}
+	return util.SourceLocation{Element: "#" + id}
+}
+
+// atLocation formats a location to follow a message, as " at line 3, column 120 (#V12)"
+func atLocation(loc util.SourceLocation) string {
+	if loc.Element == "" {
+		return ""
+	}
+	return " at " + loc.String()
+}
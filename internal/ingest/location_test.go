@@ -0,0 +1,50 @@
+package ingest
+
+import (
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func TestLocate(t *testing.T) {
+	raw := []byte("<html><body>\n<div class='chapterlabel' id=\"V0\"> 3</div><div class='p'>\n" +
+		"  <span class=\"verse\" id=\"V1\">1&#160;</span>In the beginning. <span class=\"verse\" id='V12'>12</span>\n" +
+		"<p class=\"f\" id=FN1>note</p></body></html>")
+
+	tests := []struct {
+		element      string
+		line, column int
+	}{
+		{"#V0", 2, 1},
+		{"#V1", 3, 3},
+		{"#V12", 3, 63},
+		{"#FN1", 4, 1},
+		{".chapterlabel", 2, 1},
+		{".p", 2, 43},
+		{"#V2", 0, 0},
+		{".verse-label", 0, 0},
+		{"V1", 0, 0},
+	}
+	for _, tt := range tests {
+		line, column := locate(raw, tt.element)
+		if line != tt.line || column != tt.column {
+			t.Errorf("locate(%q) = %d:%d, want %d:%d", tt.element, line, column, tt.line, tt.column)
+		}
+	}
+}
+
+func TestLocateErrors(t *testing.T) {
+	raw := []byte("<div>\n<span class=\"verse\" id=\"V3\">3</span></div>")
+	errs := []util.ValidationError{
+		{Type: "verses", Message: "gap in verse numbers", Location: util.SourceLocation{Element: "#V3"}},
+		{Type: "label", Message: "missing label"},
+	}
+	locateErrors(raw, errs)
+
+	if got := errs[0].Location.String(); got != "line 2, column 1 (#V3)" {
+		t.Errorf("located error = %q", got)
+	}
+	if got := errs[1].Location.String(); got != "" {
+		t.Errorf("error without an element located at %q", got)
+	}
+}
@@ -0,0 +1,179 @@
+package ingest
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+)
+
+// ManifestFileName is the name of the SHA256 manifest inside the raw directory
+const ManifestFileName = "SHA256MANIFEST"
+
+// GenerateManifest writes the SHA256 manifest of raw HTML and XML sources, hashing files concurrently.
+// When books is non-empty, only raw files for those book abbreviations are rehashed and entries for all
+// other files are carried over from the existing manifest. In incremental mode, files whose size is
+// unchanged and that were not modified since the existing manifest was written reuse their recorded hash.
+// Paths are written relative to the raw directory so the manifest can be verified from anywhere.
+func (proc *Processor) GenerateManifest(books []string, incremental bool) error {
+	manifestPath := filepath.Join(proc.rawDir, ManifestFileName)
+	existing, manifestModTime, err := readManifest(manifestPath, proc.rawDir)
+	if err != nil {
+		return err
+	}
+
+	scope := make(map[string]bool, len(books))
+	for _, abbr := range books {
+		scope[abbr] = true
+	}
+
+	var files []string
+	var toHash []string
+	sizes := make(map[string]int64)
+	err = filepath.WalkDir(proc.rawDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if ext != ".htm" && ext != ".xml" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, path)
+		sizes[path] = info.Size()
+
+		if recorded, ok := existing[relativeRawPath(proc.rawDir, path)]; ok {
+			// Files for books outside the scope keep their recorded hash
+			inScope := len(scope) == 0 || scope[filepath.Base(filepath.Dir(path))]
+			if !inScope {
+				return nil
+			}
+			if incremental && recorded.Size == info.Size() && !info.ModTime().After(manifestModTime) {
+				return nil
+			}
+		}
+		toHash = append(toHash, path)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk raw directory: %w", err)
+	}
+
+	hashes := hashFiles(toHash, runtime.NumCPU())
+
+	sort.Strings(files)
+
+	manifest := util.Manifest{
+		Schema:    util.ManifestSchema,
+		Generated: time.Now().Format(time.RFC3339),
+	}
+	for _, file := range files {
+		rel := relativeRawPath(proc.rawDir, file)
+		hash, hashed := hashes[file]
+		if !hashed {
+			recorded, ok := existing[rel]
+			if !ok {
+				// Unreadable file; hashFiles has already reported it
+				continue
+			}
+			hash = recorded.SHA256
+		}
+		manifest.Entries = append(manifest.Entries, util.ManifestEntry{SHA256: hash, Size: sizes[file], Path: rel})
+	}
+
+	if err := atomicfile.WriteFile(manifestPath, []byte(manifest.Format()), 0600); err != nil {
+		return fmt.Errorf("failed to write manifest file: %w", err)
+	}
+
+	return nil
+}
+
+// hashFiles computes SHA256 hashes for files using a pool of workers
+// Files that cannot be read are reported and left out of the result
+func hashFiles(files []string, workers int) map[string]string {
+	type hashResult struct {
+		path string
+		hash string
+		err  error
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan string)
+	results := make(chan hashResult)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				hash, err := util.FileSHA256(path)
+				results <- hashResult{path: path, hash: hash, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, path := range files {
+			jobs <- path
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	hashes := make(map[string]string, len(files))
+	for result := range results {
+		if result.err != nil {
+			fmt.Printf("Error reading file %s: %v\n", result.path, result.err)
+			continue
+		}
+		hashes[result.path] = result.hash
+	}
+
+	return hashes
+}
+
+// readManifest loads the entries recorded in an existing manifest, keyed by path relative to rawDir,
+// along with the manifest's modification time. A missing manifest is not an error and yields no records.
+func readManifest(path, rawDir string) (map[string]util.ManifestEntry, time.Time, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return make(map[string]util.ManifestEntry), time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to stat manifest file: %w", err)
+	}
+
+	manifest, err := util.ReadManifest(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return manifest.Relative(rawDir), info.ModTime(), nil
+}
+
+// relativeRawPath returns the slash-separated path of a raw file relative to rawDir
+func relativeRawPath(rawDir, path string) string {
+	rel, err := filepath.Rel(rawDir, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
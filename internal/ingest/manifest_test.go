@@ -0,0 +1,61 @@
+package ingest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func TestGenerateManifestScope(t *testing.T) {
+	rawDir := t.TempDir()
+	genFile := filepath.Join(rawDir, "html", "ot", "GEN", "GEN01.htm")
+	exoFile := filepath.Join(rawDir, "html", "ot", "EXO", "EXO01.htm")
+	for _, file := range []string{genFile, exoFile} {
+		if err := os.MkdirAll(filepath.Dir(file), 0750); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(file, []byte("original"), 0600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	proc := &Processor{rawDir: rawDir}
+	if err := proc.GenerateManifest(nil, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	original := util.SHA256Hex([]byte("original"))
+	records, _, err := readManifest(filepath.Join(rawDir, ManifestFileName), rawDir)
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	genKey, exoKey := "html/ot/GEN/GEN01.htm", "html/ot/EXO/EXO01.htm"
+	if len(records) != 2 || records[genKey].SHA256 != original || records[exoKey].SHA256 != original {
+		t.Fatalf("unexpected manifest records: %v", records)
+	}
+
+	// Change both files, but only rehash GEN
+	for _, file := range []string{genFile, exoFile} {
+		if err := os.WriteFile(file, []byte("changed"), 0600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	if err := proc.GenerateManifest([]string{"GEN"}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records, _, err = readManifest(filepath.Join(rawDir, ManifestFileName), rawDir)
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+
+	if records[genKey].SHA256 != util.SHA256Hex([]byte("changed")) || records[genKey].Size != 7 {
+		t.Errorf("expected in-scope file to be rehashed, got %+v", records[genKey])
+	}
+	if records[exoKey].SHA256 != original {
+		t.Errorf("expected out-of-scope file to keep its recorded hash, got %+v", records[exoKey])
+	}
+}
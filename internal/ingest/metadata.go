@@ -1,4 +1,4 @@
-package main
+package ingest
 
 import (
 	"encoding/json"
@@ -6,22 +6,22 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
 )
 
 // MetadataLoader loads and manages metadata from JSON files
 type MetadataLoader struct {
-	BooksData   util.BooksData
-	AliasesData util.AliasesData
-	BooksByAbbr map[string]util.BookMetadata
-	BooksByOSIS map[string]util.BookMetadata
+	BooksData   model.BooksData
+	AliasesData model.AliasesData
+	BooksByAbbr map[string]model.BookMetadata
+	BooksByOSIS map[string]model.BookMetadata
 }
 
 // NewMetadataLoader loads metadata from the canonical index directory
 func NewMetadataLoader(indexDir string) (*MetadataLoader, error) {
 	ml := &MetadataLoader{
-		BooksByAbbr: make(map[string]util.BookMetadata),
-		BooksByOSIS: make(map[string]util.BookMetadata),
+		BooksByAbbr: make(map[string]model.BookMetadata),
+		BooksByOSIS: make(map[string]model.BookMetadata),
 	}
 
 	// Load books.json
@@ -58,19 +58,19 @@ func NewMetadataLoader(indexDir string) (*MetadataLoader, error) {
 }
 
 // GetBookByAbbr returns book metadata by UBS abbreviation
-func (ml *MetadataLoader) GetBookByAbbr(abbr string) (util.BookMetadata, bool) {
+func (ml *MetadataLoader) GetBookByAbbr(abbr string) (model.BookMetadata, bool) {
 	book, exists := ml.BooksByAbbr[abbr]
 	return book, exists
 }
 
 // GetBookByOSIS returns book metadata by OSIS code
-func (ml *MetadataLoader) GetBookByOSIS(osis string) (util.BookMetadata, bool) {
+func (ml *MetadataLoader) GetBookByOSIS(osis string) (model.BookMetadata, bool) {
 	book, exists := ml.BooksByOSIS[osis]
 	return book, exists
 }
 
 // GetChaptersForBook returns the chapter files for a book by OSIS
-func (ml *MetadataLoader) GetChaptersForBook(osis string) (util.AliasChapters, bool) {
+func (ml *MetadataLoader) GetChaptersForBook(osis string) (model.AliasChapters, bool) {
 	chapters, exists := ml.AliasesData[osis]
 	return chapters, exists
 }
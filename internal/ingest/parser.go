@@ -1,14 +1,17 @@
-package main
+package ingest
 
 import (
 	"fmt"
-	"regexp"
+	"io"
+	"sort"
 	"strconv"
 	"strings"
 
 	"golang.org/x/net/html"
 
+	"github.com/julianstephens/kjv-sources/internal/normalize"
 	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
 )
 
 // Parser extracts verse data from HTML chapter files
@@ -19,11 +22,28 @@ func NewParser() *Parser {
 	return &Parser{}
 }
 
-// Parse parses an HTML document and extracts verses
-func (p *Parser) Parse(content []byte, filename string) (*util.ExtractedChapter, error) {
-	doc, err := html.Parse(strings.NewReader(string(content)))
+// Parse parses an HTML document and extracts verses. It fails if the chapter has no readable
+// <div class='chapterlabel'> or no verses.
+func (p *Parser) Parse(r io.Reader, filename string) (*util.ExtractedChapter, error) {
+	result, _, err := p.parse(r, filename, false)
+	return result, err
+}
+
+// ParseLenient parses an HTML document as Parse does, but recovers what it can of a malformed
+// chapter instead of failing. A missing chapter label leaves ChapterNumber 0, a chapter without
+// verses has none, and verse markers without a readable number are skipped; each problem is
+// returned as a warning, of type "label" or "verses", for the caller to decide whether the
+// partial chapter is worth keeping. Only a document that cannot be parsed as HTML is an error.
+func (p *Parser) ParseLenient(r io.Reader, filename string) (*util.ExtractedChapter, []util.ValidationError, error) {
+	return p.parse(r, filename, true)
+}
+
+// parse extracts a chapter, failing at the first problem unless lenient is set, in which case
+// problems are returned as warnings
+func (p *Parser) parse(r io.Reader, filename string, lenient bool) (*util.ExtractedChapter, []util.ValidationError, error) {
+	doc, err := html.Parse(r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
 	result := &util.ExtractedChapter{
@@ -31,30 +51,115 @@ func (p *Parser) Parse(content []byte, filename string) (*util.ExtractedChapter,
 		SourceFile: filename,
 	}
 
+	var warnings []util.ValidationError
+	warn := func(kind, message string) {
+		warnings = append(warnings, util.ValidationError{File: filename, Type: kind, Message: message})
+	}
+
 	// Extract chapter number from <div class='chapterlabel'>
 	chapterNum, err := p.extractChapterNumber(doc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract chapter number: %w", err)
+		if !lenient {
+			return nil, nil, fmt.Errorf("failed to extract chapter number: %w", err)
+		}
+		warn("label", fmt.Sprintf("failed to extract chapter number: %v", err))
 	}
 	result.ChapterNumber = chapterNum
 
 	// Extract verses
-	verses, err := p.extractVerses(doc)
+	verses, skipped, err := p.extractVerses(doc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract verses: %w", err)
+		if !lenient {
+			return nil, nil, fmt.Errorf("failed to extract verses: %w", err)
+		}
+		warn("verses", fmt.Sprintf("failed to extract verses: %v", err))
+	}
+	for _, marker := range skipped {
+		warnings = append(warnings, util.ValidationError{
+			File:     filename,
+			Type:     "verses",
+			Message:  fmt.Sprintf("skipped verse marker without a number: %q", marker.text),
+			Location: elementLocation(marker.id),
+		})
 	}
 	result.Verses = verses
 
 	// Extract footnotes
 	footnotes, err := p.extractFootnotes(doc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract footnotes: %w", err)
+		return nil, nil, fmt.Errorf("failed to extract footnotes: %w", err)
 	}
 	result.Footnotes = footnotes
 
+	return result, warnings, nil
+}
+
+// ParseIntro parses a book introduction (chapter 00) HTML document
+// Intro files have no chapterlabel or verse spans, only titles and paragraphs
+func (p *Parser) ParseIntro(r io.Reader, filename string) (*util.ExtractedIntro, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	result := &util.ExtractedIntro{
+		Paragraphs: make([]string, 0),
+		SourceFile: filename,
+	}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.Data == "div" || n.Data == "p") {
+			// Skip navigation, footnote, and copyright sections entirely
+			if p.hasClass(n, "tnav") || p.hasClass(n, "footnote") || p.hasClass(n, "copyright") {
+				return
+			}
+
+			switch {
+			case p.hasClass(n, "mt") || p.hasClass(n, "imt"):
+				if result.Title == "" {
+					result.Title = normalize.Text(p.getTextContent(n))
+				}
+				return
+			case p.isIntroBlock(n):
+				text := normalize.Text(p.getTextContent(n))
+				if text != "" {
+					result.Paragraphs = append(result.Paragraphs, text)
+				}
+				return
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+
+	walk(doc)
+
+	if len(result.Paragraphs) == 0 {
+		return nil, fmt.Errorf("no introduction paragraphs found")
+	}
+
 	return result, nil
 }
 
+// isIntroBlock reports whether a node is a paragraph-level block in an introduction
+// USFM intro markers (ip, is, im, ipi, ...) all begin with "i"; plain "p" is also accepted
+func (p *Parser) isIntroBlock(node *html.Node) bool {
+	for _, attr := range node.Attr {
+		if attr.Key != "class" {
+			continue
+		}
+		for _, c := range strings.Fields(attr.Val) {
+			if c == "p" || (strings.HasPrefix(c, "i") && c != "imt") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // extractChapterNumber finds and extracts the chapter number from <div class='chapterlabel'>
 func (p *Parser) extractChapterNumber(n *html.Node) (int, error) {
 	var chapter int
@@ -101,10 +206,18 @@ func (p *Parser) extractChapterNumber(n *html.Node) (int, error) {
 	return chapter, nil
 }
 
-// extractVerses finds all <span class="verse"> elements and extracts verse data with tokens
-func (p *Parser) extractVerses(n *html.Node) ([]util.ExtractedVerse, error) {
+// verseMarker is a <span class="verse"> whose verse number could not be read
+type verseMarker struct {
+	text string
+	id   string
+}
+
+// extractVerses finds all <span class="verse"> elements and extracts verse data with tokens. It
+// also returns any verse spans whose number could not be read, which are skipped.
+func (p *Parser) extractVerses(n *html.Node) ([]util.ExtractedVerse, []verseMarker, error) {
 	verses := make([]util.ExtractedVerse, 0)
 	verseMap := make(map[int]*util.ExtractedVerse) // verse number -> ExtractedVerse
+	var skipped []verseMarker
 
 	var walk func(*html.Node)
 	walk = func(n *html.Node) {
@@ -128,11 +241,14 @@ func (p *Parser) extractVerses(n *html.Node) ([]util.ExtractedVerse, error) {
 							tokens := p.extractVerseTokens(n)
 							verseMap[num] = &util.ExtractedVerse{
 								Number: num,
+								ID:     p.getAttr(n, "id"),
 								Plain:  plainText,
 								Tokens: tokens,
 							}
+							return
 						}
 					}
+					skipped = append(skipped, verseMarker{text: verseText, id: p.getAttr(n, "id")})
 				}
 			}
 		}
@@ -144,18 +260,22 @@ func (p *Parser) extractVerses(n *html.Node) ([]util.ExtractedVerse, error) {
 
 	walk(n)
 
-	// Convert map to sorted slice
-	for num := 1; num <= len(verseMap); num++ {
-		if verse, ok := verseMap[num]; ok {
-			verses = append(verses, *verse)
-		}
+	// Convert map to a slice sorted by verse number. Numbering need not start at 1 (AddEsth 10
+	// starts at verse 4), so every verse found is kept.
+	nums := make([]int, 0, len(verseMap))
+	for num := range verseMap {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		verses = append(verses, *verseMap[num])
 	}
 
 	if len(verses) == 0 {
-		return nil, fmt.Errorf("no verses found in chapter")
+		return verses, skipped, fmt.Errorf("no verses found in chapter")
 	}
 
-	return verses, nil
+	return verses, skipped, nil
 }
 
 // getTextContent extracts all text content from a node and its children
@@ -177,74 +297,6 @@ func (p *Parser) getTextContent(n *html.Node) string {
 	return text.String()
 }
 
-// cleanVerseText normalizes whitespace in verse text (with trim)
-func (p *Parser) cleanVerseText(text string) string {
-	// Replace multiple spaces, tabs, newlines with single space
-	re := regexp.MustCompile(`\s+`)
-	text = re.ReplaceAllString(text, " ")
-
-	// Trim leading and trailing space
-	text = strings.TrimSpace(text)
-
-	// Decode HTML entities
-	text = decodeHTMLEntities(text)
-
-	return text
-}
-
-// cleanVerseTextNoTrim normalizes whitespace but preserves leading/trailing spaces
-// This is used for individual tokens so inter-element spacing is preserved
-func (p *Parser) cleanVerseTextNoTrim(text string) string {
-	// Replace multiple spaces, tabs, newlines with single space
-	re := regexp.MustCompile(`\s+`)
-	text = re.ReplaceAllString(text, " ")
-
-	// Decode HTML entities
-	text = decodeHTMLEntities(text)
-
-	// DO NOT trim - preserve leading/trailing spaces for proper concatenation
-
-	return text
-}
-
-// decodeHTMLEntities decodes common HTML entities
-func decodeHTMLEntities(s string) string {
-	replacements := map[string]string{
-		"&#160;": " ",
-		"&nbsp;": " ",
-		"&amp;":  "&",
-		"&lt;":   "<",
-		"&gt;":   ">",
-		"&quot;": "\"",
-		"&apos;": "'",
-	}
-
-	result := s
-	for entity, replacement := range replacements {
-		result = strings.ReplaceAll(result, entity, replacement)
-	}
-
-	// Handle numeric entities like &#160;
-	for i := 0; i < 10; i++ {
-		re := regexp.MustCompile(`&#(\d+);`)
-		matches := re.FindAll([]byte(result), -1)
-		if len(matches) == 0 {
-			break
-		}
-
-		for _, match := range matches {
-			numStr := string(match[2 : len(match)-1])
-			if num, err := strconv.Atoi(numStr); err == nil {
-				if num < 128 { // ASCII range
-					result = strings.ReplaceAll(result, string(match), string(rune(num)))
-				}
-			}
-		}
-	}
-
-	return result
-}
-
 // extractVersePlainText extracts the raw plain text of a verse from the verse span to the next verse span
 // This captures the original text without tokenization for validation purposes
 func (p *Parser) extractVersePlainText(verseSpan *html.Node) string {
@@ -259,7 +311,7 @@ func (p *Parser) extractVersePlainText(verseSpan *html.Node) string {
 			for _, attr := range node.Attr {
 				if attr.Key == "class" && attr.Val == "verse" {
 					// Found next verse, return the accumulated plain text
-					return p.cleanVerseText(plainText.String())
+					return normalize.Text(plainText.String())
 				}
 			}
 		}
@@ -283,12 +335,12 @@ func (p *Parser) extractVersePlainText(verseSpan *html.Node) string {
 	}
 
 	// End of document, return what we accumulated
-	return p.cleanVerseText(plainText.String())
+	return normalize.Text(plainText.String())
 }
 
 // extractVerseTokens extracts tokenized content from a verse span through the next verse
-func (p *Parser) extractVerseTokens(verseSpan *html.Node) []util.Token {
-	var tokens []util.Token
+func (p *Parser) extractVerseTokens(verseSpan *html.Node) []model.Token {
+	var tokens []model.Token
 	var currentText strings.Builder
 
 	// Start from the next sibling after the verse span
@@ -301,9 +353,9 @@ func (p *Parser) extractVerseTokens(verseSpan *html.Node) []util.Token {
 				if attr.Key == "class" && attr.Val == "verse" {
 					// Found next verse, flush any accumulated text
 					if currentText.Len() > 0 {
-						text := p.cleanVerseTextNoTrim(currentText.String())
+						text := normalize.Token(currentText.String())
 						if text != "" {
-							tokens = append(tokens, util.Token{Text: text})
+							tokens = append(tokens, model.Token{Text: text})
 						}
 					}
 					return tokens
@@ -322,33 +374,33 @@ func (p *Parser) extractVerseTokens(verseSpan *html.Node) []util.Token {
 			case p.hasClass(node, "add"):
 				// Flush current text
 				if currentText.Len() > 0 {
-					text := p.cleanVerseTextNoTrim(currentText.String())
+					text := normalize.Token(currentText.String())
 					if text != "" {
-						tokens = append(tokens, util.Token{Text: text})
+						tokens = append(tokens, model.Token{Text: text})
 					}
 					currentText.Reset()
 				}
 				// Add "add" token - store raw text for later cleaning
-				tokens = append(tokens, util.Token{Add: p.getTextContent(node)})
+				tokens = append(tokens, model.Token{Add: p.getTextContent(node)})
 			case p.hasClass(node, "nd"):
 				// Flush current text
 				if currentText.Len() > 0 {
-					text := p.cleanVerseTextNoTrim(currentText.String())
+					text := normalize.Token(currentText.String())
 					if text != "" {
-						tokens = append(tokens, util.Token{Text: text})
+						tokens = append(tokens, model.Token{Text: text})
 					}
 					currentText.Reset()
 				}
 				// Add "nd" (divine name) token - store raw text for later cleaning
-				tokens = append(tokens, util.Token{ND: p.getTextContent(node)})
+				tokens = append(tokens, model.Token{ND: p.getTextContent(node)})
 			case node.Data == "a" && p.hasClass(node, "notemark"):
 				// Skip footnote marks - they're not part of verse text
 			default:
 				// Flush current text before recursing
 				if currentText.Len() > 0 {
-					text := p.cleanVerseTextNoTrim(currentText.String())
+					text := normalize.Token(currentText.String())
 					if text != "" {
-						tokens = append(tokens, util.Token{Text: text})
+						tokens = append(tokens, model.Token{Text: text})
 					}
 					currentText.Reset()
 				}
@@ -363,9 +415,9 @@ func (p *Parser) extractVerseTokens(verseSpan *html.Node) []util.Token {
 
 	// Flush any remaining text
 	if currentText.Len() > 0 {
-		text := p.cleanVerseTextNoTrim(currentText.String())
+		text := normalize.Token(currentText.String())
 		if text != "" {
-			tokens = append(tokens, util.Token{Text: text})
+			tokens = append(tokens, model.Token{Text: text})
 		}
 	}
 
@@ -375,8 +427,8 @@ func (p *Parser) extractVerseTokens(verseSpan *html.Node) []util.Token {
 // extractTokensFromNode extracts tokenized content from a node's children
 // This is different from extractVerseTokens which walks through siblings
 // This function walks through the children of the given node
-func (p *Parser) extractTokensFromNode(node *html.Node) []util.Token {
-	var tokens []util.Token
+func (p *Parser) extractTokensFromNode(node *html.Node) []model.Token {
+	var tokens []model.Token
 	var currentText strings.Builder
 
 	// Walk through this node's children
@@ -392,25 +444,25 @@ func (p *Parser) extractTokensFromNode(node *html.Node) []util.Token {
 			case p.hasClass(child, "add"):
 				// Flush current text
 				if currentText.Len() > 0 {
-					text := p.cleanVerseTextNoTrim(currentText.String())
+					text := normalize.Token(currentText.String())
 					if text != "" {
-						tokens = append(tokens, util.Token{Text: text})
+						tokens = append(tokens, model.Token{Text: text})
 					}
 					currentText.Reset()
 				}
 				// Add "add" token - store raw text for later cleaning
-				tokens = append(tokens, util.Token{Add: p.getTextContent(child)})
+				tokens = append(tokens, model.Token{Add: p.getTextContent(child)})
 			case p.hasClass(child, "nd"):
 				// Flush current text
 				if currentText.Len() > 0 {
-					text := p.cleanVerseTextNoTrim(currentText.String())
+					text := normalize.Token(currentText.String())
 					if text != "" {
-						tokens = append(tokens, util.Token{Text: text})
+						tokens = append(tokens, model.Token{Text: text})
 					}
 					currentText.Reset()
 				}
 				// Add "nd" (divine name) token - store raw text for later cleaning
-				tokens = append(tokens, util.Token{ND: p.getTextContent(child)})
+				tokens = append(tokens, model.Token{ND: p.getTextContent(child)})
 			case child.Data == "a" && p.hasClass(child, "notemark"):
 				// Skip footnote marks - they're not part of verse text
 			default:
@@ -423,15 +475,25 @@ func (p *Parser) extractTokensFromNode(node *html.Node) []util.Token {
 
 	// Flush any remaining text
 	if currentText.Len() > 0 {
-		text := p.cleanVerseTextNoTrim(currentText.String())
+		text := normalize.Token(currentText.String())
 		if text != "" {
-			tokens = append(tokens, util.Token{Text: text})
+			tokens = append(tokens, model.Token{Text: text})
 		}
 	}
 
 	return tokens
 }
 
+// getAttr returns the value of a node's attribute, or "" if it has none
+func (p *Parser) getAttr(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
 // hasClass checks if an HTML node has a given class
 func (p *Parser) hasClass(node *html.Node, className string) bool {
 	for _, attr := range node.Attr {
@@ -507,7 +569,7 @@ func (p *Parser) parseFootnoteParagraph(paraNode *html.Node) *util.ExtractedFoot
 					fn.Mark = p.getTextContent(child)
 				} else if p.hasClass(child, "ft") {
 					// Extract footnote text
-					fn.Text = p.cleanVerseText(p.getTextContent(child))
+					fn.Text = normalize.Text(p.getTextContent(child))
 				}
 			case "a":
 				// Extract verse number from href (e.g., "#V3" -> verse 3)
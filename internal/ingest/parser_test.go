@@ -0,0 +1,102 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+)
+
+const chapterHTML = `<html><body><div class="main">
+<div class='chapterlabel'>3</div>
+<div class='p'><span class="verse" id="V1">1&#160;</span>In the beginning. <span class="verse" id="V2">2&#160;</span>And the earth.</div>
+</div></body></html>`
+
+func TestParse(t *testing.T) {
+	ec, err := NewParser().Parse(strings.NewReader(chapterHTML), "GEN03.htm")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if ec.ChapterNumber != 3 || len(ec.Verses) != 2 {
+		t.Errorf("got chapter %d with %d verses, want chapter 3 with 2", ec.ChapterNumber, len(ec.Verses))
+	}
+
+	noLabel := strings.Replace(chapterHTML, "<div class='chapterlabel'>3</div>", "", 1)
+	if _, err := NewParser().Parse(strings.NewReader(noLabel), "GEN03.htm"); err == nil ||
+		!strings.Contains(err.Error(), "chapterlabel") {
+		t.Errorf("expected a chapterlabel error, got %v", err)
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		chapter  int
+		verses   []int
+		warnings []string // warning types, in order
+		element  string   // element the first warning is located at
+	}{
+		{
+			name:    "well-formed chapter",
+			html:    chapterHTML,
+			chapter: 3,
+			verses:  []int{1, 2},
+		},
+		{
+			name:     "missing chapter label",
+			html:     strings.Replace(chapterHTML, "<div class='chapterlabel'>3</div>", "", 1),
+			verses:   []int{1, 2},
+			warnings: []string{"label"},
+		},
+		{
+			name:     "unreadable verse marker",
+			html:     strings.Replace(chapterHTML, `id="V2">2&#160;`, `id="V2">ii&#160;`, 1),
+			chapter:  3,
+			verses:   []int{1},
+			warnings: []string{"verses"},
+			element:  "#V2",
+		},
+		{
+			name:     "no verses",
+			html:     `<html><body><div class='chapterlabel'>3</div><p>Nothing here.</p></body></html>`,
+			chapter:  3,
+			warnings: []string{"verses"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ec, warnings, err := NewParser().ParseLenient(strings.NewReader(tt.html), "GEN03.htm")
+			if err != nil {
+				t.Fatalf("ParseLenient failed: %v", err)
+			}
+
+			if ec.ChapterNumber != tt.chapter {
+				t.Errorf("chapter = %d, want %d", ec.ChapterNumber, tt.chapter)
+			}
+			var verses []int
+			for _, verse := range ec.Verses {
+				verses = append(verses, verse.Number)
+			}
+			if len(verses) != len(tt.verses) {
+				t.Fatalf("verses = %v, want %v", verses, tt.verses)
+			}
+			for i := range verses {
+				if verses[i] != tt.verses[i] {
+					t.Errorf("verses = %v, want %v", verses, tt.verses)
+				}
+			}
+
+			if len(warnings) != len(tt.warnings) {
+				t.Fatalf("warnings = %v, want types %v", warnings, tt.warnings)
+			}
+			for i, w := range warnings {
+				if w.Type != tt.warnings[i] || w.File != "GEN03.htm" {
+					t.Errorf("warning %d = %+v, want type %q for GEN03.htm", i, w, tt.warnings[i])
+				}
+			}
+			if tt.element != "" && (len(warnings) == 0 || warnings[0].Location.Element != tt.element) {
+				t.Errorf("warnings = %+v, want the first at %s", warnings, tt.element)
+			}
+		})
+	}
+}
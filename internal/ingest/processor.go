@@ -0,0 +1,902 @@
+package ingest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/export"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Processor orchestrates the parsing, validation, and output of chapters
+type Processor struct {
+	metadata  *MetadataLoader
+	parser    *Parser
+	validator *Validator
+	exporter  export.Multi
+	canon     *export.JSONExporter // the json exporter, writing the canonical chapters recorded in the filemap, if selected
+	canonOpts export.JSONOptions   // how canon writes chapter files
+	rawDir    string
+	outputDir string
+	work      string
+	verbose   bool
+	lenient   bool       // whether malformed chapter files are recovered from rather than skipped
+	sanitizer *Sanitizer // repairs known defects of chapter files before parsing, if set
+	hooks     []Hook     // called for each chapter as it is parsed, validated, and written
+	// firstChapter and lastChapter limit processing to a range of chapters; 0 processes them all
+	firstChapter, lastChapter int
+}
+
+// NewProcessor creates a new processor that writes chapters with the exporters registered for formats
+func NewProcessor(indexDir, rawDir, outputDir, work string, formats []string, verbose bool) (*Processor, error) {
+	metadata, err := NewMetadataLoader(indexDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate rawDir exists
+	if _, err := os.Stat(rawDir); err != nil {
+		return nil, fmt.Errorf("raw directory does not exist or is not accessible: %s", rawDir)
+	}
+
+	// Validate rawDir/html structure exists
+	htmlDir := filepath.Join(rawDir, "html")
+	if _, err := os.Stat(htmlDir); err != nil {
+		return nil, fmt.Errorf("raw/html directory does not exist or is not accessible: %s", htmlDir)
+	}
+
+	// Ensure outputDir exists
+	if err := os.MkdirAll(outputDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	var exporters export.Multi
+	var canon *export.JSONExporter
+	for _, format := range formats {
+		exporter, err := export.New(format, outputDir)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, exporter)
+		if json, ok := exporter.(*export.JSONExporter); ok {
+			canon = json
+		}
+	}
+
+	return &Processor{
+		metadata:  metadata,
+		parser:    NewParser(),
+		validator: NewValidator(metadata),
+		exporter:  exporters,
+		canon:     canon,
+		rawDir:    rawDir,
+		outputDir: outputDir,
+		work:      work,
+		verbose:   verbose,
+	}, nil
+}
+
+// ProcessBook processes all chapters for a given book abbreviation
+func (proc *Processor) ProcessBook(abbr string) (*util.ProcessResult, error) {
+	result := &util.ProcessResult{
+		Book:      abbr,
+		FileMap:   model.NewFileMap(),
+		Verses:    model.NewVerseIndex(),
+		StartTime: time.Now(),
+	}
+
+	// Get book metadata
+	bookMeta, exists := proc.metadata.GetBookByAbbr(abbr)
+	if !exists {
+		return result, fmt.Errorf("unknown book abbreviation: %s", abbr)
+	}
+
+	result.OSIS = bookMeta.OSIS
+	if proc.lastChapter > bookMeta.Chapters {
+		return result, fmt.Errorf("chapter %d out of range for book %s, which has %d chapters", proc.lastChapter, abbr, bookMeta.Chapters)
+	}
+
+	if proc.verbose {
+		fmt.Printf("Processing book: %s (%s)\n", abbr, bookMeta.OSIS)
+	}
+
+	// Validate book structure
+	start := time.Now()
+	validationErrs, err := proc.validator.ValidateBook(abbr)
+	result.Timings.Validate += time.Since(start)
+	if err != nil {
+		return result, err
+	}
+	result.Errors = append(result.Errors, validationErrs...)
+	proc.updateVerificationStats(result, validationErrs)
+
+	// Get chapters for this book
+	chapters, exists := proc.metadata.GetChaptersForBook(bookMeta.OSIS)
+	if !exists {
+		return result, fmt.Errorf("no chapters found for book: %s", abbr)
+	}
+
+	// Process each chapter file in chapter order, so exporters see chapters in canonical order
+	for _, chapterKey := range sortedChapterKeys(chapters.Chapters) {
+		if !proc.selected(chapterKey) {
+			continue
+		}
+		filePath := chapters.Chapters[chapterKey]
+		result.FilesProcessed++
+
+		// Chapter "0" is the book introduction, which has its own output format
+		if chapterKey == "0" {
+			proc.processIntro(result, filePath, bookMeta)
+			continue
+		}
+
+		proc.processChapter(result, filePath, bookMeta)
+	}
+
+	// List the chapters written in books/{OSIS}/book.json, so the book can be verified on its own.
+	// In the content-addressed layout index/objects.json lists them instead, and the manifest of a
+	// run in the tree layout is removed once the whole book has been written again.
+	if proc.canon != nil && len(result.FileMap.Files) > 0 {
+		start := time.Now()
+		var err error
+		switch {
+		case proc.canonOpts.Layout != export.LayoutCAS:
+			err = proc.writeBookManifest(bookMeta.OSIS, result.FileMap)
+		case proc.firstChapter == 0:
+			err = os.Remove(filepath.Join(proc.outputDir, "books", bookMeta.OSIS, util.BookManifestName))
+			if errors.Is(err, fs.ErrNotExist) {
+				err = nil
+			}
+		}
+		result.Timings.Write += time.Since(start)
+		if err != nil {
+			result.Errors = append(result.Errors, util.ValidationError{
+				Type:    "parse",
+				Message: fmt.Sprintf("failed to write book manifest: %v", err),
+			})
+		}
+	}
+
+	result.EndTime = time.Now()
+
+	return result, nil
+}
+
+// processChapter parses, validates, and writes one chapter file, recording errors and the filemap entry in result
+func (proc *Processor) processChapter(result *util.ProcessResult, filePath string, bookMeta model.BookMetadata) {
+	// Construct full path to raw HTML file and validate it exists
+	htmlPath, err := proc.constructRawFilePath(filePath)
+	if err != nil {
+		filename := filepath.Base(filePath)
+		if proc.verbose {
+			fmt.Printf("  Error locating file %s: %v\n", filename, err)
+		}
+		locateErr := util.ValidationError{
+			File:    filename,
+			Type:    "coverage",
+			Message: fmt.Sprintf("failed to locate file: %v", err),
+		}
+		result.Errors = append(result.Errors, locateErr)
+		proc.updateVerificationStats(result, []util.ValidationError{locateErr})
+		result.FilesSkipped++
+		return
+	}
+
+	// Parse HTML
+	filename := filepath.Base(filePath)
+	start := time.Now()
+	raw, err := readRaw(htmlPath)
+	result.Timings.Read += time.Since(start)
+	if err != nil {
+		if proc.verbose {
+			fmt.Printf("  Error reading file %s: %v\n", filename, err)
+		}
+		result.Errors = append(result.Errors, util.ValidationError{
+			File:    filename,
+			Type:    "parse",
+			Message: fmt.Sprintf("failed to read file: %v", err),
+		})
+		result.FilesSkipped++
+		return
+	}
+	defer rawBufPool.Put(raw)
+
+	start = time.Now()
+	source := raw.Bytes()
+	if proc.sanitizer != nil {
+		source = proc.sanitize(result, filename, source)
+	}
+	var extractedChapter *util.ExtractedChapter
+	var warnings []util.ValidationError
+	if proc.lenient {
+		extractedChapter, warnings, err = proc.parser.ParseLenient(bytes.NewReader(source), filename)
+	} else {
+		extractedChapter, err = proc.parser.Parse(bytes.NewReader(source), filename)
+	}
+	result.Timings.Parse += time.Since(start)
+	locateErrors(raw.Bytes(), warnings)
+	if err != nil {
+		if proc.verbose {
+			fmt.Printf("  Error parsing file %s: %v\n", filename, err)
+		}
+		result.Errors = append(result.Errors, util.ValidationError{
+			File:    filename,
+			Type:    "parse",
+			Message: fmt.Sprintf("failed to parse HTML: %v", err),
+		})
+		result.FilesSkipped++
+		return
+	}
+	if len(warnings) > 0 && !proc.recoverChapter(result, filename, extractedChapter, warnings) {
+		return
+	}
+	if !proc.runHooks(result, filename, "OnChapterParsed", func(h Hook) error { return h.OnChapterParsed(extractedChapter) }) {
+		result.FilesSkipped++
+		return
+	}
+
+	// Validate chapter
+	start = time.Now()
+	fileErrors := proc.validator.ValidateChapterFile(filename, extractedChapter)
+	locateErrors(raw.Bytes(), fileErrors)
+	suggestRepairs(raw.Bytes(), fileErrors)
+	result.Timings.Validate += time.Since(start)
+	if len(fileErrors) > 0 {
+		if proc.verbose {
+			fmt.Printf("  Validation errors in %s: %d error(s)\n", filename, len(fileErrors))
+			for _, fe := range fileErrors {
+				fmt.Printf("    - [%s] %s%s\n", fe.Type, fe.Message, atLocation(fe.Location))
+				if fe.Suggestion != "" {
+					fmt.Printf("      Suggestion: %s\n", fe.Suggestion)
+				}
+			}
+		}
+		result.Errors = append(result.Errors, fileErrors...)
+		proc.updateVerificationStats(result, fileErrors)
+		result.FilesSkipped++
+		return
+	}
+
+	// Convert to Chapter JSON
+	start = time.Now()
+	chapter := proc.extractedToChapter(extractedChapter, bookMeta)
+	result.Timings.Convert += time.Since(start)
+	if !proc.runHooks(result, filename, "OnChapterValidated", func(h Hook) error { return h.OnChapterValidated(chapter) }) {
+		result.FilesSkipped++
+		return
+	}
+
+	// Write output in every requested format
+	start = time.Now()
+	err = proc.exporter.WriteChapter(chapter)
+	result.Timings.Write += time.Since(start)
+	if err != nil {
+		if proc.verbose {
+			fmt.Printf("  Error writing output for %s: %v\n", filename, err)
+		}
+		result.Errors = append(result.Errors, util.ValidationError{
+			File:    filename,
+			Type:    "parse",
+			Message: fmt.Sprintf("failed to write output: %v", err),
+		})
+		result.FilesSkipped++
+		return
+	}
+	result.ChaptersWritten++
+	result.VersesWritten += len(chapter.Verses)
+	proc.runHooks(result, filename, "OnChapterWritten", func(h Hook) error { return h.OnChapterWritten(chapter) })
+
+	if proc.canon == nil {
+		return
+	}
+
+	numbers := make([]int, len(chapter.Verses))
+	for i, verse := range chapter.Verses {
+		numbers[i] = verse.V
+	}
+	result.Verses.SetVerses(chapter.OSIS, chapter.Chapter, numbers)
+
+	// Record in filemap with checksums of both sides
+	outputPath := proc.canon.ChapterFile(chapter.OSIS, chapter.Chapter)
+	start = time.Now()
+	entry, err := proc.newFileMapEntry(filePath, raw.Bytes(), outputPath)
+	result.Timings.Write += time.Since(start)
+	if err != nil {
+		if proc.verbose {
+			fmt.Printf("  Error recording output for %s: %v\n", filename, err)
+		}
+		result.Errors = append(result.Errors, util.ValidationError{
+			File:    filename,
+			Type:    "parse",
+			Message: fmt.Sprintf("failed to checksum output: %v", err),
+		})
+		return
+	}
+	result.FileMap.Add(entry)
+}
+
+// processIntro parses a book introduction file and writes it to books/{OSIS}/intro.json
+func (proc *Processor) processIntro(result *util.ProcessResult, filePath string, book model.BookMetadata) {
+	filename := filepath.Base(filePath)
+
+	htmlPath, err := proc.constructRawFilePath(filePath)
+	if err != nil {
+		proc.recordSkip(result, filename, "failed to locate file", err)
+		return
+	}
+
+	start := time.Now()
+	raw, err := readRaw(htmlPath)
+	result.Timings.Read += time.Since(start)
+	if err != nil {
+		proc.recordSkip(result, filename, "failed to read file", err)
+		return
+	}
+	defer rawBufPool.Put(raw)
+
+	start = time.Now()
+	extractedIntro, err := proc.parser.ParseIntro(bytes.NewReader(raw.Bytes()), filename)
+	result.Timings.Parse += time.Since(start)
+	if err != nil {
+		proc.recordSkip(result, filename, "failed to parse introduction", err)
+		return
+	}
+
+	start = time.Now()
+	defer func() { result.Timings.Write += time.Since(start) }()
+	outputPath, err := proc.writeIntroJSON(&model.BookIntro{
+		Schema:     model.IntroSchema,
+		Work:       proc.work,
+		OSIS:       book.OSIS,
+		Abbr:       book.Abbr,
+		Title:      extractedIntro.Title,
+		Paragraphs: extractedIntro.Paragraphs,
+	})
+	if err != nil {
+		proc.recordSkip(result, filename, "failed to write output", err)
+		return
+	}
+
+	entry, err := proc.newFileMapEntry(filePath, raw.Bytes(), outputPath)
+	if err != nil {
+		proc.recordSkip(result, filename, "failed to checksum output", err)
+		return
+	}
+	result.FileMap.Add(entry)
+}
+
+// rawBufPool holds the buffers raw HTML files are read into, so bulk runs reuse them across chapters
+var rawBufPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// readRaw reads a raw HTML file into a pooled buffer, which the caller returns to rawBufPool
+func readRaw(path string) (*bytes.Buffer, error) {
+	f, err := os.Open(path) // nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // nolint: errcheck
+
+	buf := rawBufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	if _, err := buf.ReadFrom(f); err != nil {
+		rawBufPool.Put(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+// newFileMapEntry builds a filemap entry with the output path relative to outputDir
+// and SHA256 checksums of the raw source and the written output
+func (proc *Processor) newFileMapEntry(rawPath string, rawContent []byte, outputPath string) (model.FileMapEntry, error) {
+	relOutputPath, err := filepath.Rel(proc.outputDir, outputPath)
+	if err != nil {
+		// Fallback to absolute path if Rel fails
+		relOutputPath = outputPath
+	}
+
+	outputSHA, err := util.FileSHA256(outputPath)
+	if err != nil {
+		return model.FileMapEntry{}, err
+	}
+
+	return model.FileMapEntry{
+		Raw:          rawPath,
+		RawSHA256:    util.SHA256Hex(rawContent),
+		Output:       relOutputPath,
+		OutputSHA256: outputSHA,
+		IngestedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// recordSkip records a parse-type error for a file that could not be processed
+func (proc *Processor) recordSkip(result *util.ProcessResult, filename, message string, err error) {
+	if proc.verbose {
+		fmt.Printf("  Error processing %s: %s: %v\n", filename, message, err)
+	}
+	result.Errors = append(result.Errors, util.ValidationError{
+		File:    filename,
+		Type:    "parse",
+		Message: fmt.Sprintf("%s: %v", message, err),
+	})
+	result.FilesSkipped++
+}
+
+// sanitize applies the sanitizer to a chapter file's raw HTML, logging and counting each fix in
+// result, and returns the HTML to parse. The raw file itself is left as it is, so its checksum in
+// the filemap is unchanged.
+func (proc *Processor) sanitize(result *util.ProcessResult, filename string, raw []byte) []byte {
+	source, fixes := proc.sanitizer.Sanitize(raw)
+	if len(fixes) == 0 {
+		return source
+	}
+	if result.Fixes == nil {
+		result.Fixes = make(map[string]int)
+	}
+	for _, name := range slices.Sorted(maps.Keys(fixes)) {
+		result.Fixes[name] += fixes[name]
+		if proc.verbose {
+			fmt.Printf("  Sanitized %s: %d %s\n", filename, fixes[name], name)
+		}
+	}
+	return source
+}
+
+// recoverChapter records the warnings of a lenient parse and decides whether the partial chapter
+// it returned can still be written. A chapter whose label was lost takes its number from the
+// filename; one without any verses is skipped. Whatever is kept is validated like any other chapter.
+func (proc *Processor) recoverChapter(result *util.ProcessResult, filename string, ec *util.ExtractedChapter, warnings []util.ValidationError) bool {
+	if proc.verbose {
+		fmt.Printf("  Recovered from %d problem(s) in %s\n", len(warnings), filename)
+		for _, w := range warnings {
+			fmt.Printf("    - [%s] %s%s\n", w.Type, w.Message, atLocation(w.Location))
+		}
+	}
+
+	if len(ec.Verses) == 0 {
+		result.Errors = append(result.Errors, warnings...)
+		result.FilesSkipped++
+		return false
+	}
+	result.Warnings = append(result.Warnings, warnings...)
+
+	if ec.ChapterNumber == 0 {
+		if _, chapter, err := proc.validator.parseFilename(filename); err == nil {
+			ec.ChapterNumber = chapter
+		}
+	}
+	return true
+}
+
+// constructRawFilePath constructs and validates the full path to a raw file from a metadata file path
+// Metadata paths are in the format "raw/html/ot/GEN/GEN35.htm"
+// This extracts the part after "raw/" and joins with proc.rawDir, then validates the file exists
+func (proc *Processor) constructRawFilePath(metadataPath string) (string, error) {
+	// Parse the metadata path to extract the relative path after "raw/"
+	parts := strings.Split(metadataPath, string(os.PathSeparator))
+	if len(parts) < 2 || parts[0] != "raw" {
+		return "", fmt.Errorf("invalid metadata path format (expected 'raw/...'): %s", metadataPath)
+	}
+
+	// Reconstruct the relative path (everything after "raw/")
+	relativePath := filepath.Join(parts[1:]...)
+	fullPath := filepath.Join(proc.rawDir, relativePath)
+
+	// Validate file exists
+	if _, err := os.Stat(fullPath); err != nil {
+		return "", fmt.Errorf("file not found at %s: %w", fullPath, err)
+	}
+
+	return fullPath, nil
+}
+
+// extractedToChapter converts ExtractedChapter to Chapter with metadata
+func (proc *Processor) extractedToChapter(ec *util.ExtractedChapter, book model.BookMetadata) *model.Chapter {
+	verses := make([]model.Verse, len(ec.Verses))
+	for i, ev := range ec.Verses {
+		verses[i] = model.Verse{
+			V:      ev.Number,
+			Plain:  ev.Plain,
+			Tokens: ev.Tokens,
+		}
+	}
+
+	// Convert extracted footnotes to final footnotes
+	footnotes := make([]model.Footnote, len(ec.Footnotes))
+	for i, efn := range ec.Footnotes {
+		footnotes[i] = model.Footnote{
+			ID:   efn.ID,
+			Mark: efn.Mark,
+			Text: efn.Text,
+		}
+		footnotes[i].At.V = efn.VerseNum
+	}
+
+	// Only include footnotes if there are any
+	var finalFootnotes []model.Footnote
+	if len(footnotes) > 0 {
+		finalFootnotes = footnotes
+	}
+
+	return &model.Chapter{
+		Schema:    model.ChapterSchema,
+		Work:      proc.work,
+		OSIS:      book.OSIS,
+		Abbr:      book.Abbr,
+		Chapter:   ec.ChapterNumber,
+		Verses:    verses,
+		Footnotes: finalFootnotes,
+	}
+}
+
+// UseStructure checks each processed book's books.json entry against a canon structure
+func (proc *Processor) UseStructure(structure *util.CanonStructure) {
+	proc.validator.UseStructure(structure)
+}
+
+// UseLenient makes the processor recover what it can of malformed chapter files, such as those
+// missing a chapter label, recording the problems as warnings instead of skipping the file
+func (proc *Processor) UseLenient(lenient bool) {
+	proc.lenient = lenient
+}
+
+// UseSanitizer repairs known defects of chapter files with s before they are parsed
+func (proc *Processor) UseSanitizer(s *Sanitizer) {
+	proc.sanitizer = s
+}
+
+// UseJSON sets how the json exporter writes chapter files: compressed, in the content-addressed
+// layout, or both. Book manifests are not written in the content-addressed layout, where
+// index/objects.json lists the chapters instead.
+func (proc *Processor) UseJSON(opts export.JSONOptions) {
+	if proc.canon == nil {
+		return
+	}
+	for i, exporter := range proc.exporter {
+		if exporter == export.Exporter(proc.canon) {
+			proc.canon = export.NewJSON(proc.outputDir, opts)
+			proc.exporter[i] = proc.canon
+			break
+		}
+	}
+	proc.canonOpts = opts
+}
+
+// UseChapters limits processing to chapters first to last of each book, skipping book
+// introductions; 0 for both processes every chapter
+func (proc *Processor) UseChapters(first, last int) {
+	proc.firstChapter, proc.lastChapter = first, last
+}
+
+// selected reports whether the chapter with an aliases.json chapter key is to be processed
+func (proc *Processor) selected(chapterKey string) bool {
+	if proc.firstChapter == 0 {
+		return true
+	}
+	n, err := strconv.Atoi(chapterKey)
+	return err == nil && n >= proc.firstChapter && n <= proc.lastChapter
+}
+
+// ParseChapterRange parses a chapter or an inclusive range of chapters, such as "119" or "1-50"
+func ParseChapterRange(s string) (first, last int, err error) {
+	from, to, isRange := strings.Cut(s, "-")
+	first, err = strconv.Atoi(strings.TrimSpace(from))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid chapter %q: expected a chapter such as 119 or a range such as 1-50", s)
+	}
+	last = first
+	if isRange {
+		last, err = strconv.Atoi(strings.TrimSpace(to))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid chapter range %q: expected a range such as 1-50", s)
+		}
+	}
+	if first < 1 || last < first {
+		return 0, 0, fmt.Errorf("invalid chapter range %q: chapters start at 1 and the range must not run backwards", s)
+	}
+	return first, last, nil
+}
+
+// BeginExport starts all exporters; it must be called before the first ProcessBook
+func (proc *Processor) BeginExport() error {
+	return proc.exporter.Begin(proc.work)
+}
+
+// FinishExport flushes all exporters once every book has been processed
+func (proc *Processor) FinishExport() error {
+	return proc.exporter.Finish()
+}
+
+// sortedChapterKeys returns aliases.json chapter keys in numeric order
+func sortedChapterKeys(chapters map[string]string) []string {
+	keys := make([]string, 0, len(chapters))
+	for k := range chapters {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		na, errA := strconv.Atoi(a)
+		nb, errB := strconv.Atoi(b)
+		if errA != nil || errB != nil {
+			return strings.Compare(a, b)
+		}
+		return na - nb
+	})
+	return keys
+}
+
+// writeIntroJSON writes a book introduction to books/{OSIS}/intro.json
+func (proc *Processor) writeIntroJSON(intro *model.BookIntro) (string, error) {
+	bookDir := filepath.Join(proc.outputDir, "books", intro.OSIS)
+	if err := os.MkdirAll(bookDir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	filepathStr := filepath.Join(bookDir, "intro.json")
+
+	data, err := json.MarshalIndent(intro, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(filepathStr, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return filepathStr, nil
+}
+
+// writeBookManifest writes books/{OSIS}/book.json for the chapters recorded in fileMap. When only
+// some chapters are processed, the existing book.json entries of the others are kept.
+func (proc *Processor) writeBookManifest(osis string, fileMap model.FileMap) error {
+	manifest, err := util.BuildBookManifest(proc.outputDir, proc.work, osis, fileMap)
+	if err != nil {
+		return err
+	}
+	if proc.firstChapter > 0 {
+		path := filepath.Join(proc.outputDir, "books", osis, util.BookManifestName)
+		if data, err := os.ReadFile(path); err == nil { // nolint: gosec
+			var existing model.BookManifest
+			if err := json.Unmarshal(data, &existing); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			for _, chapter := range existing.Chapters {
+				if !proc.selected(strconv.Itoa(chapter.Chapter)) {
+					manifest.Chapters = append(manifest.Chapters, chapter)
+				}
+			}
+			slices.SortFunc(manifest.Chapters, func(a, b model.BookChapter) int { return a.Chapter - b.Chapter })
+		}
+	}
+	return util.WriteBookManifest(proc.outputDir, manifest)
+}
+
+// GetAllBookAbbreviations returns all book abbreviations from books.json
+func (p *Processor) GetAllBookAbbreviations() ([]string, error) {
+	var abbrs []string
+	for _, book := range p.metadata.BooksData.Books {
+		abbrs = append(abbrs, book.Abbr)
+	}
+	return abbrs, nil
+}
+
+// SelectBooks returns the abbreviations of the books a book selector names, such as "nt",
+// "gospels", or "GEN,EXO", in books.json order (see util.SelectBooks)
+func (p *Processor) SelectBooks(selector string) ([]string, error) {
+	books, err := util.SelectBooks(p.metadata.BooksData.Books, selector)
+	if err != nil {
+		return nil, err
+	}
+	abbrs := make([]string, len(books))
+	for i, book := range books {
+		abbrs[i] = book.Abbr
+	}
+	return abbrs, nil
+}
+
+// FileMapConflict is an output path claimed by two raw files: one recorded in filemap.json and
+// one processed in this run
+type FileMapConflict struct {
+	Output   string
+	Existing string // raw path of the recorded entry, which is dropped
+	Incoming string // raw path processed in this run, which replaces it
+}
+
+// WriteFileMap merges fileMap into index/filemap.json keyed by raw path, keeping the entries of
+// books that were not processed in this run, as WriteVerseIndex does for verse counts. An entry
+// whose output is now produced from a different raw file is dropped and reported as a conflict.
+// With rebuild, the existing filemap is ignored and fileMap is written as is.
+func (proc *Processor) WriteFileMap(fileMap model.FileMap, rebuild bool) ([]FileMapConflict, error) {
+	// Create index directory
+	indexDir := filepath.Join(proc.outputDir, "index")
+	if err := os.MkdirAll(indexDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create index directory: %w", err)
+	}
+
+	filemapPath := filepath.Join(indexDir, "filemap.json")
+	merged := model.NewFileMap()
+	var conflicts []FileMapConflict
+	if !rebuild {
+		if data, err := os.ReadFile(filemapPath); err == nil { // nolint: gosec
+			existing, err := model.ParseFileMap(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read existing filemap (use --rebuild-filemap to regenerate it): %w", err)
+			}
+			merged.Merge(existing)
+		}
+		conflicts = dropConflicts(&merged, fileMap)
+	}
+	merged.Merge(fileMap)
+
+	// Marshal to JSON
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal filemap: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(filemapPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write filemap: %w", err)
+	}
+
+	return conflicts, nil
+}
+
+// dropConflicts removes the entries of existing whose output is claimed by a different raw path
+// in incoming, returning them in raw path order
+func dropConflicts(existing *model.FileMap, incoming model.FileMap) []FileMapConflict {
+	claimed := make(map[string]string, len(incoming.Files))
+	for raw, entry := range incoming.Files {
+		claimed[entry.Output] = raw
+	}
+
+	var conflicts []FileMapConflict
+	for _, raw := range existing.RawPaths() {
+		entry := existing.Files[raw]
+		if incomingRaw, ok := claimed[entry.Output]; ok && incomingRaw != raw {
+			conflicts = append(conflicts, FileMapConflict{Output: entry.Output, Existing: raw, Incoming: incomingRaw})
+			delete(existing.Files, raw)
+		}
+	}
+	return conflicts
+}
+
+// WriteVerseIndex merges verse counts into index/verses.json, keeping books that were not
+// processed in this run, and the other chapters of processed books when only some are processed
+func (proc *Processor) WriteVerseIndex(verses model.VerseIndex) error {
+	indexDir := filepath.Join(proc.outputDir, "index")
+	if err := os.MkdirAll(indexDir, 0750); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+
+	versesPath := filepath.Join(indexDir, "verses.json")
+	merged := model.NewVerseIndex()
+	if data, err := os.ReadFile(versesPath); err == nil { // nolint: gosec
+		existing, err := model.ParseVerseIndex(data)
+		if err != nil {
+			return fmt.Errorf("failed to read existing verse index: %w", err)
+		}
+		merged.Merge(existing)
+	}
+	if proc.firstChapter > 0 {
+		merged.MergeChapters(verses)
+	} else {
+		merged.Merge(verses)
+	}
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal verse index: %w", err)
+	}
+	if err := atomicfile.WriteFile(versesPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write verse index: %w", err)
+	}
+
+	return nil
+}
+
+// updateVerificationStats tracks verification issue types
+func (proc *Processor) updateVerificationStats(result *util.ProcessResult, errors []util.ValidationError) {
+	for _, err := range errors {
+		switch err.Type {
+		case "verses":
+			result.VerificationStats.ContinuousVerses++
+		case "footnotes":
+			result.VerificationStats.FootnoteIssues++
+		case "coverage":
+			result.VerificationStats.MissingChapters++
+		}
+	}
+}
+
+// PrintResult prints the processing result in a readable format
+func (proc *Processor) PrintResult(result *util.ProcessResult) {
+	fmt.Printf("\n========================================\n")
+	fmt.Printf("Book: %s (%s)\n", result.Book, result.OSIS)
+	fmt.Printf("Duration: %v\n", result.EndTime.Sub(result.StartTime))
+	fmt.Printf("Stages: %v\n", result.Timings)
+	fmt.Printf("Files Processed: %d\n", result.FilesProcessed)
+	fmt.Printf("Files Skipped: %d\n", result.FilesSkipped)
+
+	// Show verification statistics
+	hasVerificationIssues := result.VerificationStats.ContinuousVerses > 0 ||
+		result.VerificationStats.FootnoteIssues > 0 ||
+		result.VerificationStats.MissingChapters > 0
+	if hasVerificationIssues {
+		fmt.Printf("\nVerification Issues:\n")
+		if result.VerificationStats.ContinuousVerses > 0 {
+			fmt.Printf("  Verse continuity errors: %d\n", result.VerificationStats.ContinuousVerses)
+		}
+		if result.VerificationStats.FootnoteIssues > 0 {
+			fmt.Printf("  Footnote issues: %d\n", result.VerificationStats.FootnoteIssues)
+		}
+		if result.VerificationStats.MissingChapters > 0 {
+			fmt.Printf("  Chapters without raw source: %d\n", result.VerificationStats.MissingChapters)
+		}
+	}
+
+	if len(result.Fixes) > 0 {
+		fmt.Printf("Sanitized: %s\n", FormatFixes(result.Fixes))
+	}
+
+	if len(result.Warnings) > 0 {
+		fmt.Printf("Warnings: %d\n", len(result.Warnings))
+		for i, w := range result.Warnings {
+			fmt.Printf("  %d. [%s] %s\n", i+1, w.Type, w.Message)
+			if w.File != "" {
+				fmt.Printf("     File: %s\n", w.File)
+			}
+			if w.Location.Element != "" {
+				fmt.Printf("     At: %s\n", w.Location)
+			}
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		fmt.Printf("Errors: %d\n", len(result.Errors))
+		for i, err := range result.Errors {
+			fmt.Printf("  %d. [%s] %s\n", i+1, err.Type, err.Message)
+			if err.File != "" {
+				fmt.Printf("     File: %s\n", err.File)
+			}
+			if err.Location.Element != "" {
+				fmt.Printf("     At: %s\n", err.Location)
+			}
+			if err.Expected != nil {
+				fmt.Printf("     Expected: %v\n", err.Expected)
+			}
+			if err.Actual != nil {
+				fmt.Printf("     Actual: %v\n", err.Actual)
+			}
+			if err.Suggestion != "" {
+				fmt.Printf("     Suggestion: %s\n", err.Suggestion)
+			}
+		}
+	} else {
+		fmt.Printf("Status: SUCCESS\n")
+	}
+
+	if len(result.FileMap.Files) > 0 {
+		fmt.Printf("Output Files: %d\n", len(result.FileMap.Files))
+		// Sort and print first few
+		keys := result.FileMap.RawPaths()
+		for i, k := range keys {
+			if i >= 5 {
+				fmt.Printf("  ... and %d more\n", len(keys)-5)
+				break
+			}
+			fmt.Printf("  %s -> %s\n", k, result.FileMap.Files[k].Output)
+		}
+	}
+	fmt.Printf("========================================\n\n")
+}
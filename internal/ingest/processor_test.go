@@ -0,0 +1,782 @@
+package ingest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestNewProcessor(t *testing.T) {
+	tests := []struct {
+		name         string
+		setup        func() (string, string, string, func())
+		shouldFail   bool
+		errorMessage string
+	}{
+		{
+			name: "valid processor creation with proper directory structure",
+			setup: func() (string, string, string, func()) {
+				tempDir := t.TempDir()
+				indexDir := filepath.Join(tempDir, "index")
+				rawDir := filepath.Join(tempDir, "raw")
+				outputDir := filepath.Join(tempDir, "output")
+
+				// Create required directories
+				if err := os.MkdirAll(filepath.Join(rawDir, "html"), 0750); err != nil {
+					t.Fatalf("failed to create raw/html directory: %v", err)
+				}
+				if err := os.MkdirAll(indexDir, 0750); err != nil {
+					t.Fatalf("failed to create index directory: %v", err)
+				}
+
+				// Create minimal books.json
+				booksData := model.BooksData{
+					Schema: 1,
+					Work:   "KJV",
+					Books: []model.BookMetadata{
+						{OSIS: "Gen", Abbr: "GEN", Name: "Genesis", Chapters: 50},
+					},
+				}
+				booksJSON, _ := json.Marshal(booksData)
+				if err := os.WriteFile(filepath.Join(indexDir, "books.json"), booksJSON, 0600); err != nil {
+					t.Fatalf("failed to write books.json: %v", err)
+				}
+
+				// Create minimal aliases.json
+				aliasesData := model.AliasesData{
+					"Gen": model.AliasChapters{
+						SourceAbbr: "GEN",
+						Chapters:   make(map[string]string),
+					},
+				}
+				aliasesJSON, _ := json.Marshal(aliasesData)
+				if err := os.WriteFile(filepath.Join(indexDir, "aliases.json"), aliasesJSON, 0600); err != nil {
+					t.Fatalf("failed to write aliases.json: %v", err)
+				}
+
+				// Create minimal osis.json with ChaptersMetadata
+				osisData := map[string]interface{}{
+					"Gen": map[string]interface{}{
+						"chapters": []string{"raw/html/ot/GEN/GEN01.htm"},
+					},
+				}
+				osisJSON, _ := json.Marshal(osisData)
+				if err := os.WriteFile(filepath.Join(indexDir, "osis.json"), osisJSON, 0600); err != nil {
+					t.Fatalf("failed to write osis.json: %v", err)
+				}
+
+				cleanup := func() {}
+				return indexDir, rawDir, outputDir, cleanup
+			},
+			shouldFail: false,
+		},
+		{
+			name: "fails when raw directory does not exist",
+			setup: func() (string, string, string, func()) {
+				tempDir := t.TempDir()
+				indexDir := filepath.Join(tempDir, "index")
+				rawDir := filepath.Join(tempDir, "nonexistent")
+				outputDir := filepath.Join(tempDir, "output")
+
+				if err := os.MkdirAll(indexDir, 0750); err != nil {
+					t.Fatalf("failed to create index directory: %v", err)
+				}
+
+				// Create minimal books.json
+				booksData := model.BooksData{Schema: 1, Work: "KJV"}
+				booksJSON, _ := json.Marshal(booksData)
+				if err := os.WriteFile(filepath.Join(indexDir, "books.json"), booksJSON, 0600); err != nil {
+					t.Fatalf("failed to write books.json: %v", err)
+				}
+
+				aliasesJSON, _ := json.Marshal(model.AliasesData{})
+				if err := os.WriteFile(filepath.Join(indexDir, "aliases.json"), aliasesJSON, 0600); err != nil {
+					t.Fatalf("failed to write aliases.json: %v", err)
+				}
+
+				cleanup := func() {}
+				return indexDir, rawDir, outputDir, cleanup
+			},
+			shouldFail:   true,
+			errorMessage: "raw directory does not exist",
+		},
+		{
+			name: "fails when raw/html subdirectory does not exist",
+			setup: func() (string, string, string, func()) {
+				tempDir := t.TempDir()
+				indexDir := filepath.Join(tempDir, "index")
+				rawDir := filepath.Join(tempDir, "raw")
+				outputDir := filepath.Join(tempDir, "output")
+
+				if err := os.MkdirAll(indexDir, 0750); err != nil {
+					t.Fatalf("failed to create index directory: %v", err)
+				}
+				if err := os.MkdirAll(rawDir, 0750); err != nil { // Create raw but not raw/html
+					t.Fatalf("failed to create raw directory: %v", err)
+				}
+
+				// Create minimal books.json
+				booksData := model.BooksData{Schema: 1, Work: "KJV"}
+				booksJSON, _ := json.Marshal(booksData)
+				if err := os.WriteFile(filepath.Join(indexDir, "books.json"), booksJSON, 0600); err != nil {
+					t.Fatalf("failed to write books.json: %v", err)
+				}
+
+				aliasesJSON, _ := json.Marshal(model.AliasesData{})
+				if err := os.WriteFile(filepath.Join(indexDir, "aliases.json"), aliasesJSON, 0600); err != nil {
+					t.Fatalf("failed to write aliases.json: %v", err)
+				}
+
+				cleanup := func() {}
+				return indexDir, rawDir, outputDir, cleanup
+			},
+			shouldFail:   true,
+			errorMessage: "raw/html directory does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexDir, rawDir, outputDir, cleanup := tt.setup()
+			defer cleanup()
+
+			proc, err := NewProcessor(indexDir, rawDir, outputDir, "KJV", []string{"json"}, false)
+
+			if tt.shouldFail {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if proc == nil {
+				t.Errorf("expected processor, got nil")
+				return
+			}
+
+			if proc.rawDir != rawDir {
+				t.Errorf("rawDir mismatch: expected %s, got %s", rawDir, proc.rawDir)
+			}
+
+			if proc.outputDir != outputDir {
+				t.Errorf("outputDir mismatch: expected %s, got %s", outputDir, proc.outputDir)
+			}
+		})
+	}
+}
+
+func TestConstructRawFilePath(t *testing.T) {
+	tests := []struct {
+		name         string
+		metadataPath string
+		fileExists   bool
+		shouldFail   bool
+		errorMessage string
+	}{
+		{
+			name:         "valid path with file present",
+			metadataPath: "raw/html/ot/GEN/GEN01.htm",
+			fileExists:   true,
+			shouldFail:   false,
+		},
+		{
+			name:         "valid path but file missing",
+			metadataPath: "raw/html/ot/GEN/GEN99.htm",
+			fileExists:   false,
+			shouldFail:   true,
+			errorMessage: "file not found",
+		},
+		{
+			name:         "invalid path format - missing raw prefix",
+			metadataPath: "html/ot/GEN/GEN01.htm",
+			fileExists:   false,
+			shouldFail:   true,
+			errorMessage: "invalid metadata path format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			rawDir := filepath.Join(tempDir, "raw")
+			if err := os.MkdirAll(filepath.Join(rawDir, "html", "ot", "GEN"), 0750); err != nil {
+				t.Fatalf("failed to create test directory: %v", err)
+			}
+
+			if tt.fileExists {
+				testFile := filepath.Join(rawDir, "html", "ot", "GEN", "GEN01.htm")
+				if err := os.WriteFile(testFile, []byte("<html></html>"), 0600); err != nil {
+					t.Fatalf("failed to write test file: %v", err)
+				}
+			}
+
+			proc := &Processor{rawDir: rawDir}
+			fullPath, err := proc.constructRawFilePath(tt.metadataPath)
+
+			if tt.shouldFail {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if fullPath == "" {
+				t.Errorf("expected non-empty path, got empty string")
+			}
+		})
+	}
+}
+
+func TestExtractedToChapter(t *testing.T) {
+	proc := &Processor{work: "KJV"}
+
+	tests := []struct {
+		name          string
+		extractedData *util.ExtractedChapter
+		bookMeta      model.BookMetadata
+		validate      func(*model.Chapter) error
+	}{
+		{
+			name: "converts extracted chapter to chapter struct",
+			extractedData: &util.ExtractedChapter{
+				ChapterNumber: 1,
+				Verses: []util.ExtractedVerse{
+					{
+						Number: 1,
+						Plain:  "In the beginning",
+						Tokens: []model.Token{
+							{Text: "In"},
+							{Text: "the"},
+							{Text: "beginning"},
+						},
+					},
+				},
+				Footnotes: []util.ExtractedFootnote{},
+			},
+			bookMeta: model.BookMetadata{
+				OSIS: "Gen",
+				Abbr: "GEN",
+				Name: "Genesis",
+			},
+			validate: func(c *model.Chapter) error {
+				if c.Schema != 1 {
+					t.Errorf("expected schema 1, got %d", c.Schema)
+				}
+				if c.Work != "KJV" {
+					t.Errorf("expected work KJV, got %s", c.Work)
+				}
+				if c.OSIS != "Gen" {
+					t.Errorf("expected OSIS Gen, got %s", c.OSIS)
+				}
+				if c.Chapter != 1 {
+					t.Errorf("expected chapter 1, got %d", c.Chapter)
+				}
+				if len(c.Verses) != 1 {
+					t.Errorf("expected 1 verse, got %d", len(c.Verses))
+				}
+				if c.Verses[0].V != 1 {
+					t.Errorf("expected verse 1, got %d", c.Verses[0].V)
+				}
+				return nil
+			},
+		},
+		{
+			name: "handles empty footnotes correctly",
+			extractedData: &util.ExtractedChapter{
+				ChapterNumber: 2,
+				Verses: []util.ExtractedVerse{
+					{Number: 1, Plain: "test", Tokens: []model.Token{{Text: "test"}}},
+				},
+				Footnotes: []util.ExtractedFootnote{},
+			},
+			bookMeta: model.BookMetadata{
+				OSIS: "Gen",
+				Abbr: "GEN",
+			},
+			validate: func(c *model.Chapter) error {
+				if len(c.Footnotes) > 0 {
+					t.Errorf("expected no footnotes, got %d", len(c.Footnotes))
+				}
+				return nil
+			},
+		},
+		{
+			name: "includes footnotes when present",
+			extractedData: &util.ExtractedChapter{
+				ChapterNumber: 3,
+				Verses: []util.ExtractedVerse{
+					{Number: 1, Plain: "test", Tokens: []model.Token{{Text: "test"}}},
+				},
+				Footnotes: []util.ExtractedFootnote{
+					{
+						ID:       "fn1",
+						Mark:     "a",
+						VerseNum: 1,
+						Text:     "A footnote",
+					},
+				},
+			},
+			bookMeta: model.BookMetadata{
+				OSIS: "Gen",
+				Abbr: "GEN",
+			},
+			validate: func(c *model.Chapter) error {
+				if len(c.Footnotes) != 1 {
+					t.Errorf("expected 1 footnote, got %d", len(c.Footnotes))
+				}
+				if c.Footnotes[0].ID != "fn1" {
+					t.Errorf("expected footnote ID fn1, got %s", c.Footnotes[0].ID)
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chapter := proc.extractedToChapter(tt.extractedData, tt.bookMeta)
+			if err := tt.validate(chapter); err != nil {
+				t.Errorf("validation failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestWriteFileMap(t *testing.T) {
+	tempDir := t.TempDir()
+	proc := &Processor{
+		outputDir: tempDir,
+	}
+
+	fileMap := model.NewFileMap()
+	fileMap.Add(model.FileMapEntry{
+		Raw:          "raw/html/ot/GEN/GEN01.htm",
+		RawSHA256:    "aa",
+		Output:       "books/Gen/ch01.json",
+		OutputSHA256: "bb",
+	})
+	fileMap.Add(model.FileMapEntry{Raw: "raw/html/ot/GEN/GEN02.htm", Output: "books/Gen/ch02.json"})
+
+	_, err := proc.WriteFileMap(fileMap, false)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	// Verify filemap was created
+	filemapPath := filepath.Join(tempDir, "index", "filemap.json")
+	if _, err := os.Stat(filemapPath); err != nil {
+		t.Errorf("filemap file not created: %v", err)
+		return
+	}
+
+	// Verify content
+	data, err := os.ReadFile(filemapPath) // nolint: gosec
+	if err != nil {
+		t.Errorf("failed to read filemap: %v", err)
+		return
+	}
+
+	readMap, err := model.ParseFileMap(data)
+	if err != nil {
+		t.Errorf("failed to parse filemap: %v", err)
+		return
+	}
+
+	if readMap.Schema != model.FileMapSchema {
+		t.Errorf("expected schema %d, got %d", model.FileMapSchema, readMap.Schema)
+	}
+
+	if len(readMap.Files) != 2 {
+		t.Errorf("expected 2 entries, got %d", len(readMap.Files))
+	}
+
+	if val, ok := readMap.ByRaw("raw/html/ot/GEN/GEN01.htm"); !ok || val.Output != "books/Gen/ch01.json" {
+		t.Errorf("filemap entry mismatch")
+	}
+
+	if val, ok := readMap.ByOutput("books/Gen/ch01.json"); !ok || val.RawSHA256 != "aa" || val.OutputSHA256 != "bb" {
+		t.Errorf("filemap reverse lookup mismatch")
+	}
+}
+
+func TestWriteFileMapMerge(t *testing.T) {
+	tempDir := t.TempDir()
+	proc := &Processor{outputDir: tempDir}
+
+	full := model.NewFileMap()
+	full.Add(model.FileMapEntry{Raw: "raw/html/ot/GEN/GEN01.htm", Output: "books/Gen/ch01.json", OutputSHA256: "old"})
+	full.Add(model.FileMapEntry{Raw: "raw/html/ot/EXO/EXO01.htm", Output: "books/Exod/ch01.json"})
+	full.Add(model.FileMapEntry{Raw: "raw/html/ot/GEN/GEN.htm", Output: "books/Gen/ch02.json"})
+	if _, err := proc.WriteFileMap(full, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// A single-book run replaces Genesis and keeps Exodus; GEN02.htm now claims ch02.json
+	genesis := model.NewFileMap()
+	genesis.Add(model.FileMapEntry{Raw: "raw/html/ot/GEN/GEN01.htm", Output: "books/Gen/ch01.json", OutputSHA256: "new"})
+	genesis.Add(model.FileMapEntry{Raw: "raw/html/ot/GEN/GEN02.htm", Output: "books/Gen/ch02.json"})
+	conflicts, err := proc.WriteFileMap(genesis, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Output != "books/Gen/ch02.json" ||
+		conflicts[0].Existing != "raw/html/ot/GEN/GEN.htm" || conflicts[0].Incoming != "raw/html/ot/GEN/GEN02.htm" {
+		t.Errorf("unexpected conflicts: %+v", conflicts)
+	}
+
+	read := func() model.FileMap {
+		data, err := os.ReadFile(filepath.Join(tempDir, "index", "filemap.json")) // nolint: gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+		fileMap, err := model.ParseFileMap(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fileMap
+	}
+
+	merged := read()
+	if len(merged.Files) != 3 {
+		t.Errorf("expected Genesis merged with Exodus, got %v", merged.RawPaths())
+	}
+	if entry, _ := merged.ByRaw("raw/html/ot/GEN/GEN01.htm"); entry.OutputSHA256 != "new" {
+		t.Errorf("expected this run's entry to replace the recorded one, got %q", entry.OutputSHA256)
+	}
+	if _, ok := merged.ByRaw("raw/html/ot/EXO/EXO01.htm"); !ok {
+		t.Error("expected the Exodus entry to be kept")
+	}
+	if _, ok := merged.ByRaw("raw/html/ot/GEN/GEN.htm"); ok {
+		t.Error("expected the conflicting entry to be dropped")
+	}
+
+	if conflicts, err := proc.WriteFileMap(genesis, true); err != nil || conflicts != nil {
+		t.Fatalf("unexpected rebuild result: %v, %v", conflicts, err)
+	}
+	if rebuilt := read(); len(rebuilt.Files) != 2 {
+		t.Errorf("expected a rebuild to keep only this run's entries, got %v", rebuilt.RawPaths())
+	}
+}
+
+func TestWriteVerseIndex(t *testing.T) {
+	tempDir := t.TempDir()
+	proc := &Processor{
+		outputDir: tempDir,
+	}
+
+	first := model.NewVerseIndex()
+	first.Set("Gen", 1, 31)
+	first.Set("Ruth", 1, 22)
+	if err := proc.WriteVerseIndex(first); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A later run over one book keeps the books it did not process
+	second := model.NewVerseIndex()
+	second.Set("Gen", 2, 25)
+	if err := proc.WriteVerseIndex(second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "index", "verses.json")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read verse index: %v", err)
+	}
+	verses, err := model.ParseVerseIndex(data)
+	if err != nil {
+		t.Fatalf("failed to parse verse index: %v", err)
+	}
+
+	if last, ok := verses.LastVerse("Ruth", 1); !ok || last != 22 {
+		t.Errorf("expected Ruth 1 to be kept with 22 verses, got %d, %v", last, ok)
+	}
+	if last, ok := verses.LastVerse("Gen", 2); !ok || last != 25 {
+		t.Errorf("expected Gen 2 to have 25 verses, got %d, %v", last, ok)
+	}
+	if _, ok := verses.LastVerse("Gen", 1); ok {
+		t.Error("expected Gen to be replaced by the later run")
+	}
+}
+
+func TestParseLegacyFileMap(t *testing.T) {
+	legacy := []byte(`{"raw/html/ot/GEN/GEN01.htm": "books/Gen/ch01.json"}`)
+
+	fileMap, err := model.ParseFileMap(legacy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entry, ok := fileMap.ByRaw("raw/html/ot/GEN/GEN01.htm")
+	if !ok || entry.Output != "books/Gen/ch01.json" {
+		t.Errorf("legacy entry not upgraded: %+v", entry)
+	}
+
+	if entry.OutputSHA256 != "" {
+		t.Errorf("expected no checksum on legacy entry, got %q", entry.OutputSHA256)
+	}
+}
+
+func TestGetAllBookAbbreviations(t *testing.T) {
+	proc := &Processor{
+		metadata: &MetadataLoader{
+			BooksData: model.BooksData{
+				Books: []model.BookMetadata{
+					{Abbr: "GEN", Name: "Genesis"},
+					{Abbr: "EXO", Name: "Exodus"},
+					{Abbr: "LEV", Name: "Leviticus"},
+				},
+			},
+		},
+	}
+
+	abbrs, err := proc.GetAllBookAbbreviations()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	if len(abbrs) != 3 {
+		t.Errorf("expected 3 abbreviations, got %d", len(abbrs))
+	}
+
+	expectedAbbrs := map[string]bool{"GEN": true, "EXO": true, "LEV": true}
+	for _, abbr := range abbrs {
+		if !expectedAbbrs[abbr] {
+			t.Errorf("unexpected abbreviation: %s", abbr)
+		}
+	}
+}
+
+func TestProcessIntro(t *testing.T) {
+	tempDir := t.TempDir()
+	rawDir := filepath.Join(tempDir, "raw")
+	outputDir := filepath.Join(tempDir, "output")
+	if err := os.MkdirAll(filepath.Join(rawDir, "html", "ot", "GEN"), 0750); err != nil {
+		t.Fatalf("failed to create test directory: %v", err)
+	}
+
+	introHTML := `<html><body><ul class='tnav'><li>Genesis</li></ul><div class="main">
+<div class='imt'>The First Book of Moses</div>
+<div class='ip'>Genesis   tells of the   beginning.</div>
+<div class='ip'>It is the first book of the Law.</div>
+<div class="copyright">Public Domain</div></div></body></html>`
+	introFile := filepath.Join(rawDir, "html", "ot", "GEN", "GEN00.htm")
+	if err := os.WriteFile(introFile, []byte(introHTML), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	proc := &Processor{
+		parser:    NewParser(),
+		rawDir:    rawDir,
+		outputDir: outputDir,
+		work:      "KJV",
+	}
+	result := &util.ProcessResult{FileMap: model.NewFileMap()}
+	proc.processIntro(result, "raw/html/ot/GEN/GEN00.htm", model.BookMetadata{OSIS: "Gen", Abbr: "GEN"})
+
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+
+	entry, ok := result.FileMap.ByRaw("raw/html/ot/GEN/GEN00.htm")
+	if !ok || entry.Output != filepath.Join("books", "Gen", "intro.json") {
+		t.Errorf("filemap entry mismatch: got %q", entry.Output)
+	}
+
+	if entry.RawSHA256 != util.SHA256Hex([]byte(introHTML)) {
+		t.Errorf("raw checksum mismatch: got %q", entry.RawSHA256)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "books", "Gen", "intro.json")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read intro.json: %v", err)
+	}
+
+	var intro model.BookIntro
+	if err := json.Unmarshal(data, &intro); err != nil {
+		t.Fatalf("failed to unmarshal intro.json: %v", err)
+	}
+
+	if intro.Title != "The First Book of Moses" {
+		t.Errorf("unexpected title: %q", intro.Title)
+	}
+
+	if len(intro.Paragraphs) != 2 {
+		t.Fatalf("expected 2 paragraphs, got %d", len(intro.Paragraphs))
+	}
+
+	if intro.Paragraphs[0] != "Genesis tells of the beginning." {
+		t.Errorf("unexpected paragraph text: %q", intro.Paragraphs[0])
+	}
+}
+
+func TestProcessChapterLenient(t *testing.T) {
+	tempDir := t.TempDir()
+	indexDir := filepath.Join(tempDir, "index")
+	rawDir := filepath.Join(tempDir, "raw")
+	outputDir := filepath.Join(tempDir, "output")
+	if err := os.MkdirAll(filepath.Join(rawDir, "html", "ot", "GEN"), 0750); err != nil {
+		t.Fatalf("failed to create raw directory: %v", err)
+	}
+	if err := os.MkdirAll(indexDir, 0750); err != nil {
+		t.Fatalf("failed to create index directory: %v", err)
+	}
+
+	booksJSON, _ := json.Marshal(model.BooksData{
+		Schema: 1,
+		Work:   "KJV",
+		Books:  []model.BookMetadata{{OSIS: "Gen", Abbr: "GEN", Name: "Genesis", Chapters: 50}},
+	})
+	if err := os.WriteFile(filepath.Join(indexDir, "books.json"), booksJSON, 0600); err != nil {
+		t.Fatalf("failed to write books.json: %v", err)
+	}
+	aliasesJSON, _ := json.Marshal(model.AliasesData{})
+	if err := os.WriteFile(filepath.Join(indexDir, "aliases.json"), aliasesJSON, 0600); err != nil {
+		t.Fatalf("failed to write aliases.json: %v", err)
+	}
+
+	// Chapter 3 has lost its label; chapter 4 has no verses to recover
+	noLabel := strings.Replace(chapterHTML, "<div class='chapterlabel'>3</div>", "", 1)
+	noVerses := `<html><body><div class='chapterlabel'>4</div></body></html>`
+	for name, content := range map[string]string{"GEN03.htm": noLabel, "GEN04.htm": noVerses} {
+		if err := os.WriteFile(filepath.Join(rawDir, "html", "ot", "GEN", name), []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	bookMeta := model.BookMetadata{OSIS: "Gen", Abbr: "GEN", Name: "Genesis", Chapters: 50}
+	process := func(lenient bool) *util.ProcessResult {
+		proc, err := NewProcessor(indexDir, rawDir, outputDir, "KJV", []string{"json"}, false)
+		if err != nil {
+			t.Fatalf("failed to create processor: %v", err)
+		}
+		proc.UseLenient(lenient)
+		if err := proc.BeginExport(); err != nil {
+			t.Fatalf("failed to start exporters: %v", err)
+		}
+		result := &util.ProcessResult{FileMap: model.NewFileMap(), Verses: model.NewVerseIndex()}
+		proc.processChapter(result, "raw/html/ot/GEN/GEN03.htm", bookMeta)
+		proc.processChapter(result, "raw/html/ot/GEN/GEN04.htm", bookMeta)
+		if err := proc.FinishExport(); err != nil {
+			t.Fatalf("failed to finish exporters: %v", err)
+		}
+		return result
+	}
+
+	strict := process(false)
+	if strict.FilesSkipped != 2 || len(strict.Warnings) != 0 {
+		t.Errorf("strict: got %d skipped and %d warnings, want 2 and 0", strict.FilesSkipped, len(strict.Warnings))
+	}
+
+	lenient := process(true)
+	if lenient.FilesSkipped != 1 {
+		t.Errorf("lenient: got %d skipped, want 1: %v", lenient.FilesSkipped, lenient.Errors)
+	}
+	if len(lenient.Warnings) != 1 || lenient.Warnings[0].Type != "label" || lenient.Warnings[0].File != "GEN03.htm" {
+		t.Errorf("lenient: unexpected warnings %v", lenient.Warnings)
+	}
+	if len(lenient.Errors) != 1 || lenient.Errors[0].Type != "verses" || lenient.Errors[0].File != "GEN04.htm" {
+		t.Errorf("lenient: unexpected errors %v", lenient.Errors)
+	}
+
+	var chapter model.Chapter
+	data, err := os.ReadFile(filepath.Join(outputDir, "books", "Gen", "ch03.json")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("recovered chapter was not written: %v", err)
+	}
+	if err := json.Unmarshal(data, &chapter); err != nil {
+		t.Fatalf("failed to unmarshal ch03.json: %v", err)
+	}
+	if chapter.Chapter != 3 || len(chapter.Verses) != 2 {
+		t.Errorf("recovered chapter %d with %d verses, want chapter 3 with 2", chapter.Chapter, len(chapter.Verses))
+	}
+}
+
+func TestParseChapterRange(t *testing.T) {
+	tests := []struct {
+		input       string
+		first, last int
+		wantErr     bool
+	}{
+		{"119", 119, 119, false},
+		{"1-50", 1, 50, false},
+		{" 3 - 4 ", 3, 4, false},
+		{"0", 0, 0, true},
+		{"5-3", 0, 0, true},
+		{"1-", 0, 0, true},
+		{"psalm", 0, 0, true},
+	}
+	for _, tt := range tests {
+		first, last, err := ParseChapterRange(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseChapterRange(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if first != tt.first || last != tt.last {
+			t.Errorf("ParseChapterRange(%q) = %d, %d, want %d, %d", tt.input, first, last, tt.first, tt.last)
+		}
+	}
+
+	proc := &Processor{}
+	if !proc.selected("0") {
+		t.Error("expected every chapter to be selected without a range")
+	}
+	proc.UseChapters(2, 3)
+	for key, want := range map[string]bool{"0": false, "1": false, "2": true, "3": true, "4": false} {
+		if got := proc.selected(key); got != want {
+			t.Errorf("selected(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+// genesisFixture writes books.json and aliases.json for Genesis and the given raw chapter files,
+// such as "GEN03.htm", returning the index, raw, and output directories to create a Processor with
+func genesisFixture(t *testing.T, files map[string]string) (indexDir, rawDir, outputDir string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	indexDir = filepath.Join(tempDir, "index")
+	rawDir = filepath.Join(tempDir, "raw")
+	outputDir = filepath.Join(tempDir, "output")
+	if err := os.MkdirAll(filepath.Join(rawDir, "html", "ot", "GEN"), 0750); err != nil {
+		t.Fatalf("failed to create raw directory: %v", err)
+	}
+	if err := os.MkdirAll(indexDir, 0750); err != nil {
+		t.Fatalf("failed to create index directory: %v", err)
+	}
+
+	book := model.BookMetadata{OSIS: "Gen", Abbr: "GEN", Name: "Genesis", Testament: "OT", Chapters: 50}
+	booksJSON, _ := json.Marshal(model.BooksData{Schema: 1, Work: "KJV", Books: []model.BookMetadata{book}})
+	if err := os.WriteFile(filepath.Join(indexDir, "books.json"), booksJSON, 0600); err != nil {
+		t.Fatalf("failed to write books.json: %v", err)
+	}
+	chapters := make(map[string]string, len(files))
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(rawDir, "html", "ot", "GEN", name), []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		n, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "GEN"), ".htm"))
+		chapters[strconv.Itoa(n)] = "raw/html/ot/GEN/" + name
+	}
+	aliasesJSON, _ := json.Marshal(model.AliasesData{"Gen": {SourceAbbr: "GEN", Chapters: chapters}})
+	if err := os.WriteFile(filepath.Join(indexDir, "aliases.json"), aliasesJSON, 0600); err != nil {
+		t.Fatalf("failed to write aliases.json: %v", err)
+	}
+	return indexDir, rawDir, outputDir
+}
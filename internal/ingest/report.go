@@ -0,0 +1,145 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+)
+
+// ReportSchema is the current ingest report schema version
+const ReportSchema = 1
+
+// Report is the machine-readable summary of an ingest run written by --report
+type Report struct {
+	Schema         int            `json:"schema"`
+	Work           string         `json:"work"`
+	FilesProcessed int            `json:"files_processed"`
+	FilesSkipped   int            `json:"files_skipped"`
+	Errors         int            `json:"errors"`
+	Warnings       int            `json:"warnings,omitempty"`
+	Fixes          map[string]int `json:"fixes,omitempty"` // repairs the sanitizer made, by fix name
+	Timings        ReportTiming   `json:"timings"`
+	Books          []BookReport   `json:"books"`
+}
+
+// BookReport is one book's entry in the ingest report
+type BookReport struct {
+	Book           string         `json:"book"`
+	OSIS           string         `json:"osis"`
+	FilesProcessed int            `json:"files_processed"`
+	FilesSkipped   int            `json:"files_skipped"`
+	Errors         []ReportError  `json:"errors,omitempty"`
+	Warnings       []ReportError  `json:"warnings,omitempty"` // problems a lenient parse recovered from
+	Fixes          map[string]int `json:"fixes,omitempty"`    // repairs the sanitizer made, by fix name
+	DurationMS     float64        `json:"duration_ms"`
+	Timings        ReportTiming   `json:"timings"`
+}
+
+// ReportError is a validation or processing error, or a parse warning, in the ingest report
+type ReportError struct {
+	File       string `json:"file,omitempty"`
+	Type       string `json:"type"`
+	Message    string `json:"message"`
+	Element    string `json:"element,omitempty"` // the element nearest the problem, such as "#V12"
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"` // how the raw HTML might be repaired
+}
+
+// ReportTiming is StageTimings in milliseconds
+type ReportTiming struct {
+	ReadMS     float64 `json:"read_ms"`
+	ParseMS    float64 `json:"parse_ms"`
+	ValidateMS float64 `json:"validate_ms"`
+	ConvertMS  float64 `json:"convert_ms"`
+	WriteMS    float64 `json:"write_ms"`
+	TotalMS    float64 `json:"total_ms"`
+}
+
+// NewReport builds a report from the results of processing each book
+func NewReport(work string, results []*util.ProcessResult) *Report {
+	report := &Report{Schema: ReportSchema, Work: work, Books: make([]BookReport, 0, len(results))}
+
+	var total util.StageTimings
+	for _, result := range results {
+		report.FilesProcessed += result.FilesProcessed
+		report.FilesSkipped += result.FilesSkipped
+		report.Errors += len(result.Errors)
+		report.Warnings += len(result.Warnings)
+		for name, n := range result.Fixes {
+			if report.Fixes == nil {
+				report.Fixes = make(map[string]int)
+			}
+			report.Fixes[name] += n
+		}
+		total.Add(result.Timings)
+
+		book := BookReport{
+			Book:           result.Book,
+			OSIS:           result.OSIS,
+			FilesProcessed: result.FilesProcessed,
+			FilesSkipped:   result.FilesSkipped,
+			DurationMS:     milliseconds(result.EndTime.Sub(result.StartTime)),
+			Timings:        newReportTiming(result.Timings),
+			Fixes:          result.Fixes,
+		}
+		for _, err := range result.Errors {
+			book.Errors = append(book.Errors, newReportError(err))
+		}
+		for _, w := range result.Warnings {
+			book.Warnings = append(book.Warnings, newReportError(w))
+		}
+		report.Books = append(report.Books, book)
+	}
+	report.Timings = newReportTiming(total)
+
+	return report
+}
+
+// Write writes the report to path as indented JSON
+func (r *Report) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+	if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+func newReportError(err util.ValidationError) ReportError {
+	return ReportError{
+		File:       err.File,
+		Type:       err.Type,
+		Message:    err.Message,
+		Element:    err.Location.Element,
+		Line:       err.Location.Line,
+		Column:     err.Location.Column,
+		Suggestion: err.Suggestion,
+	}
+}
+
+func newReportTiming(t util.StageTimings) ReportTiming {
+	return ReportTiming{
+		ReadMS:     milliseconds(t.Read),
+		ParseMS:    milliseconds(t.Parse),
+		ValidateMS: milliseconds(t.Validate),
+		ConvertMS:  milliseconds(t.Convert),
+		WriteMS:    milliseconds(t.Write),
+		TotalMS:    milliseconds(t.Total()),
+	}
+}
+
+// milliseconds returns d in milliseconds with microsecond precision
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
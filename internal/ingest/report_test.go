@@ -0,0 +1,60 @@
+package ingest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func TestNewReport(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	results := []*util.ProcessResult{
+		{
+			Book: "GEN", OSIS: "Gen", FilesProcessed: 50,
+			Timings:   util.StageTimings{Read: 2 * time.Millisecond, Parse: 10 * time.Millisecond, Write: 3 * time.Millisecond},
+			StartTime: start, EndTime: start.Add(20 * time.Millisecond),
+		},
+		{
+			Book: "EXO", OSIS: "Exod", FilesProcessed: 40, FilesSkipped: 1,
+			Errors:    []util.ValidationError{{File: "EXO02.htm", Type: "verses", Message: "gap after verse 3"}},
+			Timings:   util.StageTimings{Read: time.Millisecond, Parse: 1500 * time.Microsecond, Validate: time.Millisecond},
+			StartTime: start, EndTime: start.Add(5 * time.Millisecond),
+		},
+	}
+
+	report := NewReport("KJV", results)
+	if report.FilesProcessed != 90 || report.FilesSkipped != 1 || report.Errors != 1 {
+		t.Errorf("unexpected totals: %d processed, %d skipped, %d errors", report.FilesProcessed, report.FilesSkipped, report.Errors)
+	}
+	want := ReportTiming{ReadMS: 3, ParseMS: 11.5, ValidateMS: 1, WriteMS: 3, TotalMS: 18.5}
+	if report.Timings != want {
+		t.Errorf("expected overall timings %+v, got %+v", want, report.Timings)
+	}
+	if len(report.Books) != 2 || report.Books[0].DurationMS != 20 || report.Books[1].Timings.ParseMS != 1.5 {
+		t.Errorf("unexpected book reports: %+v", report.Books)
+	}
+
+	path := filepath.Join(t.TempDir(), "reports", "ingest.json")
+	if err := report.Write(path); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	data, err := os.ReadFile(path) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	var parsed struct {
+		Books []struct {
+			Errors []map[string]string `json:"errors"`
+		} `json:"books"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("failed to parse report: %v", err)
+	}
+	if len(parsed.Books) != 2 || len(parsed.Books[1].Errors) != 1 || parsed.Books[1].Errors[0]["type"] != "verses" {
+		t.Errorf("unexpected report JSON: %s", data)
+	}
+}
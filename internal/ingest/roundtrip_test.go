@@ -0,0 +1,187 @@
+//go:build roundtrip
+
+// Round-trip tests over the whole Bible: raw HTML is ingested again and checked against the
+// committed canon. They process the entire corpus, so they only build with the roundtrip tag:
+//
+//	go test -tags roundtrip -run TestRoundTrip ./internal/ingest
+
+package ingest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/normalize"
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// projectRoot walks up from the working directory to the directory holding go.mod
+func projectRoot(t *testing.T) string {
+	t.Helper()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			return cwd
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+}
+
+// TestRoundTripChapters re-ingests every raw chapter file and requires the chapter JSON written
+// to be byte-identical to the committed canon
+func TestRoundTripChapters(t *testing.T) {
+	root := projectRoot(t)
+	canonDir := filepath.Join(root, "canon", "kjv")
+	outputDir := t.TempDir()
+
+	structure, err := util.LoadCanonStructure(filepath.Join(root, "metadata", "canon-structure.json"))
+	if err != nil {
+		t.Fatalf("failed to load canon structure: %v", err)
+	}
+	processor, err := NewProcessor(filepath.Join(canonDir, "index"), filepath.Join(root, "raw"), outputDir, "KJV", []string{"json"}, false)
+	if err != nil {
+		t.Fatalf("NewProcessor failed: %v", err)
+	}
+	processor.UseStructure(structure)
+
+	abbrs, err := processor.GetAllBookAbbreviations()
+	if err != nil {
+		t.Fatalf("failed to list books: %v", err)
+	}
+	if err := processor.BeginExport(); err != nil {
+		t.Fatalf("BeginExport failed: %v", err)
+	}
+	for _, abbr := range abbrs {
+		result, err := processor.ProcessBook(abbr)
+		if err != nil {
+			t.Fatalf("failed to process %s: %v", abbr, err)
+		}
+		for _, e := range result.Errors {
+			t.Errorf("%s: %s: %s", abbr, e.File, e.Message)
+		}
+	}
+	if err := processor.FinishExport(); err != nil {
+		t.Fatalf("FinishExport failed: %v", err)
+	}
+
+	want := chapterFiles(t, filepath.Join(canonDir, "books"))
+	got := chapterFiles(t, filepath.Join(outputDir, "books"))
+	if len(want) == 0 {
+		t.Fatal("no chapter files in the canon")
+	}
+	for _, rel := range want {
+		if !slices.Contains(got, rel) {
+			t.Errorf("%s: not written by ingest", rel)
+			continue
+		}
+		canon, err := os.ReadFile(filepath.Join(canonDir, "books", rel)) // nolint: gosec
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", rel, err)
+		}
+		written, err := os.ReadFile(filepath.Join(outputDir, "books", rel)) // nolint: gosec
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", rel, err)
+		}
+		if !bytes.Equal(canon, written) {
+			t.Errorf("%s: re-ingested chapter differs from the canon", rel)
+		}
+	}
+	for _, rel := range got {
+		if !slices.Contains(want, rel) {
+			t.Errorf("%s: written by ingest but not in the canon", rel)
+		}
+	}
+}
+
+// chapterFiles returns the chapter files under a books directory, as sorted paths relative to it
+func chapterFiles(t *testing.T, booksDir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(booksDir, "*", "ch*.json"))
+	if err != nil {
+		t.Fatalf("failed to list chapter files: %v", err)
+	}
+	files := make([]string, len(matches))
+	for i, match := range matches {
+		files[i], _ = filepath.Rel(booksDir, match)
+	}
+	slices.Sort(files)
+	return files
+}
+
+// TestRoundTripCanon checks invariants of every chapter in the committed canon: flattened tokens
+// equal the plain text, footnotes resolve to verses of their chapter, and verse numbers match
+// the verse index
+func TestRoundTripCanon(t *testing.T) {
+	corpus, err := kjvcorpus.Open(filepath.Join(projectRoot(t), "canon", "kjv"))
+	if err != nil {
+		t.Fatalf("failed to open canon: %v", err)
+	}
+
+	var chapters int
+	for chapter, err := range corpus.Chapters() {
+		if err != nil {
+			t.Fatalf("failed to load chapter: %v", err)
+		}
+		chapters++
+		ref := chapter.OSIS + " " + strconv.Itoa(chapter.Chapter)
+
+		numbers := make([]int, len(chapter.Verses))
+		verses := make(map[int]bool, len(chapter.Verses))
+		for i, verse := range chapter.Verses {
+			numbers[i] = verse.V
+			verses[verse.V] = true
+			if flat := flattenTokens(verse.Tokens); flat != verse.Plain {
+				t.Errorf("%s:%d: flattened tokens %q differ from plain %q", ref, verse.V, flat, verse.Plain)
+			}
+		}
+
+		indexed, err := corpus.VerseNumbers(chapter.OSIS, chapter.Chapter)
+		if err != nil {
+			t.Errorf("%s: %v", ref, err)
+		} else if !slices.Equal(numbers, indexed) {
+			t.Errorf("%s: verses %v differ from the verse index %v", ref, numbers, indexed)
+		}
+
+		ids := make(map[string]bool, len(chapter.Footnotes))
+		for _, footnote := range chapter.Footnotes {
+			if ids[footnote.ID] {
+				t.Errorf("%s: duplicate footnote %s", ref, footnote.ID)
+			}
+			ids[footnote.ID] = true
+			if !verses[footnote.At.V] {
+				t.Errorf("%s: footnote %s refers to verse %d, which the chapter does not have", ref, footnote.ID, footnote.At.V)
+			}
+			if strings.TrimSpace(footnote.Text) == "" {
+				t.Errorf("%s: footnote %s has empty text", ref, footnote.ID)
+			}
+		}
+	}
+	if chapters == 0 {
+		t.Fatal("no chapters in the canon")
+	}
+}
+
+// flattenTokens joins a verse's tokens and normalizes them as the plain text is
+func flattenTokens(tokens []model.Token) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteString(token.Text)
+		b.WriteString(token.Add)
+		b.WriteString(token.ND)
+	}
+	return normalize.Text(b.String())
+}
@@ -0,0 +1,168 @@
+package ingest
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Sanitizer repairs known defects of upstream HTML before a chapter file is parsed, so the parser
+// itself can stay strict. Which defects it repairs is set by its profile.
+type Sanitizer struct {
+	profile string
+	fixes   []sanitizeFix
+}
+
+// sanitizeFix repairs one kind of defect, returning the repaired HTML and the number of repairs
+type sanitizeFix struct {
+	name  string
+	apply func(raw []byte) ([]byte, int)
+}
+
+// Fixes the sanitizer can apply, in the order they are applied
+const (
+	FixUnclosedVerseSpans    = "unclosed-verse-spans"    // a verse number span left open over the verse text
+	FixDuplicateVerseAnchors = "duplicate-verse-anchors" // a verse number span repeating an earlier one's id
+	FixNbspRuns              = "nbsp-runs"               // runs of non-breaking spaces collapsed to one space
+)
+
+var sanitizeFixes = []sanitizeFix{
+	{FixUnclosedVerseSpans, closeVerseSpans},
+	{FixDuplicateVerseAnchors, dropDuplicateVerseAnchors},
+	{FixNbspRuns, collapseNbspRuns},
+}
+
+// sanitizeProfiles are the fixes of each profile by name
+var sanitizeProfiles = map[string][]string{
+	"none":   nil,
+	"ebible": {FixUnclosedVerseSpans, FixDuplicateVerseAnchors, FixNbspRuns},
+}
+
+// SanitizeProfiles returns the names of the sanitizer profiles, in order
+func SanitizeProfiles() []string {
+	return slices.Sorted(maps.Keys(sanitizeProfiles))
+}
+
+// NewSanitizer creates a sanitizer applying the fixes of a profile: "ebible" for every known
+// defect of the eBible HTML, or "none" to parse files as they are
+func NewSanitizer(profile string) (*Sanitizer, error) {
+	names, ok := sanitizeProfiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("unknown sanitize profile %q (expected one of %s)", profile, strings.Join(SanitizeProfiles(), ", "))
+	}
+	s := &Sanitizer{profile: profile}
+	for _, fix := range sanitizeFixes {
+		if slices.Contains(names, fix.name) {
+			s.fixes = append(s.fixes, fix)
+		}
+	}
+	return s, nil
+}
+
+// Profile returns the name of the sanitizer's profile
+func (s *Sanitizer) Profile() string {
+	return s.profile
+}
+
+// Sanitize returns raw with the profile's fixes applied and the number of repairs each fix made,
+// by fix name. Fixes that repaired nothing are left out of the counts, and raw itself is returned
+// when nothing needed repair.
+func (s *Sanitizer) Sanitize(raw []byte) ([]byte, map[string]int) {
+	counts := make(map[string]int)
+	for _, fix := range s.fixes {
+		fixed, n := fix.apply(raw)
+		if n > 0 {
+			raw = fixed
+			counts[fix.name] += n
+		}
+	}
+	return raw, counts
+}
+
+// FormatFixes formats repair counts by fix name as "3 (nbsp-runs 2, unclosed-verse-spans 1)"
+func FormatFixes(fixes map[string]int) string {
+	total := 0
+	parts := make([]string, 0, len(fixes))
+	for _, name := range slices.Sorted(maps.Keys(fixes)) {
+		total += fixes[name]
+		parts = append(parts, fmt.Sprintf("%s %d", name, fixes[name]))
+	}
+	return fmt.Sprintf("%d (%s)", total, strings.Join(parts, ", "))
+}
+
+var (
+	// verseSpanOpen matches the start tag of a verse number span
+	verseSpanOpen = regexp.MustCompile(`<span\s[^>]*\bclass\s*=\s*["']verse["'][^>]*>`)
+	// verseNumber matches a verse number and the spacing after it at the start of a span's content
+	verseNumber = regexp.MustCompile(`^\s*\d+(?:&#160;|&nbsp;|\x{00A0}|\s)*`)
+	// verseSpan matches a whole verse number span
+	verseSpan = regexp.MustCompile(`<span\s[^>]*\bclass\s*=\s*["']verse["'][^>]*>[^<]*</span>`)
+	// anchorID matches an element's id
+	anchorID = regexp.MustCompile(`\bid\s*=\s*["']?([^"'\s>]+)`)
+	// nbspRun matches two or more non-breaking spaces in a row
+	nbspRun = regexp.MustCompile(`(?:&#160;|&nbsp;|\x{00A0}){2,}`)
+)
+
+// closeVerseSpans closes verse number spans left open, as in `<span class="verse" id="V2">2&#160;And
+// the woman`, after the number, so the verse text is not read as part of the number
+func closeVerseSpans(raw []byte) ([]byte, int) {
+	var out bytes.Buffer
+	n, last := 0, 0
+	for _, match := range verseSpanOpen.FindAllIndex(raw, -1) {
+		rest := raw[match[1]:]
+		number := verseNumber.Find(rest)
+		if number == nil || bytes.HasPrefix(rest[len(number):], []byte("</span")) {
+			continue
+		}
+		at := match[1] + len(number)
+		out.Write(raw[last:at])
+		out.WriteString("</span>")
+		last = at
+		n++
+	}
+	if n == 0 {
+		return raw, 0
+	}
+	out.Write(raw[last:])
+	return out.Bytes(), n
+}
+
+// dropDuplicateVerseAnchors removes verse number spans whose id an earlier span already has,
+// leaving the text after them in the earlier verse
+func dropDuplicateVerseAnchors(raw []byte) ([]byte, int) {
+	seen := make(map[string]bool)
+	n := 0
+	fixed := verseSpan.ReplaceAllFunc(raw, func(span []byte) []byte {
+		id := anchorID.FindSubmatch(span)
+		if id == nil {
+			return span
+		}
+		if seen[string(id[1])] {
+			n++
+			return nil
+		}
+		seen[string(id[1])] = true
+		return span
+	})
+	if n == 0 {
+		return raw, 0
+	}
+	return fixed, n
+}
+
+// collapseNbspRuns replaces runs of non-breaking spaces, which upstream uses for spacing, with a
+// single space
+func collapseNbspRuns(raw []byte) ([]byte, int) {
+	n := 0
+	fixed := nbspRun.ReplaceAllFunc(raw, func([]byte) []byte {
+		n++
+		return []byte(" ")
+	})
+	if n == 0 {
+		return raw, 0
+	}
+	return fixed, n
+}
@@ -0,0 +1,101 @@
+package ingest
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   string
+		want  string
+		fixes map[string]int
+	}{
+		{
+			name:  "clean file",
+			raw:   `<span class="verse" id="V1">1&#160;</span>In the beginning.`,
+			want:  `<span class="verse" id="V1">1&#160;</span>In the beginning.`,
+			fixes: map[string]int{},
+		},
+		{
+			name:  "unclosed verse span",
+			raw:   `<span class="verse" id="V1">1&#160;In the beginning. <span class='verse' id="V2">2&#160;</span>And`,
+			want:  `<span class="verse" id="V1">1&#160;</span>In the beginning. <span class='verse' id="V2">2&#160;</span>And`,
+			fixes: map[string]int{FixUnclosedVerseSpans: 1},
+		},
+		{
+			name:  "duplicate verse anchor",
+			raw:   `<span class="verse" id="V1">1&#160;</span>In the <span class="verse" id="V1">1&#160;</span>beginning.`,
+			want:  `<span class="verse" id="V1">1&#160;</span>In the beginning.`,
+			fixes: map[string]int{FixDuplicateVerseAnchors: 1},
+		},
+		{
+			name:  "non-breaking space runs",
+			raw:   `<span class="verse" id="V1">1&#160;</span>In&#160;&#160;the&nbsp;&#160;&#160;beginning.`,
+			want:  `<span class="verse" id="V1">1&#160;</span>In the beginning.`,
+			fixes: map[string]int{FixNbspRuns: 2},
+		},
+	}
+
+	sanitizer, err := NewSanitizer("ebible")
+	if err != nil {
+		t.Fatalf("NewSanitizer failed: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fixes := sanitizer.Sanitize([]byte(tt.raw))
+			if string(got) != tt.want {
+				t.Errorf("Sanitize = %s\nwant       %s", got, tt.want)
+			}
+			if len(fixes) != len(tt.fixes) {
+				t.Fatalf("fixes = %v, want %v", fixes, tt.fixes)
+			}
+			for name, n := range tt.fixes {
+				if fixes[name] != n {
+					t.Errorf("fixes = %v, want %v", fixes, tt.fixes)
+				}
+			}
+		})
+	}
+}
+
+func TestSanitizeBeforeParse(t *testing.T) {
+	raw := []byte(`<html><body><div class='chapterlabel'>1</div><div class='p'>` +
+		`<span class="verse" id="V1">1&#160;In the beginning. <span class="verse" id="V2">2&#160;And the earth.</div></body></html>`)
+
+	if ec, err := NewParser().Parse(bytes.NewReader(raw), "GEN01.htm"); err == nil && len(ec.Verses) == 2 {
+		t.Fatalf("expected unclosed verse spans to defeat the parser, got %d verses", len(ec.Verses))
+	}
+
+	sanitizer, err := NewSanitizer("ebible")
+	if err != nil {
+		t.Fatalf("NewSanitizer failed: %v", err)
+	}
+	source, fixes := sanitizer.Sanitize(raw)
+	if fixes[FixUnclosedVerseSpans] != 2 {
+		t.Errorf("fixes = %v, want 2 %s", fixes, FixUnclosedVerseSpans)
+	}
+	ec, err := NewParser().Parse(bytes.NewReader(source), "GEN01.htm")
+	if err != nil {
+		t.Fatalf("Parse failed after sanitizing: %v", err)
+	}
+	if len(ec.Verses) != 2 || ec.Verses[1].Plain != "And the earth." {
+		t.Errorf("verses = %+v", ec.Verses)
+	}
+}
+
+func TestNewSanitizer(t *testing.T) {
+	none, err := NewSanitizer("none")
+	if err != nil {
+		t.Fatalf("NewSanitizer(none) failed: %v", err)
+	}
+	raw := []byte(`<span class="verse" id="V1">1&#160;In&#160;&#160;the beginning.`)
+	if got, fixes := none.Sanitize(raw); !bytes.Equal(got, raw) || len(fixes) != 0 {
+		t.Errorf("profile none changed the file: %s, %v", got, fixes)
+	}
+
+	if _, err := NewSanitizer("tidy"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
@@ -0,0 +1,175 @@
+package ingest
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+// rawVerseSpan is a <span class="verse"> as written in raw HTML
+type rawVerseSpan struct {
+	id   string // such as "V12", or "" if the span has none
+	text string // the span's text with entities decoded and spacing trimmed, such as "12"
+}
+
+// rawVerseSpans returns the verse number spans of raw HTML in document order. Spans left open
+// are read up to the next tag.
+func rawVerseSpans(raw []byte) []rawVerseSpan {
+	var spans []rawVerseSpan
+	for _, match := range verseSpanOpen.FindAllIndex(raw, -1) {
+		span := rawVerseSpan{}
+		if id := anchorID.FindSubmatch(raw[match[0]:match[1]]); id != nil {
+			span.id = string(id[1])
+		}
+		text := raw[match[1]:]
+		if end := bytes.IndexByte(text, '<'); end >= 0 {
+			text = text[:end]
+		}
+		span.text = strings.TrimSpace(html.UnescapeString(string(text)))
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+// suggestRepairs adds a suggestion to each verse numbering error of a chapter file saying what in
+// the raw HTML most likely caused it and how to repair it
+func suggestRepairs(raw []byte, errs []util.ValidationError) {
+	var spans []rawVerseSpan
+	for i := range errs {
+		err := &errs[i]
+		if err.Type != "verses" {
+			continue
+		}
+		if spans == nil {
+			spans = rawVerseSpans(raw)
+		}
+
+		switch expected := err.Expected.(type) {
+		case int:
+			actual, _ := err.Actual.(int)
+			if strings.HasPrefix(err.Message, "verses do not start") {
+				err.Suggestion = suggestFirstVerse(raw, spans, actual)
+			} else {
+				err.Suggestion = suggestMissing(raw, spans, missingRange(expected, actual))
+			}
+		case []int:
+			actual, _ := err.Actual.([]int)
+			var missing []int
+			for _, n := range expected {
+				if !slices.Contains(actual, n) {
+					missing = append(missing, n)
+				}
+			}
+			if len(missing) > 0 {
+				err.Suggestion = suggestMissing(raw, spans, missing)
+			}
+		}
+	}
+}
+
+// missingRange returns the verse numbers from first up to, not including, next
+func missingRange(first, next int) []int {
+	var missing []int
+	for n := first; n < next; n++ {
+		missing = append(missing, n)
+	}
+	return missing
+}
+
+// suggestFirstVerse suggests a repair for a chapter whose first verse is not 1
+func suggestFirstVerse(raw []byte, spans []rawVerseSpan, first int) string {
+	if suggestion := findCause(raw, spans, missingRange(1, first)); suggestion != "" {
+		return suggestion
+	}
+	return fmt.Sprintf("if the source really numbers this chapter from verse %d, list its verses under verse_maps in the canon structure", first)
+}
+
+// suggestMissing suggests a repair for verse numbers missing from a chapter
+func suggestMissing(raw []byte, spans []rawVerseSpan, missing []int) string {
+	if len(missing) == 0 {
+		return ""
+	}
+	if suggestion := findCause(raw, spans, missing); suggestion != "" {
+		return suggestion
+	}
+	return fmt.Sprintf("no verse span for %s; add <span class=\"verse\" id=\"V%d\">%d&#160;</span> where the verse begins, "+
+		"or list the chapter's verses under verse_maps in the canon structure if the source omits it", verseList(missing), missing[0], missing[0])
+}
+
+// findCause looks in raw HTML for the likely cause of verse numbers missing from a chapter, and
+// suggests its repair: a verse span whose number cannot be read, one numbered wrongly, or a defect
+// the sanitizer repairs. It returns "" when there is no verse span to blame.
+func findCause(raw []byte, spans []rawVerseSpan, missing []int) string {
+	if len(missing) == 0 {
+		return ""
+	}
+
+	var suggestions []string
+	for _, n := range missing {
+		id := fmt.Sprintf("V%d", n)
+		for _, span := range spans {
+			if span.id != id {
+				continue
+			}
+			if num, err := strconv.Atoi(firstField(span.text)); err != nil {
+				suggestions = append(suggestions, fmt.Sprintf("verse span #%s reads %q, not a verse number; change it to %d", id, span.text, n))
+			} else if num != n {
+				suggestions = append(suggestions, fmt.Sprintf("verse span #%s is numbered %d; renumber it to %d", id, num, n))
+			}
+			break
+		}
+	}
+	if len(suggestions) == 0 {
+		for _, span := range spans {
+			if _, err := strconv.Atoi(firstField(span.text)); err != nil {
+				suggestions = append(suggestions, fmt.Sprintf("verse span %s reads %q, not a verse number; give it the missing number", spanName(span), span.text))
+			}
+		}
+	}
+	if len(suggestions) > 0 {
+		return strings.Join(suggestions, "; ")
+	}
+
+	sanitizer, _ := NewSanitizer("ebible")
+	if _, fixes := sanitizer.Sanitize(raw); len(fixes) > 0 {
+		return fmt.Sprintf("the file has defects the sanitizer repairs, %s; rerun with --sanitize=ebible", FormatFixes(fixes))
+	}
+	return ""
+}
+
+// firstField returns the first whitespace-separated field of s, or ""
+func firstField(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// spanName names a verse span by its id, or by its text when it has none
+func spanName(span rawVerseSpan) string {
+	if span.id != "" {
+		return "#" + span.id
+	}
+	return fmt.Sprintf("%q", span.text)
+}
+
+// verseList formats ascending verse numbers as "verse 5", "verses 5-7", or "verses 5, 9"
+func verseList(verses []int) string {
+	if len(verses) == 1 {
+		return fmt.Sprintf("verse %d", verses[0])
+	}
+	if verses[len(verses)-1]-verses[0] == len(verses)-1 {
+		return fmt.Sprintf("verses %d-%d", verses[0], verses[len(verses)-1])
+	}
+	numbers := make([]string, len(verses))
+	for i, n := range verses {
+		numbers[i] = strconv.Itoa(n)
+	}
+	return "verses " + strings.Join(numbers, ", ")
+}
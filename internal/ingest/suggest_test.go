@@ -0,0 +1,102 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func TestSuggestRepairs(t *testing.T) {
+	verse := func(id, number string) string {
+		return `<span class="verse" id="` + id + `">` + number + `&#160;</span>Text. `
+	}
+	gap := util.ValidationError{Type: "verses", Message: "gap in verse numbers: expected 2, got 3", Expected: 2, Actual: 3}
+
+	tests := []struct {
+		name string
+		raw  string
+		err  util.ValidationError
+		want string // substring of the suggestion, or "" for none
+	}{
+		{
+			name: "unreadable verse number",
+			raw:  verse("V1", "1") + verse("V2", "ii") + verse("V3", "3"),
+			err:  gap,
+			want: `verse span #V2 reads "ii", not a verse number; change it to 2`,
+		},
+		{
+			name: "misnumbered verse span",
+			raw:  verse("V1", "1") + verse("V2", "1") + verse("V3", "3"),
+			err:  gap,
+			want: "verse span #V2 is numbered 1; renumber it to 2",
+		},
+		{
+			name: "unreadable span without the expected id",
+			raw:  verse("V1", "1") + verse("X", "2a") + verse("V3", "3"),
+			err:  gap,
+			want: `verse span #X reads "2a", not a verse number; give it the missing number`,
+		},
+		{
+			name: "defect the sanitizer repairs",
+			raw:  verse("V1", "1") + `<span class="verse" id="V2">2&#160;Text. ` + verse("V3", "3"),
+			err:  gap,
+			want: "rerun with --sanitize=ebible",
+		},
+		{
+			name: "no verse span",
+			raw:  verse("V1", "1") + verse("V3", "3"),
+			err:  gap,
+			want: `no verse span for verse 2; add <span class="verse" id="V2">2&#160;</span>`,
+		},
+		{
+			name: "chapter numbered from a later verse",
+			raw:  verse("V4", "4") + verse("V5", "5"),
+			err:  util.ValidationError{Type: "verses", Message: "verses do not start at 1", Expected: 1, Actual: 4},
+			want: "numbers this chapter from verse 4, list its verses under verse_maps",
+		},
+		{
+			name: "verse map mismatch",
+			raw:  verse("V1", "1") + verse("V3", "3"),
+			err: util.ValidationError{
+				Type:     "verses",
+				Message:  "verse numbers differ from the chapter's verse map in the canon structure",
+				Expected: []int{1, 2, 3},
+				Actual:   []int{1, 3},
+			},
+			want: "no verse span for verse 2",
+		},
+		{
+			name: "footnote errors get no suggestion",
+			raw:  verse("V1", "1"),
+			err:  util.ValidationError{Type: "footnotes", Message: "footnote FN1 has empty text"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := []util.ValidationError{tt.err}
+			suggestRepairs([]byte(tt.raw), errs)
+			got := errs[0].Suggestion
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("suggestion = %q, want one containing %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerseList(t *testing.T) {
+	tests := []struct {
+		verses []int
+		want   string
+	}{
+		{[]int{5}, "verse 5"},
+		{[]int{5, 6, 7}, "verses 5-7"},
+		{[]int{5, 9}, "verses 5, 9"},
+	}
+	for _, tt := range tests {
+		if got := verseList(tt.verses); got != tt.want {
+			t.Errorf("verseList(%v) = %q, want %q", tt.verses, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package ingest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+// summaryRow is one book's row of the summary, or the totals row
+type summaryRow struct {
+	book, osis string
+	chapters   int
+	verses     int
+	errors     int
+	duration   time.Duration
+}
+
+// summaryRows returns a row per book result followed by the totals row
+func summaryRows(results []*util.ProcessResult) []summaryRow {
+	rows := make([]summaryRow, 0, len(results)+1)
+	total := summaryRow{book: "Total"}
+	for _, result := range results {
+		row := summaryRow{
+			book:     result.Book,
+			osis:     result.OSIS,
+			chapters: result.ChaptersWritten,
+			verses:   result.VersesWritten,
+			errors:   len(result.Errors),
+			duration: result.EndTime.Sub(result.StartTime),
+		}
+		rows = append(rows, row)
+		total.chapters += row.chapters
+		total.verses += row.verses
+		total.errors += row.errors
+		total.duration += row.duration
+	}
+	return append(rows, total)
+}
+
+// WriteSummary writes a row per book result, with its chapters and verses written, errors, and
+// duration, followed by a totals row. The table format aligns the columns for reading; csv and tsv
+// write durations in milliseconds for spreadsheets.
+func WriteSummary(w io.Writer, format string, results []*util.ProcessResult) error {
+	rows := summaryRows(results)
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "Book\tOSIS\tChapters\tVerses\tErrors\tDuration")
+		for _, row := range rows {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%v\n",
+				row.book, row.osis, row.chapters, row.verses, row.errors, row.duration.Round(100*time.Microsecond))
+		}
+		return tw.Flush()
+	case "csv", "tsv":
+		cw := csv.NewWriter(w)
+		if format == "tsv" {
+			cw.Comma = '\t'
+		}
+		_ = cw.Write([]string{"book", "osis", "chapters", "verses", "errors", "duration_ms"})
+		for _, row := range rows {
+			_ = cw.Write([]string{
+				row.book,
+				row.osis,
+				strconv.Itoa(row.chapters),
+				strconv.Itoa(row.verses),
+				strconv.Itoa(row.errors),
+				strconv.FormatFloat(milliseconds(row.duration), 'f', -1, 64),
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unknown summary format %q", format)
+	}
+}
@@ -0,0 +1,54 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func TestWriteSummary(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	results := []*util.ProcessResult{
+		{Book: "GEN", OSIS: "Gen", ChaptersWritten: 50, VersesWritten: 1533, StartTime: start, EndTime: start.Add(40 * time.Millisecond)},
+		{
+			Book: "OBA", OSIS: "Obad", ChaptersWritten: 1, VersesWritten: 21, StartTime: start, EndTime: start.Add(2500 * time.Microsecond),
+			Errors: []util.ValidationError{{Type: "verses"}},
+		},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"table", "" +
+			"Book   OSIS  Chapters  Verses  Errors  Duration\n" +
+			"GEN    Gen   50        1533    0       40ms\n" +
+			"OBA    Obad  1         21      1       2.5ms\n" +
+			"Total        51        1554    1       42.5ms\n"},
+		{"csv", "" +
+			"book,osis,chapters,verses,errors,duration_ms\n" +
+			"GEN,Gen,50,1533,0,40\n" +
+			"OBA,Obad,1,21,1,2.5\n" +
+			"Total,,51,1554,1,42.5\n"},
+		{"tsv", "" +
+			"book\tosis\tchapters\tverses\terrors\tduration_ms\n" +
+			"GEN\tGen\t50\t1533\t0\t40\n" +
+			"OBA\tObad\t1\t21\t1\t2.5\n" +
+			"Total\t\t51\t1554\t1\t42.5\n"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := WriteSummary(&b, tt.format, results); err != nil {
+			t.Fatalf("WriteSummary(%s) failed: %v", tt.format, err)
+		}
+		if b.String() != tt.want {
+			t.Errorf("WriteSummary(%s) =\n%s\nwant\n%s", tt.format, b.String(), tt.want)
+		}
+	}
+
+	if err := WriteSummary(&strings.Builder{}, "xml", results); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -1,16 +1,20 @@
-package main
+package ingest
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
 )
 
 // Validator validates the 3-point check for HTML chapter files
 type Validator struct {
-	metadata *MetadataLoader
+	metadata  *MetadataLoader
+	structure *util.CanonStructure // optional canon structure books.json is checked against
 }
 
 // NewValidator creates a new validator
@@ -18,6 +22,11 @@ func NewValidator(metadata *MetadataLoader) *Validator {
 	return &Validator{metadata: metadata}
 }
 
+// UseStructure checks each book's books.json entry against a canon structure
+func (v *Validator) UseStructure(structure *util.CanonStructure) {
+	v.structure = structure
+}
+
 // ValidateBook validates all chapters for a book
 func (v *Validator) ValidateBook(abbr string) ([]util.ValidationError, error) {
 	var errors []util.ValidationError
@@ -57,9 +66,103 @@ func (v *Validator) ValidateBook(abbr string) ([]util.ValidationError, error) {
 		}
 	}
 
+	errors = append(errors, v.validateCoverage(book, chapters)...)
+	errors = append(errors, v.validateTestament(book)...)
+	errors = append(errors, v.validateStructure(book)...)
+
 	return errors, nil
 }
 
+// validateTestament checks that a book's testament in books.json is known and, for classified
+// books, matches the testament package
+func (v *Validator) validateTestament(book model.BookMetadata) []util.ValidationError {
+	t, err := testament.Parse(book.Testament)
+	if err != nil {
+		return []util.ValidationError{{
+			Type:    "testament",
+			Message: fmt.Sprintf("%s: %v", book.Abbr, err),
+			Actual:  book.Testament,
+		}}
+	}
+	if known, ok := testament.Of(book.OSIS); ok && known != t {
+		return []util.ValidationError{{
+			Type:     "testament",
+			Message:  fmt.Sprintf("%s (%s) is classified under the wrong testament", book.Abbr, book.OSIS),
+			Expected: string(known),
+			Actual:   book.Testament,
+		}}
+	}
+	return nil
+}
+
+// validateStructure checks that books.json agrees with the canon structure on a book's OSIS code,
+// testament, and chapter count
+func (v *Validator) validateStructure(book model.BookMetadata) []util.ValidationError {
+	if v.structure == nil {
+		return nil
+	}
+
+	entry, _, exists := v.structure.Book(book.Abbr)
+	if !exists {
+		return []util.ValidationError{{
+			Type:    "structure",
+			Message: fmt.Sprintf("book %s is not in the canon structure", book.Abbr),
+			Actual:  book.Abbr,
+		}}
+	}
+
+	var errors []util.ValidationError
+	if entry.OSIS != book.OSIS {
+		errors = append(errors, util.ValidationError{
+			Type:     "structure",
+			Message:  fmt.Sprintf("OSIS code for %s differs from the canon structure", book.Abbr),
+			Expected: entry.OSIS,
+			Actual:   book.OSIS,
+		})
+	}
+	if entry.Testament != book.Testament {
+		errors = append(errors, util.ValidationError{
+			Type:     "structure",
+			Message:  fmt.Sprintf("testament for %s differs from the canon structure", book.Abbr),
+			Expected: entry.Testament,
+			Actual:   book.Testament,
+		})
+	}
+	if entry.Chapters != book.Chapters {
+		errors = append(errors, util.ValidationError{
+			Type:     "structure",
+			Message:  fmt.Sprintf("chapter count for %s differs from the canon structure", book.Abbr),
+			Expected: entry.Chapters,
+			Actual:   book.Chapters,
+		})
+	}
+	return errors
+}
+
+// validateCoverage checks that every chapter counted in books.json has a raw source file mapped in
+// aliases.json. Mapped files missing from the raw directory are reported when the processor locates them.
+func (v *Validator) validateCoverage(book model.BookMetadata, chapters model.AliasChapters) []util.ValidationError {
+	var errors []util.ValidationError
+
+	// Special case: ESG (Esther Greek) only contains the additions starting at chapter 10,
+	// so chapters 1-9 are expected to be missing from the source
+	if book.Abbr == "ESG" {
+		return nil
+	}
+
+	for chapterNum := 1; chapterNum <= book.Chapters; chapterNum++ {
+		if _, exists := chapters.Chapters[strconv.Itoa(chapterNum)]; !exists {
+			errors = append(errors, util.ValidationError{
+				Type:     "coverage",
+				Message:  fmt.Sprintf("no raw source file for %s chapter %d", book.Abbr, chapterNum),
+				Expected: fmt.Sprintf("aliases.json entry for chapter %d", chapterNum),
+			})
+		}
+	}
+
+	return errors
+}
+
 // ValidateChapterFile validates the 3-point check for a single chapter
 func (v *Validator) ValidateChapterFile(
 	filename string,
@@ -98,6 +201,7 @@ func (v *Validator) ValidateChapterFile(
 			Message:  "chapter number mismatch between filename and <div class='chapterlabel'>",
 			Expected: chapterFromFilename,
 			Actual:   extractedChapter.ChapterNumber,
+			Location: util.SourceLocation{Element: ".chapterlabel"},
 		})
 	}
 
@@ -112,11 +216,12 @@ func (v *Validator) ValidateChapterFile(
 		})
 	}
 
-	// 4. Validate verse numbers are continuous (1..N)
-	// Special case: ESG (Esther Greek) has disordered chapter numbers, skip verse validation
-	if abbr != "ESG" {
-		verseErrors := v.validateVersesContinuous(filename, extractedChapter)
-		errors = append(errors, verseErrors...)
+	// 4. Validate verse numbers are continuous (1..N), or follow the chapter's verse map in the
+	// canon structure when it has one
+	if verses, ok := v.verseMap(abbr, chapterFromFilename); ok {
+		errors = append(errors, v.validateVerseMap(filename, extractedChapter, verses)...)
+	} else {
+		errors = append(errors, v.validateVersesContinuous(filename, extractedChapter)...)
 	}
 
 	// 5. Validate footnote anchors resolve
@@ -147,6 +252,7 @@ func (v *Validator) validateVersesContinuous(filename string, ec *util.Extracted
 			Message:  "verses do not start at 1",
 			Expected: 1,
 			Actual:   ec.Verses[0].Number,
+			Location: elementLocation(ec.Verses[0].ID),
 		})
 	}
 
@@ -161,6 +267,7 @@ func (v *Validator) validateVersesContinuous(filename string, ec *util.Extracted
 				Message:  fmt.Sprintf("gap in verse numbers: expected %d, got %d", expected, actual),
 				Expected: expected,
 				Actual:   actual,
+				Location: elementLocation(ec.Verses[i].ID),
 			})
 		}
 	}
@@ -168,6 +275,42 @@ func (v *Validator) validateVersesContinuous(filename string, ec *util.Extracted
 	return errors
 }
 
+// verseMap returns the verse numbers the canon structure expects a chapter to have, if it lists them
+func (v *Validator) verseMap(abbr string, chapter int) ([]int, bool) {
+	if v.structure == nil {
+		return nil, false
+	}
+	return v.structure.VerseMap(abbr, chapter)
+}
+
+// validateVerseMap checks that a chapter has exactly the verse numbers of its verse map, in order
+func (v *Validator) validateVerseMap(filename string, ec *util.ExtractedChapter, expected []int) []util.ValidationError {
+	actual := make([]int, len(ec.Verses))
+	for i, verse := range ec.Verses {
+		actual[i] = verse.Number
+	}
+	if slices.Equal(actual, expected) {
+		return nil
+	}
+
+	// Point at the first verse out of place, or the last verse if the chapter stops short
+	var location util.SourceLocation
+	for i, verse := range ec.Verses {
+		if i >= len(expected) || verse.Number != expected[i] || i == len(ec.Verses)-1 {
+			location = elementLocation(verse.ID)
+			break
+		}
+	}
+	return []util.ValidationError{{
+		File:     filename,
+		Type:     "verses",
+		Message:  "verse numbers differ from the chapter's verse map in the canon structure",
+		Expected: expected,
+		Actual:   actual,
+		Location: location,
+	}}
+}
+
 // validateFootnoteResolution checks that every footnote entry is properly formed
 func (v *Validator) validateFootnoteResolution(filename string, ec *util.ExtractedChapter) []util.ValidationError {
 	var errors []util.ValidationError
@@ -183,9 +326,10 @@ func (v *Validator) validateFootnoteResolution(filename string, ec *util.Extract
 		}
 		if fn.Mark == "" {
 			errors = append(errors, util.ValidationError{
-				File:    filename,
-				Type:    "footnotes",
-				Message: fmt.Sprintf("footnote %s has empty mark", fn.ID),
+				File:     filename,
+				Type:     "footnotes",
+				Message:  fmt.Sprintf("footnote %s has empty mark", fn.ID),
+				Location: elementLocation(fn.ID),
 			})
 		}
 		if fn.VerseNum < 1 {
@@ -195,13 +339,15 @@ func (v *Validator) validateFootnoteResolution(filename string, ec *util.Extract
 				Message:  fmt.Sprintf("footnote %s references invalid verse number %d", fn.ID, fn.VerseNum),
 				Expected: ">= 1",
 				Actual:   fn.VerseNum,
+				Location: elementLocation(fn.ID),
 			})
 		}
 		if fn.Text == "" {
 			errors = append(errors, util.ValidationError{
-				File:    filename,
-				Type:    "footnotes",
-				Message: fmt.Sprintf("footnote %s has empty text", fn.ID),
+				File:     filename,
+				Type:     "footnotes",
+				Message:  fmt.Sprintf("footnote %s has empty text", fn.ID),
+				Location: elementLocation(fn.ID),
 			})
 		}
 		// Verify footnote references a verse that exists in the chapter
@@ -223,6 +369,7 @@ func (v *Validator) validateFootnoteResolution(filename string, ec *util.Extract
 				),
 				Expected: "verse number in range 1..N",
 				Actual:   fn.VerseNum,
+				Location: elementLocation(fn.ID),
 			})
 		}
 	}
@@ -0,0 +1,216 @@
+package ingest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestValidateBookCoverage(t *testing.T) {
+	metadata := &MetadataLoader{
+		BooksByAbbr: map[string]model.BookMetadata{
+			"OBA": {OSIS: "Obad", Abbr: "OBA", Chapters: 1},
+			"JOL": {OSIS: "Joel", Abbr: "JOL", Chapters: 3},
+			"ESG": {OSIS: "Add Esth", Abbr: "ESG", Chapters: 10},
+		},
+		AliasesData: model.AliasesData{
+			"Obad": {SourceAbbr: "OBA", Chapters: map[string]string{"1": "raw/html/ot/OBA/OBA01.htm"}},
+			"Joel": {SourceAbbr: "JOL", Chapters: map[string]string{"1": "raw/html/ot/JOL/JOL01.htm"}},
+			"Add Esth": {
+				SourceAbbr: "ESG",
+				Chapters:   map[string]string{"10": "raw/html/ap/ESG/ESG10.htm"},
+			},
+		},
+	}
+	validator := NewValidator(metadata)
+
+	tests := []struct {
+		abbr         string
+		wantCoverage int
+	}{
+		{abbr: "OBA", wantCoverage: 0},
+		{abbr: "JOL", wantCoverage: 2},
+		{abbr: "ESG", wantCoverage: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.abbr, func(t *testing.T) {
+			errs, err := validator.ValidateBook(tt.abbr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			coverage := 0
+			for _, e := range errs {
+				if e.Type == "coverage" {
+					coverage++
+				}
+			}
+
+			if coverage != tt.wantCoverage {
+				t.Errorf("expected %d coverage errors, got %d (%v)", tt.wantCoverage, coverage, errs)
+			}
+		})
+	}
+}
+
+func TestValidateBookStructure(t *testing.T) {
+	structure, err := util.ParseCanonStructure([]byte(`{
+		"schema": 1,
+		"version": "test",
+		"work": "KJV",
+		"books": [
+			{ "abbr": "OBA", "osis": "Obad", "testament": "OT", "chapters": 1 },
+			{ "abbr": "JOL", "osis": "Joel", "testament": "OT", "chapters": 3 }
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("failed to parse structure: %v", err)
+	}
+
+	metadata := &MetadataLoader{
+		BooksByAbbr: map[string]model.BookMetadata{
+			"OBA": {OSIS: "Obad", Abbr: "OBA", Testament: "OT", Chapters: 1},
+			"JOL": {OSIS: "Joel", Abbr: "JOL", Testament: "AP", Chapters: 4},
+			"MAL": {OSIS: "Mal", Abbr: "MAL", Testament: "OT", Chapters: 1},
+		},
+		AliasesData: model.AliasesData{
+			"Obad": {SourceAbbr: "OBA", Chapters: map[string]string{"1": "raw/html/ot/OBA/OBA01.htm"}},
+			"Joel": {SourceAbbr: "JOL", Chapters: map[string]string{}},
+			"Mal":  {SourceAbbr: "MAL", Chapters: map[string]string{"1": "raw/html/ot/MAL/MAL01.htm"}},
+		},
+	}
+	validator := NewValidator(metadata)
+	validator.UseStructure(structure)
+
+	tests := []struct {
+		abbr          string
+		wantStructure int
+	}{
+		{abbr: "OBA", wantStructure: 0},
+		{abbr: "JOL", wantStructure: 2}, // testament and chapter count
+		{abbr: "MAL", wantStructure: 1}, // not in the structure
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.abbr, func(t *testing.T) {
+			errs, err := validator.ValidateBook(tt.abbr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			count := 0
+			for _, e := range errs {
+				if e.Type == "structure" {
+					count++
+				}
+			}
+			if count != tt.wantStructure {
+				t.Errorf("expected %d structure errors, got %d (%v)", tt.wantStructure, count, errs)
+			}
+		})
+	}
+}
+
+func TestValidateVerseMap(t *testing.T) {
+	structure, err := util.ParseCanonStructure([]byte(`{
+		"schema": 1,
+		"version": "test",
+		"work": "KJV",
+		"books": [
+			{ "abbr": "ESG", "osis": "AddEsth", "testament": "AP", "chapters": 10, "verse_maps": { "10": [4, 5, 6] } },
+			{ "abbr": "OBA", "osis": "Obad", "testament": "OT", "chapters": 1 }
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("failed to parse structure: %v", err)
+	}
+	metadata := &MetadataLoader{
+		BooksByAbbr: map[string]model.BookMetadata{
+			"ESG": {OSIS: "AddEsth", Abbr: "ESG", Testament: "AP", Chapters: 10},
+			"OBA": {OSIS: "Obad", Abbr: "OBA", Testament: "OT", Chapters: 1},
+		},
+	}
+	validator := NewValidator(metadata)
+	validator.UseStructure(structure)
+
+	chapter := func(number int, verses ...int) *util.ExtractedChapter {
+		ec := &util.ExtractedChapter{ChapterNumber: number}
+		for _, v := range verses {
+			ec.Verses = append(ec.Verses, util.ExtractedVerse{Number: v})
+		}
+		return ec
+	}
+	tests := []struct {
+		name       string
+		filename   string
+		chapter    *util.ExtractedChapter
+		wantVerses int
+	}{
+		{name: "follows its verse map", filename: "ESG10.htm", chapter: chapter(10, 4, 5, 6)},
+		{name: "differs from its verse map", filename: "ESG10.htm", chapter: chapter(10, 4, 5), wantVerses: 1},
+		{name: "numbered from 1", filename: "OBA01.htm", chapter: chapter(1, 1, 2, 3)},
+		{name: "gap without a verse map", filename: "OBA01.htm", chapter: chapter(1, 1, 3), wantVerses: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := 0
+			for _, e := range validator.ValidateChapterFile(tt.filename, tt.chapter) {
+				if e.Type == "verses" {
+					count++
+				}
+			}
+			if count != tt.wantVerses {
+				t.Errorf("expected %d verse errors, got %d", tt.wantVerses, count)
+			}
+		})
+	}
+}
+
+func TestParseCanonStructure(t *testing.T) {
+	book := func(abbr, osis, testament string, chapters int) string {
+		return fmt.Sprintf(`{"abbr": %q, "osis": %q, "testament": %q, "chapters": %d}`, abbr, osis, testament, chapters)
+	}
+	doc := func(schema int, books ...string) string {
+		return fmt.Sprintf(`{"schema": %d, "version": "1", "work": "KJV", "books": [%s]}`, schema, strings.Join(books, ","))
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "valid", data: doc(1, book("GEN", "Gen", "OT", 50), book("TOB", "Tob", "AP", 14))},
+		{name: "schema", data: doc(2, book("GEN", "Gen", "OT", 50)), wantErr: "schema"},
+		{name: "no books", data: doc(1), wantErr: "no books"},
+		{name: "abbreviation", data: doc(1, book("Gen", "Gen", "OT", 50)), wantErr: "invalid abbreviation"},
+		{name: "duplicate", data: doc(1, book("GEN", "Gen", "OT", 50), book("GEN", "Gen2", "OT", 50)), wantErr: "more than once"},
+		{name: "duplicate osis", data: doc(1, book("GEN", "Gen", "OT", 50), book("EXO", "Gen", "OT", 40)), wantErr: "more than once"},
+		{name: "testament", data: doc(1, book("GEN", "Gen", "DC", 50)), wantErr: "unknown testament"},
+		{name: "chapters", data: doc(1, book("GEN", "Gen", "OT", 0)), wantErr: "chapter count"},
+		{name: "malformed", data: `{"schema": 1,`, wantErr: "failed to parse"},
+		{name: "verse map chapter", data: doc(1, `{"abbr": "ESG", "osis": "AddEsth", "testament": "AP", "chapters": 10, "verse_maps": {"11": [1]}}`), wantErr: "outside chapters"},
+		{name: "verse map order", data: doc(1, `{"abbr": "ESG", "osis": "AddEsth", "testament": "AP", "chapters": 10, "verse_maps": {"10": [5, 4]}}`), wantErr: "ascending"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := util.ParseCanonStructure([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if _, order, ok := s.Book("TOB"); !ok || order != 2 {
+					t.Errorf("expected TOB at order 2, got %d (%v)", order, ok)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+// watchDebounce is how long Watch waits after a raw file changes for more changes, so the burst
+// of writes an editor makes when saving is ingested once
+const watchDebounce = 200 * time.Millisecond
+
+// watchedChapter is a chapter whose raw file Watch re-ingests when it changes
+type watchedChapter struct {
+	abbr    string
+	chapter int
+}
+
+// watchedChapters maps the raw files of the chapters of the books abbrs, by cleaned path, to their
+// chapters. Book introductions and files that do not exist are left out.
+func (proc *Processor) watchedChapters(abbrs []string) map[string]watchedChapter {
+	files := make(map[string]watchedChapter)
+	for _, abbr := range abbrs {
+		book, ok := proc.metadata.GetBookByAbbr(abbr)
+		if !ok {
+			continue
+		}
+		chapters, ok := proc.metadata.GetChaptersForBook(book.OSIS)
+		if !ok {
+			continue
+		}
+		for key, metadataPath := range chapters.Chapters {
+			n, err := strconv.Atoi(key)
+			if err != nil || n < 1 {
+				continue
+			}
+			path, err := proc.constructRawFilePath(metadataPath)
+			if err != nil {
+				continue
+			}
+			files[filepath.Clean(path)] = watchedChapter{abbr: abbr, chapter: n}
+		}
+	}
+	return files
+}
+
+// Watch re-ingests the chapters of the books abbrs whose raw files change, until ctx is done.
+// Each changed chapter is parsed, validated, and written as by ProcessBook, and its entries in
+// filemap.json, verses.json, and book.json are updated in place; done is called with its result.
+func (proc *Processor) Watch(ctx context.Context, abbrs []string, done func(*util.ProcessResult)) error {
+	files := proc.watchedChapters(abbrs)
+	if len(files) == 0 {
+		return fmt.Errorf("no raw chapter files to watch")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start watching: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	// fsnotify watches directories rather than trees, so each directory holding a chapter is added
+	dirs := make(map[string]bool)
+	for path := range files {
+		dirs[filepath.Dir(path)] = true
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+	}
+
+	pending := make(map[string]bool)
+	var flush <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// Editors save by writing the file in place or by renaming a new file over it
+			if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+			path := filepath.Clean(event.Name)
+			if _, ok := files[path]; !ok {
+				continue
+			}
+			pending[path] = true
+			flush = time.After(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Printf("Warning: watch error: %v\n", err)
+		case <-flush:
+			flush = nil
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			slices.Sort(paths)
+			clear(pending)
+			for _, path := range paths {
+				result, err := proc.reingest(files[path])
+				if err != nil {
+					fmt.Printf("Error processing %s: %v\n", path, err)
+					continue
+				}
+				done(result)
+			}
+		}
+	}
+}
+
+// reingest processes one chapter and merges its filemap and verse index entries into the
+// existing indexes
+func (proc *Processor) reingest(ch watchedChapter) (*util.ProcessResult, error) {
+	proc.UseChapters(ch.chapter, ch.chapter)
+	if err := proc.BeginExport(); err != nil {
+		return nil, fmt.Errorf("failed to start exporters: %w", err)
+	}
+	result, err := proc.ProcessBook(ch.abbr)
+	if err != nil {
+		return nil, err
+	}
+	if err := proc.FinishExport(); err != nil {
+		return nil, fmt.Errorf("failed to finish exporters: %w", err)
+	}
+
+	if len(result.FileMap.Files) > 0 {
+		conflicts, err := proc.WriteFileMap(result.FileMap, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, conflict := range conflicts {
+			fmt.Printf("Warning: filemap conflict: %s is now produced from %s, dropped the entry for %s\n",
+				conflict.Output, conflict.Incoming, conflict.Existing)
+		}
+	}
+	if len(result.Verses.Books) > 0 {
+		if err := proc.WriteVerseIndex(result.Verses); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func TestWatch(t *testing.T) {
+	indexDir, rawDir, outputDir := genesisFixture(t, map[string]string{"GEN03.htm": chapterHTML})
+	proc, err := NewProcessor(indexDir, rawDir, outputDir, "KJV", []string{"json"}, false)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := make(chan *util.ProcessResult, 8)
+	watching := make(chan error, 1)
+	go func() { watching <- proc.Watch(ctx, []string{"GEN"}, func(r *util.ProcessResult) { results <- r }) }()
+
+	// The watcher may not be running yet when the file is first saved, so it is saved until the
+	// change is picked up
+	edited := strings.Replace(chapterHTML, "And the earth.", "And the earth was without form.", 1)
+	rawPath := filepath.Join(rawDir, "html", "ot", "GEN", "GEN03.htm")
+	var result *util.ProcessResult
+	deadline := time.After(10 * time.Second)
+	for result == nil {
+		if err := os.WriteFile(rawPath, []byte(edited), 0600); err != nil {
+			t.Fatalf("failed to write GEN03.htm: %v", err)
+		}
+		select {
+		case result = <-results:
+		case <-time.After(2 * watchDebounce):
+		case <-deadline:
+			t.Fatal("the change was not ingested")
+		}
+	}
+	cancel()
+	if err := <-watching; err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	if result.FilesProcessed != 1 || len(result.FileMap.Files) != 1 {
+		t.Errorf("got %d files processed and %d filemap entries, want 1 and 1", result.FilesProcessed, len(result.FileMap.Files))
+	}
+	chapter, err := os.ReadFile(filepath.Join(outputDir, "books", "Gen", "ch03.json")) // nolint: gosec
+	if err != nil || !strings.Contains(string(chapter), "without form") {
+		t.Errorf("edited chapter was not written: %v", err)
+	}
+	for _, index := range []string{"filemap.json", "verses.json"} {
+		if _, err := os.Stat(filepath.Join(outputDir, "index", index)); err != nil {
+			t.Errorf("%s was not written: %v", index, err)
+		}
+	}
+}
+
+func TestWatchedChapters(t *testing.T) {
+	indexDir, rawDir, outputDir := genesisFixture(t, map[string]string{"GEN00.htm": "<html></html>", "GEN03.htm": chapterHTML})
+	proc, err := NewProcessor(indexDir, rawDir, outputDir, "KJV", []string{"json"}, false)
+	if err != nil {
+		t.Fatalf("failed to create processor: %v", err)
+	}
+	files := proc.watchedChapters([]string{"GEN", "EXO"})
+	if len(files) != 1 {
+		t.Fatalf("expected only chapter 3 to be watched, got %v", files)
+	}
+	if ch := files[filepath.Join(rawDir, "html", "ot", "GEN", "GEN03.htm")]; ch.abbr != "GEN" || ch.chapter != 3 {
+		t.Errorf("unexpected watched chapter %+v", ch)
+	}
+}
@@ -0,0 +1,91 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+// OSISCmd renames books with spaced codes to canonical OSIS IDs
+type OSISCmd struct {
+	Canon       string `type:"existingdir" help:"The canon directory containing index/ and books/" default:"./canon/kjv"`
+	DryRun      bool   `                   help:"List the books that would be renamed without changing anything"`
+	ForceUnlock bool   `                   help:"Take the lock on --canon even from a run holding it, such as a hung one"`
+}
+
+// ManifestsCmd writes book.json for books ingested before ingest wrote one
+type ManifestsCmd struct {
+	Canon       string `type:"existingdir" help:"The canon directory containing index/ and books/" default:"./canon/kjv"`
+	DryRun      bool   `                   help:"List the books that would get a book.json without writing anything"`
+	ForceUnlock bool   `                   help:"Take the lock on --canon even from a run holding it, such as a hung one"`
+}
+
+// Cmd upgrades an existing canon directory in place
+type Cmd struct {
+	OSIS      OSISCmd      `cmd:"" help:"Rename books such as \"1 Macc\" to canonical OSIS IDs such as \"1Macc\"" name:"osis"`
+	Manifests ManifestsCmd `cmd:"" help:"Write books/{OSIS}/book.json for books that lack one"`
+}
+
+func (c *OSISCmd) Run(ctx context.Context) error {
+	lock, err := util.AcquireLock(c.Canon, "migrate osis", c.ForceUnlock)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	result, err := MigrateOSIS(c.Canon, c.DryRun)
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	olds := make([]string, 0, len(result.Renamed))
+	for old := range result.Renamed {
+		olds = append(olds, old)
+	}
+	sort.Strings(olds)
+
+	fmt.Printf("========================================\n")
+	if len(olds) == 0 {
+		fmt.Printf("Book codes in %s are already canonical OSIS IDs\n", c.Canon)
+	}
+	for _, old := range olds {
+		fmt.Printf("%s -> %s\n", old, result.Renamed[old])
+	}
+	if c.DryRun {
+		fmt.Printf("Books To Rename: %d (dry run, nothing written)\n", len(olds))
+	} else {
+		fmt.Printf("Books Renamed: %d\n", len(olds))
+		fmt.Printf("Files Rewritten: %d\n", result.Files)
+	}
+	fmt.Printf("========================================\n")
+	return nil
+}
+
+func (c *ManifestsCmd) Run(ctx context.Context) error {
+	lock, err := util.AcquireLock(c.Canon, "migrate manifests", c.ForceUnlock)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	written, err := MigrateManifests(c.Canon, c.DryRun)
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("========================================\n")
+	if len(written) == 0 {
+		fmt.Printf("Every book in %s already has a book.json\n", c.Canon)
+	}
+	if c.DryRun {
+		fmt.Printf("Manifests To Write: %d (dry run, nothing written)\n", len(written))
+	} else {
+		fmt.Printf("Manifests Written: %d\n", len(written))
+	}
+	fmt.Printf("========================================\n")
+	return nil
+}
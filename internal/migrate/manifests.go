@@ -0,0 +1,55 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// MigrateManifests writes books/{OSIS}/book.json for every book of a canon ingested before ingest
+// wrote them, listing the chapter files recorded for the book in filemap.json. Books that already
+// have a manifest, or no directory, are left alone. It returns the books given a manifest; with
+// dryRun, the books that would be are reported without writing anything.
+func MigrateManifests(canonDir string, dryRun bool) ([]string, error) {
+	var books model.BooksData
+	if err := readJSON(filepath.Join(canonDir, "index", "books.json"), &books); err != nil {
+		return nil, err
+	}
+	file := filepath.Join(canonDir, "index", "filemap.json")
+	data, err := os.ReadFile(file) // nolint: gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	fileMap, err := model.ParseFileMap(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	var written []string
+	for _, book := range books.Books {
+		bookDir := filepath.Join(canonDir, "books", book.OSIS)
+		if _, err := os.Stat(bookDir); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(bookDir, util.BookManifestName)); err == nil {
+			continue
+		}
+
+		if !dryRun {
+			manifest, err := util.BuildBookManifest(canonDir, books.Work, book.OSIS, fileMap)
+			if err != nil {
+				return written, err
+			}
+			if err := util.WriteBookManifest(canonDir, manifest); err != nil {
+				return written, err
+			}
+		}
+		written = append(written, book.OSIS)
+	}
+	return written, nil
+}
@@ -0,0 +1,54 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestMigrateManifests(t *testing.T) {
+	dir := legacyCanon(t)
+
+	dry, err := MigrateManifests(dir, true)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(dry) != 2 {
+		t.Fatalf("expected both books to need a manifest, got %v", dry)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "books", "Gen", util.BookManifestName)); !os.IsNotExist(err) {
+		t.Fatal("expected a dry run to leave the canon alone")
+	}
+
+	written, err := MigrateManifests(dir, false)
+	if err != nil {
+		t.Fatalf("MigrateManifests failed: %v", err)
+	}
+	if len(written) != 2 || written[0] != "Gen" {
+		t.Fatalf("expected Gen and 1 Macc, got %v", written)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "books", "Gen", util.BookManifestName)) // nolint: gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := model.ParseBookManifest(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, _ := util.FileSHA256(filepath.Join(dir, "books", "Gen", "ch01.json"))
+	if manifest.OSIS != "Gen" || manifest.Work != "KJV" || len(manifest.Chapters) != 1 {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+	if got := manifest.Chapters[0]; got.Chapter != 1 || got.File != "ch01.json" || got.SHA256 != sum {
+		t.Errorf("unexpected chapter entry: %+v", got)
+	}
+
+	again, err := MigrateManifests(dir, false)
+	if err != nil || len(again) != 0 {
+		t.Errorf("expected a second run to write nothing, got %v, %v", again, err)
+	}
+}
@@ -0,0 +1,330 @@
+// Package migrate upgrades existing canon directories to the current layout in place.
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// OSISResult reports what MigrateOSIS changed
+type OSISResult struct {
+	Renamed map[string]string // old book code -> canonical OSIS ID
+	Files   int               // chapter, introduction, alignment, and index files rewritten
+}
+
+// MigrateOSIS renames the books of a canon whose codes contain spaces, such as "1 Macc" and
+// "Add Esth", to canonical OSIS IDs such as "1Macc" and "AddEsth". The books/ and align/
+// directories are renamed, the "osis" field of every file beneath them rewritten, and every index
+// keyed by or listing book codes updated. The old code is kept as an alias in books.json, so
+// references such as "1 Macc 1:1" still parse. A canon that is already canonical is left alone;
+// with dryRun, the renames are reported without changing anything.
+func MigrateOSIS(canonDir string, dryRun bool) (*OSISResult, error) {
+	var books model.BooksData
+	if err := readJSON(filepath.Join(canonDir, "index", "books.json"), &books); err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(books.Books))
+	for _, book := range books.Books {
+		known[book.OSIS] = true
+	}
+	result := &OSISResult{Renamed: make(map[string]string)}
+	for _, book := range books.Books {
+		if model.IsCanonicalOSIS(book.OSIS) {
+			continue
+		}
+		id := model.CanonicalOSIS(book.OSIS)
+		if known[id] {
+			return nil, fmt.Errorf("cannot rename %s to %s: books.json already has a book %s", book.OSIS, id, id)
+		}
+		result.Renamed[book.OSIS] = id
+	}
+	if len(result.Renamed) == 0 || dryRun {
+		return result, nil
+	}
+
+	m := &osisMigration{canon: canonDir, renamed: result.Renamed}
+	err := m.run()
+	result.Files = m.files
+	return result, err
+}
+
+// osisMigration rewrites one canon for MigrateOSIS
+type osisMigration struct {
+	canon   string
+	renamed map[string]string
+	files   int
+}
+
+// rename returns the canonical ID of a renamed book, or the code unchanged
+func (m *osisMigration) rename(code string) string {
+	if id, ok := m.renamed[code]; ok {
+		return id
+	}
+	return code
+}
+
+// run moves the book directories first, so the checksums in filemap.json can be taken from the
+// rewritten chapter files, then updates the indexes
+func (m *osisMigration) run() error {
+	for old := range m.renamed {
+		if err := m.moveBook("books", old); err != nil {
+			return err
+		}
+		if err := m.moveBook("align", old); err != nil {
+			return err
+		}
+	}
+
+	steps := []func() error{
+		m.migrateBooks,
+		m.migrateFileMap,
+		func() error {
+			var osis map[string]string
+			return m.rewriteIndex("osis.json", &osis, func() bool { return renameKeys(osis, m.renamed) })
+		},
+		func() error {
+			var aliases model.AliasesData
+			return m.rewriteIndex("aliases.json", &aliases, func() bool { return renameKeys(aliases, m.renamed) })
+		},
+		func() error {
+			var verses model.VerseIndex
+			return m.rewriteIndex("verses.json", &verses, func() bool {
+				changed := renameKeys(verses.Books, m.renamed)
+				return renameKeys(verses.Maps, m.renamed) || changed
+			})
+		},
+		func() error {
+			var chronology model.Chronology
+			return m.rewriteIndex("chronological.json", &chronology, func() bool {
+				changed := false
+				for i := range chronology.Passages {
+					changed = m.renameField(&chronology.Passages[i].OSIS) || changed
+				}
+				return changed
+			})
+		},
+		func() error {
+			var parallels model.Parallels
+			return m.rewriteIndex("parallels.json", &parallels, func() bool {
+				changed := false
+				for i := range parallels.Pairs {
+					changed = m.renameField(&parallels.Pairs[i].A.OSIS) || changed
+					changed = m.renameField(&parallels.Pairs[i].B.OSIS) || changed
+				}
+				return changed
+			})
+		},
+		func() error {
+			var versification model.Versification
+			return m.rewriteIndex("versification.json", &versification, func() bool {
+				changed := false
+				for _, scheme := range versification.Schemes {
+					for i := range scheme.Mappings {
+						changed = m.renameField(&scheme.Mappings[i].OSIS) || changed
+					}
+				}
+				return changed
+			})
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveBook rewrites the "osis" field of the chapter, introduction, or alignment files of a book
+// beneath dir (books or align), and of its book.json, then renames the book's directory to its canonical ID. A book
+// without a directory there is skipped.
+func (m *osisMigration) moveBook(dir, old string) error {
+	src := filepath.Join(m.canon, dir, old)
+	dst := filepath.Join(m.canon, dir, m.renamed[old])
+	entries, err := os.ReadDir(src)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("cannot rename %s: %s already exists", src, dst)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		file := filepath.Join(src, entry.Name())
+		switch {
+		case dir == "align":
+			var alignment model.Alignment
+			err = m.rewriteFile(file, &alignment, &alignment.OSIS)
+		case entry.Name() == "intro.json":
+			var intro model.BookIntro
+			err = m.rewriteFile(file, &intro, &intro.OSIS)
+		case entry.Name() == util.BookManifestName:
+			var manifest model.BookManifest
+			err = m.rewriteFile(file, &manifest, &manifest.OSIS)
+		default:
+			var chapter model.Chapter
+			err = m.rewriteFile(file, &chapter, &chapter.OSIS)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("failed to rename %s: %w", src, err)
+	}
+	return nil
+}
+
+// rewriteFile reads a canon document, renames the book code osis points into, and writes it back
+func (m *osisMigration) rewriteFile(file string, doc any, osis *string) error {
+	if err := readJSON(file, doc); err != nil {
+		return err
+	}
+	if !m.renameField(osis) {
+		return nil
+	}
+	return m.writeJSON(file, doc)
+}
+
+// migrateBooks gives each renamed book in books.json its canonical ID, keeping the old code as an alias
+func (m *osisMigration) migrateBooks() error {
+	var books model.BooksData
+	return m.rewriteIndex("books.json", &books, func() bool {
+		changed := false
+		for i, book := range books.Books {
+			id, ok := m.renamed[book.OSIS]
+			if !ok {
+				continue
+			}
+			if !slices.Contains(book.Aliases, book.OSIS) {
+				books.Books[i].Aliases = append(books.Books[i].Aliases, book.OSIS)
+			}
+			books.Books[i].OSIS = id
+			changed = true
+		}
+		return changed
+	})
+}
+
+// migrateFileMap points filemap.json at the renamed chapter files, updating their checksums
+func (m *osisMigration) migrateFileMap() error {
+	file := filepath.Join(m.canon, "index", "filemap.json")
+	data, err := os.ReadFile(file) // nolint: gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	fileMap, err := model.ParseFileMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	changed := false
+	for raw, entry := range fileMap.Files {
+		parts := strings.SplitN(entry.Output, "/", 3)
+		if len(parts) != 3 || parts[0] != "books" {
+			continue
+		}
+		id, ok := m.renamed[parts[1]]
+		if !ok {
+			continue
+		}
+		entry.Output = path.Join(parts[0], id, parts[2])
+		if entry.OutputSHA256 != "" {
+			sum, err := util.FileSHA256(filepath.Join(m.canon, filepath.FromSlash(entry.Output)))
+			if err != nil {
+				return err
+			}
+			entry.OutputSHA256 = sum
+		}
+		fileMap.Files[raw] = entry
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return m.writeJSON(file, fileMap)
+}
+
+// rewriteIndex reads an index file into doc, calls rename, and writes the file back if rename
+// reports a change. A missing index is skipped, since most are optional.
+func (m *osisMigration) rewriteIndex(name string, doc any, rename func() bool) error {
+	file := filepath.Join(m.canon, "index", name)
+	if err := readJSON(file, doc); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if !rename() {
+		return nil
+	}
+	return m.writeJSON(file, doc)
+}
+
+// renameField renames the book code in place, reporting whether it changed
+func (m *osisMigration) renameField(osis *string) bool {
+	id := m.rename(*osis)
+	if id == *osis {
+		return false
+	}
+	*osis = id
+	return true
+}
+
+// renameKeys moves the values of a map keyed by book code to the renamed keys, reporting whether any moved
+func renameKeys[V any](values map[string]V, renamed map[string]string) bool {
+	changed := false
+	for old, id := range renamed {
+		if v, ok := values[old]; ok {
+			delete(values, old)
+			values[id] = v
+			changed = true
+		}
+	}
+	return changed
+}
+
+// readJSON reads and unmarshals a canon file
+func readJSON(file string, v any) error {
+	data, err := os.ReadFile(file) // nolint: gosec
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return nil
+}
+
+// writeJSON writes a canon file as indented JSON, as ingest and extract do
+func (m *osisMigration) writeJSON(file string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", file, err)
+	}
+	if err := atomicfile.WriteFile(file, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	m.files++
+	return nil
+}
@@ -0,0 +1,150 @@
+package migrate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// writeDoc writes a canon document beneath dir
+func writeDoc(t *testing.T, dir, name string, v any) {
+	t.Helper()
+
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// legacyCanon writes a canon with Genesis and 1 Maccabees, the latter under its spaced code
+func legacyCanon(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	writeDoc(t, dir, "index/books.json", model.BooksData{Schema: model.BooksSchema, Work: "KJV", Books: []model.BookMetadata{
+		{OSIS: "Gen", Abbr: "GEN", Name: "Genesis", Aliases: []string{"Genesis"}, Testament: "OT", Order: 1, Chapters: 1},
+		{OSIS: "1 Macc", Abbr: "1MA", Name: "1 Maccabees", Aliases: []string{"1 Maccabees"}, Testament: "AP", Order: 2, Chapters: 1},
+	}})
+	writeDoc(t, dir, "index/osis.json", map[string]string{"Gen": "Genesis", "1 Macc": "1 Maccabees"})
+	writeDoc(t, dir, "index/verses.json", model.VerseIndex{Schema: 1, Books: map[string][]int{"Gen": {31}, "1 Macc": {64}}})
+	writeDoc(t, dir, "index/chronological.json", model.Chronology{Schema: 1, Passages: []model.ChapterSpan{
+		{OSIS: "Gen", Start: 1, End: 1}, {OSIS: "1 Macc", Start: 1, End: 1},
+	}})
+	writeDoc(t, dir, "books/Gen/ch01.json", model.Chapter{Schema: 1, Work: "KJV", OSIS: "Gen", Abbr: "GEN", Chapter: 1})
+	writeDoc(t, dir, "books/1 Macc/ch01.json", model.Chapter{Schema: 1, Work: "KJV", OSIS: "1 Macc", Abbr: "1MA", Chapter: 1})
+	writeDoc(t, dir, "books/1 Macc/intro.json", model.BookIntro{Schema: 1, Work: "KJV", OSIS: "1 Macc", Abbr: "1MA"})
+	writeDoc(t, dir, "align/1 Macc/ch01.json", model.Alignment{Schema: 1, OSIS: "1 Macc", Chapter: 1})
+
+	fileMap := model.NewFileMap()
+	fileMap.Add(model.FileMapEntry{Raw: "raw/html/ap/1MA/1MA01.htm", Output: "books/1 Macc/ch01.json", OutputSHA256: "stale"})
+	fileMap.Add(model.FileMapEntry{Raw: "raw/html/ot/GEN/GEN01.htm", Output: "books/Gen/ch01.json"})
+	writeDoc(t, dir, "index/filemap.json", fileMap)
+	return dir
+}
+
+func TestMigrateOSIS(t *testing.T) {
+	dir := legacyCanon(t)
+
+	dry, err := MigrateOSIS(dir, true)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if dry.Renamed["1 Macc"] != "1Macc" || len(dry.Renamed) != 1 || dry.Files != 0 {
+		t.Fatalf("unexpected dry run: %+v", dry)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "books", "1 Macc")); err != nil {
+		t.Fatal("expected a dry run to leave the canon alone")
+	}
+
+	result, err := MigrateOSIS(dir, false)
+	if err != nil {
+		t.Fatalf("MigrateOSIS failed: %v", err)
+	}
+	// Chapter, intro, and alignment, then books, filemap, osis, verses, and chronological
+	if result.Files != 8 {
+		t.Errorf("expected 8 files rewritten, got %d", result.Files)
+	}
+
+	for _, gone := range []string{"books/1 Macc", "align/1 Macc"} {
+		if _, err := os.Stat(filepath.Join(dir, gone)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be renamed", gone)
+		}
+	}
+	var chapter model.Chapter
+	if err := readJSON(filepath.Join(dir, "books", "1Macc", "ch01.json"), &chapter); err != nil || chapter.OSIS != "1Macc" {
+		t.Errorf("expected books/1Macc/ch01.json for 1Macc, got %q, %v", chapter.OSIS, err)
+	}
+	var intro model.BookIntro
+	if err := readJSON(filepath.Join(dir, "books", "1Macc", "intro.json"), &intro); err != nil || intro.OSIS != "1Macc" {
+		t.Errorf("expected the introduction to be rewritten, got %q, %v", intro.OSIS, err)
+	}
+	var alignment model.Alignment
+	if err := readJSON(filepath.Join(dir, "align", "1Macc", "ch01.json"), &alignment); err != nil || alignment.OSIS != "1Macc" {
+		t.Errorf("expected the alignment to be rewritten, got %q, %v", alignment.OSIS, err)
+	}
+
+	var books model.BooksData
+	if err := readJSON(filepath.Join(dir, "index", "books.json"), &books); err != nil {
+		t.Fatal(err)
+	}
+	if books.Books[1].OSIS != "1Macc" || !slices.Contains(books.Books[1].Aliases, "1 Macc") {
+		t.Errorf("expected 1Macc with the alias 1 Macc, got %+v", books.Books[1])
+	}
+	var osis map[string]string
+	if err := readJSON(filepath.Join(dir, "index", "osis.json"), &osis); err != nil || osis["1Macc"] != "1 Maccabees" || len(osis) != 2 {
+		t.Errorf("unexpected osis.json: %v, %v", osis, err)
+	}
+	var verses model.VerseIndex
+	if err := readJSON(filepath.Join(dir, "index", "verses.json"), &verses); err != nil || verses.Books["1Macc"][0] != 64 {
+		t.Errorf("unexpected verses.json: %v, %v", verses.Books, err)
+	}
+	var chronology model.Chronology
+	if err := readJSON(filepath.Join(dir, "index", "chronological.json"), &chronology); err != nil || chronology.Passages[1].OSIS != "1Macc" {
+		t.Errorf("unexpected chronological.json: %v, %v", chronology.Passages, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "index", "filemap.json")) // nolint: gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileMap, err := model.ParseFileMap(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := fileMap.ByRaw("raw/html/ap/1MA/1MA01.htm")
+	sum, _ := util.FileSHA256(filepath.Join(dir, "books", "1Macc", "ch01.json"))
+	if entry.Output != "books/1Macc/ch01.json" || entry.OutputSHA256 != sum {
+		t.Errorf("expected the filemap to point at the rewritten chapter, got %+v", entry)
+	}
+	if entry, _ := fileMap.ByRaw("raw/html/ot/GEN/GEN01.htm"); entry.OutputSHA256 != "" {
+		t.Errorf("expected a legacy entry to stay without a checksum, got %q", entry.OutputSHA256)
+	}
+
+	// A canonical canon is left alone
+	again, err := MigrateOSIS(dir, false)
+	if err != nil || len(again.Renamed) != 0 || again.Files != 0 {
+		t.Errorf("expected a second migration to do nothing, got %+v, %v", again, err)
+	}
+}
+
+func TestMigrateOSISConflict(t *testing.T) {
+	dir := legacyCanon(t)
+	if err := os.MkdirAll(filepath.Join(dir, "books", "1Macc"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MigrateOSIS(dir, false); err == nil {
+		t.Error("expected an existing books/1Macc to stop the migration")
+	}
+}
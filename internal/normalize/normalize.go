@@ -0,0 +1,108 @@
+// Package normalize is the whitespace and entity policy shared by the ingest parser, which builds
+// each verse's plain text and tokens from raw HTML, and kjv-verify canon, which checks that the
+// tokens still flatten to the plain text. Keeping one implementation means the two cannot drift.
+//
+// The policy, applied in this order:
+//
+//   - Entities: the named references &amp; &lt; &gt; &quot; &apos; and &nbsp;, &#160;, and decimal
+//     references below 128 such as &#39; are decoded, repeatedly for references that decode to
+//     another reference. &nbsp; and &#160; decode to a plain space. Other references are preserved
+//     as written. html.Parse has already decoded the source once, so these are double-escaped
+//     leftovers.
+//   - Whitespace: every run of ASCII whitespace (space, tab, newline, form feed, carriage return,
+//     what \s matches) collapses to a single space. Decoding comes first, so a decoded &nbsp; or
+//     &#9; collapses with its neighbours. A literal no-break space (U+00A0) and other Unicode
+//     spaces are text, and are preserved.
+//   - Trimming: Text trims leading and trailing spaces; Token does not, since the spaces at the
+//     edges of a token separate it from its neighbours.
+package normalize
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Text normalizes a whole run of text, such as a verse's plain text, a title, or a footnote
+func Text(s string) string {
+	return strings.Trim(Token(s), " ")
+}
+
+// Token normalizes one piece of a verse, keeping a single leading or trailing space
+func Token(s string) string {
+	return Collapse(Entities(s))
+}
+
+// Collapse replaces each run of ASCII whitespace in s with a single space, returning s itself when
+// it has nothing to collapse
+func Collapse(s string) string {
+	clean := true
+	for i := 0; i < len(s); i++ {
+		if isSpace(s[i]) && (s[i] != ' ' || (i+1 < len(s) && isSpace(s[i+1]))) {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	inSpace := false
+	for i := 0; i < len(s); i++ {
+		if isSpace(s[i]) {
+			if !inSpace {
+				b.WriteByte(' ')
+			}
+			inSpace = true
+			continue
+		}
+		inSpace = false
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// isSpace reports whether c is an ASCII whitespace character, as matched by \s
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
+// entityReplacer decodes the named HTML entities left in text
+var entityReplacer = strings.NewReplacer(
+	"&#160;", " ",
+	"&nbsp;", " ",
+	"&amp;", "&",
+	"&lt;", "<",
+	"&gt;", ">",
+	"&quot;", "\"",
+	"&apos;", "'",
+)
+
+// numericEntity matches decimal character references such as &#39;
+var numericEntity = regexp.MustCompile(`&#(\d+);`)
+
+// Entities decodes the HTML entities the policy covers, leaving any others as written
+func Entities(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+	result := entityReplacer.Replace(s)
+
+	// Repeat for references that decode to another reference, such as &#38;#39;
+	for i := 0; i < 10 && strings.Contains(result, "&#"); i++ {
+		decoded := numericEntity.ReplaceAllStringFunc(result, func(match string) string {
+			if num, err := strconv.Atoi(match[2 : len(match)-1]); err == nil && num < 128 { // ASCII range
+				return string(rune(num))
+			}
+			return match
+		})
+		if decoded == result {
+			break
+		}
+		result = decoded
+	}
+
+	return result
+}
@@ -0,0 +1,92 @@
+package normalize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"unchanged", "In the beginning God", "In the beginning God"},
+		{"empty", "", ""},
+		{"only whitespace", " \t\n ", ""},
+		{"collapses runs", "God  created\tthe\n\nheaven", "God created the heaven"},
+		{"trims", "\n  And the earth  ", "And the earth"},
+		{"carriage return and form feed", "light\r\nand\fdarkness", "light and darkness"},
+		{"named entities", "Jacob &amp; Esau &lt;Edom&gt; &quot;red&quot; &apos;", `Jacob & Esau <Edom> "red" '`},
+		{"nbsp decodes then collapses", "waters&nbsp; &#160;under", "waters under"},
+		{"nbsp at the edge is trimmed", "&nbsp;Selah&#160;", "Selah"},
+		{"numeric entity", "LORD&#39;s", "LORD's"},
+		{"numeric tab collapses", "seed&#9; yielding", "seed yielding"},
+		{"nested reference", "LORD&#38;#39;s", "LORD's"},
+		{"unknown entity preserved", "&hellip; &copy;", "&hellip; &copy;"},
+		{"non-ASCII reference preserved", "&#8212;", "&#8212;"},
+		{"bare ampersand", "salt & light", "salt & light"},
+		{"literal no-break space preserved", "waters\u00a0under ", "waters\u00a0under"},
+		{"literal no-break space at the edge", "\u00a0Selah", "\u00a0Selah"},
+		{"unicode space preserved", "em\u2003space", "em\u2003space"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.in); got != tt.want {
+				t.Errorf("Text(%q) = %q; want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToken(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"In the beginning ", "In the beginning "},
+		{"\n  God ", " God "},
+		{" &amp;\t", " & "},
+		{"&nbsp;&nbsp;", " "},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Token(tt.in); got != tt.want {
+			t.Errorf("Token(%q) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// The verifier flattens tokens with Text; the pieces of a verse normalized with Token must
+// flatten to the verse normalized whole, however the raw text was split
+func TestTokensFlattenToText(t *testing.T) {
+	tests := [][]string{
+		{"\n And God said, ", "Let there be ", "light", ": and there was light.\n"},
+		{"the ", "LORD", " &amp;\t ", "his ", "anointed"},
+		{"waters&nbsp;", " &#160;", "under"},
+		{"  ", "Selah", "  "},
+	}
+
+	for _, pieces := range tests {
+		var tokens strings.Builder
+		for _, piece := range pieces {
+			tokens.WriteString(Token(piece))
+		}
+		whole := Text(strings.Join(pieces, ""))
+		if got := Text(tokens.String()); got != whole {
+			t.Errorf("tokens %q flatten to %q; the whole text normalizes to %q", pieces, got, whole)
+		}
+	}
+}
+
+func TestCollapseReturnsCleanInput(t *testing.T) {
+	s := "already clean text"
+	if got := Collapse(s); got != s {
+		t.Errorf("Collapse(%q) = %q", s, got)
+	}
+	if got := Collapse(" a  b "); got != " a b " {
+		t.Errorf("expected Collapse to keep single edge spaces, got %q", got)
+	}
+}
@@ -0,0 +1,181 @@
+package packaging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/release"
+)
+
+// BuildCmd writes a signed release artifact of the canon
+type BuildCmd struct {
+	Version     string `arg:""              help:"Semantic version of the release, such as 1.2.0"`
+	Canon       string `type:"existingdir"  help:"The canon directory containing index/ and books/"                    default:"./canon/kjv"`
+	Out         string `                    help:"Directory to write the artifact to"                                  default:"./dist"`
+	Name        string `                    help:"Name of the release, the prefix of the artifact's file name"         default:"kjv"`
+	Format      string `                    help:"How the artifact carries the canon (canon, or packed for canon.zip)" default:"canon"       enum:"canon,packed"`
+	Key         string `type:"existingfile" help:"PEM ed25519 private key to sign the release with"                                          required:""`
+	ForceUnlock bool   `                    help:"Take the lock on --canon even from a run holding it, such as a hung one"`
+}
+
+// VerifyCmd checks the signature and checksums of a release artifact
+type VerifyCmd struct {
+	Artifact string `arg:"" type:"existingfile" help:"Release artifact to verify"`
+	Key      string `       type:"existingfile" help:"PEM ed25519 public key the release should be signed with" required:""`
+}
+
+// KeygenCmd writes a new release signing key
+type KeygenCmd struct {
+	Out string `help:"Path of the private key to write; the public key is written beside it with .pub added" default:"./release.key"`
+}
+
+// Cmd builds and verifies versioned release artifacts of the canon
+type Cmd struct {
+	Build  BuildCmd  `cmd:"" default:"withargs" help:"Bundle the canon, checksums, and corpus.json into a signed tar.zst artifact"`
+	Verify VerifyCmd `cmd:""                    help:"Check the signature and checksums of a release artifact"`
+	Keygen KeygenCmd `cmd:""                    help:"Write a new ed25519 key pair for signing releases"`
+}
+
+func (c *BuildCmd) Run(ctx context.Context) error {
+	data, err := os.ReadFile(c.Key) // nolint: gosec
+	if err != nil {
+		util.StopProgress(ctx)
+		return fmt.Errorf("failed to read key: %w", err)
+	}
+	key, err := release.ParsePrivateKey(data)
+	if err != nil {
+		util.StopProgress(ctx)
+		return err
+	}
+	created, err := buildTime()
+	if err != nil {
+		util.StopProgress(ctx)
+		return err
+	}
+
+	lock, err := util.AcquireLock(c.Canon, "package", c.ForceUnlock)
+	if err != nil {
+		util.StopProgress(ctx)
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	var buf bytes.Buffer
+	manifest, err := release.Build(&buf, os.DirFS(c.Canon), release.Options{
+		Name:    c.Name,
+		Version: c.Version,
+		Format:  release.Format(c.Format),
+		Created: created,
+		Key:     key,
+	})
+	path := ""
+	if err == nil {
+		path = filepath.Join(c.Out, release.FileName(manifest.Name, manifest.Version))
+		if err = os.MkdirAll(c.Out, 0750); err == nil {
+			err = atomicfile.WriteFile(path, buf.Bytes(), 0600)
+		}
+	}
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Release: %s %s (%s)\n", manifest.Name, manifest.Version, manifest.Format)
+	fmt.Printf("Books: %d\n", manifest.Books)
+	fmt.Printf("Chapters: %d\n", manifest.Chapters)
+	fmt.Printf("Files: %d\n", manifest.Files)
+	fmt.Printf("Signed By: %s\n", manifest.KeyID)
+	fmt.Printf("Size: %d bytes\n", buf.Len())
+	fmt.Printf("Output: %s\n", path)
+	fmt.Printf("========================================\n")
+	return nil
+}
+
+// buildTime returns the time to record in a release: $SOURCE_DATE_EPOCH if set, so that builds
+// can be reproduced, or now
+func buildTime() (time.Time, error) {
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		return time.Now().UTC().Truncate(time.Second), nil
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q", epoch)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+func (c *VerifyCmd) Run(ctx context.Context) error {
+	util.StopProgress(ctx)
+
+	data, err := os.ReadFile(c.Key) // nolint: gosec
+	if err != nil {
+		return fmt.Errorf("failed to read key: %w", err)
+	}
+	key, err := release.ParsePublicKey(data)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(c.Artifact) // nolint: gosec
+	if err != nil {
+		return fmt.Errorf("failed to open artifact: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	manifest, err := release.Verify(f, key)
+	if err != nil {
+		return util.Exit(util.ExitErrors, err)
+	}
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Release: %s %s (%s)\n", manifest.Name, manifest.Version, manifest.Format)
+	if manifest.Created != "" {
+		fmt.Printf("Created: %s\n", manifest.Created)
+	}
+	fmt.Printf("Books: %d\n", manifest.Books)
+	fmt.Printf("Chapters: %d\n", manifest.Chapters)
+	fmt.Printf("Files: %d\n", manifest.Files)
+	fmt.Printf("Signed By: %s\n", manifest.KeyID)
+	fmt.Printf("Signature and checksums OK\n")
+	fmt.Printf("========================================\n")
+	return nil
+}
+
+func (c *KeygenCmd) Run(ctx context.Context) error {
+	util.StopProgress(ctx)
+
+	for _, path := range []string{c.Out, c.Out + ".pub"} {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", path)
+		}
+	}
+	private, public, err := release.GenerateKey()
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.WriteFile(c.Out, private, 0600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+	if err := atomicfile.WriteFile(c.Out+".pub", public, 0600); err != nil {
+		return fmt.Errorf("failed to write public key: %w", err)
+	}
+
+	pub, err := release.ParsePublicKey(public)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("========================================\n")
+	fmt.Printf("Key ID: %s\n", release.KeyID(pub))
+	fmt.Printf("Private Key: %s\n", c.Out)
+	fmt.Printf("Public Key: %s\n", c.Out+".pub")
+	fmt.Printf("========================================\n")
+	return nil
+}
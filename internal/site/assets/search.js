@@ -0,0 +1,80 @@
+// Client-side search over search-index.json built by kjv-site.
+// A query that looks like a reference ("John 3:16", "1 Sam 3") jumps straight to the chapter;
+// anything else is matched case-insensitively against verse text, all words required.
+// A permalink (?p=john/3/16-18, the slug kjvcorpus writes) jumps to the passage's first verse.
+(function () {
+  var MAX_RESULTS = 200;
+  var status = document.getElementById("status");
+  var results = document.getElementById("results");
+  var params = new URLSearchParams(window.location.search);
+  var query = (params.get("q") || "").trim();
+  var passage = (params.get("p") || "").replace(/^\/+|\/+$/g, "").toLowerCase();
+
+  function normalize(s) {
+    return s.trim().toLowerCase().replace(/\./g, "").replace(/\s+/g, " ");
+  }
+
+  function link(book, chapter, verse) {
+    return book.slug + "/" + chapter + ".html" + (verse ? "#v" + verse : "");
+  }
+
+  function item(href, label, text) {
+    var li = document.createElement("li");
+    var a = document.createElement("a");
+    a.href = href;
+    a.textContent = label;
+    li.appendChild(a);
+    if (text) {
+      li.appendChild(document.createTextNode(" " + text));
+    }
+    results.appendChild(li);
+  }
+
+  if (!query && !passage) {
+    status.textContent = "Enter a reference or words to search for.";
+    return;
+  }
+
+  fetch("search-index.json")
+    .then(function (resp) { return resp.json(); })
+    .then(function (index) {
+      if (passage) {
+        var parts = passage.split("/");
+        for (var b = 0; b < index.books.length; b++) {
+          if (index.books[b].ref === parts[0] && /^\d+$/.test(parts[1] || "")) {
+            window.location.replace(link(index.books[b], parts[1], parseInt(parts[2], 10) || 0));
+            return;
+          }
+        }
+        status.textContent = "No passage “" + passage + "” in this site.";
+        return;
+      }
+
+      var ref = query.match(/^(.+?)\s*(\d+)(?::(\d+))?$/);
+      if (ref) {
+        var book = index.books[index.aliases[normalize(ref[1])]];
+        if (book) {
+          window.location.replace(link(book, ref[2], ref[3]));
+          return;
+        }
+      }
+
+      var words = normalize(query).split(" ");
+      var count = 0;
+      for (var i = 0; i < index.verses.length && count < MAX_RESULTS; i++) {
+        var v = index.verses[i];
+        var text = v[3].toLowerCase();
+        if (words.every(function (w) { return text.indexOf(w) !== -1; })) {
+          var b = index.books[v[0]];
+          item(link(b, v[1], v[2]), b.name + " " + v[1] + ":" + v[2], v[3]);
+          count++;
+        }
+      }
+      status.textContent = count === MAX_RESULTS
+        ? "Showing the first " + MAX_RESULTS + " verses matching “" + query + "”"
+        : count + " verse(s) matching “" + query + "”";
+    })
+    .catch(function (err) {
+      status.textContent = "Failed to load search index: " + err;
+    });
+})();
@@ -0,0 +1,96 @@
+package site
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+type BuildCmd struct {
+	Canon string `type:"existingdir" help:"The canon directory containing index/ and books/" default:"./canon/kjv"`
+	Out   string `                   help:"Directory to write the static site to"             default:"./site"`
+	Title string `                   help:"Site title shown on every page"                    default:"King James Version"`
+}
+
+type FeedCmd struct {
+	Canon   string `type:"existingdir" help:"The canon directory containing index/ and books/"                          default:"./canon/kjv"`
+	Out     string `                   help:"Directory to write feed.xml, atom.xml, and feed.json to"                   default:"./site"`
+	Title   string `                   help:"Feed title"                                                                default:"KJV Daily Reading"`
+	BaseURL string `                   help:"Absolute URL the site is published at, used for item links" required:""`
+	Plan    string `type:"existingfile" help:"Reading plan file with one day's references per line (default: whole canon in a year)"`
+	Order   string `                   help:"Order of the whole-canon plan (canonical or chronological)"                default:"canonical" enum:"canonical,chronological"`
+	Start   string `                   help:"Date of day 1 of the plan (YYYY-MM-DD)"                                    default:"2026-01-01"`
+	Date    string `                   help:"Date of the newest item (YYYY-MM-DD, default: today)"`
+	Days    int    `                   help:"Number of days to include in the feed"                                     default:"7"`
+}
+
+// Cmd renders the canon as a static site and writes reading feeds
+type Cmd struct {
+	Build BuildCmd `cmd:"" default:"withargs" help:"Render the canon into a static HTML site"`
+	Feed  FeedCmd  `cmd:""                    help:"Write RSS, Atom, and JSON feeds of daily reading portions"`
+}
+
+func (c *BuildCmd) Run(ctx context.Context) error {
+	gen, err := NewGenerator(c.Canon, c.Out, c.Title)
+	if err != nil {
+		util.StopProgress(ctx)
+		return err
+	}
+
+	stats, err := gen.Generate()
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Books: %d\n", stats.Books)
+	fmt.Printf("Chapter Pages: %d\n", stats.Chapters)
+	fmt.Printf("Verses Indexed: %d\n", stats.Verses)
+	fmt.Printf("Output: %s\n", c.Out)
+	fmt.Printf("========================================\n")
+	return nil
+}
+
+func (c *FeedCmd) Run(ctx context.Context) error {
+	start, err := time.Parse(time.DateOnly, c.Start)
+	if err != nil {
+		util.StopProgress(ctx)
+		return fmt.Errorf("invalid --start date: %w", err)
+	}
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if c.Date != "" {
+		if date, err = time.Parse(time.DateOnly, c.Date); err != nil {
+			util.StopProgress(ctx)
+			return fmt.Errorf("invalid --date: %w", err)
+		}
+	}
+
+	gen, err := NewGenerator(c.Canon, c.Out, c.Title)
+	if err != nil {
+		util.StopProgress(ctx)
+		return err
+	}
+
+	items, err := gen.WriteFeeds(FeedOptions{
+		Title:   c.Title,
+		BaseURL: c.BaseURL,
+		Plan:    c.Plan,
+		Order:   PlanOrder(c.Order),
+		Start:   start,
+		Date:    date,
+		Days:    c.Days,
+	})
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Feed Items: %d\n", items)
+	fmt.Printf("Output: %s\n", c.Out)
+	fmt.Printf("========================================\n")
+	return nil
+}
@@ -0,0 +1,366 @@
+package site
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+)
+
+// defaultPlanDays is the length of the built-in plan that reads the whole canon in order
+const defaultPlanDays = 365
+
+// PlanOrder is the order the built-in plan reads the canon in
+type PlanOrder string
+
+const (
+	OrderCanonical     PlanOrder = "canonical"     // book by book, as in books.json
+	OrderChronological PlanOrder = "chronological" // as in index/chronological.json
+)
+
+// FeedOptions configures WriteFeeds
+type FeedOptions struct {
+	Title   string
+	BaseURL string    // absolute URL of the published site
+	Plan    string    // reading plan file; empty for the built-in whole-canon plan
+	Order   PlanOrder // order of the built-in plan; empty for canonical
+	Start   time.Time // date of day 1 of the plan
+	Date    time.Time // date of the newest item
+	Days    int       // number of daily items, counting back from Date
+}
+
+// readingPlan holds one portion per day, each a list of references
+type readingPlan [][]*bibleref.BibleRef
+
+// feedItem is a single day's portion, shared by all feed formats
+type feedItem struct {
+	ID    string
+	Title string
+	URL   string
+	HTML  string
+	Text  string
+	Date  time.Time
+}
+
+// WriteFeeds writes feed.xml (RSS 2.0), atom.xml (Atom), and feed.json (JSON Feed 1.1) with one item
+// per day for the opts.Days days up to and including opts.Date, and returns the number of items
+func (g *Generator) WriteFeeds(opts FeedOptions) (int, error) {
+	baseURL := strings.TrimSuffix(opts.BaseURL, "/")
+
+	var plan readingPlan
+	var err error
+	if opts.Plan != "" {
+		plan, err = g.loadPlan(opts.Plan)
+	} else {
+		plan, err = g.canonPlan(defaultPlanDays, opts.Order)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(plan) == 0 {
+		return 0, fmt.Errorf("reading plan has no days")
+	}
+
+	var items []feedItem
+	for i := 0; i < opts.Days; i++ {
+		date := opts.Date.AddDate(0, 0, -i)
+		day := int(date.Sub(opts.Start).Hours() / 24)
+		if day < 0 {
+			break
+		}
+
+		item, err := g.feedItem(baseURL, date, plan[day%len(plan)])
+		if err != nil {
+			return 0, fmt.Errorf("failed to resolve reading for %s: %w", date.Format(time.DateOnly), err)
+		}
+		items = append(items, item)
+	}
+
+	if err := os.MkdirAll(g.out, 0750); err != nil {
+		return 0, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	writers := map[string]func(string, string, []feedItem) ([]byte, error){
+		"feed.xml":  renderRSS,
+		"atom.xml":  renderAtom,
+		"feed.json": renderJSONFeed,
+	}
+	for name, render := range writers {
+		data, err := render(opts.Title, baseURL, items)
+		if err != nil {
+			return 0, fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		if err := atomicfile.WriteFile(filepath.Join(g.out, name), data, 0600); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	return len(items), nil
+}
+
+// loadPlan reads a reading plan file: one day per line, references separated by ";"
+// Blank lines and lines starting with "#" are ignored
+func (g *Generator) loadPlan(path string) (readingPlan, error) {
+	file, err := os.Open(path) // nolint: gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reading plan: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Printf("Error closing reading plan: %v\n", err)
+		}
+	}()
+
+	var plan readingPlan
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var portion []*bibleref.BibleRef
+		for _, part := range strings.Split(line, ";") {
+			ref, err := bibleref.Parse(strings.TrimSpace(part), g.corpus.Books)
+			if err != nil {
+				return nil, fmt.Errorf("reading plan line %d: %w", lineNum, err)
+			}
+			portion = append(portion, ref)
+		}
+		plan = append(plan, portion)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading plan: %w", err)
+	}
+	return plan, nil
+}
+
+// canonPlan spreads every chapter of the canon, in the given order, evenly over days
+func (g *Generator) canonPlan(days int, order PlanOrder) (readingPlan, error) {
+	var chapters []*bibleref.BibleRef
+	switch order {
+	case OrderCanonical, "":
+		pages, err := g.bookPages(g.corpus.BooksIn())
+		if err != nil {
+			return nil, err
+		}
+		for _, page := range pages {
+			for _, chapter := range page.Chapters {
+				chapters = append(chapters, &bibleref.BibleRef{OSIS: page.OSIS, Chapter: chapter})
+			}
+		}
+	case OrderChronological:
+		var err error
+		if chapters, err = g.corpus.ChronologicalOrder(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown plan order %q", order)
+	}
+
+	if len(chapters) < days {
+		days = len(chapters)
+	}
+	plan := make(readingPlan, days)
+	for day := range plan {
+		plan[day] = chapters[day*len(chapters)/days : (day+1)*len(chapters)/days]
+	}
+	return plan, nil
+}
+
+// feedItem resolves a day's portion into a feed item linking to the site's chapter page
+func (g *Generator) feedItem(baseURL string, date time.Time, portion []*bibleref.BibleRef) (feedItem, error) {
+	var titles []string
+	var html, text strings.Builder
+
+	for _, ref := range portion {
+		resolved, err := g.corpus.Resolve(ref)
+		if err != nil {
+			return feedItem{}, err
+		}
+
+		title := ref.Format(bibleref.FormatHuman, g.corpus.Books)
+		titles = append(titles, title)
+
+		fmt.Fprintf(&html, "<h3>%s</h3>\n<p>", template.HTMLEscapeString(title))
+		for i, verse := range resolved.Verses {
+			if i > 0 {
+				html.WriteString(" ")
+				text.WriteString(" ")
+			}
+			// Footnote links only work on the chapter page, so they are left out of feed content
+			fmt.Fprintf(&html, "<sup>%d</sup>%s", verse.V, verseHTML(verse, nil))
+			fmt.Fprintf(&text, "%d %s", verse.V, strings.TrimSpace(verse.Plain))
+		}
+		html.WriteString("</p>\n")
+		text.WriteString("\n")
+	}
+
+	first := portion[0]
+	url := fmt.Sprintf("%s/%s/%d.html", baseURL, slug(first.OSIS), first.Chapter)
+	if first.Verse != nil {
+		url += fmt.Sprintf("#v%d", first.Verse.StartVerse)
+	}
+
+	return feedItem{
+		ID:    fmt.Sprintf("%s/feed/%s", baseURL, date.Format(time.DateOnly)),
+		Title: strings.Join(titles, "; "),
+		URL:   url,
+		HTML:  html.String(),
+		Text:  strings.TrimSpace(text.String()),
+		Date:  date,
+	}, nil
+}
+
+// renderRSS renders items as an RSS 2.0 feed
+func renderRSS(title, baseURL string, items []feedItem) ([]byte, error) {
+	type rssItem struct {
+		Title string `xml:"title"`
+		Link  string `xml:"link"`
+		GUID  struct {
+			IsPermaLink string `xml:"isPermaLink,attr"`
+			Value       string `xml:",chardata"`
+		} `xml:"guid"`
+		PubDate     string `xml:"pubDate"`
+		Description string `xml:"description"`
+	}
+	type rss struct {
+		XMLName xml.Name `xml:"rss"`
+		Version string   `xml:"version,attr"`
+		Channel struct {
+			Title         string    `xml:"title"`
+			Link          string    `xml:"link"`
+			Description   string    `xml:"description"`
+			LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+			Items         []rssItem `xml:"item"`
+		} `xml:"channel"`
+	}
+
+	feed := rss{Version: "2.0"}
+	feed.Channel.Title = title
+	feed.Channel.Link = baseURL + "/"
+	feed.Channel.Description = title
+	if len(items) > 0 {
+		feed.Channel.LastBuildDate = items[0].Date.Format(time.RFC1123Z)
+	}
+	for _, item := range items {
+		ri := rssItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			PubDate:     item.Date.Format(time.RFC1123Z),
+			Description: item.HTML,
+		}
+		ri.GUID.IsPermaLink = "false"
+		ri.GUID.Value = item.ID
+		feed.Channel.Items = append(feed.Channel.Items, ri)
+	}
+
+	return marshalXML(feed)
+}
+
+// renderAtom renders items as an Atom feed
+func renderAtom(title, baseURL string, items []feedItem) ([]byte, error) {
+	type link struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr,omitempty"`
+	}
+	type content struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	}
+	type entry struct {
+		Title   string  `xml:"title"`
+		ID      string  `xml:"id"`
+		Updated string  `xml:"updated"`
+		Link    link    `xml:"link"`
+		Content content `xml:"content"`
+	}
+	type atom struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Title   string   `xml:"title"`
+		ID      string   `xml:"id"`
+		Updated string   `xml:"updated"`
+		Links   []link   `xml:"link"`
+		Entries []entry  `xml:"entry"`
+	}
+
+	feed := atom{
+		Title: title,
+		ID:    baseURL + "/atom.xml",
+		Links: []link{{Href: baseURL + "/"}, {Href: baseURL + "/atom.xml", Rel: "self"}},
+	}
+	if len(items) > 0 {
+		feed.Updated = items[0].Date.Format(time.RFC3339)
+	}
+	for _, item := range items {
+		feed.Entries = append(feed.Entries, entry{
+			Title:   item.Title,
+			ID:      item.ID,
+			Updated: item.Date.Format(time.RFC3339),
+			Link:    link{Href: item.URL},
+			Content: content{Type: "html", Value: item.HTML},
+		})
+	}
+
+	return marshalXML(feed)
+}
+
+// renderJSONFeed renders items as a JSON Feed 1.1 document
+func renderJSONFeed(title, baseURL string, items []feedItem) ([]byte, error) {
+	type jsonItem struct {
+		ID            string `json:"id"`
+		URL           string `json:"url"`
+		Title         string `json:"title"`
+		ContentHTML   string `json:"content_html"`
+		ContentText   string `json:"content_text"`
+		DatePublished string `json:"date_published"`
+	}
+	type jsonFeed struct {
+		Version     string     `json:"version"`
+		Title       string     `json:"title"`
+		HomePageURL string     `json:"home_page_url"`
+		FeedURL     string     `json:"feed_url"`
+		Items       []jsonItem `json:"items"`
+	}
+
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       title,
+		HomePageURL: baseURL + "/",
+		FeedURL:     baseURL + "/feed.json",
+		Items:       []jsonItem{},
+	}
+	for _, item := range items {
+		feed.Items = append(feed.Items, jsonItem{
+			ID:            item.ID,
+			URL:           item.URL,
+			Title:         item.Title,
+			ContentHTML:   item.HTML,
+			ContentText:   item.Text,
+			DatePublished: item.Date.Format(time.RFC3339),
+		})
+	}
+
+	return json.MarshalIndent(feed, "", "  ")
+}
+
+// marshalXML marshals v as an indented XML document with a declaration
+func marshalXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
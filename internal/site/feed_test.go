@@ -0,0 +1,129 @@
+package site
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteFeeds(t *testing.T) {
+	out := t.TempDir()
+	planPath := filepath.Join(t.TempDir(), "plan.txt")
+	plan := "# verses for the week\nJohn 3:16\nPs 23; Prov 3:5-6\n"
+	if err := os.WriteFile(planPath, []byte(plan), 0600); err != nil {
+		t.Fatalf("failed to write plan: %v", err)
+	}
+
+	gen, err := NewGenerator(findCanon(t), out, "KJV")
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	items, err := gen.WriteFeeds(FeedOptions{
+		Title:   "Daily",
+		BaseURL: "https://example.org/kjv/",
+		Plan:    planPath,
+		Start:   start,
+		Date:    start.AddDate(0, 0, 2),
+		Days:    7,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items != 3 {
+		t.Fatalf("expected 3 items (feed stops at the plan start), got %d", items)
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, "feed.json")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read feed.json: %v", err)
+	}
+	var feed struct {
+		Items []struct {
+			ID          string `json:"id"`
+			URL         string `json:"url"`
+			Title       string `json:"title"`
+			ContentText string `json:"content_text"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("failed to parse feed.json: %v", err)
+	}
+
+	// Newest first: day 3 wraps around to the first line of the plan
+	if feed.Items[0].URL != "https://example.org/kjv/John/3.html#v16" {
+		t.Errorf("unexpected item URL: %s", feed.Items[0].URL)
+	}
+	if !strings.HasPrefix(feed.Items[0].ContentText, "16 ¶ For God so loved the world") {
+		t.Errorf("unexpected item text: %s", feed.Items[0].ContentText)
+	}
+	if parts := strings.Split(feed.Items[1].Title, "; "); len(parts) != 2 || !strings.HasSuffix(parts[1], " 3:5–6") {
+		t.Errorf("unexpected multi-reference title: %s", feed.Items[1].Title)
+	}
+	if feed.Items[2].ID != "https://example.org/kjv/feed/2026-01-01" {
+		t.Errorf("unexpected item id: %s", feed.Items[2].ID)
+	}
+
+	for _, name := range []string{"feed.xml", "atom.xml"} {
+		data, err := os.ReadFile(filepath.Join(out, name)) // nolint: gosec
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		var doc struct{}
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			t.Errorf("%s is not well-formed XML: %v", name, err)
+		}
+	}
+}
+
+func TestCanonPlan(t *testing.T) {
+	gen, err := NewGenerator(findCanon(t), t.TempDir(), "KJV")
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+
+	plan, err := gen.canonPlan(365, OrderCanonical)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plan) != 365 {
+		t.Fatalf("expected 365 days, got %d", len(plan))
+	}
+
+	var chapters int
+	for _, portion := range plan {
+		if len(portion) == 0 {
+			t.Fatalf("expected every day to have a reading")
+		}
+		chapters += len(portion)
+	}
+	if first := plan[0][0]; first.OSIS != "Gen" || first.Chapter != 1 {
+		t.Errorf("expected plan to start at Gen 1, got %s", first)
+	}
+	if chapters < 1300 {
+		t.Errorf("expected the plan to cover the whole canon, got %d chapters", chapters)
+	}
+
+	chronological, err := gen.canonPlan(365, OrderChronological)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var order []string
+	for _, portion := range chronological {
+		for _, ref := range portion {
+			order = append(order, ref.String())
+		}
+	}
+	if len(order) != chapters || order[0] != "Gen 1" || order[11] != "Job 1" {
+		t.Errorf("expected the chronological plan to read the same %d chapters from Gen 1 with Job after Gen 11, got %d", chapters, len(order))
+	}
+
+	if _, err := gen.canonPlan(365, "alphabetical"); err == nil {
+		t.Error("expected an unknown order to fail")
+	}
+}
@@ -0,0 +1,368 @@
+package site
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+//go:embed assets/*
+var assetFS embed.FS
+
+// Generator renders the canon into a static HTML site
+type Generator struct {
+	corpus *kjvcorpus.Corpus
+	out    string
+	title  string
+	pages  map[string]*template.Template
+}
+
+// SiteStats summarises a generated site
+type SiteStats struct {
+	Books    int
+	Chapters int
+	Verses   int
+}
+
+// bookPage is a book with the chapters that exist in the canon
+type bookPage struct {
+	OSIS     string
+	Name     string
+	Slug     string
+	Chapters []int
+}
+
+// chapterLink points at a chapter page relative to the site root
+type chapterLink struct {
+	Label string
+	URL   string
+}
+
+// versePage is a verse rendered for a chapter page
+type versePage struct {
+	V         int
+	HTML      template.HTML
+	Paragraph bool
+}
+
+// searchIndex is written to search-index.json and loaded by assets/search.js
+type searchIndex struct {
+	Books   []searchBook     `json:"books"`
+	Aliases map[string]int   `json:"aliases"` // normalized alias -> index into Books
+	Verses  [][4]interface{} `json:"verses"`  // [book index, chapter, verse, plain text]
+}
+
+type searchBook struct {
+	OSIS string `json:"osis"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	Ref  string `json:"ref"` // the book's segment of reference slugs, such as "1sam"
+}
+
+// NewGenerator opens the canon and parses the page templates
+func NewGenerator(canonDir, out, title string) (*Generator, error) {
+	corpus, err := kjvcorpus.Open(canonDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	pages := make(map[string]*template.Template)
+	for _, page := range []string{"index.html", "book.html", "chapter.html", "search.html"} {
+		tmpl, err := template.ParseFS(templateFS, "templates/base.html", "templates/"+page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", page, err)
+		}
+		pages[page] = tmpl
+	}
+
+	return &Generator{corpus: corpus, out: out, title: title, pages: pages}, nil
+}
+
+// Generate writes the index, book, chapter, and search pages, the search index, and static assets
+func (g *Generator) Generate() (SiteStats, error) {
+	var stats SiteStats
+
+	if err := os.MkdirAll(g.out, 0750); err != nil {
+		return stats, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	books := g.corpus.BooksIn()
+	pages, err := g.bookPages(books)
+	if err != nil {
+		return stats, err
+	}
+
+	index := searchIndex{Aliases: make(map[string]int)}
+	bookIndex := make(map[string]int)
+	for i, page := range pages {
+		bookIndex[page.OSIS] = i
+		index.Books = append(index.Books, searchBook{OSIS: page.OSIS, Name: page.Name, Slug: page.Slug, Ref: kjvcorpus.BookSlug(page.OSIS)})
+
+		for _, chapter := range page.Chapters {
+			resolved, err := g.corpus.Resolve(&bibleref.BibleRef{OSIS: page.OSIS, Chapter: chapter})
+			if err != nil {
+				return stats, err
+			}
+			for _, verse := range resolved.Verses {
+				index.Verses = append(index.Verses, [4]interface{}{i, chapter, verse.V, verse.Plain})
+			}
+		}
+	}
+
+	for alias, osis := range g.corpus.Books.ByAlias {
+		if i, ok := bookIndex[osis]; ok {
+			index.Aliases[alias] = i
+		}
+	}
+
+	if err := g.writeIndex(pages); err != nil {
+		return stats, err
+	}
+
+	for i, page := range pages {
+		if err := g.writeBook(page); err != nil {
+			return stats, err
+		}
+		for j, chapter := range page.Chapters {
+			prev, next := neighbours(pages, i, j)
+			verses, err := g.writeChapter(page, chapter, prev, next)
+			if err != nil {
+				return stats, err
+			}
+			stats.Chapters++
+			stats.Verses += verses
+		}
+	}
+	stats.Books = len(pages)
+
+	if err := g.render("search.html", "search.html", map[string]interface{}{
+		"Site": g.title, "Title": "Search", "Root": "",
+	}); err != nil {
+		return stats, err
+	}
+
+	if err := g.writeSearchIndex(index); err != nil {
+		return stats, err
+	}
+
+	return stats, g.copyAssets()
+}
+
+// bookPages returns the books that have at least one chapter in the canon, with those chapters
+func (g *Generator) bookPages(books []bibleref.Book) ([]bookPage, error) {
+	var pages []bookPage
+	for _, book := range books {
+		page := bookPage{OSIS: book.OSIS, Name: book.Name, Slug: slug(book.OSIS)}
+		for chapter := 1; chapter <= book.Chapters; chapter++ {
+			_, err := g.corpus.Resolve(&bibleref.BibleRef{OSIS: book.OSIS, Chapter: chapter})
+			if errors.Is(err, kjvcorpus.ErrChapterNotFound) {
+				// Books such as Additions to Esther only carry some of their chapters
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			page.Chapters = append(page.Chapters, chapter)
+		}
+
+		if len(page.Chapters) > 0 {
+			pages = append(pages, page)
+		}
+	}
+	return pages, nil
+}
+
+// writeIndex renders index.html grouped by testament
+func (g *Generator) writeIndex(pages []bookPage) error {
+	type section struct {
+		Name  string
+		Books []bookPage
+	}
+	var sections []section
+	for _, t := range testament.All {
+		in := make(map[string]bool)
+		for _, book := range g.corpus.BooksIn(t) {
+			in[book.OSIS] = true
+		}
+
+		s := section{Name: t.Name()}
+		for _, page := range pages {
+			if in[page.OSIS] {
+				s.Books = append(s.Books, page)
+			}
+		}
+		if len(s.Books) > 0 {
+			sections = append(sections, s)
+		}
+	}
+
+	return g.render("index.html", "index.html", map[string]interface{}{
+		"Site": g.title, "Title": g.title, "Root": "", "Sections": sections,
+	})
+}
+
+// writeBook renders {slug}/index.html with the book's introduction, if any, and its chapters
+func (g *Generator) writeBook(page bookPage) error {
+	var intro []string
+	bookIntro, err := g.corpus.BookIntro(page.OSIS)
+	switch {
+	case err == nil:
+		intro = bookIntro.Paragraphs
+	case !errors.Is(err, kjvcorpus.ErrIntroNotFound):
+		return err
+	}
+
+	return g.render("book.html", filepath.Join(page.Slug, "index.html"), map[string]interface{}{
+		"Site": g.title, "Title": page.Name, "Root": "../", "Book": page, "Intro": intro,
+	})
+}
+
+// writeChapter renders {slug}/{chapter}.html and returns the number of verses written
+func (g *Generator) writeChapter(page bookPage, chapter int, prev, next *chapterLink) (int, error) {
+	resolved, err := g.corpus.Resolve(&bibleref.BibleRef{OSIS: page.OSIS, Chapter: chapter})
+	if err != nil {
+		return 0, err
+	}
+
+	verses := make([]versePage, len(resolved.Verses))
+	for i, verse := range resolved.Verses {
+		verses[i] = versePage{
+			V:         verse.V,
+			HTML:      verseHTML(verse, resolved.Footnotes),
+			Paragraph: i > 0 && len(verse.Tokens) > 0 && strings.HasPrefix(verse.Tokens[0].Text, "¶"),
+		}
+	}
+
+	err = g.render("chapter.html", filepath.Join(page.Slug, fmt.Sprintf("%d.html", chapter)), map[string]interface{}{
+		"Site":      g.title,
+		"Title":     fmt.Sprintf("%s %d", page.Name, chapter),
+		"Root":      "../",
+		"Book":      page,
+		"Chapter":   chapter,
+		"Verses":    verses,
+		"Footnotes": resolved.Footnotes,
+		"Prev":      prev,
+		"Next":      next,
+	})
+	return len(verses), err
+}
+
+// render executes a page template into a file beneath the output directory
+func (g *Generator) render(page, path string, data interface{}) error {
+	fullPath := filepath.Join(g.out, path)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	var b strings.Builder
+	if err := g.pages[page].ExecuteTemplate(&b, "base", data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", path, err)
+	}
+
+	if err := atomicfile.WriteFile(fullPath, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeSearchIndex writes search-index.json
+func (g *Generator) writeSearchIndex(index searchIndex) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %w", err)
+	}
+	if err := atomicfile.WriteFile(filepath.Join(g.out, "search-index.json"), data, 0600); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	return nil
+}
+
+// copyAssets copies the embedded stylesheet and scripts into the output directory
+func (g *Generator) copyAssets() error {
+	return fs.WalkDir(assetFS, "assets", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := assetFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := atomicfile.WriteFile(filepath.Join(g.out, filepath.Base(path)), data, 0600); err != nil {
+			return fmt.Errorf("failed to write asset %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// neighbours returns links to the chapters before and after chapter j of book i
+func neighbours(pages []bookPage, i, j int) (*chapterLink, *chapterLink) {
+	link := func(page bookPage, chapter int) *chapterLink {
+		return &chapterLink{
+			Label: fmt.Sprintf("%s %d", page.Name, chapter),
+			URL:   fmt.Sprintf("%s/%d.html", page.Slug, chapter),
+		}
+	}
+
+	var prev, next *chapterLink
+	switch {
+	case j > 0:
+		prev = link(pages[i], pages[i].Chapters[j-1])
+	case i > 0:
+		prevBook := pages[i-1]
+		prev = link(prevBook, prevBook.Chapters[len(prevBook.Chapters)-1])
+	}
+	switch {
+	case j < len(pages[i].Chapters)-1:
+		next = link(pages[i], pages[i].Chapters[j+1])
+	case i < len(pages)-1:
+		next = link(pages[i+1], pages[i+1].Chapters[0])
+	}
+	return prev, next
+}
+
+// verseHTML renders a verse's tokens with added words in italics, the divine name in small caps,
+// and links to the verse's footnotes
+func verseHTML(verse model.Verse, footnotes []model.Footnote) template.HTML {
+	var b strings.Builder
+	for _, token := range verse.Tokens {
+		switch {
+		case token.Add != "":
+			fmt.Fprintf(&b, `<em class="add">%s</em>`, template.HTMLEscapeString(token.Add))
+		case token.ND != "":
+			fmt.Fprintf(&b, `<span class="nd">%s</span>`, template.HTMLEscapeString(token.ND))
+		default:
+			b.WriteString(template.HTMLEscapeString(token.Text))
+		}
+	}
+
+	for _, fn := range footnotes {
+		if fn.At.V == verse.V {
+			fmt.Fprintf(&b, `<sup class="note"><a href="#%s" id="ref-%s">%s</a></sup>`,
+				template.HTMLEscapeString(fn.ID), template.HTMLEscapeString(fn.ID), template.HTMLEscapeString(fn.Mark))
+		}
+	}
+
+	// #nosec G203 -- every token has been escaped above
+	return template.HTML(strings.TrimSpace(b.String()))
+}
+
+// slug returns the URL path segment for a book, e.g. "1 Sam" -> "1Sam"
+func slug(osis string) string {
+	return strings.ReplaceAll(osis, " ", "")
+}
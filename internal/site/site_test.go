@@ -0,0 +1,84 @@
+package site
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func findCanon(t *testing.T) string {
+	t.Helper()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+
+	// Navigate to project root
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	return filepath.Join(cwd, "canon", "kjv")
+}
+
+func TestGenerate(t *testing.T) {
+	out := t.TempDir()
+	gen, err := NewGenerator(findCanon(t), out, "KJV")
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+
+	stats, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("failed to generate site: %v", err)
+	}
+	if stats.Books != 80 || stats.Chapters < 1300 || stats.Verses < 36000 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	for _, file := range []string{"index.html", "search.html", "search.js", "style.css", "Gen/index.html"} {
+		if _, err := os.Stat(filepath.Join(out, file)); err != nil {
+			t.Errorf("expected %s to be written: %v", file, err)
+		}
+	}
+
+	// Chapter pages link across book boundaries and render markup
+	page, err := os.ReadFile(filepath.Join(out, "Gen", "50.html")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read chapter page: %v", err)
+	}
+	for _, want := range []string{`href="../Gen/49.html"`, `href="../Exod/1.html"`, `<span class="verse" id="v26">`} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("expected Gen 50 page to contain %q", want)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, "search-index.json")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read search index: %v", err)
+	}
+	var index searchIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatalf("failed to parse search index: %v", err)
+	}
+	if len(index.Verses) != stats.Verses {
+		t.Errorf("expected %d indexed verses, got %d", stats.Verses, len(index.Verses))
+	}
+	if i, ok := index.Aliases["genesis"]; !ok || index.Books[i].Slug != "Gen" {
+		t.Errorf("expected alias genesis to resolve to Gen")
+	}
+	// Permalinks (search.html?p=1sam/3/10) find books by their slug segment
+	if i, ok := index.Aliases["1 samuel"]; !ok || index.Books[i].Ref != "1sam" || index.Books[i].Slug != "1Sam" {
+		t.Errorf("expected 1 Samuel to have the slug segment 1sam, got %+v", index.Books[i])
+	}
+}
@@ -0,0 +1,70 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// BookManifestName is the name of the per-book manifest in books/{OSIS}/
+const BookManifestName = "book.json"
+
+// BuildBookManifest lists the chapter files of a book recorded in fileMap, reading each one from
+// canonDir to count its verses and take its checksum, which is of the file as written, compressed
+// or not
+func BuildBookManifest(canonDir, work, osis string, fileMap model.FileMap) (model.BookManifest, error) {
+	manifest := model.BookManifest{Schema: model.BookManifestSchema, Work: work, OSIS: osis, Chapters: []model.BookChapter{}}
+	prefix := path.Join("books", osis) + "/"
+	for _, raw := range fileMap.RawPaths() {
+		output := filepath.ToSlash(fileMap.Files[raw].Output)
+		if !strings.HasPrefix(output, prefix) || strings.Contains(output[len(prefix):], "/") {
+			continue
+		}
+
+		file := filepath.Join(canonDir, filepath.FromSlash(output))
+		data, err := os.ReadFile(file) // nolint: gosec
+		if err != nil {
+			return manifest, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		content, err := compression.Decompress(data)
+		if err != nil {
+			return manifest, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		var chapter model.Chapter
+		if err := json.Unmarshal(content, &chapter); err != nil {
+			return manifest, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		manifest.Chapters = append(manifest.Chapters, model.BookChapter{
+			Chapter: chapter.Chapter,
+			File:    path.Base(output),
+			Verses:  len(chapter.Verses),
+			SHA256:  SHA256Hex(data),
+		})
+	}
+
+	sort.Slice(manifest.Chapters, func(i, j int) bool {
+		return manifest.Chapters[i].Chapter < manifest.Chapters[j].Chapter
+	})
+	return manifest, nil
+}
+
+// WriteBookManifest writes books/{OSIS}/book.json beneath canonDir
+func WriteBookManifest(canonDir string, manifest model.BookManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal book manifest: %w", err)
+	}
+	file := filepath.Join(canonDir, "books", manifest.OSIS, BookManifestName)
+	if err := atomicfile.WriteFile(file, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return nil
+}
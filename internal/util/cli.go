@@ -1,31 +1,68 @@
 package util
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"time"
+
+	"github.com/alecthomas/kong"
 )
 
-func Spinner(text string, stop chan bool) {
-	// A common set of spinner characters
-	frames := []string{"-", "\\", "|", "/"}
-	for {
-		select {
-		case <-stop:
-			return
-		default:
-			for _, frame := range frames {
-				// Use \r to return the cursor to the beginning of the line
-				// and overwrite the previous frame.
-				// The extra spaces are to clear any remaining characters from the previous line.
-				fmt.Printf("\r%s %s... ", frame, text)
-				// Flush the output buffer
-				if err := os.Stdout.Sync(); err != nil {
-					fmt.Printf("\nError flushing output: %v\n", err)
-					return
-				}
-				time.Sleep(100 * time.Millisecond) // Control the spinner speed
-			}
-		}
+// CLIOptions describes a command-line tool run by RunCLI
+type CLIOptions struct {
+	Name        string
+	Description string
+	Config      string            // configuration section passed to Configuration
+	Spinners    map[string]string // selected command (as reported by kong) -> progress text
+	Deprecated  string            // command to suggest instead of this tool, if it is deprecated
+}
+
+// NewParser builds the kong parser for cli with the options every tool shares, so the same
+// command definitions drive parsing, configuration, completions, and man pages.
+func NewParser(cli interface{}, opts CLIOptions, extra ...kong.Option) (*kong.Kong, error) {
+	options := append([]kong.Option{
+		kong.Name(opts.Name),
+		kong.Description(opts.Description),
+		kong.ConfigureHelp(kong.HelpOptions{
+			Compact: true,
+		}),
+		Configuration(opts.Config),
+	}, extra...)
+	return kong.New(cli, options...)
+}
+
+// RunCLI parses the command line into cli, runs the selected command, and exits with the status of
+// the error it returns (see ExitCode). Commands receive a context.Context carrying the command's
+// Progress, if it has one, which they stop with StopProgress before printing their results.
+func RunCLI(cli interface{}, opts CLIOptions) {
+	parser, err := NewParser(cli, opts)
+	if err != nil {
+		panic(err)
+	}
+	kongCtx, err := parser.Parse(os.Args[1:])
+	parser.FatalIfErrorf(err)
+
+	if opts.Deprecated != "" {
+		fmt.Fprintf(os.Stderr, "%s is deprecated and will be removed; use %s instead\n", opts.Name, opts.Deprecated)
+	}
+
+	ctx := context.Background()
+	if text, ok := opts.Spinners[kongCtx.Command()]; ok {
+		ctx = WithProgress(ctx, StartProgress(os.Stderr, text))
+	}
+	kongCtx.BindTo(ctx, (*context.Context)(nil))
+
+	err = kongCtx.Run()
+	StopProgress(ctx)
+
+	code := ExitCode(err)
+	switch code {
+	case ExitClean:
+		return
+	case ExitWarnings:
+		fmt.Printf("\nWarning: %v\n", err)
+	default:
+		fmt.Printf("\nError: %v\n", err)
 	}
+	os.Exit(code)
 }
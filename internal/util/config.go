@@ -0,0 +1,123 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigEnv names an environment variable that points at a configuration file to use instead of
+// kjv.toml or kjv.yaml in the working directory
+const ConfigEnv = "KJV_CONFIG"
+
+// Configuration returns a kong option that reads flag values for tool (e.g. "ingest" or "verify")
+// from kjv.toml, kjv.yaml, or kjv.yml in the working directory, or from $KJV_CONFIG if set.
+// Flags given on the command line always take precedence over the file. Pass an empty tool for
+// kjvsrc, whose subcommands already carry the tool names.
+//
+// A flag is looked up under [tool.command] for subcommands, then [tool], then the top level,
+// so settings shared by several tools can be written once:
+//
+//	work = "KJV"
+//
+//	[ingest]
+//	raw-dir = "raw"
+//	format = ["json", "usfm"]
+//
+//	[verify.canon]
+//	partial-book = ["AddEsth"]
+func Configuration(tool string) kong.Option {
+	return kong.OptionFunc(func(k *kong.Kong) error {
+		if path := os.Getenv(ConfigEnv); path != "" {
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("%s: %w", ConfigEnv, err)
+			}
+			loader := configLoader(tool, yamlDecode)
+			if strings.EqualFold(filepath.Ext(path), ".toml") {
+				loader = configLoader(tool, tomlDecode)
+			}
+			return kong.Configuration(loader, path).Apply(k)
+		}
+
+		if err := kong.Configuration(configLoader(tool, tomlDecode), "kjv.toml").Apply(k); err != nil {
+			return err
+		}
+		return kong.Configuration(configLoader(tool, yamlDecode), "kjv.yaml", "kjv.yml").Apply(k)
+	})
+}
+
+// tomlDecode decodes a TOML document into a generic map
+func tomlDecode(r io.Reader) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	if err := toml.NewDecoder(r).Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid TOML configuration: %w", err)
+	}
+	return config, nil
+}
+
+// yamlDecode decodes a YAML document into a generic map
+func yamlDecode(r io.Reader) (map[string]interface{}, error) {
+	config := map[string]interface{}{}
+	if err := yaml.NewDecoder(r).Decode(&config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid YAML configuration: %w", err)
+	}
+	return config, nil
+}
+
+// configLoader builds a kong configuration loader that resolves flags from the tool's section
+func configLoader(tool string, decode func(io.Reader) (map[string]interface{}, error)) kong.ConfigurationLoader {
+	return func(r io.Reader) (kong.Resolver, error) {
+		config, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return kong.ResolverFunc(func(_ *kong.Context, parent *kong.Path, flag *kong.Flag) (interface{}, error) {
+			var path []string
+			for n := parent.Node(); n != nil && n.Type != kong.ApplicationNode; n = n.Parent {
+				path = append([]string{n.Name}, path...)
+			}
+			if tool != "" {
+				path = append([]string{tool}, path...)
+			}
+
+			// Try the innermost section first, then each enclosing one
+			for i := len(path); i >= 0; i-- {
+				if value, ok := lookupConfig(config, path[:i], flag.Name); ok {
+					// kong's existingdir and existingfile mappers ignore values for flags already
+					// marked set, which every flag with a default is by now
+					flag.Set = false
+					return value, nil
+				}
+			}
+			return nil, nil
+		}), nil
+	}
+}
+
+// lookupConfig finds name, in kebab-case or snake_case, within the nested table at scope
+func lookupConfig(config map[string]interface{}, scope []string, name string) (interface{}, bool) {
+	table := config
+	for _, key := range scope {
+		child, ok := table[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		table = child
+	}
+
+	for _, key := range []string{name, strings.ReplaceAll(name, "-", "_")} {
+		if value, ok := table[key]; ok {
+			if _, isTable := value.(map[string]interface{}); !isTable {
+				return value, true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,119 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Exit statuses of the command-line tools. A run that fails to parse its command line exits with
+// kong's usage status, 80, instead.
+const (
+	ExitClean    = 0 // nothing to report
+	ExitWarnings = 1 // warnings, but no errors
+	ExitErrors   = 2 // validation errors in the sources or the canon
+	ExitFatal    = 3 // the run could not complete, such as when a file cannot be read or written
+)
+
+// ExitError is an error that ends a run with Code as its exit status. RunCLI exits with
+// ExitFatal for any other error.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Exit wraps err to end the run with code as its exit status
+func Exit(code int, err error) error {
+	return &ExitError{Code: code, Err: err}
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit status a run that returned err ends with
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitClean
+	}
+	var exit *ExitError
+	if errors.As(err, &exit) {
+		return exit.Code
+	}
+	return ExitFatal
+}
+
+// Gate counts the errors and warnings of a run by their code, such as "footnotes" or "plain",
+// leaving out the codes to ignore, and decides the exit status the run ends with
+type Gate struct {
+	Errors           int
+	Warnings         int
+	Ignored          int
+	warningsAsErrors bool
+	ignore           map[string]bool
+}
+
+// NewGate returns a gate that leaves out the codes in ignore, which may be comma-separated, and
+// fails the run on warnings too if warningsAsErrors is set
+func NewGate(ignore []string, warningsAsErrors bool) *Gate {
+	g := &Gate{warningsAsErrors: warningsAsErrors, ignore: make(map[string]bool)}
+	for _, codes := range ignore {
+		for _, code := range strings.Split(codes, ",") {
+			if code = strings.ToLower(strings.TrimSpace(code)); code != "" {
+				g.ignore[code] = true
+			}
+		}
+	}
+	return g
+}
+
+// Error counts an error with the given code, unless the code is ignored
+func (g *Gate) Error(code string) {
+	if g.ignore[strings.ToLower(code)] {
+		g.Ignored++
+		return
+	}
+	g.Errors++
+}
+
+// Warning counts a warning with the given code, unless the code is ignored
+func (g *Gate) Warning(code string) {
+	if g.ignore[strings.ToLower(code)] {
+		g.Ignored++
+		return
+	}
+	g.Warnings++
+}
+
+// Code returns ExitErrors if any errors were counted, or any warnings with warnings as errors;
+// ExitWarnings if only warnings were; and ExitClean otherwise
+func (g *Gate) Code() int {
+	switch {
+	case g.Errors > 0, g.Warnings > 0 && g.warningsAsErrors:
+		return ExitErrors
+	case g.Warnings > 0:
+		return ExitWarnings
+	default:
+		return ExitClean
+	}
+}
+
+// Err returns nil for a clean run, or an error ending the run with Code that reports the counts,
+// such as "validation completed with 2 errors and 1 warnings"
+func (g *Gate) Err(what string) error {
+	code := g.Code()
+	switch {
+	case code == ExitClean:
+		return nil
+	case g.Errors > 0 && g.Warnings > 0:
+		return Exit(code, fmt.Errorf("%s completed with %d errors and %d warnings", what, g.Errors, g.Warnings))
+	case g.Errors > 0:
+		return Exit(code, fmt.Errorf("%s completed with %d errors", what, g.Errors))
+	default:
+		return Exit(code, fmt.Errorf("%s completed with %d warnings", what, g.Warnings))
+	}
+}
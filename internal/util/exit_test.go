@@ -0,0 +1,72 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitClean},
+		{errors.New("failed to read books.json"), ExitFatal},
+		{Exit(ExitWarnings, errors.New("2 warnings")), ExitWarnings},
+		{fmt.Errorf("verify: %w", Exit(ExitErrors, errors.New("3 errors"))), ExitErrors},
+	}
+	for _, c := range cases {
+		if got := ExitCode(c.err); got != c.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", c.err, got, c.want)
+		}
+	}
+}
+
+func TestGate(t *testing.T) {
+	gate := NewGate([]string{"footnotes, Coverage", "divine-name"}, false)
+	if gate.Code() != ExitClean {
+		t.Errorf("expected a clean run before anything is counted, got %d", gate.Code())
+	}
+
+	gate.Error("footnotes")
+	gate.Error("coverage")
+	gate.Warning("Divine-Name")
+	if gate.Code() != ExitClean || gate.Ignored != 3 {
+		t.Errorf("expected ignored codes to leave the run clean, got status %d with %d ignored", gate.Code(), gate.Ignored)
+	}
+
+	gate.Warning("parse")
+	if gate.Code() != ExitWarnings {
+		t.Errorf("expected ExitWarnings with only warnings counted, got %d", gate.Code())
+	}
+
+	gate.Error("verses")
+	if gate.Code() != ExitErrors || gate.Errors != 1 || gate.Warnings != 1 {
+		t.Errorf("expected ExitErrors with 1 error and 1 warning, got %d with %d errors and %d warnings",
+			gate.Code(), gate.Errors, gate.Warnings)
+	}
+
+	strict := NewGate(nil, true)
+	strict.Warning("parse")
+	if strict.Code() != ExitErrors {
+		t.Errorf("expected warnings as errors to fail the run, got %d", strict.Code())
+	}
+}
+
+func TestGateErr(t *testing.T) {
+	gate := NewGate(nil, false)
+	if err := gate.Err("validation"); err != nil {
+		t.Errorf("expected no error for a clean run, got %v", err)
+	}
+	gate.Warning("plain")
+	err := gate.Err("validation")
+	if ExitCode(err) != ExitWarnings || err.Error() != "validation completed with 1 warnings" {
+		t.Errorf("unexpected error for a run with warnings: %v (status %d)", err, ExitCode(err))
+	}
+	gate.Error("layout")
+	err = gate.Err("validation")
+	if ExitCode(err) != ExitErrors || err.Error() != "validation completed with 1 errors and 1 warnings" {
+		t.Errorf("unexpected error for a run with errors: %v (status %d)", err, ExitCode(err))
+	}
+}
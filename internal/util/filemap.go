@@ -0,0 +1,21 @@
+package util
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+)
+
+// SHA256Hex returns the hex-encoded SHA256 digest of data
+func SHA256Hex(data []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// FileSHA256 returns the hex-encoded SHA256 digest of a file's contents
+func FileSHA256(path string) (string, error) {
+	data, err := os.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return "", err
+	}
+	return SHA256Hex(data), nil
+}
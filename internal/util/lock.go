@@ -0,0 +1,161 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LockName is the lock file a writer creates in the directory it writes to
+const LockName = ".lock"
+
+// ErrLocked is returned by AcquireLock when another run holds the lock
+var ErrLocked = errors.New("directory is locked by another run")
+
+// LockInfo records who holds a lock, for the error of a run the lock keeps out
+type LockInfo struct {
+	Command string    `json:"command"`
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Started time.Time `json:"started"`
+}
+
+// errLockHeld is returned by lockFile when another open file holds the lock
+var errLockHeld = errors.New("lock is held")
+
+// Lock is an advisory lock on a directory held by one ingest, extract, export, or migrate run
+type Lock struct {
+	path string
+	file *os.File
+}
+
+// AcquireLock takes an exclusive OS lock on dir/.lock for command (flock on Unix, LockFileEx on
+// Windows), failing with ErrLocked if another run already holds it, and records who holds it in
+// the file. The lock is advisory: it only keeps out other runs that also take it. The kernel
+// releases it when the process exits, so a killed run does not leave the directory locked. force
+// takes the lock even from a run that holds it, by replacing the file, for a run that is hung.
+func AcquireLock(dir, command string, force bool) (*Lock, error) {
+	path := filepath.Join(dir, LockName)
+	if force {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+
+	f, err := openLock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	host, _ := os.Hostname()
+	data, err := json.MarshalIndent(LockInfo{Command: command, PID: os.Getpid(), Host: host, Started: time.Now().UTC()}, "", "  ")
+	if err == nil {
+		err = f.Truncate(0)
+	}
+	if err == nil {
+		_, err = f.WriteAt(data, 0)
+	}
+	if err != nil {
+		l := &Lock{path: path, file: f}
+		_ = l.Release()
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return &Lock{path: path, file: f}, nil
+}
+
+// openLock opens and locks the file at path, creating it if need be. A run releasing the lock
+// removes the file while it still holds it, so a file that is no longer at path once locked is
+// let go and the new one locked instead.
+func openLock(path string) (*os.File, error) {
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600) // nolint: gosec
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		if err := lockFile(f); errors.Is(err, errLockHeld) {
+			_ = f.Close()
+			return nil, lockedError(path)
+		} else if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+
+		locked, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		current, err := os.Stat(path)
+		if err == nil && os.SameFile(locked, current) {
+			return f, nil
+		}
+		_ = unlockFile(f)
+		_ = f.Close()
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+	}
+}
+
+// lockedError describes the run holding the lock at path
+func lockedError(path string) error {
+	var info LockInfo
+	data, err := os.ReadFile(path) // nolint: gosec
+	if err != nil || json.Unmarshal(data, &info) != nil {
+		return fmt.Errorf("%w: %s is held by another run; if that run is hung, pass --force-unlock", ErrLocked, path)
+	}
+	return fmt.Errorf(
+		"%w: %s is held by %q (pid %d on %s) since %s; if that run is hung, pass --force-unlock",
+		ErrLocked,
+		path,
+		info.Command,
+		info.PID,
+		info.Host,
+		info.Started.Format(time.RFC3339),
+	)
+}
+
+// Release removes the lock file and releases the lock. The file is removed while still locked, so
+// a run that opened it meanwhile finds it gone once it gets the lock. A file that --force-unlock
+// put in its place belongs to the run that took the lock over, and is left alone.
+func (l *Lock) Release() error {
+	held, err := l.file.Stat()
+	if err != nil {
+		_ = unlockFile(l.file)
+		_ = l.file.Close()
+		return fmt.Errorf("failed to stat %s: %w", l.path, err)
+	}
+
+	removeErr := l.remove(held)
+	_ = unlockFile(l.file)
+	closeErr := l.file.Close()
+	if removeErr != nil {
+		// Windows does not remove a file that is open, so it is removed again once closed
+		if err := l.remove(held); err != nil {
+			return err
+		}
+	}
+	return closeErr
+}
+
+// remove removes the lock file if the path still refers to held, the file this lock was taken on
+func (l *Lock) remove(held fs.FileInfo) error {
+	current, err := os.Stat(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", l.path, err)
+	}
+	if !os.SameFile(held, current) {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove %s: %w", l.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package util
+
+import "os"
+
+// lockFile does nothing where the OS offers no file locks, such as GOOS=js, so runs are not kept
+// apart there
+func lockFile(f *os.File) error {
+	return nil
+}
+
+// unlockFile does nothing, as lockFile takes no lock
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAcquireLock(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := AcquireLock(dir, "ingest", false)
+	if err != nil {
+		t.Fatalf("AcquireLock failed: %v", err)
+	}
+
+	_, err = AcquireLock(dir, "extract all", false)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked while ingest holds the lock, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `held by "ingest"`) || !strings.Contains(err.Error(), "--force-unlock") {
+		t.Errorf("expected the error to name the holder and the escape hatch, got %v", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, LockName)); !os.IsNotExist(err) {
+		t.Error("expected Release to remove the lock file")
+	}
+
+	again, err := AcquireLock(dir, "export", false)
+	if err != nil {
+		t.Fatalf("expected the lock to be free after Release, got %v", err)
+	}
+	_ = again.Release()
+}
+
+func TestAcquireLockStale(t *testing.T) {
+	dir := t.TempDir()
+	// A run that was killed leaves its lock file behind, possibly half written, but not its lock
+	if err := os.WriteFile(filepath.Join(dir, LockName), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := AcquireLock(dir, "ingest", false)
+	if err != nil {
+		t.Fatalf("expected a lock file without a holder not to block the run, got %v", err)
+	}
+
+	// Closing the file without Release, as exiting does, gives up the lock
+	_ = lock.file.Close()
+	again, err := AcquireLock(dir, "extract all", false)
+	if err != nil {
+		t.Fatalf("expected the lock to be free once its file is closed, got %v", err)
+	}
+	if err := again.Release(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAcquireLockForce(t *testing.T) {
+	dir := t.TempDir()
+	hung, err := AcquireLock(dir, "ingest", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = hung.file.Close() }()
+
+	lock, err := AcquireLock(dir, "ingest", true)
+	if err != nil {
+		t.Fatalf("expected force to take over a held lock, got %v", err)
+	}
+	if _, err := AcquireLock(dir, "export", false); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected the forced lock to be held, got %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReleaseAfterForceUnlock(t *testing.T) {
+	dir := t.TempDir()
+	hung, err := AcquireLock(dir, "ingest", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := AcquireLock(dir, "ingest", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The hung run finishing must leave the lock of the run that took it over alone
+	if err := hung.Release(); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, LockName)); err != nil {
+		t.Fatalf("expected the lock file of the new holder to remain, got %v", err)
+	}
+	if _, err := AcquireLock(dir, "export", false); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected the lock to still be held, got %v", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, LockName)); !os.IsNotExist(err) {
+		t.Error("expected Release to remove the lock file")
+	}
+}
@@ -0,0 +1,168 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const manifestTitle = "# SHA256 manifest of raw KJV HTML and XML sources"
+
+// ParseManifest parses a SHA256MANIFEST in either the version 1 or version 2 format.
+// Manifests without a "# Schema:" header are treated as version 1.
+func ParseManifest(r io.Reader) (Manifest, error) {
+	m := Manifest{Schema: 1}
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			key, value, ok := strings.Cut(strings.TrimSpace(strings.TrimPrefix(line, "#")), ":")
+			if !ok {
+				continue
+			}
+			switch strings.TrimSpace(key) {
+			case "Schema":
+				schema, err := strconv.Atoi(strings.TrimSpace(value))
+				if err != nil {
+					return m, fmt.Errorf("invalid manifest schema on line %d: %q", lineNum, value)
+				}
+				if schema > ManifestSchema {
+					return m, fmt.Errorf("unsupported manifest schema version %d", schema)
+				}
+				m.Schema = schema
+			case "Generated":
+				m.Generated = strings.TrimSpace(value)
+			}
+			continue
+		}
+
+		entry, err := parseManifestLine(line, m.Schema)
+		if err != nil {
+			return m, fmt.Errorf("invalid manifest line %d: %w", lineNum, err)
+		}
+		m.Entries = append(m.Entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return m, fmt.Errorf("error reading manifest: %w", err)
+	}
+
+	return m, nil
+}
+
+// parseManifestLine parses "hash  path" (version 1) or "hash  size  path" (version 2)
+func parseManifestLine(line string, schema int) (ManifestEntry, error) {
+	hash, rest, ok := strings.Cut(line, "  ")
+	if !ok || hash == "" {
+		return ManifestEntry{}, fmt.Errorf("expected \"hash  path\", got %q", line)
+	}
+
+	entry := ManifestEntry{SHA256: hash, Size: -1, Path: rest}
+	if schema >= 2 {
+		size, path, ok := strings.Cut(rest, "  ")
+		if !ok {
+			return ManifestEntry{}, fmt.Errorf("expected \"hash  size  path\", got %q", line)
+		}
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil || n < 0 {
+			return ManifestEntry{}, fmt.Errorf("invalid file size %q", size)
+		}
+		entry.Size = n
+		entry.Path = path
+	}
+
+	if entry.Path == "" {
+		return ManifestEntry{}, fmt.Errorf("missing file path in %q", line)
+	}
+	return entry, nil
+}
+
+// ReadManifest reads and parses the manifest file at path
+func ReadManifest(path string) (Manifest, error) {
+	file, err := os.Open(path) // nolint: gosec
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to open manifest file: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Printf("Error closing manifest file: %v\n", err)
+		}
+	}()
+
+	return ParseManifest(file)
+}
+
+// Format renders the manifest in the current (version 2) format
+func (m Manifest) Format() string {
+	var b strings.Builder
+	b.WriteString(manifestTitle + "\n")
+	fmt.Fprintf(&b, "# Schema: %d\n", ManifestSchema)
+	if m.Generated != "" {
+		fmt.Fprintf(&b, "# Generated: %s\n", m.Generated)
+	}
+	for _, entry := range m.Entries {
+		fmt.Fprintf(&b, "%s  %d  %s\n", entry.SHA256, entry.Size, entry.Path)
+	}
+	return b.String()
+}
+
+// Resolve returns the on-disk location of an entry.
+// Version 2 paths are joined onto rawRoot. Version 1 paths are used as recorded, unless they lie
+// under rebase, the raw root the manifest was generated from, in which case they are moved onto rawRoot.
+func (m Manifest) Resolve(entry ManifestEntry, rawRoot, rebase string) string {
+	if m.Schema >= 2 {
+		return filepath.Join(rawRoot, filepath.FromSlash(entry.Path))
+	}
+	if rebase != "" {
+		if rel, ok := relativeTo(rebase, entry.Path); ok {
+			return filepath.Join(rawRoot, rel)
+		}
+	}
+	return entry.Path
+}
+
+// Relative returns entries keyed by their slash-separated path relative to rawRoot.
+// Version 1 entries recorded outside rawRoot are dropped.
+func (m Manifest) Relative(rawRoot string) map[string]ManifestEntry {
+	records := make(map[string]ManifestEntry, len(m.Entries))
+	for _, entry := range m.Entries {
+		rel := entry.Path
+		if m.Schema < 2 {
+			var ok bool
+			if rel, ok = relativeTo(rawRoot, entry.Path); !ok {
+				continue
+			}
+		}
+		entry.Path = filepath.ToSlash(rel)
+		records[entry.Path] = entry
+	}
+	return records
+}
+
+// relativeTo returns path relative to root if path lies beneath it
+func relativeTo(root, path string) (string, bool) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
@@ -0,0 +1,83 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+// progressFrames are the frames of the spinner Progress animates on a terminal
+var progressFrames = []string{"-", "\\", "|", "/"}
+
+// Progress shows that a command is working. On a terminal it animates a spinner on one line,
+// which Stop clears; otherwise it writes its text once as a plain line, so piped output and logs
+// carry no control characters.
+type Progress struct {
+	text string
+	out  io.Writer
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// StartProgress starts showing text, such as "Processing", on out, animated if out is a terminal
+func StartProgress(out *os.File, text string) *Progress {
+	return startProgress(out, text, isatty.IsTerminal(out.Fd()) || isatty.IsCygwinTerminal(out.Fd()))
+}
+
+func startProgress(out io.Writer, text string, tty bool) *Progress {
+	p := &Progress{text: text, out: out, stop: make(chan struct{}), done: make(chan struct{})}
+	if !tty {
+		_, _ = fmt.Fprintf(out, "%s...\n", text)
+		close(p.done)
+		return p
+	}
+	go p.spin()
+	return p
+}
+
+// spin animates the spinner until Stop is called, then clears its line
+func (p *Progress) spin() {
+	defer close(p.done)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for i := 0; ; i++ {
+		_, _ = fmt.Fprintf(p.out, "\r%s %s... ", progressFrames[i%len(progressFrames)], p.text)
+		select {
+		case <-p.stop:
+			_, _ = fmt.Fprint(p.out, "\r\033[K")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop stops the spinner and waits until its line is cleared, so output that follows is not
+// interleaved with it. It may be called more than once, and on a nil Progress.
+func (p *Progress) Stop() {
+	if p == nil {
+		return
+	}
+	p.once.Do(func() { close(p.stop) })
+	<-p.done
+}
+
+// progressKey is the context key of the Progress of a command
+type progressKey struct{}
+
+// WithProgress returns a copy of ctx that carries p, for StopProgress
+func WithProgress(ctx context.Context, p *Progress) context.Context {
+	return context.WithValue(ctx, progressKey{}, p)
+}
+
+// StopProgress stops the Progress ctx carries, if any. Commands call it before they print their
+// results; RunCLI stops it once the command returns in any case.
+func StopProgress(ctx context.Context) {
+	p, _ := ctx.Value(progressKey{}).(*Progress)
+	p.Stop()
+}
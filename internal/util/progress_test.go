@@ -0,0 +1,44 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestProgressLine(t *testing.T) {
+	var out bytes.Buffer
+	p := startProgress(&out, "Processing", false)
+	p.Stop()
+	p.Stop()
+	if out.String() != "Processing...\n" {
+		t.Errorf("expected one plain line off a terminal, got %q", out.String())
+	}
+}
+
+func TestProgressSpinner(t *testing.T) {
+	var out bytes.Buffer
+	p := startProgress(&out, "Processing", true)
+	p.Stop()
+	got := out.String()
+	if !strings.HasPrefix(got, "\r- Processing... ") {
+		t.Errorf("expected the spinner's first frame, got %q", got)
+	}
+	if !strings.HasSuffix(got, "\r\033[K") {
+		t.Errorf("expected Stop to clear the spinner's line, got %q", got)
+	}
+}
+
+func TestStopProgress(t *testing.T) {
+	// A context without a Progress, as in tests that run commands directly, stops nothing
+	StopProgress(context.Background())
+
+	var out bytes.Buffer
+	ctx := WithProgress(context.Background(), startProgress(&out, "Exporting", true))
+	StopProgress(ctx)
+	StopProgress(ctx)
+	if !strings.HasSuffix(out.String(), "\r\033[K") {
+		t.Errorf("expected StopProgress to stop the spinner, got %q", out.String())
+	}
+}
@@ -0,0 +1,76 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+// SelectBooks resolves a book selector against the books of books.json. A selector is a
+// comma-separated list of "all", testaments ("ot", "ap", "nt"), groups such as "gospels" (see
+// testament.Groups), and books by abbreviation or OSIS code, such as "GEN,Exod,ps", all matched
+// case-insensitively. The selected books are returned in books.json order, each once.
+func SelectBooks(books []model.BookMetadata, selector string) ([]model.BookMetadata, error) {
+	selected := make(map[string]bool)
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		matched, err := selectTerm(books, term)
+		if err != nil {
+			return nil, err
+		}
+		for _, osis := range matched {
+			selected[osis] = true
+		}
+	}
+
+	var result []model.BookMetadata
+	for _, book := range books {
+		if selected[book.OSIS] {
+			result = append(result, book)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("book selector %q selects no books", selector)
+	}
+	return result, nil
+}
+
+// selectTerm returns the OSIS codes, as written in books.json, of the books one selector term names
+func selectTerm(books []model.BookMetadata, term string) ([]string, error) {
+	match := func(keep func(model.BookMetadata) bool) []string {
+		var matched []string
+		for _, book := range books {
+			if keep(book) {
+				matched = append(matched, book.OSIS)
+			}
+		}
+		return matched
+	}
+
+	if strings.EqualFold(term, "all") {
+		return match(func(model.BookMetadata) bool { return true }), nil
+	}
+	if t, err := testament.Parse(strings.ToUpper(term)); err == nil {
+		return match(func(book model.BookMetadata) bool { return book.Testament == string(t) }), nil
+	}
+	if group, ok := testament.Group(term); ok {
+		members := make(map[string]bool, len(group))
+		for _, osis := range group {
+			members[osis] = true
+		}
+		return match(func(book model.BookMetadata) bool { return members[model.CanonicalOSIS(book.OSIS)] }), nil
+	}
+	if matched := match(func(book model.BookMetadata) bool {
+		return strings.EqualFold(book.Abbr, term) || strings.EqualFold(book.OSIS, term) ||
+			model.CanonicalOSIS(book.OSIS) == model.CanonicalOSIS(term)
+	}); len(matched) > 0 {
+		return matched, nil
+	}
+	return nil, fmt.Errorf("unknown book or group %q: expected all, ot, ap, nt, a group (%s), or a book abbreviation or OSIS code",
+		term, strings.Join(testament.Groups(), ", "))
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestSelectBooks(t *testing.T) {
+	books := []model.BookMetadata{
+		{OSIS: "Gen", Abbr: "GEN", Testament: "OT"},
+		{OSIS: "Exod", Abbr: "EXO", Testament: "OT"},
+		{OSIS: "Ps", Abbr: "PSA", Testament: "OT"},
+		{OSIS: "Tob", Abbr: "TOB", Testament: "AP"},
+		{OSIS: "Matt", Abbr: "MAT", Testament: "NT"},
+		{OSIS: "John", Abbr: "JHN", Testament: "NT"},
+		{OSIS: "1Cor", Abbr: "1CO", Testament: "NT"},
+	}
+
+	tests := []struct {
+		selector string
+		want     string // OSIS codes, comma-separated
+		wantErr  string
+	}{
+		{selector: "all", want: "Gen,Exod,Ps,Tob,Matt,John,1Cor"},
+		{selector: "ot", want: "Gen,Exod,Ps"},
+		{selector: "NT", want: "Matt,John,1Cor"},
+		{selector: "gospels", want: "Matt,John"},
+		{selector: "pentateuch,epistles", want: "Gen,Exod,1Cor"},
+		{selector: "PSA, GEN,exod", want: "Gen,Exod,Ps"},
+		{selector: "1 Cor,nt", want: "Matt,John,1Cor"},
+		{selector: "GEN,apostles", wantErr: `unknown book or group "apostles"`},
+		{selector: "minor-prophets", wantErr: "selects no books"},
+		{selector: "", wantErr: "selects no books"},
+	}
+	for _, tt := range tests {
+		selected, err := SelectBooks(books, tt.selector)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("SelectBooks(%q) error = %v, want %q", tt.selector, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("SelectBooks(%q) failed: %v", tt.selector, err)
+			continue
+		}
+		osis := make([]string, len(selected))
+		for i, book := range selected {
+			osis[i] = book.OSIS
+		}
+		if got := strings.Join(osis, ","); got != tt.want {
+			t.Errorf("SelectBooks(%q) = %s, want %s", tt.selector, got, tt.want)
+		}
+	}
+}
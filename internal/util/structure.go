@@ -0,0 +1,166 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+// CanonStructureSchema is the current canon-structure.json schema version
+const CanonStructureSchema = 1
+
+// DefaultCanonStructure is the canon structure file shipped with the repository, relative to its root
+const DefaultCanonStructure = "metadata/canon-structure.json"
+
+// StructureBook is one book in canon-structure.json
+type StructureBook struct {
+	Abbr      string `json:"abbr"` // UBS abbreviation used by the raw sources
+	OSIS      string `json:"osis"`
+	Testament string `json:"testament"` // OT, AP, or NT
+	Chapters  int    `json:"chapters"`
+	// Aliases are carried into books.json, such as the spaced code ("1 Sam") that canons ingested
+	// before OSIS IDs were normalized used for the book
+	Aliases []string `json:"aliases,omitempty"`
+	// VerseMaps lists, by chapter, the verse numbers of chapters that do not run from verse 1 without
+	// gaps, such as the additions to Esther, whose chapter 10 starts at verse 4
+	VerseMaps map[int][]int `json:"verse_maps,omitempty"`
+}
+
+// CanonStructure is the structure of canon-structure.json: the books of a canon in order, with their chapter counts
+type CanonStructure struct {
+	Schema  int             `json:"schema"`
+	Version string          `json:"version"`
+	Work    string          `json:"work"`
+	Books   []StructureBook `json:"books"`
+}
+
+var ubsAbbrPattern = regexp.MustCompile(`^[0-9A-Z]{3}$`)
+
+// LoadCanonStructure reads and validates a canon structure file
+func LoadCanonStructure(path string) (*CanonStructure, error) {
+	data, err := os.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to read canon structure: %w", err)
+	}
+	return ParseCanonStructure(data)
+}
+
+// ParseCanonStructure parses and validates canon-structure.json
+func ParseCanonStructure(data []byte) (*CanonStructure, error) {
+	var s CanonStructure
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse canon structure: %w", err)
+	}
+	s.normalize()
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate checks the schema version and that every book has a unique abbreviation and OSIS code,
+// a known testament, and at least one chapter
+func (s *CanonStructure) Validate() error {
+	if s.Schema != CanonStructureSchema {
+		return fmt.Errorf("unsupported canon structure schema version %d", s.Schema)
+	}
+	if s.Version == "" {
+		return fmt.Errorf("canon structure has no version")
+	}
+	if len(s.Books) == 0 {
+		return fmt.Errorf("canon structure lists no books")
+	}
+
+	abbrs := make(map[string]bool, len(s.Books))
+	osis := make(map[string]bool, len(s.Books))
+	for i, book := range s.Books {
+		if !ubsAbbrPattern.MatchString(book.Abbr) {
+			return fmt.Errorf("book %d: invalid abbreviation %q", i+1, book.Abbr)
+		}
+		if abbrs[book.Abbr] {
+			return fmt.Errorf("book %s is listed more than once", book.Abbr)
+		}
+		if book.OSIS == "" {
+			return fmt.Errorf("book %s has no OSIS code", book.Abbr)
+		}
+		if osis[book.OSIS] {
+			return fmt.Errorf("book %s: OSIS code %q is listed more than once", book.Abbr, book.OSIS)
+		}
+		t, err := testament.Parse(book.Testament)
+		if err != nil {
+			return fmt.Errorf("book %s: %w", book.Abbr, err)
+		}
+		if known, ok := testament.Of(book.OSIS); ok && known != t {
+			return fmt.Errorf("book %s: %s belongs to %s, not %s", book.Abbr, book.OSIS, known, t)
+		}
+		if book.Chapters < 1 {
+			return fmt.Errorf("book %s: chapter count must be positive, got %d", book.Abbr, book.Chapters)
+		}
+		for chapter, verses := range book.VerseMaps {
+			if err := validateVerseMap(book.Chapters, chapter, verses); err != nil {
+				return fmt.Errorf("book %s: %w", book.Abbr, err)
+			}
+		}
+		abbrs[book.Abbr] = true
+		osis[book.OSIS] = true
+	}
+	return nil
+}
+
+// validateVerseMap checks that a verse map belongs to a chapter of the book and lists positive verse
+// numbers in ascending order
+func validateVerseMap(chapters, chapter int, verses []int) error {
+	if chapter < 1 || chapter > chapters {
+		return fmt.Errorf("verse map for chapter %d is outside chapters 1-%d", chapter, chapters)
+	}
+	if len(verses) == 0 {
+		return fmt.Errorf("verse map for chapter %d is empty", chapter)
+	}
+	for i, v := range verses {
+		if v < 1 || (i > 0 && v <= verses[i-1]) {
+			return fmt.Errorf("verse map for chapter %d must list positive verse numbers in ascending order", chapter)
+		}
+	}
+	return nil
+}
+
+// normalize replaces spaced OSIS codes such as "1 Sam" with canonical IDs such as "1Sam", keeping
+// the spaced code as an alias, so structure files written before OSIS IDs were normalized still
+// produce a canonical index
+func (s *CanonStructure) normalize() {
+	for i, book := range s.Books {
+		if book.OSIS == "" || model.IsCanonicalOSIS(book.OSIS) {
+			continue
+		}
+		if !slices.Contains(book.Aliases, book.OSIS) {
+			s.Books[i].Aliases = append(book.Aliases, book.OSIS)
+		}
+		s.Books[i].OSIS = model.CanonicalOSIS(book.OSIS)
+	}
+}
+
+// Book returns the book with a UBS abbreviation and its 1-based canonical order
+func (s *CanonStructure) Book(abbr string) (StructureBook, int, bool) {
+	for i, book := range s.Books {
+		if book.Abbr == abbr {
+			return book, i + 1, true
+		}
+	}
+	return StructureBook{}, 0, false
+}
+
+// VerseMap returns the verse numbers a chapter of the book with a UBS abbreviation is expected to
+// have, reporting false for chapters numbered from 1 without gaps
+func (s *CanonStructure) VerseMap(abbr string, chapter int) ([]int, bool) {
+	book, _, exists := s.Book(abbr)
+	if !exists {
+		return nil, false
+	}
+	verses, ok := book.VerseMaps[chapter]
+	return verses, ok
+}
@@ -1,108 +1,94 @@
 package util
 
-import "time"
-
-// BookMetadata represents book information from books.json
-type BookMetadata struct {
-	OSIS      string   `json:"osis"`
-	Abbr      string   `json:"abbr"`
-	Name      string   `json:"name"`
-	Aliases   []string `json:"aliases"`
-	Testament string   `json:"testament"`
-	Order     int      `json:"order"`
-	Chapters  int      `json:"chapters"`
-}
-
-// BooksData is the structure of books.json
-type BooksData struct {
-	Schema int            `json:"schema"`
-	Work   string         `json:"work"`
-	Books  []BookMetadata `json:"books"`
-}
-
-// AliasChapters represents the chapter mapping for a book
-type AliasChapters struct {
-	SourceAbbr string            `json:"source_abbr"`
-	Chapters   map[string]string `json:"chapters"`
-}
-
-// AliasesData is the structure of aliases.json (map of OSIS -> AliasChapters)
-type AliasesData map[string]AliasChapters
-
-// Verse represents a single verse
-// Token represents a single token in a verse (text, added word, divine name, etc.)
-type Token struct {
-	Text string `json:"t,omitempty"`
-	Add  string `json:"add,omitempty"`
-	ND   string `json:"nd,omitempty"`
-}
+import (
+	"fmt"
+	"time"
 
-// Verse represents a single verse with tokenized content
-type Verse struct {
-	V      int     `json:"v"`
-	Plain  string  `json:"plain,omitempty"`
-	Tokens []Token `json:"tokens"`
-}
-
-// Footnote represents a biblical footnote
-type Footnote struct {
-	ID   string `json:"id"`
-	Mark string `json:"mark"`
-	At   struct {
-		V int `json:"v"`
-	} `json:"at"`
-	Text string `json:"text"`
-}
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
 
-// ChapterMeta holds metadata about a chapter
-type ChapterMeta struct {
-	Work    string `json:"work"`
-	OSIS    string `json:"osis"`
-	Abbr    string `json:"abbr"`
-	Chapter int    `json:"chapter"`
+// ValidationError represents a validation failure
+type ValidationError struct {
+	File       string
+	Type       string // "filename", "label", "range", "parse", "verses", "footnotes", "coverage"
+	Message    string
+	Expected   interface{}
+	Actual     interface{}
+	Location   SourceLocation // where in File the failure is, when it concerns a particular element
+	Suggestion string         // how the raw HTML might be repaired, when the cause could be found
 }
 
-// Chapter represents a complete chapter with verses and footnotes
-type Chapter struct {
-	Schema    int        `json:"schema"`
-	Work      string     `json:"work"`
-	OSIS      string     `json:"osis"`
-	Abbr      string     `json:"abbr"`
-	Chapter   int        `json:"chapter"`
-	Verses    []Verse    `json:"verses"`
-	Footnotes []Footnote `json:"footnotes,omitempty"`
+// SourceLocation is the approximate place in a raw HTML file that an error refers to
+type SourceLocation struct {
+	Element string // the element nearest the problem, as "#V12" for an id or ".chapterlabel" for a class
+	Line    int    // 1-based line of the element's start tag, or 0 if it was not found in the file
+	Column  int    // 1-based byte column of the element's start tag
 }
 
-// ValidationError represents a validation failure
-type ValidationError struct {
-	File     string
-	Type     string // "filename", "label", "range", "parse"
-	Message  string
-	Expected interface{}
-	Actual   interface{}
+// String formats the location as "line 3, column 120 (#V12)", or as the element alone when its
+// position is unknown; it is empty when the error concerns no particular element
+func (l SourceLocation) String() string {
+	if l.Line == 0 {
+		return l.Element
+	}
+	return fmt.Sprintf("line %d, column %d (%s)", l.Line, l.Column, l.Element)
 }
 
-// FileMap tracks source to output file mappings
-type FileMap map[string]string
-
 // ProcessResult holds the result of processing a book
 type ProcessResult struct {
 	Book              string
 	OSIS              string
 	FilesProcessed    int
 	FilesSkipped      int
+	ChaptersWritten   int // chapters written by the exporters
+	VersesWritten     int // verses in the chapters written
 	Errors            []ValidationError
-	FileMap           FileMap
+	Warnings          []ValidationError // problems a lenient parse recovered from, in chapters that were still written
+	Fixes             map[string]int    // repairs the sanitizer made to raw HTML before parsing, by fix name
+	FileMap           model.FileMap
+	Verses            model.VerseIndex
 	VerificationStats VerificationStats
+	Timings           StageTimings
 	StartTime         time.Time
 	EndTime           time.Time
 }
 
+// StageTimings is the time spent in each stage of ingesting chapter files
+type StageTimings struct {
+	Read     time.Duration // reading raw HTML files
+	Parse    time.Duration // parsing HTML into extracted chapters
+	Validate time.Duration // validating book structure and extracted chapters
+	Convert  time.Duration // converting extracted chapters to the canonical model
+	Write    time.Duration // writing output and checksumming it for the filemap
+}
+
+// Add adds other's stage durations to t
+func (t *StageTimings) Add(other StageTimings) {
+	t.Read += other.Read
+	t.Parse += other.Parse
+	t.Validate += other.Validate
+	t.Convert += other.Convert
+	t.Write += other.Write
+}
+
+// Total returns the time spent across all stages
+func (t StageTimings) Total() time.Duration {
+	return t.Read + t.Parse + t.Validate + t.Convert + t.Write
+}
+
+// String formats the stage durations on one line
+func (t StageTimings) String() string {
+	return fmt.Sprintf("read %v, parse %v, validate %v, convert %v, write %v",
+		t.Read.Round(time.Microsecond), t.Parse.Round(time.Microsecond), t.Validate.Round(time.Microsecond),
+		t.Convert.Round(time.Microsecond), t.Write.Round(time.Microsecond))
+}
+
 // VerificationStats tracks validation results
 type VerificationStats struct {
 	ContinuousVerses int // chapters with verse continuity errors
 	MissingVerses    int // chapters with missing verse counts
 	FootnoteIssues   int // chapters with footnote validation issues
+	MissingChapters  int // expected chapters with no raw source file
 }
 
 // ExtractedChapter holds raw extracted data from HTML
@@ -113,11 +99,19 @@ type ExtractedChapter struct {
 	SourceFile    string
 }
 
+// ExtractedIntro holds raw introduction data from HTML
+type ExtractedIntro struct {
+	Title      string
+	Paragraphs []string
+	SourceFile string
+}
+
 // ExtractedVerse holds raw verse data from HTML
 type ExtractedVerse struct {
 	Number int
+	ID     string // id of the verse's <span class="verse">, e.g., "V12"
 	Plain  string
-	Tokens []Token
+	Tokens []model.Token
 }
 
 // ExtractedFootnote holds raw footnote data from HTML
@@ -127,3 +121,22 @@ type ExtractedFootnote struct {
 	VerseNum int    // verse number this footnote references
 	Text     string // footnote text
 }
+
+// ManifestSchema is the current SHA256MANIFEST format version. Version 1 lines are "hash  path" with
+// paths as they were passed to the generator; version 2 lines are "hash  size  path" with paths
+// relative to the raw root.
+const ManifestSchema = 2
+
+// Manifest is a parsed SHA256MANIFEST of raw source files
+type Manifest struct {
+	Schema    int
+	Generated string
+	Entries   []ManifestEntry
+}
+
+// ManifestEntry is a single file recorded in the manifest
+type ManifestEntry struct {
+	SHA256 string
+	Size   int64 // -1 when the manifest does not record sizes
+	Path   string
+}
@@ -0,0 +1,66 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+// alignmentFile matches the name of an alignment sidecar, capturing its chapter number
+var alignmentFile = regexp.MustCompile(`^ch(\d+)\.json$`)
+
+// checkAlignments reports sidecars under alignDir ({OSIS}/chNN.json) that do not parse or no longer
+// match the text of their chapter. A canon without align/ has nothing to check.
+func checkAlignments(canonDir, alignDir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(alignDir, "*", "*"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	sort.Strings(files)
+
+	corpus, err := kjvcorpus.Open(canonDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	var problems []string
+	for _, file := range files {
+		name, _ := filepath.Rel(alignDir, file)
+		osis := filepath.Base(filepath.Dir(file))
+		match := alignmentFile.FindStringSubmatch(filepath.Base(file))
+		if match == nil {
+			problems = append(problems, fmt.Sprintf("%s: not an alignment file (expected chNN.json)", name))
+			continue
+		}
+		chapter, _ := strconv.Atoi(match[1])
+
+		data, err := os.ReadFile(file) // nolint: gosec
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		alignment, err := kjvcorpus.ParseAlignment(data)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		resolved, err := corpus.Resolve(&bibleref.BibleRef{OSIS: osis, Chapter: chapter})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: no such chapter: %v", name, err))
+			continue
+		}
+		if err := kjvcorpus.ValidateAlignment(&resolved.Chapter, alignment); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	return problems, nil
+}
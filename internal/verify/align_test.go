@@ -0,0 +1,72 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckAlignments(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+	canon := filepath.Join(cwd, "canon", "kjv")
+
+	// A missing align/ directory has nothing to check
+	problems, err := checkAlignments(canon, filepath.Join(t.TempDir(), "align"))
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("expected no problems without align/, got %v, %v", problems, err)
+	}
+
+	alignDir := t.TempDir()
+	files := map[string]string{
+		"Gen/ch01.json":  `{"schema": 1, "osis": "Gen", "chapter": 1, "source": "test", "language": "hbo", "verses": [{"v": 1, "kjv_words": 10, "words": [{"text": "בָּרָא", "kjv": [4]}]}]}`,
+		"Gen/ch02.json":  `{"schema": 1, "osis": "Gen", "chapter": 2, "verses": [{"v": 1, "kjv_words": 99, "words": []}]}`,
+		"Gen/ch03.json":  `{"schema": 2}`,
+		"Gen/notes.txt":  `not an alignment`,
+		"Nope/ch01.json": `{"schema": 1, "osis": "Nope", "chapter": 1, "verses": []}`,
+	}
+	for name, data := range files {
+		path := filepath.Join(alignDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	problems, err = checkAlignments(canon, alignDir)
+	if err != nil {
+		t.Fatalf("checkAlignments failed: %v", err)
+	}
+	want := []string{
+		filepath.Join("Gen", "ch02.json") + ": ",
+		filepath.Join("Gen", "ch03.json") + ": unsupported alignment schema version 2",
+		filepath.Join("Gen", "notes.txt") + ": not an alignment file",
+		filepath.Join("Nope", "ch01.json") + ": no such chapter",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("expected %d problems, got %d: %v", len(want), len(problems), problems)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(problems[i], prefix) {
+			t.Errorf("problem %d: expected prefix %q, got %q", i, prefix, problems[i])
+		}
+	}
+	if !strings.Contains(problems[0], "aligned against 99 words") {
+		t.Errorf("expected the stale word count to be reported, got %q", problems[0])
+	}
+}
@@ -0,0 +1,138 @@
+package verify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// getBookFiles returns the chapter files of one book, the --book counterpart of getCanonFiles
+func getBookFiles(canonDir, osis string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(canonDir, "books", osis, "ch*.json*"))
+	var files []string
+	for _, file := range matches {
+		if isChapterFile(file) {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files, err
+}
+
+// checkBookManifest reports where books/{OSIS}/book.json disagrees with the book's chapter files:
+// listed chapters that are missing, changed since ingest, or hold a different number of verses,
+// and chapter files the manifest does not list. A book without a directory has nothing to check.
+func checkBookManifest(canonDir, osis string) []string {
+	bookDir := filepath.Join(canonDir, "books", osis)
+	if _, err := os.Stat(bookDir); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	file := filepath.Join(bookDir, util.BookManifestName)
+	data, err := os.ReadFile(file) // nolint: gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{fmt.Sprintf("%s: missing %s; run ingest or kjvsrc migrate manifests", osis, util.BookManifestName)}
+	} else if err != nil {
+		return []string{fmt.Sprintf("%s: %v", osis, err)}
+	}
+	manifest, err := model.ParseBookManifest(data)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", file, err)}
+	}
+
+	var problems []string
+	if manifest.OSIS != osis {
+		problems = append(problems, fmt.Sprintf("%s: lists book %q", file, manifest.OSIS))
+	}
+
+	listed := make(map[string]bool, len(manifest.Chapters))
+	for _, entry := range manifest.Chapters {
+		listed[entry.File] = true
+		path := filepath.Join(bookDir, entry.File)
+		content, err := os.ReadFile(path) // nolint: gosec
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s %d: listed chapter file %s cannot be read", osis, entry.Chapter, entry.File))
+			continue
+		}
+		if sum := util.SHA256Hex(content); sum != entry.SHA256 {
+			problems = append(problems, fmt.Sprintf("%s %d: checksum mismatch for %s: recorded %s, got %s", osis, entry.Chapter, entry.File, entry.SHA256, sum))
+		}
+
+		var chapter model.Chapter
+		if content, err = compression.Decompress(content); err != nil {
+			continue // reported by chapter validation
+		}
+		if err := json.Unmarshal(content, &chapter); err != nil {
+			continue // reported by chapter validation
+		}
+		if chapter.Chapter != entry.Chapter {
+			problems = append(problems, fmt.Sprintf("%s: %s is listed as chapter %d but holds chapter %d", osis, entry.File, entry.Chapter, chapter.Chapter))
+		}
+		if len(chapter.Verses) != entry.Verses {
+			problems = append(problems, fmt.Sprintf("%s %d: recorded %d verses, found %d", osis, entry.Chapter, entry.Verses, len(chapter.Verses)))
+		}
+	}
+
+	files, err := getBookFiles(canonDir, osis)
+	if err != nil {
+		return append(problems, fmt.Sprintf("%s: %v", osis, err))
+	}
+	for _, path := range files {
+		if name := filepath.Base(path); !listed[name] {
+			problems = append(problems, fmt.Sprintf("%s: chapter file %s is not listed in %s", osis, name, util.BookManifestName))
+		}
+	}
+	return problems
+}
+
+// refreshBookManifests records new checksums in book.json for chapter files rewritten by
+// --autofix-plain, as updateFileMapChecksums does in filemap.json
+func refreshBookManifests(rewritten map[string]bool) error {
+	byBook := make(map[string]bool)
+	for path := range rewritten {
+		byBook[filepath.Dir(path)] = true
+	}
+
+	for bookDir := range byBook {
+		file := filepath.Join(bookDir, util.BookManifestName)
+		data, err := os.ReadFile(file) // nolint: gosec
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // reported by checkBookManifest
+		} else if err != nil {
+			return err
+		}
+		manifest, err := model.ParseBookManifest(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		for i, entry := range manifest.Chapters {
+			path := filepath.Join(bookDir, entry.File)
+			if !rewritten[filepath.Clean(path)] {
+				continue
+			}
+			sum, err := util.FileSHA256(path)
+			if err != nil {
+				return err
+			}
+			manifest.Chapters[i].SHA256 = sum
+		}
+		data, err = json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", file, err)
+		}
+		if err := atomicfile.WriteFile(file, data, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package verify
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestCheckBookManifest(t *testing.T) {
+	canonDir := t.TempDir()
+	bookDir := filepath.Join(canonDir, "books", "Obad")
+	if err := os.MkdirAll(bookDir, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if problems := checkBookManifest(canonDir, "Joel"); problems != nil {
+		t.Errorf("expected a book without a directory to be skipped, got %v", problems)
+	}
+
+	chapter := model.Chapter{Schema: 1, Work: "KJV", OSIS: "Obad", Chapter: 1, Verses: []model.Verse{{V: 1}, {V: 2}}}
+	data, err := json.MarshalIndent(chapter, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bookDir, "ch01.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	problems := checkBookManifest(canonDir, "Obad")
+	if len(problems) != 1 || !strings.Contains(problems[0], "migrate manifests") {
+		t.Fatalf("expected a missing book.json to be reported, got %v", problems)
+	}
+
+	fileMap := model.NewFileMap()
+	fileMap.Add(model.FileMapEntry{Raw: "raw/html/ot/OBA/OBA01.htm", Output: "books/Obad/ch01.json"})
+	manifest, err := util.BuildBookManifest(canonDir, "KJV", "Obad", fileMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := util.WriteBookManifest(canonDir, manifest); err != nil {
+		t.Fatal(err)
+	}
+	if problems := checkBookManifest(canonDir, "Obad"); len(problems) != 0 {
+		t.Fatalf("expected a fresh manifest to match, got %v", problems)
+	}
+
+	chapter.Verses = chapter.Verses[:1]
+	data, _ = json.MarshalIndent(chapter, "", "  ")
+	if err := os.WriteFile(filepath.Join(bookDir, "ch01.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bookDir, "ch02.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	problems = checkBookManifest(canonDir, "Obad")
+	if len(problems) != 3 {
+		t.Fatalf("expected checksum, verse count, and unlisted file problems, got %v", problems)
+	}
+	for i, want := range []string{"checksum mismatch", "recorded 2 verses, found 1", "ch02.json is not listed"} {
+		if !strings.Contains(problems[i], want) {
+			t.Errorf("expected problem %d to mention %q, got %q", i, want, problems[i])
+		}
+	}
+}
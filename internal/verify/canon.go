@@ -0,0 +1,683 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/internal/analyze"
+	"github.com/julianstephens/kjv-sources/internal/normalize"
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+func (c *CanonCmd) Run(ctx context.Context) error {
+	books, err := loadBooks(c.Indexes)
+	if err != nil {
+		return err
+	}
+
+	// --books checks each selected book's directory against its book.json instead of walking the
+	// whole tree, unless it selects every book
+	scope := books.Books
+	if c.Books != "" {
+		scope, err = util.SelectBooks(books.Books, c.Books)
+		if err != nil {
+			return err
+		}
+	}
+	someBooks := len(scope) < len(books.Books)
+	// A canon written in the content-addressed layout has its chapters in objects/
+	objects, err := loadObjectIndex(c.Indexes)
+	if err != nil {
+		return err
+	}
+	objBooks := objectBooks(c.Canon, objects)
+	var chapters []string
+	if someBooks {
+		for _, book := range scope {
+			files, err := getBookFiles(c.Canon, book.OSIS)
+			if err != nil {
+				return err
+			}
+			chapters = append(chapters, files...)
+		}
+	} else {
+		chapters, err = getCanonFiles(c.Canon)
+		if err != nil {
+			return err
+		}
+	}
+	chapters = append(chapters, getObjectFiles(c.Canon, objects, scope)...)
+	fmt.Printf("Found %d chapter files\n", len(chapters))
+
+	if len(chapters) == 0 {
+		fmt.Println("No chapter files found, skipping validation")
+		return nil
+	}
+
+	fileMap, err := loadFileMap(c.Indexes)
+	if err != nil {
+		return err
+	}
+
+	verseIndex, err := loadVerseIndex(c.Indexes)
+	if err != nil {
+		return err
+	}
+
+	gate := util.NewGate(c.Ignore, c.WarningsAsErrors)
+	order := make([]string, len(books.Books))
+	for i, book := range books.Books {
+		order[i] = book.OSIS
+	}
+	report := newCanonReport(gate, os.Stdout, c.MaxOutput, order)
+
+	// Orphaned and stale chapter files are either pruned or reported, and never validated
+	orphans := findOrphans(c.Canon, chapters, fileMap, books)
+	orphanSet := make(map[string]bool, len(orphans))
+	for _, orphan := range orphans {
+		orphanSet[orphan.Path] = true
+		if c.Prune {
+			if err := os.Remove(orphan.Path); err != nil {
+				report.error("prune", bookOf(c.Canon, orphan.Path, objBooks), "Prune error: failed to remove %s: %v", orphan.Path, err)
+				continue
+			}
+			fmt.Printf("Pruned %s file: %s (%s)\n", orphan.Kind, orphan.Path, orphan.Reason)
+			continue
+		}
+		report.error("orphan", bookOf(c.Canon, orphan.Path, objBooks), "Orphan error: %s file %s (%s)", orphan.Kind, orphan.Path, orphan.Reason)
+	}
+
+	bookChapterCounts := make(map[string]int)
+	plain := newPlainStats()
+	divine := analyze.NewDivineNameAuditor()
+	added := analyze.NewAddedWordAuditor()
+	rewritten := make(map[string]bool)
+
+	// stopped is set when --fail-fast ends the run at its first error, after checked chapter files
+	stopped := false
+	checked := len(orphans)
+	failed := func() bool {
+		stopped = c.FailFast && gate.Errors > 0
+		return stopped
+	}
+
+	// Chapter files are validated concurrently, and reported in order
+	valid := slices.DeleteFunc(slices.Clone(chapters), func(path string) bool { return orphanSet[path] })
+	checkChapters(valid, verseIndex, c.Workers, func(check chapterCheck) bool {
+		checked++
+		chapterPath, chapter := check.path, check.chapter
+		if check.err != nil {
+			report.error("validation", bookOf(c.Canon, chapterPath, objBooks), "Validation error in %s: %v", chapterPath, check.err)
+			return !failed() // Skip processing this chapter if validation failed
+		}
+
+		// A plain field that disagrees with its tokens is reported per verse rather than failing the
+		// chapter, and whitespace and entity artifacts can be regenerated from the tokens
+		plain.add(check.plain)
+		mismatches := check.mismatches
+		// Objects are named by their contents, so they are not rewritten in place
+		_, isObject := objBooks[chapterPath]
+		autofix := c.AutofixPlain && !isObject
+		if autofix {
+			fixed, err := fixPlain(chapterPath, chapter, mismatches)
+			if err != nil {
+				report.error("plain", chapter.OSIS, "Plain error: %v", err)
+			} else if len(fixed) > 0 {
+				rewritten[filepath.Clean(chapterPath)] = true
+				plain.Files++
+				plain.Fixed += len(fixed)
+				for _, m := range fixed {
+					fmt.Printf("Plain fixed: %s %d:%d (%s)\n", chapter.OSIS, chapter.Chapter, m.V, m.Kind)
+				}
+			}
+		}
+		for _, m := range mismatches {
+			if autofix && m.fixable() {
+				continue
+			}
+			report.error("plain", chapter.OSIS, "Plain error: %s %d:%d (%s) in %s: %s", chapter.OSIS, chapter.Chapter, m.V, m.Kind, chapterPath, m.Diff())
+		}
+
+		// Divine names outside nd markup and verses wholly in added words are only likely to be
+		// ingest losses, as the source can have either legitimately, so they are reported without
+		// failing the run
+		for _, suspect := range divine.Add(chapter) {
+			report.warning("divine-name", chapter.OSIS, "Divine name warning: %s in %s", suspect, chapterPath)
+		}
+		for _, suspect := range added.Add(chapter) {
+			report.warning("added-words", chapter.OSIS, "Added words warning: %s in %s", suspect, chapterPath)
+		}
+
+		val := bookChapterCounts[chapter.OSIS]
+		if val > 0 {
+			bookChapterCounts[chapter.OSIS] = val + 1
+		} else {
+			bookChapterCounts[chapter.OSIS] = 1
+		}
+		return !failed()
+	})
+	if stopped || failed() {
+		util.StopProgress(ctx)
+		fmt.Printf("Stopped at the first error after %d of %d chapter files (--fail-fast)\n", checked, len(chapters))
+		return c.summarize(nil, checked, report, plain, divine.Audit(), added.Audit())
+	}
+
+	// Chapters rewritten by --autofix-plain get new output checksums before they are checked
+	if len(rewritten) > 0 {
+		if err := updateFileMapChecksums(c.Canon, c.Indexes, fileMap, rewritten); err != nil {
+			report.error("filemap", "", "Filemap error: %v", err)
+		}
+		if err := refreshBookManifests(rewritten); err != nil {
+			report.error("book-manifest", "", "Book manifest error: %v", err)
+		}
+	}
+
+	partial := make(map[string]bool, len(c.PartialBook))
+	for _, osis := range c.PartialBook {
+		partial[model.CanonicalOSIS(osis)] = true
+	}
+
+	// Every directory under books/ belongs to a book in books.json and holds chNN.json files without gaps
+	if !someBooks {
+		dirProblems, err := checkBookDirs(c.Canon, books)
+		if err != nil {
+			report.error("layout", "", "Layout error: %v", err)
+		}
+		for _, problem := range dirProblems {
+			report.error("layout", "", "Layout error: %s", problem)
+		}
+	}
+	for _, book := range scope {
+		nameProblems, err := checkChapterNames(c.Canon, book.OSIS, partial[model.CanonicalOSIS(book.OSIS)])
+		if err != nil {
+			report.error("layout", book.OSIS, "Layout error: %v", err)
+		}
+		for _, problem := range nameProblems {
+			report.error("layout", book.OSIS, "Layout error: %s", problem)
+		}
+	}
+
+	inObjects := make(map[string]bool)
+	for _, osis := range objBooks {
+		inObjects[osis] = true
+	}
+	for _, book := range scope {
+		// objects.json lists the chapters of books written in the content-addressed layout
+		if inObjects[book.OSIS] {
+			continue
+		}
+		for _, problem := range checkBookManifest(c.Canon, book.OSIS) {
+			report.error("book-manifest", book.OSIS, "Book manifest error: %s", problem)
+		}
+	}
+
+	// filemap points to existing files whose checksums match the recorded values
+	inScope := make(map[string]bool, len(scope))
+	for _, book := range scope {
+		inScope[book.OSIS] = true
+	}
+	for _, raw := range fileMap.RawPaths() {
+		entry := fileMap.Files[raw]
+		book := bookOf(c.Canon, filepath.Join(c.Canon, entry.Output), objBooks)
+		if someBooks && !inScope[book] {
+			continue
+		}
+		path, found := resolveOutputPath(c.Canon, entry.Output)
+		if !found {
+			report.error("filemap", book, "Filemap error: file does not exist - %s", entry.Output)
+			continue
+		}
+
+		// Entries upgraded from the legacy flat filemap carry no checksum
+		if entry.OutputSHA256 == "" {
+			continue
+		}
+
+		actual, err := util.FileSHA256(path)
+		if err != nil {
+			report.error("filemap", book, "Filemap error: cannot read %s - %v", path, err)
+			continue
+		}
+		if actual != entry.OutputSHA256 {
+			report.error(
+				"filemap",
+				book,
+				"Filemap error: checksum mismatch for %s: recorded %s, got %s",
+				entry.Output,
+				entry.OutputSHA256,
+				actual,
+			)
+		}
+	}
+
+	for _, p := range checkObjects(c.Canon, objects, scope) {
+		report.error("objects", p.book, "Object error: %s", p.line)
+	}
+
+	for _, book := range scope {
+		if book.Chapters != bookChapterCounts[book.OSIS] {
+			// Partial books such as AddEsth (Esther Greek), which only has chapters 10-16 with
+			// non-contiguous verses, are expected to fall short, so a mismatch is not an error
+			if partial[model.CanonicalOSIS(book.OSIS)] {
+				continue
+			}
+			report.error(
+				"chapter-count",
+				book.OSIS,
+				"Chapter count mismatch for %s: expected %d, found %d",
+				book.Name,
+				book.Chapters,
+				bookChapterCounts[book.OSIS],
+			)
+		}
+	}
+
+	// The remaining checks span books, so a run over some books skips them
+	if someBooks {
+		util.StopProgress(ctx)
+		return c.summarize(scope, len(chapters), report, plain, divine.Audit(), added.Audit())
+	}
+	if failed() {
+		util.StopProgress(ctx)
+		fmt.Println("Stopped at the first error, skipping the checks that span books (--fail-fast)")
+		return c.summarize(nil, len(chapters), report, plain, divine.Audit(), added.Audit())
+	}
+
+	for _, problem := range checkTestaments(books) {
+		report.error("testament", "", "Testament error: %s", problem)
+	}
+
+	topicProblems, err := checkTopics(c.Canon, c.Indexes)
+	if err != nil {
+		report.error("topics", "", "Topics error: %v", err)
+	}
+	for _, problem := range topicProblems {
+		report.error("topics", "", "Topics error: %s", problem)
+	}
+
+	localeProblems, err := checkLocales(c.Indexes, books)
+	if err != nil {
+		report.error("locale", "", "Locale error: %v", err)
+	}
+	for _, problem := range localeProblems {
+		report.error("locale", "", "Locale error: %s", problem)
+	}
+
+	normalizationProblems, err := checkNormalization(c.Indexes)
+	if err != nil {
+		report.error("normalization", "", "Normalization error: %v", err)
+	}
+	for _, problem := range normalizationProblems {
+		report.error("normalization", "", "Normalization error: %s", problem)
+	}
+
+	chronologyProblems, err := checkChronology(c.Canon, c.Indexes)
+	if err != nil {
+		report.error("chronology", "", "Chronology error: %v", err)
+	}
+	for _, problem := range chronologyProblems {
+		report.error("chronology", "", "Chronology error: %s", problem)
+	}
+
+	alignProblems, err := checkAlignments(c.Canon, filepath.Join(c.Canon, "align"))
+	if err != nil {
+		report.error("alignment", "", "Alignment error: %v", err)
+	}
+	for _, problem := range alignProblems {
+		report.error("alignment", "", "Alignment error: %s", problem)
+	}
+
+	editionProblems, err := checkEditions(c.Canon, filepath.Join(c.Canon, "editions"))
+	if err != nil {
+		report.error("edition", "", "Edition error: %v", err)
+	}
+	for _, problem := range editionProblems {
+		report.error("edition", "", "Edition error: %s", problem)
+	}
+
+	util.StopProgress(ctx)
+	return c.summarize(nil, len(chapters), report, plain, divine.Audit(), added.Audit())
+}
+
+// summarize prints the totals of a verify canon run over the books in scope, or over every book
+// when scope is nil, and ends it with the exit status of the errors and warnings the gate counted
+func (c *CanonCmd) summarize(scope []model.BookMetadata, files int, report *canonReport, plain *plainStats, divine *analyze.DivineNameAudit, added *analyze.AddedWordAudit) error {
+	if err := report.Write(os.Stdout); err != nil {
+		return err
+	}
+	gate := report.gate
+	fmt.Println("========================================")
+	if scope != nil {
+		osis := make([]string, len(scope))
+		for i, book := range scope {
+			osis[i] = book.OSIS
+		}
+		fmt.Printf("Books: %s (testaments, topics, locales, normalization, chronology, alignments, and editions skipped)\n", strings.Join(osis, ", "))
+	}
+	fmt.Printf("Total Files Validated: %d\n", files)
+	fmt.Printf("Plain/Token Consistency: %s\n", plain)
+	fmt.Printf("Divine Names: %s\n", divine)
+	fmt.Printf("Added Words: %s\n", added)
+	if plain.Files > 0 {
+		fmt.Printf("Chapter Files Rewritten: %d\n", plain.Files)
+	}
+	fmt.Printf("Total Errors Found: %d\n", gate.Errors)
+	fmt.Printf("Total Warnings Found: %d\n", gate.Warnings)
+	if gate.Ignored > 0 {
+		fmt.Printf("Total Ignored: %d\n", gate.Ignored)
+	}
+	fmt.Println("========================================")
+
+	if err := gate.Err("validation"); err != nil {
+		return err
+	}
+	fmt.Println("Validation completed successfully with no errors")
+	return nil
+}
+
+// checkTestaments reports books.json entries whose testament is unknown or disagrees with the testament package
+func checkTestaments(books model.BooksData) []string {
+	var problems []string
+	for _, book := range books.Books {
+		t, err := testament.Parse(book.Testament)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", book.OSIS, err))
+			continue
+		}
+		if known, ok := testament.Of(book.OSIS); ok && known != t {
+			problems = append(problems, fmt.Sprintf("%s is listed under %s but belongs to %s", book.OSIS, t, known))
+		}
+	}
+	return problems
+}
+
+// resolveOutputPath locates a filemap output path, trying it as-is first (absolute and
+// repo-root relative paths) and then relative to the canon directory
+func resolveOutputPath(canonDir, path string) (string, bool) {
+	if _, err := os.Stat(path); err == nil {
+		return path, true
+	}
+
+	if !filepath.IsAbs(path) {
+		checkPath := filepath.Join(canonDir, path)
+		if _, err := os.Stat(checkPath); err == nil {
+			return checkPath, true
+		}
+	}
+
+	return "", false
+}
+
+// updateFileMapChecksums records new output checksums for the rewritten chapter files in
+// filemap.json. Entries without a checksum, upgraded from the legacy flat filemap, stay without one.
+func updateFileMapChecksums(canonDir, indexDir string, fileMap model.FileMap, rewritten map[string]bool) error {
+	for raw, entry := range fileMap.Files {
+		path, found := resolveOutputPath(canonDir, entry.Output)
+		if !found || !rewritten[filepath.Clean(path)] || entry.OutputSHA256 == "" {
+			continue
+		}
+		sum, err := util.FileSHA256(path)
+		if err != nil {
+			return err
+		}
+		entry.OutputSHA256 = sum
+		fileMap.Files[raw] = entry
+	}
+
+	data, err := json.MarshalIndent(fileMap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal filemap.json: %w", err)
+	}
+	if err := atomicfile.WriteFile(filepath.Join(indexDir, "filemap.json"), data, 0600); err != nil {
+		return fmt.Errorf("failed to write filemap.json: %w", err)
+	}
+	return nil
+}
+
+// bookOf returns the OSIS code of the book a file under the canon directory belongs to, or "" if
+// it is neither under books/ nor an object of objBooks (see objectBooks)
+func bookOf(canon, path string, objBooks map[string]string) string {
+	if osis, ok := objBooks[path]; ok {
+		return osis
+	}
+	rel, err := filepath.Rel(canon, path)
+	if err != nil {
+		return ""
+	}
+	return outputBook(rel)
+}
+
+// outputBook returns the OSIS code of the book a filemap output path such as books/Gen/ch01.json
+// belongs to, or "" if it is not under books/
+func outputBook(output string) string {
+	parts := strings.Split(filepath.ToSlash(output), "/")
+	if len(parts) < 3 || parts[0] != "books" {
+		return ""
+	}
+	return parts[1]
+}
+
+func loadFileMap(indexDir string) (model.FileMap, error) {
+	fileMapData, err := os.ReadFile(filepath.Join(indexDir, "filemap.json")) // nolint: gosec
+	if err != nil {
+		return model.FileMap{}, fmt.Errorf("failed to read filemap.json: %w", err)
+	}
+
+	fileMap, err := model.ParseFileMap(fileMapData)
+	if err != nil {
+		return fileMap, fmt.Errorf("failed to parse filemap.json: %w", err)
+	}
+
+	return fileMap, nil
+}
+
+// loadVerseIndex reads verses.json for its verse maps. Canons ingested before verses.json was
+// written get an empty index, so every chapter is expected to be numbered from 1.
+func loadVerseIndex(indexDir string) (model.VerseIndex, error) {
+	data, err := os.ReadFile(filepath.Join(indexDir, "verses.json")) // nolint: gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return model.NewVerseIndex(), nil
+	} else if err != nil {
+		return model.VerseIndex{}, fmt.Errorf("failed to read verses.json: %w", err)
+	}
+	verseIndex, err := model.ParseVerseIndex(data)
+	if err != nil {
+		return verseIndex, fmt.Errorf("failed to parse verses.json: %w", err)
+	}
+	return verseIndex, nil
+}
+
+func loadBooks(indexDir string) (model.BooksData, error) {
+	var books model.BooksData
+
+	booksData, err := os.ReadFile(filepath.Join(indexDir, "books.json")) // nolint: gosec
+	if err != nil {
+		return books, fmt.Errorf("failed to read books.json: %w", err)
+	}
+
+	if err := json.Unmarshal(booksData, &books); err != nil {
+		return books, fmt.Errorf("failed to parse books.json: %w", err)
+	}
+
+	return books, nil
+}
+
+func getCanonFiles(canonDir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(filepath.Join(canonDir, "books"), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// intro.json holds book introductions and book.json lists the chapters, neither is a chapter
+		name := info.Name()
+		if !info.IsDir() && isChapterFile(name) && name != "intro.json" && name != util.BookManifestName {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// isChapterFile reports whether name is a JSON document, or one compressed by ingest --compress
+func isChapterFile(name string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(name, compression.ForName(name).Ext()), ".json")
+}
+
+// validateChapterFile checks a chapter's schema, metadata, verses, and footnotes. Verses are
+// numbered from 1 without gaps unless verses.json records a verse map for the chapter, which they
+// must then follow exactly.
+func validateChapterFile(path string, verseIndex model.VerseIndex) (*model.Chapter, error) {
+	content, err := os.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if content, err = compression.Decompress(content); err != nil {
+		return nil, err
+	}
+
+	var chapterData model.Chapter
+	err = json.Unmarshal(content, &chapterData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	// validate schema version
+	if version := chapterData.Schema; version != 1 {
+		return nil, fmt.Errorf("invalid or missing schema version")
+	}
+
+	if chapterData.Verses == nil {
+		return nil, fmt.Errorf("missing verses field")
+	}
+	previousNum := 0
+	verseMap, mapped := verseIndex.VerseMap(chapterData.OSIS, chapterData.Chapter)
+	for _, verseData := range chapterData.Verses {
+		if mapped {
+			if err := validateVerseBasic(verseData); err != nil {
+				return nil, fmt.Errorf("verse validation failed: %w", err)
+			}
+		} else {
+			if err := validateVerse(verseData, &previousNum); err != nil {
+				return nil, fmt.Errorf("verse validation failed: %w", err)
+			}
+			previousNum = verseData.V
+		}
+	}
+	if mapped {
+		numbers := make([]int, len(chapterData.Verses))
+		for i, verse := range chapterData.Verses {
+			numbers[i] = verse.V
+		}
+		if !slices.Equal(numbers, verseMap) {
+			return nil, fmt.Errorf("verse numbers %v differ from the verse map %v in verses.json", numbers, verseMap)
+		}
+	}
+
+	if chapterData.Footnotes != nil {
+		if err := validateFootnotes(chapterData.Footnotes, chapterData.Verses); err != nil {
+			return nil, fmt.Errorf("footnote validation failed: %w", err)
+		}
+	}
+
+	if chapterData.Work == "" || chapterData.OSIS == "" || chapterData.Abbr == "" {
+		return nil, fmt.Errorf("missing required metadata fields")
+	}
+
+	if chapterData.Chapter < 1 {
+		return nil, fmt.Errorf("invalid chapter number: expected >= 1, got %d", chapterData.Chapter)
+	}
+
+	return &chapterData, nil
+}
+
+func validateVerse(verseData interface{}, previousNum *int) error {
+	verse, ok := verseData.(model.Verse)
+	if !ok {
+		return fmt.Errorf("invalid verse data format")
+	}
+
+	if verse.V <= 0 {
+		return fmt.Errorf("invalid or missing verse number")
+	}
+
+	if verse.V != *previousNum+1 {
+		return fmt.Errorf("non-contiguous verse numbers: expected %d, got %d", *previousNum+1, verse.V)
+	}
+
+	if verse.Tokens == nil {
+		return fmt.Errorf("missing tokens field in verse")
+	}
+
+	if verse.Plain == "" {
+		return fmt.Errorf("missing plain field in verse")
+	}
+
+	return nil
+}
+
+// validateVerseBasic validates basic verse properties without checking contiguous numbering
+// Used for chapters with a verse map, such as AddEsth 10, which starts at verse 4
+func validateVerseBasic(verseData interface{}) error {
+	verse, ok := verseData.(model.Verse)
+	if !ok {
+		return fmt.Errorf("invalid verse data format")
+	}
+
+	if verse.V <= 0 {
+		return fmt.Errorf("invalid or missing verse number")
+	}
+
+	if verse.Tokens == nil {
+		return fmt.Errorf("missing tokens field in verse")
+	}
+
+	if verse.Plain == "" {
+		return fmt.Errorf("missing plain field in verse")
+	}
+
+	return nil
+}
+
+// flatten concatenates a verse's tokens and normalizes the result with the same policy the
+// parser applies to the verse's plain text
+func flatten(tokens []model.Token) string {
+	var result strings.Builder
+	for _, token := range tokens {
+		result.WriteString(token.Text)
+		result.WriteString(token.Add)
+		result.WriteString(token.ND)
+	}
+	return normalize.Text(result.String())
+}
+
+func validateFootnotes(footnotes []model.Footnote, verses []model.Verse) error {
+	validVerses := make(map[int]bool)
+	for _, verse := range verses {
+		validVerses[verse.V] = true
+	}
+
+	seenIDs := make(map[string]bool)
+	for _, footnote := range footnotes {
+		if !validVerses[footnote.At.V] {
+			return fmt.Errorf("footnote %s references non-existent verse %d", footnote.ID, footnote.At.V)
+		}
+
+		if seenIDs[footnote.ID] {
+			return fmt.Errorf("duplicate footnote ID: %s", footnote.ID)
+		}
+		seenIDs[footnote.ID] = true
+	}
+
+	return nil
+}
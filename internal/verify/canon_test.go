@@ -0,0 +1,94 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestCheckTestaments(t *testing.T) {
+	books := model.BooksData{Books: []model.BookMetadata{
+		{OSIS: "Gen", Testament: "OT"},
+		{OSIS: "Tob", Testament: "AP"},
+		{OSIS: "Matt", Testament: "OT"},
+		{OSIS: "Sir", Testament: "DC"},
+		{OSIS: "Unknown", Testament: "NT"},
+	}}
+
+	problems := checkTestaments(books)
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %d: %v", len(problems), problems)
+	}
+	if !strings.Contains(problems[0], "Matt is listed under OT but belongs to NT") {
+		t.Errorf("unexpected problem: %s", problems[0])
+	}
+	if !strings.Contains(problems[1], `unknown testament "DC"`) {
+		t.Errorf("unexpected problem: %s", problems[1])
+	}
+}
+
+func TestValidateChapterFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(osis string, chapter int, verses ...int) string {
+		doc := model.Chapter{Schema: 1, Work: "KJV", OSIS: osis, Abbr: osis, Chapter: chapter}
+		for _, v := range verses {
+			doc.Verses = append(doc.Verses, model.Verse{V: v, Tokens: []model.Token{{Text: "text"}}, Plain: "text"})
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("%s-%v.json", osis, verses))
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	// compress replaces the chapter file at path with its zstd-compressed copy, as ingest --compress=zstd writes
+	compress := func(path string) string {
+		data, err := os.ReadFile(path) // nolint: gosec
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data, err = compression.Zstd.Compress(data); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path+".zst", data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path + ".zst"
+	}
+
+	verseIndex := model.NewVerseIndex()
+	verseIndex.SetVerses("AddEsth", 10, []int{4, 5, 6})
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "follows its verse map", path: write("AddEsth", 10, 4, 5, 6)},
+		{name: "differs from its verse map", path: write("AddEsth", 10, 4, 5), wantErr: "differ from the verse map"},
+		{name: "contiguous", path: write("Obad", 1, 1, 2, 3)},
+		{name: "gap without a verse map", path: write("Obad", 1, 1, 3), wantErr: "non-contiguous"},
+		{name: "compressed", path: compress(write("Obad", 1, 1, 2))},
+		{name: "compressed with a gap", path: compress(write("Obad", 1, 2)), wantErr: "non-contiguous"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateChapterFile(tt.path, verseIndex)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
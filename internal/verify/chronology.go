@@ -0,0 +1,77 @@
+package verify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// checkChronology reports chronological.json spans that name unknown books or chapters, chapters
+// listed more than once, and chapters of the canon it leaves out. A canon without
+// chronological.json has nothing to check.
+func checkChronology(canonDir, indexDir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(indexDir, "chronological.json")) // nolint: gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chronological.json: %w", err)
+	}
+
+	var chronology model.Chronology
+	if err := json.Unmarshal(data, &chronology); err != nil {
+		return nil, fmt.Errorf("failed to parse chronological.json: %w", err)
+	}
+	if chronology.Schema != model.ChronologySchema {
+		return []string{fmt.Sprintf("unsupported schema version %d", chronology.Schema)}, nil
+	}
+
+	corpus, err := kjvcorpus.Open(canonDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open canon: %w", err)
+	}
+	books := corpus.Table().ByOsis
+
+	var problems []string
+	listed := make(map[string]map[int]bool)
+	for _, span := range chronology.Passages {
+		book, exists := books[span.OSIS]
+		if !exists {
+			problems = append(problems, fmt.Sprintf("unknown book %q", span.OSIS))
+			continue
+		}
+		if span.Start < 1 || span.Start > span.End || span.End > book.Chapters {
+			problems = append(problems, fmt.Sprintf("%s %d-%d is not a span of chapters 1-%d", span.OSIS, span.Start, span.End, book.Chapters))
+			continue
+		}
+		if listed[span.OSIS] == nil {
+			listed[span.OSIS] = make(map[int]bool)
+		}
+		for chapter := span.Start; chapter <= span.End; chapter++ {
+			if listed[span.OSIS][chapter] {
+				problems = append(problems, fmt.Sprintf("%s %d is listed more than once", span.OSIS, chapter))
+			}
+			listed[span.OSIS][chapter] = true
+		}
+	}
+
+	for _, book := range corpus.BooksIn() {
+		var missing []string
+		for chapter := 1; chapter <= book.Chapters; chapter++ {
+			if !listed[book.OSIS][chapter] && corpus.HasChapter(book.OSIS, chapter) {
+				missing = append(missing, fmt.Sprint(chapter))
+			}
+		}
+		if len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("%s chapters %s are not listed", book.OSIS, strings.Join(missing, ", ")))
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,79 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckChronology(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+	canon := filepath.Join(cwd, "canon", "kjv")
+
+	// The committed chronology lists every chapter once
+	problems, err := checkChronology(canon, filepath.Join(canon, "index"))
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("expected no problems in the canon chronology, got %v, %v", problems, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(canon, "index", "chronological.json")) // nolint: gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Replace Genesis 12-50 with an unknown book, a span past Ruth 4, and a second Job 1
+	broken := strings.Replace(string(data), `"osis": "Gen",
+      "start": 12,
+      "end": 50`, `"osis": "Nope",
+      "start": 1,
+      "end": 1
+    },
+    {
+      "osis": "Ruth",
+      "start": 4,
+      "end": 5
+    },
+    {
+      "osis": "Job",
+      "start": 1,
+      "end": 1`, 1)
+	if broken == string(data) {
+		t.Fatal("failed to edit chronological.json")
+	}
+	indexDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(indexDir, "chronological.json"), []byte(broken), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err = checkChronology(canon, indexDir)
+	if err != nil {
+		t.Fatalf("checkChronology failed: %v", err)
+	}
+	want := []string{
+		`unknown book "Nope"`,
+		"Ruth 4-5 is not a span of chapters 1-4",
+		"Job 1 is listed more than once",
+		"Gen chapters 12, 13",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("expected %d problems, got %d: %v", len(want), len(problems), problems)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(problems[i], prefix) {
+			t.Errorf("problem %d: expected prefix %q, got %q", i, prefix, problems[i])
+		}
+	}
+}
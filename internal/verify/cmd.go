@@ -0,0 +1,49 @@
+package verify
+
+import "time"
+
+type RawCmd struct {
+	Raw       string   `type:"existingdir" help:"The raw HTML source directory"                                    default:"./raw"`
+	Structure bool     `                   help:"Also parse every raw HTML file and check its structure"       default:"false"`
+	Rebase    string   `                   help:"Raw root a version 1 manifest was generated from, mapped onto --raw"`
+	Except    []string `                   help:"Known structure issues to accept, as FILE:RULE (e.g. GEN00.htm:class)"`
+}
+
+type CanonCmd struct {
+	Canon            string   `type:"existingdir" help:"The output directory for processed files"                                         default:"./canon/kjv"`
+	Indexes          string   `type:"existingdir" help:"The index directory containing metadata files"                                    default:"./canon/kjv/index"`
+	Books            string   `                   help:"Only verify these books: ot, ap, nt, a group (e.g. gospels), or a list (Gen,Exod)" aliases:"book"`
+	Prune            bool     `                   help:"Delete orphaned and stale chapter files instead of reporting"                      default:"false"`
+	PartialBook      []string `                   help:"Books (OSIS) whose source carries fewer chapters than books.json"                  default:"AddEsth"`
+	AutofixPlain     bool     `                   help:"Regenerate plain text from tokens where they differ only by whitespace or entities" default:"false"`
+	Workers          int      `                   help:"Chapter files to validate at once; 0 uses one per CPU"                             default:"0"`
+	FailFast         bool     `                   help:"Stop at the first error, skipping the files and checks after it"                   default:"false"`
+	MaxOutput        int      `                   help:"Most errors and warnings to print as they are found; 0 prints all"                 default:"0"`
+	WarningsAsErrors bool     `                   help:"Exit with status 2 when a run has warnings but no errors"                          default:"false"`
+	Ignore           []string `                   help:"Error and warning codes left out of the exit status (e.g. plain,divine-name)"`
+}
+
+type UpstreamCmd struct {
+	Raw     string        `type:"existingdir" help:"The raw HTML source directory"                                                  default:"./raw"`
+	URL     string        `                   help:"URL of the upstream eBible HTML archive"                                        default:"https://ebible.org/Scriptures/eng-kjv_html.zip"`
+	Head    bool          `                   help:"Only compare the upstream Last-Modified header to the manifest, as a heuristic" default:"false"`
+	Timeout time.Duration `                   help:"Maximum time to spend contacting upstream"                                      default:"2m"`
+}
+
+type CompareCmd struct {
+	Canon             string        `type:"existingdir" help:"The canon directory containing index/ and books/"                                  default:"./canon/kjv"`
+	Against           string        `                   help:"Reference dataset of verse texts, as a CSV or TSV file path or an http(s) URL"     required:""`
+	Threshold         int           `                   help:"Greatest edit distance between verse texts not reported as a difference"          default:"0"`
+	IgnoreCase        bool          `                   help:"Compare verse texts case-insensitively"                                             default:"false"`
+	IgnorePunctuation bool          `                   help:"Leave punctuation out of the comparison"                                           default:"false"`
+	Limit             int           `                   help:"Most differences to print; 0 prints every difference"                              default:"50"`
+	Timeout           time.Duration `                   help:"Maximum time to spend downloading a reference URL"                                 default:"2m"`
+}
+
+// Cmd checks the raw sources, the canon, and the upstream archive
+type Cmd struct {
+	Raw      RawCmd      `cmd:"" help:"Validate raw HTML chapter files for structure and content correctness"`
+	Canon    CanonCmd    `cmd:"" help:"Validate processed canon files for structure and content correctness"`
+	Upstream UpstreamCmd `cmd:"" help:"Check whether the upstream eBible source has been revised since the raw manifest"`
+	Compare  CompareCmd  `cmd:"" help:"Compare the canon's verse texts against an independent reference dataset"`
+}
@@ -0,0 +1,420 @@
+package verify
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+func (c *CompareCmd) Run(ctx context.Context) error {
+	corpus, err := kjvcorpus.Open(c.Canon)
+	if err != nil {
+		util.StopProgress(ctx)
+		return fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+	data, err := loadReference(ctx, c.Against)
+	if err != nil {
+		util.StopProgress(ctx)
+		return err
+	}
+
+	comma := ','
+	if strings.EqualFold(filepath.Ext(c.Against), ".tsv") {
+		comma = '\t'
+	}
+	reference, err := readReference(bytes.NewReader(data), comma, corpus)
+	if err != nil {
+		util.StopProgress(ctx)
+		return fmt.Errorf("failed to read %s: %w", c.Against, err)
+	}
+
+	comparison, err := compareCanon(corpus, reference, compareOptions{
+		threshold:         c.Threshold,
+		ignoreCase:        c.IgnoreCase,
+		ignorePunctuation: c.IgnorePunctuation,
+	})
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, diff := range comparison.Differences {
+		if c.Limit > 0 && i == c.Limit {
+			fmt.Printf("... %d more differences (raise --limit to see them)\n", len(comparison.Differences)-c.Limit)
+			break
+		}
+		fmt.Printf("Difference: %s (distance %d)\n", diff.Ref, diff.Distance)
+		fmt.Printf("  canon:     %s\n", diff.Canon)
+		fmt.Printf("  reference: %s\n", diff.Reference)
+	}
+	for _, ref := range comparison.MissingFromCanon {
+		fmt.Printf("Missing from canon: %s\n", ref)
+	}
+	for _, row := range reference.unmatched {
+		fmt.Printf("Unmatched reference row: %s\n", row)
+	}
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Reference: %s\n", c.Against)
+	fmt.Printf("Books Compared: %d\n", comparison.Books)
+	fmt.Printf("Verses Compared: %d\n", comparison.Compared)
+	fmt.Printf("Identical: %d\n", comparison.Identical)
+	fmt.Printf("Within Threshold (distance <= %d): %d\n", c.Threshold, comparison.Minor)
+	fmt.Printf("Differences: %d\n", len(comparison.Differences))
+	if len(comparison.Differences) > 0 {
+		fmt.Printf("Differences By Book: %s\n", comparison.byBook())
+	}
+	fmt.Printf("Missing From Canon: %d\n", len(comparison.MissingFromCanon))
+	fmt.Printf("Missing From Reference: %d\n", comparison.MissingFromReference)
+	fmt.Printf("Unmatched Rows: %d\n", len(reference.unmatched))
+	fmt.Printf("========================================\n")
+
+	if n := len(comparison.Differences) + len(comparison.MissingFromCanon); n > 0 {
+		return util.Exit(util.ExitErrors, fmt.Errorf("canon differs from the reference in %d verses", n))
+	}
+	fmt.Println("✓ Canon matches the reference")
+	return nil
+}
+
+// loadReference reads a reference dataset from a file, or downloads it from an http(s) URL
+func loadReference(ctx context.Context, against string) ([]byte, error) {
+	if !strings.HasPrefix(against, "http://") && !strings.HasPrefix(against, "https://") {
+		data, err := os.ReadFile(against) // nolint: gosec
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", against, err)
+		}
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, against, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", against, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Printf("Error closing response body: %v\n", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", against, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", against, err)
+	}
+	return data, nil
+}
+
+// referenceVerse is a verse of a reference dataset
+type referenceVerse struct {
+	osis    string
+	chapter int
+	verse   int
+	text    string
+}
+
+// referenceData is a reference dataset read against the canon
+type referenceData struct {
+	verses    []referenceVerse
+	unmatched []string // rows naming no verse of the canon, as "line 12: ..."
+}
+
+// referenceColumns are the header names each column of a reference dataset may have, compared
+// in lowercase with underscores as spaces
+var referenceColumns = map[string][]string{
+	"book":      {"book", "book name", "osis", "b"},
+	"number":    {"book number", "book id"},
+	"chapter":   {"chapter", "chapter number", "c"},
+	"verse":     {"verse", "verse number", "v"},
+	"text":      {"text", "verse text", "scripture", "t"},
+	"reference": {"reference", "ref", "citation"},
+}
+
+// readReference reads a reference dataset: delimited rows with a header naming a text column and
+// either a reference column, such as "Genesis 1:1", or book, chapter, and verse columns. Books
+// are matched by name, OSIS code, or alias, or by number in the 66-book Protestant order.
+func readReference(r io.Reader, comma rune, corpus *kjvcorpus.Corpus) (*referenceData, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(strings.TrimPrefix(name, "\ufeff"), "_", " ")))
+		for column, names := range referenceColumns {
+			if _, found := columns[column]; !found && slices.Contains(names, name) {
+				columns[column] = i
+			}
+		}
+	}
+	_, hasRef := columns["reference"]
+	_, hasBook := columns["book"]
+	_, hasNumber := columns["number"]
+	_, hasChapter := columns["chapter"]
+	_, hasVerse := columns["verse"]
+	if _, ok := columns["text"]; !ok || !(hasRef || (hasBook || hasNumber) && hasChapter && hasVerse) {
+		return nil, errors.New("header needs a text column and either a reference column or book, chapter, and verse columns")
+	}
+
+	protestant := corpus.BooksIn(testament.OT, testament.NT)
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	data := &referenceData{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		unmatched := func(reason string) {
+			data.unmatched = append(data.unmatched, fmt.Sprintf("line %d: %s", line, reason))
+		}
+
+		verse := referenceVerse{text: field(record, "text")}
+		if hasRef && field(record, "reference") != "" {
+			ref, err := corpus.ParseRef(field(record, "reference"))
+			if err != nil || ref.Verses == nil || ref.Verses.End != 0 {
+				unmatched(fmt.Sprintf("not a verse reference: %q", field(record, "reference")))
+				continue
+			}
+			verse.osis, verse.chapter, verse.verse = ref.OSIS, ref.Chapter, ref.Verses.Start
+		} else {
+			name, number := field(record, "book"), field(record, "number")
+			osis, ok := referenceBook(corpus, protestant, name, number)
+			if !ok {
+				unmatched(fmt.Sprintf("unknown book %q", cmp.Or(name, number)))
+				continue
+			}
+			verse.osis = osis
+			chapter, err1 := strconv.Atoi(field(record, "chapter"))
+			v, err2 := strconv.Atoi(field(record, "verse"))
+			if err1 != nil || err2 != nil {
+				unmatched(fmt.Sprintf("chapter and verse are not numbers: %q, %q", field(record, "chapter"), field(record, "verse")))
+				continue
+			}
+			verse.chapter, verse.verse = chapter, v
+		}
+		data.verses = append(data.verses, verse)
+	}
+	return data, nil
+}
+
+// referenceBook finds the book of a reference row by name, OSIS code, or alias, or by its number
+// in the 66-book Protestant order when the row gives a number
+func referenceBook(corpus *kjvcorpus.Corpus, protestant []bibleref.Book, name, number string) (string, bool) {
+	if _, err := strconv.Atoi(name); name != "" && err != nil {
+		book, ok := corpus.LookupBook(name)
+		return book.OSIS, ok
+	}
+	n, err := strconv.Atoi(cmp.Or(name, number))
+	if err != nil || n < 1 || n > len(protestant) {
+		return "", false
+	}
+	return protestant[n-1].OSIS, true
+}
+
+// compareOptions control how verse texts are compared
+type compareOptions struct {
+	threshold         int  // greatest edit distance between texts that is not reported as a difference
+	ignoreCase        bool // compare texts case-insensitively, as for a reference without small capitals
+	ignorePunctuation bool // leave punctuation out of the comparison
+}
+
+// Comparison is the result of comparing the canon's verse texts against a reference dataset
+type Comparison struct {
+	Books                int // books of the canon the reference has verses of
+	Compared             int // verses in both
+	Identical            int // verses whose texts match once normalized
+	Minor                int // verses whose texts differ by no more than the threshold
+	Differences          []VerseDifference
+	MissingFromCanon     []string // references of reference verses the canon lacks, in reference order
+	MissingFromReference int      // verses of the compared books the reference lacks
+}
+
+// VerseDifference is a verse whose texts differ by more than the threshold
+type VerseDifference struct {
+	Ref       string // such as "Gen 1:1"
+	OSIS      string
+	Distance  int // Levenshtein distance between the normalized texts, in characters
+	Canon     string
+	Reference string
+}
+
+// compareCanon compares each verse of the books the reference covers with its text in the
+// reference, after normalizing both: curly quotes are made straight, brackets and pilcrows are
+// dropped, and spacing is collapsed
+func compareCanon(corpus *kjvcorpus.Corpus, reference *referenceData, opts compareOptions) (*Comparison, error) {
+	type key struct {
+		osis           string
+		chapter, verse int
+	}
+	texts := make(map[key]string, len(reference.verses))
+	books := make(map[string]bool)
+	for _, verse := range reference.verses {
+		texts[key{verse.osis, verse.chapter, verse.verse}] = verse.text
+		books[verse.osis] = true
+	}
+
+	comparison := &Comparison{}
+	seen := make(map[string]bool)
+	for chapter, err := range corpus.Chapters() {
+		if err != nil {
+			return nil, err
+		}
+		if !books[chapter.OSIS] {
+			continue
+		}
+		if !seen[chapter.OSIS] {
+			seen[chapter.OSIS] = true
+			comparison.Books++
+		}
+		for _, verse := range chapter.Verses {
+			k := key{chapter.OSIS, chapter.Chapter, verse.V}
+			text, ok := texts[k]
+			if !ok {
+				comparison.MissingFromReference++
+				continue
+			}
+			delete(texts, k)
+			comparison.Compared++
+
+			canon, ref := normalizeForCompare(verse.Plain, opts), normalizeForCompare(text, opts)
+			if canon == ref {
+				comparison.Identical++
+				continue
+			}
+			distance := levenshtein(canon, ref)
+			if distance <= opts.threshold {
+				comparison.Minor++
+				continue
+			}
+			comparison.Differences = append(comparison.Differences, VerseDifference{
+				Ref:       fmt.Sprintf("%s %d:%d", chapter.OSIS, chapter.Chapter, verse.V),
+				OSIS:      chapter.OSIS,
+				Distance:  distance,
+				Canon:     verse.Plain,
+				Reference: text,
+			})
+		}
+	}
+
+	for _, verse := range reference.verses {
+		if _, missing := texts[key{verse.osis, verse.chapter, verse.verse}]; missing {
+			comparison.MissingFromCanon = append(comparison.MissingFromCanon, fmt.Sprintf("%s %d:%d", verse.osis, verse.chapter, verse.verse))
+			delete(texts, key{verse.osis, verse.chapter, verse.verse})
+		}
+	}
+	return comparison, nil
+}
+
+// byBook formats the number of differences in each book, most first, as "Ps 12, Gen 3", so
+// differences concentrated in a few books stand out
+func (c *Comparison) byBook() string {
+	counts := make(map[string]int)
+	var order []string
+	for _, diff := range c.Differences {
+		if counts[diff.OSIS] == 0 {
+			order = append(order, diff.OSIS)
+		}
+		counts[diff.OSIS]++
+	}
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	parts := make([]string, len(order))
+	for i, osis := range order {
+		parts[i] = fmt.Sprintf("%s %d", osis, counts[osis])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// normalizeForCompare reduces a verse text to the form it is compared in
+func normalizeForCompare(text string, opts compareOptions) string {
+	var b strings.Builder
+	space := false
+	for _, r := range text {
+		switch r {
+		case '‘', '’':
+			r = '\''
+		case '“', '”':
+			r = '"'
+		case '[', ']', '¶':
+			continue
+		}
+		if opts.ignorePunctuation && unicode.IsPunct(r) {
+			continue
+		}
+		if unicode.IsSpace(r) {
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		if opts.ignoreCase {
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// levenshtein returns the number of single-character insertions, deletions, and substitutions
+// that turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
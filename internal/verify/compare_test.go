@@ -0,0 +1,149 @@
+package verify
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+func TestCompareCanon(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+	corpus, err := kjvcorpus.Open(filepath.Join(cwd, "canon", "kjv"))
+	if err != nil {
+		t.Fatalf("failed to open canon: %v", err)
+	}
+	obadiah, err := corpus.Resolve(&bibleref.BibleRef{OSIS: "Obad", Chapter: 1})
+	if err != nil {
+		t.Fatalf("failed to resolve Obadiah: %v", err)
+	}
+
+	// Obadiah is book 31 of the Protestant canon. Verse 1 differs by a character, verse 2 only in
+	// brackets and quotes, verse 3 by a whole clause; verse 21 is left out, and verse 22 and a row
+	// for book 99 are not in the canon.
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	_ = w.Write([]string{"b", "c", "v", "t"})
+	for _, verse := range obadiah.Chapter.Verses {
+		text := verse.Plain
+		switch verse.V {
+		case 1:
+			text = strings.Replace(text, "Obadiah", "Obadyah", 1)
+		case 2:
+			text = "[" + strings.ReplaceAll(text, " ", "  ") + "]"
+		case 3:
+			text = "The pride of thine heart hath deceived thee."
+		case 21:
+			continue
+		}
+		_ = w.Write([]string{"31", "1", strconv.Itoa(verse.V), text})
+	}
+	_ = w.Write([]string{"31", "1", "22", "An extra verse."})
+	_ = w.Write([]string{"99", "1", "1", "No such book."})
+	w.Flush()
+
+	reference, err := readReference(strings.NewReader(b.String()), ',', corpus)
+	if err != nil {
+		t.Fatalf("readReference failed: %v", err)
+	}
+	if len(reference.unmatched) != 1 || !strings.Contains(reference.unmatched[0], `line 23: unknown book "99"`) {
+		t.Errorf("unexpected unmatched rows: %v", reference.unmatched)
+	}
+
+	comparison, err := compareCanon(corpus, reference, compareOptions{threshold: 2})
+	if err != nil {
+		t.Fatalf("compareCanon failed: %v", err)
+	}
+	if comparison.Books != 1 || comparison.Compared != 20 || comparison.Identical != 18 || comparison.Minor != 1 {
+		t.Errorf("unexpected counts: %+v", comparison)
+	}
+	if len(comparison.Differences) != 1 || comparison.Differences[0].Ref != "Obad 1:3" {
+		t.Fatalf("unexpected differences: %+v", comparison.Differences)
+	}
+	if comparison.MissingFromReference != 1 {
+		t.Errorf("expected 1 verse missing from the reference, got %d", comparison.MissingFromReference)
+	}
+	if len(comparison.MissingFromCanon) != 1 || comparison.MissingFromCanon[0] != "Obad 1:22" {
+		t.Errorf("unexpected verses missing from canon: %v", comparison.MissingFromCanon)
+	}
+	if got := comparison.byBook(); got != "Obad 1" {
+		t.Errorf("byBook = %q", got)
+	}
+
+	// With no threshold the one-character difference is reported too
+	comparison, err = compareCanon(corpus, reference, compareOptions{})
+	if err != nil {
+		t.Fatalf("compareCanon failed: %v", err)
+	}
+	if len(comparison.Differences) != 2 || comparison.Differences[0].Ref != "Obad 1:1" || comparison.Differences[0].Distance != 1 {
+		t.Errorf("unexpected differences: %+v", comparison.Differences)
+	}
+
+	// References and book names, tab-separated
+	tsv := "Reference\tText\nObadiah 1:1\t" + obadiah.Chapter.Verses[0].Plain + "\nObad 1:2-3\tTwo verses.\n"
+	reference, err = readReference(strings.NewReader(tsv), '\t', corpus)
+	if err != nil {
+		t.Fatalf("readReference failed: %v", err)
+	}
+	if len(reference.verses) != 1 || reference.verses[0].osis != "Obad" || len(reference.unmatched) != 1 {
+		t.Errorf("unexpected reference: %+v", reference)
+	}
+
+	if _, err := readReference(strings.NewReader("id,content\n1,text\n"), ',', corpus); err == nil {
+		t.Error("expected an error for a header without the needed columns")
+	}
+}
+
+func TestNormalizeForCompare(t *testing.T) {
+	tests := []struct {
+		text string
+		opts compareOptions
+		want string
+	}{
+		{"¶ And  God said, Let there [be] light:", compareOptions{}, "And God said, Let there be light:"},
+		{"the LORD’s “house”", compareOptions{}, `the LORD's "house"`},
+		{"the LORD’s house.", compareOptions{ignoreCase: true, ignorePunctuation: true}, "the lords house"},
+	}
+	for _, tt := range tests {
+		if got := normalizeForCompare(tt.text, tt.opts); got != tt.want {
+			t.Errorf("normalizeForCompare(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"kitten", "sitting", 3},
+		{"shew", "show", 1},
+		{"", "abc", 3},
+		{"Naomi’s", "Naomi's", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
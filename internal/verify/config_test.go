@@ -0,0 +1,107 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func TestConfiguration(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+
+	// Navigate to project root, where the default directories exist
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+	t.Chdir(cwd)
+
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "toml",
+			file: "kjv.toml",
+			content: `
+[verify]
+prune = true
+
+[verify.canon]
+canon = "` + dir + `"
+indexes = "` + dir + `"
+partial-book = ["Add Esth", "Obad"]
+
+[verify.raw]
+raw = "` + dir + `"
+except = ["GEN00.htm:class"]
+`,
+		},
+		{
+			name: "yaml",
+			file: "kjv.yaml",
+			content: `
+verify:
+  prune: true
+  canon:
+    canon: "` + dir + `"
+    indexes: "` + dir + `"
+    partial_book: ["Add Esth", "Obad"]
+  raw:
+    raw: "` + dir + `"
+    except: ["GEN00.htm:class"]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv(util.ConfigEnv, path)
+
+			var cli Cmd
+			parser, err := kong.New(&cli, util.Configuration("verify"))
+			if err != nil {
+				t.Fatalf("failed to create parser: %v", err)
+			}
+
+			if _, err := parser.Parse([]string{"canon"}); err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if !cli.Canon.Prune || cli.Canon.Canon != dir {
+				t.Errorf("expected settings from config, got %+v", cli.Canon)
+			}
+			if strings.Join(cli.Canon.PartialBook, ",") != "Add Esth,Obad" {
+				t.Errorf("expected partial books from config, got %v", cli.Canon.PartialBook)
+			}
+
+			// Flags on the command line take precedence
+			if _, err := parser.Parse([]string{"raw", "--except=*:verses"}); err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if strings.Join(cli.Raw.Except, ",") != "*:verses" {
+				t.Errorf("expected command-line exceptions, got %v", cli.Raw.Except)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+// checkEditions reports edition layers under editionsDir ({edition}/{OSIS}/chNN.json) that do not
+// parse or no longer match the verse structure of their chapter. A canon without editions/ has
+// nothing to check.
+func checkEditions(canonDir, editionsDir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(editionsDir, "*", "*", "*"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	sort.Strings(files)
+
+	corpus, err := kjvcorpus.Open(canonDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	var problems []string
+	for _, file := range files {
+		name, _ := filepath.Rel(editionsDir, file)
+		edition := filepath.Base(filepath.Dir(filepath.Dir(file)))
+		osis := filepath.Base(filepath.Dir(file))
+		match := alignmentFile.FindStringSubmatch(filepath.Base(file))
+		if match == nil {
+			problems = append(problems, fmt.Sprintf("%s: not an edition file (expected chNN.json)", name))
+			continue
+		}
+		chapter, _ := strconv.Atoi(match[1])
+
+		data, err := os.ReadFile(file) // nolint: gosec
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		layer, err := kjvcorpus.ParseEdition(data)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if layer.Edition != edition {
+			problems = append(problems, fmt.Sprintf("%s: layer is for the %s edition", name, layer.Edition))
+		}
+		resolved, err := corpus.Resolve(&bibleref.BibleRef{OSIS: osis, Chapter: chapter})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: no such chapter: %v", name, err))
+			continue
+		}
+		if err := kjvcorpus.ValidateEdition(&resolved.Chapter, layer); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,70 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckEditions(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+	canon := filepath.Join(cwd, "canon", "kjv")
+
+	// A missing editions/ directory has nothing to check
+	problems, err := checkEditions(canon, filepath.Join(t.TempDir(), "editions"))
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("expected no problems without editions/, got %v, %v", problems, err)
+	}
+
+	editionsDir := t.TempDir()
+	files := map[string]string{
+		"1611/Ps/ch117.json": `{"schema": 1, "edition": "1611", "osis": "Ps", "chapter": 117, "verses": [{"v": 1, "plain": "O praise the LORD, all yee nations"}, {"v": 2, "plain": "For his mercifull kindnesse"}]}`,
+		"1611/Ps/ch118.json": `{"schema": 1, "edition": "1611", "osis": "Ps", "chapter": 118, "verses": [{"v": 1, "plain": "O giue thankes"}]}`,
+		"1611/Ps/ch119.json": `{"schema": 2}`,
+		"1560/Ps/ch117.json": `{"schema": 1, "edition": "1611", "osis": "Ps", "chapter": 117, "verses": [{"v": 1, "plain": "O"}, {"v": 2, "plain": "For"}]}`,
+	}
+	for name, data := range files {
+		path := filepath.Join(editionsDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	problems, err = checkEditions(canon, editionsDir)
+	if err != nil {
+		t.Fatalf("checkEditions failed: %v", err)
+	}
+	want := []string{
+		filepath.Join("1560", "Ps", "ch117.json") + ": layer is for the 1611 edition",
+		filepath.Join("1611", "Ps", "ch118.json") + ": ",
+		filepath.Join("1611", "Ps", "ch119.json") + ": unsupported edition schema version 2",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("expected %d problems, got %d: %v", len(want), len(problems), problems)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(problems[i], prefix) {
+			t.Errorf("problem %d: expected prefix %q, got %q", i, prefix, problems[i])
+		}
+	}
+	if !strings.Contains(problems[1], "verse 2 is missing") {
+		t.Errorf("expected the missing verse to be reported, got %q", problems[1])
+	}
+}
@@ -0,0 +1,112 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// checkBookDirs reports entries under books/ that are not the directory of a book in books.json,
+// such as typos, directories named after an alias instead of the OSIS code, and leftover experiments
+func checkBookDirs(canonDir string, books model.BooksData) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(canonDir, "books"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read books directory: %w", err)
+	}
+
+	known := make(map[string]bool, len(books.Books))
+	for _, book := range books.Books {
+		known[book.OSIS] = true
+	}
+
+	var problems []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() {
+			problems = append(problems, fmt.Sprintf("books/%s is not a book directory", name))
+			continue
+		}
+		if known[name] {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("books/%s does not match any OSIS code in books.json%s", name, dirHint(name, books)))
+	}
+	return problems, nil
+}
+
+// dirHint suggests the book an unknown directory name was probably meant for
+func dirHint(name string, books model.BooksData) string {
+	for _, book := range books.Books {
+		if model.CanonicalOSIS(name) == model.CanonicalOSIS(book.OSIS) {
+			return fmt.Sprintf("; it is the spaced form of %s, run kjvsrc migrate osis", book.OSIS)
+		}
+		if strings.EqualFold(name, book.OSIS) || strings.EqualFold(name, book.Abbr) || strings.EqualFold(name, book.Name) {
+			return fmt.Sprintf("; expected books/%s", book.OSIS)
+		}
+		for _, alias := range book.Aliases {
+			if strings.EqualFold(name, alias) {
+				return fmt.Sprintf("; %q is an alias of %s, expected books/%s", alias, book.OSIS, book.OSIS)
+			}
+		}
+	}
+	return ""
+}
+
+// checkChapterNames reports files in a book directory that are neither chNN.json chapters, with
+// the two-digit padding ingest writes, nor intro.json or book.json, and chapter numbers missing
+// between the first chapter and the last. Chapters are numbered from 1 unless the book is partial,
+// such as AddEsth, whose source starts at chapter 10.
+func checkChapterNames(canonDir, osis string, partial bool) ([]string, error) {
+	bookDir := filepath.Join(canonDir, "books", osis)
+	entries, err := os.ReadDir(bookDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", bookDir, err)
+	}
+
+	var problems []string
+	var numbers []int
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == "intro.json" || name == util.BookManifestName {
+			continue
+		}
+		chapter, err := chapterFromFilename(name)
+		if entry.IsDir() || err != nil || chapter < 1 || name != fmt.Sprintf("ch%02d.json", chapter)+compression.ForName(name).Ext() {
+			problems = append(problems, fmt.Sprintf("books/%s/%s is not a chNN.json chapter file", osis, name))
+			continue
+		}
+		numbers = append(numbers, chapter)
+	}
+	if len(numbers) == 0 {
+		return problems, nil
+	}
+
+	sort.Ints(numbers)
+	next := 1
+	if partial {
+		next = numbers[0]
+	}
+	for _, n := range numbers {
+		if n > next {
+			problems = append(problems, fmt.Sprintf("books/%s is missing %s", osis, chapterRange(next, n-1)))
+		}
+		next = n + 1
+	}
+	return problems, nil
+}
+
+// chapterRange names the chapter files from first to last, e.g. ch04.json or ch04.json-ch06.json
+func chapterRange(first, last int) string {
+	if first == last {
+		return fmt.Sprintf("ch%02d.json", first)
+	}
+	return fmt.Sprintf("ch%02d.json-ch%02d.json", first, last)
+}
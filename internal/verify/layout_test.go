@@ -0,0 +1,68 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestCheckLayout(t *testing.T) {
+	canonDir := t.TempDir()
+	for _, name := range []string{
+		"Gen/ch01.json", "Gen/ch02.json", "Gen/ch04.json.zst", "Gen/ch05.json", "Gen/ch06.json.br",
+		"Gen/ch3.json", "Gen/notes.json", "Gen/book.json",
+		"AddEsth/ch10.json", "AddEsth/ch11.json",
+		"Genesis/ch01.json", "1 Sam/ch01.json", "Gen-old/ch01.json", "README.md",
+	} {
+		path := filepath.Join(canonDir, "books", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	books := model.BooksData{Books: []model.BookMetadata{
+		{OSIS: "Gen", Abbr: "GEN", Name: "Genesis", Aliases: []string{"Genesis"}},
+		{OSIS: "1Sam", Abbr: "1SA", Name: "1 Samuel", Aliases: []string{"1 Samuel"}},
+		{OSIS: "AddEsth", Abbr: "ESG", Name: "Esther (Greek)"},
+	}}
+
+	problems, err := checkBookDirs(canonDir, books)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"books/1 Sam does not match any OSIS code in books.json; it is the spaced form of 1Sam, run kjvsrc migrate osis",
+		"books/Gen-old does not match any OSIS code in books.json",
+		"books/Genesis does not match any OSIS code in books.json; expected books/Gen",
+		"books/README.md is not a book directory",
+	}
+	if strings.Join(problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected directory problems:\n%s", strings.Join(problems, "\n"))
+	}
+
+	problems, err = checkChapterNames(canonDir, "Gen", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		"books/Gen/ch06.json.br is not a chNN.json chapter file",
+		"books/Gen/ch3.json is not a chNN.json chapter file",
+		"books/Gen/notes.json is not a chNN.json chapter file",
+		"books/Gen is missing ch03.json",
+	}
+	if strings.Join(problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected chapter name problems:\n%s", strings.Join(problems, "\n"))
+	}
+
+	if problems, _ := checkChapterNames(canonDir, "AddEsth", true); len(problems) != 0 {
+		t.Errorf("expected a partial book to start at its first chapter, got %v", problems)
+	}
+	if problems, _ := checkChapterNames(canonDir, "AddEsth", false); len(problems) != 1 || !strings.Contains(problems[0], "ch01.json-ch09.json") {
+		t.Errorf("expected a gap before chapter 10, got %v", problems)
+	}
+}
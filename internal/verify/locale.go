@@ -0,0 +1,58 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// checkLocales reports locale files in index/locales whose schema is unsupported, whose lang does
+// not match the file name, or that name books missing from books.json or give a book an empty
+// name. A canon without locales has nothing to check.
+func checkLocales(indexDir string, books model.BooksData) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(indexDir, "locales", "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list locales: %w", err)
+	}
+
+	known := make(map[string]bool, len(books.Books))
+	for _, book := range books.Books {
+		known[book.OSIS] = true
+	}
+
+	var problems []string
+	for _, path := range paths {
+		file := filepath.Base(path)
+		data, err := os.ReadFile(path) // nolint: gosec
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		locale, err := model.ParseLocale(data)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		if lang := strings.TrimSuffix(file, ".json"); locale.Lang != lang {
+			problems = append(problems, fmt.Sprintf("%s: lang %q does not match the file name", file, locale.Lang))
+		}
+
+		osisCodes := make([]string, 0, len(locale.Books))
+		for osis := range locale.Books {
+			osisCodes = append(osisCodes, osis)
+		}
+		sort.Strings(osisCodes)
+		for _, osis := range osisCodes {
+			if !known[osis] {
+				problems = append(problems, fmt.Sprintf("%s: unknown book %s", file, osis))
+			} else if strings.TrimSpace(locale.Books[osis]) == "" {
+				problems = append(problems, fmt.Sprintf("%s: empty name for %s", file, osis))
+			}
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,53 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestCheckLocales(t *testing.T) {
+	books := model.BooksData{Books: []model.BookMetadata{{OSIS: "Gen"}, {OSIS: "John"}}}
+
+	// A canon without locales has nothing to check
+	problems, err := checkLocales(t.TempDir(), books)
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("expected no problems without locales, got %v, %v", problems, err)
+	}
+
+	indexDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(indexDir, "locales"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	locales := map[string]string{
+		"de.json": `{"schema": 2, "lang": "de", "books": {}}`,
+		"es.json": `{"schema": 1, "lang": "es", "books": {"Gen": "Génesis", "John": "Juan"}}`,
+		"fr.json": `{"schema": 1, "lang": "it", "books": {"Gen": "Genèse", "John": " ", "Nope": "Non"}}`,
+	}
+	for name, data := range locales {
+		if err := os.WriteFile(filepath.Join(indexDir, "locales", name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	problems, err = checkLocales(indexDir, books)
+	if err != nil {
+		t.Fatalf("checkLocales failed: %v", err)
+	}
+	want := []string{
+		"de.json: unsupported locale schema version 2",
+		`fr.json: lang "it" does not match the file name`,
+		"fr.json: empty name for John",
+		"fr.json: unknown book Nope",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("expected %d problems, got %d: %v", len(want), len(problems), problems)
+	}
+	for i := range want {
+		if problems[i] != want[i] {
+			t.Errorf("problem %d: expected %q, got %q", i, want[i], problems[i])
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package verify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// checkNormalization reports normalization.json entries that are not single lowercase words, and
+// words both normalized and kept. A canon without normalization.json has nothing to check.
+func checkNormalization(indexDir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(indexDir, "normalization.json")) // nolint: gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read normalization.json: %w", err)
+	}
+
+	var table model.Normalization
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse normalization.json: %w", err)
+	}
+	if table.Schema != model.NormalizationSchema {
+		return []string{fmt.Sprintf("unsupported schema version %d", table.Schema)}, nil
+	}
+
+	var problems []string
+	for _, section := range []struct {
+		name    string
+		entries map[string]string
+	}{{"words", table.Words}, {"spellings", table.Spellings}} {
+		keys := make([]string, 0, len(section.entries))
+		for key := range section.entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !isWord(key) {
+				problems = append(problems, fmt.Sprintf("%s: %q is not a lowercase word", section.name, key))
+			}
+			if value := section.entries[key]; !isWord(value) {
+				problems = append(problems, fmt.Sprintf("%s: %q normalizes to %q, which is not a lowercase word", section.name, key, value))
+			}
+		}
+	}
+	for _, word := range table.Keep {
+		if !isWord(word) {
+			problems = append(problems, fmt.Sprintf("keep: %q is not a lowercase word", word))
+		} else if _, ok := table.Words[word]; ok {
+			problems = append(problems, fmt.Sprintf("keep: %q is also in words", word))
+		}
+	}
+	return problems, nil
+}
+
+// isWord reports whether s is one lowercase word, as search and analyze split text into: letters,
+// with apostrophes and hyphens only between them
+func isWord(s string) bool {
+	if s == "" || s != strings.ToLower(s) {
+		return false
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsLetter(r) {
+			continue
+		}
+		if (r == '\'' || r == '’' || r == '-') && i > 0 && i < len(runes)-1 && unicode.IsLetter(runes[i-1]) && unicode.IsLetter(runes[i+1]) {
+			continue
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckNormalization(t *testing.T) {
+	// A canon without normalization.json has nothing to check
+	problems, err := checkNormalization(t.TempDir())
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("expected no problems without normalization.json, got %v, %v", problems, err)
+	}
+
+	indexDir := t.TempDir()
+	table := `{
+  "schema": 1,
+  "words": {"thou": "you", "Ye": "you", "hath": "has been", "man's": "man's"},
+  "spellings": {"shew": "show", "-sted": "stead"},
+  "keep": ["nazareth", "thou", "seth-"]
+}`
+	if err := os.WriteFile(filepath.Join(indexDir, "normalization.json"), []byte(table), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err = checkNormalization(indexDir)
+	if err != nil {
+		t.Fatalf("checkNormalization failed: %v", err)
+	}
+	want := []string{
+		`words: "Ye" is not a lowercase word`,
+		`words: "hath" normalizes to "has been", which is not a lowercase word`,
+		`spellings: "-sted" is not a lowercase word`,
+		`keep: "thou" is also in words`,
+		`keep: "seth-" is not a lowercase word`,
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("expected %d problems, got %d: %v", len(want), len(problems), problems)
+	}
+	for i := range want {
+		if problems[i] != want[i] {
+			t.Errorf("problem %d: expected %q, got %q", i, want[i], problems[i])
+		}
+	}
+
+	// The canon's own table is clean
+	problems, err = checkNormalization(filepath.Join("..", "..", "canon", "kjv", "index"))
+	if err != nil || len(problems) != 0 {
+		t.Errorf("expected the canon's table to pass, got %v, %v", problems, err)
+	}
+}
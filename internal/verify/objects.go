@@ -0,0 +1,133 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// loadObjectIndex reads index/objects.json of a canon written in the content-addressed layout, or
+// returns nil for a canon whose chapters are all in books/
+func loadObjectIndex(indexDir string) (*model.ObjectIndex, error) {
+	data, err := os.ReadFile(filepath.Join(indexDir, "objects.json")) // nolint: gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read objects.json: %w", err)
+	}
+	objects, err := model.ParseObjectIndex(data)
+	if err != nil {
+		return nil, err
+	}
+	return &objects, nil
+}
+
+// objectChapter is a chapter stored as an object
+type objectChapter struct {
+	osis    string
+	chapter int
+	name    string // the object's name in objects.json
+}
+
+// objectChapters returns the chapters objects lists for the books in scope, in books.json and
+// chapter order
+func objectChapters(objects *model.ObjectIndex, scope []model.BookMetadata) []objectChapter {
+	if objects == nil {
+		return nil
+	}
+	byBook := make(map[string][]objectChapter)
+	for key, name := range objects.Chapters {
+		osis, chapter, _ := strings.Cut(key, ".")
+		number, err := strconv.Atoi(chapter)
+		if err != nil {
+			continue // reported by checkObjects
+		}
+		byBook[osis] = append(byBook[osis], objectChapter{osis: osis, chapter: number, name: name})
+	}
+
+	var chapters []objectChapter
+	for _, book := range scope {
+		found := byBook[book.OSIS]
+		sort.Slice(found, func(i, j int) bool { return found[i].chapter < found[j].chapter })
+		chapters = append(chapters, found...)
+	}
+	return chapters
+}
+
+// objectPath returns the path of an object beneath canonDir
+func objectPath(canonDir, name string) string {
+	return filepath.Join(canonDir, filepath.FromSlash(model.ObjectPath(name)))
+}
+
+// getObjectFiles returns the object files of the chapters objects lists for the books in scope,
+// each once, as getCanonFiles returns the chapter files under books/. Missing objects are left to
+// checkObjects.
+func getObjectFiles(canonDir string, objects *model.ObjectIndex, scope []model.BookMetadata) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, ch := range objectChapters(objects, scope) {
+		path := objectPath(canonDir, ch.name)
+		if _, err := os.Stat(path); err != nil || seen[path] {
+			continue
+		}
+		seen[path] = true
+		files = append(files, path)
+	}
+	return files
+}
+
+// objectBooks maps the path beneath canonDir of each object objects lists to the OSIS code of its
+// chapter's book, so problems found in objects are reported under their books
+func objectBooks(canonDir string, objects *model.ObjectIndex) map[string]string {
+	if objects == nil {
+		return nil
+	}
+	books := make(map[string]string, len(objects.Chapters))
+	for key, name := range objects.Chapters {
+		osis, _, _ := strings.Cut(key, ".")
+		books[objectPath(canonDir, name)] = osis
+	}
+	return books
+}
+
+// checkObjects reports chapters of objects.json in the books in scope whose objects are missing or
+// hold data that no longer hashes to their names. Objects are named by the SHA-256 of their files,
+// so a changed object is corrupt rather than updated.
+func checkObjects(canonDir string, objects *model.ObjectIndex, scope []model.BookMetadata) []problem {
+	if objects == nil {
+		return nil
+	}
+	var problems []problem
+	for key := range objects.Chapters {
+		if _, chapter, _ := strings.Cut(key, "."); !isNumber(chapter) {
+			problems = append(problems, problem{line: fmt.Sprintf("objects.json lists %q, which is not a chapter", key)})
+		}
+	}
+	for _, ch := range objectChapters(objects, scope) {
+		path := objectPath(canonDir, ch.name)
+		sum, err := util.FileSHA256(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			problems = append(problems, problem{book: ch.osis, line: fmt.Sprintf("%s %d: object %s is missing", ch.osis, ch.chapter, ch.name)})
+		case err != nil:
+			problems = append(problems, problem{book: ch.osis, line: fmt.Sprintf("%s %d: %v", ch.osis, ch.chapter, err)})
+		case !strings.HasPrefix(ch.name, sum):
+			problems = append(problems, problem{book: ch.osis, line: fmt.Sprintf("%s %d: object %s has changed since ingest (sha256 %s)", ch.osis, ch.chapter, ch.name, sum)})
+		}
+	}
+	return problems
+}
+
+// isNumber reports whether s is a decimal number
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
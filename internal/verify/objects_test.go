@@ -0,0 +1,76 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestCheckObjects(t *testing.T) {
+	canonDir := t.TempDir()
+	write := func(data string) string {
+		name := model.ObjectName([]byte(data), "")
+		path := objectPath(canonDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+
+	first := write(`{"chapter":1}`)
+	second := write(`{"chapter":2}`)
+	changed := write(`{"chapter":3}`)
+	if err := os.WriteFile(objectPath(canonDir, changed), []byte(`{"chapter":4}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	missing := model.ObjectName([]byte(`{"chapter":5}`), "")
+
+	objects := model.NewObjectIndex("KJV")
+	objects.Chapters["Gen.2"] = second
+	objects.Chapters["Gen.1"] = first
+	objects.Chapters["Exod.1"] = first // identical chapters share their object
+	objects.Chapters["Exod.2"] = changed
+	objects.Chapters["Lev.1"] = missing
+	objects.Chapters["Gen.intro"] = first
+	scope := []model.BookMetadata{{OSIS: "Gen"}, {OSIS: "Exod"}, {OSIS: "Lev"}}
+
+	files := getObjectFiles(canonDir, &objects, scope)
+	want := []string{objectPath(canonDir, first), objectPath(canonDir, second), objectPath(canonDir, changed)}
+	if strings.Join(files, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected object files:\n%s", strings.Join(files, "\n"))
+	}
+	if book := objectBooks(canonDir, &objects)[objectPath(canonDir, second)]; book != "Gen" {
+		t.Errorf("expected the second object to belong to Gen, got %q", book)
+	}
+
+	var lines []string
+	for _, p := range checkObjects(canonDir, &objects, scope) {
+		lines = append(lines, p.line)
+	}
+	got := strings.Join(lines, "\n")
+	for _, want := range []string{
+		`objects.json lists "Gen.intro", which is not a chapter`,
+		"Exod 2: object " + changed + " has changed since ingest",
+		"Lev 1: object " + missing + " is missing",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+	if len(lines) != 3 {
+		t.Errorf("expected 3 problems, got:\n%s", got)
+	}
+
+	if problems := checkObjects(canonDir, &objects, []model.BookMetadata{{OSIS: "Gen"}}); len(problems) != 1 {
+		t.Errorf("expected only the bad key to be reported for Gen, got %v", problems)
+	}
+	if problems := checkObjects(canonDir, nil, scope); problems != nil {
+		t.Errorf("expected no problems without objects.json, got %v", problems)
+	}
+}
@@ -0,0 +1,69 @@
+package verify
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// OrphanFile is a chapter file under books/ that no current ingest run would have produced
+type OrphanFile struct {
+	Path   string
+	Kind   string // "orphaned" (not in filemap.json) or "stale" (beyond the book's chapter count)
+	Reason string
+}
+
+// findOrphans finds chapter files that are not referenced by filemap.json or whose chapter number
+// exceeds the book's chapter count in books.json, typically left behind by previous runs
+func findOrphans(canonDir string, chapters []string, fileMap model.FileMap, books model.BooksData) []OrphanFile {
+	referenced := make(map[string]bool, len(fileMap.Files))
+	for _, entry := range fileMap.Files {
+		path := entry.Output
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(canonDir, path)
+		}
+		referenced[filepath.Clean(path)] = true
+	}
+
+	chapterCounts := make(map[string]int, len(books.Books))
+	for _, book := range books.Books {
+		chapterCounts[book.OSIS] = book.Chapters
+	}
+
+	var orphans []OrphanFile
+	for _, path := range chapters {
+		osis := filepath.Base(filepath.Dir(path))
+		chapterNum, err := chapterFromFilename(filepath.Base(path))
+
+		switch {
+		case err == nil && chapterCounts[osis] > 0 && chapterNum > chapterCounts[osis]:
+			orphans = append(orphans, OrphanFile{
+				Path:   path,
+				Kind:   "stale",
+				Reason: fmt.Sprintf("chapter %d exceeds %s chapter count %d", chapterNum, osis, chapterCounts[osis]),
+			})
+		case !referenced[filepath.Clean(path)]:
+			orphans = append(orphans, OrphanFile{
+				Path:   path,
+				Kind:   "orphaned",
+				Reason: "not referenced by filemap.json",
+			})
+		}
+	}
+
+	return orphans
+}
+
+// chapterFromFilename extracts the chapter number from a chNN.json file name, or that of a
+// compressed chapter such as chNN.json.zst
+func chapterFromFilename(name string) (int, error) {
+	name = strings.TrimSuffix(name, compression.ForName(name).Ext())
+	if !strings.HasPrefix(name, "ch") || !strings.HasSuffix(name, ".json") {
+		return 0, fmt.Errorf("not a chapter file name: %s", name)
+	}
+	return strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "ch"), ".json"))
+}
@@ -0,0 +1,39 @@
+package verify
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestFindOrphans(t *testing.T) {
+	canonDir := filepath.Join("canon", "kjv")
+	chapters := []string{
+		filepath.Join(canonDir, "books", "Obad", "ch01.json"),
+		filepath.Join(canonDir, "books", "Obad", "ch02.json"),
+		filepath.Join(canonDir, "books", "Joel", "ch01.json"),
+	}
+	fileMap := model.NewFileMap()
+	fileMap.Add(model.FileMapEntry{Raw: "raw/html/ot/OBA/OBA01.htm", Output: "books/Obad/ch01.json"})
+	fileMap.Add(model.FileMapEntry{Raw: "raw/html/ot/OBA/OBA02.htm", Output: "books/Obad/ch02.json"})
+	books := model.BooksData{
+		Books: []model.BookMetadata{
+			{OSIS: "Obad", Chapters: 1},
+			{OSIS: "Joel", Chapters: 3},
+		},
+	}
+
+	orphans := findOrphans(canonDir, chapters, fileMap, books)
+	if len(orphans) != 2 {
+		t.Fatalf("expected 2 orphans, got %d (%v)", len(orphans), orphans)
+	}
+
+	if orphans[0].Path != chapters[1] || orphans[0].Kind != "stale" {
+		t.Errorf("expected stale %s, got %s %s", chapters[1], orphans[0].Kind, orphans[0].Path)
+	}
+
+	if orphans[1].Path != chapters[2] || orphans[1].Kind != "orphaned" {
+		t.Errorf("expected orphaned %s, got %s %s", chapters[2], orphans[1].Kind, orphans[1].Path)
+	}
+}
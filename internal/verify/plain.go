@@ -0,0 +1,253 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Kinds of plain-vs-token mismatch, from most to least mechanical
+const (
+	plainWhitespace = "whitespace" // equal once whitespace is ignored
+	plainEntity     = "entity"     // equal once HTML entities are decoded
+	plainText       = "text"       // the words themselves differ
+)
+
+const (
+	// diffContextRunes is how much matching text charDiff keeps on either side of the changes
+	diffContextRunes = 20
+	// maxDiffCells bounds the LCS table charDiff builds; larger changes are shown as one replacement
+	maxDiffCells = 1 << 20
+)
+
+// plainMismatch is a verse whose plain field differs from its flattened tokens
+type plainMismatch struct {
+	V     int
+	Kind  string
+	Plain string
+	Flat  string
+}
+
+// Diff returns a character-level diff from the plain field to the flattened tokens
+func (m plainMismatch) Diff() string {
+	return charDiff(m.Plain, m.Flat)
+}
+
+// fixable reports whether --autofix-plain may regenerate the verse's plain field. Only
+// whitespace and entity artifacts are fixed; a text mismatch needs a look at the source.
+func (m plainMismatch) fixable() bool {
+	return m.Kind == plainWhitespace || m.Kind == plainEntity
+}
+
+// plainStats tallies plain-vs-token consistency across a canon
+type plainStats struct {
+	Verses int            // verses checked
+	Kinds  map[string]int // mismatches by kind, fixed or not
+	Fixed  int            // verses regenerated by --autofix-plain
+	Files  int            // chapter files rewritten by --autofix-plain
+}
+
+func newPlainStats() *plainStats {
+	return &plainStats{Kinds: make(map[string]int)}
+}
+
+// add adds the verses checked and mismatches of another tally to s
+func (s *plainStats) add(other *plainStats) {
+	s.Verses += other.Verses
+	for kind, n := range other.Kinds {
+		s.Kinds[kind] += n
+	}
+}
+
+// String summarizes the tally for the verify canon report
+func (s *plainStats) String() string {
+	mismatches := 0
+	for _, n := range s.Kinds {
+		mismatches += n
+	}
+	return fmt.Sprintf(
+		"%d verses, %d mismatches (%d whitespace, %d entity, %d text), %d fixed",
+		s.Verses,
+		mismatches,
+		s.Kinds[plainWhitespace],
+		s.Kinds[plainEntity],
+		s.Kinds[plainText],
+		s.Fixed,
+	)
+}
+
+// checkPlain compares the plain field of every verse of a chapter to its flattened tokens,
+// tallying the result. Verses without a plain field are left to validateChapterFile.
+func checkPlain(chapter *model.Chapter, stats *plainStats) []plainMismatch {
+	var mismatches []plainMismatch
+	for _, verse := range chapter.Verses {
+		if verse.Plain == "" {
+			continue
+		}
+		stats.Verses++
+		flat := flatten(verse.Tokens)
+		if flat == verse.Plain {
+			continue
+		}
+		kind := classifyPlain(verse.Plain, flat)
+		stats.Kinds[kind]++
+		mismatches = append(mismatches, plainMismatch{V: verse.V, Kind: kind, Plain: verse.Plain, Flat: flat})
+	}
+	return mismatches
+}
+
+// classifyPlain reports why a plain field and its flattened tokens differ
+func classifyPlain(plain, flat string) string {
+	if stripSpace(plain) == stripSpace(flat) {
+		return plainWhitespace
+	}
+	if stripSpace(html.UnescapeString(plain)) == stripSpace(html.UnescapeString(flat)) {
+		return plainEntity
+	}
+	return plainText
+}
+
+func stripSpace(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
+
+// fixPlain regenerates the plain field of the fixable mismatches from the verse tokens and
+// rewrites the chapter file at path, recording a provenance note in the chapter. It returns the
+// verses it fixed; the file is left alone when there are none.
+func fixPlain(path string, chapter *model.Chapter, mismatches []plainMismatch) ([]plainMismatch, error) {
+	flats := make(map[int]string, len(mismatches))
+	var fixed []plainMismatch
+	for _, m := range mismatches {
+		if m.fixable() {
+			flats[m.V] = m.Flat
+			fixed = append(fixed, m)
+		}
+	}
+	if len(fixed) == 0 {
+		return nil, nil
+	}
+
+	verses := make([]string, 0, len(fixed))
+	for i := range chapter.Verses {
+		if flat, ok := flats[chapter.Verses[i].V]; ok {
+			chapter.Verses[i].Plain = flat
+			verses = append(verses, strconv.Itoa(chapter.Verses[i].V))
+		}
+	}
+	chapter.Provenance = append(chapter.Provenance, fmt.Sprintf(
+		"%s: plain text of verse(s) %s regenerated from tokens by verify canon --autofix-plain",
+		time.Now().UTC().Format(time.DateOnly),
+		strings.Join(verses, ", "),
+	))
+
+	data, err := json.MarshalIndent(chapter, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	// A compressed chapter is written back as it was found
+	if data, err = compression.ForName(path).Compress(data); err != nil {
+		return nil, fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+	if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return fixed, nil
+}
+
+// charDiff returns a character-level diff from a to b, marking text only in a as [-...-] and
+// text only in b as {+...+}, with up to diffContextRunes of matching text around the changes.
+// Whitespace inside the markers is made visible, since it is the usual culprit.
+func charDiff(a, b string) string {
+	ra, rb := []rune(a), []rune(b)
+	prefix := 0
+	for prefix < len(ra) && prefix < len(rb) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(ra)-prefix && suffix < len(rb)-prefix && ra[len(ra)-1-suffix] == rb[len(rb)-1-suffix] {
+		suffix++
+	}
+	ma, mb := ra[prefix:len(ra)-suffix], rb[prefix:len(rb)-suffix]
+
+	var out strings.Builder
+	if prefix > diffContextRunes {
+		out.WriteString("…")
+		out.WriteString(string(ra[prefix-diffContextRunes : prefix]))
+	} else {
+		out.WriteString(string(ra[:prefix]))
+	}
+
+	var removed, added []rune
+	flush := func() {
+		if len(removed) > 0 {
+			out.WriteString("[-" + visibleSpace(removed) + "-]")
+		}
+		if len(added) > 0 {
+			out.WriteString("{+" + visibleSpace(added) + "+}")
+		}
+		removed, added = nil, nil
+	}
+
+	if len(ma)*len(mb) > maxDiffCells {
+		removed, added = ma, mb
+	} else {
+		// lcs[i][j] is the length of the longest common subsequence of ma[i:] and mb[j:]
+		lcs := make([][]int, len(ma)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(mb)+1)
+		}
+		for i := len(ma) - 1; i >= 0; i-- {
+			for j := len(mb) - 1; j >= 0; j-- {
+				if ma[i] == mb[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+
+		// Mostly unrelated text reads better as one replacement than as scattered shared letters
+		i, j := 0, 0
+		if lcs[0][0]*4 < max(len(ma), len(mb)) {
+			i, j = len(ma), len(mb)
+			removed, added = ma, mb
+		}
+		for i < len(ma) || j < len(mb) {
+			switch {
+			case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+				flush()
+				out.WriteRune(ma[i])
+				i++
+				j++
+			case j == len(mb) || (i < len(ma) && lcs[i+1][j] >= lcs[i][j+1]):
+				removed = append(removed, ma[i])
+				i++
+			default:
+				added = append(added, mb[j])
+				j++
+			}
+		}
+	}
+	flush()
+
+	tail := ra[len(ra)-suffix:]
+	if len(tail) > diffContextRunes {
+		out.WriteString(string(tail[:diffContextRunes]))
+		out.WriteString("…")
+	} else {
+		out.WriteString(string(tail))
+	}
+	return out.String()
+}
+
+// visibleSpace renders spaces, tabs, and newlines so a whitespace-only change can be seen
+func visibleSpace(rs []rune) string {
+	return strings.NewReplacer(" ", "·", "\t", "→", "\n", "↵").Replace(string(rs))
+}
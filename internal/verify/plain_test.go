@@ -0,0 +1,101 @@
+package verify
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestCharDiff(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"In the beginning", "In the beginning", "In the beginning"},
+		{"God  created", "God created", "God [-·-]created"},
+		{"the heaven and", "the heaven &amp; and", "the heaven {+&amp;·+}and"},
+		{"LORD God", "Lord God", "L[-ORD-]{+ord+} God"},
+		{
+			"And God said, Let there be light: and there was light.",
+			"And God said, Let there be lights and there was light.",
+			"…, Let there be light[-:-]{+s+} and there was light…",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := charDiff(tt.a, tt.b); got != tt.want {
+			t.Errorf("charDiff(%q, %q) = %q; want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyPlain(t *testing.T) {
+	tests := []struct {
+		plain, flat string
+		want        string
+	}{
+		{"the LORD  God", "the LORD God", plainWhitespace},
+		{"heaven\tand earth ", "heaven and earth", plainWhitespace},
+		{"Jacob &amp; Esau", "Jacob & Esau", plainEntity},
+		{"the LORD God", "the Lord God", plainText},
+	}
+
+	for _, tt := range tests {
+		if got := classifyPlain(tt.plain, tt.flat); got != tt.want {
+			t.Errorf("classifyPlain(%q, %q) = %q; want %q", tt.plain, tt.flat, got, tt.want)
+		}
+	}
+}
+
+func TestCheckAndFixPlain(t *testing.T) {
+	chapter := &model.Chapter{Schema: 1, Work: "KJV", OSIS: "Gen", Abbr: "GEN", Chapter: 1, Verses: []model.Verse{
+		{V: 1, Plain: "In the beginning God", Tokens: []model.Token{{Text: "In the beginning God"}}},
+		{V: 2, Plain: "And  the earth", Tokens: []model.Token{{Text: "And the earth"}}},
+		{V: 3, Plain: "light &amp; darkness", Tokens: []model.Token{{Text: "light & darkness"}}},
+		{V: 4, Plain: "And God saw", Tokens: []model.Token{{Text: "And the "}, {ND: "LORD"}, {Text: " saw"}}},
+	}}
+
+	stats := newPlainStats()
+	mismatches := checkPlain(chapter, stats)
+	if len(mismatches) != 3 || stats.Verses != 4 {
+		t.Fatalf("expected 3 mismatches in 4 verses, got %d in %d", len(mismatches), stats.Verses)
+	}
+	if stats.Kinds[plainWhitespace] != 1 || stats.Kinds[plainEntity] != 1 || stats.Kinds[plainText] != 1 {
+		t.Errorf("unexpected kinds: %v", stats.Kinds)
+	}
+
+	path := filepath.Join(t.TempDir(), "ch01.json")
+	fixed, err := fixPlain(path, chapter, mismatches)
+	if err != nil {
+		t.Fatalf("fixPlain failed: %v", err)
+	}
+	if len(fixed) != 2 {
+		t.Fatalf("expected the whitespace and entity mismatches to be fixed, got %v", fixed)
+	}
+
+	data, err := os.ReadFile(path) // nolint: gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written model.Chapter
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	if written.Verses[1].Plain != "And the earth" || written.Verses[2].Plain != "light & darkness" {
+		t.Errorf("expected plain text regenerated from tokens, got %q and %q", written.Verses[1].Plain, written.Verses[2].Plain)
+	}
+	if written.Verses[3].Plain != "And God saw" {
+		t.Errorf("expected a text mismatch to be left alone, got %q", written.Verses[3].Plain)
+	}
+	if len(written.Provenance) != 1 || !strings.Contains(written.Provenance[0], "verse(s) 2, 3 regenerated from tokens") {
+		t.Errorf("unexpected provenance: %v", written.Provenance)
+	}
+
+	if again := checkPlain(&written, newPlainStats()); len(again) != 1 || again[0].V != 4 {
+		t.Errorf("expected only the text mismatch to remain, got %v", again)
+	}
+}
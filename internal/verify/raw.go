@@ -0,0 +1,113 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+const ManifestFileName = "SHA256MANIFEST"
+
+func (r *RawCmd) Run(ctx context.Context) error {
+	if _, err := os.Stat(r.Raw); os.IsNotExist(err) {
+		util.StopProgress(ctx)
+		return fmt.Errorf("raw directory does not exist: %s", r.Raw)
+	}
+
+	totalFiles, mismatches, errors, manifestErr := r.verifyManifest()
+
+	var structureFiles, structureFilesWithIssues, structureIssues int
+	var structureErr error
+	if r.Structure {
+		structureFiles, structureFilesWithIssues, structureIssues, structureErr = r.verifyStructure()
+	}
+
+	util.StopProgress(ctx)
+
+	if manifestErr != nil {
+		fmt.Printf("Manifest error: %v\n", manifestErr)
+	}
+
+	fmt.Println("========================================")
+	fmt.Printf("Total Files Verified: %d\n", totalFiles)
+	fmt.Printf("Hash Mismatches: %d\n", mismatches)
+	fmt.Printf("Read Errors: %d\n", errors)
+	if r.Structure {
+		fmt.Printf("Files Parsed: %d\n", structureFiles)
+		fmt.Printf("Files With Issues: %d\n", structureFilesWithIssues)
+		fmt.Printf("Structure Issues: %d\n", structureIssues)
+	}
+	fmt.Println("========================================")
+
+	if manifestErr != nil {
+		return manifestErr
+	}
+	if structureErr != nil {
+		return structureErr
+	}
+	// Files that cannot be read are fatal, while mismatches and structure issues are validation errors
+	if errors > 0 {
+		return fmt.Errorf("manifest validation failed: %d mismatches, %d errors", mismatches, errors)
+	}
+	if mismatches > 0 {
+		return util.Exit(util.ExitErrors, fmt.Errorf("manifest validation failed: %d mismatches", mismatches))
+	}
+	if structureIssues > 0 {
+		return util.Exit(util.ExitErrors, fmt.Errorf("structure validation failed: %d issues in %d files", structureIssues, structureFilesWithIssues))
+	}
+
+	fmt.Println("Raw validation completed successfully")
+	return nil
+}
+
+// verifyManifest checks every entry of the SHA256 manifest against the file on disk
+// Both manifest formats are supported; version 1 paths can be moved onto --raw with --rebase
+func (r *RawCmd) verifyManifest() (int, int, int, error) {
+	manifestPath := filepath.Join(r.Raw, ManifestFileName)
+	if _, err := os.Stat(manifestPath); os.IsNotExist(err) {
+		return 0, 0, 0, fmt.Errorf("manifest file not found in raw directory: %s", manifestPath)
+	}
+
+	manifest, err := util.ReadManifest(manifestPath)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	var mismatches int
+	var errors int
+
+	for _, entry := range manifest.Entries {
+		filePath := manifest.Resolve(entry, r.Raw, r.Rebase)
+
+		if entry.Size >= 0 {
+			info, err := os.Stat(filePath)
+			if err != nil {
+				fmt.Printf("Manifest error: cannot stat file %s - %v\n", filePath, err)
+				errors++
+				continue
+			}
+			if info.Size() != entry.Size {
+				fmt.Printf("Size mismatch for %s: expected %d bytes, got %d\n", filePath, entry.Size, info.Size())
+				mismatches++
+				continue
+			}
+		}
+
+		actualHash, err := util.FileSHA256(filePath)
+		if err != nil {
+			fmt.Printf("Manifest error: cannot read file %s - %v\n", filePath, err)
+			errors++
+			continue
+		}
+
+		if actualHash != entry.SHA256 {
+			fmt.Printf("Hash mismatch for %s: expected %s, got %s\n", filePath, entry.SHA256, actualHash)
+			mismatches++
+		}
+	}
+
+	return len(manifest.Entries), mismatches, errors, nil
+}
@@ -0,0 +1,68 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func TestVerifyManifest(t *testing.T) {
+	content := []byte("<html></html>")
+	hash := util.SHA256Hex(content)
+
+	tests := []struct {
+		name           string
+		manifest       string
+		rebase         string
+		wantMismatches int
+		wantErrors     int
+	}{
+		{
+			name:     "v2 relative paths",
+			manifest: "# Schema: 2\n" + hash + "  13  html/ot/OBA/OBA01.htm\n",
+		},
+		{
+			name:           "v2 size mismatch",
+			manifest:       "# Schema: 2\n" + hash + "  99  html/ot/OBA/OBA01.htm\n",
+			wantMismatches: 1,
+		},
+		{
+			name:     "v1 rebased from another machine",
+			manifest: hash + "  /home/someone/kjv-sources/raw/html/ot/OBA/OBA01.htm\n",
+			rebase:   "/home/someone/kjv-sources/raw",
+		},
+		{
+			name:       "v1 without rebase",
+			manifest:   hash + "  /home/someone/kjv-sources/raw/html/ot/OBA/OBA01.htm\n",
+			wantErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawDir := t.TempDir()
+			file := filepath.Join(rawDir, "html", "ot", "OBA", "OBA01.htm")
+			if err := os.MkdirAll(filepath.Dir(file), 0750); err != nil {
+				t.Fatalf("failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(file, content, 0600); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(rawDir, ManifestFileName), []byte(tt.manifest), 0600); err != nil {
+				t.Fatalf("failed to write manifest: %v", err)
+			}
+
+			r := &RawCmd{Raw: rawDir, Rebase: tt.rebase}
+			total, mismatches, errors, err := r.verifyManifest()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if total != 1 || mismatches != tt.wantMismatches || errors != tt.wantErrors {
+				t.Errorf("expected 1 file, %d mismatches, %d errors; got %d, %d, %d",
+					tt.wantMismatches, tt.wantErrors, total, mismatches, errors)
+			}
+		})
+	}
+}
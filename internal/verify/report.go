@@ -0,0 +1,179 @@
+package verify
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+// reportExamples is how many problems of each rule the grouped report of verify canon prints
+const reportExamples = 3
+
+// canonWide is the row of the report matrix for problems that belong to no one book
+const canonWide = "(canon)"
+
+// problem is an error or warning found by verify canon
+type problem struct {
+	code    string // the rule that found it, as counted by the gate
+	book    string // OSIS code of the book it was found in, or "" if it spans books
+	warning bool
+	line    string // the line printed for it
+}
+
+// canonReport prints the problems of a verify canon run as they are found, up to a limit, counts
+// them with the gate, and ends the run with them grouped by rule and by book, so a large set of
+// failures can be reviewed
+type canonReport struct {
+	gate       *util.Gate
+	out        io.Writer
+	maxOutput  int // most problems to print as they are found; 0 prints every problem
+	books      []string
+	problems   []problem
+	suppressed int
+}
+
+// newCanonReport returns a report of problems counted by gate, which orders the books it groups
+// by books, their OSIS codes in books.json order
+func newCanonReport(gate *util.Gate, out io.Writer, maxOutput int, books []string) *canonReport {
+	return &canonReport{gate: gate, out: out, maxOutput: maxOutput, books: books}
+}
+
+// error reports an error found by the rule code in book, or in no one book if book is ""
+func (r *canonReport) error(code, book, format string, args ...any) {
+	r.gate.Error(code)
+	r.add(problem{code: code, book: book, line: fmt.Sprintf(format, args...)})
+}
+
+// warning reports a warning found by the rule code in book, or in no one book if book is ""
+func (r *canonReport) warning(code, book, format string, args ...any) {
+	r.gate.Warning(code)
+	r.add(problem{code: code, book: book, warning: true, line: fmt.Sprintf(format, args...)})
+}
+
+func (r *canonReport) add(p problem) {
+	r.problems = append(r.problems, p)
+	if r.maxOutput > 0 && len(r.problems) > r.maxOutput {
+		r.suppressed++
+		return
+	}
+	_, _ = fmt.Fprintln(r.out, p.line)
+}
+
+// codes returns the rules that found problems, errors before warnings, each in sorted order
+func (r *canonReport) codes() []string {
+	var errorCodes, warningCodes []string
+	for _, p := range r.problems {
+		if p.warning {
+			if !slices.Contains(warningCodes, p.code) {
+				warningCodes = append(warningCodes, p.code)
+			}
+		} else if !slices.Contains(errorCodes, p.code) {
+			errorCodes = append(errorCodes, p.code)
+		}
+	}
+	slices.Sort(errorCodes)
+	slices.Sort(warningCodes)
+	return append(errorCodes, warningCodes...)
+}
+
+// rows returns the books that have problems in books.json order, followed by canonWide if any
+// problems belong to no one book
+func (r *canonReport) rows() []string {
+	found := make(map[string]bool)
+	for _, p := range r.problems {
+		found[p.book] = true
+	}
+	var rows []string
+	for _, book := range r.books {
+		if found[book] {
+			rows = append(rows, book)
+			delete(found, book)
+		}
+	}
+	// Books missing from books.json, such as those of orphaned directories, come after the rest
+	var others []string
+	for book := range found {
+		if book != "" {
+			others = append(others, book)
+		}
+	}
+	slices.Sort(others)
+	rows = append(rows, others...)
+	if found[""] {
+		rows = append(rows, canonWide)
+	}
+	return rows
+}
+
+// Write writes the problems grouped by rule, with their counts and first examples, and a matrix
+// of their counts by book and rule. It writes nothing if there were no problems.
+func (r *canonReport) Write(w io.Writer) error {
+	if len(r.problems) == 0 {
+		return nil
+	}
+	if r.suppressed > 0 {
+		_, _ = fmt.Fprintf(w, "... %d more problems not shown (--max-output=%d)\n", r.suppressed, r.maxOutput)
+	}
+
+	codes := r.codes()
+	_, _ = fmt.Fprintln(w, "========================================")
+	_, _ = fmt.Fprintln(w, "Problems By Rule:")
+	for _, code := range codes {
+		var matching []problem
+		for _, p := range r.problems {
+			if p.code == code {
+				matching = append(matching, p)
+			}
+		}
+		kind := "errors"
+		if matching[0].warning {
+			kind = "warnings"
+		}
+		_, _ = fmt.Fprintf(w, "  %s: %d %s\n", code, len(matching), kind)
+		for _, p := range matching[:min(len(matching), reportExamples)] {
+			_, _ = fmt.Fprintf(w, "    %s\n", p.line)
+		}
+		if len(matching) > reportExamples {
+			_, _ = fmt.Fprintf(w, "    ... %d more\n", len(matching)-reportExamples)
+		}
+	}
+
+	counts := make(map[string]map[string]int)
+	for _, p := range r.problems {
+		book := p.book
+		if book == "" {
+			book = canonWide
+		}
+		if counts[book] == nil {
+			counts[book] = make(map[string]int)
+		}
+		counts[book][p.code]++
+	}
+
+	_, _ = fmt.Fprintln(w, "\nProblems By Book:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "  Book\t%s\tTotal\n", strings.Join(codes, "\t"))
+	totals := make([]int, len(codes)+1)
+	for _, book := range r.rows() {
+		cells := make([]string, 0, len(codes)+1)
+		sum := 0
+		for i, code := range codes {
+			n := counts[book][code]
+			cells = append(cells, fmt.Sprint(n))
+			totals[i] += n
+			sum += n
+		}
+		totals[len(codes)] += sum
+		_, _ = fmt.Fprintf(tw, "  %s\t%s\t%d\n", book, strings.Join(cells, "\t"), sum)
+	}
+	cells := make([]string, len(totals))
+	for i, n := range totals {
+		cells[i] = fmt.Sprint(n)
+	}
+	_, _ = fmt.Fprintf(tw, "  Total\t%s\n", strings.Join(cells, "\t"))
+	return tw.Flush()
+}
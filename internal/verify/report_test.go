@@ -0,0 +1,76 @@
+package verify
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func TestCanonReport(t *testing.T) {
+	gate := util.NewGate([]string{"divine-name"}, false)
+	var out bytes.Buffer
+	report := newCanonReport(gate, &out, 3, []string{"Gen", "Exod", "Lev"})
+
+	for v := 1; v <= 5; v++ {
+		report.error("plain", "Lev", "Plain error: Lev 1:%d", v)
+	}
+	report.error("layout", "Gen", "Layout error: gap")
+	report.error("topics", "", "Topics error: missing")
+	report.warning("divine-name", "Exod", "Divine name warning: Exod 33:9")
+
+	// Problems are printed as they are found up to --max-output, and counted by the gate in any case
+	if got := strings.Count(out.String(), "\n"); got != 3 {
+		t.Errorf("expected 3 problems printed, got %d:\n%s", got, out.String())
+	}
+	if gate.Errors != 7 || gate.Warnings != 0 || gate.Ignored != 1 {
+		t.Errorf("expected 7 errors and 1 ignored warning, got %d errors, %d warnings, and %d ignored",
+			gate.Errors, gate.Warnings, gate.Ignored)
+	}
+
+	var summary bytes.Buffer
+	if err := report.Write(&summary); err != nil {
+		t.Fatal(err)
+	}
+	got := summary.String()
+	for _, want := range []string{
+		"... 5 more problems not shown (--max-output=3)\n",
+		"  plain: 5 errors\n    Plain error: Lev 1:1\n    Plain error: Lev 1:2\n    Plain error: Lev 1:3\n    ... 2 more\n",
+		"  divine-name: 1 warnings\n    Divine name warning: Exod 33:9\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	// The matrix has errors before warnings and books in books.json order, then the canon as a whole
+	matrix := got[strings.Index(got, "Problems By Book:"):]
+	lines := strings.Split(strings.TrimSpace(matrix), "\n")[1:]
+	var rows []string
+	for _, line := range lines {
+		rows = append(rows, strings.Join(strings.Fields(line), " "))
+	}
+	want := []string{
+		"Book layout plain topics divine-name Total",
+		"Gen 1 0 0 0 1",
+		"Exod 0 0 0 1 1",
+		"Lev 0 5 0 0 5",
+		"(canon) 0 0 1 0 1",
+		"Total 1 5 1 1 8",
+	}
+	if strings.Join(rows, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected matrix:\n%s", strings.Join(rows, "\n"))
+	}
+}
+
+func TestCanonReportClean(t *testing.T) {
+	var out bytes.Buffer
+	report := newCanonReport(util.NewGate(nil, false), &out, 0, nil)
+	if err := report.Write(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected nothing written for a clean run, got %q", out.String())
+	}
+}
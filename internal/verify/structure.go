@@ -0,0 +1,340 @@
+package verify
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// knownClasses lists every class name the ingest parser understands or deliberately ignores
+var knownClasses = map[string]bool{
+	"main": true, "tnav": true, "mt": true, "mt2": true, "chapterlabel": true,
+	"p": true, "q": true, "b": true, "d": true, "s": true, "ms": true, "tl": true, "is": true,
+	"verse": true, "add": true, "nd": true, "wj": true,
+	"notemark": true, "popup": true, "footnote": true, "f": true, "notebackref": true, "ft": true,
+	"copyright": true,
+}
+
+// StructureIssue is a single structural problem found in a raw HTML file
+type StructureIssue struct {
+	Rule    string // "chapterlabel", "verses", "footnotes", "class"
+	Message string
+}
+
+// verifyStructure parses every raw HTML file and reports structural problems per file, returning
+// the number of files parsed, of files with issues, and of issues
+func (r *RawCmd) verifyStructure() (int, int, int, error) {
+	var files []string
+	err := filepath.WalkDir(filepath.Join(r.Raw, "html"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(path) == ".htm" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to walk raw html directory: %w", err)
+	}
+
+	sort.Strings(files)
+
+	var filesWithIssues int
+	var totalIssues int
+	for _, path := range files {
+		content, err := os.ReadFile(path) // nolint: gosec
+		if err != nil {
+			fmt.Printf("Structure error: cannot read file %s - %v\n", path, err)
+			filesWithIssues++
+			totalIssues++
+			continue
+		}
+
+		issues, err := checkStructure(content, filepath.Base(path))
+		if err != nil {
+			fmt.Printf("Structure error: cannot parse file %s - %v\n", path, err)
+			filesWithIssues++
+			totalIssues++
+			continue
+		}
+		issues = acceptIssues(issues, filepath.Base(path), r.Except)
+
+		if len(issues) > 0 {
+			filesWithIssues++
+			totalIssues += len(issues)
+			fmt.Printf("%s:\n", path)
+			for _, issue := range issues {
+				fmt.Printf("  [%s] %s\n", issue.Rule, issue.Message)
+			}
+		}
+	}
+
+	return len(files), filesWithIssues, totalIssues, nil
+}
+
+// acceptIssues drops issues listed in except as FILE:RULE, where either part may be "*"
+func acceptIssues(issues []StructureIssue, filename string, except []string) []StructureIssue {
+	if len(except) == 0 {
+		return issues
+	}
+
+	var kept []StructureIssue
+	for _, issue := range issues {
+		accepted := false
+		for _, entry := range except {
+			file, rule, ok := strings.Cut(entry, ":")
+			if !ok {
+				file, rule = entry, "*"
+			}
+			if (file == "*" || file == filename) && (rule == "*" || rule == issue.Rule) {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// checkStructure runs all structural rules against a single raw HTML document
+func checkStructure(content []byte, filename string) ([]StructureIssue, error) {
+	doc, err := html.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []StructureIssue
+	isIntro := strings.HasSuffix(strings.TrimSuffix(filename, ".htm"), "00")
+
+	var chapterLabels int
+	var verseNums []int
+	noteRefs := make(map[string]bool)  // footnote ids referenced from the text
+	footnotes := make(map[string]bool) // footnote ids defined in the footnote section
+	unknown := make(map[string]int)
+
+	var walk func(*html.Node, bool)
+	walk = func(n *html.Node, inFootnotes bool) {
+		if n.Type == html.ElementNode {
+			for _, c := range classesOf(n) {
+				if !knownClasses[c] {
+					unknown[c]++
+				}
+			}
+
+			switch {
+			case n.Data == "div" && hasClass(n, "chapterlabel"):
+				chapterLabels++
+				if _, err := strconv.Atoi(strings.TrimSpace(textContent(n))); err != nil {
+					issues = append(issues, StructureIssue{
+						Rule:    "chapterlabel",
+						Message: fmt.Sprintf("chapter label is not a number: %q", strings.TrimSpace(textContent(n))),
+					})
+				}
+			case n.Data == "span" && hasClass(n, "verse"):
+				fields := strings.Fields(strings.ReplaceAll(textContent(n), "\u00a0", " "))
+				num := -1
+				if len(fields) > 0 {
+					if v, err := strconv.Atoi(fields[0]); err == nil {
+						num = v
+					}
+				}
+				if num < 1 {
+					issues = append(issues, StructureIssue{
+						Rule:    "verses",
+						Message: fmt.Sprintf("verse span has unparsable number: %q", textContent(n)),
+					})
+				} else {
+					verseNums = append(verseNums, num)
+				}
+			case n.Data == "a" && hasClass(n, "notemark") && !inFootnotes:
+				if href := attrOf(n, "href"); strings.HasPrefix(href, "#") {
+					noteRefs[strings.TrimPrefix(href, "#")] = true
+				}
+			case n.Data == "div" && hasClass(n, "footnote"):
+				inFootnotes = true
+			case n.Data == "p" && hasClass(n, "f") && inFootnotes:
+				id := attrOf(n, "id")
+				footnotes[id] = true
+				issues = append(issues, checkFootnoteParagraph(n, id)...)
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, inFootnotes)
+		}
+	}
+
+	walk(doc, false)
+
+	if !isIntro && chapterLabels != 1 {
+		issues = append(issues, StructureIssue{
+			Rule:    "chapterlabel",
+			Message: fmt.Sprintf("expected exactly one <div class='chapterlabel'>, found %d", chapterLabels),
+		})
+	}
+
+	if !isIntro {
+		issues = append(issues, checkVerseNumbering(verseNums, filename)...)
+	}
+
+	for _, id := range sortedKeys(noteRefs) {
+		if !footnotes[id] {
+			issues = append(issues, StructureIssue{
+				Rule:    "footnotes",
+				Message: fmt.Sprintf("note mark references missing footnote %s", id),
+			})
+		}
+	}
+	for _, id := range sortedKeys(footnotes) {
+		if id != "" && !noteRefs[id] {
+			issues = append(issues, StructureIssue{
+				Rule:    "footnotes",
+				Message: fmt.Sprintf("footnote %s is never referenced from the text", id),
+			})
+		}
+	}
+
+	for _, c := range sortedKeys(unknown) {
+		issues = append(issues, StructureIssue{
+			Rule:    "class",
+			Message: fmt.Sprintf("unexpected class name %q (%d occurrence(s))", c, unknown[c]),
+		})
+	}
+
+	return issues, nil
+}
+
+// checkVerseNumbering checks that verse spans form a continuous sequence starting at 1
+// ESG (Esther Greek) chapters start mid-sequence, so only ordering is checked for them
+func checkVerseNumbering(verseNums []int, filename string) []StructureIssue {
+	var issues []StructureIssue
+
+	if len(verseNums) == 0 {
+		return append(issues, StructureIssue{Rule: "verses", Message: "no verse spans found"})
+	}
+
+	contiguous := !strings.HasPrefix(filename, "ESG")
+	if contiguous && verseNums[0] != 1 {
+		issues = append(issues, StructureIssue{
+			Rule:    "verses",
+			Message: fmt.Sprintf("verses do not start at 1 (first verse is %d)", verseNums[0]),
+		})
+	}
+
+	for i := 1; i < len(verseNums); i++ {
+		prev, cur := verseNums[i-1], verseNums[i]
+		if (contiguous && cur != prev+1) || (!contiguous && cur <= prev) {
+			issues = append(issues, StructureIssue{
+				Rule:    "verses",
+				Message: fmt.Sprintf("verse %d follows verse %d", cur, prev),
+			})
+		}
+	}
+
+	return issues
+}
+
+// checkFootnoteParagraph checks that a <p class="f"> entry has an id, mark, back reference, and text
+func checkFootnoteParagraph(n *html.Node, id string) []StructureIssue {
+	var issues []StructureIssue
+
+	if id == "" {
+		issues = append(issues, StructureIssue{Rule: "footnotes", Message: "footnote paragraph has no id"})
+		id = "(unnamed)"
+	}
+
+	var hasMark, hasBackref, hasText bool
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		switch {
+		case child.Data == "span" && hasClass(child, "notemark"):
+			hasMark = strings.TrimSpace(textContent(child)) != ""
+		case child.Data == "span" && hasClass(child, "ft"):
+			hasText = strings.TrimSpace(textContent(child)) != ""
+		case child.Data == "a" && hasClass(child, "notebackref"):
+			href := attrOf(child, "href")
+			if _, err := strconv.Atoi(strings.TrimPrefix(href, "#V")); err == nil && strings.HasPrefix(href, "#V") {
+				hasBackref = true
+			}
+		}
+	}
+
+	if !hasMark {
+		issues = append(issues, StructureIssue{Rule: "footnotes", Message: fmt.Sprintf("footnote %s has no mark", id)})
+	}
+	if !hasBackref {
+		issues = append(issues, StructureIssue{
+			Rule:    "footnotes",
+			Message: fmt.Sprintf("footnote %s has no valid verse back reference", id),
+		})
+	}
+	if !hasText {
+		issues = append(issues, StructureIssue{Rule: "footnotes", Message: fmt.Sprintf("footnote %s has no text", id)})
+	}
+
+	return issues
+}
+
+// classesOf returns the class names of an HTML node
+func classesOf(n *html.Node) []string {
+	return strings.Fields(attrOf(n, "class"))
+}
+
+// hasClass checks if an HTML node has a given class
+func hasClass(n *html.Node, className string) bool {
+	for _, c := range classesOf(n) {
+		if c == className {
+			return true
+		}
+	}
+	return false
+}
+
+// attrOf returns the value of an attribute, or "" if absent
+func attrOf(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// textContent extracts all text content from a node and its children
+func textContent(n *html.Node) string {
+	var text strings.Builder
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			text.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+
+	walk(n)
+	return text.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
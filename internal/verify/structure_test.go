@@ -0,0 +1,103 @@
+package verify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckStructure(t *testing.T) {
+	tests := []struct {
+		name      string
+		filename  string
+		html      string
+		wantRules []string
+	}{
+		{
+			name:     "well-formed chapter",
+			filename: "OBA01.htm",
+			html: `<div class="main"><div class='chapterlabel'> 1</div><div class='p'>
+<span class="verse" id="V1">1&#160;</span>The vision.<a href="#FN1" class="notemark">*<span class="popup">x</span></a>
+<span class="verse" id="V2">2&#160;</span>Behold.</div>
+<div class="footnote"><p class="f" id="FN1"><span class="notemark">*</span><a class="notebackref" href="#V1">1.1</a>
+<span class="ft">vision: or, sight</span></p></div></div>`,
+			wantRules: nil,
+		},
+		{
+			name:      "missing chapter label",
+			filename:  "OBA01.htm",
+			html:      `<div class="main"><div class='p'><span class="verse" id="V1">1&#160;</span>Text</div></div>`,
+			wantRules: []string{"chapterlabel"},
+		},
+		{
+			name:     "verse gap",
+			filename: "OBA01.htm",
+			html: `<div class='chapterlabel'>1</div><span class="verse">1&#160;</span>a
+<span class="verse">3&#160;</span>b`,
+			wantRules: []string{"verses"},
+		},
+		{
+			name:      "dangling note mark",
+			filename:  "OBA01.htm",
+			html:      `<div class='chapterlabel'>1</div><span class="verse">1</span>a<a href="#FN9" class="notemark">*</a>`,
+			wantRules: []string{"footnotes"},
+		},
+		{
+			name:      "unexpected class",
+			filename:  "OBA01.htm",
+			html:      `<div class='chapterlabel'>1</div><span class="verse">1</span><span class="sc">a</span>`,
+			wantRules: []string{"class"},
+		},
+		{
+			name:      "intro files need no chapter label or verses",
+			filename:  "GEN00.htm",
+			html:      `<div class="main"><div class='is'>Introduction</div></div>`,
+			wantRules: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := checkStructure([]byte(tt.html), tt.filename)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gotRules []string
+			for _, issue := range issues {
+				gotRules = append(gotRules, issue.Rule)
+			}
+
+			if strings.Join(gotRules, ",") != strings.Join(tt.wantRules, ",") {
+				t.Errorf("expected rules %v, got %v (%v)", tt.wantRules, gotRules, issues)
+			}
+		})
+	}
+}
+
+func TestAcceptIssues(t *testing.T) {
+	issues := []StructureIssue{{Rule: "class", Message: "a"}, {Rule: "verses", Message: "b"}}
+
+	tests := []struct {
+		name      string
+		except    []string
+		wantRules []string
+	}{
+		{name: "no exceptions", except: nil, wantRules: []string{"class", "verses"}},
+		{name: "file and rule", except: []string{"GEN01.htm:class"}, wantRules: []string{"verses"}},
+		{name: "other file", except: []string{"EXO01.htm:class"}, wantRules: []string{"class", "verses"}},
+		{name: "any file", except: []string{"*:verses"}, wantRules: []string{"class"}},
+		{name: "whole file", except: []string{"GEN01.htm"}, wantRules: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRules []string
+			for _, issue := range acceptIssues(issues, "GEN01.htm", tt.except) {
+				gotRules = append(gotRules, issue.Rule)
+			}
+			if strings.Join(gotRules, ",") != strings.Join(tt.wantRules, ",") {
+				t.Errorf("expected rules %v, got %v", tt.wantRules, gotRules)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package verify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// checkTopics reports topics.json entries whose identifier is not lowercase or whose references
+// do not parse or name verses missing from the canon. A canon without topics.json has nothing to check.
+func checkTopics(canonDir, indexDir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(indexDir, "topics.json")) // nolint: gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topics.json: %w", err)
+	}
+
+	var topics model.Topics
+	if err := json.Unmarshal(data, &topics); err != nil {
+		return nil, fmt.Errorf("failed to parse topics.json: %w", err)
+	}
+	if topics.Schema != model.TopicsSchema {
+		return []string{fmt.Sprintf("unsupported schema version %d", topics.Schema)}, nil
+	}
+
+	corpus, err := kjvcorpus.Open(canonDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	names := make([]string, 0, len(topics.Topics))
+	for name := range topics.Topics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		if name != strings.ToLower(name) {
+			problems = append(problems, fmt.Sprintf("topic %q: identifier must be lowercase", name))
+		}
+		for _, s := range topics.Topics[name].Refs {
+			ref, err := bibleref.Parse(s, corpus.Table())
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("topic %q: invalid reference %q: %v", name, s, err))
+				continue
+			}
+			if missing := missingVerses(corpus, ref); missing != "" {
+				problems = append(problems, fmt.Sprintf("topic %q: %s %s", name, s, missing))
+			}
+		}
+	}
+	return problems, nil
+}
+
+// missingVerses describes what ref names that the canon lacks, or returns "" if every verse exists
+func missingVerses(corpus *kjvcorpus.Corpus, ref *bibleref.BibleRef) string {
+	chapter := *ref
+	chapter.Verse = nil
+	if chapter.Chapter == 0 {
+		chapter.Chapter = 1
+	}
+	resolved, err := corpus.Resolve(&chapter)
+	if err != nil {
+		return fmt.Sprintf("does not resolve: %v", err)
+	}
+	if ref.Verse == nil {
+		return ""
+	}
+
+	present := make(map[int]bool, len(resolved.Verses))
+	for _, verse := range resolved.Verses {
+		present[verse.V] = true
+	}
+	start, end := ref.Verse.StartVerse, ref.Verse.StartVerse
+	if ref.Verse.EndVerse != nil {
+		end = *ref.Verse.EndVerse
+	}
+
+	var missing []string
+	for v := start; v <= end; v++ {
+		if !present[v] {
+			missing = append(missing, fmt.Sprint(v))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Sprintf("names missing verses %s", strings.Join(missing, ", "))
+	}
+	return ""
+}
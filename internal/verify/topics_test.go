@@ -0,0 +1,66 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckTopics(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+	canon := filepath.Join(cwd, "canon", "kjv")
+
+	// The committed topical index is clean
+	problems, err := checkTopics(canon, filepath.Join(canon, "index"))
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("expected no problems in canon topics, got %v, %v", problems, err)
+	}
+
+	indexDir := t.TempDir()
+	topics := `{"schema": 1, "topics": {
+		"ok": {"name": "OK", "refs": ["Genesis 1", "John 3:16-18", "Esther (Greek) 10:4"]},
+		"Bad": {"name": "Bad", "refs": ["Nope 1:1", "Esther (Greek) 2", "John 3:35-37", "Esther (Greek) 10:1-4"]}
+	}}`
+	if err := os.WriteFile(filepath.Join(indexDir, "topics.json"), []byte(topics), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err = checkTopics(canon, indexDir)
+	if err != nil {
+		t.Fatalf("checkTopics failed: %v", err)
+	}
+	want := []string{
+		`topic "Bad": identifier must be lowercase`,
+		`topic "Bad": invalid reference "Nope 1:1"`,
+		`topic "Bad": Esther (Greek) 2 does not resolve`,
+		`topic "Bad": John 3:35-37 names missing verses 37`,
+		`topic "Bad": Esther (Greek) 10:1-4 names missing verses 1, 2, 3`,
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("expected %d problems, got %d: %v", len(want), len(problems), problems)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(problems[i], prefix) {
+			t.Errorf("problem %d: expected prefix %q, got %q", i, prefix, problems[i])
+		}
+	}
+
+	// Without topics.json there is nothing to check
+	if problems, err := checkTopics(canon, t.TempDir()); err != nil || problems != nil {
+		t.Errorf("expected no problems without topics.json, got %v, %v", problems, err)
+	}
+}
@@ -0,0 +1,225 @@
+package verify
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+// upstreamChapterPattern matches chapter file names in the upstream archive, e.g. GEN01.htm or PSA119.htm
+var upstreamChapterPattern = regexp.MustCompile(`^[0-9A-Z]{3}\d{2,3}\.htm$`)
+
+// UpstreamDiff lists the chapter files that differ between the upstream archive and the local manifest
+type UpstreamDiff struct {
+	Changed []string // present in both with different hashes
+	Added   []string // present upstream but not in the local manifest
+	Removed []string // present in the local manifest but no longer upstream
+	Checked int
+}
+
+func (u *UpstreamCmd) Run(ctx context.Context) error {
+	manifest, err := util.ReadManifest(filepath.Join(u.Raw, ManifestFileName))
+	if err != nil {
+		util.StopProgress(ctx)
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, u.Timeout)
+	defer cancel()
+
+	if u.Head {
+		stale, err := u.checkHead(ctx, manifest)
+		util.StopProgress(ctx)
+		if err != nil {
+			return err
+		}
+		if stale {
+			return util.Exit(util.ExitErrors, fmt.Errorf("upstream source may have been updated since the local manifest was generated; run without --head to compare the files"))
+		}
+		fmt.Println("Upstream source appears unchanged since the local manifest was generated; run without --head to compare the files")
+		return nil
+	}
+
+	archive, err := u.download(ctx)
+	if err != nil {
+		util.StopProgress(ctx)
+		return err
+	}
+
+	diff, err := compareUpstream(archive, manifest)
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range diff.Changed {
+		fmt.Printf("Changed upstream: %s\n", name)
+	}
+	for _, name := range diff.Added {
+		fmt.Printf("Added upstream: %s\n", name)
+	}
+	for _, name := range diff.Removed {
+		fmt.Printf("Removed upstream: %s\n", name)
+	}
+
+	fmt.Println("========================================")
+	fmt.Printf("Upstream Files Compared: %d\n", diff.Checked)
+	fmt.Printf("Changed: %d\n", len(diff.Changed))
+	fmt.Printf("Added: %d\n", len(diff.Added))
+	fmt.Printf("Removed: %d\n", len(diff.Removed))
+	fmt.Println("========================================")
+
+	if len(diff.Changed) > 0 || len(diff.Added) > 0 || len(diff.Removed) > 0 {
+		return util.Exit(util.ExitErrors, fmt.Errorf("upstream source has been revised; refresh raw/ and regenerate the corpus"))
+	}
+
+	fmt.Println("Raw sources match upstream")
+	return nil
+}
+
+// checkHead compares the upstream Last-Modified header against the manifest's generation time
+// without downloading the archive. This is only a heuristic: Generated records when the manifest
+// was written, not when raw/ was fetched, so an archive revised before a manifest regenerated from
+// an old raw/ goes unnoticed, and one re-stamped without changes is reported. Comparing the
+// downloaded files, as Run does without --head, is authoritative.
+func (u *UpstreamCmd) checkHead(ctx context.Context, manifest util.Manifest) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.URL, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to reach upstream: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Printf("Error closing response body: %v\n", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("upstream returned %s", resp.Status)
+	}
+
+	fmt.Printf("Upstream URL: %s\n", u.URL)
+	fmt.Printf("Last-Modified: %s\n", resp.Header.Get("Last-Modified"))
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		fmt.Printf("ETag: %s\n", etag)
+	}
+	if resp.ContentLength >= 0 {
+		fmt.Printf("Content-Length: %d\n", resp.ContentLength)
+	}
+	fmt.Printf("Manifest Generated: %s\n", manifest.Generated)
+
+	lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified"))
+	if err != nil {
+		return false, fmt.Errorf("upstream did not send a usable Last-Modified header; run without --head")
+	}
+	generated, err := time.Parse(time.RFC3339, manifest.Generated)
+	if err != nil {
+		return false, fmt.Errorf("manifest has no RFC 3339 generation time; run without --head")
+	}
+
+	return lastModified.After(generated), nil
+}
+
+// download fetches the upstream HTML archive into memory
+func (u *UpstreamCmd) download(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download upstream archive: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Printf("Error closing response body: %v\n", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("upstream returned %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upstream archive: %w", err)
+	}
+	return data, nil
+}
+
+// compareUpstream hashes every chapter file in the upstream archive and compares it to the
+// manifest entry with the same file name
+func compareUpstream(archive []byte, manifest util.Manifest) (UpstreamDiff, error) {
+	var diff UpstreamDiff
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return diff, fmt.Errorf("failed to open upstream archive: %w", err)
+	}
+
+	local := make(map[string]string)
+	for _, entry := range manifest.Entries {
+		name := path.Base(filepath.ToSlash(entry.Path))
+		if upstreamChapterPattern.MatchString(name) {
+			local[name] = entry.SHA256
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range reader.File {
+		name := path.Base(file.Name)
+		if !upstreamChapterPattern.MatchString(name) {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return diff, fmt.Errorf("failed to open %s in upstream archive: %w", file.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		if closeErr := rc.Close(); closeErr != nil {
+			fmt.Printf("Error closing %s: %v\n", file.Name, closeErr)
+		}
+		if err != nil {
+			return diff, fmt.Errorf("failed to read %s in upstream archive: %w", file.Name, err)
+		}
+
+		seen[name] = true
+		diff.Checked++
+
+		hash, ok := local[name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, name)
+		case hash != util.SHA256Hex(content):
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+
+	for name := range local {
+		if !seen[name] {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	sort.Strings(diff.Changed)
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+
+	return diff, nil
+}
@@ -0,0 +1,58 @@
+package verify
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func TestCompareUpstream(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"eng-kjv_html/OBA01.htm": "obadiah",
+		"eng-kjv_html/JON01.htm": "jonah revised",
+		"eng-kjv_html/MIC01.htm": "micah",
+		"eng-kjv_html/index.htm": "index",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create zip entry: %v", err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write zip entry: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+
+	manifest := util.Manifest{
+		Schema: util.ManifestSchema,
+		Entries: []util.ManifestEntry{
+			{SHA256: util.SHA256Hex([]byte("obadiah")), Size: 7, Path: "html/ot/OBA/OBA01.htm"},
+			{SHA256: util.SHA256Hex([]byte("jonah")), Size: 5, Path: "html/ot/JON/JON01.htm"},
+			{SHA256: util.SHA256Hex([]byte("nahum")), Size: 5, Path: "html/ot/NAM/NAM01.htm"},
+		},
+	}
+
+	diff, err := compareUpstream(buf.Bytes(), manifest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff.Checked != 3 {
+		t.Errorf("expected 3 upstream chapter files compared, got %d", diff.Checked)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0] != "JON01.htm" {
+		t.Errorf("expected JON01.htm to be changed, got %v", diff.Changed)
+	}
+	if len(diff.Added) != 1 || diff.Added[0] != "MIC01.htm" {
+		t.Errorf("expected MIC01.htm to be added, got %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0] != "NAM01.htm" {
+		t.Errorf("expected NAM01.htm to be removed, got %v", diff.Removed)
+	}
+}
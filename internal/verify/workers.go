@@ -0,0 +1,74 @@
+package verify
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// chapterCheck is the result of validating one chapter file
+type chapterCheck struct {
+	path       string
+	chapter    *model.Chapter  // nil if the file failed validation
+	err        error           // why the file failed validation
+	mismatches []plainMismatch // verses whose plain field disagrees with their tokens
+	plain      *plainStats     // the plain/token tally of the file alone
+}
+
+// checkChapter validates the chapter file at path and compares its plain text to its tokens
+func checkChapter(path string, verseIndex model.VerseIndex) chapterCheck {
+	check := chapterCheck{path: path, plain: newPlainStats()}
+	check.chapter, check.err = validateChapterFile(path, verseIndex)
+	if check.err == nil {
+		check.mismatches = checkPlain(check.chapter, check.plain)
+	}
+	return check
+}
+
+// checkChapters checks the chapter files at paths with a pool of workers, one per CPU when workers
+// is less than 1, and calls report with each file's check in the order of paths, so the output does
+// not depend on scheduling. Once report returns false, the files not yet checked are skipped.
+func checkChapters(paths []string, verseIndex model.VerseIndex, workers int, report func(chapterCheck) bool) {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+
+	// Each file gets its own buffered channel, so workers never wait on report
+	checks := make([]chan chapterCheck, len(paths))
+	for i := range checks {
+		checks[i] = make(chan chapterCheck, 1)
+	}
+	jobs := make(chan int)
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				checks[i] <- checkChapter(paths[i], verseIndex)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range paths {
+			select {
+			case jobs <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for i := range paths {
+		if !report(<-checks[i]) {
+			break
+		}
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,85 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// writeChapters writes n chapter files of Gen, with a gap in the verses of the chapters in broken
+// and plain text that disagrees with the tokens
+func writeChapters(t *testing.T, n int, broken ...int) []string {
+	dir := t.TempDir()
+	paths := make([]string, n)
+	for i := range paths {
+		doc := model.Chapter{Schema: 1, Work: "KJV", OSIS: "Gen", Abbr: "GEN", Chapter: i + 1}
+		for _, v := range []int{1, 2, 3} {
+			if v == 2 && slices.Contains(broken, i+1) {
+				continue
+			}
+			doc.Verses = append(doc.Verses, model.Verse{V: v, Tokens: []model.Token{{Text: "text"}}, Plain: "texts"})
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths[i] = filepath.Join(dir, fmt.Sprintf("ch%02d.json", i+1))
+		if err := os.WriteFile(paths[i], data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return paths
+}
+
+func TestCheckChapters(t *testing.T) {
+	paths := writeChapters(t, 20, 7, 12)
+
+	for _, workers := range []int{0, 1, 4} {
+		var reported []string
+		var failed []int
+		plain := newPlainStats()
+		checkChapters(paths, model.NewVerseIndex(), workers, func(check chapterCheck) bool {
+			reported = append(reported, check.path)
+			if check.err != nil {
+				failed = append(failed, len(reported))
+				return true
+			}
+			plain.add(check.plain)
+			return true
+		})
+
+		if len(reported) != len(paths) {
+			t.Fatalf("workers=%d: expected %d checks, got %d", workers, len(paths), len(reported))
+		}
+		for i, path := range reported {
+			if path != paths[i] {
+				t.Fatalf("workers=%d: expected checks in order, got %s at %d", workers, path, i)
+			}
+		}
+		if fmt.Sprint(failed) != "[7 12]" {
+			t.Errorf("workers=%d: expected chapters 7 and 12 to fail, got %v", workers, failed)
+		}
+		// 18 valid chapters of 3 verses, whose plain text disagrees with their tokens
+		if plain.Verses != 54 || plain.Kinds[plainText] != 54 {
+			t.Errorf("workers=%d: unexpected plain tally: %s", workers, plain)
+		}
+	}
+}
+
+func TestCheckChaptersStops(t *testing.T) {
+	paths := writeChapters(t, 50, 3)
+
+	var reported int
+	checkChapters(paths, model.NewVerseIndex(), 4, func(check chapterCheck) bool {
+		reported++
+		return check.err == nil
+	})
+	if reported != 3 {
+		t.Errorf("expected reporting to stop at the first failed chapter, got %d checks", reported)
+	}
+}
@@ -0,0 +1,248 @@
+// Package annotations stores user highlights and notes keyed to references. Annotations live in
+// their own JSON file, never in the canon, so regenerating the canon leaves them untouched.
+package annotations
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/julianstephens/canonref/bibleref"
+	"github.com/julianstephens/canonref/util"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+)
+
+// Schema is the current schema version of the annotations file
+const Schema = 1
+
+// ErrNotFound is returned by Delete when no annotation has the given ID
+var ErrNotFound = errors.New("annotation not found")
+
+// Kind is the type of an annotation
+type Kind string
+
+const (
+	KindHighlight Kind = "highlight" // marks verses, optionally with a color
+	KindNote      Kind = "note"      // attaches text to verses
+)
+
+// Kinds lists the annotation kinds
+var Kinds = []Kind{KindHighlight, KindNote}
+
+// File is the structure of the annotations file
+type File struct {
+	Schema      int          `json:"schema"`
+	Annotations []Annotation `json:"annotations"`
+}
+
+// Annotation is a highlight or note on a chapter or a range of verses within one chapter
+type Annotation struct {
+	ID         string    `json:"id"`
+	OSIS       string    `json:"osis"`
+	Chapter    int       `json:"chapter"`
+	StartVerse int       `json:"start_verse,omitempty"` // 0 when the annotation covers the whole chapter
+	EndVerse   int       `json:"end_verse,omitempty"`
+	Kind       Kind      `json:"kind"`
+	Color      string    `json:"color,omitempty"` // highlight color, free-form such as "yellow"
+	Text       string    `json:"text,omitempty"`  // note text
+	Created    time.Time `json:"created"`
+}
+
+// Ref returns the reference the annotation is keyed to
+func (a Annotation) Ref() *bibleref.BibleRef {
+	ref := &bibleref.BibleRef{OSIS: a.OSIS, Chapter: a.Chapter}
+	if a.StartVerse > 0 {
+		ref.Verse = &util.VerseRange{StartVerse: a.StartVerse}
+		if a.EndVerse > a.StartVerse {
+			end := a.EndVerse
+			ref.Verse.EndVerse = &end
+		}
+	}
+	return ref
+}
+
+// Covers reports whether the annotation applies to a verse of its book
+func (a Annotation) Covers(chapter, verse int) bool {
+	if chapter != a.Chapter {
+		return false
+	}
+	return a.StartVerse == 0 || (verse >= a.StartVerse && verse <= max(a.EndVerse, a.StartVerse))
+}
+
+// overlaps reports whether the annotation applies to any verse of ref
+func (a Annotation) overlaps(ref *bibleref.BibleRef) bool {
+	if a.OSIS != ref.OSIS || a.Chapter != ref.Chapter {
+		return false
+	}
+	if a.StartVerse == 0 || ref.Verse == nil {
+		return true
+	}
+	start, end := ref.Verse.StartVerse, ref.Verse.StartVerse
+	if ref.Verse.EndVerse != nil {
+		end = *ref.Verse.EndVerse
+	}
+	return a.StartVerse <= end && max(a.EndVerse, a.StartVerse) >= start
+}
+
+// Store is an annotations file loaded into memory. Every change is written back to the file
+// before it returns. A Store is safe for concurrent use.
+type Store struct {
+	path string
+
+	mu          sync.RWMutex
+	annotations []Annotation
+}
+
+// Open loads the annotations file at path. A file that does not exist yet is created by the first Add.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+
+	data, err := os.ReadFile(path) // nolint: gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read annotations: %w", err)
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse annotations: %w", err)
+	}
+	if file.Schema != Schema {
+		return nil, fmt.Errorf("unsupported annotations schema version %d", file.Schema)
+	}
+	s.annotations = file.Annotations
+
+	return s, nil
+}
+
+// Add keys an annotation to ref, assigning its ID and, if unset, its creation time, and saves
+// the store. The reference and ID already on the annotation are ignored.
+func (s *Store) Add(ref *bibleref.BibleRef, a Annotation) (Annotation, error) {
+	if ref.OSIS == "" || ref.Chapter < 1 {
+		return Annotation{}, fmt.Errorf("annotation reference needs a book and chapter")
+	}
+	if !slices.Contains(Kinds, a.Kind) {
+		return Annotation{}, fmt.Errorf("unknown annotation kind %q", a.Kind)
+	}
+	if a.Kind == KindNote && a.Text == "" {
+		return Annotation{}, fmt.Errorf("note has no text")
+	}
+
+	a.OSIS = ref.OSIS
+	a.Chapter = ref.Chapter
+	a.StartVerse, a.EndVerse = 0, 0
+	if ref.Verse != nil {
+		a.StartVerse = ref.Verse.StartVerse
+		a.EndVerse = ref.Verse.StartVerse
+		if ref.Verse.EndVerse != nil {
+			a.EndVerse = *ref.Verse.EndVerse
+		}
+		if a.StartVerse < 1 || a.EndVerse < a.StartVerse {
+			return Annotation{}, fmt.Errorf("invalid verse range %d-%d", a.StartVerse, a.EndVerse)
+		}
+	}
+	if a.Created.IsZero() {
+		a.Created = time.Now().UTC()
+	}
+	id, err := newID()
+	if err != nil {
+		return Annotation{}, err
+	}
+	a.ID = id
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.annotations = append(s.annotations, a)
+	if err := s.save(); err != nil {
+		s.annotations = s.annotations[:len(s.annotations)-1]
+		return Annotation{}, err
+	}
+	return a, nil
+}
+
+// List returns the annotations on any verse of ref in verse order, or every annotation when ref is nil
+func (s *Store) List(ref *bibleref.BibleRef) []Annotation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []Annotation
+	for _, a := range s.annotations {
+		if ref == nil || a.overlaps(ref) {
+			result = append(result, a)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.OSIS != b.OSIS {
+			return a.OSIS < b.OSIS
+		}
+		if a.Chapter != b.Chapter {
+			return a.Chapter < b.Chapter
+		}
+		if a.StartVerse != b.StartVerse {
+			return a.StartVerse < b.StartVerse
+		}
+		return a.Created.Before(b.Created)
+	})
+	return result
+}
+
+// Delete removes the annotation with the given ID and saves the store
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.annotations, func(a Annotation) bool { return a.ID == id })
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	removed := s.annotations[i]
+	s.annotations = slices.Delete(s.annotations, i, i+1)
+	if err := s.save(); err != nil {
+		s.annotations = slices.Insert(s.annotations, i, removed)
+		return err
+	}
+	return nil
+}
+
+// save writes the annotations file; the caller must hold mu
+func (s *Store) save() error {
+	file := File{Schema: Schema, Annotations: s.annotations}
+	if file.Annotations == nil {
+		file.Annotations = []Annotation{}
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal annotations: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0750); err != nil {
+		return fmt.Errorf("failed to create annotations directory: %w", err)
+	}
+	if err := atomicfile.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write annotations: %w", err)
+	}
+	return nil
+}
+
+// newID returns a random 16-character hex ID
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate annotation ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,152 @@
+package annotations
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/canonref/bibleref"
+	"github.com/julianstephens/canonref/util"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+func verses(osis string, chapter, start, end int) *bibleref.BibleRef {
+	return &bibleref.BibleRef{OSIS: osis, Chapter: chapter, Verse: &util.VerseRange{StartVerse: start, EndVerse: &end}}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes", "annotations.json")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	highlight, err := store.Add(verses("John", 3, 16, 17), Annotation{Kind: KindHighlight, Color: "yellow"})
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := store.Add(&bibleref.BibleRef{OSIS: "John", Chapter: 3}, Annotation{Kind: KindNote, Text: "Nicodemus"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := store.Add(verses("Gen", 1, 1, 1), Annotation{Kind: KindNote, Text: "beginning"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	invalid := []struct {
+		name string
+		ref  *bibleref.BibleRef
+		a    Annotation
+	}{
+		{"unknown kind", verses("Gen", 1, 1, 1), Annotation{Kind: "bookmark"}},
+		{"empty note", verses("Gen", 1, 1, 1), Annotation{Kind: KindNote}},
+		{"no chapter", &bibleref.BibleRef{OSIS: "Gen"}, Annotation{Kind: KindHighlight}},
+		{"reversed range", verses("Gen", 1, 5, 2), Annotation{Kind: KindHighlight}},
+	}
+	for _, tt := range invalid {
+		if _, err := store.Add(tt.ref, tt.a); err == nil {
+			t.Errorf("%s: expected Add to fail", tt.name)
+		}
+	}
+
+	lists := []struct {
+		name string
+		ref  *bibleref.BibleRef
+		want int
+	}{
+		{"overlapping verse", verses("John", 3, 17, 20), 2},
+		{"chapter note only", verses("John", 3, 1, 2), 1},
+		{"whole chapter", &bibleref.BibleRef{OSIS: "John", Chapter: 3}, 2},
+		{"other chapter", &bibleref.BibleRef{OSIS: "John", Chapter: 4}, 0},
+		{"everything", nil, 3},
+	}
+	for _, tt := range lists {
+		if got := store.List(tt.ref); len(got) != tt.want {
+			t.Errorf("%s: expected %d annotations, got %+v", tt.name, tt.want, got)
+		}
+	}
+
+	// Annotations survive reopening the file
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("reopening failed: %v", err)
+	}
+	got := reopened.List(verses("John", 3, 16, 16))
+	if len(got) != 2 || got[0].Kind != KindNote || got[1].ID != highlight.ID || got[1].Ref().String() != "John 3:16–17" {
+		t.Errorf("unexpected annotations after reopening: %+v", got)
+	}
+
+	if err := reopened.Delete(highlight.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := reopened.Delete(highlight.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound deleting twice, got %v", err)
+	}
+	if len(reopened.List(nil)) != 2 {
+		t.Errorf("expected 2 annotations after deleting, got %d", len(reopened.List(nil)))
+	}
+
+	if err := os.WriteFile(path, []byte(`{"schema": 2, "annotations": []}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); err == nil {
+		t.Error("expected a newer schema to be rejected")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+	corpus, err := kjvcorpus.Open(filepath.Join(cwd, "canon", "kjv"))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+
+	store, err := Open(filepath.Join(t.TempDir(), "annotations.json"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, err := store.Add(verses("John", 3, 16, 16), Annotation{Kind: KindHighlight}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Add(verses("John", 3, 17, 17), Annotation{Kind: KindNote, Text: "see 12:47"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := corpus.Resolve(verses("John", 3, 15, 17))
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	text, err := store.Format(resolved, kjvcorpus.PresetLines)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	lines := strings.Split(text, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", text)
+	}
+	if strings.Contains(lines[0], HighlightOpen) || strings.Contains(lines[0], "[note:") {
+		t.Errorf("expected verse 15 to be unannotated, got %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "16 "+HighlightOpen+"For God") || !strings.HasSuffix(lines[1], HighlightClose) {
+		t.Errorf("expected verse 16 to be highlighted, got %q", lines[1])
+	}
+	if !strings.HasSuffix(lines[2], " [note: see 12:47]") {
+		t.Errorf("expected the note after verse 17, got %q", lines[2])
+	}
+}
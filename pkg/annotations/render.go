@@ -0,0 +1,54 @@
+package annotations
+
+import (
+	"strings"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Highlight marks are placed around highlighted verse text by Hook, following the Markdown
+// highlight extension
+const (
+	HighlightOpen  = "=="
+	HighlightClose = "=="
+)
+
+// Hook returns a kjvcorpus.VerseHook that displays annotations alongside resolved text: verses
+// under a highlight are wrapped in highlight marks, and each note on a verse follows its text as
+// "[note: ...]". Annotations on other books or chapters are ignored.
+func Hook(resolved *kjvcorpus.Resolved, annotations []Annotation) kjvcorpus.VerseHook {
+	osis, chapter := resolved.Chapter.OSIS, resolved.Chapter.Chapter
+
+	return func(verse model.Verse, text string) string {
+		highlighted := false
+		var notes []string
+		for _, a := range annotations {
+			if a.OSIS != osis || !a.Covers(chapter, verse.V) {
+				continue
+			}
+			switch a.Kind {
+			case KindHighlight:
+				highlighted = true
+			case KindNote:
+				notes = append(notes, "[note: "+a.Text+"]")
+			}
+		}
+
+		if highlighted {
+			text = HighlightOpen + text + HighlightClose
+		}
+		if len(notes) > 0 {
+			text += " " + strings.Join(notes, " ")
+		}
+		return text
+	}
+}
+
+// Format formats resolved with preset, displaying the store's annotations on its verses
+func (s *Store) Format(resolved *kjvcorpus.Resolved, preset kjvcorpus.Preset) (string, error) {
+	chapter := &bibleref.BibleRef{OSIS: resolved.Chapter.OSIS, Chapter: resolved.Chapter.Chapter}
+	return resolved.FormatWith(preset, Hook(resolved, s.List(chapter)))
+}
@@ -0,0 +1,77 @@
+// Package atomicfile writes files by way of a temporary file renamed into place, so a reader, or a
+// run that follows an interrupted one, sees either the old contents or the new and never a
+// truncated file. Every writer of the canon, its indexes, and the raw manifest goes through it.
+package atomicfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+var syncWrites atomic.Bool
+
+// SetSync sets whether WriteFile flushes each file, and the directory it is renamed into, to
+// stable storage before returning. The rename alone survives an interrupted process; syncing also
+// survives a crash or power loss, at the cost of a much slower ingest. It is off by default.
+func SetSync(enabled bool) {
+	syncWrites.Store(enabled)
+}
+
+// WriteFile writes data to path with the given permissions, like os.WriteFile, but writes to a
+// temporary file in the same directory first and renames it over path once it is complete. The
+// temporary file is removed if anything fails.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	if err := write(tmp, data, perm); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	if syncWrites.Load() {
+		return syncDir(dir)
+	}
+	return nil
+}
+
+// write fills the temporary file and gives it its final permissions
+func write(tmp *os.File, data []byte, perm os.FileMode) error {
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if syncWrites.Load() {
+		return tmp.Sync()
+	}
+	return nil
+}
+
+// syncDir flushes a directory, so a rename into it is durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir) // nolint: gosec
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		_ = d.Close()
+		return fmt.Errorf("failed to sync %s: %w", dir, err)
+	}
+	return d.Close()
+}
@@ -0,0 +1,62 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	for _, sync := range []bool{false, true} {
+		SetSync(sync)
+		dir := t.TempDir()
+		path := filepath.Join(dir, "ch01.json")
+
+		if err := WriteFile(path, []byte(`{"chapter":1}`), 0600); err != nil {
+			t.Fatalf("WriteFile failed (sync %v): %v", sync, err)
+		}
+		if err := WriteFile(path, []byte(`{"chapter":2}`), 0600); err != nil {
+			t.Fatalf("WriteFile failed to replace a file (sync %v): %v", sync, err)
+		}
+
+		data, err := os.ReadFile(path) // nolint: gosec
+		if err != nil || string(data) != `{"chapter":2}` {
+			t.Errorf("expected the replaced contents, got %q, %v", data, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+			t.Errorf("expected no temporary files left behind, got %d entries", len(entries))
+		}
+	}
+	SetSync(false)
+}
+
+func TestWriteFileFailureKeepsOriginal(t *testing.T) {
+	dir := t.TempDir()
+	// A directory cannot be replaced by a file, so the rename fails after the data is written
+	path := filepath.Join(dir, "books")
+	if err := os.Mkdir(path, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "keep.json"), []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFile(path, []byte("truncated"), 0600); err == nil {
+		t.Fatal("expected replacing a directory to fail")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || !entries[0].IsDir() {
+		t.Errorf("expected only the original directory to remain, got %v", entries)
+	}
+
+	if err := WriteFile(filepath.Join(dir, "missing", "ch01.json"), nil, 0600); err == nil {
+		t.Error("expected a missing directory to fail")
+	}
+}
@@ -0,0 +1,290 @@
+// Package bookmarks stores a reader's place in the canon: the last passage read, named bookmarks,
+// and the history of passages visited. References are checked against the corpus before they are
+// stored. The state lives in its own JSON file under the XDG data directory, never in the canon,
+// so a reader CLI and a TUI browser can share it.
+package bookmarks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+// Schema is the current schema version of the bookmarks file
+const Schema = 1
+
+// MaxHistory is the number of visits the history keeps; older visits are dropped
+const MaxHistory = 100
+
+// ErrNotFound is returned by DeleteBookmark when no bookmark has the given name
+var ErrNotFound = errors.New("bookmark not found")
+
+// File is the structure of the bookmarks file
+type File struct {
+	Schema    int        `json:"schema"`
+	LastRead  *Visit     `json:"last_read,omitempty"`
+	Bookmarks []Bookmark `json:"bookmarks"`
+	History   []Visit    `json:"history"` // oldest first
+}
+
+// Position is a chapter, or a verse or range of verses within one chapter, as the corpus resolved it
+type Position struct {
+	OSIS       string `json:"osis"`
+	Chapter    int    `json:"chapter"`
+	StartVerse int    `json:"start_verse,omitempty"` // 0 for the whole chapter
+	EndVerse   int    `json:"end_verse,omitempty"`   // 0 for a single verse
+}
+
+// Ref returns the reference the position is keyed to
+func (p Position) Ref() kjvcorpus.Ref {
+	ref := kjvcorpus.Ref{OSIS: p.OSIS, Chapter: p.Chapter}
+	if p.StartVerse > 0 {
+		ref.Verses = &kjvcorpus.VerseRange{Start: p.StartVerse, End: p.EndVerse}
+	}
+	return ref
+}
+
+// Bookmark is a named position
+type Bookmark struct {
+	Name string `json:"name"`
+	Position
+	Created time.Time `json:"created"`
+}
+
+// Visit is a position and when it was read
+type Visit struct {
+	Position
+	At time.Time `json:"at"`
+}
+
+// DefaultPath returns the bookmarks file in the XDG data directory,
+// $XDG_DATA_HOME/kjv-sources/bookmarks.json, or ~/.local/share/kjv-sources/bookmarks.json when
+// XDG_DATA_HOME is unset
+func DefaultPath() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find the data directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "kjv-sources", "bookmarks.json"), nil
+}
+
+// Store is a bookmarks file loaded into memory. Every change is written back to the file before
+// it returns. A Store is safe for concurrent use.
+type Store struct {
+	path   string
+	corpus *kjvcorpus.Corpus
+
+	mu   sync.RWMutex
+	file File
+}
+
+// Open loads the bookmarks file at path, checking the references later stored in it against
+// corpus. A file that does not exist yet is created by the first change.
+func Open(path string, corpus *kjvcorpus.Corpus) (*Store, error) {
+	s := &Store{path: path, corpus: corpus, file: File{Schema: Schema}}
+
+	data, err := os.ReadFile(path) // nolint: gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &s.file); err != nil {
+		return nil, fmt.Errorf("failed to parse bookmarks: %w", err)
+	}
+	if s.file.Schema != Schema {
+		return nil, fmt.Errorf("unsupported bookmarks schema version %d", s.file.Schema)
+	}
+
+	return s, nil
+}
+
+// Visit records ref as the last passage read and adds it to the history, unless it is the most
+// recent visit already, and saves the store. ref must resolve in the corpus; it is stored with
+// the book's OSIS code from books.json and its range cut to the verses the chapter has.
+func (s *Store) Visit(ref kjvcorpus.Ref) (Visit, error) {
+	pos, err := s.position(ref)
+	if err != nil {
+		return Visit{}, err
+	}
+	visit := Visit{Position: pos, At: time.Now().UTC()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.file
+	prev.History = slices.Clone(s.file.History)
+
+	s.file.LastRead = &visit
+	if n := len(s.file.History); n == 0 || s.file.History[n-1].Position != pos {
+		s.file.History = append(s.file.History, visit)
+	} else {
+		s.file.History[n-1] = visit
+	}
+	if excess := len(s.file.History) - MaxHistory; excess > 0 {
+		s.file.History = slices.Delete(s.file.History, 0, excess)
+	}
+
+	if err := s.save(); err != nil {
+		s.file = prev
+		return Visit{}, err
+	}
+	return visit, nil
+}
+
+// LastRead returns the last passage read, reporting false before the first Visit
+func (s *Store) LastRead() (Visit, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.file.LastRead == nil {
+		return Visit{}, false
+	}
+	return *s.file.LastRead, true
+}
+
+// History returns the visits in the history, most recent first
+func (s *Store) History() []Visit {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	history := slices.Clone(s.file.History)
+	slices.Reverse(history)
+	return history
+}
+
+// ClearHistory removes every visit from the history, keeping the last passage read, and saves
+// the store
+func (s *Store) ClearHistory() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	history := s.file.History
+	s.file.History = nil
+	if err := s.save(); err != nil {
+		s.file.History = history
+		return err
+	}
+	return nil
+}
+
+// AddBookmark bookmarks ref under name, replacing any bookmark with the same name, and saves the
+// store. An empty name defaults to the reference, such as "John 3:16". ref must resolve in the
+// corpus, as for Visit.
+func (s *Store) AddBookmark(name string, ref kjvcorpus.Ref) (Bookmark, error) {
+	pos, err := s.position(ref)
+	if err != nil {
+		return Bookmark{}, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = pos.Ref().String()
+	}
+	bookmark := Bookmark{Name: name, Position: pos, Created: time.Now().UTC()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bookmarks := s.file.Bookmarks
+	s.file.Bookmarks = slices.DeleteFunc(slices.Clone(bookmarks), func(b Bookmark) bool { return b.Name == name })
+	s.file.Bookmarks = append(s.file.Bookmarks, bookmark)
+	if err := s.save(); err != nil {
+		s.file.Bookmarks = bookmarks
+		return Bookmark{}, err
+	}
+	return bookmark, nil
+}
+
+// Bookmark returns the bookmark with the given name
+func (s *Store) Bookmark(name string) (Bookmark, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i := slices.IndexFunc(s.file.Bookmarks, func(b Bookmark) bool { return b.Name == name })
+	if i < 0 {
+		return Bookmark{}, false
+	}
+	return s.file.Bookmarks[i], true
+}
+
+// Bookmarks returns every bookmark in canonical order of their positions, then by name
+func (s *Store) Bookmarks() []Bookmark {
+	s.mu.RLock()
+	bookmarks := slices.Clone(s.file.Bookmarks)
+	s.mu.RUnlock()
+
+	slices.SortStableFunc(bookmarks, func(a, b Bookmark) int {
+		if n := s.corpus.CompareRefs(a.Ref(), b.Ref()); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return bookmarks
+}
+
+// DeleteBookmark removes the bookmark with the given name and saves the store
+func (s *Store) DeleteBookmark(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := slices.IndexFunc(s.file.Bookmarks, func(b Bookmark) bool { return b.Name == name })
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	removed := s.file.Bookmarks[i]
+	s.file.Bookmarks = slices.Delete(s.file.Bookmarks, i, i+1)
+	if err := s.save(); err != nil {
+		s.file.Bookmarks = slices.Insert(s.file.Bookmarks, i, removed)
+		return err
+	}
+	return nil
+}
+
+// position resolves ref against the corpus and returns the position of the verses resolved
+func (s *Store) position(ref kjvcorpus.Ref) (Position, error) {
+	resolved, err := s.corpus.ResolveRef(ref)
+	if err != nil {
+		return Position{}, fmt.Errorf("invalid reference %s: %w", ref, err)
+	}
+	pos := Position{OSIS: resolved.Chapter.OSIS, Chapter: resolved.Chapter.Chapter}
+	if ref.Verses != nil && len(resolved.Verses) > 0 {
+		pos.StartVerse = resolved.Verses[0].V
+		if last := resolved.Verses[len(resolved.Verses)-1].V; last != pos.StartVerse {
+			pos.EndVerse = last
+		}
+	}
+	return pos, nil
+}
+
+// save writes the bookmarks file; the caller must hold mu
+func (s *Store) save() error {
+	file := s.file
+	if file.Bookmarks == nil {
+		file.Bookmarks = []Bookmark{}
+	}
+	if file.History == nil {
+		file.History = []Visit{}
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bookmarks: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0750); err != nil {
+		return fmt.Errorf("failed to create bookmarks directory: %w", err)
+	}
+	if err := atomicfile.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write bookmarks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package bookmarks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+func openCorpus(t *testing.T) *kjvcorpus.Corpus {
+	t.Helper()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	corpus, err := kjvcorpus.Open(filepath.Join(cwd, "canon", "kjv"))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	return corpus
+}
+
+func mustParse(t *testing.T, corpus *kjvcorpus.Corpus, s string) kjvcorpus.Ref {
+	t.Helper()
+	ref, err := corpus.ParseRef(s)
+	if err != nil {
+		t.Fatalf("ParseRef(%q) failed: %v", s, err)
+	}
+	return ref
+}
+
+func TestStore(t *testing.T) {
+	corpus := openCorpus(t)
+	path := filepath.Join(t.TempDir(), "kjv-sources", "bookmarks.json")
+	store, err := Open(path, corpus)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, ok := store.LastRead(); ok {
+		t.Error("expected no last read position in a new store")
+	}
+
+	// Visits are stored as resolved, with the book's OSIS code and the range cut to the chapter
+	visit, err := store.Visit(mustParse(t, corpus, "john 3:35-40"))
+	if err != nil {
+		t.Fatalf("Visit failed: %v", err)
+	}
+	if want := (Position{OSIS: "John", Chapter: 3, StartVerse: 35, EndVerse: 36}); visit.Position != want {
+		t.Errorf("expected %+v, got %+v", want, visit.Position)
+	}
+	for _, s := range []string{"Gen 1", "Gen 1", "Ps 23:1"} {
+		if _, err := store.Visit(mustParse(t, corpus, s)); err != nil {
+			t.Fatalf("Visit(%s) failed: %v", s, err)
+		}
+	}
+	if _, err := store.Visit(kjvcorpus.Ref{OSIS: "John", Chapter: 30}); !errors.Is(err, kjvcorpus.ErrChapterNotFound) {
+		t.Errorf("expected ErrChapterNotFound for a missing chapter, got %v", err)
+	}
+
+	last, ok := store.LastRead()
+	if !ok || last.Ref().String() != "Ps 23:1" {
+		t.Errorf("expected Ps 23:1 last read, got %+v", last)
+	}
+	history := store.History()
+	if len(history) != 3 || history[0].Ref().String() != "Ps 23:1" || history[1].Ref().String() != "Gen 1" || history[2].Ref().String() != "John 3:35–36" {
+		t.Errorf("unexpected history, most recent first: %+v", history)
+	}
+
+	if _, err := store.AddBookmark("evening", mustParse(t, corpus, "Ps 4:8")); err != nil {
+		t.Fatalf("AddBookmark failed: %v", err)
+	}
+	if _, err := store.AddBookmark("", mustParse(t, corpus, "Gen 1:1")); err != nil {
+		t.Fatalf("AddBookmark failed: %v", err)
+	}
+	if _, err := store.AddBookmark("evening", mustParse(t, corpus, "Ps 141:2")); err != nil {
+		t.Fatalf("AddBookmark failed: %v", err)
+	}
+	if _, err := store.AddBookmark("nowhere", kjvcorpus.Ref{OSIS: "Nope", Chapter: 1}); !errors.Is(err, kjvcorpus.ErrUnknownBook) {
+		t.Errorf("expected ErrUnknownBook, got %v", err)
+	}
+
+	// The store is written on every change and reads back the same
+	reopened, err := Open(path, corpus)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	bookmarks := reopened.Bookmarks()
+	if len(bookmarks) != 2 || bookmarks[0].Name != "Gen 1:1" || bookmarks[1].Name != "evening" || bookmarks[1].Ref().String() != "Ps 141:2" {
+		t.Errorf("unexpected bookmarks in canonical order: %+v", bookmarks)
+	}
+	if last, _ := reopened.LastRead(); last.Ref().String() != "Ps 23:1" {
+		t.Errorf("expected the last read position to be saved, got %+v", last)
+	}
+	if len(reopened.History()) != 3 {
+		t.Errorf("expected the history to be saved, got %+v", reopened.History())
+	}
+
+	if err := reopened.DeleteBookmark("evening"); err != nil {
+		t.Fatalf("DeleteBookmark failed: %v", err)
+	}
+	if _, ok := reopened.Bookmark("evening"); ok {
+		t.Error("expected the bookmark to be deleted")
+	}
+	if err := reopened.DeleteBookmark("evening"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := reopened.ClearHistory(); err != nil {
+		t.Fatalf("ClearHistory failed: %v", err)
+	}
+	if len(reopened.History()) != 0 {
+		t.Errorf("expected an empty history, got %+v", reopened.History())
+	}
+	if _, ok := reopened.LastRead(); !ok {
+		t.Error("expected ClearHistory to keep the last read position")
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	corpus := openCorpus(t)
+	store, err := Open(filepath.Join(t.TempDir(), "bookmarks.json"), corpus)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for chapter := 1; chapter <= MaxHistory+5; chapter++ {
+		if _, err := store.Visit(kjvcorpus.Ref{OSIS: "Ps", Chapter: chapter}); err != nil {
+			t.Fatalf("Visit failed: %v", err)
+		}
+	}
+	history := store.History()
+	if len(history) != MaxHistory || history[0].Chapter != MaxHistory+5 || history[len(history)-1].Chapter != 6 {
+		t.Errorf("expected the %d most recent visits, got %d from Ps %d to %d", MaxHistory, len(history), history[0].Chapter, history[len(history)-1].Chapter)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data")
+	if path, err := DefaultPath(); err != nil || path != filepath.Join("/data", "kjv-sources", "bookmarks.json") {
+		t.Errorf("unexpected path %q, %v", path, err)
+	}
+
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("HOME", "/home/reader")
+	if path, err := DefaultPath(); err != nil || path != filepath.Join("/home/reader", ".local", "share", "kjv-sources", "bookmarks.json") {
+		t.Errorf("unexpected fallback path %q, %v", path, err)
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bookmarks.json")
+	if err := os.WriteFile(path, []byte(`{"schema": 2}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, nil); err == nil {
+		t.Error("expected an unsupported schema to fail")
+	}
+}
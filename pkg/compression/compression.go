@@ -0,0 +1,143 @@
+// Package compression compresses canon chapter files for distributions where the size of the
+// canon matters, such as embedded and mobile builds. A compressed chapter is written beside where
+// its JSON would be, with the codec's extension added (books/Gen/ch01.json.zst), and is recognised
+// on reading by its magic number, so readers need not know how a canon was written.
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec names a compression of chapter files
+type Codec string
+
+const (
+	None Codec = "none" // plain JSON, chNN.json
+	Gzip Codec = "gzip" // chNN.json.gz
+	Zstd Codec = "zstd" // chNN.json.zst
+)
+
+// Codecs are the codecs that compress, in the order readers look for their files
+var Codecs = []Codec{Zstd, Gzip}
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// zstd encoders and decoders are expensive to create and safe for concurrent EncodeAll and
+// DecodeAll, so one of each is shared
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+	})
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil)
+	})
+)
+
+// Parse returns the codec named name: none, gzip, or zstd
+func Parse(name string) (Codec, error) {
+	switch codec := Codec(name); codec {
+	case None, Gzip, Zstd:
+		return codec, nil
+	}
+	return "", fmt.Errorf("unknown compression %q (available: none, gzip, zstd)", name)
+}
+
+// Ext returns the extension the codec adds to a file name, or "" for None
+func (c Codec) Ext() string {
+	switch c {
+	case Gzip:
+		return ".gz"
+	case Zstd:
+		return ".zst"
+	}
+	return ""
+}
+
+// ForName returns the codec of a file from the extension of its name, such as Zstd for
+// ch01.json.zst, or None
+func ForName(name string) Codec {
+	for _, codec := range Codecs {
+		if strings.HasSuffix(name, codec.Ext()) {
+			return codec
+		}
+	}
+	return None
+}
+
+// Compress compresses data with the codec. The output depends only on data, so compressed canons
+// of the same text are identical.
+func (c Codec) Compress(data []byte) ([]byte, error) {
+	switch c {
+	case None:
+		return data, nil
+	case Gzip:
+		var buf bytes.Buffer
+		zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write(data); err != nil {
+			return nil, fmt.Errorf("failed to compress with gzip: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress with gzip: %w", err)
+		}
+		return buf.Bytes(), nil
+	case Zstd:
+		enc, err := zstdEncoder()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+		}
+		return enc.EncodeAll(data, nil), nil
+	}
+	return nil, fmt.Errorf("unknown compression %q", string(c))
+}
+
+// Detect returns the codec data was compressed with, from its magic number, or None
+func Detect(data []byte) Codec {
+	switch {
+	case bytes.HasPrefix(data, zstdMagic):
+		return Zstd
+	case bytes.HasPrefix(data, gzipMagic):
+		return Gzip
+	}
+	return None
+}
+
+// Decompress returns data decompressed with the codec it was compressed with, or data itself if
+// it is not compressed
+func Decompress(data []byte) ([]byte, error) {
+	switch Detect(data) {
+	case Gzip:
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip: %w", err)
+		}
+		out, err := io.ReadAll(zr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip: %w", err)
+		}
+		return out, nil
+	case Zstd:
+		dec, err := zstdDecoder()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+		}
+		out, err := dec.DecodeAll(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress zstd: %w", err)
+		}
+		return out, nil
+	}
+	return data, nil
+}
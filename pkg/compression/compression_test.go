@@ -0,0 +1,54 @@
+package compression
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	data := []byte(`{"osis": "Gen", "chapter": 1, "verses": [{"v": 1, "plain": "In the beginning God created the heaven and the earth."}]}`)
+
+	for _, codec := range []Codec{None, Gzip, Zstd} {
+		compressed, err := codec.Compress(data)
+		if err != nil {
+			t.Fatalf("%s: %v", codec, err)
+		}
+		if got := Detect(compressed); got != codec {
+			t.Errorf("%s: detected %s", codec, got)
+		}
+
+		again, err := codec.Compress(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(compressed, again) {
+			t.Errorf("%s: expected the same output for the same data", codec)
+		}
+
+		out, err := Decompress(compressed)
+		if err != nil {
+			t.Fatalf("%s: %v", codec, err)
+		}
+		if !bytes.Equal(out, data) {
+			t.Errorf("%s: round trip changed the data: %s", codec, out)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for name, ext := range map[string]string{"none": "", "gzip": ".gz", "zstd": ".zst"} {
+		codec, err := Parse(name)
+		if err != nil || codec.Ext() != ext {
+			t.Errorf("Parse(%q) = %q, %v; want extension %q", name, codec, err, ext)
+		}
+	}
+	if _, err := Parse("brotli"); err == nil {
+		t.Error("expected an error for an unknown compression")
+	}
+}
+
+func TestDecompressCorrupt(t *testing.T) {
+	if _, err := Decompress(append([]byte{0x28, 0xb5, 0x2f, 0xfd}, "not zstd"...)); err == nil {
+		t.Error("expected an error for a corrupt zstd frame")
+	}
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+)
+
+// bookWriter buffers the chapters of one book at a time for formats that write a file per book
+// render is called with the buffered chapters whenever the book changes and on Finish
+type bookWriter struct {
+	dir      string
+	ext      string
+	work     string
+	chapters []*Chapter
+	render   func(work string, chapters []*Chapter) ([]byte, error)
+}
+
+func (w *bookWriter) Begin(work string) error {
+	w.work = work
+	if err := os.MkdirAll(w.dir, 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	return nil
+}
+
+func (w *bookWriter) WriteChapter(ch *Chapter) error {
+	if len(w.chapters) > 0 && w.chapters[0].OSIS != ch.OSIS {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+	w.chapters = append(w.chapters, ch)
+	return nil
+}
+
+func (w *bookWriter) Finish() error {
+	return w.flush()
+}
+
+// flush renders and writes the buffered book
+func (w *bookWriter) flush() error {
+	if len(w.chapters) == 0 {
+		return nil
+	}
+
+	data, err := w.render(w.work, w.chapters)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(w.dir, w.chapters[0].Abbr+w.ext)
+	w.chapters = nil
+	if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// footnotesByVerse groups a chapter's footnotes by the verse they are attached to
+func footnotesByVerse(ch *Chapter) map[int][]Footnote {
+	notes := make(map[int][]Footnote)
+	for _, fn := range ch.Footnotes {
+		notes[fn.At.V] = append(notes[fn.At.V], fn)
+	}
+	return notes
+}
@@ -0,0 +1,197 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register("docx", func(dir string) (Exporter, error) {
+		e := &docxExporter{}
+		e.bookWriter = bookWriter{dir: filepath.Join(dir, "docx"), ext: ".docx", render: func(work string, chapters []*Chapter) ([]byte, error) {
+			return renderDOCX(work, chapters, e.opts)
+		}}
+		return e, nil
+	})
+}
+
+// docxExporter is the bookWriter for docx, holding the options it renders with
+type docxExporter struct {
+	bookWriter
+	opts RenderOptions
+}
+
+func (e *docxExporter) SetRenderOptions(opts RenderOptions) {
+	e.opts = opts
+}
+
+// docxNamespaces are the namespace declarations of the WordprocessingML parts
+const docxNamespaces = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+
+// docxContentTypes, docxRels, and docxDocumentRels declare the parts of the package and how they relate
+const docxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+<Override PartName="/word/footnotes.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.footnotes+xml"/>
+<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>
+</Types>
+`
+
+const docxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>
+</Relationships>
+`
+
+const docxDocumentRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/footnotes" Target="footnotes.xml"/>
+</Relationships>
+`
+
+// docxStyles defines the styles the document uses, so they can be restyled in Word: the paragraph
+// styles Title (the book), Heading2 (each chapter), and FootnoteText, and the character styles
+// VerseNumber, AddedWord, DivineName, and FootnoteReference
+const docxStyles = xml.Header + `<w:styles ` + docxNamespaces + `>
+<w:docDefaults>
+<w:rPrDefault><w:rPr><w:rFonts w:ascii="Georgia" w:hAnsi="Georgia" w:cs="Georgia"/><w:sz w:val="22"/><w:lang w:val="en-GB"/></w:rPr></w:rPrDefault>
+<w:pPrDefault><w:pPr><w:spacing w:after="120" w:line="288" w:lineRule="auto"/></w:pPr></w:pPrDefault>
+</w:docDefaults>
+<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>
+<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:jc w:val="center"/><w:spacing w:after="240"/></w:pPr><w:rPr><w:sz w:val="48"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:sz w:val="26"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="FootnoteText"><w:name w:val="footnote text"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr><w:rPr><w:sz w:val="18"/></w:rPr></w:style>
+<w:style w:type="character" w:styleId="VerseNumber"><w:name w:val="Verse Number"/><w:qFormat/><w:rPr><w:b/><w:color w:val="808080"/><w:vertAlign w:val="superscript"/></w:rPr></w:style>
+<w:style w:type="character" w:styleId="AddedWord"><w:name w:val="Added Word"/><w:qFormat/><w:rPr><w:i/></w:rPr></w:style>
+<w:style w:type="character" w:styleId="DivineName"><w:name w:val="Divine Name"/><w:qFormat/><w:rPr><w:smallCaps/></w:rPr></w:style>
+<w:style w:type="character" w:styleId="FootnoteReference"><w:name w:val="footnote reference"/><w:rPr><w:vertAlign w:val="superscript"/></w:rPr></w:style>
+</w:styles>
+`
+
+// renderDOCX renders one book as a Word document, written to docx/{ABBR}.docx. The book is titled
+// with its OSIS code and each chapter headed "Chapter N". Verse numbers, added words, and the divine
+// name are runs in the VerseNumber, AddedWord, and DivineName character styles, and footnotes are
+// Word footnotes. A drop cap takes the place of the first verse number, as in print editions.
+func renderDOCX(work string, chapters []*Chapter, opts RenderOptions) ([]byte, error) {
+	var body, notes strings.Builder
+	footnoteID := 0
+
+	fmt.Fprintf(&body, `<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr>%s</w:p>`+"\n", docxRun("", chapters[0].OSIS))
+	for _, ch := range chapters {
+		fmt.Fprintf(&body, `<w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr>%s</w:p>`+"\n", docxRun("", fmt.Sprintf("Chapter %d", ch.Chapter)))
+
+		byVerse := footnotesByVerse(ch)
+		for i, verse := range ch.Verses {
+			var spans []docxSpan
+			for _, token := range verse.Tokens {
+				switch {
+				case token.Add != "":
+					spans = append(spans, docxSpan{style: "AddedWord", text: token.Add})
+				case token.ND != "":
+					spans = append(spans, docxSpan{style: "DivineName", text: token.ND})
+				case token.Text != "":
+					spans = append(spans, docxSpan{text: token.Text})
+				}
+			}
+			if len(spans) > 0 {
+				spans[0].text = strings.TrimLeft(spans[0].text, " ")
+				spans[len(spans)-1].text = strings.TrimRight(spans[len(spans)-1].text, " ")
+			}
+
+			numbered := true
+			switch {
+			case i == 0:
+				if opts.DropCap && len(spans) > 0 && spans[0].style == "" {
+					if _, letter, rest := splitDropCap(spans[0].text); letter != "" {
+						body.WriteString(`<w:p><w:pPr><w:framePr w:dropCap="drop" w:lines="3" w:wrap="around" w:vAnchor="text" w:hAnchor="text"/>` +
+							`<w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr>` +
+							`<w:r><w:rPr><w:position w:val="-6"/><w:sz w:val="76"/></w:rPr><w:t>` + escapeXML(letter) + "</w:t></w:r></w:p>\n")
+						spans[0].text = rest
+						numbered = false
+					}
+				}
+				body.WriteString("<w:p>")
+			case startsParagraph(verse):
+				body.WriteString("</w:p>\n<w:p>")
+			default:
+				body.WriteString(docxRun("", " "))
+			}
+
+			if number, plain := plainVerseNumber(opts.VerseNumbers, verse.V); plain && numbered {
+				body.WriteString(docxRun("", number))
+			} else if numbered {
+				body.WriteString(docxRun("VerseNumber", fmt.Sprint(verse.V)))
+			}
+			for _, span := range spans {
+				body.WriteString(docxRun(span.style, span.text))
+			}
+
+			for _, fn := range byVerse[verse.V] {
+				footnoteID++
+				fmt.Fprintf(&body, `<w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteReference w:id="%d"/></w:r>`, footnoteID)
+				fmt.Fprintf(&notes, `<w:footnote w:id="%d"><w:p><w:pPr><w:pStyle w:val="FootnoteText"/></w:pPr>`+
+					`<w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteRef/></w:r>%s</w:p></w:footnote>`+"\n",
+					footnoteID, docxRun("", " "+fn.Text))
+			}
+		}
+		if len(ch.Verses) > 0 {
+			body.WriteString("</w:p>\n")
+		}
+	}
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRels},
+		{"docProps/core.xml", xml.Header + `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+			"<dc:title>" + escapeXML(chapters[0].OSIS) + "</dc:title><dc:publisher>" + escapeXML(work) + "</dc:publisher></cp:coreProperties>\n"},
+		{"word/_rels/document.xml.rels", docxDocumentRels},
+		{"word/styles.xml", docxStyles},
+		{"word/footnotes.xml", xml.Header + "<w:footnotes " + docxNamespaces + ">\n" +
+			`<w:footnote w:type="separator" w:id="-1"><w:p><w:r><w:separator/></w:r></w:p></w:footnote>` + "\n" +
+			`<w:footnote w:type="continuationSeparator" w:id="0"><w:p><w:r><w:continuationSeparator/></w:r></w:p></w:footnote>` + "\n" +
+			notes.String() + "</w:footnotes>\n"},
+		{"word/document.xml", xml.Header + "<w:document " + docxNamespaces + ">\n<w:body>\n" + body.String() + "</w:body>\n</w:document>\n"},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, part := range parts {
+		// The zero modification time keeps the archive identical from run to run
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: part.name, Method: zip.Deflate})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write docx: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// docxSpan is a run of verse text in one character style, or none
+type docxSpan struct {
+	style string
+	text  string
+}
+
+// docxRun renders text as a run in the given character style; empty text renders nothing
+func docxRun(style, text string) string {
+	if text == "" {
+		return ""
+	}
+	var rPr string
+	if style != "" {
+		rPr = `<w:rPr><w:rStyle w:val="` + style + `"/></w:rPr>`
+	}
+	return `<w:r>` + rPr + `<w:t xml:space="preserve">` + escapeXML(text) + `</w:t></w:r>`
+}
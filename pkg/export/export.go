@@ -0,0 +1,121 @@
+// Package export writes canonical chapters to output formats.
+//
+// Exporters are registered by name and created with New. Each exporter owns a subdirectory of the
+// output root it is given, so several formats can be written side by side in a single ingest pass.
+// Third parties can add formats by calling Register from an init function.
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Chapter is the canonical chapter representation passed to exporters
+type Chapter = model.Chapter
+
+// Verse is a single verse of a Chapter
+type Verse = model.Verse
+
+// Token is a single token of a Verse
+type Token = model.Token
+
+// Footnote is a footnote attached to a verse of a Chapter
+type Footnote = model.Footnote
+
+// Exporter writes chapters to an output format.
+// Begin is called once before any chapter, WriteChapter once per chapter in canonical order
+// (books in order, chapters ascending), and Finish once after the last chapter.
+type Exporter interface {
+	Begin(work string) error
+	WriteChapter(ch *Chapter) error
+	Finish() error
+}
+
+// Factory creates an exporter that writes beneath the output root dir
+type Factory func(dir string) (Exporter, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes an exporter available under name
+// It panics if name is empty, factory is nil, or name is already registered
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" {
+		panic("export: Register called with empty name")
+	}
+	if factory == nil {
+		panic("export: Register factory is nil for " + name)
+	}
+	if _, dup := registry[name]; dup {
+		panic("export: Register called twice for " + name)
+	}
+	registry[name] = factory
+}
+
+// New creates the exporter registered under name, writing beneath dir
+func New(name, dir string) (Exporter, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown export format %q (available: %s)", name, strings.Join(Formats(), ", "))
+	}
+	return factory(dir)
+}
+
+// Formats returns the names of all registered exporters in sorted order
+func Formats() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Multi fans each call out to several exporters, stopping at the first error
+type Multi []Exporter
+
+// Begin calls Begin on every exporter
+func (m Multi) Begin(work string) error {
+	for _, e := range m {
+		if err := e.Begin(work); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteChapter calls WriteChapter on every exporter
+func (m Multi) WriteChapter(ch *Chapter) error {
+	for _, e := range m {
+		if err := e.WriteChapter(ch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Finish calls Finish on every exporter, returning the first error
+func (m Multi) Finish() error {
+	var firstErr error
+	for _, e := range m {
+		if err := e.Finish(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
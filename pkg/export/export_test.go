@@ -0,0 +1,634 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func testChapters() []*Chapter {
+	gen1 := &Chapter{
+		Schema:  1,
+		Work:    "KJV",
+		OSIS:    "Gen",
+		Abbr:    "GEN",
+		Chapter: 1,
+		Verses: []Verse{
+			{V: 1, Tokens: []Token{{Text: "In the beginning God created the heaven and the earth. "}}},
+			{V: 2, Tokens: []Token{{Text: "And the earth "}, {Add: "was"}, {Text: " without form. "}}},
+		},
+	}
+	gen2 := &Chapter{
+		Schema:  1,
+		Work:    "KJV",
+		OSIS:    "Gen",
+		Abbr:    "GEN",
+		Chapter: 2,
+		Verses: []Verse{
+			{V: 1, Tokens: []Token{{Text: "Thus the heavens were finished. "}}},
+			{V: 2, Tokens: []Token{{Text: "¶ And the "}, {ND: "LORD"}, {Text: " God & man. "}}},
+		},
+		Footnotes: []Footnote{{ID: "FN1", Mark: "*", Text: "finished: Heb. made"}},
+	}
+	gen2.Footnotes[0].At.V = 1
+	exo1 := &Chapter{
+		Schema:  1,
+		Work:    "KJV",
+		OSIS:    "Exod",
+		Abbr:    "EXO",
+		Chapter: 1,
+		Verses:  []Verse{{V: 1, Tokens: []Token{{Text: "Now these are the names. "}}}},
+	}
+	return []*Chapter{gen1, gen2, exo1}
+}
+
+func runExporter(t *testing.T, format, dir string) {
+	t.Helper()
+
+	exporter, err := New(format, dir)
+	if err != nil {
+		t.Fatalf("failed to create %s exporter: %v", format, err)
+	}
+	if err := exporter.Begin("KJV"); err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	for _, ch := range testChapters() {
+		if err := exporter.WriteChapter(ch); err != nil {
+			t.Fatalf("WriteChapter failed: %v", err)
+		}
+	}
+	if err := exporter.Finish(); err != nil {
+		t.Fatalf("Finish failed: %v", err)
+	}
+}
+
+func TestJSONExporter(t *testing.T) {
+	dir := t.TempDir()
+	runExporter(t, "json", dir)
+
+	data, err := os.ReadFile(ChapterPath(dir, "Gen", 2)) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+
+	var ch Chapter
+	if err := json.Unmarshal(data, &ch); err != nil {
+		t.Fatalf("failed to unmarshal JSON: %v", err)
+	}
+	if ch.Chapter != 2 || len(ch.Verses) != 2 || len(ch.Footnotes) != 1 {
+		t.Errorf("unexpected chapter content: %+v", ch)
+	}
+}
+
+func TestJSONExporterCompressed(t *testing.T) {
+	dir := t.TempDir()
+	runExporter(t, "json", dir)
+
+	for _, codec := range compression.Codecs {
+		ex := NewJSON(dir, JSONOptions{Compression: codec})
+		if err := ex.WriteChapter(testChapters()[1]); err != nil {
+			t.Fatalf("%s: WriteChapter failed: %v", codec, err)
+		}
+
+		data, err := os.ReadFile(ChapterPath(dir, "Gen", 2) + codec.Ext()) // nolint: gosec
+		if err != nil {
+			t.Fatalf("%s: failed to read output: %v", codec, err)
+		}
+		if compression.Detect(data) != codec {
+			t.Errorf("%s: expected the chapter file to be compressed", codec)
+		}
+		if data, err = compression.Decompress(data); err != nil {
+			t.Fatal(err)
+		}
+		var ch Chapter
+		if err := json.Unmarshal(data, &ch); err != nil || ch.Chapter != 2 {
+			t.Errorf("%s: unexpected chapter content: %+v, %v", codec, ch, err)
+		}
+
+		// Only the file of the latest compression is kept
+		matches, _ := filepath.Glob(ChapterPath(dir, "Gen", 2) + "*")
+		if len(matches) != 1 {
+			t.Errorf("%s: expected one file for the chapter, got %v", codec, matches)
+		}
+	}
+}
+
+func TestJSONExporterCAS(t *testing.T) {
+	dir := t.TempDir()
+	run := func(opts JSONOptions) *JSONExporter {
+		ex := NewJSON(dir, opts)
+		if err := ex.Begin("KJV"); err != nil {
+			t.Fatalf("Begin failed: %v", err)
+		}
+		for _, ch := range testChapters() {
+			if err := ex.WriteChapter(ch); err != nil {
+				t.Fatalf("WriteChapter failed: %v", err)
+			}
+		}
+		if err := ex.Finish(); err != nil {
+			t.Fatalf("Finish failed: %v", err)
+		}
+		return ex
+	}
+	objectFiles := func() []string {
+		matches, _ := filepath.Glob(filepath.Join(dir, "objects", "*", "*"))
+		return matches
+	}
+
+	run(JSONOptions{})
+	ex := run(JSONOptions{Layout: LayoutCAS})
+
+	data, err := os.ReadFile(filepath.Join(dir, "index", ObjectIndexName)) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", ObjectIndexName, err)
+	}
+	objects, err := model.ParseObjectIndex(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, ok := objects.Object("Gen", 2)
+	path := filepath.Join(dir, filepath.FromSlash(model.ObjectPath(name)))
+	if !ok || ex.ChapterFile("Gen", 2) != path {
+		t.Fatalf("expected Gen 2 to be written to its object, got %q and %q", name, ex.ChapterFile("Gen", 2))
+	}
+	if data, err = os.ReadFile(path); err != nil || model.ObjectName(data, "") != name { // nolint: gosec
+		t.Errorf("expected the object to be named by its contents, got %v", err)
+	}
+	if _, err := os.Stat(ChapterPath(dir, "Gen", 2)); !os.IsNotExist(err) {
+		t.Errorf("expected the chapter file of the tree layout to be removed, got %v", err)
+	}
+
+	// Unchanged chapters are stored once, however often they are written
+	written := objectFiles()
+	run(JSONOptions{Layout: LayoutCAS})
+	if again := objectFiles(); len(again) != len(written) || len(written) != len(testChapters()) {
+		t.Errorf("expected one object per chapter, got %d and then %d", len(written), len(again))
+	}
+
+	// Writing the tree layout again takes the chapters out of the index, which goes once empty
+	ex = run(JSONOptions{})
+	if ex.ChapterFile("Gen", 2) != ChapterPath(dir, "Gen", 2) {
+		t.Errorf("expected Gen 2 in the tree layout, got %q", ex.ChapterFile("Gen", 2))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "index", ObjectIndexName)); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got %v", ObjectIndexName, err)
+	}
+}
+
+func TestBookExporters(t *testing.T) {
+	tests := []struct {
+		format string
+		file   string
+		want   []string
+	}{
+		{
+			format: "usfm",
+			file:   filepath.Join("usfm", "GEN.usfm"),
+			want: []string{
+				"\\id GEN KJV\n\\c 1\n\\p\n\\v 1 In the beginning",
+				"\\v 2 And the earth \\add was\\add* without form.\n",
+				"\\v 1 Thus the heavens were finished.\\f * \\fr 2:1 \\ft finished: Heb. made\\f*\n\\p\n",
+				"\\v 2 ¶ And the \\nd LORD\\nd* God & man.\n",
+			},
+		},
+		{
+			format: "osis",
+			file:   filepath.Join("osis", "GEN.xml"),
+			want: []string{
+				`<div type="book" osisID="Gen">`,
+				`<verse osisID="Gen.1.2">And the earth <transChange type="added">was</transChange> without form.</verse>`,
+				`<note type="translation" osisRef="Gen.2.1" n="*">finished: Heb. made</note>`,
+				`<divineName>LORD</divineName> God &amp; man.</verse>`,
+			},
+		},
+		{
+			format: "markdown-book",
+			file:   filepath.Join("markdown", "GEN.md"),
+			want: []string{
+				"---\ntitle: \"Gen\"\nwork: \"KJV\"\nosis: \"Gen\"\nchapters: 2\n---\n",
+				"## Chapter 2\n\n<sup>1</sup>Thus the heavens were finished.[^2-1]\n\n<sup>2</sup>",
+				"[^2-1]: finished: Heb. made\n",
+			},
+		},
+		{
+			format: "latex",
+			file:   filepath.Join("latex", "GEN.tex"),
+			want: []string{
+				"\\kjvbook{Gen}\n\n\\kjvchapter{1}\n\\versenumber{1}In the beginning",
+				"\\versenumber{2}And the earth \\add{was} without form.\n",
+				"finished.\\footnote{finished: Heb. made}\n\\par\n\\versenumber{2}¶ And the \\divinename{LORD} God \\& man.\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			dir := t.TempDir()
+			runExporter(t, tt.format, dir)
+
+			data, err := os.ReadFile(filepath.Join(dir, tt.file)) // nolint: gosec
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, data)
+				}
+			}
+			if strings.Contains(string(data), "Exod") || strings.Contains(string(data), "EXO") {
+				t.Errorf("expected Exodus to be written to its own file")
+			}
+
+			if _, err := os.Stat(filepath.Join(dir, filepath.Dir(tt.file), "EXO"+filepath.Ext(tt.file))); err != nil {
+				t.Errorf("expected a second book file: %v", err)
+			}
+		})
+	}
+}
+
+func TestMarkdownExporter(t *testing.T) {
+	dir := t.TempDir()
+	runExporter(t, "markdown", dir)
+
+	data, err := os.ReadFile(filepath.Join(dir, "markdown", "Gen", "ch01.md")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+
+	want := "---\ntitle: \"Gen 1\"\nwork: \"KJV\"\nosis: \"Gen\"\nchapter: 1\n---\n\n" +
+		"<sup>1</sup>In the beginning God created the heaven and the earth. " +
+		"<sup>2</sup>And the earth *was* without form.\n"
+	if string(data) != want {
+		t.Errorf("unexpected markdown:\n%s\nwant:\n%s", data, want)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "markdown", "Gen", "ch02.md")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(data), "finished.[^1]") || !strings.Contains(string(data), "[^1]: finished: Heb. made") {
+		t.Errorf("expected a Markdown footnote, got:\n%s", data)
+	}
+}
+
+func TestHTMLExporter(t *testing.T) {
+	dir := t.TempDir()
+	runExporter(t, "html", dir)
+
+	data, err := os.ReadFile(filepath.Join(dir, "html", "Gen", "ch01.html")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	want := "<article class=\"kjv-chapter\" lang=\"en\" data-work=\"KJV\" data-osis=\"Gen\" data-chapter=\"1\">\n<p>\n" +
+		"<span class=\"verse\" id=\"Gen.1.1\" data-verse=\"1\"><sup class=\"verse-num\">1</sup>In the beginning God created the heaven and the earth.</span>\n" +
+		"<span class=\"verse\" id=\"Gen.1.2\" data-verse=\"2\"><sup class=\"verse-num\">2</sup>And the earth <span class=\"add\">was</span> without form.</span>\n" +
+		"</p>\n</article>\n"
+	if string(data) != want {
+		t.Errorf("unexpected html:\n%s\nwant:\n%s", data, want)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "html", "Gen", "ch02.html")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	for _, want := range []string{
+		`finished.<button type="button" class="footnote-ref" popovertarget="Gen.2.fn1">*</button><span class="footnote" id="Gen.2.fn1" popover><span class="footnote-term">finished</span>: Heb. <span class="footnote-gloss" data-language="Hebrew">made</span></span></span>`,
+		"</p>\n<p>\n<span class=\"verse\" id=\"Gen.2.2\"",
+		`<span class="divine-name">LORD</span> God &amp; man.`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "<style>") {
+		t.Error("expected the stylesheet to be left out by default")
+	}
+
+	css, err := os.ReadFile(filepath.Join(dir, "html", "kjv.css")) // nolint: gosec
+	if err != nil || string(css) != DefaultStylesheet {
+		t.Errorf("expected the default stylesheet in html/kjv.css: %v", err)
+	}
+
+	opts := RenderOptions{VerseNumbers: VerseNumbersBracketed, ChapterHeaders: true, DropCap: true, Stylesheet: true}
+	got := renderHTMLChapter("KJV", testChapters()[0], opts)
+	for _, want := range []string{
+		"<style>\n" + DefaultStylesheet + "</style>\n<h2 class=\"chapter-heading\">Chapter 1</h2>\n",
+		`<span class="verse-num">[1] </span><span class="drop-cap">I</span>n the beginning`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestLaTeXExporter(t *testing.T) {
+	dir := t.TempDir()
+	runExporter(t, "latex", dir)
+
+	data, err := os.ReadFile(filepath.Join(dir, "latex", "main.tex")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	for _, want := range []string{
+		"\\documentclass[10pt,twocolumn,openany]{memoir}",
+		"\\newcommand{\\versenumber}",
+		"\\title{KJV}",
+		"\\include{GEN}\n\\include{EXO}\n\n\\end{document}\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected main.tex to contain %q, got:\n%s", want, data)
+		}
+	}
+
+	opts := RenderOptions{VerseNumbers: VerseNumbersUnicode, DropCap: true}
+	got, err := renderLaTeXBook(testChapters()[:1], opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "\\lettrine{I}{}n the beginning God created the heaven and the earth.\n²And the earth"
+	if !strings.Contains(string(got), want) {
+		t.Errorf("expected a drop cap in place of the first verse number, got:\n%s", got)
+	}
+	if got := escapeLaTeX(`50% of $5 & {x}_1`); got != `50\% of \$5 \& \{x\}\_1` {
+		t.Errorf("escapeLaTeX = %q", got)
+	}
+}
+
+func TestDOCXExporter(t *testing.T) {
+	dir := t.TempDir()
+	runExporter(t, "docx", dir)
+
+	r, err := zip.OpenReader(filepath.Join(dir, "docx", "GEN.docx"))
+	if err != nil {
+		t.Fatalf("failed to open docx: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	parts := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/_rels/document.xml.rels", "word/styles.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("expected a %s part", name)
+		}
+	}
+	for name, wants := range map[string][]string{
+		"word/document.xml": {
+			`<w:r><w:rPr><w:rStyle w:val="VerseNumber"/></w:rPr><w:t xml:space="preserve">1</w:t></w:r><w:r><w:t xml:space="preserve">In the beginning`,
+			`<w:r><w:rPr><w:rStyle w:val="AddedWord"/></w:rPr><w:t xml:space="preserve">was</w:t></w:r>`,
+			`finished.</w:t></w:r><w:r><w:rPr><w:rStyle w:val="FootnoteReference"/></w:rPr><w:footnoteReference w:id="1"/></w:r></w:p>` + "\n<w:p>",
+			`<w:rStyle w:val="DivineName"/></w:rPr><w:t xml:space="preserve">LORD</w:t></w:r><w:r><w:t xml:space="preserve"> God &amp; man.</w:t>`,
+		},
+		"word/footnotes.xml": {`<w:footnote w:id="1">`, "finished: Heb. made"},
+	} {
+		for _, want := range wants {
+			if !strings.Contains(parts[name], want) {
+				t.Errorf("expected %s to contain %q, got:\n%s", name, want, parts[name])
+			}
+		}
+	}
+	for name, part := range parts {
+		if strings.HasSuffix(name, ".xml") || strings.HasSuffix(name, ".rels") {
+			if err := xml.Unmarshal([]byte(part), new(struct{})); err != nil {
+				t.Errorf("%s is not well-formed XML: %v", name, err)
+			}
+		}
+	}
+
+	// Rendering the same book twice gives the same archive
+	a, _ := renderDOCX("KJV", testChapters()[:2], RenderOptions{DropCap: true})
+	b, _ := renderDOCX("KJV", testChapters()[:2], RenderOptions{DropCap: true})
+	if !bytes.Equal(a, b) {
+		t.Error("expected docx output to be reproducible")
+	}
+}
+
+func TestODTExporter(t *testing.T) {
+	dir := t.TempDir()
+	runExporter(t, "odt", dir)
+
+	r, err := zip.OpenReader(filepath.Join(dir, "odt", "GEN.odt"))
+	if err != nil {
+		t.Fatalf("failed to open odt: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	if len(r.File) == 0 || r.File[0].Name != "mimetype" || r.File[0].Method != zip.Store || r.File[0].Flags&0x8 != 0 {
+		t.Fatal("expected an uncompressed mimetype entry first")
+	}
+	parts := make(map[string]string)
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[f.Name] = string(data)
+	}
+
+	if parts["mimetype"] != "application/vnd.oasis.opendocument.text" {
+		t.Errorf("unexpected mimetype %q", parts["mimetype"])
+	}
+	for name, wants := range map[string][]string{
+		"content.xml": {
+			`<text:p text:style-name="Text_20_body"><text:span text:style-name="Verse_20_Number">1</text:span>In the beginning`,
+			`<text:span text:style-name="Added_20_Word">was</text:span>`,
+			`finished.<text:note text:id="ftn1" text:note-class="footnote"><text:note-citation>1</text:note-citation>` +
+				`<text:note-body><text:p text:style-name="Footnote">finished: Heb. made</text:p></text:note-body></text:note></text:p>` +
+				"\n<text:p text:style-name=\"Text_20_body\">",
+			`<text:span text:style-name="Divine_20_Name">LORD</text:span> God &amp; man.`,
+		},
+		"styles.xml":            {`style:name="Verse_20_Number"`, `style:name="Footnote_20_anchor"`},
+		"META-INF/manifest.xml": {`manifest:full-path="content.xml"`},
+		"meta.xml":              {"<dc:title>Gen</dc:title>"},
+	} {
+		for _, want := range wants {
+			if !strings.Contains(parts[name], want) {
+				t.Errorf("expected %s to contain %q, got:\n%s", name, want, parts[name])
+			}
+		}
+	}
+	for name, part := range parts {
+		if strings.HasSuffix(name, ".xml") {
+			if err := xml.Unmarshal([]byte(part), new(struct{})); err != nil {
+				t.Errorf("%s is not well-formed XML: %v", name, err)
+			}
+		}
+	}
+
+	// Rendering the same book twice gives the same archive
+	a, _ := renderODT("KJV", testChapters()[:2], RenderOptions{DropCap: true})
+	b, _ := renderODT("KJV", testChapters()[:2], RenderOptions{DropCap: true})
+	if !bytes.Equal(a, b) {
+		t.Error("expected odt output to be reproducible")
+	}
+	zr, err := zip.NewReader(bytes.NewReader(a), int64(len(a)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range zr.File {
+		if f.Name != "content.xml" {
+			continue
+		}
+		rc, _ := f.Open()
+		content, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		if !strings.Contains(string(content), `<text:p text:style-name="Drop_Cap">In the beginning`) {
+			t.Errorf("expected a drop cap in place of the first verse number, got:\n%s", content)
+		}
+	}
+	if got := odtSpan("", "a  b"); got != "a <text:s/>b" {
+		t.Errorf("odtSpan = %q", got)
+	}
+}
+
+func TestRenderOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts RenderOptions
+		want string
+	}{
+		{
+			name: "unicode",
+			opts: RenderOptions{VerseNumbers: VerseNumbersUnicode},
+			want: "¹In the beginning God created the heaven and the earth. ²And the earth *was* without form.\n",
+		},
+		{
+			name: "bracketed",
+			opts: RenderOptions{VerseNumbers: VerseNumbersBracketed},
+			want: "\\[1\\] In the beginning God created the heaven and the earth. \\[2\\] And the earth *was* without form.\n",
+		},
+		{
+			name: "omitted with header and drop cap",
+			opts: RenderOptions{VerseNumbers: VerseNumbersOmitted, ChapterHeaders: true, DropCap: true},
+			want: "## Chapter 1\n\n<span class=\"drop-cap\">I</span>n the beginning God created the heaven and the earth. And the earth *was* without form.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			markdown, err := New("markdown", dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			usfm, err := New("usfm", dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Formats that do not render for reading are left alone
+			exporter := Multi{usfm, markdown}
+			if err := Configure(exporter, tt.opts); err != nil {
+				t.Fatalf("Configure failed: %v", err)
+			}
+			if err := exporter.Begin("KJV"); err != nil {
+				t.Fatal(err)
+			}
+			if err := exporter.WriteChapter(testChapters()[0]); err != nil {
+				t.Fatal(err)
+			}
+			if err := exporter.Finish(); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, "markdown", "Gen", "ch01.md")) // nolint: gosec
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			if _, body, _ := strings.Cut(string(data), "---\n\n"); body != tt.want {
+				t.Errorf("unexpected markdown:\n%s\nwant:\n%s", body, tt.want)
+			}
+		})
+	}
+
+	if got := superscript(1207); got != "¹²⁰⁷" {
+		t.Errorf("superscript(1207) = %q", got)
+	}
+	if lead, letter, rest := splitDropCap("¶ And God"); lead != "¶ " || letter != "A" || rest != "nd God" {
+		t.Errorf("splitDropCap kept %q, %q, %q", lead, letter, rest)
+	}
+	if err := Configure(Multi{}, RenderOptions{VerseNumbers: "roman"}); err == nil {
+		t.Error("expected an unknown verse number style to fail")
+	}
+}
+
+func TestSpeechExporters(t *testing.T) {
+	dir := t.TempDir()
+	runExporter(t, "speech", dir)
+	runExporter(t, "ssml", dir)
+
+	data, err := os.ReadFile(filepath.Join(dir, "speech", "Gen", "ch02.txt")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if want := "Thus the heavens were finished.\n\nAnd the Lord God & man.\n"; string(data) != want {
+		t.Errorf("unexpected speech:\n%s\nwant:\n%s", data, want)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "ssml", "Gen", "ch02.ssml")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	want := xml.Header + `<speak version="1.1" xmlns="http://www.w3.org/2001/10/synthesis" xml:lang="en">` + "\n" +
+		`<p><mark name="Gen.2.1"/>Thus the heavens were finished.</p>` + "\n" +
+		`<p><mark name="Gen.2.2"/>And the Lord God &amp; man.</p>` + "\n</speak>\n"
+	if string(data) != want {
+		t.Errorf("unexpected ssml:\n%s\nwant:\n%s", data, want)
+	}
+	if err := xml.Unmarshal(data, new(struct{})); err != nil {
+		t.Errorf("expected well-formed SSML: %v", err)
+	}
+
+	verse := Verse{V: 23, Tokens: []Token{
+		{Text: "the same hath not the Father: [but] he that "},
+		{Add: "is"},
+		{Text: " of the "},
+		{ND: "LORD’s"},
+		{Text: " house, viz. the tabernacle, &c., "},
+	}}
+	if got, want := spokenText(verse), "the same hath not the Father: but he that is of the Lord’s house, namely the tabernacle, et cetera,"; got != want {
+		t.Errorf("spokenText = %q, want %q", got, want)
+	}
+	if got := renderSpeech(testChapters()[0], RenderOptions{ChapterHeaders: true}); !strings.HasPrefix(got, "Chapter 1.\n\nIn the beginning") {
+		t.Errorf("expected a spoken chapter heading, got %q", got)
+	}
+}
+
+func TestNewUnknownFormat(t *testing.T) {
+	if _, err := New("nope", t.TempDir()); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+	for _, name := range []string{"json", "osis", "usfm", "ssml", "speech", "odt"} {
+		found := false
+		for _, format := range Formats() {
+			found = found || format == name
+		}
+		if !found {
+			t.Errorf("expected %s to be registered, got %v", name, Formats())
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package export
+
+import (
+	_ "embed"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// DefaultStylesheet is the stylesheet written to html/kjv.css, and embedded in each chapter when
+// RenderOptions.Stylesheet is set. Its rules are scoped to .kjv-chapter.
+//
+//go:embed html.css
+var DefaultStylesheet string
+
+func init() {
+	Register("html", func(dir string) (Exporter, error) {
+		return &htmlExporter{dir: filepath.Join(dir, "html")}, nil
+	})
+}
+
+// htmlExporter writes one HTML fragment per chapter to html/{OSIS}/chNN.html, for embedding in a
+// page. Each fragment is an <article class="kjv-chapter"> using these classes:
+//
+//	verse            <span> around each verse, with an OSIS id such as Gen.1.1 or AddEsth.10.4
+//	verse-num        the verse number, a <sup> in the markup style
+//	add              words added by the translators
+//	divine-name      the divine name (LORD)
+//	chapter-heading  the <h2> written when ChapterHeaders is set
+//	drop-cap         the first letter of the chapter when DropCap is set
+//	footnote-ref     the <button> that opens a footnote
+//	footnote         the footnote text, a popover
+//	footnote-term    in a note on the original language, the words of the text it is on
+//	footnote-gloss   in a note on the original language, the literal rendering, with the
+//	                 language in data-language
+//
+// Paragraphs are <p> elements.
+type htmlExporter struct {
+	dir  string
+	work string
+	opts RenderOptions
+}
+
+func (e *htmlExporter) SetRenderOptions(opts RenderOptions) {
+	e.opts = opts
+}
+
+// Begin writes the default stylesheet to html/kjv.css, for pages that link it rather than embed it
+func (e *htmlExporter) Begin(work string) error {
+	e.work = work
+	if err := os.MkdirAll(e.dir, 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := atomicfile.WriteFile(filepath.Join(e.dir, "kjv.css"), []byte(DefaultStylesheet), 0600); err != nil {
+		return fmt.Errorf("failed to write stylesheet: %w", err)
+	}
+	return nil
+}
+
+func (e *htmlExporter) WriteChapter(ch *Chapter) error {
+	path := filepath.Join(e.dir, ch.OSIS, fmt.Sprintf("ch%02d.html", ch.Chapter))
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if err := atomicfile.WriteFile(path, []byte(renderHTMLChapter(e.work, ch, e.opts)), 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+func (e *htmlExporter) Finish() error {
+	return nil
+}
+
+// renderHTMLChapter renders a chapter as an <article> fragment. Element ids are prefixed with the
+// chapter's OSIS id, so several chapters can share a page.
+func renderHTMLChapter(work string, ch *Chapter, opts RenderOptions) string {
+	var b strings.Builder
+
+	chapterID := fmt.Sprintf("%s.%d", osisID(ch.OSIS), ch.Chapter)
+	fmt.Fprintf(&b, "<article class=\"kjv-chapter\" lang=\"en\" data-work=\"%s\" data-osis=\"%s\" data-chapter=\"%d\">\n",
+		html.EscapeString(work), html.EscapeString(ch.OSIS), ch.Chapter)
+	if opts.Stylesheet {
+		b.WriteString("<style>\n" + DefaultStylesheet + "</style>\n")
+	}
+	if opts.ChapterHeaders {
+		fmt.Fprintf(&b, "<h2 class=\"chapter-heading\">Chapter %d</h2>\n", ch.Chapter)
+	}
+
+	notes := footnotesByVerse(ch)
+	footnotes := 0
+	for i, verse := range ch.Verses {
+		switch {
+		case i == 0:
+			b.WriteString("<p>\n")
+		case startsParagraph(verse):
+			b.WriteString("</p>\n<p>\n")
+		}
+
+		verseID := fmt.Sprintf("%s.%d", chapterID, verse.V)
+		fmt.Fprintf(&b, "<span class=\"verse\" id=\"%s\" data-verse=\"%d\">", html.EscapeString(verseID), verse.V)
+		if number, plain := plainVerseNumber(opts.VerseNumbers, verse.V); !plain {
+			fmt.Fprintf(&b, "<sup class=\"verse-num\">%d</sup>", verse.V)
+		} else if number != "" {
+			fmt.Fprintf(&b, "<span class=\"verse-num\">%s</span>", number)
+		}
+
+		var text strings.Builder
+		for _, token := range verse.Tokens {
+			switch {
+			case token.Add != "":
+				fmt.Fprintf(&text, "<span class=\"add\">%s</span>", html.EscapeString(token.Add))
+			case token.ND != "":
+				fmt.Fprintf(&text, "<span class=\"divine-name\">%s</span>", html.EscapeString(token.ND))
+			default:
+				text.WriteString(html.EscapeString(token.Text))
+			}
+		}
+		rendered := strings.TrimSpace(text.String())
+		if i == 0 && opts.DropCap {
+			if lead, letter, rest := splitDropCap(rendered); letter != "" {
+				rendered = lead + `<span class="drop-cap">` + letter + "</span>" + rest
+			}
+		}
+		b.WriteString(rendered)
+
+		for _, fn := range notes[verse.V] {
+			footnotes++
+			mark := fn.Mark
+			if mark == "" {
+				mark = "*"
+			}
+			noteID := html.EscapeString(fmt.Sprintf("%s.fn%d", chapterID, footnotes))
+			fmt.Fprintf(&b, "<button type=\"button\" class=\"footnote-ref\" popovertarget=\"%s\">%s</button>", noteID, html.EscapeString(mark))
+			fmt.Fprintf(&b, "<span class=\"footnote\" id=\"%s\" popover>%s</span>", noteID, footnoteHTML(fn.Text))
+		}
+		b.WriteString("</span>\n")
+	}
+	if len(ch.Verses) > 0 {
+		b.WriteString("</p>\n")
+	}
+
+	b.WriteString("</article>\n")
+	return b.String()
+}
+
+// footnoteHTML escapes a footnote's text, marking the term and gloss of a note on the original
+// language, such as "equity: Heb. equities", so they can be styled apart
+func footnoteHTML(text string) string {
+	term, ok := model.ParseFootnoteTerm(text)
+	if !ok {
+		return html.EscapeString(text)
+	}
+	between := text[len(term.Term) : len(text)-len(term.Gloss)]
+	return fmt.Sprintf(`<span class="footnote-term">%s</span>%s<span class="footnote-gloss" data-language="%s">%s</span>`,
+		html.EscapeString(term.Term), html.EscapeString(between), term.Language, html.EscapeString(term.Gloss))
+}
@@ -0,0 +1,181 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func init() {
+	Register("json", func(dir string) (Exporter, error) {
+		return NewJSON(dir, JSONOptions{}), nil
+	})
+}
+
+// Layout selects where the json exporter writes chapters
+type Layout string
+
+const (
+	LayoutTree Layout = "tree" // books/{OSIS}/chNN.json
+	LayoutCAS  Layout = "cas"  // objects/{hh}/{sha256}.json, referenced from index/objects.json
+)
+
+// ObjectIndexName is the name of the reference index of the content-addressed layout in index/
+const ObjectIndexName = "objects.json"
+
+// JSONOptions configures the json exporter
+type JSONOptions struct {
+	Compression compression.Codec // how chapter files are compressed; "" is compression.None
+	Layout      Layout            // where chapter files are written; "" is LayoutTree
+}
+
+// JSONExporter writes the canonical chapter JSON to books/{OSIS}/chNN.json, or to
+// books/{OSIS}/chNN.json.gz or .zst when compressed. In the content-addressed layout each chapter
+// is written once to objects/ under the SHA-256 of its file, and index/objects.json maps chapters
+// to their objects.
+type JSONExporter struct {
+	dir     string
+	opts    JSONOptions
+	objects *model.ObjectIndex // index/objects.json as of Begin, with the changes since
+	changed bool               // whether objects differs from index/objects.json on disk
+	files   map[string]string  // the file written for each chapter, by model.ObjectKey
+}
+
+// NewJSON creates the json exporter with opts. It is registered as "json" with the zero options.
+func NewJSON(dir string, opts JSONOptions) *JSONExporter {
+	if opts.Compression == "" {
+		opts.Compression = compression.None
+	}
+	if opts.Layout == "" {
+		opts.Layout = LayoutTree
+	}
+	objects := model.NewObjectIndex("")
+	return &JSONExporter{dir: dir, opts: opts, objects: &objects, files: make(map[string]string)}
+}
+
+// ChapterPath returns the path of a chapter's canonical JSON file beneath dir
+func ChapterPath(dir, osis string, chapter int) string {
+	return filepath.Join(dir, "books", osis, fmt.Sprintf("ch%02d.json", chapter))
+}
+
+// ChapterFile returns the path of the file WriteChapter last wrote for a chapter, or "" if it has
+// written none
+func (e *JSONExporter) ChapterFile(osis string, chapter int) string {
+	return e.files[model.ObjectKey(osis, chapter)]
+}
+
+// Begin loads index/objects.json, so that chapters written in either layout replace their entries
+// in it
+func (e *JSONExporter) Begin(work string) error {
+	objects := model.NewObjectIndex(work)
+	e.objects, e.changed = &objects, false
+	data, err := os.ReadFile(filepath.Join(e.dir, "index", ObjectIndexName)) // nolint: gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read %s: %w", ObjectIndexName, err)
+	}
+	if objects, err = model.ParseObjectIndex(data); err != nil {
+		return err
+	}
+	e.objects = &objects
+	return nil
+}
+
+func (e *JSONExporter) WriteChapter(ch *Chapter) error {
+	data, err := json.MarshalIndent(ch, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if data, err = e.opts.Compression.Compress(data); err != nil {
+		return err
+	}
+
+	plain := ChapterPath(e.dir, ch.OSIS, ch.Chapter)
+	key := model.ObjectKey(ch.OSIS, ch.Chapter)
+	keep := plain + e.opts.Compression.Ext()
+	if e.opts.Layout == LayoutCAS {
+		keep = ""
+		name := model.ObjectName(data, e.opts.Compression.Ext())
+		path := filepath.Join(e.dir, filepath.FromSlash(model.ObjectPath(name)))
+		if err := writeObject(path, data); err != nil {
+			return err
+		}
+		e.objects.Chapters[key] = name
+		e.changed = true
+		e.files[key] = path
+	} else {
+		if err := os.MkdirAll(filepath.Dir(keep), 0750); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := atomicfile.WriteFile(keep, data, 0600); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		if _, ok := e.objects.Chapters[key]; ok {
+			delete(e.objects.Chapters, key)
+			e.changed = true
+		}
+		e.files[key] = keep
+	}
+
+	// Readers take the first of chNN.json, .zst, .gz, and the chapter's object they find, so the
+	// chapter's files from runs with another compression or layout are removed
+	for _, codec := range append([]compression.Codec{compression.None}, compression.Codecs...) {
+		if path := plain + codec.Ext(); path != keep {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove stale chapter file: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeObject writes an object unless it already exists, which, being named by its contents,
+// means it holds the same data
+func writeObject(path string, data []byte) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	return nil
+}
+
+// Finish writes index/objects.json if any chapter's entry changed, or removes it once no chapter
+// is left in it
+func (e *JSONExporter) Finish() error {
+	if !e.changed {
+		return nil
+	}
+	path := filepath.Join(e.dir, "index", ObjectIndexName)
+	if len(e.objects.Chapters) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", ObjectIndexName, err)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(e.objects, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", ObjectIndexName, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ObjectIndexName, err)
+	}
+	e.changed = false
+	return nil
+}
@@ -0,0 +1,143 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+)
+
+func init() {
+	Register("latex", func(dir string) (Exporter, error) {
+		e := &latexExporter{}
+		e.bookWriter = bookWriter{dir: filepath.Join(dir, "latex"), ext: ".tex", render: func(work string, chapters []*Chapter) ([]byte, error) {
+			e.books = append(e.books, chapters[0].Abbr)
+			return renderLaTeXBook(chapters, e.opts)
+		}}
+		return e, nil
+	})
+}
+
+// latexExporter writes one LaTeX file per book to latex/{ABBR}.tex and, on Finish, a master
+// document latex/main.tex that defines the macros the books use and includes them in order
+type latexExporter struct {
+	bookWriter
+	opts  RenderOptions
+	books []string
+}
+
+func (e *latexExporter) SetRenderOptions(opts RenderOptions) {
+	e.opts = opts
+}
+
+func (e *latexExporter) Finish() error {
+	if err := e.bookWriter.Finish(); err != nil {
+		return err
+	}
+
+	path := filepath.Join(e.dir, "main.tex")
+	if err := atomicfile.WriteFile(path, []byte(renderLaTeXMain(e.work, e.books)), 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// latexPreamble sets up a two-column memoir document and defines the macros used by the book files.
+// Redefine \kjvbook after it to print full book names rather than OSIS codes.
+const latexPreamble = `\documentclass[10pt,twocolumn,openany]{memoir}
+\usepackage[T1]{fontenc}
+\usepackage[utf8]{inputenc}
+\usepackage{lettrine}
+
+\setlength{\columnsep}{1.5em}
+\setlength{\columnseprule}{0.4pt}
+\pagestyle{ruled}
+\nouppercaseheads
+
+% \kjvbook{OSIS} begins a book, \kjvchapter{N} a chapter
+\newcommand{\kjvbook}[1]{\chapter*{#1}\markboth{#1}{#1}}
+\newcommand{\kjvchapter}[1]{\par\bigskip\noindent{\large\bfseries Chapter #1}\par\nobreak\smallskip}
+% \versenumber{N} marks a verse, \add{...} words added by the translators, \divinename{...} the divine name
+\newcommand{\versenumber}[1]{\textsuperscript{\bfseries #1}\,}
+\newcommand{\add}[1]{\textit{#1}}
+\newcommand{\divinename}[1]{\textsc{#1}}
+`
+
+// renderLaTeXMain renders the master document including each book file in order
+func renderLaTeXMain(work string, books []string) string {
+	var b strings.Builder
+	b.WriteString(latexPreamble)
+	fmt.Fprintf(&b, "\n\\title{%s}\n\\author{}\n\\date{}\n\n\\begin{document}\n\\maketitle\n\n", escapeLaTeX(work))
+	for _, abbr := range books {
+		fmt.Fprintf(&b, "\\include{%s}\n", abbr)
+	}
+	b.WriteString("\n\\end{document}\n")
+	return b.String()
+}
+
+// renderLaTeXBook renders one book as a LaTeX fragment, written to latex/{ABBR}.tex
+// Verse numbers become \versenumber in the markup style, added words \add, the divine name
+// \divinename, footnotes \footnote, and a drop cap \lettrine, which takes the place of a leading
+// paragraph mark and, as in print editions, of the first verse number. Every chapter is headed
+// with \kjvchapter.
+func renderLaTeXBook(chapters []*Chapter, opts RenderOptions) ([]byte, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "\\kjvbook{%s}\n", escapeLaTeX(chapters[0].OSIS))
+	for _, ch := range chapters {
+		fmt.Fprintf(&b, "\n\\kjvchapter{%d}\n", ch.Chapter)
+
+		notes := footnotesByVerse(ch)
+		for i, verse := range ch.Verses {
+			if i > 0 && startsParagraph(verse) {
+				b.WriteString("\\par\n")
+			}
+
+			var text strings.Builder
+			for _, token := range verse.Tokens {
+				switch {
+				case token.Add != "":
+					fmt.Fprintf(&text, "\\add{%s}", escapeLaTeX(token.Add))
+				case token.ND != "":
+					fmt.Fprintf(&text, "\\divinename{%s}", escapeLaTeX(token.ND))
+				default:
+					text.WriteString(escapeLaTeX(token.Text))
+				}
+			}
+			rendered := strings.TrimSpace(text.String())
+			numbered := true
+			if i == 0 && opts.DropCap {
+				if _, letter, rest := splitDropCap(rendered); letter != "" {
+					rendered = fmt.Sprintf("\\lettrine{%s}{}%s", letter, rest)
+					numbered = false
+				}
+			}
+
+			if number, plain := plainVerseNumber(opts.VerseNumbers, verse.V); plain && numbered {
+				b.WriteString(escapeLaTeX(number))
+			} else if numbered {
+				fmt.Fprintf(&b, "\\versenumber{%d}", verse.V)
+			}
+			b.WriteString(rendered)
+
+			for _, fn := range notes[verse.V] {
+				fmt.Fprintf(&b, "\\footnote{%s}", escapeLaTeX(fn.Text))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+// latexEscaper escapes LaTeX's special characters
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`, `{`, `\{`, `}`, `\}`, `$`, `\$`, `&`, `\&`, `#`, `\#`, `%`, `\%`,
+	`_`, `\_`, `^`, `\textasciicircum{}`, `~`, `\textasciitilde{}`,
+)
+
+// escapeLaTeX escapes text for use in LaTeX
+func escapeLaTeX(s string) string {
+	return latexEscaper.Replace(s)
+}
@@ -0,0 +1,159 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+)
+
+func init() {
+	Register("markdown", func(dir string) (Exporter, error) {
+		return &markdownExporter{dir: filepath.Join(dir, "markdown")}, nil
+	})
+	Register("markdown-book", func(dir string) (Exporter, error) {
+		e := &markdownBookExporter{}
+		e.bookWriter = bookWriter{dir: filepath.Join(dir, "markdown"), ext: ".md", render: func(work string, chapters []*Chapter) ([]byte, error) {
+			return renderMarkdownBook(work, chapters, e.opts)
+		}}
+		return e, nil
+	})
+}
+
+// markdownExporter writes one Markdown file per chapter to markdown/{OSIS}/chNN.md
+type markdownExporter struct {
+	dir  string
+	work string
+	opts RenderOptions
+}
+
+func (e *markdownExporter) SetRenderOptions(opts RenderOptions) {
+	e.opts = opts
+}
+
+func (e *markdownExporter) Begin(work string) error {
+	e.work = work
+	return nil
+}
+
+func (e *markdownExporter) WriteChapter(ch *Chapter) error {
+	path := filepath.Join(e.dir, ch.OSIS, fmt.Sprintf("ch%02d.md", ch.Chapter))
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %q\n", fmt.Sprintf("%s %d", ch.OSIS, ch.Chapter))
+	fmt.Fprintf(&b, "work: %q\n", e.work)
+	fmt.Fprintf(&b, "osis: %q\n", ch.OSIS)
+	fmt.Fprintf(&b, "chapter: %d\n", ch.Chapter)
+	b.WriteString("---\n\n")
+	if e.opts.ChapterHeaders {
+		fmt.Fprintf(&b, "## Chapter %d\n\n", ch.Chapter)
+	}
+	writeMarkdownChapter(&b, ch, "", e.opts)
+
+	if err := atomicfile.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+func (e *markdownExporter) Finish() error {
+	return nil
+}
+
+// markdownBookExporter is the bookWriter for markdown-book, holding the options it renders with
+type markdownBookExporter struct {
+	bookWriter
+	opts RenderOptions
+}
+
+func (e *markdownBookExporter) SetRenderOptions(opts RenderOptions) {
+	e.opts = opts
+}
+
+// renderMarkdownBook renders one book as a single Markdown file, written to markdown/{ABBR}.md
+func renderMarkdownBook(work string, chapters []*Chapter, opts RenderOptions) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %q\n", chapters[0].OSIS)
+	fmt.Fprintf(&b, "work: %q\n", work)
+	fmt.Fprintf(&b, "osis: %q\n", chapters[0].OSIS)
+	fmt.Fprintf(&b, "chapters: %d\n", len(chapters))
+	b.WriteString("---\n")
+
+	for _, ch := range chapters {
+		fmt.Fprintf(&b, "\n## Chapter %d\n\n", ch.Chapter)
+		// Footnote labels must be unique within the file, so they carry the chapter number
+		writeMarkdownChapter(&b, ch, fmt.Sprintf("%d-", ch.Chapter), opts)
+	}
+
+	return []byte(b.String()), nil
+}
+
+// writeMarkdownChapter writes a chapter's verses with verse numbers in the style opts asks for
+// (<sup> by default), added words in italics, and footnotes as Markdown footnotes labelled with
+// prefix and a running number. A drop cap is a <span class="drop-cap"> around the first letter.
+func writeMarkdownChapter(b *strings.Builder, ch *Chapter, prefix string, opts RenderOptions) {
+	notes := footnotesByVerse(ch)
+	var definitions []string
+
+	for i, verse := range ch.Verses {
+		if i > 0 {
+			if startsParagraph(verse) {
+				b.WriteString("\n\n")
+			} else {
+				b.WriteString(" ")
+			}
+		}
+
+		if number, plain := plainVerseNumber(opts.VerseNumbers, verse.V); plain {
+			b.WriteString(escapeMarkdown(number))
+		} else {
+			fmt.Fprintf(b, "<sup>%d</sup>", verse.V)
+		}
+		var text strings.Builder
+		for _, token := range verse.Tokens {
+			switch {
+			case token.Add != "":
+				fmt.Fprintf(&text, "*%s*", escapeMarkdown(token.Add))
+			case token.ND != "":
+				text.WriteString(escapeMarkdown(token.ND))
+			default:
+				text.WriteString(escapeMarkdown(token.Text))
+			}
+		}
+		rendered := strings.TrimSpace(text.String())
+		if i == 0 && opts.DropCap {
+			if lead, letter, rest := splitDropCap(rendered); letter != "" {
+				rendered = lead + `<span class="drop-cap">` + letter + "</span>" + rest
+			}
+		}
+		b.WriteString(rendered)
+
+		for _, fn := range notes[verse.V] {
+			label := fmt.Sprintf("%s%d", prefix, len(definitions)+1)
+			fmt.Fprintf(b, "[^%s]", label)
+			definitions = append(definitions, fmt.Sprintf("[^%s]: %s", label, escapeMarkdown(fn.Text)))
+		}
+	}
+	b.WriteString("\n")
+
+	if len(definitions) > 0 {
+		b.WriteString("\n" + strings.Join(definitions, "\n") + "\n")
+	}
+}
+
+// markdownEscaper escapes characters that would otherwise be read as Markdown or HTML markup
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `<`, `&lt;`, "`", "\\`",
+)
+
+// escapeMarkdown escapes text for use in Markdown
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
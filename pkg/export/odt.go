@@ -0,0 +1,208 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"hash/crc32"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register("odt", func(dir string) (Exporter, error) {
+		e := &odtExporter{}
+		e.bookWriter = bookWriter{dir: filepath.Join(dir, "odt"), ext: ".odt", render: func(work string, chapters []*Chapter) ([]byte, error) {
+			return renderODT(work, chapters, e.opts)
+		}}
+		return e, nil
+	})
+}
+
+// odtExporter is the bookWriter for odt, holding the options it renders with
+type odtExporter struct {
+	bookWriter
+	opts RenderOptions
+}
+
+func (e *odtExporter) SetRenderOptions(opts RenderOptions) {
+	e.opts = opts
+}
+
+// odtMimetype is the media type of an OpenDocument text, which the package's first entry holds
+const odtMimetype = "application/vnd.oasis.opendocument.text"
+
+// odtNamespaces are the namespace declarations of the OpenDocument parts
+const odtNamespaces = `xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" ` +
+	`xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" ` +
+	`xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" ` +
+	`xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0" ` +
+	`xmlns:svg="urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0" ` +
+	`xmlns:dc="http://purl.org/dc/elements/1.1/" ` +
+	`xmlns:meta="urn:oasis:names:tc:opendocument:xmlns:meta:1.0" office:version="1.3"`
+
+// odtManifest lists the parts of the package
+const odtManifest = xml.Header + `<manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0" manifest:version="1.3">
+<manifest:file-entry manifest:full-path="/" manifest:version="1.3" manifest:media-type="` + odtMimetype + `"/>
+<manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>
+<manifest:file-entry manifest:full-path="styles.xml" manifest:media-type="text/xml"/>
+<manifest:file-entry manifest:full-path="meta.xml" manifest:media-type="text/xml"/>
+</manifest:manifest>
+`
+
+// odtStyles defines the styles the document uses, so they can be restyled in LibreOffice: the
+// paragraph styles Title (the book), Heading 2 (each chapter), Text body, and Footnote, and the
+// character styles Verse Number, Added Word, Divine Name, and Footnote anchor
+const odtStyles = xml.Header + `<office:document-styles ` + odtNamespaces + `>
+<office:font-face-decls><style:font-face style:name="Georgia" svg:font-family="Georgia"/></office:font-face-decls>
+<office:styles>
+<style:default-style style:family="paragraph"><style:paragraph-properties fo:margin-bottom="0.21cm" fo:line-height="120%"/><style:text-properties style:font-name="Georgia" fo:font-size="11pt" fo:language="en" fo:country="GB"/></style:default-style>
+<style:style style:name="Standard" style:family="paragraph" style:class="text"/>
+<style:style style:name="Text_20_body" style:display-name="Text body" style:family="paragraph" style:parent-style-name="Standard" style:class="text"/>
+<style:style style:name="Title" style:family="paragraph" style:parent-style-name="Standard" style:next-style-name="Text_20_body" style:class="chapter"><style:paragraph-properties fo:text-align="center" fo:margin-bottom="0.42cm"/><style:text-properties fo:font-size="24pt"/></style:style>
+<style:style style:name="Heading_20_2" style:display-name="Heading 2" style:family="paragraph" style:parent-style-name="Standard" style:next-style-name="Text_20_body" style:default-outline-level="2" style:class="text"><style:paragraph-properties fo:margin-top="0.42cm" fo:margin-bottom="0.21cm" fo:keep-with-next="always"/><style:text-properties fo:font-size="13pt" fo:font-weight="bold"/></style:style>
+<style:style style:name="Footnote" style:family="paragraph" style:parent-style-name="Standard" style:class="extra"><style:paragraph-properties fo:margin-bottom="0cm" fo:line-height="100%"/><style:text-properties fo:font-size="9pt"/></style:style>
+<style:style style:name="Verse_20_Number" style:display-name="Verse Number" style:family="text"><style:text-properties fo:font-weight="bold" fo:color="#808080" style:text-position="super 58%"/></style:style>
+<style:style style:name="Added_20_Word" style:display-name="Added Word" style:family="text"><style:text-properties fo:font-style="italic"/></style:style>
+<style:style style:name="Divine_20_Name" style:display-name="Divine Name" style:family="text"><style:text-properties fo:font-variant="small-caps"/></style:style>
+<style:style style:name="Footnote_20_anchor" style:display-name="Footnote anchor" style:family="text"><style:text-properties style:text-position="super 58%"/></style:style>
+<text:notes-configuration text:note-class="footnote" text:citation-style-name="Footnote_20_anchor" text:default-style-name="Footnote" style:num-format="1" text:start-value="0" text:footnotes-position="page" text:start-numbering-at="document"/>
+</office:styles>
+</office:document-styles>
+`
+
+// odtAutomaticStyles are the styles of content.xml that are not offered for restyling: Drop_Cap,
+// the first paragraph of a chapter opening with a drop cap
+const odtAutomaticStyles = `<office:automatic-styles>
+<style:style style:name="Drop_Cap" style:family="paragraph" style:parent-style-name="Text_20_body"><style:paragraph-properties><style:drop-cap style:lines="3" style:length="1" style:distance="0.1cm"/></style:paragraph-properties></style:style>
+</office:automatic-styles>
+`
+
+// renderODT renders one book as an OpenDocument text, written to odt/{ABBR}.odt, laid out as
+// renderDOCX lays out a Word document: the book is titled with its OSIS code and each chapter
+// headed "Chapter N", verse numbers, added words, and the divine name are spans in the Verse
+// Number, Added Word, and Divine Name character styles, and footnotes are OpenDocument footnotes.
+// A drop cap takes the place of the first verse number, as in print editions.
+func renderODT(work string, chapters []*Chapter, opts RenderOptions) ([]byte, error) {
+	var body strings.Builder
+	footnoteID := 0
+
+	fmt.Fprintf(&body, "<text:p text:style-name=\"Title\">%s</text:p>\n", escapeXML(chapters[0].OSIS))
+	for _, ch := range chapters {
+		fmt.Fprintf(&body, "<text:h text:style-name=\"Heading_20_2\" text:outline-level=\"2\">Chapter %d</text:h>\n", ch.Chapter)
+
+		byVerse := footnotesByVerse(ch)
+		for i, verse := range ch.Verses {
+			var spans []docxSpan
+			for _, token := range verse.Tokens {
+				switch {
+				case token.Add != "":
+					spans = append(spans, docxSpan{style: "Added_20_Word", text: token.Add})
+				case token.ND != "":
+					spans = append(spans, docxSpan{style: "Divine_20_Name", text: token.ND})
+				case token.Text != "":
+					spans = append(spans, docxSpan{text: token.Text})
+				}
+			}
+			if len(spans) > 0 {
+				spans[0].text = strings.TrimLeft(spans[0].text, " ")
+				spans[len(spans)-1].text = strings.TrimRight(spans[len(spans)-1].text, " ")
+			}
+
+			numbered := true
+			switch {
+			case i == 0:
+				style := "Text_20_body"
+				// The drop cap is the paragraph's first letter, so a leading paragraph mark is left out
+				if opts.DropCap && len(spans) > 0 && spans[0].style == "" {
+					if _, letter, rest := splitDropCap(spans[0].text); letter != "" {
+						spans[0].text = letter + rest
+						style, numbered = "Drop_Cap", false
+					}
+				}
+				fmt.Fprintf(&body, "<text:p text:style-name=\"%s\">", style)
+			case startsParagraph(verse):
+				body.WriteString("</text:p>\n<text:p text:style-name=\"Text_20_body\">")
+			default:
+				body.WriteString(" ")
+			}
+
+			if number, plain := plainVerseNumber(opts.VerseNumbers, verse.V); plain && numbered {
+				body.WriteString(odtSpan("", number))
+			} else if numbered {
+				body.WriteString(odtSpan("Verse_20_Number", fmt.Sprint(verse.V)))
+			}
+			for _, span := range spans {
+				body.WriteString(odtSpan(span.style, span.text))
+			}
+
+			for _, fn := range byVerse[verse.V] {
+				footnoteID++
+				fmt.Fprintf(&body, `<text:note text:id="ftn%d" text:note-class="footnote"><text:note-citation>%d</text:note-citation>`+
+					`<text:note-body><text:p text:style-name="Footnote">%s</text:p></text:note-body></text:note>`,
+					footnoteID, footnoteID, escapeXML(fn.Text))
+			}
+		}
+		if len(ch.Verses) > 0 {
+			body.WriteString("</text:p>\n")
+		}
+	}
+
+	parts := []struct{ name, content string }{
+		{"META-INF/manifest.xml", odtManifest},
+		{"meta.xml", xml.Header + "<office:document-meta " + odtNamespaces + "><office:meta><dc:title>" + escapeXML(chapters[0].OSIS) +
+			`</dc:title><meta:user-defined meta:name="Work">` + escapeXML(work) + "</meta:user-defined></office:meta></office:document-meta>\n"},
+		{"styles.xml", odtStyles},
+		{"content.xml", xml.Header + "<office:document-content " + odtNamespaces + ">\n" + odtAutomaticStyles +
+			"<office:body>\n<office:text>\n" + body.String() + "</office:text>\n</office:body>\n</office:document-content>\n"},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	// The mimetype comes first and uncompressed, without a data descriptor, so that it can be read
+	// at a fixed offset to identify the file
+	w, err := zw.CreateRaw(&zip.FileHeader{
+		Name:               "mimetype",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE([]byte(odtMimetype)),
+		CompressedSize64:   uint64(len(odtMimetype)),
+		UncompressedSize64: uint64(len(odtMimetype)),
+	})
+	if err == nil {
+		_, err = w.Write([]byte(odtMimetype))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write mimetype: %w", err)
+	}
+	for _, part := range parts {
+		// The zero modification time keeps the archive identical from run to run
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: part.name, Method: zip.Deflate})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write odt: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// odtSpan renders text in the given character style, or none; empty text renders nothing. Runs of
+// spaces, which OpenDocument collapses, are written as <text:s/>.
+func odtSpan(style, text string) string {
+	if text == "" {
+		return ""
+	}
+	escaped := escapeXML(text)
+	for strings.Contains(escaped, "  ") {
+		escaped = strings.ReplaceAll(escaped, "  ", " <text:s/>")
+	}
+	if style == "" {
+		return escaped
+	}
+	return `<text:span text:style-name="` + style + `">` + escaped + `</text:span>`
+}
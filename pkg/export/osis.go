@@ -0,0 +1,75 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register("osis", func(dir string) (Exporter, error) {
+		return &bookWriter{dir: filepath.Join(dir, "osis"), ext: ".xml", render: renderOSIS}, nil
+	})
+}
+
+// renderOSIS renders one book as an OSIS XML document, written to osis/{ABBR}.xml
+// Added words become <transChange type="added">, the divine name <divineName>, and footnotes <note>
+func renderOSIS(work string, chapters []*Chapter) ([]byte, error) {
+	var b strings.Builder
+
+	bookID := osisID(chapters[0].OSIS)
+	b.WriteString(xml.Header)
+	b.WriteString(`<osis xmlns="http://www.bibletechnologies.net/2003/OSIS/namespace">` + "\n")
+	fmt.Fprintf(&b, "<osisText osisIDWork=\"%s\" osisRefWork=\"Bible\">\n", escapeXML(work))
+	fmt.Fprintf(&b, "<div type=\"book\" osisID=\"%s\">\n", escapeXML(bookID))
+
+	for _, ch := range chapters {
+		chapterID := fmt.Sprintf("%s.%d", bookID, ch.Chapter)
+		fmt.Fprintf(&b, "<chapter osisID=\"%s\">\n", escapeXML(chapterID))
+
+		notes := footnotesByVerse(ch)
+		for _, verse := range ch.Verses {
+			verseID := fmt.Sprintf("%s.%d", chapterID, verse.V)
+			fmt.Fprintf(&b, "<verse osisID=\"%s\">", escapeXML(verseID))
+
+			var text strings.Builder
+			for _, token := range verse.Tokens {
+				switch {
+				case token.Add != "":
+					fmt.Fprintf(&text, `<transChange type="added">%s</transChange>`, escapeXML(token.Add))
+				case token.ND != "":
+					fmt.Fprintf(&text, "<divineName>%s</divineName>", escapeXML(token.ND))
+				default:
+					text.WriteString(escapeXML(token.Text))
+				}
+			}
+			b.WriteString(strings.TrimRight(text.String(), " "))
+
+			for _, fn := range notes[verse.V] {
+				fmt.Fprintf(&b, "<note type=\"translation\" osisRef=\"%s\" n=\"%s\">%s</note>",
+					escapeXML(verseID), escapeXML(fn.Mark), escapeXML(fn.Text))
+			}
+			b.WriteString("</verse>\n")
+		}
+
+		b.WriteString("</chapter>\n")
+	}
+
+	b.WriteString("</div>\n</osisText>\n</osis>\n")
+	return []byte(b.String()), nil
+}
+
+// osisID returns an OSIS identifier for a book, removing spaces (e.g. "Add Esth" -> "AddEsth")
+func osisID(osis string) string {
+	return strings.ReplaceAll(osis, " ", "")
+}
+
+// escapeXML escapes text for use in XML character data
+func escapeXML(s string) string {
+	var b strings.Builder
+	if err := xml.EscapeText(&b, []byte(s)); err != nil {
+		return s
+	}
+	return b.String()
+}
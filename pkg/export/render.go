@@ -0,0 +1,109 @@
+package export
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// VerseNumberStyle is how verse numbers are shown in formats rendered for reading
+type VerseNumberStyle string
+
+const (
+	// VerseNumbersMarkup uses the format's own superscript markup, such as <sup>1</sup> in Markdown
+	VerseNumbersMarkup VerseNumberStyle = "markup"
+	// VerseNumbersUnicode writes the number in Unicode superscript digits, as in "¹²In the beginning"
+	VerseNumbersUnicode VerseNumberStyle = "unicode"
+	// VerseNumbersBracketed writes the number in square brackets, as in "[12] In the beginning"
+	VerseNumbersBracketed VerseNumberStyle = "bracketed"
+	// VerseNumbersOmitted leaves verse numbers out, for continuous reading
+	VerseNumbersOmitted VerseNumberStyle = "omitted"
+)
+
+// VerseNumberStyles lists the verse number styles
+var VerseNumberStyles = []VerseNumberStyle{VerseNumbersMarkup, VerseNumbersUnicode, VerseNumbersBracketed, VerseNumbersOmitted}
+
+// RenderOptions controls how formats rendered for reading, such as markdown and html, present the text.
+// The zero value renders each format's default.
+type RenderOptions struct {
+	VerseNumbers   VerseNumberStyle // empty for VerseNumbersMarkup
+	ChapterHeaders bool             // head each chapter with "Chapter N"; book-per-file formats always do
+	DropCap        bool             // mark the first letter of each chapter as a drop cap
+	Stylesheet     bool             // embed DefaultStylesheet in each html chapter
+}
+
+// Validate reports an unknown verse number style
+func (o RenderOptions) Validate() error {
+	if o.VerseNumbers != "" && !slices.Contains(VerseNumberStyles, o.VerseNumbers) {
+		return fmt.Errorf("unknown verse number style %q", o.VerseNumbers)
+	}
+	return nil
+}
+
+// Rendering is implemented by exporters that render text for reading and accept RenderOptions.
+// Data formats such as json, usfm, and osis do not implement it.
+type Rendering interface {
+	SetRenderOptions(opts RenderOptions)
+}
+
+// Configure validates opts and passes them to e, or to every exporter of a Multi, that implements
+// Rendering. Other exporters are left unchanged.
+func Configure(e Exporter, opts RenderOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if multi, ok := e.(Multi); ok {
+		for _, each := range multi {
+			if err := Configure(each, opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if r, ok := e.(Rendering); ok {
+		r.SetRenderOptions(opts)
+	}
+	return nil
+}
+
+// superscriptDigits are the Unicode superscript forms of 0-9
+var superscriptDigits = []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+
+// superscript writes n in Unicode superscript digits
+func superscript(n int) string {
+	var b strings.Builder
+	for _, d := range strconv.Itoa(n) {
+		b.WriteRune(superscriptDigits[d-'0'])
+	}
+	return b.String()
+}
+
+// plainVerseNumber renders a verse number in the plain-text styles, or returns false for
+// VerseNumbersMarkup, which each format renders with its own markup
+func plainVerseNumber(style VerseNumberStyle, v int) (string, bool) {
+	switch style {
+	case VerseNumbersUnicode:
+		return superscript(v), true
+	case VerseNumbersBracketed:
+		return fmt.Sprintf("[%d] ", v), true
+	case VerseNumbersOmitted:
+		return "", true
+	default:
+		return "", false
+	}
+}
+
+// splitDropCap splits text around its first letter, which a drop cap is placed on. A leading
+// paragraph mark and spaces are kept before it; text starting with anything else has no drop cap
+// and is returned whole as lead.
+func splitDropCap(text string) (lead, letter, rest string) {
+	start := len(text) - len(strings.TrimLeft(text, "¶ "))
+	r, size := utf8.DecodeRuneInString(text[start:])
+	if !unicode.IsLetter(r) {
+		return text, "", ""
+	}
+	return text[:start], text[start : start+size], text[start+size:]
+}
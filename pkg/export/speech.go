@@ -0,0 +1,158 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+)
+
+func init() {
+	Register("ssml", func(dir string) (Exporter, error) {
+		return &speechExporter{dir: filepath.Join(dir, "ssml"), ext: ".ssml", render: renderSSML}, nil
+	})
+	Register("speech", func(dir string) (Exporter, error) {
+		return &speechExporter{dir: filepath.Join(dir, "speech"), ext: ".txt", render: renderSpeech}, nil
+	})
+}
+
+// speechExporter writes one file per chapter to {format}/{OSIS}/chNN{ext}, holding the chapter as
+// it is to be read aloud by a text-to-speech engine: without verse numbers, paragraph marks, or
+// footnotes. Of RenderOptions only ChapterHeaders applies.
+type speechExporter struct {
+	dir    string
+	ext    string
+	opts   RenderOptions
+	render func(ch *Chapter, opts RenderOptions) string
+}
+
+func (e *speechExporter) SetRenderOptions(opts RenderOptions) {
+	e.opts = opts
+}
+
+func (e *speechExporter) Begin(work string) error {
+	return nil
+}
+
+func (e *speechExporter) WriteChapter(ch *Chapter) error {
+	path := filepath.Join(e.dir, ch.OSIS, fmt.Sprintf("ch%02d%s", ch.Chapter, e.ext))
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := atomicfile.WriteFile(path, []byte(e.render(ch, e.opts)), 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+func (e *speechExporter) Finish() error {
+	return nil
+}
+
+// renderSpeech renders a chapter as plain prose, one paragraph per line with a blank line between
+func renderSpeech(ch *Chapter, opts RenderOptions) string {
+	var b strings.Builder
+	if opts.ChapterHeaders {
+		fmt.Fprintf(&b, "Chapter %d.\n\n", ch.Chapter)
+	}
+	for i, verse := range ch.Verses {
+		if i > 0 {
+			if startsParagraph(verse) {
+				b.WriteString("\n\n")
+			} else {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(spokenText(verse))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderSSML renders a chapter as an SSML 1.1 document with a <p> per paragraph. Each verse is
+// preceded by a <mark> named by its OSIS ID, such as "Gen.1.1", which engines report as they reach
+// it, so the audio can be aligned with the verses.
+func renderSSML(ch *Chapter, opts RenderOptions) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<speak version="1.1" xmlns="http://www.w3.org/2001/10/synthesis" xml:lang="en">` + "\n")
+	if opts.ChapterHeaders {
+		fmt.Fprintf(&b, "<p>Chapter %d.</p>\n", ch.Chapter)
+	}
+	b.WriteString("<p>")
+	for i, verse := range ch.Verses {
+		if i > 0 {
+			if startsParagraph(verse) {
+				b.WriteString("</p>\n<p>")
+			} else {
+				b.WriteString(" ")
+			}
+		}
+		fmt.Fprintf(&b, `<mark name="%s.%d.%d"/>`, escapeXML(osisID(ch.OSIS)), ch.Chapter, verse.V)
+		b.WriteString(escapeXML(spokenText(verse)))
+	}
+	b.WriteString("</p>\n</speak>\n")
+	return b.String()
+}
+
+// speechMarks are characters that engines would read aloud or stumble on: paragraph marks, and
+// the brackets around words some manuscripts lack, as in 1 John 2:23
+var speechMarks = strings.NewReplacer("¶", "", "[", "", "]", "")
+
+// speechAbbreviations are the abbreviations expanded to the words an engine should say. The 1769
+// text spells its words out, but editions and notes of the period abbreviate these.
+var speechAbbreviations = map[string]string{
+	"&c.":    "et cetera",
+	"etc.":   "et cetera",
+	"viz.":   "namely",
+	"i.e.":   "that is",
+	"Heb.":   "Hebrew",
+	"Gr.":    "Greek",
+	"Chald.": "Chaldee",
+}
+
+// spokenText returns the text of a verse as it is to be spoken: added words as plain text, the
+// divine name in ordinary capitalization, abbreviations expanded, and marks and runs of spaces
+// removed
+func spokenText(verse Verse) string {
+	var text strings.Builder
+	for _, token := range verse.Tokens {
+		switch {
+		case token.Add != "":
+			text.WriteString(token.Add)
+		case token.ND != "":
+			text.WriteString(speakDivineName(token.ND))
+		default:
+			text.WriteString(token.Text)
+		}
+	}
+
+	words := strings.Fields(speechMarks.Replace(text.String()))
+	for i, word := range words {
+		bare := strings.TrimRight(word, ",;:")
+		if expanded, ok := speechAbbreviations[bare]; ok {
+			words[i] = expanded + word[len(bare):]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// speakDivineName returns the divine name, which the text sets in capitals for small capitals, as
+// an ordinary capitalized word, so "LORD" and "LORD’s" are said as "Lord" and "Lord’s" rather than
+// spelled out
+func speakDivineName(name string) string {
+	var b strings.Builder
+	afterLetter := false
+	for _, r := range name {
+		if afterLetter {
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+		afterLetter = unicode.IsLetter(r)
+	}
+	return b.String()
+}
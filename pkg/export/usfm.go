@@ -0,0 +1,57 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	Register("usfm", func(dir string) (Exporter, error) {
+		return &bookWriter{dir: filepath.Join(dir, "usfm"), ext: ".usfm", render: renderUSFM}, nil
+	})
+}
+
+// renderUSFM renders one book as USFM, written to usfm/{ABBR}.usfm
+// Added words become \add, the divine name \nd, and footnotes \f with a chapter:verse reference
+func renderUSFM(work string, chapters []*Chapter) ([]byte, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "\\id %s %s\n", chapters[0].Abbr, work)
+	for _, ch := range chapters {
+		fmt.Fprintf(&b, "\\c %d\n\\p\n", ch.Chapter)
+
+		notes := footnotesByVerse(ch)
+		for i, verse := range ch.Verses {
+			if i > 0 && startsParagraph(verse) {
+				b.WriteString("\\p\n")
+			}
+
+			fmt.Fprintf(&b, "\\v %d ", verse.V)
+			var text strings.Builder
+			for _, token := range verse.Tokens {
+				switch {
+				case token.Add != "":
+					fmt.Fprintf(&text, "\\add %s\\add*", token.Add)
+				case token.ND != "":
+					fmt.Fprintf(&text, "\\nd %s\\nd*", token.ND)
+				default:
+					text.WriteString(token.Text)
+				}
+			}
+			b.WriteString(strings.TrimRight(text.String(), " "))
+
+			for _, fn := range notes[verse.V] {
+				fmt.Fprintf(&b, "\\f %s \\fr %d:%d \\ft %s\\f*", fn.Mark, ch.Chapter, verse.V, fn.Text)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+// startsParagraph reports whether a verse begins with a pilcrow paragraph mark
+func startsParagraph(verse Verse) bool {
+	return len(verse.Tokens) > 0 && strings.HasPrefix(verse.Tokens[0].Text, "¶")
+}
@@ -0,0 +1,37 @@
+package kjvcorpus
+
+import (
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// dropAdded returns copies of verses without their added-word tokens, with plain text rebuilt
+// from the tokens left. The space an added word leaves is closed up, so "that it was good" with
+// "it was" added reads "that good".
+func dropAdded(verses []model.Verse) []model.Verse {
+	result := make([]model.Verse, len(verses))
+	for i, verse := range verses {
+		tokens := make([]model.Token, 0, len(verse.Tokens))
+		var text strings.Builder
+		for _, token := range verse.Tokens {
+			if token.Add != "" {
+				continue
+			}
+			tokens = append(tokens, token)
+			text.WriteString(token.Text)
+			text.WriteString(token.ND)
+		}
+		result[i] = model.Verse{V: verse.V, Plain: closeUp(text.String()), Tokens: tokens}
+	}
+	return result
+}
+
+// closeUp collapses runs of spaces, removes those left before punctuation, and trims the ends
+func closeUp(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	for _, mark := range []string{",", ";", ":", ".", "?", "!", ")"} {
+		text = strings.ReplaceAll(text, " "+mark, mark)
+	}
+	return strings.ReplaceAll(text, "( ", "(")
+}
@@ -0,0 +1,59 @@
+package kjvcorpus
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDropAdded(t *testing.T) {
+	corpus := openCanon(t)
+
+	ref := Ref{OSIS: "Gen", Chapter: 1, Verses: &VerseRange{Start: 4}}
+	resolved, err := corpus.ResolveWith(ref, ResolveOptions{DropAdded: true})
+	if err != nil {
+		t.Fatalf("ResolveWith failed: %v", err)
+	}
+	verse := resolved.Verses[0]
+	if want := "And God saw the light, that good: and God divided the light from the darkness."; verse.Plain != want {
+		t.Errorf("expected %q, got %q", want, verse.Plain)
+	}
+	for _, token := range verse.Tokens {
+		if token.Add != "" {
+			t.Errorf("added token left in: %+v", token)
+		}
+	}
+	if got := resolved.Citation(); got != "Genesis 1:4 (KJV, added words omitted)" {
+		t.Errorf("unexpected citation %q", got)
+	}
+	data, err := json.Marshal(resolved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"no_added":true`) {
+		t.Errorf("expected no_added in %s", data)
+	}
+
+	// The cached chapter keeps its added words
+	full, err := corpus.ResolveRef(ref)
+	if err != nil {
+		t.Fatalf("ResolveRef failed: %v", err)
+	}
+	if !strings.Contains(full.Verses[0].Plain, "that it was good") || full.NoAdded {
+		t.Errorf("expected the full verse, got %q", full.Verses[0].Plain)
+	}
+}
+
+func TestCloseUp(t *testing.T) {
+	tests := map[string]string{
+		"that  good: and":         "that good: and",
+		"for thou  with me ;":     "for thou with me;",
+		" ( my  ) sake .":         "(my) sake.",
+		"¶ In the beginning God ": "¶ In the beginning God",
+	}
+	for in, want := range tests {
+		if got := closeUp(in); got != want {
+			t.Errorf("closeUp(%q) = %q; want %q", in, got, want)
+		}
+	}
+}
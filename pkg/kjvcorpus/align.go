@@ -0,0 +1,164 @@
+package kjvcorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Alignment returns the original-language alignment of a chapter from its align/ sidecar. The
+// sidecar is checked against the chapter's text when first read, so a returned alignment only
+// points at words the verses have. Chapters without a sidecar return ErrAlignmentNotFound.
+func (c *Corpus) Alignment(osis string, chapter int) (*model.Alignment, error) {
+	snap := c.snap.Load()
+	osis = snap.bookID(osis)
+	if _, err := snap.checkChapter(osis, chapter); err != nil {
+		return nil, err
+	}
+	return snap.loadAlignment(c.store, osis, chapter)
+}
+
+// VerseAlignment returns the alignment of a single verse, or ErrAlignmentNotFound if the chapter
+// has no sidecar or the sidecar leaves the verse out
+func (c *Corpus) VerseAlignment(osis string, chapter, verse int) (*model.VerseAlignment, error) {
+	alignment, err := c.Alignment(osis, chapter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range alignment.Verses {
+		if alignment.Verses[i].V == verse {
+			return &alignment.Verses[i], nil
+		}
+	}
+
+	msg := fmt.Sprintf("%s %d:%d is not aligned", osis, chapter, verse)
+	return nil, &CorpusError{
+		Kind:    RangeError,
+		Message: &msg,
+		Err:     ErrAlignmentNotFound,
+	}
+}
+
+// loadAlignment reads, parses, and validates an alignment sidecar into the snapshot on first use
+func (s *snapshot) loadAlignment(store ChapterStore, osis string, chapter int) (*model.Alignment, error) {
+	key := chapterKey{osis: osis, chapter: chapter}
+	s.mu.RLock()
+	if alignment, exists := s.aligned[key]; exists {
+		s.mu.RUnlock()
+		return alignment, nil
+	}
+	s.mu.RUnlock()
+
+	alignPath := AlignmentPath(osis, chapter)
+	var data []byte
+	err := fs.ErrNotExist
+	if aligner, ok := store.(AlignmentStore); ok {
+		data, err = aligner.ReadAlignment(osis, chapter)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("failed to read alignment file: %s", alignPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			msg = fmt.Sprintf("no alignment for %s %d", osis, chapter)
+		}
+		return nil, &CorpusError{
+			Kind:    FileError,
+			Message: &msg,
+			Err:     ErrAlignmentNotFound,
+			Cause:   err,
+		}
+	}
+
+	alignment, err := ParseAlignment(data)
+	if err != nil {
+		msg := fmt.Sprintf("failed to parse alignment file: %s", alignPath)
+		return nil, &CorpusError{
+			Kind:    ParseError,
+			Message: &msg,
+			Err:     err,
+		}
+	}
+
+	loaded, err := s.loadChapter(store, osis, chapter)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateAlignment(loaded.Chapter, alignment); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if existing, exists := s.aligned[key]; exists {
+		alignment = existing
+	} else {
+		s.aligned[key] = alignment
+	}
+	s.mu.Unlock()
+	return alignment, nil
+}
+
+// ParseAlignment parses an alignment sidecar, rejecting schema versions other than the current one
+func ParseAlignment(data []byte) (*model.Alignment, error) {
+	var alignment model.Alignment
+	if err := json.Unmarshal(data, &alignment); err != nil {
+		return nil, fmt.Errorf("JSON unmarshal failed: %w", err)
+	}
+	if alignment.Schema != model.AlignmentSchema {
+		return nil, fmt.Errorf("unsupported alignment schema version %d", alignment.Schema)
+	}
+	return &alignment, nil
+}
+
+// ValidateAlignment checks an alignment against the chapter it belongs to: it must name the same
+// book and chapter, align each verse at most once and only verses the chapter has, agree with each
+// verse's KJV word count, and point only at words within the verse. Every problem found is listed
+// in the returned error, which wraps ErrAlignmentMismatch.
+func ValidateAlignment(chapter *model.Chapter, alignment *model.Alignment) error {
+	var problems []string
+	if alignment.OSIS != chapter.OSIS || alignment.Chapter != chapter.Chapter {
+		problems = append(problems, fmt.Sprintf("alignment is for %s %d", alignment.OSIS, alignment.Chapter))
+	}
+
+	words := make(map[int]int, len(chapter.Verses))
+	for _, verse := range chapter.Verses {
+		words[verse.V] = len(verse.Words())
+	}
+
+	seen := make(map[int]bool, len(alignment.Verses))
+	for _, va := range alignment.Verses {
+		count, exists := words[va.V]
+		switch {
+		case !exists:
+			problems = append(problems, fmt.Sprintf("verse %d does not exist", va.V))
+			continue
+		case seen[va.V]:
+			problems = append(problems, fmt.Sprintf("verse %d is aligned twice", va.V))
+			continue
+		case va.KJVWords != count:
+			problems = append(problems, fmt.Sprintf("verse %d was aligned against %d words but has %d", va.V, va.KJVWords, count))
+			continue
+		}
+		seen[va.V] = true
+
+		for i, word := range va.Words {
+			for _, position := range word.KJV {
+				if position < 0 || position >= count {
+					problems = append(problems, fmt.Sprintf("verse %d word %d (%s) points at word %d of %d", va.V, i, word.Text, position, count))
+				}
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("alignment of %s %d: %s", chapter.OSIS, chapter.Chapter, strings.Join(problems, "; "))
+	return &CorpusError{
+		Kind:    ContentError,
+		Message: &msg,
+		Err:     ErrAlignmentMismatch,
+	}
+}
@@ -0,0 +1,70 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAlignment(t *testing.T) {
+	books := `{"schema": 1, "work": "KJV", "books": [{"osis": "Gen", "abbr": "GEN", "name": "Genesis", "aliases": ["Genesis"], "testament": "OT", "order": 1, "chapters": 50}]}`
+	chapter := `{"schema": 1, "work": "KJV", "osis": "Gen", "abbr": "GEN", "chapter": 1, "verses": [
+		{"v": 1, "tokens": [{"t": "In the beginning God created the heaven and the earth. "}]},
+		{"v": 2, "tokens": [{"t": "And the earth was without form, and void; and darkness "}, {"add": "was"}, {"t": " upon the face of the deep."}]}
+	]}`
+	alignment := `{"schema": 1, "osis": "Gen", "chapter": 1, "source": "test", "language": "hbo", "verses": [
+		{"v": 1, "kjv_words": 10, "words": [
+			{"text": "בְּרֵאשִׁית", "lemma": "H7225", "kjv": [0, 1, 2]},
+			{"text": "בָּרָא", "lemma": "H1254", "kjv": [4]},
+			{"text": "אֱלֹהִים", "lemma": "H430", "kjv": [3]},
+			{"text": "אֵת", "lemma": "H853", "kjv": []}
+		]}
+	]}`
+	store := NewFSStore(fstest.MapFS{
+		"index/books.json":    &fstest.MapFile{Data: []byte(books)},
+		"books/Gen/ch01.json": &fstest.MapFile{Data: []byte(chapter)},
+		"books/Gen/ch02.json": &fstest.MapFile{Data: []byte(`{"schema": 1, "osis": "Gen", "chapter": 2, "verses": [{"v": 1, "tokens": [{"t": "Thus the heavens"}]}]}`)},
+		"books/Gen/ch03.json": &fstest.MapFile{Data: []byte(`{"schema": 1, "osis": "Gen", "chapter": 3, "verses": [{"v": 1, "tokens": [{"t": "Now the serpent"}]}]}`)},
+		"align/Gen/ch01.json": &fstest.MapFile{Data: []byte(alignment)},
+		// Aligned against an older text with more words, and pointing past the end of the verse
+		"align/Gen/ch02.json": &fstest.MapFile{Data: []byte(`{"schema": 1, "osis": "Gen", "chapter": 2, "verses": [{"v": 1, "kjv_words": 4, "words": [{"text": "וַיְכֻלּוּ", "kjv": [3]}]}]}`)},
+		"align/Gen/ch03.json": &fstest.MapFile{Data: []byte(`{"schema": 1, "osis": "Gen", "chapter": 3, "verses": [{"v": 1, "kjv_words": 3, "words": [{"text": "וְהַנָּחָשׁ", "kjv": [0, 3]}]}]}`)},
+	})
+	corpus, err := Open("", WithStore(store))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+
+	verse, err := corpus.VerseAlignment("Gen", 1, 1)
+	if err != nil {
+		t.Fatalf("VerseAlignment failed: %v", err)
+	}
+	if len(verse.Words) != 4 || verse.Words[2].Lemma != "H430" || verse.Words[2].KJV[0] != 3 {
+		t.Errorf("unexpected alignment of Gen 1:1: %+v", verse)
+	}
+
+	if _, err := corpus.VerseAlignment("Gen", 1, 2); !errors.Is(err, ErrAlignmentNotFound) {
+		t.Errorf("expected ErrAlignmentNotFound for an unaligned verse, got %v", err)
+	}
+	if _, err := corpus.Alignment("Gen", 4); !errors.Is(err, ErrAlignmentNotFound) {
+		t.Errorf("expected ErrAlignmentNotFound for a chapter without a sidecar, got %v", err)
+	}
+	if _, err := corpus.Alignment("Gen", 51); !errors.Is(err, ErrChapterNotFound) {
+		t.Errorf("expected ErrChapterNotFound past the last chapter, got %v", err)
+	}
+	if _, err := corpus.Alignment("Gen", 2); !errors.Is(err, ErrAlignmentMismatch) {
+		t.Errorf("expected ErrAlignmentMismatch for a stale word count, got %v", err)
+	}
+	if _, err := corpus.Alignment("Gen", 3); !errors.Is(err, ErrAlignmentMismatch) {
+		t.Errorf("expected ErrAlignmentMismatch for a position past the verse, got %v", err)
+	}
+
+	// Stores without alignment support have no alignments
+	bare, err := Open("", WithStore(struct{ ChapterStore }{store}))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	if _, err := bare.Alignment("Gen", 1); !errors.Is(err, ErrAlignmentNotFound) {
+		t.Errorf("expected ErrAlignmentNotFound from a store without alignments, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package kjvcorpus
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/canonref/bibleref"
+)
+
+// benchCorpusRoot returns the path to canon/kjv from the project root
+func benchCorpusRoot(b *testing.B) string {
+	b.Helper()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		b.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			b.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+	return filepath.Join(cwd, "canon", "kjv")
+}
+
+func BenchmarkOpen(b *testing.B) {
+	root := benchCorpusRoot(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Open(root); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOpenSnapshot(b *testing.B) {
+	root := benchCorpusRoot(b)
+	corpus, err := Open(root)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := corpus.WriteSnapshot(&buf); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Open(root, WithSnapshot(buf.Bytes())); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoadChapter(b *testing.B) {
+	corpus, err := Open(benchCorpusRoot(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("cold", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			snap := corpus.snap.Load()
+			snap.mu.Lock()
+			clear(snap.chapters)
+			snap.mu.Unlock()
+			if _, err := corpus.loadChapter("Ps", 119); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := corpus.loadChapter("Ps", 119); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkResolve(b *testing.B) {
+	corpus, err := Open(benchCorpusRoot(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, ref := range []string{"Ps 119:176", "Ps 119:89-112", "Ps 119", "Gen 1:1"} {
+		parsed, err := bibleref.Parse(ref, corpus.Books)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(ref, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := corpus.Resolve(parsed); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkResolveCanon resolves every chapter of the canon, the access pattern of full-text
+// search and the site generator
+func BenchmarkResolveCanon(b *testing.B) {
+	corpus, err := Open(benchCorpusRoot(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var refs []*bibleref.BibleRef
+	for osis, book := range corpus.snap.Load().booksByID {
+		for chapter := 1; chapter <= book.Chapters; chapter++ {
+			refs = append(refs, &bibleref.BibleRef{OSIS: osis, Chapter: chapter})
+		}
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, ref := range refs {
+			// Books such as Additions to Esther only carry some of their chapters
+			_, _ = corpus.Resolve(ref)
+		}
+	}
+}
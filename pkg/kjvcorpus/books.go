@@ -0,0 +1,67 @@
+package kjvcorpus
+
+import (
+	"strings"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// compactReplacer removes the characters that book names are written both with and without
+var compactReplacer = strings.NewReplacer(" ", "", "-", "", "'", "")
+
+// compactName is a normalized book name with spaces, hyphens, and apostrophes removed, so
+// "Add Esth", "addesth", and "ADD-ESTH" are the same name
+func compactName(name string) string {
+	return compactReplacer.Replace(bibleref.NormalizeAlias(name))
+}
+
+// addBookVariants adds aliases to the table so that books are found case-insensitively by OSIS
+// code, name, USFM code, or alias, with or without spaces: "1 Macc", "1macc", "I Maccabees", and
+// "1MA" all name 1 Maccabees. Aliases from books.json are never replaced, and a variant that
+// would name two books is left out.
+func addBookVariants(table *bibleref.Table, books []model.BookMetadata) {
+	variants := make(map[string]string)
+	ambiguous := make(map[string]bool)
+	add := func(key, osis string) {
+		if key == "" {
+			return
+		}
+		if other, exists := variants[key]; exists && other != osis {
+			ambiguous[key] = true
+		}
+		variants[key] = osis
+	}
+
+	for _, book := range books {
+		add(bibleref.NormalizeAlias(book.Name), book.OSIS)
+		add(bibleref.NormalizeAlias(book.Abbr), book.OSIS)
+		for _, name := range append([]string{book.OSIS, book.Name, book.Abbr}, book.Aliases...) {
+			add(compactName(name), book.OSIS)
+		}
+	}
+
+	for key, osis := range variants {
+		if _, exists := table.ByAlias[key]; exists || ambiguous[key] {
+			continue
+		}
+		table.ByAlias[key] = osis
+	}
+}
+
+// LookupBook finds a book by OSIS code, name, USFM code, or alias, ignoring case, periods, spaces,
+// hyphens, and apostrophes
+func (c *Corpus) LookupBook(name string) (bibleref.Book, bool) {
+	table := c.Table()
+	if book, exists := table.ByOsis[name]; exists {
+		return book, true
+	}
+	for _, key := range []string{bibleref.NormalizeAlias(name), compactName(name)} {
+		if osis, exists := table.ByAlias[key]; exists {
+			book, exists := table.ByOsis[osis]
+			return book, exists
+		}
+	}
+	return bibleref.Book{}, false
+}
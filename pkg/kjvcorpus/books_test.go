@@ -0,0 +1,100 @@
+package kjvcorpus
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestBookVariants(t *testing.T) {
+	corpus := openCanon(t)
+
+	tests := map[string][]string{
+		"1Macc":   {"1Macc", "1macc", "1 Macc", "I Maccabees", "1MA", "1 maccabees", "1 MACC"},
+		"AddEsth": {"AddEsth", "Add Esth", "addesth", "ADD ESTH", "ESG"},
+		"SgThree": {"SgThree", "Sg Three", "sgthree", "S3Y"},
+		"1Kgs":    {"1kgs", "1 Kgs", "1 Kings", "I Kings", "1KI"},
+		"Song":    {"Song", "SNG"},
+	}
+	for osis, names := range tests {
+		for _, name := range names {
+			book, ok := corpus.LookupBook(name)
+			if !ok || book.OSIS != osis {
+				t.Errorf("LookupBook(%q) = %q, %v; want %q", name, book.OSIS, ok, osis)
+			}
+
+			ref, err := corpus.ParseRef(name + " 1:1")
+			if osis == "AddEsth" {
+				ref, err = corpus.ParseRef(name + " 10:4")
+			}
+			if err != nil || ref.OSIS != osis {
+				t.Errorf("ParseRef(%q) = %+v, %v; want %s", name, ref, err, osis)
+			}
+		}
+	}
+
+	if book, ok := corpus.LookupBook("add-esth"); !ok || book.OSIS != "AddEsth" {
+		t.Errorf("expected LookupBook to ignore hyphens, got %q", book.OSIS)
+	}
+	if _, ok := corpus.LookupBook("Nope"); ok {
+		t.Error("expected an unknown book not to be found")
+	}
+}
+
+func TestBookVariantsAmbiguous(t *testing.T) {
+	books := []model.BookMetadata{
+		{OSIS: "Jud", Abbr: "JDG", Name: "Judges", Chapters: 21, Order: 1},
+		{OSIS: "Jude", Abbr: "JUD", Name: "Jude", Chapters: 1, Order: 2},
+		{OSIS: "A B", Abbr: "AB1", Name: "A B", Chapters: 1, Order: 3},
+		{OSIS: "AB", Abbr: "AB2", Name: "Ab", Chapters: 1, Order: 4},
+	}
+	biblerefBooks := make([]bibleref.Book, len(books))
+	for i, book := range books {
+		biblerefBooks[i] = bibleref.Book{OSIS: book.OSIS, Name: book.Name, Chapters: book.Chapters, Order: book.Order}
+	}
+	table, err := bibleref.NewTable(biblerefBooks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addBookVariants(table, books)
+
+	// The OSIS code "Jud" is kept even though it is also Jude's USFM code
+	if table.ByAlias["jud"] != "Jud" {
+		t.Errorf("expected jud to stay Judges, got %q", table.ByAlias["jud"])
+	}
+	// "ab" is the OSIS code of one book and the compact name of another
+	if table.ByAlias["ab"] != "AB" {
+		t.Errorf("expected ab to stay AB, got %q", table.ByAlias["ab"])
+	}
+	if table.ByAlias["jdg"] != "Jud" || table.ByAlias["ab1"] != "A B" {
+		t.Errorf("expected USFM codes to be added, got %v", table.ByAlias)
+	}
+}
+
+func TestLegacyBookCodes(t *testing.T) {
+	corpus := openCanon(t)
+
+	// The canon lists books by canonical OSIS ID, and the spaced codes of older canons still work
+	if !corpus.HasBook("1Macc") || !corpus.HasBook("1 Macc") {
+		t.Error("expected 1Macc to be found by its canonical ID and its spaced code")
+	}
+	if n, err := corpus.VerseCount("3 John", 1); err != nil || n != 14 {
+		t.Errorf("VerseCount(\"3 John\", 1) = %d, %v; want 14", n, err)
+	}
+	if !corpus.HasChapter("Add Esth", 10) {
+		t.Error("expected Add Esth 10 to exist")
+	}
+	resolved, err := corpus.ResolveRef(Ref{OSIS: "Add Esth", Chapter: 10, Verses: &VerseRange{Start: 4}})
+	if err != nil || resolved.Chapter.OSIS != "AddEsth" {
+		t.Errorf("expected Add Esth 10:4 to resolve from books/AddEsth, got %v", err)
+	}
+
+	// The spaced code is kept as an alias in books.json
+	book, ok := corpus.Table().ByOsis["1Macc"]
+	if !ok || !slices.Contains(book.Aliases, "1 Macc") {
+		t.Errorf("expected 1Macc to keep 1 Macc as an alias, got %v", book.Aliases)
+	}
+}
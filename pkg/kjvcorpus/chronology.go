@@ -0,0 +1,122 @@
+package kjvcorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+// ChronologicalOrder returns a reference to each chapter of the given testaments, or of every
+// book when none are given, in the order of index/chronological.json. Chapters that HasChapter
+// reports missing are skipped. A canon without chronological.json fails with ErrNoChronology.
+func (c *Corpus) ChronologicalOrder(testaments ...testament.Testament) ([]*bibleref.BibleRef, error) {
+	snap := c.snap.Load()
+	chapters, err := snap.loadChronology(c.store)
+	if err != nil {
+		return nil, err
+	}
+
+	include := make(map[string]bool)
+	for _, book := range c.BooksIn(testaments...) {
+		include[book.OSIS] = true
+	}
+
+	var refs []*bibleref.BibleRef
+	for _, key := range chapters {
+		if include[key.osis] && c.HasChapter(key.osis, key.chapter) {
+			refs = append(refs, &bibleref.BibleRef{OSIS: key.osis, Chapter: key.chapter})
+		}
+	}
+	return refs, nil
+}
+
+// ChronologicalChapters iterates over the chapters of ChronologicalOrder, as Chapters does over
+// the canonical order. Iteration stops after the first error, which is yielded with a nil chapter.
+func (c *Corpus) ChronologicalChapters(testaments ...testament.Testament) iter.Seq2[*model.Chapter, error] {
+	return func(yield func(*model.Chapter, error) bool) {
+		refs, err := c.ChronologicalOrder(testaments...)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for _, ref := range refs {
+			_, loaded, err := c.chapter(ref.OSIS, ref.Chapter)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(loaded.Chapter, nil) {
+				return
+			}
+		}
+	}
+}
+
+// loadChronology loads chronological.json into the snapshot on first use, expanded to one key per
+// chapter. Spans naming unknown books or chapters beyond a book's count are rejected.
+func (s *snapshot) loadChronology(store ChapterStore) ([]chapterKey, error) {
+	s.mu.RLock()
+	if s.chrono != nil {
+		s.mu.RUnlock()
+		return s.chrono, nil
+	}
+	s.mu.RUnlock()
+
+	data, err := store.ReadIndex("chronological.json")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, &CorpusError{
+			Kind:  FileError,
+			Err:   ErrNoChronology,
+			Cause: err,
+		}
+	case err != nil:
+		return nil, &CorpusError{
+			Kind: FileError,
+			Err:  fmt.Errorf("failed to read chronological.json: %w", err),
+		}
+	}
+
+	var chronology model.Chronology
+	if err := json.Unmarshal(data, &chronology); err != nil {
+		return nil, &CorpusError{
+			Kind: ParseError,
+			Err:  fmt.Errorf("failed to parse chronological.json: %w", err),
+		}
+	}
+	if chronology.Schema != model.ChronologySchema {
+		return nil, &CorpusError{
+			Kind: ParseError,
+			Err:  fmt.Errorf("unsupported chronological.json schema version %d", chronology.Schema),
+		}
+	}
+
+	chapters := []chapterKey{}
+	for _, span := range chronology.Passages {
+		book, exists := s.booksByID[span.OSIS]
+		if !exists || span.Start < 1 || span.Start > span.End || span.End > book.Chapters {
+			return nil, &CorpusError{
+				Kind: ParseError,
+				Err:  fmt.Errorf("chronological.json lists an invalid span: %s %d-%d", span.OSIS, span.Start, span.End),
+			}
+		}
+		for chapter := span.Start; chapter <= span.End; chapter++ {
+			chapters = append(chapters, chapterKey{osis: span.OSIS, chapter: chapter})
+		}
+	}
+
+	s.mu.Lock()
+	if s.chrono == nil {
+		s.chrono = chapters
+	}
+	chapters = s.chrono
+	s.mu.Unlock()
+	return chapters, nil
+}
@@ -0,0 +1,76 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+func TestChronologicalOrder(t *testing.T) {
+	corpus := openCanon(t)
+
+	refs, err := corpus.ChronologicalOrder()
+	if err != nil {
+		t.Fatalf("ChronologicalOrder failed: %v", err)
+	}
+	// Every chapter of the canon once, with Add Esth contributing only chapter 10
+	if len(refs) != 1355 {
+		t.Errorf("expected 1355 chapters, got %d", len(refs))
+	}
+	seen := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		if seen[ref.String()] {
+			t.Errorf("%s is listed twice", ref)
+		}
+		seen[ref.String()] = true
+	}
+	if refs[0].String() != "Gen 1" || refs[11].String() != "Job 1" {
+		t.Errorf("expected Genesis 1-11 then Job, got %s and %s", refs[0], refs[11])
+	}
+
+	nt, err := corpus.ChronologicalOrder(testament.NT)
+	if err != nil {
+		t.Fatalf("ChronologicalOrder failed: %v", err)
+	}
+	if len(nt) != 260 || nt[0].OSIS != "Matt" || nt[len(nt)-1].OSIS != "Rev" {
+		t.Errorf("expected 260 New Testament chapters from Matt to Rev, got %d", len(nt))
+	}
+
+	count := 0
+	for chapter, err := range corpus.ChronologicalChapters(testament.NT) {
+		if err != nil {
+			t.Fatalf("ChronologicalChapters failed: %v", err)
+		}
+		if chapter.OSIS != nt[count].OSIS || chapter.Chapter != nt[count].Chapter {
+			t.Fatalf("chapter %d: expected %s, got %s %d", count, nt[count], chapter.OSIS, chapter.Chapter)
+		}
+		count++
+	}
+	if count != len(nt) {
+		t.Errorf("expected %d chapters, got %d", len(nt), count)
+	}
+
+	books := `{"schema": 1, "work": "KJV", "books": [{"osis": "Ruth", "abbr": "RUT", "name": "Ruth", "aliases": ["Ruth"], "testament": "OT", "order": 8, "chapters": 4}]}`
+	bare, err := Open("", WithStore(NewFSStore(fstest.MapFS{
+		"index/books.json": &fstest.MapFile{Data: []byte(books)},
+	})))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	if _, err := bare.ChronologicalOrder(); !errors.Is(err, ErrNoChronology) {
+		t.Errorf("expected ErrNoChronology without chronological.json, got %v", err)
+	}
+
+	invalid, err := Open("", WithStore(NewFSStore(fstest.MapFS{
+		"index/books.json":         &fstest.MapFile{Data: []byte(books)},
+		"index/chronological.json": &fstest.MapFile{Data: []byte(`{"schema": 1, "passages": [{"osis": "Ruth", "start": 1, "end": 5}]}`)},
+	})))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	if _, err := invalid.ChronologicalOrder(); err == nil {
+		t.Error("expected a span past the last chapter to be rejected")
+	}
+}
@@ -0,0 +1,294 @@
+package kjvcorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// editionKey identifies a cached edition layer
+type editionKey struct {
+	edition string
+	chapterKey
+}
+
+// isCanonEdition reports whether edition names the canon's own text
+func isCanonEdition(edition string) bool {
+	return edition == "" || edition == model.EditionBlayney
+}
+
+// Edition returns a chapter's layer in another edition of the text from its editions/ file. The
+// layer is checked against the chapter when first read, so a returned layer has exactly the
+// chapter's verses. Chapters without a layer in that edition return ErrEditionNotFound.
+func (c *Corpus) Edition(edition, osis string, chapter int) (*model.Edition, error) {
+	snap := c.snap.Load()
+	osis = snap.bookID(osis)
+	if _, err := snap.checkChapter(osis, chapter); err != nil {
+		return nil, err
+	}
+	return snap.loadEdition(c.store, edition, osis, chapter)
+}
+
+// applyEdition replaces the text of the resolved verses with their text in edition. The verses are
+// copied, since they share their backing array with the cached chapter. An edition layer carries
+// plain text only, so each verse gets a single token holding it.
+func (c *Corpus) applyEdition(resolved *Resolved, edition string) error {
+	layer, err := c.Edition(edition, resolved.Chapter.OSIS, resolved.Chapter.Chapter)
+	if err != nil {
+		return err
+	}
+	plain := make(map[int]string, len(layer.Verses))
+	for _, verse := range layer.Verses {
+		plain[verse.V] = verse.Plain
+	}
+
+	verses := make([]model.Verse, len(resolved.Verses))
+	for i, verse := range resolved.Verses {
+		verses[i] = model.Verse{V: verse.V, Plain: plain[verse.V], Tokens: []model.Token{{Text: plain[verse.V]}}}
+	}
+	resolved.Verses = verses
+	resolved.Edition = edition
+	return nil
+}
+
+// loadEdition reads, parses, and validates an edition layer into the snapshot on first use
+func (s *snapshot) loadEdition(store ChapterStore, edition, osis string, chapter int) (*model.Edition, error) {
+	key := editionKey{edition: edition, chapterKey: chapterKey{osis: osis, chapter: chapter}}
+	s.mu.RLock()
+	if layer, exists := s.editions[key]; exists {
+		s.mu.RUnlock()
+		return layer, nil
+	}
+	s.mu.RUnlock()
+
+	editionPath := EditionPath(edition, osis, chapter)
+	var data []byte
+	err := fs.ErrNotExist
+	if editions, ok := store.(EditionStore); ok {
+		data, err = editions.ReadEdition(edition, osis, chapter)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("failed to read edition file: %s", editionPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			msg = fmt.Sprintf("no %s edition of %s %d", edition, osis, chapter)
+		}
+		return nil, &CorpusError{
+			Kind:    FileError,
+			Message: &msg,
+			Err:     ErrEditionNotFound,
+			Cause:   err,
+		}
+	}
+
+	layer, err := ParseEdition(data)
+	if err != nil {
+		msg := fmt.Sprintf("failed to parse edition file: %s", editionPath)
+		return nil, &CorpusError{
+			Kind:    ParseError,
+			Message: &msg,
+			Err:     err,
+		}
+	}
+
+	loaded, err := s.loadChapter(store, osis, chapter)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateEdition(loaded.Chapter, layer); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if existing, exists := s.editions[key]; exists {
+		layer = existing
+	} else {
+		s.editions[key] = layer
+	}
+	s.mu.Unlock()
+	return layer, nil
+}
+
+// ParseEdition parses an edition layer, rejecting schema versions other than the current one
+func ParseEdition(data []byte) (*model.Edition, error) {
+	var layer model.Edition
+	if err := json.Unmarshal(data, &layer); err != nil {
+		return nil, fmt.Errorf("JSON unmarshal failed: %w", err)
+	}
+	if layer.Schema != model.EditionSchema {
+		return nil, fmt.Errorf("unsupported edition schema version %d", layer.Schema)
+	}
+	return &layer, nil
+}
+
+// ValidateEdition checks an edition layer against the chapter it belongs to: it must name the same
+// book and chapter and give text for exactly the chapter's verses, each once. Every problem found
+// is listed in the returned error, which wraps ErrEditionMismatch.
+func ValidateEdition(chapter *model.Chapter, layer *model.Edition) error {
+	var problems []string
+	if layer.OSIS != chapter.OSIS || layer.Chapter != chapter.Chapter {
+		problems = append(problems, fmt.Sprintf("edition layer is for %s %d", layer.OSIS, layer.Chapter))
+	}
+
+	exists := make(map[int]bool, len(chapter.Verses))
+	for _, verse := range chapter.Verses {
+		exists[verse.V] = true
+	}
+	seen := make(map[int]bool, len(layer.Verses))
+	for _, verse := range layer.Verses {
+		switch {
+		case !exists[verse.V]:
+			problems = append(problems, fmt.Sprintf("verse %d does not exist", verse.V))
+		case seen[verse.V]:
+			problems = append(problems, fmt.Sprintf("verse %d is given twice", verse.V))
+		case strings.TrimSpace(verse.Plain) == "":
+			problems = append(problems, fmt.Sprintf("verse %d has no text", verse.V))
+		}
+		seen[verse.V] = true
+	}
+	for _, verse := range chapter.Verses {
+		if !seen[verse.V] {
+			problems = append(problems, fmt.Sprintf("verse %d is missing", verse.V))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("%s edition of %s %d: %s", layer.Edition, chapter.OSIS, chapter.Chapter, strings.Join(problems, "; "))
+	return &CorpusError{
+		Kind:    ContentError,
+		Message: &msg,
+		Err:     ErrEditionMismatch,
+	}
+}
+
+// DiffOp is what a word diff does with a run of words
+type DiffOp int
+
+const (
+	DiffEqual  DiffOp = iota // in both texts
+	DiffDelete               // only in the first text
+	DiffInsert               // only in the second text
+)
+
+// WordChange is a run of words a word diff keeps, removes, or adds
+type WordChange struct {
+	Op   DiffOp
+	Text string
+}
+
+// VerseDiff is a verse whose words differ between two editions
+type VerseDiff struct {
+	V       int
+	From    string // the verse in the first edition
+	To      string // the verse in the second edition
+	Changes []WordChange
+}
+
+// String marks the words only in the first edition as [-...-] and those only in the second as
+// {+...+}, such as "And God [-said,-]{+sayd,+} Let there be light"
+func (d VerseDiff) String() string {
+	parts := make([]string, 0, len(d.Changes))
+	for i, change := range d.Changes {
+		switch change.Op {
+		case DiffDelete:
+			parts = append(parts, "[-"+change.Text+"-]")
+		case DiffInsert:
+			// A replacement reads as one unit, without a space between its halves
+			if i > 0 && d.Changes[i-1].Op == DiffDelete {
+				parts[len(parts)-1] += "{+" + change.Text + "+}"
+				continue
+			}
+			parts = append(parts, "{+"+change.Text+"+}")
+		default:
+			parts = append(parts, change.Text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// CompareEditions resolves ref in two editions and returns the verses whose words differ, in verse
+// order. Differences in spacing alone are ignored. Either edition may be empty or
+// model.EditionBlayney for the canon's own text.
+func (c *Corpus) CompareEditions(ref Ref, from, to string) ([]VerseDiff, error) {
+	a, err := c.ResolveWith(ref, ResolveOptions{Edition: from})
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.ResolveWith(ref, ResolveOptions{Edition: to})
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []VerseDiff
+	for i := range a.Verses {
+		changes := diffWords(strings.Fields(a.Verses[i].Plain), strings.Fields(b.Verses[i].Plain))
+		if !slices.ContainsFunc(changes, func(change WordChange) bool { return change.Op != DiffEqual }) {
+			continue
+		}
+		diffs = append(diffs, VerseDiff{V: a.Verses[i].V, From: a.Verses[i].Plain, To: b.Verses[i].Plain, Changes: changes})
+	}
+	return diffs, nil
+}
+
+// diffWords finds the longest common subsequence of two word lists and returns the runs of words
+// kept, removed, and added, with a removal always before the addition that replaces it
+func diffWords(a, b []string) []WordChange {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var changes []WordChange
+	var deleted, inserted []string
+	flush := func() {
+		if len(deleted) > 0 {
+			changes = append(changes, WordChange{Op: DiffDelete, Text: strings.Join(deleted, " ")})
+		}
+		if len(inserted) > 0 {
+			changes = append(changes, WordChange{Op: DiffInsert, Text: strings.Join(inserted, " ")})
+		}
+		deleted, inserted = nil, nil
+	}
+	emit := func(word string) {
+		flush()
+		if n := len(changes); n > 0 && changes[n-1].Op == DiffEqual {
+			changes[n-1].Text += " " + word
+			return
+		}
+		changes = append(changes, WordChange{Op: DiffEqual, Text: word})
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			emit(a[i])
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			deleted = append(deleted, a[i])
+			i++
+		default:
+			inserted = append(inserted, b[j])
+			j++
+		}
+	}
+	flush()
+	return changes
+}
@@ -0,0 +1,105 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestEdition(t *testing.T) {
+	books := `{"schema": 1, "work": "KJV", "books": [{"osis": "Ps", "abbr": "PSA", "name": "Psalms", "aliases": ["Psalm"], "testament": "OT", "order": 1, "chapters": 150}]}`
+	chapter := `{"schema": 1, "work": "KJV", "osis": "Ps", "abbr": "PSA", "chapter": 117, "verses": [
+		{"v": 1, "plain": "O praise the LORD, all ye nations: praise him, all ye people.", "tokens": [{"t": "O praise the "}, {"nd": "LORD"}, {"t": ", all ye nations: praise him, all ye people."}]},
+		{"v": 2, "plain": "For his merciful kindness is great toward us: and the truth of the LORD endureth for ever. Praise ye the LORD.", "tokens": [{"t": "For his merciful kindness is great toward us: and the truth of the LORD endureth for ever. Praise ye the LORD."}]}
+	]}`
+	layer := `{"schema": 1, "edition": "1611", "osis": "Ps", "chapter": 117, "source": "test", "verses": [
+		{"v": 1, "plain": "O praise the LORD, all yee nations: praise him, all yee people."},
+		{"v": 2, "plain": "For his mercifull kindnesse is great toward vs: and the trueth of the LORD endureth for euer. Praise ye the LORD."}
+	]}`
+	store := NewFSStore(fstest.MapFS{
+		"index/books.json":            &fstest.MapFile{Data: []byte(books)},
+		"books/Ps/ch117.json":         &fstest.MapFile{Data: []byte(chapter)},
+		"books/Ps/ch118.json":         &fstest.MapFile{Data: []byte(`{"schema": 1, "osis": "Ps", "chapter": 118, "verses": [{"v": 1, "tokens": [{"t": "O give thanks"}]}, {"v": 2, "tokens": [{"t": "Let Israel now say"}]}]}`)},
+		"editions/1611/Ps/ch117.json": &fstest.MapFile{Data: []byte(layer)},
+		"editions/1611/Ps/ch118.json": &fstest.MapFile{Data: []byte(`{"schema": 1, "edition": "1611", "osis": "Ps", "chapter": 118, "verses": [{"v": 1, "plain": "O giue thankes"}]}`)},
+	})
+	corpus, err := Open("", WithStore(store))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+
+	ref := Ref{OSIS: "Ps", Chapter: 117, Verses: &VerseRange{Start: 2}}
+	resolved, err := corpus.ResolveWith(ref, ResolveOptions{Edition: model.Edition1611})
+	if err != nil {
+		t.Fatalf("ResolveWith failed: %v", err)
+	}
+	if len(resolved.Verses) != 1 || resolved.Verses[0].Plain[:20] != "For his mercifull ki" {
+		t.Errorf("expected the 1611 text of Ps 117:2, got %+v", resolved.Verses)
+	}
+	if got := resolved.Citation(); got != "Psalms 117:2 (KJV 1611)" {
+		t.Errorf("unexpected citation %q", got)
+	}
+
+	// The canon's own text is untouched by resolving another edition
+	plain, err := corpus.ResolveWith(ref, ResolveOptions{Edition: model.EditionBlayney})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Verses[0].Plain[:20] != "For his merciful kin" || plain.Edition != "" || plain.Citation() != "Psalms 117:2 (KJV)" {
+		t.Errorf("expected the canon's text, got %+v", plain)
+	}
+
+	if _, err := corpus.ResolveWith(Ref{OSIS: "Ps", Chapter: 117}, ResolveOptions{Edition: "1560"}); !errors.Is(err, ErrEditionNotFound) {
+		t.Errorf("expected ErrEditionNotFound for an edition without a layer, got %v", err)
+	}
+	if _, err := corpus.Edition(model.Edition1611, "Ps", 118); !errors.Is(err, ErrEditionMismatch) {
+		t.Errorf("expected ErrEditionMismatch for a layer missing a verse, got %v", err)
+	}
+	bare, err := Open("", WithStore(struct{ ChapterStore }{store}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bare.Edition(model.Edition1611, "Ps", 117); !errors.Is(err, ErrEditionNotFound) {
+		t.Errorf("expected ErrEditionNotFound from a store without editions, got %v", err)
+	}
+
+	diffs, err := corpus.CompareEditions(Ref{OSIS: "Ps", Chapter: 117}, "", model.Edition1611)
+	if err != nil {
+		t.Fatalf("CompareEditions failed: %v", err)
+	}
+	if len(diffs) != 2 {
+		t.Fatalf("expected both verses to differ, got %d", len(diffs))
+	}
+	want := "O praise the LORD, all [-ye-]{+yee+} nations: praise him, all [-ye-]{+yee+} people."
+	if got := diffs[0].String(); got != want {
+		t.Errorf("unexpected diff of Ps 117:1:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestDiffWords(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want []WordChange
+	}{
+		{"and the earth", "and the earth", []WordChange{{DiffEqual, "and the earth"}}},
+		{"the truth of", "the trueth of", []WordChange{{DiffEqual, "the"}, {DiffDelete, "truth"}, {DiffInsert, "trueth"}, {DiffEqual, "of"}}},
+		{"Let there be light", "Let there bee light and", []WordChange{{DiffEqual, "Let there"}, {DiffDelete, "be"}, {DiffInsert, "bee"}, {DiffEqual, "light"}, {DiffInsert, "and"}}},
+		{"", "word", []WordChange{{DiffInsert, "word"}}},
+	}
+	for _, tt := range tests {
+		got := diffWords(strings.Fields(tt.a), strings.Fields(tt.b))
+		if len(got) != len(tt.want) {
+			t.Errorf("diffWords(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("diffWords(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+				break
+			}
+		}
+	}
+}
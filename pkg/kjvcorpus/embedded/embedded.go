@@ -0,0 +1,28 @@
+// Package embedded compiles a packed canon into the binary, for applications that can neither
+// read canon/kjv from disk nor fetch it. Only the New Testament is embedded, which adds under
+// 700 KB where the whole canon packs to several megabytes, and only binaries that import this
+// package carry it.
+//
+//	corpus, err := embedded.OpenEmbeddedNT()
+//
+// kjv-nt.zip is packed from canon/kjv by kjvcorpus.PackBooks, from the repository root with
+//
+//	go run ./tools/kjvsrc pack --testament NT pkg/kjvcorpus/embedded/kjv-nt.zip
+//
+// and must be packed again when the canon changes; the package's tests fail until it is.
+package embedded
+
+import (
+	_ "embed"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+//go:embed kjv-nt.zip
+var nt []byte
+
+// OpenEmbeddedNT opens a corpus over the embedded New Testament, as kjvcorpus.OpenPacked does with
+// opts. Books of the Old Testament and the Apocrypha are unknown to it.
+func OpenEmbeddedNT(opts ...kjvcorpus.Option) (*kjvcorpus.Corpus, error) {
+	return kjvcorpus.OpenPacked(nt, opts...)
+}
@@ -0,0 +1,44 @@
+package embedded
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+func TestOpenEmbeddedNT(t *testing.T) {
+	corpus, err := OpenEmbeddedNT(kjvcorpus.WithStrictScan())
+	if err != nil {
+		t.Fatalf("failed to open the embedded New Testament: %v", err)
+	}
+	if books := corpus.BooksIn(); len(books) != 27 {
+		t.Errorf("expected 27 books, got %d", len(books))
+	}
+	resolved, err := corpus.ResolveRef(kjvcorpus.Ref{OSIS: "John", Chapter: 3, Verses: &kjvcorpus.VerseRange{Start: 16}})
+	if err != nil || resolved.Citation() != "John 3:16 (KJV)" {
+		t.Errorf("expected to resolve John 3:16, got %v", err)
+	}
+	if _, err := corpus.ResolveRef(kjvcorpus.Ref{OSIS: "Ps", Chapter: 23}); !errors.Is(err, kjvcorpus.ErrUnknownBook) {
+		t.Errorf("expected Psalms to be unknown, got %v", err)
+	}
+}
+
+// TestEmbeddedNTCurrent checks that kjv-nt.zip was packed from the canon as it is now
+func TestEmbeddedNTCurrent(t *testing.T) {
+	canon, err := filepath.Abs(filepath.Join("..", "..", "..", "canon", "kjv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var packed bytes.Buffer
+	if _, err := kjvcorpus.PackBooks(&packed, os.DirFS(canon), testament.Books(testament.NT)); err != nil {
+		t.Fatalf("failed to pack the New Testament: %v", err)
+	}
+	if !bytes.Equal(packed.Bytes(), nt) {
+		t.Error("kjv-nt.zip is out of date with canon/kjv; run go run ./tools/kjvsrc pack --testament NT pkg/kjvcorpus/embedded/kjv-nt.zip")
+	}
+}
@@ -15,10 +15,29 @@ const (
 )
 
 var (
-	ErrInvalidRoot     = errors.New("invalid corpus root")
-	ErrUnknownBook     = errors.New("unknown book")
-	ErrChapterNotFound = errors.New("chapter not found")
-	ErrVerseOutOfRange = errors.New("verse out of range")
+	ErrInvalidRoot        = errors.New("invalid corpus root")
+	ErrUnknownBook        = errors.New("unknown book")
+	ErrInvalidReference   = errors.New("invalid reference")
+	ErrChapterNotFound    = errors.New("chapter not found")
+	ErrVerseOutOfRange    = errors.New("verse out of range")
+	ErrIntroNotFound      = errors.New("introduction not found")
+	ErrChapterUnavailable = errors.New("chapter unavailable")
+	ErrUnknownScheme      = errors.New("unknown versification scheme")
+	ErrUnmappableRange    = errors.New("verse range spans a versification boundary")
+	ErrUnknownTopic       = errors.New("unknown topic")
+	ErrAlignmentNotFound  = errors.New("alignment not found")
+	ErrAlignmentMismatch  = errors.New("alignment does not match the text")
+	ErrEditionNotFound    = errors.New("edition not found")
+	ErrEditionMismatch    = errors.New("edition does not match the verse structure")
+	ErrUnknownLexiconKey  = errors.New("unknown lexicon key")
+	ErrNoChronology       = errors.New("no chronological order")
+	ErrInvalidSnapshot    = errors.New("invalid index snapshot")
+	ErrUnknownLocale      = errors.New("unknown locale")
+	ErrNoVerseIndex       = errors.New("no verse index")
+	ErrEmptyPhrase        = errors.New("empty phrase")
+	ErrNoRelease          = errors.New("no signed release")
+	ErrSignatureMismatch  = errors.New("release signature does not match")
+	ErrChecksumMismatch   = errors.New("document does not match the release checksums")
 )
 
 type CorpusError struct {
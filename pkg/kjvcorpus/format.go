@@ -0,0 +1,161 @@
+package kjvcorpus
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Preset is a way of joining resolved verses into copyable text
+type Preset string
+
+const (
+	// PresetLines puts each verse on its own line, prefixed with its number, for data files
+	PresetLines Preset = "lines"
+	// PresetParagraph runs verses together as prose, breaking paragraphs where the source marks them
+	PresetParagraph Preset = "paragraph"
+	// PresetPoetry puts each verse on its own line, indenting verses that continue a stanza and
+	// separating stanzas with a blank line
+	PresetPoetry Preset = "poetry"
+)
+
+// Presets lists the formatting presets
+var Presets = []Preset{PresetLines, PresetParagraph, PresetPoetry}
+
+// poetryIndent is the indent of verses that continue a stanza in PresetPoetry
+const poetryIndent = "    "
+
+// VerseHook rewrites a verse's text as it is formatted, so callers can add their own markup,
+// such as highlights or notes, alongside the canon text
+type VerseHook func(verse model.Verse, text string) string
+
+// Format joins the resolved verses into plain text according to preset. Paragraph marks are
+// dropped from the text and used only to place breaks.
+func (r *Resolved) Format(preset Preset) (string, error) {
+	return r.FormatWith(preset, nil)
+}
+
+// FormatWith is Format with each verse's text passed through hook, if not nil, before it is
+// placed. Verse numbers and breaks are not passed to the hook.
+func (r *Resolved) FormatWith(preset Preset, hook VerseHook) (string, error) {
+	if !slices.Contains(Presets, preset) {
+		return "", fmt.Errorf("unknown format preset %q", preset)
+	}
+
+	var b strings.Builder
+	for i, verse := range r.Verses {
+		text, paragraph := verseText(verse)
+		if hook != nil {
+			text = hook(verse, text)
+		}
+
+		switch preset {
+		case PresetLines:
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "%d %s", verse.V, text)
+		case PresetParagraph:
+			switch {
+			case i == 0:
+			case paragraph:
+				b.WriteString("\n\n")
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(text)
+		default: // PresetPoetry
+			switch {
+			case i == 0:
+			case paragraph:
+				b.WriteString("\n\n")
+			default:
+				b.WriteString("\n" + poetryIndent)
+			}
+			b.WriteString(text)
+		}
+	}
+	return b.String(), nil
+}
+
+// Note is a footnote of a formatted passage. Notes are numbered in verse order, and footnotes with
+// the same text, such as a note repeated on several verses, share one number.
+type Note struct {
+	Number int
+	Verses []int // verses the note is anchored to, in order
+	Text   string
+}
+
+// String returns the note as it is listed beneath a passage, such as "[3] firmament: Heb. expansion"
+func (n Note) String() string {
+	return fmt.Sprintf("[%d] %s", n.Number, n.Text)
+}
+
+// Notes numbers the resolved footnotes as FormatWithNotes marks them
+func (r *Resolved) Notes() []Note {
+	footnotes := slices.Clone(r.Footnotes)
+	slices.SortStableFunc(footnotes, func(a, b model.Footnote) int { return a.At.V - b.At.V })
+
+	var notes []Note
+	byText := make(map[string]int) // footnote text -> index into notes
+	for _, fn := range footnotes {
+		text := strings.TrimSpace(fn.Text)
+		if text == "" {
+			continue
+		}
+		i, seen := byText[text]
+		if !seen {
+			i = len(notes)
+			byText[text] = i
+			notes = append(notes, Note{Number: i + 1, Text: text})
+		}
+		if !slices.Contains(notes[i].Verses, fn.At.V) {
+			notes[i].Verses = append(notes[i].Verses, fn.At.V)
+		}
+	}
+	return notes
+}
+
+// FormatWithNotes is FormatWith with each verse followed by the numbers of its notes, such as
+// "[1]", so a quoted passage carries its translators' notes. It returns the notes to list beneath
+// the passage, as FormatNotes does.
+func (r *Resolved) FormatWithNotes(preset Preset, hook VerseHook) (string, []Note, error) {
+	notes := r.Notes()
+	marks := make(map[int]string) // verse number -> note numbers
+	for _, note := range notes {
+		for _, v := range note.Verses {
+			marks[v] += fmt.Sprintf("[%d]", note.Number)
+		}
+	}
+
+	text, err := r.FormatWith(preset, func(verse model.Verse, text string) string {
+		if hook != nil {
+			text = hook(verse, text)
+		}
+		return text + marks[verse.V]
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return text, notes, nil
+}
+
+// FormatNotes lists notes one per line, as they go beneath a passage
+func FormatNotes(notes []Note) string {
+	lines := make([]string, len(notes))
+	for i, note := range notes {
+		lines[i] = note.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// verseText returns a verse's plain text without its paragraph mark, and whether it had one
+func verseText(verse model.Verse) (string, bool) {
+	text := strings.TrimSpace(verse.Plain)
+	if rest, ok := strings.CutPrefix(text, paragraphMark); ok {
+		return strings.TrimSpace(rest), true
+	}
+	return text, false
+}
@@ -0,0 +1,109 @@
+package kjvcorpus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/canonref/bibleref"
+	"github.com/julianstephens/canonref/util"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+func TestFormat(t *testing.T) {
+	corpus := openCanon(t)
+
+	resolved, err := corpus.Resolve(&bibleref.BibleRef{OSIS: "John", Chapter: 3, Verse: &util.VerseRange{StartVerse: 15, EndVerse: ptrInt(17)}})
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+
+	tests := []struct {
+		preset Preset
+		want   []string // lines, abbreviated to their first words
+	}{
+		{PresetLines, []string{"15 That whosoever", "16 For God", "17 For God sent"}},
+		{PresetParagraph, []string{"That whosoever", "", "For God so loved"}},
+		{PresetPoetry, []string{"That whosoever", "", "For God so loved", poetryIndent + "For God sent"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.preset), func(t *testing.T) {
+			got, err := resolved.Format(tt.preset)
+			if err != nil {
+				t.Fatalf("Format failed: %v", err)
+			}
+			if strings.Contains(got, paragraphMark) {
+				t.Errorf("expected paragraph marks to be dropped, got %q", got)
+			}
+
+			lines := strings.Split(got, "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("expected %d lines, got %d: %q", len(tt.want), len(lines), got)
+			}
+			for i, prefix := range tt.want {
+				if !strings.HasPrefix(lines[i], prefix) || (prefix == "" && lines[i] != "") {
+					t.Errorf("line %d: expected prefix %q, got %q", i+1, prefix, lines[i])
+				}
+			}
+		})
+	}
+
+	if _, err := resolved.Format("html"); err == nil {
+		t.Error("expected an unknown preset to fail")
+	}
+
+	hooked, err := resolved.FormatWith(PresetLines, func(verse model.Verse, text string) string {
+		if verse.V == 16 {
+			return "[" + text + "]"
+		}
+		return text
+	})
+	if err != nil {
+		t.Fatalf("FormatWith failed: %v", err)
+	}
+	if lines := strings.Split(hooked, "\n"); !strings.HasPrefix(lines[1], "16 [For God") || !strings.HasSuffix(lines[1], "life.]") {
+		t.Errorf("expected the hook to wrap verse 16 after its number, got %q", lines[1])
+	}
+}
+
+func TestFormatWithNotes(t *testing.T) {
+	corpus := openCanon(t)
+
+	ref, err := corpus.ParseRef("1 Kgs 6:29-32")
+	if err != nil {
+		t.Fatalf("ParseRef failed: %v", err)
+	}
+	resolved, err := corpus.ResolveRef(ref)
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+
+	// The note repeated on verses 29 and 32 is listed once
+	text, notes, err := resolved.FormatWithNotes(PresetLines, nil)
+	if err != nil {
+		t.Fatalf("FormatWithNotes failed: %v", err)
+	}
+	lines := strings.Split(text, "\n")
+	for i, suffix := range []string{"[1]", "", "[2]", "[1][3]"} {
+		if !strings.HasSuffix(lines[i], "."+suffix) && !strings.HasSuffix(lines[i], ":"+suffix) {
+			t.Errorf("line %d: expected note marks %q, got %q", i, suffix, lines[i])
+		}
+	}
+	want := "[1] open flowers: Heb. openings of flowers\n[2] a fifth…: or, fivesquare\n[3] two…: or, leaves of the doors"
+	if got := FormatNotes(notes); got != want {
+		t.Errorf("expected notes:\n%s\ngot:\n%s", want, got)
+	}
+	if len(notes) != 3 || len(notes[0].Verses) != 2 || notes[0].Verses[1] != 32 {
+		t.Errorf("unexpected notes: %+v", notes)
+	}
+
+	// A hook runs before the marks are added
+	text, _, err = resolved.FormatWithNotes(PresetParagraph, func(_ model.Verse, text string) string { return "«" + text + "»" })
+	if err != nil {
+		t.Fatalf("FormatWithNotes failed: %v", err)
+	}
+	if !strings.HasPrefix(text, "«") || !strings.Contains(text, "»[1]") {
+		t.Errorf("expected the hook's text followed by the marks, got %q", text)
+	}
+}
@@ -0,0 +1,235 @@
+// Package httpstore provides a read-through kjvcorpus.ChapterStore that fetches documents from a
+// remote corpus server and caches them on disk.
+//
+// The server must expose the canon layout (index/books.json, books/{OSIS}/chNN.json, ...) beneath
+// a base URL, which kjvsrc serve and any static file host of canon/kjv do. Cached documents are revalidated with
+// ETag (If-None-Match) or Last-Modified (If-Modified-Since), and served from the cache when the
+// server cannot be reached.
+//
+//	store, err := httpstore.New("https://example.org/kjv/", "/var/cache/kjv")
+//	corpus, err := kjvcorpus.Open("", kjvcorpus.WithStore(store))
+package httpstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Store fetches canonical documents over HTTP with an on-disk cache
+type Store struct {
+	base     *url.URL
+	cacheDir string
+	client   *http.Client
+	maxAge   time.Duration
+}
+
+// Option configures a Store
+type Option func(*Store)
+
+// WithHTTPClient sets the client used for requests (default: a client with a 30s timeout)
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Store) {
+		s.client = client
+	}
+}
+
+// WithMaxAge serves cached documents younger than maxAge without revalidating them
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(s *Store) {
+		s.maxAge = maxAge
+	}
+}
+
+// cacheMeta is stored next to each cached document as {name}.meta
+type cacheMeta struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// New creates a store for the corpus served at baseURL, caching documents in cacheDir
+func New(baseURL, cacheDir string, opts ...Option) (*Store, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid corpus URL: %w", err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("invalid corpus URL %q: scheme must be http or https", baseURL)
+	}
+
+	if err := os.MkdirAll(cacheDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	s := &Store{
+		base:     base,
+		cacheDir: cacheDir,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// ReadIndex fetches index/{name}
+func (s *Store) ReadIndex(name string) ([]byte, error) {
+	return s.get(path.Join("index", name))
+}
+
+// ReadChapter fetches books/{osis}/chNN.json, or chNN.json.zst or chNN.json.gz from a server of a
+// compressed canon
+func (s *Store) ReadChapter(osis string, chapter int) ([]byte, error) {
+	var err error
+	for _, name := range kjvcorpus.ChapterPaths(osis, chapter) {
+		var data []byte
+		if data, err = s.get(name); !errors.Is(err, fs.ErrNotExist) {
+			return data, err
+		}
+	}
+	return nil, err
+}
+
+// ReadObject fetches objects/{hh}/{name} from a server of a canon in the content-addressed layout
+func (s *Store) ReadObject(name string) ([]byte, error) {
+	return s.get(model.ObjectPath(name))
+}
+
+// ReadRelease fetches a release file such as SHA256SUMS from beneath the base URL, for
+// kjvcorpus.WithSignatureVerification
+func (s *Store) ReadRelease(name string) ([]byte, error) {
+	return s.get(name)
+}
+
+// ReadIntro fetches books/{osis}/intro.json
+func (s *Store) ReadIntro(osis string) ([]byte, error) {
+	return s.get(path.Join("books", osis, "intro.json"))
+}
+
+// get returns the document at the slash-separated path rel, from the cache when it is fresh or
+// the server confirms it is unchanged, and from the server otherwise
+func (s *Store) get(rel string) ([]byte, error) {
+	cachePath := filepath.Join(s.cacheDir, filepath.FromSlash(rel))
+	cached, meta, cacheErr := s.readCache(cachePath)
+	if cacheErr == nil && s.maxAge > 0 && time.Since(meta.FetchedAt) < s.maxAge {
+		return cached, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.base.JoinPath(rel).String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", rel, err)
+	}
+	if cacheErr == nil {
+		if meta.ETag != "" {
+			req.Header.Set("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if cacheErr == nil {
+			// Offline: fall back to the last copy we saw
+			return cached, nil
+		}
+		return nil, fmt.Errorf("failed to fetch %s: %w", rel, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			fmt.Printf("Error closing response body: %v\n", err)
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cacheErr == nil:
+		meta.FetchedAt = time.Now().UTC()
+		if err := writeMeta(cachePath, meta); err != nil {
+			return nil, err
+		}
+		return cached, nil
+	case resp.StatusCode == http.StatusNotFound:
+		_ = os.Remove(cachePath)
+		_ = os.Remove(cachePath + ".meta")
+		return nil, fmt.Errorf("%s: %w", rel, fs.ErrNotExist)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to fetch %s: server returned %s", rel, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+
+	meta = cacheMeta{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now().UTC(),
+	}
+	if err := writeCache(cachePath, data, meta); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readCache returns a cached document and its metadata
+func (s *Store) readCache(cachePath string) ([]byte, cacheMeta, error) {
+	var meta cacheMeta
+
+	metaData, err := os.ReadFile(cachePath + ".meta") // nolint: gosec
+	if err != nil {
+		return nil, meta, err
+	}
+	if err := json.Unmarshal(metaData, &meta); err != nil {
+		return nil, meta, err
+	}
+
+	data, err := os.ReadFile(cachePath) // nolint: gosec
+	if err != nil {
+		return nil, meta, err
+	}
+	return data, meta, nil
+}
+
+// writeCache stores a document and its metadata, replacing any previous copy
+func writeCache(cachePath string, data []byte, meta cacheMeta) error {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := writeAtomic(cachePath, data); err != nil {
+		return err
+	}
+	return writeMeta(cachePath, meta)
+}
+
+// writeMeta stores the metadata for a cached document
+func writeMeta(cachePath string, meta cacheMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache metadata: %w", err)
+	}
+	return writeAtomic(cachePath+".meta", data)
+}
+
+// writeAtomic writes data to a temporary file and renames it into place, so concurrent readers
+// never see a partially written file
+func writeAtomic(path string, data []byte) error {
+	if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
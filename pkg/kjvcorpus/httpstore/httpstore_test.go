@@ -0,0 +1,138 @@
+package httpstore
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+// etagServer serves dir with a content-hash ETag and counts full (200) responses
+func etagServer(t *testing.T, dir string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var full atomic.Int32
+	files := http.FileServer(http.Dir(dir))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(r.URL.Path))) // nolint: gosec
+		if err == nil {
+			etag := fmt.Sprintf(`"%x"`, sha256.Sum256(data))
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			full.Add(1)
+		}
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &full
+}
+
+func TestStoreResolveAndRevalidate(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+
+	// Navigate to project root
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	server, full := etagServer(t, filepath.Join(cwd, "canon", "kjv"))
+	cacheDir := t.TempDir()
+
+	resolve := func(s string) string {
+		t.Helper()
+		store, err := New(server.URL, cacheDir)
+		if err != nil {
+			t.Fatalf("failed to create store: %v", err)
+		}
+		corpus, err := kjvcorpus.Open("", kjvcorpus.WithStore(store))
+		if err != nil {
+			t.Fatalf("failed to open corpus: %v", err)
+		}
+		ref, err := bibleref.Parse(s, corpus.Books)
+		if err != nil {
+			t.Fatalf("failed to parse reference: %v", err)
+		}
+		resolved, err := corpus.Resolve(ref)
+		if err != nil {
+			t.Fatalf("failed to resolve %s: %v", s, err)
+		}
+		return resolved.Verses[0].Plain
+	}
+
+	first := resolve("1 Sam 3:10")
+	fetched := full.Load()
+	if fetched == 0 {
+		t.Fatal("expected documents to be fetched from the server")
+	}
+
+	if second := resolve("1 Sam 3:10"); second != first {
+		t.Errorf("expected cached text %q, got %q", first, second)
+	}
+	if full.Load() != fetched {
+		t.Errorf("expected revalidation to return 304 for every document, got %d new full responses", full.Load()-fetched)
+	}
+
+	// Offline: cached documents are still served
+	server.Close()
+	if offline := resolve("1 Sam 3:10"); offline != first {
+		t.Errorf("expected offline text %q, got %q", first, offline)
+	}
+}
+
+func TestStoreNotFound(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "index"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	server, _ := etagServer(t, dir)
+
+	store, err := New(server.URL, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	if _, err := store.ReadIntro("Gen"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+	if _, err := store.ReadChapter("Gen", 1); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+	if _, err := store.ReadRelease("SHA256SUMS"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist, got %v", err)
+	}
+
+	// Without release files, a verified corpus cannot be opened
+	pub := make([]byte, 32)
+	if _, err := kjvcorpus.Open("", kjvcorpus.WithStore(store), kjvcorpus.WithSignatureVerification(pub)); !errors.Is(err, kjvcorpus.ErrNoRelease) {
+		t.Errorf("expected ErrNoRelease, got %v", err)
+	}
+}
+
+func TestNewRejectsInvalidURL(t *testing.T) {
+	if _, err := New("ftp://example.org/kjv", t.TempDir()); err == nil {
+		t.Error("expected error for non-HTTP URL")
+	}
+}
@@ -0,0 +1,126 @@
+package kjvcorpus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// SnapshotSchema is the current schema version of index snapshots written by WriteSnapshot
+const SnapshotSchema = 1
+
+// snapshotMagic starts every index snapshot, so other files are rejected before decoding
+const snapshotMagic = "kjvcorpus-snapshot\n"
+
+// indexSnapshot is the body of an index snapshot: the books table as Open builds it, including the
+// name variants it adds, and the indices Open would otherwise load on first use. It is encoded as
+// compact JSON, whose sorted map keys make snapshots of the same canon identical.
+type indexSnapshot struct {
+	Schema  int               `json:"schema"`
+	Version string            `json:"version"` // index version the snapshot was built from, as the watcher computes it
+	Books   []bibleref.Book   `json:"books"`   // in canonical order
+	Aliases map[string]string `json:"aliases"` // the table's aliases, including the variants addBookVariants adds
+	OSISIDs map[string]string `json:"osis_ids"`
+	Verses  model.VerseIndex  `json:"verses"`
+}
+
+// WithSnapshot builds the index from data written by WriteSnapshot instead of reading and
+// parsing books.json and the other index documents, for fast cold starts. Decoding works on data
+// as given, so it may be a memory-mapped snapshot file. data is not retained after Open, and
+// chapters are still read from the store. A snapshot of a different canon is not detected at
+// Open; with WithWatch the first poll notices the index version differs and reloads. Reload
+// always reads the store.
+func WithSnapshot(data []byte) Option {
+	return func(c *Corpus) {
+		c.snapshotData = data
+	}
+}
+
+// WriteSnapshot writes the corpus index, with verses.json loaded, as a snapshot WithSnapshot can
+// open. Caches of chapters and other documents are not included.
+func (c *Corpus) WriteSnapshot(w io.Writer) error {
+	snap := c.snap.Load()
+	verses, err := snap.loadVerseIndex(c.store)
+	if err != nil {
+		return err
+	}
+
+	body := indexSnapshot{
+		Schema:  SnapshotSchema,
+		Version: snap.version,
+		Books:   make([]bibleref.Book, 0, len(snap.booksByID)),
+		Aliases: snap.books.ByAlias,
+		OSISIDs: snap.osisIDs,
+		Verses:  *verses,
+	}
+	for _, book := range snap.booksByID {
+		body.Books = append(body.Books, *book)
+	}
+	sort.Slice(body.Books, func(i, j int) bool { return body.Books[i].Order < body.Books[j].Order })
+
+	data, err := json.Marshal(&body)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if _, err := w.Write(append([]byte(snapshotMagic), data...)); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// decodeSnapshot builds a snapshot from data written by WriteSnapshot, scanning every chapter
+// first if mode asks for it
+func decodeSnapshot(data []byte, store ChapterStore, mode scanMode) (*snapshot, error) {
+	invalid := func(cause error) error {
+		return &CorpusError{
+			Kind:  ParseError,
+			Err:   ErrInvalidSnapshot,
+			Cause: cause,
+		}
+	}
+
+	rest, ok := bytes.CutPrefix(data, []byte(snapshotMagic))
+	if !ok {
+		return nil, invalid(fmt.Errorf("not an index snapshot"))
+	}
+	var body indexSnapshot
+	if err := json.Unmarshal(rest, &body); err != nil {
+		return nil, invalid(err)
+	}
+	if body.Schema != SnapshotSchema {
+		return nil, invalid(fmt.Errorf("unsupported snapshot schema version %d", body.Schema))
+	}
+
+	s := newSnapshot(body.Version)
+	table := &bibleref.Table{
+		ByOsis:  make(map[string]bibleref.Book, len(body.Books)),
+		ByAlias: body.Aliases,
+	}
+	if table.ByAlias == nil {
+		table.ByAlias = make(map[string]string)
+	}
+	for i := range body.Books {
+		book := &body.Books[i]
+		table.ByOsis[book.OSIS] = *book
+		s.booksByID[book.OSIS] = book
+	}
+	if body.OSISIDs != nil {
+		s.osisIDs = body.OSISIDs
+	}
+	if body.Verses.Books == nil {
+		body.Verses.Books = make(map[string][]int)
+	}
+	s.books = table
+	s.verses = &body.Verses
+
+	if err := s.applyScan(store, mode); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
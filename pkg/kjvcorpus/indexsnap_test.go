@@ -0,0 +1,76 @@
+package kjvcorpus
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"reflect"
+	"testing"
+)
+
+// indexCountingStore counts reads of index documents
+type indexCountingStore struct {
+	ChapterStore
+	reads []string
+}
+
+func (s *indexCountingStore) ReadIndex(name string) ([]byte, error) {
+	s.reads = append(s.reads, name)
+	return s.ChapterStore.ReadIndex(name)
+}
+
+func TestSnapshot(t *testing.T) {
+	corpus := openCanon(t)
+
+	var buf bytes.Buffer
+	if err := corpus.WriteSnapshot(&buf); err != nil {
+		t.Fatalf("WriteSnapshot failed: %v", err)
+	}
+	var again bytes.Buffer
+	if err := corpus.WriteSnapshot(&again); err != nil || !bytes.Equal(buf.Bytes(), again.Bytes()) {
+		t.Errorf("expected snapshots of the same canon to be identical, got %v", err)
+	}
+
+	store := &indexCountingStore{ChapterStore: NewDirStore(corpus.root)}
+	warm, err := Open("", WithStore(store), WithSnapshot(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("failed to open from snapshot: %v", err)
+	}
+	if len(store.reads) != 0 {
+		t.Errorf("expected no index reads at Open, got %v", store.reads)
+	}
+
+	if !reflect.DeepEqual(warm.Table().ByOsis, corpus.Table().ByOsis) || !maps.Equal(warm.Table().ByAlias, corpus.Table().ByAlias) {
+		t.Error("expected the snapshot's books table to match the one built from books.json")
+	}
+	if book, ok := warm.LookupBook("1 macc"); !ok || book.OSIS != "1Macc" {
+		t.Errorf("expected name variants to survive the snapshot, got %v", book)
+	}
+	if last, err := warm.LastVerse("Add Esth", 10); err != nil || last != 13 {
+		t.Errorf("expected LastVerse from the snapshot's verse index, got %d, %v", last, err)
+	}
+	if verses, err := warm.VerseNumbers("AddEsth", 10); err != nil || len(verses) != 10 {
+		t.Errorf("expected the verse map from the snapshot, got %v, %v", verses, err)
+	}
+	if len(store.reads) != 0 {
+		t.Errorf("expected no index reads for verse lookups, got %v", store.reads)
+	}
+	resolved, err := warm.ResolveRef(Ref{OSIS: "John", Chapter: 3, Verses: &VerseRange{Start: 16}})
+	if err != nil || resolved.Reference() != "John 3:16" {
+		t.Errorf("expected to resolve chapters from the store, got %v", err)
+	}
+	if warm.snap.Load().version != corpus.snap.Load().version {
+		t.Error("expected the snapshot to keep the index version for the watcher")
+	}
+
+	for name, data := range map[string][]byte{
+		"empty":          {},
+		"not a snapshot": []byte(`{"schema": 1}`),
+		"truncated":      buf.Bytes()[:len(buf.Bytes())/2],
+		"schema":         []byte(snapshotMagic + `{"schema": 99}`),
+	} {
+		if _, err := Open(corpus.root, WithSnapshot(data)); !errors.Is(err, ErrInvalidSnapshot) {
+			t.Errorf("%s: expected ErrInvalidSnapshot, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package kjvcorpus
+
+import (
+	"iter"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+// Chapters iterates over the chapters of the given testaments, or of every book when none are
+// given, in canonical order. Chapters that HasChapter reports missing, such as those Additions
+// to Esther lacks, are skipped. Iteration stops after the first error, which is yielded with a
+// nil chapter. Chapters are shared with the corpus cache and must not be modified.
+func (c *Corpus) Chapters(testaments ...testament.Testament) iter.Seq2[*model.Chapter, error] {
+	return func(yield func(*model.Chapter, error) bool) {
+		for _, book := range c.BooksIn(testaments...) {
+			for chapter := 1; chapter <= book.Chapters; chapter++ {
+				if !c.HasChapter(book.OSIS, chapter) {
+					continue
+				}
+				_, loaded, err := c.chapter(book.OSIS, chapter)
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				if !yield(loaded.Chapter, nil) {
+					return
+				}
+			}
+		}
+	}
+}
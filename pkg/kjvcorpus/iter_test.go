@@ -0,0 +1,52 @@
+package kjvcorpus
+
+import (
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+func TestChapters(t *testing.T) {
+	corpus := openCanon(t)
+
+	count, last := 0, ""
+	for chapter, err := range corpus.Chapters(testament.NT) {
+		if err != nil {
+			t.Fatalf("Chapters failed: %v", err)
+		}
+		if count == 0 && (chapter.OSIS != "Matt" || chapter.Chapter != 1) {
+			t.Errorf("expected Matt 1 first, got %s %d", chapter.OSIS, chapter.Chapter)
+		}
+		count++
+		last = chapter.OSIS
+	}
+	if count != 260 || last != "Rev" {
+		t.Errorf("expected 260 New Testament chapters ending in Rev, got %d ending in %s", count, last)
+	}
+
+	// Add Esth is numbered from chapter 1 but the source only carries chapter 10
+	var chapters []int
+	for chapter, err := range corpus.Chapters(testament.AP) {
+		if err != nil {
+			t.Fatalf("Chapters failed: %v", err)
+		}
+		if chapter.OSIS == "AddEsth" {
+			chapters = append(chapters, chapter.Chapter)
+		}
+	}
+	if len(chapters) != 1 || chapters[0] != 10 {
+		t.Errorf("expected only AddEsth 10, got %v", chapters)
+	}
+
+	// Breaking out of the loop stops iteration
+	seen := 0
+	for range corpus.Chapters() {
+		seen++
+		if seen == 3 {
+			break
+		}
+	}
+	if seen != 3 {
+		t.Errorf("expected to stop after 3 chapters, saw %d", seen)
+	}
+}
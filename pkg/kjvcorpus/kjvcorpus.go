@@ -1,55 +1,184 @@
 package kjvcorpus
 
 import (
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"path/filepath"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/julianstephens/canonref/bibleref"
-	"github.com/julianstephens/canonref/util"
 
-	utilinternal "github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
 )
 
 type Corpus struct {
-	root      string
-	Books     *bibleref.Table
-	booksByID map[string]*bibleref.Book        // OSIS -> Book from bibleref
-	chapters  map[string]*utilinternal.Chapter // cache of loaded chapters
-	mu        sync.RWMutex
+	root  string
+	store ChapterStore
+	// source is the store given to Open, which store checks documents from with
+	// WithSignatureVerification and which is otherwise store itself
+	source ChapterStore
+	// Books is the table loaded by Open and replaced by Reload. Code that may run concurrently
+	// with Reload should use Table instead.
+	Books *bibleref.Table
+	snap  atomic.Pointer[snapshot]
+
+	scanMode      scanMode
+	snapshotData  []byte // index snapshot given to WithSnapshot, used once by Open
+	locale        string // language of the book names given to WithLocale
+	releaseKey    ed25519.PublicKey
+	verified      *verifiedStore // store with WithSignatureVerification
+	reloadMu      sync.Mutex
+	watchInterval time.Duration
+	onReload      func(error)
+	stopWatch     chan struct{}
+	watchDone     chan struct{}
+	closeOnce     sync.Once
+}
+
+// snapshot is an immutable view of the index with its own chapter and introduction caches.
+// Reload replaces the whole snapshot, so a Resolve that started before a reload finishes against
+// the books and chapters it began with.
+type snapshot struct {
+	books     *bibleref.Table
+	booksByID map[string]*bibleref.Book // OSIS -> Book from bibleref
+	osisIDs   map[string]string         // canonical OSIS ID -> OSIS code in books.json
+	version   string                    // hash of the watched index documents
+	names     map[string]string         // OSIS -> book name in the corpus locale, nil without one
+
+	report      *ScanReport                 // result of the eager scan, if one was requested
+	unavailable map[chapterKey]ChapterIssue // chapters marked unavailable by a lenient scan
+
+	mu       sync.RWMutex
+	chapters map[chapterKey]*loadedChapter   // cache of loaded chapters
+	intros   map[string]*model.BookIntro     // cache of loaded book introductions
+	schemes  *versification                  // versification.json, loaded on first MapRef
+	verses   *model.VerseIndex               // verses.json, loaded on first HasChapter or LastVerse
+	verseIDs *verseIDTable                   // verse IDs numbered from verses.json on first VerseID or RefFromID
+	topics   *model.Topics                   // topics.json, loaded on first Topic or Topics
+	norms    *model.Normalization            // normalization.json, loaded on first Normalization
+	concord  *concordance                    // positions of every word, indexed on first Occurrences
+	lexicon  map[string]model.LexiconEntry   // lexicon.json by normalized key, loaded on first Lexicon
+	chrono   []chapterKey                    // chronological.json expanded to chapters, loaded on first use
+	aligned  map[chapterKey]*model.Alignment // cache of validated alignment sidecars
+	editions map[editionKey]*model.Edition   // cache of validated edition layers
+	objects  *model.ObjectIndex              // objects.json, loaded on the first chapter not in books/
+}
+
+// chapterKey identifies a cached chapter
+type chapterKey struct {
+	osis    string
+	chapter int
+}
+
+// loadedChapter is a cached chapter with its verses and footnotes indexed by verse number
+type loadedChapter struct {
+	*model.Chapter
+	verseAt     map[int]int              // verse number -> index into Verses; nil if verses are out of order
+	footnotesAt map[int][]model.Footnote // verse number -> footnotes anchored to it
+	lastVerse   int                      // highest verse number in the chapter
 }
 
 type Resolved struct {
 	Ref       *bibleref.BibleRef
 	BookName  string
-	Chapter   utilinternal.Chapter
-	Verses    []utilinternal.Verse
-	Footnotes []utilinternal.Footnote
+	Chapter   model.Chapter
+	Verses    []model.Verse
+	Footnotes []model.Footnote
+	Edition   string // edition the verses are taken from, empty for the canon's own text
+	NoAdded   bool   // whether the added words were left out with ResolveOptions.DropAdded
+	// Parts holds the sub-part the reference asked for of its first or last verse, such as "a"
+	// for Gen 1:1a, keyed by verse number. It is nil when the reference asked for whole verses.
+	Parts map[int]string
+}
+
+// ResolveOptions selects the text a ResolveWith call returns
+type ResolveOptions struct {
+	// Edition is the edition to take the verses' text from, such as model.Edition1611. Empty or
+	// model.EditionBlayney returns the canon's own text; another edition without a layer for the
+	// chapter fails with ErrEditionNotFound.
+	Edition string
+	// DropAdded leaves out the added words, which the translators supplied for sense and the
+	// source sets in italics, for comparing the text with the original languages. Each verse's
+	// plain text is rebuilt from its other tokens. Edition layers carry no added words to drop.
+	DropAdded bool
 }
 
 // Open loads the KJV corpus from the canonical root directory
-// root should be the path to canon/kjv containing index/ and books/ subdirectories
-func Open(root string) (*Corpus, error) {
-	// Validate root exists
-	if _, err := os.Stat(root); os.IsNotExist(err) {
-		return nil, &CorpusError{
-			Kind:  FileError,
-			Err:   ErrInvalidRoot,
-			Cause: err,
+// root should be the path to canon/kjv containing index/ and books/ subdirectories.
+// With WithStore, documents are read from the given store and root is ignored.
+func Open(root string, opts ...Option) (*Corpus, error) {
+	c := &Corpus{root: root}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.store == nil {
+		// Validate root exists
+		fsys := os.DirFS(root)
+		if _, err := fs.Stat(fsys, "."); errors.Is(err, fs.ErrNotExist) {
+			return nil, &CorpusError{
+				Kind:  FileError,
+				Err:   ErrInvalidRoot,
+				Cause: err,
+			}
+		}
+		c.store = NewFSStore(fsys)
+	}
+	c.source = c.store
+	if c.releaseKey != nil {
+		r, err := verifyRelease(c.source, c.releaseKey)
+		if err != nil {
+			return nil, &CorpusError{
+				Kind: ContentError,
+				Err:  err,
+			}
 		}
+		c.verified = &verifiedStore{}
+		c.verified.release.Store(r)
+		c.store = c.verified
 	}
 
-	c := &Corpus{
-		root:      root,
-		booksByID: make(map[string]*bibleref.Book),
-		chapters:  make(map[string]*utilinternal.Chapter),
+	var snap *snapshot
+	var err error
+	if c.snapshotData != nil {
+		snap, err = decodeSnapshot(c.snapshotData, c.store, c.scanMode)
+		c.snapshotData = nil
+	} else {
+		snap, err = loadSnapshot(c.store, c.scanMode)
+	}
+	if err == nil && c.locale != "" {
+		err = snap.loadLocale(c.store, c.locale)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.snap.Store(snap)
+	c.Books = snap.books
+
+	if c.watchInterval > 0 {
+		c.startWatch()
 	}
 
+	return c, nil
+}
+
+// loadSnapshot reads books.json from store into a fresh snapshot with empty caches, scanning
+// every chapter first if mode asks for it
+func loadSnapshot(store ChapterStore, mode scanMode) (*snapshot, error) {
 	// Load books.json from internal format
-	booksPath := filepath.Join(root, "index", "books.json")
-	booksData, err := os.ReadFile(booksPath) // nolint: gosec
+	booksData, err := store.ReadIndex("books.json")
 	if err != nil {
 		return nil, &CorpusError{
 			Kind: FileError,
@@ -57,7 +186,7 @@ func Open(root string) (*Corpus, error) {
 		}
 	}
 
-	var booksOutput utilinternal.BooksData
+	var booksOutput model.BooksData
 	if err := json.Unmarshal(booksData, &booksOutput); err != nil {
 		return nil, &CorpusError{
 			Kind: ParseError,
@@ -65,6 +194,16 @@ func Open(root string) (*Corpus, error) {
 		}
 	}
 
+	version, err := indexVersion(store, booksData)
+	if err != nil {
+		return nil, &CorpusError{
+			Kind: FileError,
+			Err:  fmt.Errorf("failed to read filemap.json: %w", err),
+		}
+	}
+
+	s := newSnapshot(version)
+
 	// Convert internal BookMetadata to bibleref.Book
 	biblerefBooks := make([]bibleref.Book, len(booksOutput.Books))
 	for i, book := range booksOutput.Books {
@@ -76,7 +215,8 @@ func Open(root string) (*Corpus, error) {
 			Order:     book.Order,
 			Chapters:  book.Chapters,
 		}
-		c.booksByID[book.OSIS] = &biblerefBooks[i]
+		s.booksByID[book.OSIS] = &biblerefBooks[i]
+		s.osisIDs[model.CanonicalOSIS(book.OSIS)] = book.OSIS
 	}
 
 	// Build bibleref Table
@@ -87,13 +227,100 @@ func Open(root string) (*Corpus, error) {
 			Err:  fmt.Errorf("failed to create bibleref table: %w", err),
 		}
 	}
-	c.Books = table
+	addBookVariants(table, booksOutput.Books)
+	s.books = table
 
-	return c, nil
+	if err := s.applyScan(store, mode); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// newSnapshot creates a snapshot of the given index version with empty tables and caches
+func newSnapshot(version string) *snapshot {
+	return &snapshot{
+		booksByID: make(map[string]*bibleref.Book),
+		osisIDs:   make(map[string]string),
+		version:   version,
+		chapters:  make(map[chapterKey]*loadedChapter),
+		intros:    make(map[string]*model.BookIntro),
+		aligned:   make(map[chapterKey]*model.Alignment),
+		editions:  make(map[editionKey]*model.Edition),
+	}
+}
+
+// applyScan scans every chapter if mode asks for it, failing a strict scan that finds problems
+// and marking the chapters a lenient scan finds missing or corrupt unavailable
+func (s *snapshot) applyScan(store ChapterStore, mode scanMode) error {
+	if mode == scanNone {
+		return nil
+	}
+
+	report, err := s.scan(store)
+	if err != nil {
+		return &CorpusError{
+			Kind: FileError,
+			Err:  err,
+		}
+	}
+	s.report = report
+
+	if mode == scanStrict && !report.OK() {
+		return &CorpusError{
+			Kind: ContentError,
+			Err:  &ScanError{Report: report},
+		}
+	}
+
+	s.unavailable = make(map[chapterKey]ChapterIssue)
+	for _, issue := range append(append([]ChapterIssue{}, report.Missing...), report.Corrupt...) {
+		s.unavailable[chapterKey{osis: issue.OSIS, chapter: issue.Chapter}] = issue
+	}
+	return nil
+}
+
+// Table returns the current books table
+func (c *Corpus) Table() *bibleref.Table {
+	return c.snap.Load().books
 }
 
-// Resolve takes a BibleRef and returns the resolved verses, tokens, and footnotes
+// BooksIn returns the books of the given testaments in canonical order, or every book when none
+// are given. Books are classified by the testament package, falling back to books.json for OSIS
+// codes it does not know.
+func (c *Corpus) BooksIn(testaments ...testament.Testament) []bibleref.Book {
+	var books []bibleref.Book
+	for _, book := range c.Table().ByOsis {
+		t, ok := testament.Of(book.OSIS)
+		if !ok {
+			t = testament.Testament(book.Testament)
+		}
+		if len(testaments) == 0 || slices.Contains(testaments, t) {
+			books = append(books, book)
+		}
+	}
+
+	sort.Slice(books, func(i, j int) bool { return books[i].Order < books[j].Order })
+	return books
+}
+
+// Resolve takes a BibleRef and returns the resolved verses, tokens, and footnotes. It is
+// ResolveRef for callers that parse references with canonref; Resolved.Ref is ref itself.
 func (c *Corpus) Resolve(ref *bibleref.BibleRef) (*Resolved, error) {
+	resolved, err := c.ResolveRef(RefFrom(ref))
+	if err != nil {
+		return nil, err
+	}
+	resolved.Ref = ref
+	return resolved, nil
+}
+
+// ResolveRef takes a Ref and returns the resolved verses, tokens, and footnotes
+func (c *Corpus) ResolveRef(ref Ref) (*Resolved, error) {
+	return c.ResolveWith(ref, ResolveOptions{})
+}
+
+// ResolveWith resolves a Ref as ResolveRef does, with options selecting the text returned
+func (c *Corpus) ResolveWith(ref Ref, opts ResolveOptions) (*Resolved, error) {
 	if ref.OSIS == "" {
 		msg := "no book specified in reference"
 		return nil, &CorpusError{
@@ -103,10 +330,271 @@ func (c *Corpus) Resolve(ref *bibleref.BibleRef) (*Resolved, error) {
 		}
 	}
 
+	// If no chapter specified, use chapter 1
+	chapter := ref.Chapter
+	if chapter == 0 {
+		chapter = 1
+	}
+
+	book, chapterData, err := c.chapter(ref.OSIS, chapter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the start of the verse range against the chapter's last verse, or against its verse
+	// map when its numbering does not start at 1 or has gaps
+	if ref.Verses != nil {
+		verseMap, mapped := c.snap.Load().verseMap(c.store, book.OSIS, chapter)
+		outOfRange := ref.Verses.Start < 1 || ref.Verses.Start > chapterData.lastVerse
+		valid := fmt.Sprintf("1-%d", chapterData.lastVerse)
+		if mapped {
+			outOfRange = !slices.Contains(verseMap, ref.Verses.Start)
+			valid = formatVerseNumbers(verseMap)
+		}
+		if outOfRange {
+			msg := fmt.Sprintf("verse %d out of range for %s %d (%s)", ref.Verses.Start, book.Name, chapter, valid)
+			return nil, &CorpusError{
+				Kind:    RangeError,
+				Message: &msg,
+				Err:     ErrVerseOutOfRange,
+			}
+		}
+	}
+
+	// Extract requested verses
+	verses := c.extractVerses(chapterData, ref.Verses)
+
+	// Collect footnotes relevant to the requested verses
+	footnotes := c.extractFootnotes(chapterData, verses)
+
+	resolved := &Resolved{
+		Ref:       ref.BibleRef(),
+		BookName:  c.snap.Load().displayName(book.OSIS, book.Name),
+		Chapter:   *chapterData.Chapter,
+		Verses:    verses,
+		Footnotes: footnotes,
+		Parts:     verseParts(ref.Verses, verses),
+	}
+	if !isCanonEdition(opts.Edition) {
+		if err := c.applyEdition(resolved, opts.Edition); err != nil {
+			return nil, err
+		}
+	}
+	if opts.DropAdded {
+		resolved.Verses = dropAdded(resolved.Verses)
+		resolved.NoAdded = true
+	}
+	return resolved, nil
+}
+
+// verseParts records the sub-parts a verse range asks for against the verses resolved for it. The
+// end's sub-part is dropped when the range ran past the chapter and its last verse was not resolved.
+func verseParts(vr *VerseRange, verses []model.Verse) map[int]string {
+	if vr == nil || len(verses) == 0 || (vr.StartPart == "" && vr.EndPart == "") {
+		return nil
+	}
+	parts := make(map[int]string, 2)
+	if vr.StartPart != "" {
+		parts[vr.Start] = vr.StartPart
+	}
+	if vr.End != 0 && vr.EndPart != "" && verses[len(verses)-1].V == vr.End {
+		parts[vr.End] = vr.EndPart
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return parts
+}
+
+// VerseCount returns the number of the last verse in a chapter, so callers can validate verse
+// numbers before resolving them
+func (c *Corpus) VerseCount(osis string, chapter int) (int, error) {
+	_, loaded, err := c.chapter(osis, chapter)
+	if err != nil {
+		return 0, err
+	}
+	return loaded.lastVerse, nil
+}
+
+// HasBook reports whether books.json lists a book
+func (c *Corpus) HasBook(osis string) bool {
+	snap := c.snap.Load()
+	_, exists := snap.booksByID[snap.bookID(osis)]
+	return exists
+}
+
+// bookID returns the code books.json lists a book under. Canons ingested before OSIS IDs were
+// normalized list books by spaced codes such as "1 Macc", and later ones by canonical IDs such as
+// "1Macc"; either form finds the book in either canon. Unknown codes are returned unchanged.
+func (s *snapshot) bookID(osis string) string {
+	if _, exists := s.booksByID[osis]; exists {
+		return osis
+	}
+	if id, exists := s.osisIDs[model.CanonicalOSIS(osis)]; exists {
+		return id
+	}
+	return osis
+}
+
+// HasChapter reports whether a chapter exists without reading it. A chapter exists when it is
+// within the book's chapter count, was not marked unavailable by a lenient scan, and, when
+// index/verses.json records the book, was produced by ingest.
+func (c *Corpus) HasChapter(osis string, chapter int) bool {
+	snap := c.snap.Load()
+	osis = snap.bookID(osis)
+	if _, err := snap.checkChapter(osis, chapter); err != nil {
+		return false
+	}
+
+	// Without a readable verse index, books.json is the only authority
+	verses, err := snap.loadVerseIndex(c.store)
+	if err != nil {
+		return true
+	}
+	if _, recorded := verses.Books[osis]; !recorded {
+		return true
+	}
+	_, produced := verses.LastVerse(osis, chapter)
+	return produced
+}
+
+// LastVerse returns the number of the last verse in a chapter from index/verses.json, reading the
+// chapter itself only when the index does not record it. Unlike VerseCount it is cheap enough to
+// call for every reference a parser or linter checks.
+func (c *Corpus) LastVerse(osis string, chapter int) (int, error) {
+	snap := c.snap.Load()
+	osis = snap.bookID(osis)
+	if _, err := snap.checkChapter(osis, chapter); err != nil {
+		return 0, err
+	}
+
+	verses, err := snap.loadVerseIndex(c.store)
+	if err != nil {
+		return 0, err
+	}
+	if last, ok := verses.LastVerse(osis, chapter); ok {
+		return last, nil
+	}
+
+	loaded, err := snap.loadChapter(c.store, osis, chapter)
+	if err != nil {
+		return 0, err
+	}
+	return loaded.lastVerse, nil
+}
+
+// VerseNumbers returns the verse numbers of a chapter in order: its verse map from
+// index/verses.json for chapters such as AddEsth 10, which starts at verse 4, and 1 to the last
+// verse for every other chapter
+func (c *Corpus) VerseNumbers(osis string, chapter int) ([]int, error) {
+	snap := c.snap.Load()
+	osis = snap.bookID(osis)
+	if verses, mapped := snap.verseMap(c.store, osis, chapter); mapped {
+		if _, err := snap.checkChapter(osis, chapter); err != nil {
+			return nil, err
+		}
+		return slices.Clone(verses), nil
+	}
+
+	last, err := c.LastVerse(osis, chapter)
+	if err != nil {
+		return nil, err
+	}
+	verses := make([]int, last)
+	for i := range verses {
+		verses[i] = i + 1
+	}
+	return verses, nil
+}
+
+// verseMap returns a chapter's verse map from verses.json, reporting false when the chapter is
+// numbered from 1 without gaps or the index cannot be read
+func (s *snapshot) verseMap(store ChapterStore, osis string, chapter int) ([]int, bool) {
+	verses, err := s.loadVerseIndex(store)
+	if err != nil {
+		return nil, false
+	}
+	return verses.VerseMap(osis, chapter)
+}
+
+// formatVerseNumbers describes verse numbers as runs, such as "4-13" or "1-3, 5"
+func formatVerseNumbers(verses []int) string {
+	var runs []string
+	for i := 0; i < len(verses); {
+		j := i
+		for j+1 < len(verses) && verses[j+1] == verses[j]+1 {
+			j++
+		}
+		if i == j {
+			runs = append(runs, strconv.Itoa(verses[i]))
+		} else {
+			runs = append(runs, fmt.Sprintf("%d-%d", verses[i], verses[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(runs, ", ")
+}
+
+// loadVerseIndex loads verses.json into the snapshot on first use. A canon without one gets an
+// empty index, so every lookup falls back to reading the chapter.
+func (s *snapshot) loadVerseIndex(store ChapterStore) (*model.VerseIndex, error) {
+	s.mu.RLock()
+	if s.verses != nil {
+		s.mu.RUnlock()
+		return s.verses, nil
+	}
+	s.mu.RUnlock()
+
+	verses := model.NewVerseIndex()
+	data, err := store.ReadIndex("verses.json")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, &CorpusError{
+			Kind: FileError,
+			Err:  fmt.Errorf("failed to read verses.json: %w", err),
+		}
+	default:
+		if verses, err = model.ParseVerseIndex(data); err != nil {
+			return nil, &CorpusError{
+				Kind: ParseError,
+				Err:  fmt.Errorf("failed to parse verses.json: %w", err),
+			}
+		}
+	}
+
+	s.mu.Lock()
+	if s.verses == nil {
+		s.verses = &verses
+	}
+	v := s.verses
+	s.mu.Unlock()
+	return v, nil
+}
+
+// chapter validates a book and chapter number against the current snapshot and loads the chapter
+func (c *Corpus) chapter(osis string, chapter int) (*bibleref.Book, *loadedChapter, error) {
+	snap := c.snap.Load()
+	osis = snap.bookID(osis)
+	book, err := snap.checkChapter(osis, chapter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Load chapter file
+	loaded, err := snap.loadChapter(c.store, osis, chapter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return book, loaded, nil
+}
+
+// checkChapter validates a book and chapter number against books.json and the lenient scan
+func (s *snapshot) checkChapter(osis string, chapter int) (*bibleref.Book, error) {
 	// Get book metadata
-	book, exists := c.booksByID[ref.OSIS]
+	book, exists := s.booksByID[osis]
 	if !exists {
-		msg := fmt.Sprintf("unknown book: %s", ref.OSIS)
+		msg := fmt.Sprintf("unknown book: %s", osis)
 		return nil, &CorpusError{
 			Kind:    RangeError,
 			Message: &msg,
@@ -114,12 +602,6 @@ func (c *Corpus) Resolve(ref *bibleref.BibleRef) (*Resolved, error) {
 		}
 	}
 
-	// If no chapter specified, use chapter 1
-	chapter := ref.Chapter
-	if chapter == 0 {
-		chapter = 1
-	}
-
 	// Validate chapter number
 	if chapter < 1 || chapter > book.Chapters {
 		msg := fmt.Sprintf("chapter %d out of range for %s (1-%d)", chapter, book.Name, book.Chapters)
@@ -130,44 +612,50 @@ func (c *Corpus) Resolve(ref *bibleref.BibleRef) (*Resolved, error) {
 		}
 	}
 
-	// Load chapter file
-	chapterData, err := c.loadChapter(ref.OSIS, chapter)
-	if err != nil {
-		return nil, err
+	// Chapters that failed a lenient scan are not read again
+	if issue, unavailable := s.unavailable[chapterKey{osis: osis, chapter: chapter}]; unavailable {
+		msg := fmt.Sprintf("chapter %d of %s is unavailable", chapter, book.Name)
+		return nil, &CorpusError{
+			Kind:    ContentError,
+			Message: &msg,
+			Err:     ErrChapterUnavailable,
+			Cause:   issue.Err,
+		}
 	}
 
-	// Extract requested verses
-	verses := c.extractVerses(chapterData, ref.Verse)
-
-	// Collect footnotes relevant to the requested verses
-	footnotes := c.extractFootnotes(chapterData, verses)
+	return book, nil
+}
 
-	return &Resolved{
-		Ref:       ref,
-		BookName:  book.Name,
-		Chapter:   *chapterData,
-		Verses:    verses,
-		Footnotes: footnotes,
-	}, nil
+// loadChapter loads a chapter from the store into the current snapshot's cache
+func (c *Corpus) loadChapter(osis string, chapter int) (*loadedChapter, error) {
+	return c.snap.Load().loadChapter(c.store, osis, chapter)
 }
 
-// loadChapter loads a chapter from disk, with caching
-func (c *Corpus) loadChapter(osis string, chapter int) (*utilinternal.Chapter, error) {
-	cacheKey := fmt.Sprintf("%s:%d", osis, chapter)
+// loadChapter loads a chapter from the store, with caching
+func (s *snapshot) loadChapter(store ChapterStore, osis string, chapter int) (*loadedChapter, error) {
+	key := chapterKey{osis: osis, chapter: chapter}
 
 	// Check cache
-	c.mu.RLock()
-	if ch, exists := c.chapters[cacheKey]; exists {
-		c.mu.RUnlock()
+	s.mu.RLock()
+	if ch, exists := s.chapters[key]; exists {
+		s.mu.RUnlock()
 		return ch, nil
 	}
-	c.mu.RUnlock()
+	s.mu.RUnlock()
 
-	// Load from disk
-	chapterPath := filepath.Join(c.root, "books", osis, fmt.Sprintf("ch%02d.json", chapter))
-	data, err := os.ReadFile(chapterPath) // nolint: gosec
+	// Load from the store
+	data, err := s.readChapter(store, osis, chapter)
+	if errors.Is(err, ErrChecksumMismatch) {
+		msg := fmt.Sprintf("chapter file does not match the release: %s", ChapterPath(osis, chapter))
+		return nil, &CorpusError{
+			Kind:    ContentError,
+			Message: &msg,
+			Err:     err,
+			Cause:   err,
+		}
+	}
 	if err != nil {
-		msg := fmt.Sprintf("failed to read chapter file: %s", chapterPath)
+		msg := fmt.Sprintf("failed to read chapter file: %s", ChapterPath(osis, chapter))
 		return nil, &CorpusError{
 			Kind:    FileError,
 			Message: &msg,
@@ -176,9 +664,20 @@ func (c *Corpus) loadChapter(osis string, chapter int) (*utilinternal.Chapter, e
 		}
 	}
 
-	var ch utilinternal.Chapter
+	// Chapters of a compressed canon are recognised by their magic number, whatever the store
+	if data, err = compression.Decompress(data); err != nil {
+		msg := fmt.Sprintf("failed to decompress chapter file: %s", ChapterPath(osis, chapter))
+		return nil, &CorpusError{
+			Kind:    ParseError,
+			Message: &msg,
+			Err:     err,
+			Cause:   err,
+		}
+	}
+
+	var ch model.Chapter
 	if err := json.Unmarshal(data, &ch); err != nil {
-		msg := fmt.Sprintf("failed to parse chapter file: %s", chapterPath)
+		msg := fmt.Sprintf("failed to parse chapter file: %s", ChapterPath(osis, chapter))
 		return nil, &CorpusError{
 			Kind:    ParseError,
 			Message: &msg,
@@ -186,58 +685,153 @@ func (c *Corpus) loadChapter(osis string, chapter int) (*utilinternal.Chapter, e
 			Cause:   err,
 		}
 	}
+	loaded := newLoadedChapter(&ch)
+
+	// Cache it, keeping the first copy if another goroutine loaded the chapter concurrently
+	s.mu.Lock()
+	if existing, exists := s.chapters[key]; exists {
+		loaded = existing
+	} else {
+		s.chapters[key] = loaded
+	}
+	s.mu.Unlock()
+
+	return loaded, nil
+}
 
-	// Cache it
-	c.mu.Lock()
-	c.chapters[cacheKey] = &ch
-	c.mu.Unlock()
+// newLoadedChapter indexes a chapter's verses and footnotes by verse number
+func newLoadedChapter(ch *model.Chapter) *loadedChapter {
+	loaded := &loadedChapter{Chapter: ch}
 
-	return &ch, nil
+	if len(ch.Footnotes) > 0 {
+		loaded.footnotesAt = make(map[int][]model.Footnote)
+		for _, fn := range ch.Footnotes {
+			loaded.footnotesAt[fn.At.V] = append(loaded.footnotesAt[fn.At.V], fn)
+		}
+	}
+
+	for _, verse := range ch.Verses {
+		loaded.lastVerse = max(loaded.lastVerse, verse.V)
+	}
+
+	verseAt := make(map[int]int, len(ch.Verses))
+	for i, verse := range ch.Verses {
+		if i > 0 && verse.V <= ch.Verses[i-1].V {
+			// Range extraction relies on ascending verse numbers; fall back to scanning
+			return loaded
+		}
+		verseAt[verse.V] = i
+	}
+	loaded.verseAt = verseAt
+
+	return loaded
 }
 
-// extractVerses extracts the specific verses requested in the BibleRef
-func (c *Corpus) extractVerses(chapter *utilinternal.Chapter, verseRange *util.VerseRange) []utilinternal.Verse {
+// BookIntro returns the introduction for a book, if the source provided one
+func (c *Corpus) BookIntro(osis string) (*model.BookIntro, error) {
+	snap := c.snap.Load()
+	osis = snap.bookID(osis)
+	if _, exists := snap.booksByID[osis]; !exists {
+		msg := fmt.Sprintf("unknown book: %s", osis)
+		return nil, &CorpusError{
+			Kind:    RangeError,
+			Message: &msg,
+			Err:     ErrUnknownBook,
+		}
+	}
+
+	snap.mu.RLock()
+	if intro, exists := snap.intros[osis]; exists {
+		snap.mu.RUnlock()
+		return intro, nil
+	}
+	snap.mu.RUnlock()
+
+	introPath := path.Join("books", osis, "intro.json")
+	data, err := c.store.ReadIntro(osis)
+	if err != nil {
+		msg := fmt.Sprintf("failed to read introduction file: %s", introPath)
+		return nil, &CorpusError{
+			Kind:    FileError,
+			Message: &msg,
+			Err:     ErrIntroNotFound,
+			Cause:   err,
+		}
+	}
+
+	var intro model.BookIntro
+	if err := json.Unmarshal(data, &intro); err != nil {
+		msg := fmt.Sprintf("failed to parse introduction file: %s", introPath)
+		return nil, &CorpusError{
+			Kind:    ParseError,
+			Message: &msg,
+			Err:     fmt.Errorf("JSON unmarshal failed: %w", err),
+			Cause:   err,
+		}
+	}
+
+	snap.mu.Lock()
+	snap.intros[osis] = &intro
+	snap.mu.Unlock()
+
+	return &intro, nil
+}
+
+// extractVerses extracts the specific verses requested in the Ref
+// The result shares its backing array with the cached chapter, so it must not be modified.
+func (c *Corpus) extractVerses(chapter *loadedChapter, verseRange *VerseRange) []model.Verse {
 	// If no verse range specified, return all verses in the chapter
 	if verseRange == nil {
 		return chapter.Verses
 	}
 
 	// Determine the verse range
-	startVerse := verseRange.StartVerse
-	endVerse := startVerse
-	if verseRange.EndVerse != nil {
-		endVerse = *verseRange.EndVerse
+	startVerse := verseRange.Start
+	endVerse := verseRange.Last()
+
+	if chapter.verseAt == nil {
+		var result []model.Verse
+		for _, verse := range chapter.Verses {
+			if verse.V >= startVerse && verse.V <= endVerse {
+				result = append(result, verse)
+			}
+		}
+		return result
 	}
 
-	// Extract the requested verse range
-	var result []utilinternal.Verse
-	for _, verse := range chapter.Verses {
-		if verse.V >= startVerse && verse.V <= endVerse {
-			result = append(result, verse)
-		}
+	// Verses are in ascending order, so the range is a contiguous slice. Both ends are usually
+	// present in the index; the binary search only handles gaps in the numbering.
+	start, ok := chapter.verseAt[startVerse]
+	if !ok {
+		start = sort.Search(len(chapter.Verses), func(i int) bool { return chapter.Verses[i].V >= startVerse })
+	}
+	end, ok := chapter.verseAt[endVerse]
+	if ok {
+		end++
+	} else {
+		end = sort.Search(len(chapter.Verses), func(i int) bool { return chapter.Verses[i].V > endVerse })
+	}
+	if start >= end {
+		return nil
 	}
 
-	return result
+	return chapter.Verses[start:end:end]
 }
 
-// extractFootnotes extracts footnotes relevant to the given verses
-func (c *Corpus) extractFootnotes(chapter *utilinternal.Chapter, verses []utilinternal.Verse) []utilinternal.Footnote {
-	if chapter.Footnotes == nil {
+// extractFootnotes extracts footnotes relevant to the given verses, in verse order
+func (c *Corpus) extractFootnotes(chapter *loadedChapter, verses []model.Verse) []model.Footnote {
+	if chapter.footnotesAt == nil {
 		return nil
 	}
 
-	// Build a set of relevant verse numbers
-	verseSet := make(map[int]bool)
-	for _, verse := range verses {
-		verseSet[verse.V] = true
+	// A whole chapter carries all of its footnotes
+	if len(verses) == len(chapter.Verses) && chapter.verseAt != nil {
+		return chapter.Footnotes
 	}
 
-	// Collect footnotes for these verses
-	var result []utilinternal.Footnote
-	for _, fn := range chapter.Footnotes {
-		if verseSet[fn.At.V] {
-			result = append(result, fn)
-		}
+	var result []model.Footnote
+	for _, verse := range verses {
+		result = append(result, chapter.footnotesAt[verse.V]...)
 	}
 
 	return result
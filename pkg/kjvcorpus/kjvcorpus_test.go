@@ -1,12 +1,17 @@
 package kjvcorpus
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/julianstephens/canonref/bibleref"
 	"github.com/julianstephens/canonref/util"
+
+	"github.com/julianstephens/kjv-sources/pkg/testament"
 )
 
 func TestOpen(t *testing.T) {
@@ -311,3 +316,321 @@ func TestEdgeCases(t *testing.T) {
 func ptrInt(v int) *int {
 	return &v
 }
+
+func TestBookIntro(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	corpus, err := Open(filepath.Join(cwd, "canon", "kjv"))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+
+	if _, err := corpus.BookIntro("Nope"); !errors.Is(err, ErrUnknownBook) {
+		t.Errorf("expected ErrUnknownBook, got %v", err)
+	}
+
+	// The KJV source ships no chapter 00 files, so no introductions are present
+	if _, err := corpus.BookIntro("Gen"); !errors.Is(err, ErrIntroNotFound) {
+		t.Errorf("expected ErrIntroNotFound, got %v", err)
+	}
+}
+
+func TestVerseIndex(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	corpus, err := Open(filepath.Join(cwd, "canon", "kjv"))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		start, end    int
+		wantVerses    int
+		wantFootnotes []int
+	}{
+		{name: "single verse", start: 20, end: 20, wantVerses: 1, wantFootnotes: []int{20, 20, 20, 20}},
+		{name: "range", start: 28, end: 31, wantVerses: 4, wantFootnotes: []int{28, 29, 29, 30, 31}},
+		{name: "verse without footnotes", start: 1, end: 1, wantVerses: 1},
+		{name: "range past end of chapter", start: 30, end: 40, wantVerses: 2, wantFootnotes: []int{30, 31}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end := tt.end
+			resolved, err := corpus.Resolve(&bibleref.BibleRef{
+				OSIS:    "Gen",
+				Chapter: 1,
+				Verse:   &util.VerseRange{StartVerse: tt.start, EndVerse: &end},
+			})
+			if err != nil {
+				t.Fatalf("failed to resolve: %v", err)
+			}
+
+			if len(resolved.Verses) != tt.wantVerses {
+				t.Errorf("expected %d verses, got %d", tt.wantVerses, len(resolved.Verses))
+			}
+			var gotFootnotes []int
+			for _, fn := range resolved.Footnotes {
+				gotFootnotes = append(gotFootnotes, fn.At.V)
+			}
+			if len(gotFootnotes) != len(tt.wantFootnotes) {
+				t.Fatalf("expected footnotes at %v, got %v", tt.wantFootnotes, gotFootnotes)
+			}
+			for i := range gotFootnotes {
+				if gotFootnotes[i] != tt.wantFootnotes[i] {
+					t.Errorf("expected footnotes at %v, got %v", tt.wantFootnotes, gotFootnotes)
+					break
+				}
+			}
+		})
+	}
+
+	resolved, err := corpus.Resolve(&bibleref.BibleRef{OSIS: "Gen", Chapter: 1})
+	if err != nil {
+		t.Fatalf("failed to resolve Gen 1: %v", err)
+	}
+	if verse, ok := resolved.Chapter.Verse(27); !ok || verse.V != 27 {
+		t.Errorf("expected Chapter.Verse(27) to find verse 27, got %v, %v", verse, ok)
+	}
+	if _, ok := resolved.Chapter.Verse(32); ok {
+		t.Error("expected Chapter.Verse(32) to report a missing verse")
+	}
+}
+
+func TestBooksIn(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	corpus, err := Open(filepath.Join(cwd, "canon", "kjv"))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		testaments []testament.Testament
+		want       int
+		first      string
+		last       string
+	}{
+		{"all", nil, len(corpus.Table().ByOsis), "Gen", "Rev"},
+		{"old testament", []testament.Testament{testament.OT}, 39, "Gen", "Mal"},
+		{"apocrypha", []testament.Testament{testament.AP}, 14, "Tob", "2Esd"},
+		{"new testament", []testament.Testament{testament.NT}, 27, "Matt", "Rev"},
+		{"protocanon", []testament.Testament{testament.OT, testament.NT}, 66, "Gen", "Rev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			books := corpus.BooksIn(tt.testaments...)
+			if len(books) != tt.want {
+				t.Fatalf("expected %d books, got %d", tt.want, len(books))
+			}
+			if books[0].OSIS != tt.first || books[len(books)-1].OSIS != tt.last {
+				t.Errorf("expected %s..%s, got %s..%s", tt.first, tt.last, books[0].OSIS, books[len(books)-1].OSIS)
+			}
+		})
+	}
+}
+
+func TestVerseOutOfRange(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	corpus, err := Open(filepath.Join(cwd, "canon", "kjv"))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		start int
+		end   *int
+	}{
+		{name: "start past last verse", start: 32},
+		{name: "range beyond chapter", start: 40, end: ptrInt(50)},
+		{name: "verse zero", start: 0, end: ptrInt(3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := corpus.Resolve(&bibleref.BibleRef{
+				OSIS:    "Gen",
+				Chapter: 1,
+				Verse:   &util.VerseRange{StartVerse: tt.start, EndVerse: tt.end},
+			})
+			if !errors.Is(err, ErrVerseOutOfRange) {
+				t.Fatalf("expected ErrVerseOutOfRange, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "(1-31)") {
+				t.Errorf("expected the valid range in the error, got %v", err)
+			}
+		})
+	}
+
+	// AddEsth 10 starts at verse 4, as its verse map in verses.json records
+	_, err = corpus.ResolveRef(Ref{OSIS: "AddEsth", Chapter: 10, Verses: &VerseRange{Start: 1}})
+	if !errors.Is(err, ErrVerseOutOfRange) || !strings.Contains(err.Error(), "(4-13)") {
+		t.Errorf("expected AddEsth 10:1 to be out of range of verses 4-13, got %v", err)
+	}
+	resolved, err := corpus.ResolveRef(Ref{OSIS: "AddEsth", Chapter: 10, Verses: &VerseRange{Start: 4, End: 5}})
+	if err != nil || len(resolved.Verses) != 2 || resolved.Verses[0].V != 4 {
+		t.Errorf("expected AddEsth 10:4-5 to resolve two verses, got %v", err)
+	}
+	verses, err := corpus.VerseNumbers("Add Esth", 10)
+	if err != nil || len(verses) != 10 || verses[0] != 4 || verses[9] != 13 {
+		t.Errorf("expected verses 4-13 for AddEsth 10, got %v, %v", verses, err)
+	}
+	if verses, err := corpus.VerseNumbers("Obad", 1); err != nil || len(verses) != 21 || verses[0] != 1 {
+		t.Errorf("expected verses 1-21 for Obad 1, got %v, %v", verses, err)
+	}
+	if got := formatVerseNumbers([]int{1, 2, 3, 5, 7, 8}); got != "1-3, 5, 7-8" {
+		t.Errorf("unexpected verse runs %q", got)
+	}
+
+	counts := []struct {
+		osis    string
+		chapter int
+		want    int
+		err     error
+	}{
+		{"Gen", 1, 31, nil},
+		{"Ps", 119, 176, nil},
+		{"3 John", 1, 14, nil},
+		{"Gen", 51, 0, ErrChapterNotFound},
+		{"Nope", 1, 0, ErrUnknownBook},
+	}
+	for _, tt := range counts {
+		got, err := corpus.VerseCount(tt.osis, tt.chapter)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("VerseCount(%q, %d) = %d, %v; want %d, %v", tt.osis, tt.chapter, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestProbe(t *testing.T) {
+	corpus := openCanon(t)
+
+	if !corpus.HasBook("Gen") || corpus.HasBook("Nope") {
+		t.Error("HasBook did not match books.json")
+	}
+
+	chapters := []struct {
+		osis    string
+		chapter int
+		want    bool
+	}{
+		{"Gen", 1, true},
+		{"Gen", 50, true},
+		{"Gen", 51, false},
+		{"Gen", 0, false},
+		{"Nope", 1, false},
+		{"Add Esth", 1, false}, // numbered in books.json but never produced by ingest
+		{"Add Esth", 10, true},
+	}
+	for _, tt := range chapters {
+		if got := corpus.HasChapter(tt.osis, tt.chapter); got != tt.want {
+			t.Errorf("HasChapter(%q, %d) = %v; want %v", tt.osis, tt.chapter, got, tt.want)
+		}
+	}
+
+	// Every chapter in the index agrees with the chapter file
+	for _, book := range corpus.BooksIn() {
+		for chapter := 1; chapter <= book.Chapters; chapter++ {
+			if !corpus.HasChapter(book.OSIS, chapter) {
+				continue
+			}
+			last, err := corpus.LastVerse(book.OSIS, chapter)
+			if err != nil {
+				t.Fatalf("LastVerse(%q, %d) failed: %v", book.OSIS, chapter, err)
+			}
+			count, err := corpus.VerseCount(book.OSIS, chapter)
+			if err != nil {
+				t.Fatalf("VerseCount(%q, %d) failed: %v", book.OSIS, chapter, err)
+			}
+			if last != count {
+				t.Errorf("%s %d: verses.json has %d, chapter has %d", book.OSIS, chapter, last, count)
+			}
+		}
+	}
+}
+
+func TestLastVerseFromIndex(t *testing.T) {
+	// The store has no chapter files, so answers must come from verses.json
+	store := NewFSStore(fstest.MapFS{
+		"index/books.json":  &fstest.MapFile{Data: []byte(`{"schema": 1, "work": "KJV", "books": [{"osis": "Ruth", "abbr": "RUT", "name": "Ruth", "aliases": ["Ruth"], "testament": "OT", "order": 8, "chapters": 4}]}`)},
+		"index/verses.json": &fstest.MapFile{Data: []byte(`{"schema": 1, "books": {"Ruth": [22, 23, 0]}}`)},
+	})
+	corpus, err := Open("", WithStore(store))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+
+	if last, err := corpus.LastVerse("Ruth", 2); err != nil || last != 23 {
+		t.Errorf("LastVerse(Ruth, 2) = %d, %v; want 23", last, err)
+	}
+	if corpus.HasChapter("Ruth", 3) || corpus.HasChapter("Ruth", 4) {
+		t.Error("expected chapters missing from verses.json not to exist")
+	}
+	if _, err := corpus.LastVerse("Ruth", 3); !errors.Is(err, ErrChapterNotFound) {
+		t.Errorf("expected ErrChapterNotFound reading the unindexed chapter, got %v", err)
+	}
+	if _, err := corpus.LastVerse("Ruth", 5); !errors.Is(err, ErrChapterNotFound) {
+		t.Errorf("expected ErrChapterNotFound past the last chapter, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package kjvcorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Lexicon looks up a Strong's number or KJV word in index/lexicon.json. The key is normalized with
+// model.LexiconKey, so "h07225", "H7225", and the Lemma of an aligned word all find the same entry,
+// and a word with punctuation attached, as in model.Verse.Words, finds its bare form. Keys without
+// an entry, including every key when the canon has no lexicon.json, fail with ErrUnknownLexiconKey.
+func (c *Corpus) Lexicon(key string) (*model.LexiconEntry, error) {
+	entries, err := c.snap.Load().loadLexicon(c.store)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, exists := entries[model.LexiconKey(key)]
+	if !exists {
+		msg := fmt.Sprintf("no lexicon entry for %s", key)
+		return nil, &CorpusError{
+			Kind:    RangeError,
+			Message: &msg,
+			Err:     ErrUnknownLexiconKey,
+		}
+	}
+	return &entry, nil
+}
+
+// LexiconKeys returns the normalized keys of index/lexicon.json, sorted. A canon without
+// lexicon.json has none.
+func (c *Corpus) LexiconKeys() ([]string, error) {
+	entries, err := c.snap.Load().loadLexicon(c.store)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// loadLexicon loads lexicon.json into the snapshot on first use, indexing its entries by
+// normalized key. Two keys that normalize to the same one are rejected rather than one silently
+// hiding the other.
+func (s *snapshot) loadLexicon(store ChapterStore) (map[string]model.LexiconEntry, error) {
+	s.mu.RLock()
+	if s.lexicon != nil {
+		s.mu.RUnlock()
+		return s.lexicon, nil
+	}
+	s.mu.RUnlock()
+
+	entries := make(map[string]model.LexiconEntry)
+	data, err := store.ReadIndex("lexicon.json")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, &CorpusError{
+			Kind: FileError,
+			Err:  fmt.Errorf("failed to read lexicon.json: %w", err),
+		}
+	default:
+		var lexicon model.Lexicon
+		if err := json.Unmarshal(data, &lexicon); err != nil {
+			return nil, &CorpusError{
+				Kind: ParseError,
+				Err:  fmt.Errorf("failed to parse lexicon.json: %w", err),
+			}
+		}
+		if lexicon.Schema != model.LexiconSchema {
+			return nil, &CorpusError{
+				Kind: ParseError,
+				Err:  fmt.Errorf("unsupported lexicon.json schema version %d", lexicon.Schema),
+			}
+		}
+
+		original := make(map[string]string, len(lexicon.Entries))
+		for key, entry := range lexicon.Entries {
+			normalized := model.LexiconKey(key)
+			if other, exists := original[normalized]; exists {
+				return nil, &CorpusError{
+					Kind: ParseError,
+					Err:  fmt.Errorf("lexicon.json keys %q and %q are the same entry %q", min(key, other), max(key, other), normalized),
+				}
+			}
+			original[normalized] = key
+			entries[normalized] = entry
+		}
+	}
+
+	s.mu.Lock()
+	if s.lexicon == nil {
+		s.lexicon = entries
+	}
+	entries = s.lexicon
+	s.mu.Unlock()
+	return entries, nil
+}
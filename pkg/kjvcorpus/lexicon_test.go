@@ -0,0 +1,79 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLexicon(t *testing.T) {
+	books := `{"schema": 1, "work": "KJV", "books": [{"osis": "Ps", "abbr": "PSA", "name": "Psalms", "aliases": ["Psalms"], "testament": "OT", "order": 19, "chapters": 150}]}`
+	lexicon := `{"schema": 1, "source": "test", "entries": {
+		"H7225": {"lemma": "רֵאשִׁית", "transliteration": "rê'shîyth", "pronunciation": "ray-sheeth'", "definition": "the first, in place, time, order or rank"},
+		"g03056": {"lemma": "λόγος", "definition": "something said"},
+		"Selah": {"pronunciation": "seh'-law"}
+	}}`
+	corpus, err := Open("", WithStore(NewFSStore(fstest.MapFS{
+		"index/books.json":   &fstest.MapFile{Data: []byte(books)},
+		"index/lexicon.json": &fstest.MapFile{Data: []byte(lexicon)},
+	})))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+
+	lookups := []struct {
+		key  string
+		want string
+	}{
+		{"H7225", "ray-sheeth'"},
+		{"h07225", "ray-sheeth'"},
+		{"Selah.", "seh'-law"},
+		{"selah", "seh'-law"},
+	}
+	for _, tt := range lookups {
+		entry, err := corpus.Lexicon(tt.key)
+		if err != nil || entry.Pronunciation != tt.want {
+			t.Errorf("Lexicon(%q) = %+v, %v; want pronunciation %q", tt.key, entry, err, tt.want)
+		}
+	}
+	if entry, err := corpus.Lexicon("G3056"); err != nil || entry.Lemma != "λόγος" {
+		t.Errorf("expected a zero-padded key to be normalized, got %+v, %v", entry, err)
+	}
+	if _, err := corpus.Lexicon("H1"); !errors.Is(err, ErrUnknownLexiconKey) {
+		t.Errorf("expected ErrUnknownLexiconKey, got %v", err)
+	}
+
+	keys, err := corpus.LexiconKeys()
+	if err != nil || !slices.Equal(keys, []string{"G3056", "H7225", "selah"}) {
+		t.Errorf("LexiconKeys() = %v, %v", keys, err)
+	}
+
+	// A canon without lexicon.json has no entries
+	bare, err := Open("", WithStore(NewFSStore(fstest.MapFS{
+		"index/books.json": &fstest.MapFile{Data: []byte(books)},
+	})))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	if _, err := bare.Lexicon("H7225"); !errors.Is(err, ErrUnknownLexiconKey) {
+		t.Errorf("expected ErrUnknownLexiconKey without lexicon.json, got %v", err)
+	}
+
+	invalid := map[string]string{
+		"newer schema":  `{"schema": 2, "entries": {}}`,
+		"duplicate key": `{"schema": 1, "entries": {"H7225": {}, "h07225": {}}}`,
+	}
+	for name, data := range invalid {
+		corpus, err := Open("", WithStore(NewFSStore(fstest.MapFS{
+			"index/books.json":   &fstest.MapFile{Data: []byte(books)},
+			"index/lexicon.json": &fstest.MapFile{Data: []byte(data)},
+		})))
+		if err != nil {
+			t.Fatalf("failed to open corpus: %v", err)
+		}
+		if _, err := corpus.Lexicon("H7225"); err == nil || errors.Is(err, ErrUnknownLexiconKey) {
+			t.Errorf("%s: expected lexicon.json to be rejected, got %v", name, err)
+		}
+	}
+}
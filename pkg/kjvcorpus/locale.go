@@ -0,0 +1,81 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// WithLocale displays book names from index/locales/{lang}.json, such as "es" for Spanish, in
+// Resolved.BookName and the references and citations rendered from it. Only the names are
+// localized; the text stays English, and references are still parsed by their English names and
+// aliases. Books the locale does not name keep their English names. Open and Reload fail with
+// ErrUnknownLocale if the canon has no such locale.
+func WithLocale(lang string) Option {
+	return func(c *Corpus) {
+		c.locale = lang
+	}
+}
+
+// Locale returns the language given to WithLocale, or "" when book names are displayed in English
+func (c *Corpus) Locale() string {
+	return c.locale
+}
+
+// BookName returns the display name of the book with the given OSIS code: its name in the corpus
+// locale, or its English name from books.json
+func (c *Corpus) BookName(osis string) (string, bool) {
+	snap := c.snap.Load()
+	book, exists := snap.booksByID[snap.bookID(osis)]
+	if !exists {
+		return "", false
+	}
+	return snap.displayName(book.OSIS, book.Name), true
+}
+
+// displayName returns the localized name of a book, or name when the locale does not name it
+func (s *snapshot) displayName(osis, name string) string {
+	if localized, exists := s.names[osis]; exists {
+		return localized
+	}
+	return name
+}
+
+// loadLocale reads index/locales/{lang}.json into the snapshot's display names. Names of books
+// not in books.json are ignored.
+func (s *snapshot) loadLocale(store ChapterStore, lang string) error {
+	file := "locales/" + lang + ".json"
+	data, err := store.ReadIndex(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		msg := fmt.Sprintf("no locale %s in the canon", lang)
+		return &CorpusError{
+			Kind:    FileError,
+			Message: &msg,
+			Err:     ErrUnknownLocale,
+			Cause:   err,
+		}
+	} else if err != nil {
+		return &CorpusError{
+			Kind: FileError,
+			Err:  fmt.Errorf("failed to read %s: %w", file, err),
+		}
+	}
+
+	locale, err := model.ParseLocale(data)
+	if err != nil {
+		return &CorpusError{
+			Kind: ParseError,
+			Err:  fmt.Errorf("failed to parse %s: %w", file, err),
+		}
+	}
+
+	s.names = make(map[string]string, len(locale.Books))
+	for osis, name := range locale.Books {
+		if id := s.bookID(osis); s.booksByID[id] != nil && name != "" {
+			s.names[id] = name
+		}
+	}
+	return nil
+}
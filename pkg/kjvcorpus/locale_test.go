@@ -0,0 +1,90 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"testing"
+)
+
+// localeStore serves extra index documents, such as locales, over another store
+type localeStore struct {
+	ChapterStore
+	extra map[string][]byte
+}
+
+func (s *localeStore) ReadIndex(name string) ([]byte, error) {
+	if data, exists := s.extra[name]; exists {
+		return data, nil
+	}
+	return s.ChapterStore.ReadIndex(name)
+}
+
+func TestLocale(t *testing.T) {
+	corpus := openCanon(t)
+	if corpus.Locale() != "" {
+		t.Errorf("expected no locale by default, got %q", corpus.Locale())
+	}
+
+	es, err := Open(corpus.root, WithLocale("es"))
+	if err != nil {
+		t.Fatalf("failed to open with locale: %v", err)
+	}
+	if es.Locale() != "es" {
+		t.Errorf("expected locale es, got %q", es.Locale())
+	}
+
+	ref, err := es.ParseRef("John 3:16")
+	if err != nil {
+		t.Fatalf("failed to parse reference: %v", err)
+	}
+	resolved, err := es.ResolveRef(ref)
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if resolved.BookName != "Juan" || resolved.Citation() != "Juan 3:16 (KJV)" {
+		t.Errorf("expected Juan 3:16 (KJV), got %q (%q)", resolved.Citation(), resolved.BookName)
+	}
+	if resolved.Slug() != "john/3/16" {
+		t.Errorf("expected the slug to stay English, got %q", resolved.Slug())
+	}
+	if name, ok := es.BookName("1Cor"); !ok || name != "1 Corintios" {
+		t.Errorf("expected 1 Corintios, got %q, %v", name, ok)
+	}
+	if name, ok := corpus.BookName("1Cor"); !ok || name != "1 Corinthians" {
+		t.Errorf("expected 1 Corinthians without a locale, got %q, %v", name, ok)
+	}
+	if _, ok := es.BookName("Nope"); ok {
+		t.Error("expected an unknown book to have no name")
+	}
+
+	// Books a locale does not name keep their English names
+	store := &localeStore{
+		ChapterStore: NewDirStore(corpus.root),
+		extra: map[string][]byte{
+			"locales/xx.json": []byte(`{"schema": 1, "lang": "xx", "books": {"John": "Jean", "Nope": "Non"}}`),
+		},
+	}
+	partial, err := Open("", WithStore(store), WithLocale("xx"))
+	if err != nil {
+		t.Fatalf("failed to open with a partial locale: %v", err)
+	}
+	if name, _ := partial.BookName("John"); name != "Jean" {
+		t.Errorf("expected Jean, got %q", name)
+	}
+	if name, _ := partial.BookName("Gen"); name != "Genesis" {
+		t.Errorf("expected Genesis to fall back to English, got %q", name)
+	}
+	if err := partial.Reload(); err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+	if name, _ := partial.BookName("John"); name != "Jean" {
+		t.Errorf("expected the locale to survive a reload, got %q", name)
+	}
+
+	if _, err := Open(corpus.root, WithLocale("zz")); !errors.Is(err, ErrUnknownLocale) {
+		t.Errorf("expected ErrUnknownLocale, got %v", err)
+	}
+	store.extra["locales/bad.json"] = []byte(`{"schema": 9, "lang": "bad", "books": {}}`)
+	if _, err := Open("", WithStore(store), WithLocale("bad")); err == nil || errors.Is(err, ErrUnknownLocale) {
+		t.Errorf("expected a parse error for an unsupported schema, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package kjvcorpus
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/julianstephens/canonref/bibleref"
+)
+
+// warmConcurrency is the number of chapters ResolveRefs loads at once before resolving
+const warmConcurrency = 8
+
+// ResolveMulti resolves several references, such as the parts of "John 3:16; Rom 3:23; Eph 2:8-9",
+// returning one Resolved per reference in the same order, each keeping the caller's reference as
+// Resolve does. It fails with the error of the first reference that cannot be resolved.
+func (c *Corpus) ResolveMulti(refs []*bibleref.BibleRef) ([]*Resolved, error) {
+	converted := make([]Ref, len(refs))
+	for i, ref := range refs {
+		converted[i] = RefFrom(ref)
+	}
+	resolved, err := c.ResolveRefs(converted)
+	if err != nil {
+		return nil, err
+	}
+	for i, ref := range refs {
+		resolved[i].Ref = ref
+	}
+	return resolved, nil
+}
+
+// ResolveRefs is ResolveMulti for Refs. Every chapter the references name is loaded before any is
+// resolved, several at a time, so a store that reads over the network is not waited on once per
+// reference. A chapter named twice is read once.
+func (c *Corpus) ResolveRefs(refs []Ref) ([]*Resolved, error) {
+	c.warmChapters(refs)
+
+	resolved := make([]*Resolved, len(refs))
+	for i, ref := range refs {
+		r, err := c.ResolveRef(ref)
+		if err != nil {
+			return nil, fmt.Errorf("reference %d (%s): %w", i+1, ref, err)
+		}
+		resolved[i] = r
+	}
+	return resolved, nil
+}
+
+// warmChapters loads the chapters of the references into the cache. References that name an
+// unknown book or chapter are skipped, and load errors are left for ResolveRef to report.
+func (c *Corpus) warmChapters(refs []Ref) {
+	snap := c.snap.Load()
+	seen := make(map[chapterKey]bool, len(refs))
+	var keys []chapterKey
+	for _, ref := range refs {
+		key := chapterKey{osis: snap.bookID(ref.OSIS), chapter: ref.Chapter}
+		if key.chapter == 0 {
+			key.chapter = 1
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if _, err := snap.checkChapter(key.osis, key.chapter); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) < 2 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, warmConcurrency)
+	for _, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			_, _ = snap.loadChapter(c.store, key.osis, key.chapter)
+		}()
+	}
+	wg.Wait()
+}
+
+// ParseRefs parses a list of references separated by semicolons, such as
+// "John 3:16; Rom 3:23; Eph 2:8-9", as ParseRef parses each. Empty entries are skipped. The first
+// reference that cannot be parsed fails with ErrInvalidReference.
+func (c *Corpus) ParseRefs(s string) ([]Ref, error) {
+	var refs []Ref
+	for _, part := range strings.Split(s, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		ref, err := c.ParseRef(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+	if len(refs) == 0 {
+		msg := fmt.Sprintf("no references in %q", s)
+		return nil, &CorpusError{
+			Kind:    RangeError,
+			Message: &msg,
+			Err:     ErrInvalidReference,
+		}
+	}
+	return refs, nil
+}
@@ -0,0 +1,70 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/canonref/bibleref"
+	"github.com/julianstephens/canonref/util"
+)
+
+func TestResolveMulti(t *testing.T) {
+	corpus := openCanon(t)
+
+	refs := []*bibleref.BibleRef{
+		bibleref.MustParse("John 3:16", corpus.Table()),
+		bibleref.MustParse("Rom 3:23", corpus.Table()),
+		bibleref.MustParse("Eph 2:8-9", corpus.Table()),
+		bibleref.MustParse("John 3:17", corpus.Table()),
+	}
+	resolved, err := corpus.ResolveMulti(refs)
+	if err != nil {
+		t.Fatalf("ResolveMulti failed: %v", err)
+	}
+	want := []string{"John 3:16", "Romans 3:23", "Ephesians 2:8–9", "John 3:17"}
+	if len(resolved) != len(want) {
+		t.Fatalf("expected %d passages, got %d", len(want), len(resolved))
+	}
+	for i, r := range resolved {
+		if r.Reference() != want[i] {
+			t.Errorf("passage %d: got %s, want %s", i, r.Reference(), want[i])
+		}
+		if r.Ref != refs[i] {
+			t.Errorf("passage %d: expected the caller's reference to be kept", i)
+		}
+	}
+
+	// The chapters were loaded once, up front
+	snap := corpus.snap.Load()
+	snap.mu.RLock()
+	for _, key := range []chapterKey{{"John", 3}, {"Rom", 3}, {"Eph", 2}} {
+		if snap.chapters[key] == nil {
+			t.Errorf("expected %s %d to be cached", key.osis, key.chapter)
+		}
+	}
+	snap.mu.RUnlock()
+
+	_, err = corpus.ResolveMulti([]*bibleref.BibleRef{refs[0], {OSIS: "Rom", Chapter: 3, Verse: &util.VerseRange{StartVerse: 99}}})
+	if !errors.Is(err, ErrVerseOutOfRange) || !strings.Contains(err.Error(), "reference 2 (Rom 3:99)") {
+		t.Errorf("expected the second reference to be out of range, got %v", err)
+	}
+}
+
+func TestParseRefs(t *testing.T) {
+	corpus := openCanon(t)
+
+	refs, err := corpus.ParseRefs("John 3:16; Rom 3:23;; Eph 2:8b-9 ;")
+	if err != nil {
+		t.Fatalf("ParseRefs failed: %v", err)
+	}
+	if len(refs) != 3 || refs[0].String() != "John 3:16" || refs[2].String() != "Eph 2:8b–9" {
+		t.Errorf("unexpected refs: %v", refs)
+	}
+
+	for _, s := range []string{"", " ; ", "John 3:16; Nope 1:1"} {
+		if _, err := corpus.ParseRefs(s); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("ParseRefs(%q): expected ErrInvalidReference, got %v", s, err)
+		}
+	}
+}
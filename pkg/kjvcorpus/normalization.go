@@ -0,0 +1,59 @@
+package kjvcorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Normalization returns index/normalization.json, the table mapping early modern English words
+// in the text to modern English, for building a normalize.Normalizer. A canon without
+// normalization.json has an empty table. The table is shared; callers must not modify it.
+func (c *Corpus) Normalization() (*model.Normalization, error) {
+	return c.snap.Load().loadNormalization(c.store)
+}
+
+// loadNormalization loads normalization.json, caching it in the snapshot
+func (s *snapshot) loadNormalization(store ChapterStore) (*model.Normalization, error) {
+	s.mu.RLock()
+	if s.norms != nil {
+		s.mu.RUnlock()
+		return s.norms, nil
+	}
+	s.mu.RUnlock()
+
+	table := &model.Normalization{Schema: model.NormalizationSchema}
+	data, err := store.ReadIndex("normalization.json")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, &CorpusError{
+			Kind: FileError,
+			Err:  fmt.Errorf("failed to read normalization.json: %w", err),
+		}
+	default:
+		if err := json.Unmarshal(data, table); err != nil {
+			return nil, &CorpusError{
+				Kind: ParseError,
+				Err:  fmt.Errorf("failed to parse normalization.json: %w", err),
+			}
+		}
+		if table.Schema != model.NormalizationSchema {
+			return nil, &CorpusError{
+				Kind: ParseError,
+				Err:  fmt.Errorf("unsupported normalization.json schema version %d", table.Schema),
+			}
+		}
+	}
+
+	s.mu.Lock()
+	if s.norms == nil {
+		s.norms = table
+	}
+	table = s.norms
+	s.mu.Unlock()
+	return table, nil
+}
@@ -0,0 +1,18 @@
+package kjvcorpus
+
+import "testing"
+
+func TestNormalization(t *testing.T) {
+	corpus := openCanon(t)
+
+	table, err := corpus.Normalization()
+	if err != nil {
+		t.Fatalf("Normalization failed: %v", err)
+	}
+	if table.Words["thou"] != "you" || table.Spellings["shew"] != "show" || len(table.Keep) == 0 {
+		t.Errorf("unexpected table: %d words, %v spellings, %d kept", len(table.Words), table.Spellings, len(table.Keep))
+	}
+	if again, _ := corpus.Normalization(); again != table {
+		t.Error("expected the table to be cached")
+	}
+}
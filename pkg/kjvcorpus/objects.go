@@ -0,0 +1,60 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// readChapter reads a chapter's file from the store: books/{OSIS}/chNN.json, compressed or not,
+// or else the chapter's object in a canon written in the content-addressed layout
+func (s *snapshot) readChapter(store ChapterStore, osis string, chapter int) ([]byte, error) {
+	data, err := store.ReadChapter(osis, chapter)
+	objects, ok := store.(ObjectStore)
+	if !ok || !errors.Is(err, fs.ErrNotExist) {
+		return data, err
+	}
+
+	index, indexErr := s.loadObjectIndex(store)
+	if indexErr != nil {
+		return nil, indexErr
+	}
+	name, found := index.Object(osis, chapter)
+	if !found {
+		return nil, err
+	}
+	return objects.ReadObject(name)
+}
+
+// loadObjectIndex loads objects.json into the snapshot on first use. A canon without one gets an
+// empty index, as every chapter is then in books/.
+func (s *snapshot) loadObjectIndex(store ChapterStore) (*model.ObjectIndex, error) {
+	s.mu.RLock()
+	if s.objects != nil {
+		s.mu.RUnlock()
+		return s.objects, nil
+	}
+	s.mu.RUnlock()
+
+	index := model.NewObjectIndex("")
+	data, err := store.ReadIndex("objects.json")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read objects.json: %w", err)
+	default:
+		if index, err = model.ParseObjectIndex(data); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	if s.objects == nil {
+		s.objects = &index
+	}
+	objects := s.objects
+	s.mu.Unlock()
+	return objects, nil
+}
@@ -0,0 +1,218 @@
+package kjvcorpus
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PhraseOccurrences is where a phrase occurs in the text
+type PhraseOccurrences struct {
+	Phrase string            `json:"phrase"` // the phrase as matched: its words in lowercase, separated by spaces
+	Total  int               `json:"total"`  // occurrences, counting each one in a verse that has several
+	Verses []VerseOccurrence `json:"verses"` // verses containing the phrase, in canonical order
+	Books  []BookOccurrences `json:"books"`  // occurrences by book, in canonical order, for books containing the phrase
+}
+
+// VerseOccurrence is a verse containing a phrase
+type VerseOccurrence struct {
+	Ref       Ref    `json:"-"`
+	Reference string `json:"reference"` // such as "Psalms 23:1"
+	Slug      string `json:"slug"`      // such as "ps/23/1"
+	Count     int    `json:"count"`     // times the phrase occurs in the verse
+}
+
+// BookOccurrences is how often a phrase occurs in a book
+type BookOccurrences struct {
+	OSIS   string `json:"osis"`
+	Name   string `json:"name"`
+	Verses int    `json:"verses"` // verses of the book containing the phrase
+	Count  int    `json:"count"`  // occurrences in the book
+}
+
+// concordance is a positional index of every word in the text, built on the first Occurrences
+type concordance struct {
+	verses   []concordVerse       // every verse in canonical order
+	postings map[string][]wordPos // lowercase word -> its positions, in text order
+}
+
+// concordVerse is a verse of the concordance
+type concordVerse struct {
+	osis    string
+	chapter int
+	verse   int
+}
+
+// wordPos is a word's place in the text: an index into concordance.verses and the word's
+// position in that verse, counting from 0
+type wordPos struct {
+	verse int32
+	word  int32
+}
+
+func compareWordPos(a, b wordPos) int {
+	if c := cmp.Compare(a.verse, b.verse); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.word, b.word)
+}
+
+// Occurrences finds every verse containing phrase, a run of consecutive words. Words are matched
+// case-insensitively and exactly, without stemming, and a phrase does not continue from one verse
+// into the next; punctuation between the words is ignored, so "the Lord is my shepherd" matches
+// Psalms 23:1. The first call indexes the positions of every word in the canon, which later calls
+// share. A phrase without words fails with ErrEmptyPhrase.
+func (c *Corpus) Occurrences(phrase string) (*PhraseOccurrences, error) {
+	terms := phraseWords(phrase)
+	if len(terms) == 0 {
+		msg := fmt.Sprintf("phrase has no words: %q", phrase)
+		return nil, &CorpusError{
+			Kind:    RangeError,
+			Message: &msg,
+			Err:     ErrEmptyPhrase,
+		}
+	}
+
+	concord, err := c.snap.Load().loadConcordance(c)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PhraseOccurrences{
+		Phrase: strings.Join(terms, " "),
+		Verses: []VerseOccurrence{},
+		Books:  []BookOccurrences{},
+	}
+	for verse, count := range concord.match(terms) {
+		key := concord.verses[verse]
+		ref := Ref{OSIS: key.osis, Chapter: key.chapter, Verses: &VerseRange{Start: key.verse}}
+		name, _ := c.BookName(key.osis)
+		result.Verses = append(result.Verses, VerseOccurrence{
+			Ref:       ref,
+			Reference: fmt.Sprintf("%s %d:%d", name, key.chapter, key.verse),
+			Slug:      ref.Slug(),
+			Count:     count,
+		})
+		result.Total += count
+
+		if n := len(result.Books); n == 0 || result.Books[n-1].OSIS != key.osis {
+			result.Books = append(result.Books, BookOccurrences{OSIS: key.osis, Name: name})
+		}
+		book := &result.Books[len(result.Books)-1]
+		book.Verses++
+		book.Count += count
+	}
+	return result, nil
+}
+
+// match yields the index of each verse containing terms consecutively, in canonical order, with
+// the number of times it does. Only the positions of the rarest term are scanned; the others are
+// looked up by binary search at the offsets the phrase puts them.
+func (cc *concordance) match(terms []string) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		rarest := 0
+		for i, term := range terms {
+			if len(cc.postings[term]) < len(cc.postings[terms[rarest]]) {
+				rarest = i
+			}
+		}
+
+		verse, count := -1, 0
+		for _, pos := range cc.postings[terms[rarest]] {
+			start := pos.word - int32(rarest)
+			if start < 0 || !cc.matchAt(terms, pos.verse, start) {
+				continue
+			}
+			if int(pos.verse) != verse {
+				if count > 0 && !yield(verse, count) {
+					return
+				}
+				verse, count = int(pos.verse), 0
+			}
+			count++
+		}
+		if count > 0 {
+			yield(verse, count)
+		}
+	}
+}
+
+// matchAt reports whether terms occur consecutively in a verse from word position start
+func (cc *concordance) matchAt(terms []string, verse, start int32) bool {
+	for i, term := range terms {
+		if _, found := slices.BinarySearchFunc(cc.postings[term], wordPos{verse: verse, word: start + int32(i)}, compareWordPos); !found {
+			return false
+		}
+	}
+	return true
+}
+
+// loadConcordance indexes the positions of every word in the canon into the snapshot on first use
+func (s *snapshot) loadConcordance(c *Corpus) (*concordance, error) {
+	s.mu.RLock()
+	if s.concord != nil {
+		s.mu.RUnlock()
+		return s.concord, nil
+	}
+	s.mu.RUnlock()
+
+	concord := &concordance{postings: make(map[string][]wordPos)}
+	for chapter, err := range c.Chapters() {
+		if err != nil {
+			return nil, err
+		}
+		for _, verse := range chapter.Verses {
+			at := int32(len(concord.verses))
+			concord.verses = append(concord.verses, concordVerse{osis: chapter.OSIS, chapter: chapter.Chapter, verse: verse.V})
+			for i, word := range phraseWords(verse.Plain) {
+				concord.postings[word] = append(concord.postings[word], wordPos{verse: at, word: int32(i)})
+			}
+		}
+	}
+
+	s.mu.Lock()
+	if s.concord == nil {
+		s.concord = concord
+	}
+	concord = s.concord
+	s.mu.Unlock()
+	return concord, nil
+}
+
+// phraseWords splits text into lowercase words: runs of letters, with apostrophes and hyphens
+// between letters kept, as in "man's" and "Beer-sheba". Curly apostrophes are written straight, so
+// a phrase typed with either matches the text.
+func phraseWords(text string) []string {
+	var words []string
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && (r == '\'' || r == '’' || r == '-') {
+			next, _ := utf8.DecodeRuneInString(text[i+utf8.RuneLen(r):])
+			if unicode.IsLetter(next) {
+				continue
+			}
+		}
+		if start >= 0 {
+			words = append(words, phraseWord(text[start:i]))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, phraseWord(text[start:]))
+	}
+	return words
+}
+
+func phraseWord(word string) string {
+	return strings.ReplaceAll(strings.ToLower(word), "’", "'")
+}
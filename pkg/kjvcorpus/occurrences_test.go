@@ -0,0 +1,60 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOccurrences(t *testing.T) {
+	corpus := openCanon(t)
+
+	found, err := corpus.Occurrences("The LORD is my shepherd;")
+	if err != nil {
+		t.Fatalf("Occurrences failed: %v", err)
+	}
+	if found.Phrase != "the lord is my shepherd" || found.Total != 1 || len(found.Verses) != 1 {
+		t.Fatalf("unexpected occurrences: %+v", found)
+	}
+	if v := found.Verses[0]; v.Reference != "Psalms 23:1" || v.Slug != "ps/23/1" || v.Count != 1 {
+		t.Errorf("unexpected verse: %+v", v)
+	}
+
+	// Punctuation between words is ignored, and the books are listed in canonical order
+	found, err = corpus.Occurrences("holy holy holy")
+	if err != nil {
+		t.Fatalf("Occurrences failed: %v", err)
+	}
+	if len(found.Books) != 2 || found.Books[0].OSIS != "Isa" || found.Books[1].OSIS != "Rev" {
+		t.Errorf("unexpected books: %+v", found.Books)
+	}
+	if found.Verses[0].Ref.String() != "Isa 6:3" {
+		t.Errorf("expected Isa 6:3 first, got %s", found.Verses[0].Ref)
+	}
+
+	// A verse with the phrase twice counts both, and a curly apostrophe matches a straight one
+	found, err = corpus.Occurrences("man's")
+	if err != nil {
+		t.Fatalf("Occurrences failed: %v", err)
+	}
+	if v := found.Verses[0]; v.Reference != "Genesis 8:21" || v.Count != 2 {
+		t.Errorf("unexpected first verse: %+v", v)
+	}
+	if found.Total <= len(found.Verses) {
+		t.Errorf("expected more occurrences than verses, got %d in %d", found.Total, len(found.Verses))
+	}
+	if gen := found.Books[0]; gen.OSIS != "Gen" || gen.Count != gen.Verses+1 {
+		t.Errorf("unexpected Genesis counts: %+v", gen)
+	}
+
+	found, err = corpus.Occurrences("shepherd is my Lord the")
+	if err != nil {
+		t.Fatalf("Occurrences failed: %v", err)
+	}
+	if found.Total != 0 || len(found.Verses) != 0 || len(found.Books) != 0 {
+		t.Errorf("expected no occurrences, got %+v", found)
+	}
+
+	if _, err := corpus.Occurrences(" ;, "); !errors.Is(err, ErrEmptyPhrase) {
+		t.Errorf("expected ErrEmptyPhrase, got %v", err)
+	}
+}
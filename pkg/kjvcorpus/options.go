@@ -0,0 +1,55 @@
+package kjvcorpus
+
+import (
+	"crypto/ed25519"
+	"time"
+)
+
+// Option configures a Corpus opened with Open
+type Option func(*Corpus)
+
+// WithStore backs the corpus with store instead of the canon directory passed to Open
+func WithStore(store ChapterStore) Option {
+	return func(c *Corpus) {
+		c.store = store
+	}
+}
+
+// WithWatch polls the store's index every interval and reloads the corpus when books.json or
+// filemap.json changes. onReload, if not nil, is called with the result of each reload attempt.
+// Call Close to stop watching.
+func WithWatch(interval time.Duration, onReload func(error)) Option {
+	return func(c *Corpus) {
+		c.watchInterval = interval
+		c.onReload = onReload
+	}
+}
+
+// WithStrictScan reads and validates every chapter when the corpus is opened or reloaded, and
+// fails with a *ScanError if any chapter is missing or corrupt
+func WithStrictScan() Option {
+	return func(c *Corpus) {
+		c.scanMode = scanStrict
+	}
+}
+
+// WithLenientScan reads and validates every chapter when the corpus is opened or reloaded, and
+// marks missing or corrupt chapters unavailable instead of failing. Resolve returns
+// ErrChapterUnavailable for them, and ScanReport lists them.
+func WithLenientScan() Option {
+	return func(c *Corpus) {
+		c.scanMode = scanLenient
+	}
+}
+
+// WithSignatureVerification opens the corpus from an extracted release artifact (see package
+// release) only if its SHA256SUMS carries pub's signature and corpus.json matches it, and then
+// rejects every document read that does not match its signed checksum with ErrChecksumMismatch.
+// The corpus of a packed release is read from its canon.zip once that matches. The store must
+// implement ReleaseStore; Open fails with ErrNoRelease without one or without the release files,
+// and with ErrSignatureMismatch when the signature does not match.
+func WithSignatureVerification(pub ed25519.PublicKey) Option {
+	return func(c *Corpus) {
+		c.releaseKey = pub
+	}
+}
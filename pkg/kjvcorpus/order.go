@@ -0,0 +1,93 @@
+package kjvcorpus
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+)
+
+// CompareRefs orders two references canonically, returning -1, 0, or +1 as a sorts before, the
+// same as, or after b. References are ordered by the book's position in books.json, then chapter,
+// then first verse, with a whole verse before its sub-parts, then last verse, so a shorter range
+// sorts first. A reference with no chapter means chapter 1 and one with no verses the whole
+// chapter, which sorts after every range starting at its first verse. Books are matched as
+// Resolve matches them; unknown books sort after every known book, by OSIS code.
+func (c *Corpus) CompareRefs(a, b Ref) int {
+	return c.snap.Load().compareRefs(a, b)
+}
+
+// RefLess reports whether a sorts before b in canonical order, as CompareRefs orders them
+func (c *Corpus) RefLess(a, b Ref) bool {
+	return c.CompareRefs(a, b) < 0
+}
+
+// SortRefs sorts references into canonical order, as CompareRefs orders them. The sort is stable,
+// so equal references keep their order.
+func (c *Corpus) SortRefs(refs []Ref) {
+	slices.SortStableFunc(refs, c.snap.Load().compareRefs)
+}
+
+// compareRefs is CompareRefs against the snapshot's books table
+func (s *snapshot) compareRefs(a, b Ref) int {
+	if n := s.compareBooks(a.OSIS, b.OSIS); n != 0 {
+		return n
+	}
+	if n := cmp.Compare(max(a.Chapter, 1), max(b.Chapter, 1)); n != 0 {
+		return n
+	}
+
+	aStart, aStartPart, aLast, aLastPart := refBounds(a)
+	bStart, bStartPart, bLast, bLastPart := refBounds(b)
+	if n := cmp.Compare(aStart, bStart); n != 0 {
+		return n
+	}
+	if n := strings.Compare(aStartPart, bStartPart); n != 0 {
+		return n
+	}
+	if n := cmp.Compare(aLast, bLast); n != 0 {
+		return n
+	}
+	return comparePartEnds(aLastPart, bLastPart)
+}
+
+// compareBooks orders books by their order in books.json, with unknown books last by OSIS code
+func (s *snapshot) compareBooks(a, b string) int {
+	bookA, knownA := s.booksByID[s.bookID(a)]
+	bookB, knownB := s.booksByID[s.bookID(b)]
+	switch {
+	case knownA && knownB:
+		return cmp.Compare(bookA.Order, bookB.Order)
+	case knownA:
+		return -1
+	case knownB:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// refBounds returns the first and last verse of a reference with their sub-parts. A whole
+// chapter runs from verse 1 past every verse in it.
+func refBounds(ref Ref) (start int, startPart string, last int, lastPart string) {
+	if ref.Verses == nil {
+		return 1, "", math.MaxInt, ""
+	}
+	if ref.Verses.End == 0 {
+		return ref.Verses.Start, ref.Verses.StartPart, ref.Verses.Start, ref.Verses.StartPart
+	}
+	return ref.Verses.Start, ref.Verses.StartPart, ref.Verses.End, ref.Verses.EndPart
+}
+
+// comparePartEnds orders where two ranges end within the same verse: a sub-part ends before the
+// whole verse does, and earlier sub-parts end before later ones
+func comparePartEnds(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+	return strings.Compare(a, b)
+}
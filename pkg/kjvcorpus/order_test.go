@@ -0,0 +1,65 @@
+package kjvcorpus
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSortRefs(t *testing.T) {
+	corpus := openCanon(t)
+
+	// In canonical order: books.json order, with the Apocrypha between the testaments. Ranges from
+	// the same verse sort by where they start, then by where they end.
+	want := []string{
+		"Gen 1:1",
+		"Gen 1:1-2a",
+		"Gen 1:1-2",
+		"Gen 1",
+		"Gen 1:1a",
+		"Gen 1:1b",
+		"Gen 1:2",
+		"Gen 2:1",
+		"Mal 4:6",
+		"Tob 1:1",
+		"Matt 1:1",
+		"John 3:16",
+		"John 3:16-18",
+		"Rev 22:21",
+	}
+	refs := make([]Ref, len(want))
+	for i, s := range want {
+		ref, err := corpus.ParseRef(s)
+		if err != nil {
+			t.Fatalf("ParseRef(%q) failed: %v", s, err)
+		}
+		refs[i] = ref
+	}
+	sorted := append([]Ref{}, refs...)
+	rand.New(rand.NewSource(1)).Shuffle(len(sorted), func(i, j int) { sorted[i], sorted[j] = sorted[j], sorted[i] })
+
+	corpus.SortRefs(sorted)
+	for i := range refs {
+		if sorted[i].String() != refs[i].String() {
+			t.Errorf("position %d: expected %s, got %s", i, refs[i], sorted[i])
+		}
+	}
+
+	for i := 1; i < len(refs); i++ {
+		if !corpus.RefLess(refs[i-1], refs[i]) || corpus.RefLess(refs[i], refs[i-1]) {
+			t.Errorf("expected %s before %s", refs[i-1], refs[i])
+		}
+	}
+	if n := corpus.CompareRefs(refs[0], refs[0]); n != 0 {
+		t.Errorf("expected a reference to equal itself, got %d", n)
+	}
+
+	// Books are matched as Resolve matches them, and unknown books sort last by OSIS code
+	if n := corpus.CompareRefs(Ref{OSIS: "Gen", Chapter: 1}, Ref{OSIS: "Gen"}); n != 0 {
+		t.Errorf("expected no chapter to mean chapter 1, got %d", n)
+	}
+	unknown := []Ref{{OSIS: "Zzz", Chapter: 1}, {OSIS: "Aaa", Chapter: 1}, {OSIS: "Rev", Chapter: 22}}
+	corpus.SortRefs(unknown)
+	if unknown[0].OSIS != "Rev" || unknown[1].OSIS != "Aaa" || unknown[2].OSIS != "Zzz" {
+		t.Errorf("expected unknown books last, got %v", unknown)
+	}
+}
@@ -0,0 +1,121 @@
+package kjvcorpus
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+)
+
+// packDirs are the canon directories Pack copies; align/, editions/, and objects/ are optional
+var packDirs = []string{"index", "books", "align", "editions", "objects"}
+
+// Pack writes the canon layout of fsys, every JSON document under index/, books/, align/,
+// editions/, and objects/ including compressed chapter files, to w as a zip archive that
+// NewPackedStore and OpenPacked read from memory. A canon in the content-addressed layout may have
+// no books/. Entries are written in path order without timestamps, so packs of the same canon are identical. It
+// returns the number of documents packed.
+func Pack(w io.Writer, fsys fs.FS) (int, error) {
+	return pack(w, fsys, nil)
+}
+
+// PackBooks packs the canon of fsys as Pack does, keeping only the books with the given OSIS IDs:
+// their chapter files, introductions, alignments, edition layers, and objects, with the index
+// files rewritten to list only them. It is how a smaller canon, such as the New Testament alone,
+// is extracted for applications with tight size budgets.
+func PackBooks(w io.Writer, fsys fs.FS, books []string) (int, error) {
+	sub, err := newSubset(fsys, books)
+	if err != nil {
+		return 0, err
+	}
+	return pack(w, fsys, sub)
+}
+
+// pack writes the documents of fsys to w, only those sub keeps if it is not nil
+func pack(w io.Writer, fsys fs.FS, sub *subset) (int, error) {
+	zw := zip.NewWriter(w)
+	count := 0
+	for _, dir := range packDirs {
+		err := fs.WalkDir(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !isDocument(name) {
+				return nil
+			}
+			data, rewritten := sub.document(name)
+			if !rewritten {
+				if !sub.keeps(name) {
+					return nil
+				}
+				if data, err = fs.ReadFile(fsys, name); err != nil {
+					return err
+				}
+			}
+			entry, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+			if err != nil {
+				return err
+			}
+			if _, err := entry.Write(data); err != nil {
+				return err
+			}
+			count++
+			return nil
+		})
+		if errors.Is(err, fs.ErrNotExist) && dir != "index" && (dir != "books" || hasObjects(fsys)) {
+			continue
+		} else if err != nil {
+			return count, fmt.Errorf("failed to pack %s: %w", dir, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return count, fmt.Errorf("failed to finish pack: %w", err)
+	}
+	return count, nil
+}
+
+// hasObjects reports whether fsys is a canon in the content-addressed layout
+func hasObjects(fsys fs.FS) bool {
+	_, err := fs.Stat(fsys, "objects")
+	return err == nil
+}
+
+// isDocument reports whether name is a JSON document of the canon, compressed or not
+func isDocument(name string) bool {
+	return path.Ext(strings.TrimSuffix(name, compression.ForName(name).Ext())) == ".json"
+}
+
+// NewPackedStore creates a store over a canon packed by Pack and held in memory, such as one
+// fetched by a web page. Documents are decompressed as they are read.
+func NewPackedStore(data []byte) (*FSStore, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, &CorpusError{
+			Kind:  FileError,
+			Err:   ErrInvalidRoot,
+			Cause: fmt.Errorf("failed to read pack: %w", err),
+		}
+	}
+	return NewFSStore(zr), nil
+}
+
+// OpenPacked opens a corpus over a canon packed by Pack, as Open does over a directory. It reads
+// nothing from disk, so it works where there is no filesystem, such as GOOS=js.
+func OpenPacked(data []byte, opts ...Option) (*Corpus, error) {
+	store, err := NewPackedStore(data)
+	if err != nil {
+		return nil, err
+	}
+	return Open("", append([]Option{WithStore(store)}, opts...)...)
+}
+
+// OpenFS opens a corpus over the canon layout in fsys, such as an embedded filesystem
+func OpenFS(fsys fs.FS, opts ...Option) (*Corpus, error) {
+	return Open("", append([]Option{WithStore(NewFSStore(fsys))}, opts...)...)
+}
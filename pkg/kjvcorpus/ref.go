@@ -0,0 +1,248 @@
+package kjvcorpus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/julianstephens/canonref/bibleref"
+	"github.com/julianstephens/canonref/util"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Ref is a reference to a chapter, or to a verse or range of verses within it. It carries the same
+// information as bibleref.BibleRef, so programs can resolve passages with ParseRef and ResolveRef
+// without importing canonref, and the corpus API does not change when canonref's types do.
+// RefFrom and Ref.BibleRef convert between the two.
+type Ref struct {
+	OSIS    string
+	Chapter int
+	Verses  *VerseRange // nil for the whole chapter
+}
+
+// VerseRange is the verses of a Ref, from Start to End inclusive. End is zero for a single verse.
+// StartPart and EndPart name the sub-part of the first and last verse a citation asks for, such as
+// the "a" of "Gen 1:1a" or the "b" and "a" of "John 3:16b–18a", and are empty for whole verses.
+// The canon does not divide verses, so a sub-part annotates the verse resolved rather than
+// shortening it. EndPart is ignored when End is zero.
+type VerseRange struct {
+	Start     int
+	End       int
+	StartPart string
+	EndPart   string
+}
+
+// Last returns the last verse of the range
+func (v VerseRange) Last() int {
+	if v.End == 0 {
+		return v.Start
+	}
+	return v.End
+}
+
+// String returns the reference as "OSIS Chapter:Verses", such as "John 3:16–18" or "John 3"
+// for a whole chapter, the same form as bibleref.BibleRef.String
+func (r Ref) String() string {
+	if r.Verses == nil {
+		return r.BibleRef().String()
+	}
+	return fmt.Sprintf("%s %d:%s", r.OSIS, r.Chapter, r.Verses)
+}
+
+// String returns the verses as in a reference, such as "16", "16–18", or "16b–18a"
+func (v VerseRange) String() string {
+	if v.End == 0 {
+		return fmt.Sprintf("%d%s", v.Start, v.StartPart)
+	}
+	return fmt.Sprintf("%d%s–%d%s", v.Start, v.StartPart, v.End, v.EndPart)
+}
+
+// RefFrom converts a canonref reference; a nil reference converts to the zero Ref
+func RefFrom(ref *bibleref.BibleRef) Ref {
+	if ref == nil {
+		return Ref{}
+	}
+	converted := Ref{OSIS: ref.OSIS, Chapter: ref.Chapter}
+	if ref.Verse != nil {
+		converted.Verses = &VerseRange{Start: ref.Verse.StartVerse}
+		if ref.Verse.EndVerse != nil && *ref.Verse.EndVerse != ref.Verse.StartVerse {
+			converted.Verses.End = *ref.Verse.EndVerse
+		}
+	}
+	return converted
+}
+
+// BibleRef converts the reference to its canonref form, which has no verse sub-parts
+func (r Ref) BibleRef() *bibleref.BibleRef {
+	ref := &bibleref.BibleRef{OSIS: r.OSIS, Chapter: r.Chapter}
+	if r.Verses != nil {
+		ref.Verse = &util.VerseRange{StartVerse: r.Verses.Start}
+		if r.Verses.End != 0 {
+			end := r.Verses.End
+			ref.Verse.EndVerse = &end
+		}
+	}
+	return ref
+}
+
+// ParseRef parses a reference such as "John 3:16-18" or "1 Cor 13" against the books table, so
+// callers need not import canonref. Verses may carry a sub-part letter, as in "Gen 1:1a" or
+// "John 3:16b-18a", which is kept in the VerseRange. An unparseable reference, or a range that
+// starts and ends in the same verse, fails with ErrInvalidReference.
+func (c *Corpus) ParseRef(s string) (Ref, error) {
+	invalid := func(cause error) (Ref, error) {
+		msg := fmt.Sprintf("cannot parse %q", s)
+		return Ref{}, &CorpusError{
+			Kind:    RangeError,
+			Message: &msg,
+			Err:     ErrInvalidReference,
+			Cause:   cause,
+		}
+	}
+
+	trimmed, startPart, endPart := cutSubverses(s)
+	ref, err := bibleref.Parse(trimmed, c.Table())
+	if err != nil {
+		return invalid(err)
+	}
+	converted := RefFrom(ref)
+	if converted.Verses != nil {
+		if endPart != "" && converted.Verses.End == 0 {
+			return invalid(fmt.Errorf("range within verse %d", converted.Verses.Start))
+		}
+		converted.Verses.StartPart = startPart
+		converted.Verses.EndPart = endPart
+	}
+	return converted, nil
+}
+
+// cutSubverses removes the sub-part letters from the verses of a reference, such as the "b" and
+// "a" of "John 3:16b-18a", returning the reference without them and the letters in lower case
+func cutSubverses(s string) (string, string, string) {
+	colon := strings.LastIndex(s, ":")
+	if colon < 0 {
+		return s, "", ""
+	}
+	verses := s[colon+1:]
+	sep := strings.IndexAny(verses, "-–")
+	if sep < 0 {
+		start, startPart := cutPart(verses)
+		return s[:colon+1] + start, startPart, ""
+	}
+	start, startPart := cutPart(verses[:sep])
+	end, endPart := cutPart(verses[sep:])
+	return s[:colon+1] + start + end, startPart, endPart
+}
+
+// cutPart removes a sub-part letter following a verse number, such as the "a" of "16a"
+func cutPart(s string) (string, string) {
+	trimmed := strings.TrimRight(s, " ")
+	n := len(trimmed)
+	if n < 2 || trimmed[n-2] < '0' || trimmed[n-2] > '9' {
+		return s, ""
+	}
+	part := strings.ToLower(trimmed[n-1:])
+	if part[0] < 'a' || part[0] > 'z' {
+		return s, ""
+	}
+	return trimmed[:n-1], part
+}
+
+// parseVerse parses a verse number with an optional sub-part letter, such as "16" or "16b"
+func parseVerse(s string) (int, string, error) {
+	number, part := cutPart(s)
+	v, err := strconv.Atoi(number)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid verse %q", s)
+	}
+	return v, part, nil
+}
+
+// BookSlug returns a book's segment of a reference slug: its canonical OSIS ID in lower case, such
+// as "john" or "1sam"
+func BookSlug(osis string) string {
+	return strings.ToLower(model.CanonicalOSIS(osis))
+}
+
+// Slug returns the reference as a URL path, such as "john/3/16-18", "john/3/16b", or "john/3" for
+// a whole chapter. Slugs are built from the OSIS ID rather than the book's name, so a permalink
+// does not change when names or aliases in books.json do. ParseSlug reads them back.
+func (r Ref) Slug() string {
+	slug := fmt.Sprintf("%s/%d", BookSlug(r.OSIS), r.Chapter)
+	if r.Verses == nil {
+		return slug
+	}
+	if r.Verses.End == 0 {
+		return fmt.Sprintf("%s/%d%s", slug, r.Verses.Start, r.Verses.StartPart)
+	}
+	return fmt.Sprintf("%s/%d%s-%d%s", slug, r.Verses.Start, r.Verses.StartPart, r.Verses.End, r.Verses.EndPart)
+}
+
+// ParseSlug parses a slug written by Ref.Slug. The book segment is matched like LookupBook, so
+// "1Sam/3" and "1-sam/3" parse as well as "1sam/3", and leading and trailing slashes are ignored.
+// A malformed slug, or one naming a book or chapter the canon does not have, fails with
+// ErrInvalidReference.
+func (c *Corpus) ParseSlug(slug string) (Ref, error) {
+	invalid := func(cause error) (Ref, error) {
+		msg := fmt.Sprintf("cannot parse slug %q", slug)
+		return Ref{}, &CorpusError{
+			Kind:    RangeError,
+			Message: &msg,
+			Err:     ErrInvalidReference,
+			Cause:   cause,
+		}
+	}
+
+	parts := strings.Split(strings.Trim(slug, "/"), "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return invalid(fmt.Errorf("want book/chapter or book/chapter/verses"))
+	}
+	book, ok := c.LookupBook(parts[0])
+	if !ok {
+		return invalid(fmt.Errorf("unknown book %q", parts[0]))
+	}
+	chapter, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return invalid(fmt.Errorf("invalid chapter %q", parts[1]))
+	}
+
+	ref := Ref{OSIS: book.OSIS, Chapter: chapter}
+	if len(parts) == 3 {
+		first, last, isRange := strings.Cut(parts[2], "-")
+		start, startPart, err := parseVerse(first)
+		if err != nil {
+			return invalid(err)
+		}
+		ref.Verses = &VerseRange{Start: start, StartPart: startPart}
+		if isRange {
+			end, endPart, err := parseVerse(last)
+			if err != nil {
+				return invalid(err)
+			}
+			if end == start && endPart != "" {
+				return invalid(fmt.Errorf("range within verse %d", start))
+			}
+			if end != start {
+				ref.Verses.End = end
+				ref.Verses.EndPart = endPart
+			}
+		}
+	}
+	if err := ref.BibleRef().Validate(c.Table()); err != nil {
+		return invalid(err)
+	}
+	return ref, nil
+}
+
+// Passage returns the reference that was resolved as a Ref, with the sub-parts it asked for
+func (r *Resolved) Passage() Ref {
+	ref := RefFrom(r.Ref)
+	if ref.Verses != nil {
+		ref.Verses.StartPart = r.Parts[ref.Verses.Start]
+		if ref.Verses.End != 0 {
+			ref.Verses.EndPart = r.Parts[ref.Verses.End]
+		}
+	}
+	return ref
+}
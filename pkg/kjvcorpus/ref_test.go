@@ -0,0 +1,196 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/julianstephens/canonref/bibleref"
+)
+
+func TestResolveRef(t *testing.T) {
+	corpus := openCanon(t)
+
+	ref, err := corpus.ParseRef("John 3:16-18")
+	if err != nil {
+		t.Fatalf("ParseRef failed: %v", err)
+	}
+	if ref.OSIS != "John" || ref.Chapter != 3 || ref.Verses == nil || ref.Verses.Start != 16 || ref.Verses.Last() != 18 {
+		t.Fatalf("unexpected ref: %+v", ref)
+	}
+	if ref.String() != "John 3:16–18" {
+		t.Errorf("String() = %q", ref.String())
+	}
+
+	resolved, err := corpus.ResolveRef(ref)
+	if err != nil {
+		t.Fatalf("ResolveRef failed: %v", err)
+	}
+	if resolved.Reference() != "John 3:16–18" || len(resolved.Verses) != 3 {
+		t.Errorf("unexpected resolution: %s with %d verses", resolved.Reference(), len(resolved.Verses))
+	}
+	if got := resolved.Passage(); got.String() != ref.String() {
+		t.Errorf("Passage() = %s, want %s", got, ref)
+	}
+
+	// Resolve and ResolveRef agree, and Resolve keeps the caller's reference
+	bref := bibleref.MustParse("John 3:16-18", corpus.Table())
+	viaBibleRef, err := corpus.Resolve(bref)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if viaBibleRef.Ref != bref || viaBibleRef.Reference() != resolved.Reference() {
+		t.Errorf("expected Resolve to match ResolveRef and keep its reference")
+	}
+
+	single, err := corpus.ResolveRef(Ref{OSIS: "John", Chapter: 11, Verses: &VerseRange{Start: 35}})
+	if err != nil || len(single.Verses) != 1 || single.Verses[0].V != 35 {
+		t.Errorf("expected John 11:35 alone, got %v, %v", single, err)
+	}
+	chapter, err := corpus.ResolveRef(Ref{OSIS: "Obad", Chapter: 1})
+	if err != nil || len(chapter.Verses) != 21 {
+		t.Errorf("expected all of Obadiah, got %v", err)
+	}
+
+	if _, err := corpus.ParseRef("Nope 1:1"); !errors.Is(err, ErrInvalidReference) {
+		t.Errorf("expected ErrInvalidReference, got %v", err)
+	}
+	if _, err := corpus.ResolveRef(Ref{}); !errors.Is(err, ErrUnknownBook) {
+		t.Errorf("expected ErrUnknownBook for an empty ref, got %v", err)
+	}
+	if _, err := corpus.ResolveRef(Ref{OSIS: "John", Chapter: 3, Verses: &VerseRange{Start: 99}}); !errors.Is(err, ErrVerseOutOfRange) {
+		t.Errorf("expected ErrVerseOutOfRange, got %v", err)
+	}
+}
+
+func TestRefConversion(t *testing.T) {
+	table := openCanon(t).Table()
+	for _, s := range []string{"Gen 1", "Gen 1:1", "Gen 1:1-3"} {
+		bref := bibleref.MustParse(s, table)
+		if got := RefFrom(bref).BibleRef(); got.String() != bref.String() {
+			t.Errorf("%s round-tripped to %s", bref, got)
+		}
+	}
+
+	// A range ending where it starts is a single verse
+	end := 4
+	bref := bibleref.MustParse("Gen 1:4", table)
+	bref.Verse.EndVerse = &end
+	if ref := RefFrom(bref); ref.Verses.End != 0 || ref.String() != "Gen 1:4" {
+		t.Errorf("expected a single verse, got %+v", ref.Verses)
+	}
+	if ref := RefFrom(nil); ref.OSIS != "" || ref.Verses != nil {
+		t.Errorf("expected the zero Ref for nil, got %+v", ref)
+	}
+}
+
+func TestSubverses(t *testing.T) {
+	corpus := openCanon(t)
+
+	tests := []struct {
+		ref       string
+		want      string
+		reference string
+		parts     map[int]string
+	}{
+		{ref: "Gen 1:1a", want: "Gen 1:1a", reference: "Genesis 1:1a", parts: map[int]string{1: "a"}},
+		{ref: "John 3:16B", want: "John 3:16b", reference: "John 3:16b", parts: map[int]string{16: "b"}},
+		{ref: "John 3:16b-18a", want: "John 3:16b–18a", reference: "John 3:16b–18a", parts: map[int]string{16: "b", 18: "a"}},
+		{ref: "John 3:16-17b", want: "John 3:16–17b", reference: "John 3:16–17b", parts: map[int]string{17: "b"}},
+		{ref: "John 3:16-18", want: "John 3:16–18", reference: "John 3:16–18"},
+	}
+	for _, tt := range tests {
+		ref, err := corpus.ParseRef(tt.ref)
+		if err != nil {
+			t.Fatalf("ParseRef(%q) failed: %v", tt.ref, err)
+		}
+		if ref.String() != tt.want {
+			t.Errorf("ParseRef(%q) = %s, want %s", tt.ref, ref, tt.want)
+		}
+		resolved, err := corpus.ResolveRef(ref)
+		if err != nil {
+			t.Fatalf("ResolveRef(%s) failed: %v", ref, err)
+		}
+		if resolved.Reference() != tt.reference {
+			t.Errorf("Reference() = %q, want %q", resolved.Reference(), tt.reference)
+		}
+		if len(resolved.Parts) != len(tt.parts) {
+			t.Errorf("%s: Parts = %v, want %v", tt.ref, resolved.Parts, tt.parts)
+		}
+		for v, part := range tt.parts {
+			if resolved.Parts[v] != part {
+				t.Errorf("%s: Parts[%d] = %q, want %q", tt.ref, v, resolved.Parts[v], part)
+			}
+		}
+		if got := resolved.Passage(); got.String() != ref.String() {
+			t.Errorf("Passage() = %s, want %s", got, ref)
+		}
+	}
+
+	// Sub-parts annotate whole verses: Gen 1:1a returns all of verse 1
+	resolved, err := corpus.ResolveRef(Ref{OSIS: "Gen", Chapter: 1, Verses: &VerseRange{Start: 1, StartPart: "a"}})
+	if err != nil || len(resolved.Verses) != 1 || resolved.Verses[0].Plain != "In the beginning God created the heaven and the earth." {
+		t.Errorf("expected all of Gen 1:1, got %v", err)
+	}
+	// The end's sub-part is dropped when the range runs past the chapter
+	resolved, err = corpus.ResolveRef(Ref{OSIS: "Obad", Chapter: 1, Verses: &VerseRange{Start: 20, End: 99, EndPart: "a"}})
+	if err != nil || resolved.Parts != nil || resolved.Reference() != "Obadiah 1:20–21" {
+		t.Errorf("expected Obadiah 1:20–21 without sub-parts, got %v", err)
+	}
+
+	for _, s := range []string{"Gen 1:1a-1b", "Gen 1:1ab", "Gen 1a"} {
+		if _, err := corpus.ParseRef(s); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("ParseRef(%q): expected ErrInvalidReference, got %v", s, err)
+		}
+	}
+}
+
+func TestSlug(t *testing.T) {
+	corpus := openCanon(t)
+
+	tests := []struct {
+		ref  string
+		slug string
+	}{
+		{"John 3:16-18", "john/3/16-18"},
+		{"John 3:16", "john/3/16"},
+		{"John 3", "john/3"},
+		{"1 Sam 3:10", "1sam/3/10"},
+		{"Add Esth 10:4-6", "addesth/10/4-6"},
+		{"John 3:16b-18a", "john/3/16b-18a"},
+		{"Gen 1:1a", "gen/1/1a"},
+	}
+	for _, tt := range tests {
+		ref, err := corpus.ParseRef(tt.ref)
+		if err != nil {
+			t.Fatalf("ParseRef(%q) failed: %v", tt.ref, err)
+		}
+		if got := ref.Slug(); got != tt.slug {
+			t.Errorf("%s: Slug() = %q, want %q", tt.ref, got, tt.slug)
+		}
+		back, err := corpus.ParseSlug(tt.slug)
+		if err != nil || back.String() != ref.String() {
+			t.Errorf("ParseSlug(%q) = %s, %v; want %s", tt.slug, back, err, ref)
+		}
+	}
+
+	// Book segments are matched like LookupBook, and surrounding slashes and one-verse ranges are tolerated
+	for _, slug := range []string{"/1Sam/3/10/", "1-sam/3/10", "1sam/3/10-10"} {
+		if ref, err := corpus.ParseSlug(slug); err != nil || ref.String() != "1Sam 3:10" {
+			t.Errorf("ParseSlug(%q) = %s, %v; want 1Sam 3:10", slug, ref, err)
+		}
+	}
+	for _, slug := range []string{"", "john", "john/x", "john/3/16/17", "john/3/18-16", "john/99", "nope/1", "john/3/a-b", "john/3/16ab", "john/3/16a-16b"} {
+		if _, err := corpus.ParseSlug(slug); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("ParseSlug(%q): expected ErrInvalidReference, got %v", slug, err)
+		}
+	}
+
+	// A resolved passage's slug covers the verses actually resolved
+	resolved, err := corpus.ResolveRef(Ref{OSIS: "Obad", Chapter: 1, Verses: &VerseRange{Start: 20, End: 99}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resolved.Slug(); got != "obad/1/20-21" {
+		t.Errorf("Resolved.Slug() = %q, want obad/1/20-21", got)
+	}
+}
@@ -0,0 +1,192 @@
+package kjvcorpus
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sync/atomic"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// ReleaseStore is implemented by stores that can also provide the release files at the root of an
+// extracted release artifact: corpus.json, SHA256SUMS, SHA256SUMS.sig, and the canon.zip of a
+// packed release (see package release). WithSignatureVerification requires one.
+type ReleaseStore interface {
+	// ReadRelease returns the release file with a name such as "SHA256SUMS"
+	ReadRelease(name string) ([]byte, error)
+}
+
+// VerifyChecksums checks that sig, the contents of SHA256SUMS.sig, is pub's signature of sums,
+// the contents of SHA256SUMS, and returns the checksums it lists by path
+func VerifyChecksums(sums, sig []byte, pub ed25519.PublicKey) (map[string]string, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("a release must be verified with an ed25519 public key")
+	}
+	var signature model.ReleaseSignature
+	if err := json.Unmarshal(sig, &signature); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", model.SignatureName, err)
+	}
+	raw, err := hex.DecodeString(signature.Signature)
+	if err != nil || signature.Algorithm != "ed25519" {
+		return nil, fmt.Errorf("%s is not an ed25519 signature", model.SignatureName)
+	}
+	if !ed25519.Verify(pub, sums, raw) {
+		return nil, fmt.Errorf("%w: signed by key %s, verified with key %s", ErrSignatureMismatch, signature.KeyID, model.KeyID(pub))
+	}
+	return model.ParseChecksums(sums)
+}
+
+// release is a release verified by WithSignatureVerification
+type release struct {
+	manifest *model.ReleaseManifest
+	store    ChapterStore      // the canon's documents: the release's directory, or its canon.zip
+	sums     map[string]string // checksums of the documents by path; nil when canon.zip was checked whole
+}
+
+// verifiedStore reads documents from a verified release and rejects those that do not match its
+// signed checksums. Reload verifies the release again and swaps it in.
+type verifiedStore struct {
+	release atomic.Pointer[release]
+}
+
+// verifyRelease checks the signature of the release in source and that corpus.json, and canon.zip
+// for a packed release, match their signed checksums
+func verifyRelease(source ChapterStore, pub ed25519.PublicKey) (*release, error) {
+	files, ok := source.(ReleaseStore)
+	if !ok {
+		return nil, fmt.Errorf("%w: the store does not provide release files", ErrNoRelease)
+	}
+	read := func(name string) ([]byte, error) {
+		data, err := files.ReadRelease(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s not found", ErrNoRelease, name)
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		return data, nil
+	}
+
+	sumsData, err := read(model.ChecksumsName)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := read(model.SignatureName)
+	if err != nil {
+		return nil, err
+	}
+	sums, err := VerifyChecksums(sumsData, sig, pub)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := read(model.ReleaseManifestName)
+	if err == nil {
+		err = checkSum(sums, data, model.ReleaseManifestName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := model.ParseReleaseManifest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &release{manifest: &manifest, store: source, sums: sums}
+	if manifest.Format == model.ReleasePacked {
+		if data, err = read(model.PackName); err == nil {
+			err = checkSum(sums, data, model.PackName)
+		}
+		if err != nil {
+			return nil, err
+		}
+		packed, err := NewPackedStore(data)
+		if err != nil {
+			return nil, err
+		}
+		r.store, r.sums = packed, nil
+	}
+	return r, nil
+}
+
+// checkSum checks data against the checksum sums lists for the first of paths it lists
+func checkSum(sums map[string]string, data []byte, paths ...string) error {
+	sum := sha256.Sum256(data)
+	got := hex.EncodeToString(sum[:])
+	for _, p := range paths {
+		if want, ok := sums[p]; ok {
+			if got != want {
+				return fmt.Errorf("%w: %s", ErrChecksumMismatch, p)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is not listed in %s", ErrChecksumMismatch, paths[0], model.ChecksumsName)
+}
+
+// read reads a document from the release with get and checks it against the checksum of the
+// first of its possible paths the release lists, such as the .zst of a compressed chapter
+func (v *verifiedStore) read(get func(store ChapterStore) ([]byte, error), paths ...string) ([]byte, error) {
+	r := v.release.Load()
+	data, err := get(r.store)
+	if err != nil || r.sums == nil {
+		return data, err
+	}
+	if err := checkSum(r.sums, data, paths...); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (v *verifiedStore) ReadIndex(name string) ([]byte, error) {
+	return v.read(func(store ChapterStore) ([]byte, error) { return store.ReadIndex(name) }, path.Join("index", name))
+}
+
+func (v *verifiedStore) ReadChapter(osis string, chapter int) ([]byte, error) {
+	return v.read(func(store ChapterStore) ([]byte, error) { return store.ReadChapter(osis, chapter) }, ChapterPaths(osis, chapter)...)
+}
+
+func (v *verifiedStore) ReadIntro(osis string) ([]byte, error) {
+	return v.read(func(store ChapterStore) ([]byte, error) { return store.ReadIntro(osis) }, path.Join("books", osis, "intro.json"))
+}
+
+func (v *verifiedStore) ReadObject(name string) ([]byte, error) {
+	return v.read(func(store ChapterStore) ([]byte, error) {
+		if objects, ok := store.(ObjectStore); ok {
+			return objects.ReadObject(name)
+		}
+		return nil, fmt.Errorf("object %s: %w", name, fs.ErrNotExist)
+	}, model.ObjectPath(name))
+}
+
+func (v *verifiedStore) ReadAlignment(osis string, chapter int) ([]byte, error) {
+	return v.read(func(store ChapterStore) ([]byte, error) {
+		if aligner, ok := store.(AlignmentStore); ok {
+			return aligner.ReadAlignment(osis, chapter)
+		}
+		return nil, fmt.Errorf("alignment %s %d: %w", osis, chapter, fs.ErrNotExist)
+	}, AlignmentPath(osis, chapter))
+}
+
+func (v *verifiedStore) ReadEdition(edition, osis string, chapter int) ([]byte, error) {
+	return v.read(func(store ChapterStore) ([]byte, error) {
+		if editions, ok := store.(EditionStore); ok {
+			return editions.ReadEdition(edition, osis, chapter)
+		}
+		return nil, fmt.Errorf("edition %s %s %d: %w", edition, osis, chapter, fs.ErrNotExist)
+	}, EditionPath(edition, osis, chapter))
+}
+
+// Release returns the manifest of the release the corpus was opened from with
+// WithSignatureVerification, or nil for a corpus opened without it
+func (c *Corpus) Release() *model.ReleaseManifest {
+	if c.verified == nil {
+		return nil
+	}
+	return c.verified.release.Load().manifest
+}
@@ -0,0 +1,120 @@
+package kjvcorpus
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// signRelease adds corpus.json, SHA256SUMS, and SHA256SUMS.sig over the files of fsys, as package
+// release writes them, signed with priv
+func signRelease(t *testing.T, fsys fstest.MapFS, priv ed25519.PrivateKey) {
+	t.Helper()
+	manifest, err := json.Marshal(model.ReleaseManifest{Schema: model.ReleaseSchema, Name: "kjv", Version: "1.0.0", Format: model.ReleaseCanon})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys[model.ReleaseManifestName] = &fstest.MapFile{Data: manifest}
+
+	var names []string
+	for name := range fsys {
+		if name != model.ChecksumsName && name != model.SignatureName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var sums strings.Builder
+	for _, name := range names {
+		sum := sha256.Sum256(fsys[name].Data)
+		fmt.Fprintf(&sums, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+	fsys[model.ChecksumsName] = &fstest.MapFile{Data: []byte(sums.String())}
+
+	sig, err := json.Marshal(model.ReleaseSignature{
+		Algorithm: "ed25519",
+		KeyID:     model.KeyID(priv.Public().(ed25519.PublicKey)),
+		Signature: hex.EncodeToString(ed25519.Sign(priv, []byte(sums.String()))),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys[model.SignatureName] = &fstest.MapFile{Data: sig}
+}
+
+func TestSignatureVerification(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	books := `{"schema":1,"work":"KJV","books":[{"osis":"Obad","abbr":"OBA","name":"Obadiah",` +
+		`"aliases":["Obadiah"],"testament":"OT","order":1,"chapters":1}]}`
+	chapter := `{"schema":1,"work":"KJV","osis":"Obad","abbr":"OBA","chapter":1,` +
+		`"verses":[{"v":1,"tokens":[{"t":"The vision of Obadiah."}]}]}`
+	fsys := fstest.MapFS{
+		"index/books.json":     {Data: []byte(books)},
+		"books/Obad/ch01.json": {Data: []byte(chapter)},
+	}
+	signRelease(t, fsys, priv)
+
+	corpus, err := OpenFS(fsys, WithSignatureVerification(pub))
+	if err != nil {
+		t.Fatalf("failed to open a signed release: %v", err)
+	}
+	if release := corpus.Release(); release == nil || release.Version != "1.0.0" {
+		t.Errorf("unexpected release manifest: %+v", release)
+	}
+	if _, err := corpus.loadChapter("Obad", 1); err != nil {
+		t.Errorf("failed to load a signed chapter: %v", err)
+	}
+	if unverified, err := OpenFS(fsys); err != nil || unverified.Release() != nil {
+		t.Errorf("expected a corpus opened without verification to have no release, got %v", err)
+	}
+
+	// A chapter changed after signing is rejected when it is read
+	fsys["books/Obad/ch01.json"] = &fstest.MapFile{Data: []byte(strings.Replace(chapter, "vision", "word", 1))}
+	if err := corpus.Reload(); err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+	var cerr *CorpusError
+	if _, err := corpus.loadChapter("Obad", 1); !errors.Is(err, ErrChecksumMismatch) || !errors.As(err, &cerr) || cerr.Kind != ContentError {
+		t.Errorf("expected ErrChecksumMismatch for a changed chapter, got %v", err)
+	}
+
+	// Re-signing the release and reloading picks up the new checksums
+	signRelease(t, fsys, priv)
+	if err := corpus.Reload(); err != nil {
+		t.Fatalf("failed to reload a re-signed release: %v", err)
+	}
+	if _, err := corpus.loadChapter("Obad", 1); err != nil {
+		t.Errorf("failed to load a re-signed chapter: %v", err)
+	}
+
+	// Documents missing from SHA256SUMS are rejected as well
+	fsys["books/Obad/intro.json"] = &fstest.MapFile{Data: []byte(`{"schema":1,"osis":"Obad"}`)}
+	if _, err := corpus.BookIntro("Obad"); err == nil || !strings.Contains(err.Error(), "is not listed in SHA256SUMS") {
+		t.Errorf("expected an unlisted introduction to be rejected, got %v", err)
+	}
+
+	other, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFS(fsys, WithSignatureVerification(other)); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("expected ErrSignatureMismatch for another key, got %v", err)
+	}
+
+	delete(fsys, model.SignatureName)
+	if _, err := OpenFS(fsys, WithSignatureVerification(pub)); !errors.Is(err, ErrNoRelease) {
+		t.Errorf("expected ErrNoRelease without a signature, got %v", err)
+	}
+}
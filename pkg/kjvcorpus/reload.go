@@ -0,0 +1,113 @@
+package kjvcorpus
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"time"
+)
+
+// Reload reads the index again and atomically replaces the books table and the chapter and
+// introduction caches. Resolve calls already in flight finish against the previous snapshot.
+// If the index cannot be loaded, the current snapshot is kept and the error is returned.
+func (c *Corpus) Reload() error {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	// A verified corpus verifies the release again and loads the snapshot from it, swapping it in
+	// only once the snapshot has loaded
+	store := c.store
+	var next *verifiedStore
+	if c.verified != nil {
+		r, err := verifyRelease(c.source, c.releaseKey)
+		if err != nil {
+			return &CorpusError{
+				Kind: ContentError,
+				Err:  err,
+			}
+		}
+		next = &verifiedStore{}
+		next.release.Store(r)
+		store = next
+	}
+
+	snap, err := loadSnapshot(store, c.scanMode)
+	if err == nil && c.locale != "" {
+		err = snap.loadLocale(store, c.locale)
+	}
+	if err != nil {
+		return err
+	}
+	if next != nil {
+		c.verified.release.Store(next.release.Load())
+	}
+	c.snap.Store(snap)
+	c.Books = snap.books
+
+	return nil
+}
+
+// Close stops the watcher started by WithWatch, if any
+func (c *Corpus) Close() error {
+	c.closeOnce.Do(func() {
+		if c.stopWatch != nil {
+			close(c.stopWatch)
+			<-c.watchDone
+		}
+	})
+	return nil
+}
+
+// startWatch polls the index every watchInterval and reloads the corpus when it changes
+func (c *Corpus) startWatch() {
+	c.stopWatch = make(chan struct{})
+	c.watchDone = make(chan struct{})
+
+	go func() {
+		defer close(c.watchDone)
+
+		ticker := time.NewTicker(c.watchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.stopWatch:
+				return
+			case <-ticker.C:
+			}
+
+			booksData, err := c.source.ReadIndex("books.json")
+			if err == nil {
+				var version string
+				version, err = indexVersion(c.source, booksData)
+				if err == nil && version == c.snap.Load().version {
+					continue
+				}
+			}
+			if err == nil {
+				err = c.Reload()
+			}
+			if c.onReload != nil {
+				c.onReload(err)
+			}
+		}
+	}()
+}
+
+// indexVersion hashes books.json and, when present, filemap.json. Ingest rewrites filemap.json
+// whenever it writes chapters, so the hash changes whenever the canon does.
+func indexVersion(store ChapterStore, booksData []byte) (string, error) {
+	h := sha256.New()
+	h.Write(booksData)
+
+	filemap, err := store.ReadIndex("filemap.json")
+	switch {
+	case err == nil:
+		h.Write(filemap)
+	case !errors.Is(err, fs.ErrNotExist):
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,148 @@
+package kjvcorpus
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/canonref/bibleref"
+)
+
+// writeCanon writes a one-chapter Obadiah canon whose single verse reads text
+func writeCanon(t *testing.T, root, name, text string) {
+	t.Helper()
+
+	books := `{"schema":1,"work":"KJV","books":[{"osis":"Obad","abbr":"OBA","name":"` + name + `",` +
+		`"aliases":["` + name + `"],"testament":"OT","order":1,"chapters":1}]}`
+	chapter := `{"schema":1,"work":"KJV","osis":"Obad","abbr":"OBA","chapter":1,` +
+		`"verses":[{"v":1,"plain":"` + text + `","tokens":[{"t":"` + text + `"}]}]}`
+
+	for path, content := range map[string]string{
+		"index/books.json":     books,
+		"books/Obad/ch01.json": chapter,
+	} {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func resolveObadiah(t *testing.T, corpus *Corpus) *Resolved {
+	t.Helper()
+	resolved, err := corpus.Resolve(&bibleref.BibleRef{OSIS: "Obad", Chapter: 1})
+	if err != nil {
+		t.Fatalf("failed to resolve Obadiah 1: %v", err)
+	}
+	return resolved
+}
+
+func TestReload(t *testing.T) {
+	root := t.TempDir()
+	writeCanon(t, root, "Obadiah", "old")
+
+	corpus, err := Open(root)
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	before := resolveObadiah(t, corpus)
+
+	writeCanon(t, root, "Abdias", "new")
+
+	// The chapter cache belongs to the old snapshot until Reload
+	if got := resolveObadiah(t, corpus).Verses[0].Plain; got != "old" {
+		t.Errorf("expected cached text before reload, got %q", got)
+	}
+
+	if err := corpus.Reload(); err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+
+	after := resolveObadiah(t, corpus)
+	if after.Verses[0].Plain != "new" || after.BookName != "Abdias" {
+		t.Errorf("expected reloaded book and chapter, got %s %q", after.BookName, after.Verses[0].Plain)
+	}
+	if corpus.Table().ByOsis["Obad"].Name != "Abdias" || corpus.Books.ByOsis["Obad"].Name != "Abdias" {
+		t.Error("expected books table to be replaced")
+	}
+	if before.Verses[0].Plain != "old" {
+		t.Errorf("expected earlier result to be unaffected, got %q", before.Verses[0].Plain)
+	}
+
+	// A broken index keeps the current snapshot
+	if err := os.WriteFile(filepath.Join(root, "index", "books.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := corpus.Reload(); err == nil {
+		t.Error("expected reload of a corrupt books.json to fail")
+	}
+	if got := resolveObadiah(t, corpus).BookName; got != "Abdias" {
+		t.Errorf("expected previous snapshot after failed reload, got %s", got)
+	}
+}
+
+func TestReloadConcurrentResolve(t *testing.T) {
+	root := t.TempDir()
+	writeCanon(t, root, "Obadiah", "text")
+
+	corpus, err := Open(root)
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := corpus.Resolve(&bibleref.BibleRef{OSIS: "Obad", Chapter: 1}); err != nil {
+					t.Errorf("resolve failed during reload: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		if err := corpus.Reload(); err != nil {
+			t.Fatalf("failed to reload: %v", err)
+		}
+	}
+	wg.Wait()
+}
+
+func TestWithWatch(t *testing.T) {
+	root := t.TempDir()
+	writeCanon(t, root, "Obadiah", "old")
+
+	reloaded := make(chan error, 10)
+	corpus, err := Open(root, WithWatch(10*time.Millisecond, func(err error) { reloaded <- err }))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	defer func() {
+		if err := corpus.Close(); err != nil {
+			t.Errorf("failed to close corpus: %v", err)
+		}
+	}()
+
+	writeCanon(t, root, "Abdias", "new")
+
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatalf("watch reload failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the watcher to reload")
+	}
+
+	if got := resolveObadiah(t, corpus); got.BookName != "Abdias" || got.Verses[0].Plain != "new" {
+		t.Errorf("expected reloaded corpus, got %s %q", got.BookName, got.Verses[0].Plain)
+	}
+}
@@ -0,0 +1,151 @@
+package kjvcorpus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// defaultWork is the work named in citations when a chapter does not record one
+const defaultWork = "KJV"
+
+// paragraphMark is the pilcrow the source places before verses that start a paragraph
+const paragraphMark = "¶"
+
+// resolvedJSON is the wire format of Resolved
+type resolvedJSON struct {
+	Reference string             `json:"reference"`
+	Citation  string             `json:"citation"`
+	Slug      string             `json:"slug"`
+	Work      string             `json:"work"`
+	Edition   string             `json:"edition,omitempty"`
+	NoAdded   bool               `json:"no_added,omitempty"`
+	OSIS      string             `json:"osis"`
+	Book      string             `json:"book"`
+	Chapter   int                `json:"chapter"`
+	Verses    []resolvedVerse    `json:"verses"`
+	Footnotes []resolvedFootnote `json:"footnotes,omitempty"`
+}
+
+type resolvedVerse struct {
+	V    int    `json:"v"`
+	Part string `json:"part,omitempty"`
+	Text string `json:"text"`
+}
+
+type resolvedFootnote struct {
+	V        int                 `json:"v"`
+	Mark     string              `json:"mark"`
+	Text     string              `json:"text"`
+	Original *model.FootnoteTerm `json:"original,omitempty"` // the reading of a note on the original language
+}
+
+// MarshalJSON encodes the resolved passage as its reference, citation, and slug, the book and chapter,
+// the plain text of each verse with the sub-part asked of it, and the footnotes anchored to them
+func (r *Resolved) MarshalJSON() ([]byte, error) {
+	out := resolvedJSON{
+		Reference: r.Reference(),
+		Citation:  r.Citation(),
+		Slug:      r.Slug(),
+		Work:      r.work(),
+		Edition:   r.Edition,
+		NoAdded:   r.NoAdded,
+		OSIS:      r.Chapter.OSIS,
+		Book:      r.BookName,
+		Chapter:   r.Chapter.Chapter,
+		Verses:    make([]resolvedVerse, 0, len(r.Verses)),
+	}
+	for _, verse := range r.Verses {
+		out.Verses = append(out.Verses, resolvedVerse{V: verse.V, Part: r.Parts[verse.V], Text: verse.Plain})
+	}
+	for _, fn := range r.Footnotes {
+		note := resolvedFootnote{V: fn.At.V, Mark: fn.Mark, Text: fn.Text}
+		if term, ok := fn.Term(); ok {
+			note.Original = &term
+		}
+		out.Footnotes = append(out.Footnotes, note)
+	}
+	return json.Marshal(out)
+}
+
+// Reference returns the book name, chapter, and the verses actually resolved with the sub-parts
+// asked for, such as "John 3:16–18" or "John 3:16b–18a", or "John 3" for a whole chapter
+func (r *Resolved) Reference() string {
+	ref := fmt.Sprintf("%s %d", r.BookName, r.Chapter.Chapter)
+	if r.Ref == nil || r.Ref.Verse == nil || len(r.Verses) == 0 {
+		return ref
+	}
+
+	first, last := r.Verses[0].V, r.Verses[len(r.Verses)-1].V
+	if first == last {
+		return fmt.Sprintf("%s:%d%s", ref, first, r.Parts[first])
+	}
+	return fmt.Sprintf("%s:%d%s–%d%s", ref, first, r.Parts[first], last, r.Parts[last])
+}
+
+// Slug returns the URL path of the verses actually resolved, as Ref.Slug, such as "john/3/16-18"
+// or "john/3" for a whole chapter
+func (r *Resolved) Slug() string {
+	ref := Ref{OSIS: r.Chapter.OSIS, Chapter: r.Chapter.Chapter}
+	if r.Ref != nil && r.Ref.Verse != nil && len(r.Verses) > 0 {
+		ref.Verses = &VerseRange{Start: r.Verses[0].V, StartPart: r.Parts[r.Verses[0].V]}
+		if last := r.Verses[len(r.Verses)-1].V; last != ref.Verses.Start {
+			ref.Verses.End = last
+			ref.Verses.EndPart = r.Parts[last]
+		}
+	}
+	return ref.Slug()
+}
+
+// Citation returns the reference followed by the work, such as "John 3:16–18 (KJV)", and the
+// edition when the text is not the canon's own, such as "John 3:16 (KJV 1611)". Text resolved
+// without its added words says so, as in "John 3:16 (KJV, added words omitted)".
+func (r *Resolved) Citation() string {
+	work := r.work()
+	if r.Edition != "" {
+		work += " " + r.Edition
+	}
+	if r.NoAdded {
+		work += ", added words omitted"
+	}
+	return fmt.Sprintf("%s (%s)", r.Reference(), work)
+}
+
+// work returns the work the chapter belongs to
+func (r *Resolved) work() string {
+	if r.Chapter.Work == "" {
+		return defaultWork
+	}
+	return r.Chapter.Work
+}
+
+// Snippet returns the plain text of the resolved verses followed by the citation, such as
+// "For God so loved the world… — John 3:16 (KJV)". Text longer than maxWords words is cut at a
+// word boundary and ends with an ellipsis; maxWords of zero or less keeps every word. Paragraph
+// marks are dropped.
+func (r *Resolved) Snippet(maxWords int) string {
+	var words []string
+	for _, verse := range r.Verses {
+		text, _ := verseText(verse)
+		words = append(words, strings.Fields(text)...)
+	}
+
+	text := strings.Join(words, " ")
+	if maxWords > 0 && len(words) > maxWords {
+		text = strings.TrimRight(strings.Join(words[:maxWords], " "), ",;:.") + "…"
+	}
+	return fmt.Sprintf("%s — %s", text, r.Citation())
+}
+
+// Quote resolves a reference and returns its snippet, truncated to maxWords words
+func (c *Corpus) Quote(ref *bibleref.BibleRef, maxWords int) (string, error) {
+	resolved, err := c.Resolve(ref)
+	if err != nil {
+		return "", err
+	}
+	return resolved.Snippet(maxWords), nil
+}
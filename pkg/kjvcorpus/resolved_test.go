@@ -0,0 +1,175 @@
+package kjvcorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/canonref/bibleref"
+	"github.com/julianstephens/canonref/util"
+)
+
+// openCanon opens the corpus committed under canon/kjv
+func openCanon(t *testing.T) *Corpus {
+	t.Helper()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	corpus, err := Open(filepath.Join(cwd, "canon", "kjv"))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	return corpus
+}
+
+func TestCitation(t *testing.T) {
+	corpus := openCanon(t)
+
+	tests := []struct {
+		name string
+		ref  bibleref.BibleRef
+		want string
+	}{
+		{"single verse", bibleref.BibleRef{OSIS: "John", Chapter: 3, Verse: &util.VerseRange{StartVerse: 16}}, "John 3:16 (KJV)"},
+		{"range", bibleref.BibleRef{OSIS: "John", Chapter: 3, Verse: &util.VerseRange{StartVerse: 16, EndVerse: ptrInt(18)}}, "John 3:16–18 (KJV)"},
+		{"range clipped to chapter", bibleref.BibleRef{OSIS: "Gen", Chapter: 1, Verse: &util.VerseRange{StartVerse: 30, EndVerse: ptrInt(40)}}, "Genesis 1:30–31 (KJV)"},
+		{"whole chapter", bibleref.BibleRef{OSIS: "Ps", Chapter: 23}, "Psalms 23 (KJV)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := corpus.Resolve(&tt.ref)
+			if err != nil {
+				t.Fatalf("failed to resolve: %v", err)
+			}
+			if got := resolved.Citation(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResolvedMarshalJSON(t *testing.T) {
+	corpus := openCanon(t)
+
+	resolved, err := corpus.Resolve(&bibleref.BibleRef{OSIS: "Gen", Chapter: 1, Verse: &util.VerseRange{StartVerse: 1, EndVerse: ptrInt(2)}})
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+
+	data, err := json.Marshal(resolved)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	want := map[string]interface{}{
+		"reference": "Genesis 1:1–2",
+		"citation":  "Genesis 1:1–2 (KJV)",
+		"work":      "KJV",
+		"osis":      "Gen",
+		"book":      "Genesis",
+		"chapter":   float64(1),
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s: expected %v, got %v", key, value, got[key])
+		}
+	}
+
+	verses, _ := got["verses"].([]interface{})
+	if len(verses) != 2 {
+		t.Fatalf("expected 2 verses, got %v", got["verses"])
+	}
+	if first, _ := verses[0].(map[string]interface{}); first["text"] != "In the beginning God created the heaven and the earth." {
+		t.Errorf("unexpected first verse: %v", first)
+	}
+	if first, _ := verses[0].(map[string]interface{}); first["part"] != nil {
+		t.Errorf("expected no part for a whole verse, got %v", first["part"])
+	}
+
+	// Sub-parts are annotated on the verses they were asked of
+	resolved.Parts = map[int]string{2: "a"}
+	data, err = json.Marshal(resolved)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"reference":"Genesis 1:1–2a"`) || !strings.Contains(string(data), `{"v":2,"part":"a",`) {
+		t.Errorf("expected verse 2 to be annotated with part a, got %s", data)
+	}
+}
+
+func TestResolvedFootnoteTerms(t *testing.T) {
+	corpus := openCanon(t)
+
+	resolved, err := corpus.Resolve(&bibleref.BibleRef{OSIS: "Gen", Chapter: 1, Verse: &util.VerseRange{StartVerse: 6}})
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	data, err := json.Marshal(resolved)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	// Notes on the original language carry their parts beside the raw text
+	if !strings.Contains(string(data), `"text":"firmament: Heb. expansion","original":{"term":"firmament","language":"Hebrew","gloss":"expansion"}}`) {
+		t.Errorf("expected the footnote on verse 6 with its original-language reading, got %s", data)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	corpus := openCanon(t)
+	john316 := &bibleref.BibleRef{OSIS: "John", Chapter: 3, Verse: &util.VerseRange{StartVerse: 16}}
+
+	tests := []struct {
+		name     string
+		maxWords int
+		want     string
+	}{
+		{"truncated", 6, "For God so loved the world… — John 3:16 (KJV)"},
+		{"trailing punctuation trimmed", 7, "For God so loved the world, that… — John 3:16 (KJV)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := corpus.Quote(john316, tt.maxWords)
+			if err != nil {
+				t.Fatalf("Quote failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	full, err := corpus.Quote(john316, 0)
+	if err != nil {
+		t.Fatalf("Quote failed: %v", err)
+	}
+	if !strings.HasSuffix(full, "everlasting life. — John 3:16 (KJV)") || strings.Contains(full, "…") {
+		t.Errorf("expected the whole verse without an ellipsis, got %q", full)
+	}
+
+	if _, err := corpus.Quote(&bibleref.BibleRef{OSIS: "John", Chapter: 3, Verse: &util.VerseRange{StartVerse: 99}}, 5); !errors.Is(err, ErrVerseOutOfRange) {
+		t.Errorf("expected ErrVerseOutOfRange, got %v", err)
+	}
+}
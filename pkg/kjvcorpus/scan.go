@@ -0,0 +1,165 @@
+package kjvcorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// scanMode selects whether Open validates every chapter up front
+type scanMode int
+
+const (
+	scanNone    scanMode = iota // chapters are read lazily by Resolve
+	scanStrict                  // Open fails if any chapter is missing or corrupt
+	scanLenient                 // missing and corrupt chapters are marked unavailable
+)
+
+// ChapterIssue describes a chapter that did not pass the eager scan
+type ChapterIssue struct {
+	OSIS    string
+	Chapter int
+	Err     error
+}
+
+func (i ChapterIssue) String() string {
+	return fmt.Sprintf("%s: %v", ChapterPath(i.OSIS, i.Chapter), i.Err)
+}
+
+// ScanReport is the result of reading and validating every chapter listed in books.json
+type ScanReport struct {
+	Scanned int            // chapters read and validated successfully
+	Missing []ChapterIssue // chapters ingest produced (or, without filemap.json, any chapter) that could not be read
+	Corrupt []ChapterIssue // chapters that could not be parsed or failed validation
+	Absent  []ChapterIssue // chapters with no file that ingest never produced, e.g. Additions to Esther 1-9
+}
+
+// OK reports whether no chapters are missing or corrupt
+func (r *ScanReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Corrupt) == 0
+}
+
+// ScanError is returned by Open with WithStrictScan when the scan finds missing or corrupt chapters
+type ScanError struct {
+	Report *ScanReport
+}
+
+func (e *ScanError) Error() string {
+	issues := append(append([]ChapterIssue{}, e.Report.Missing...), e.Report.Corrupt...)
+	shown := make([]string, 0, 3)
+	for i := 0; i < len(issues) && i < 3; i++ {
+		shown = append(shown, issues[i].String())
+	}
+	if len(issues) > len(shown) {
+		shown = append(shown, fmt.Sprintf("and %d more", len(issues)-len(shown)))
+	}
+	return fmt.Sprintf("canon scan found %d missing and %d corrupt chapters: %s",
+		len(e.Report.Missing), len(e.Report.Corrupt), strings.Join(shown, "; "))
+}
+
+// ScanReport returns the report of the most recent eager scan, or nil if the corpus was opened
+// without WithStrictScan or WithLenientScan
+func (c *Corpus) ScanReport() *ScanReport {
+	return c.snap.Load().report
+}
+
+// scan reads and validates every chapter of every book in s, in canonical order
+func (s *snapshot) scan(store ChapterStore) (*ScanReport, error) {
+	// filemap.json tells chapters that were lost apart from chapters the source never had
+	var produced map[string]bool
+	data, err := store.ReadIndex("filemap.json")
+	switch {
+	case err == nil:
+		fileMap, err := model.ParseFileMap(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse filemap.json: %w", err)
+		}
+		produced = make(map[string]bool, len(fileMap.Files))
+		for _, entry := range fileMap.Files {
+			produced[filepath.ToSlash(entry.Output)] = true
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, fmt.Errorf("failed to read filemap.json: %w", err)
+	}
+
+	books := make([]string, 0, len(s.booksByID))
+	for osis := range s.booksByID {
+		books = append(books, osis)
+	}
+	sort.Slice(books, func(i, j int) bool { return s.booksByID[books[i]].Order < s.booksByID[books[j]].Order })
+
+	report := &ScanReport{}
+	for _, osis := range books {
+		for chapter := 1; chapter <= s.booksByID[osis].Chapters; chapter++ {
+			issue := ChapterIssue{OSIS: osis, Chapter: chapter}
+
+			data, err := s.readChapter(store, osis, chapter)
+			if err != nil {
+				issue.Err = err
+				if errors.Is(err, fs.ErrNotExist) && produced != nil && !producedAny(produced, ChapterPaths(osis, chapter)) {
+					report.Absent = append(report.Absent, issue)
+				} else {
+					report.Missing = append(report.Missing, issue)
+				}
+				continue
+			}
+
+			if err := validateChapter(data, osis, chapter); err != nil {
+				issue.Err = err
+				report.Corrupt = append(report.Corrupt, issue)
+				continue
+			}
+			report.Scanned++
+		}
+	}
+
+	return report, nil
+}
+
+// producedAny reports whether the filemap records any of paths as produced
+func producedAny(produced map[string]bool, paths []string) bool {
+	for _, p := range paths {
+		if produced[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// validateChapter checks that a chapter file, compressed or not, parses and describes the
+// expected chapter
+func validateChapter(data []byte, osis string, chapter int) error {
+	data, err := compression.Decompress(data)
+	if err != nil {
+		return err
+	}
+	var ch model.Chapter
+	if err := json.Unmarshal(data, &ch); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if ch.OSIS != osis || ch.Chapter != chapter {
+		return fmt.Errorf("file describes %s %d", ch.OSIS, ch.Chapter)
+	}
+	if len(ch.Verses) == 0 {
+		return fmt.Errorf("no verses")
+	}
+
+	for i, verse := range ch.Verses {
+		if verse.V < 1 {
+			return fmt.Errorf("invalid verse number %d", verse.V)
+		}
+		if i > 0 && verse.V <= ch.Verses[i-1].V {
+			return fmt.Errorf("verse %d follows verse %d", verse.V, ch.Verses[i-1].V)
+		}
+	}
+
+	return nil
+}
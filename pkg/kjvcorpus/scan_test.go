@@ -0,0 +1,125 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/canonref/bibleref"
+)
+
+func TestStrictScanCanon(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	corpus, err := Open(filepath.Join(cwd, "canon", "kjv"), WithStrictScan())
+	if err != nil {
+		t.Fatalf("strict scan of the canon failed: %v", err)
+	}
+
+	report := corpus.ScanReport()
+	if report == nil || report.Scanned == 0 {
+		t.Fatalf("expected a scan report, got %+v", report)
+	}
+	// Additions to Esther only carries chapter 10 onward in the source
+	for _, issue := range report.Absent {
+		if issue.OSIS != "AddEsth" {
+			t.Errorf("unexpected absent chapter %s", issue)
+		}
+	}
+}
+
+// writeScanCanon writes a three-chapter canon in which chapter 2 is corrupt and chapter 3, which
+// filemap.json says ingest produced, is missing
+func writeScanCanon(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"index/books.json": `{"schema":1,"work":"KJV","books":[{"osis":"Obad","abbr":"OBA","name":"Obadiah",` +
+			`"aliases":["Obadiah"],"testament":"OT","order":1,"chapters":4}]}`,
+		"index/filemap.json": `{"schema":2,"files":{` +
+			`"raw/OBA01.htm":{"raw":"raw/OBA01.htm","output":"books/Obad/ch01.json"},` +
+			`"raw/OBA02.htm":{"raw":"raw/OBA02.htm","output":"books/Obad/ch02.json"},` +
+			`"raw/OBA03.htm":{"raw":"raw/OBA03.htm","output":"books/Obad/ch03.json"}}}`,
+		"books/Obad/ch01.json": `{"schema":1,"osis":"Obad","chapter":1,"verses":[{"v":1,"tokens":[{"t":"a"}]}]}`,
+		"books/Obad/ch02.json": `{"schema":1,"osis":"Obad","chapter":1,"verses":[{"v":1,"tokens":[{"t":"b"}]}]}`,
+	}
+	for path, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestStrictScan(t *testing.T) {
+	_, err := Open(writeScanCanon(t), WithStrictScan())
+
+	var scanErr *ScanError
+	if !errors.As(err, &scanErr) {
+		t.Fatalf("expected *ScanError, got %v", err)
+	}
+
+	report := scanErr.Report
+	if report.Scanned != 1 {
+		t.Errorf("expected 1 scanned chapter, got %d", report.Scanned)
+	}
+	if len(report.Corrupt) != 1 || report.Corrupt[0].Chapter != 2 {
+		t.Errorf("expected chapter 2 to be corrupt, got %v", report.Corrupt)
+	}
+	if len(report.Missing) != 1 || report.Missing[0].Chapter != 3 {
+		t.Errorf("expected chapter 3 to be missing, got %v", report.Missing)
+	}
+	if len(report.Absent) != 1 || report.Absent[0].Chapter != 4 {
+		t.Errorf("expected chapter 4 to be absent, got %v", report.Absent)
+	}
+}
+
+func TestLenientScan(t *testing.T) {
+	corpus, err := Open(writeScanCanon(t), WithLenientScan())
+	if err != nil {
+		t.Fatalf("lenient scan should not fail: %v", err)
+	}
+	if corpus.ScanReport().OK() {
+		t.Error("expected the report to list problems")
+	}
+
+	tests := []struct {
+		chapter int
+		wantErr error
+	}{
+		{chapter: 1, wantErr: nil},
+		{chapter: 2, wantErr: ErrChapterUnavailable},
+		{chapter: 3, wantErr: ErrChapterUnavailable},
+		{chapter: 4, wantErr: ErrChapterNotFound},
+	}
+
+	for _, tt := range tests {
+		_, err := corpus.Resolve(&bibleref.BibleRef{OSIS: "Obad", Chapter: tt.chapter})
+		if tt.wantErr == nil && err != nil {
+			t.Errorf("chapter %d: unexpected error: %v", tt.chapter, err)
+		}
+		if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+			t.Errorf("chapter %d: expected %v, got %v", tt.chapter, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Package sqlitestore provides a kjvcorpus.ChapterStore backed by a single SQLite database file.
+//
+// The database is built from a canon directory with Import and opened read-only with Open:
+//
+//	store, err := sqlitestore.Open("kjv.sqlite")
+//	corpus, err := kjvcorpus.Open("", kjvcorpus.WithStore(store))
+package sqlitestore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+
+	// Registers the pure Go "sqlite" database/sql driver
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS indexes (name TEXT PRIMARY KEY, data BLOB NOT NULL);
+CREATE TABLE IF NOT EXISTS chapters (osis TEXT NOT NULL, chapter INTEGER NOT NULL, data BLOB NOT NULL,
+	PRIMARY KEY (osis, chapter));
+CREATE TABLE IF NOT EXISTS intros (osis TEXT PRIMARY KEY, data BLOB NOT NULL);
+`
+
+// chapterFilePattern matches canonical chapter file names, capturing the chapter number. Chapters
+// of a compressed canon are imported as they are and decompressed as they are read.
+var chapterFilePattern = regexp.MustCompile(`^ch(\d+)\.json(\.gz|\.zst)?$`)
+
+// Store reads canonical documents from a SQLite database
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens an existing database built with Import
+func Open(path string) (*Store, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open SQLite store: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite store: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open SQLite store: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// ReadIndex returns an index document such as "books.json"
+func (s *Store) ReadIndex(name string) ([]byte, error) {
+	return s.query(fmt.Sprintf("index %s", name), "SELECT data FROM indexes WHERE name = ?", name)
+}
+
+// ReadChapter returns the chapter JSON for a book and chapter number
+func (s *Store) ReadChapter(osis string, chapter int) ([]byte, error) {
+	return s.query(fmt.Sprintf("chapter %s %d", osis, chapter),
+		"SELECT data FROM chapters WHERE osis = ? AND chapter = ?", osis, chapter)
+}
+
+// ReadIntro returns the introduction JSON for a book
+func (s *Store) ReadIntro(osis string) ([]byte, error) {
+	return s.query(fmt.Sprintf("introduction %s", osis), "SELECT data FROM intros WHERE osis = ?", osis)
+}
+
+// query returns the single data column selected by query, reporting a missing row as fs.ErrNotExist
+func (s *Store) query(what, query string, args ...interface{}) ([]byte, error) {
+	var data []byte
+	err := s.db.QueryRow(query, args...).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", what, fs.ErrNotExist)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", what, err)
+	}
+	return data, nil
+}
+
+// Import creates or replaces the database at path with every index, chapter, and introduction
+// document in the canon directory canonDir (e.g. canon/kjv)
+func Import(path, canonDir string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace SQLite store: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		return fmt.Errorf("failed to create SQLite store: %w", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Printf("Error closing SQLite store: %v\n", err)
+		}
+	}()
+
+	if _, err := db.Exec(schema); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin import: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	indexDir := filepath.Join(canonDir, "index")
+	indexFiles, err := filepath.Glob(filepath.Join(indexDir, "*.json"))
+	if err != nil {
+		return err
+	}
+	localeFiles, err := filepath.Glob(filepath.Join(indexDir, "locales", "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range append(indexFiles, localeFiles...) {
+		data, err := os.ReadFile(file) // nolint: gosec
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		// Locales are named by their path under index/, as ReadIndex is asked for them
+		name, err := filepath.Rel(indexDir, file)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("INSERT INTO indexes (name, data) VALUES (?, ?)", filepath.ToSlash(name), data); err != nil {
+			return fmt.Errorf("failed to import %s: %w", file, err)
+		}
+	}
+
+	// A canon in the content-addressed layout may have no books/, if no book has an introduction
+	objects, err := readObjectIndex(indexDir)
+	if err != nil {
+		return err
+	}
+	bookDirs, err := os.ReadDir(filepath.Join(canonDir, "books"))
+	if err != nil && (objects == nil || !errors.Is(err, fs.ErrNotExist)) {
+		return fmt.Errorf("failed to read books directory: %w", err)
+	}
+	for _, bookDir := range bookDirs {
+		if !bookDir.IsDir() {
+			continue
+		}
+		osis := bookDir.Name()
+
+		files, err := os.ReadDir(filepath.Join(canonDir, "books", osis))
+		if err != nil {
+			return fmt.Errorf("failed to read book directory %s: %w", osis, err)
+		}
+		for _, f := range files {
+			file := filepath.Join(canonDir, "books", osis, f.Name())
+
+			var stmt string
+			var args []interface{}
+			if f.Name() == "intro.json" {
+				stmt = "INSERT INTO intros (osis, data) VALUES (?, ?)"
+				args = []interface{}{osis}
+			} else if m := chapterFilePattern.FindStringSubmatch(f.Name()); m != nil {
+				chapter, _ := strconv.Atoi(m[1])
+				stmt = "INSERT INTO chapters (osis, chapter, data) VALUES (?, ?, ?)"
+				args = []interface{}{osis, chapter}
+			} else {
+				continue
+			}
+
+			data, err := os.ReadFile(file) // nolint: gosec
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			if _, err := tx.Exec(stmt, append(args, data)...); err != nil {
+				return fmt.Errorf("failed to import %s: %w", file, err)
+			}
+		}
+	}
+
+	// Chapters in objects/ are imported under their book and chapter, unless books/ holds them too,
+	// as readers prefer the file in books/
+	if objects != nil {
+		for key, name := range objects.Chapters {
+			osis, chapter, ok := strings.Cut(key, ".")
+			number, err := strconv.Atoi(chapter)
+			if !ok || err != nil {
+				return fmt.Errorf("invalid chapter %q in objects.json", key)
+			}
+			file := filepath.Join(canonDir, filepath.FromSlash(model.ObjectPath(name)))
+			data, err := os.ReadFile(file) // nolint: gosec
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", file, err)
+			}
+			if _, err := tx.Exec("INSERT OR IGNORE INTO chapters (osis, chapter, data) VALUES (?, ?, ?)", osis, number, data); err != nil {
+				return fmt.Errorf("failed to import %s: %w", file, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit import: %w", err)
+	}
+	return nil
+}
+
+// readObjectIndex reads index/objects.json of a canon in the content-addressed layout, or returns
+// nil if the canon has none
+func readObjectIndex(indexDir string) (*model.ObjectIndex, error) {
+	data, err := os.ReadFile(filepath.Join(indexDir, "objects.json")) // nolint: gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read objects.json: %w", err)
+	}
+	objects, err := model.ParseObjectIndex(data)
+	if err != nil {
+		return nil, err
+	}
+	return &objects, nil
+}
@@ -0,0 +1,80 @@
+package sqlitestore
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+func TestImportAndResolve(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+
+	// Navigate to project root
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	dbPath := filepath.Join(t.TempDir(), "kjv.sqlite")
+	if err := Import(dbPath, filepath.Join(cwd, "canon", "kjv")); err != nil {
+		t.Fatalf("failed to import canon: %v", err)
+	}
+
+	store, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("failed to close store: %v", err)
+		}
+	}()
+
+	corpus, err := kjvcorpus.Open("", kjvcorpus.WithStore(store))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+
+	ref, err := bibleref.Parse("John 3:16", corpus.Books)
+	if err != nil {
+		t.Fatalf("failed to parse reference: %v", err)
+	}
+	resolved, err := corpus.Resolve(ref)
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+	if len(resolved.Verses) != 1 || resolved.Verses[0].V != 16 {
+		t.Errorf("unexpected verses: %+v", resolved.Verses)
+	}
+
+	// Locales are imported from index/locales/
+	localized, err := kjvcorpus.Open("", kjvcorpus.WithStore(store), kjvcorpus.WithLocale("es"))
+	if err != nil {
+		t.Fatalf("failed to open corpus with locale: %v", err)
+	}
+	if name, _ := localized.BookName("John"); name != "Juan" {
+		t.Errorf("expected Juan, got %q", name)
+	}
+
+	if _, err := store.ReadChapter("Gen", 51); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected fs.ErrNotExist for a missing chapter, got %v", err)
+	}
+	if _, err := corpus.BookIntro("Gen"); !errors.Is(err, kjvcorpus.ErrIntroNotFound) {
+		t.Errorf("expected ErrIntroNotFound, got %v", err)
+	}
+}
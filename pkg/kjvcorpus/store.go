@@ -0,0 +1,131 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// ChapterStore provides the raw canonical JSON documents a Corpus is built from.
+// Implementations must be safe for concurrent use. A document that does not exist must be
+// reported with an error that wraps fs.ErrNotExist.
+type ChapterStore interface {
+	// ReadIndex returns an index document such as "books.json"
+	ReadIndex(name string) ([]byte, error)
+	// ReadChapter returns the chapter JSON for a book and chapter number, which may be compressed
+	// (see package compression)
+	ReadChapter(osis string, chapter int) ([]byte, error)
+	// ReadIntro returns the introduction JSON for a book
+	ReadIntro(osis string) ([]byte, error)
+}
+
+// AlignmentStore is implemented by stores that can also provide alignment sidecars. Corpora over
+// stores that do not implement it have no alignments.
+type AlignmentStore interface {
+	// ReadAlignment returns the alignment JSON for a book and chapter number
+	ReadAlignment(osis string, chapter int) ([]byte, error)
+}
+
+// EditionStore is implemented by stores that can also provide edition layers. Corpora over stores
+// that do not implement it have only the canon's own edition.
+type EditionStore interface {
+	// ReadEdition returns the edition layer JSON for an edition, book, and chapter number
+	ReadEdition(edition, osis string, chapter int) ([]byte, error)
+}
+
+// ObjectStore is implemented by stores that can also provide the objects of a canon written in the
+// content-addressed layout, which index/objects.json names (see model.ObjectIndex). Corpora over
+// stores that do not implement it read chapters with ReadChapter only.
+type ObjectStore interface {
+	// ReadObject returns the object with a name listed in index/objects.json
+	ReadObject(name string) ([]byte, error)
+}
+
+// FSStore reads the canon layout (index/*.json, books/{OSIS}/chNN.json, books/{OSIS}/intro.json)
+// from an fs.FS, so it can be backed by a directory, an embedded filesystem, or a packed zip archive
+type FSStore struct {
+	fsys fs.FS
+}
+
+// NewFSStore creates a store over fsys, whose root holds index/ and books/
+func NewFSStore(fsys fs.FS) *FSStore {
+	return &FSStore{fsys: fsys}
+}
+
+// NewDirStore creates a store over a canon directory on disk such as canon/kjv
+func NewDirStore(root string) *FSStore {
+	return NewFSStore(os.DirFS(root))
+}
+
+// ReadIndex reads index/{name}
+func (s *FSStore) ReadIndex(name string) ([]byte, error) {
+	return fs.ReadFile(s.fsys, path.Join("index", name))
+}
+
+// ReadChapter reads books/{osis}/chNN.json, or chNN.json.zst or chNN.json.gz in a compressed canon
+func (s *FSStore) ReadChapter(osis string, chapter int) ([]byte, error) {
+	var err error
+	for _, name := range ChapterPaths(osis, chapter) {
+		var data []byte
+		if data, err = fs.ReadFile(s.fsys, name); !errors.Is(err, fs.ErrNotExist) {
+			return data, err
+		}
+	}
+	return nil, err
+}
+
+// ReadObject reads objects/{hh}/{name}
+func (s *FSStore) ReadObject(name string) ([]byte, error) {
+	return fs.ReadFile(s.fsys, model.ObjectPath(name))
+}
+
+// ReadRelease reads a release file such as SHA256SUMS from the root of fsys
+func (s *FSStore) ReadRelease(name string) ([]byte, error) {
+	return fs.ReadFile(s.fsys, name)
+}
+
+// ReadIntro reads books/{osis}/intro.json
+func (s *FSStore) ReadIntro(osis string) ([]byte, error) {
+	return fs.ReadFile(s.fsys, path.Join("books", osis, "intro.json"))
+}
+
+// ReadAlignment reads align/{osis}/chNN.json
+func (s *FSStore) ReadAlignment(osis string, chapter int) ([]byte, error) {
+	return fs.ReadFile(s.fsys, AlignmentPath(osis, chapter))
+}
+
+// ReadEdition reads editions/{edition}/{osis}/chNN.json
+func (s *FSStore) ReadEdition(edition, osis string, chapter int) ([]byte, error) {
+	return fs.ReadFile(s.fsys, EditionPath(edition, osis, chapter))
+}
+
+// ChapterPath returns the slash-separated path of a chapter file relative to the canon root
+func ChapterPath(osis string, chapter int) string {
+	return path.Join("books", osis, fmt.Sprintf("ch%02d.json", chapter))
+}
+
+// ChapterPaths returns the paths a chapter file may have relative to the canon root, ChapterPath
+// followed by the names it has when compressed, in the order stores look for them
+func ChapterPaths(osis string, chapter int) []string {
+	plain := ChapterPath(osis, chapter)
+	paths := []string{plain}
+	for _, codec := range compression.Codecs {
+		paths = append(paths, plain+codec.Ext())
+	}
+	return paths
+}
+
+// AlignmentPath returns the slash-separated path of an alignment sidecar relative to the canon root
+func AlignmentPath(osis string, chapter int) string {
+	return path.Join("align", osis, fmt.Sprintf("ch%02d.json", chapter))
+}
+
+// EditionPath returns the slash-separated path of an edition layer relative to the canon root
+func EditionPath(edition, osis string, chapter int) string {
+	return path.Join("editions", edition, osis, fmt.Sprintf("ch%02d.json", chapter))
+}
@@ -0,0 +1,278 @@
+package kjvcorpus
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+func TestFSStore(t *testing.T) {
+	books := `{"schema":1,"work":"KJV","books":[{"osis":"Obad","abbr":"OBA","name":"Obadiah",` +
+		`"aliases":["Obadiah"],"testament":"OT","order":1,"chapters":1}]}`
+	chapter := `{"schema":1,"work":"KJV","osis":"Obad","abbr":"OBA","chapter":1,` +
+		`"verses":[{"v":1,"tokens":[{"t":"The vision of Obadiah."}]}]}`
+
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for name, content := range map[string]string{
+		"index/books.json":     books,
+		"books/Obad/ch01.json": chapter,
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create zip entry: %v", err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write zip entry: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatalf("failed to open zip: %v", err)
+	}
+
+	stores := map[string]ChapterStore{
+		"map": NewFSStore(fstest.MapFS{
+			"index/books.json":     {Data: []byte(books)},
+			"books/Obad/ch01.json": {Data: []byte(chapter)},
+		}),
+		"zip": NewFSStore(zr),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			corpus, err := Open("", WithStore(store))
+			if err != nil {
+				t.Fatalf("failed to open corpus: %v", err)
+			}
+
+			ch, err := corpus.loadChapter("Obad", 1)
+			if err != nil {
+				t.Fatalf("failed to load chapter: %v", err)
+			}
+			if len(ch.Verses) != 1 || ch.Verses[0].Tokens[0].Text != "The vision of Obadiah." {
+				t.Errorf("unexpected chapter: %+v", ch)
+			}
+
+			if _, err := corpus.BookIntro("Obad"); !errors.Is(err, ErrIntroNotFound) {
+				t.Errorf("expected ErrIntroNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCompressedChapters(t *testing.T) {
+	books := `{"schema":1,"work":"KJV","books":[{"osis":"Obad","abbr":"OBA","name":"Obadiah",` +
+		`"aliases":["Obadiah"],"testament":"OT","order":1,"chapters":1}]}`
+	chapter := `{"schema":1,"work":"KJV","osis":"Obad","abbr":"OBA","chapter":1,` +
+		`"verses":[{"v":1,"tokens":[{"t":"The vision of Obadiah."}]}]}`
+
+	for _, codec := range compression.Codecs {
+		t.Run(string(codec), func(t *testing.T) {
+			data, err := codec.Compress([]byte(chapter))
+			if err != nil {
+				t.Fatal(err)
+			}
+			fsys := fstest.MapFS{
+				"index/books.json":                   {Data: []byte(books)},
+				"books/Obad/ch01.json" + codec.Ext(): {Data: data},
+			}
+
+			var packed bytes.Buffer
+			if count, err := Pack(&packed, fsys); err != nil || count != 2 {
+				t.Fatalf("expected the compressed chapter to be packed, got %d documents, %v", count, err)
+			}
+
+			for name, open := range map[string]func() (*Corpus, error){
+				"fs":     func() (*Corpus, error) { return OpenFS(fsys, WithStrictScan()) },
+				"packed": func() (*Corpus, error) { return OpenPacked(packed.Bytes(), WithStrictScan()) },
+			} {
+				corpus, err := open()
+				if err != nil {
+					t.Fatalf("%s: failed to open corpus: %v", name, err)
+				}
+				ch, err := corpus.loadChapter("Obad", 1)
+				if err != nil {
+					t.Fatalf("%s: failed to load chapter: %v", name, err)
+				}
+				if ch.Verses[0].Tokens[0].Text != "The vision of Obadiah." {
+					t.Errorf("%s: unexpected chapter: %+v", name, ch)
+				}
+			}
+		})
+	}
+
+	corrupt := fstest.MapFS{
+		"index/books.json":         {Data: []byte(books)},
+		"books/Obad/ch01.json.zst": {Data: append([]byte{0x28, 0xb5, 0x2f, 0xfd}, "not zstd"...)},
+	}
+	corpus, err := OpenFS(corrupt)
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	var corpusErr *CorpusError
+	if _, err := corpus.loadChapter("Obad", 1); !errors.As(err, &corpusErr) || corpusErr.Kind != ParseError {
+		t.Errorf("expected a parse error for a corrupt chapter, got %v", err)
+	}
+}
+
+func TestObjectStore(t *testing.T) {
+	books := `{"schema":1,"work":"KJV","books":[{"osis":"Obad","abbr":"OBA","name":"Obadiah",` +
+		`"aliases":["Obadiah"],"testament":"OT","order":1,"chapters":1}]}`
+	chapter := []byte(`{"schema":1,"work":"KJV","osis":"Obad","abbr":"OBA","chapter":1,` +
+		`"verses":[{"v":1,"tokens":[{"t":"The vision of Obadiah."}]}]}`)
+
+	name := model.ObjectName(chapter, "")
+	objects := model.NewObjectIndex("KJV")
+	objects.Chapters[model.ObjectKey("Obad", 1)] = name
+	index, err := json.Marshal(objects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{
+		"index/books.json":         {Data: []byte(books)},
+		"index/objects.json":       {Data: index},
+		model.ObjectPath(name):     {Data: chapter},
+		"objects/00/unlisted.json": {Data: []byte("{}")},
+	}
+
+	var packed bytes.Buffer
+	if count, err := Pack(&packed, fsys); err != nil || count != 4 {
+		t.Fatalf("expected the objects to be packed, got %d documents, %v", count, err)
+	}
+
+	for name, open := range map[string]func() (*Corpus, error){
+		"fs":     func() (*Corpus, error) { return OpenFS(fsys, WithStrictScan()) },
+		"packed": func() (*Corpus, error) { return OpenPacked(packed.Bytes(), WithStrictScan()) },
+	} {
+		corpus, err := open()
+		if err != nil {
+			t.Fatalf("%s: failed to open corpus: %v", name, err)
+		}
+		ch, err := corpus.loadChapter("Obad", 1)
+		if err != nil {
+			t.Fatalf("%s: failed to load chapter: %v", name, err)
+		}
+		if ch.Verses[0].Tokens[0].Text != "The vision of Obadiah." {
+			t.Errorf("%s: unexpected chapter: %+v", name, ch)
+		}
+	}
+
+	// A file in books/ takes precedence over the chapter's object
+	fsys["books/Obad/ch01.json"] = &fstest.MapFile{Data: bytes.Replace(chapter, []byte("vision"), []byte("word"), 1)}
+	corpus, err := OpenFS(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch, err := corpus.loadChapter("Obad", 1); err != nil || ch.Verses[0].Tokens[0].Text != "The word of Obadiah." {
+		t.Errorf("expected the chapter in books/ to be read, got %v", err)
+	}
+}
+
+func TestOpenWithoutStoreRequiresRoot(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "missing"))
+	if !errors.Is(err, ErrInvalidRoot) {
+		t.Errorf("expected ErrInvalidRoot, got %v", err)
+	}
+}
+
+func TestPack(t *testing.T) {
+	corpus := openCanon(t)
+	fsys := os.DirFS(corpus.root)
+
+	var packed bytes.Buffer
+	count, err := Pack(&packed, fsys)
+	if err != nil {
+		t.Fatalf("Pack failed: %v", err)
+	}
+	if count < corpus.Table().ByOsis["Gen"].Chapters {
+		t.Fatalf("expected the whole canon to be packed, got %d documents", count)
+	}
+	var again bytes.Buffer
+	if _, err := Pack(&again, fsys); err != nil || !bytes.Equal(packed.Bytes(), again.Bytes()) {
+		t.Errorf("expected packs of the same canon to be identical, got %v", err)
+	}
+
+	for name, open := range map[string]func() (*Corpus, error){
+		"packed": func() (*Corpus, error) { return OpenPacked(packed.Bytes()) },
+		"fs":     func() (*Corpus, error) { return OpenFS(fsys) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, err := open()
+			if err != nil {
+				t.Fatalf("failed to open: %v", err)
+			}
+			resolved, err := c.ResolveRef(Ref{OSIS: "John", Chapter: 3, Verses: &VerseRange{Start: 16}})
+			if err != nil || resolved.Citation() != "John 3:16 (KJV)" {
+				t.Errorf("expected to resolve John 3:16, got %v", err)
+			}
+			if last, err := c.LastVerse("Obad", 1); err != nil || last != 21 {
+				t.Errorf("expected the index to be packed, got %d, %v", last, err)
+			}
+		})
+	}
+
+	if _, err := OpenPacked([]byte("not a zip")); !errors.Is(err, ErrInvalidRoot) {
+		t.Errorf("expected ErrInvalidRoot for a bad pack, got %v", err)
+	}
+	if _, err := Pack(&bytes.Buffer{}, fstest.MapFS{}); err == nil {
+		t.Error("expected a canon without index/ to fail")
+	}
+}
+
+func TestPackBooks(t *testing.T) {
+	corpus := openCanon(t)
+	fsys := os.DirFS(corpus.root)
+
+	var packed bytes.Buffer
+	count, err := PackBooks(&packed, fsys, testament.Books(testament.NT))
+	if err != nil {
+		t.Fatalf("PackBooks failed: %v", err)
+	}
+	var whole bytes.Buffer
+	if all, err := Pack(&whole, fsys); err != nil || count >= all || packed.Len() >= whole.Len() {
+		t.Errorf("expected the New Testament to pack smaller than the canon: %d of %d documents, %v", count, all, err)
+	}
+
+	c, err := OpenPacked(packed.Bytes(), WithStrictScan(), WithLocale("es"))
+	if err != nil {
+		t.Fatalf("failed to open the New Testament: %v", err)
+	}
+	if books := c.BooksIn(); len(books) != 27 || books[0].OSIS != "Matt" || books[26].OSIS != "Rev" {
+		t.Errorf("expected the 27 books of the New Testament, got %d", len(books))
+	}
+	if resolved, err := c.ResolveRef(Ref{OSIS: "John", Chapter: 3, Verses: &VerseRange{Start: 16}}); err != nil || resolved.Citation() != "Juan 3:16 (KJV)" {
+		t.Errorf("expected to resolve John 3:16, got %v", err)
+	}
+	if _, err := c.ResolveRef(Ref{OSIS: "Gen", Chapter: 1}); !errors.Is(err, ErrUnknownBook) {
+		t.Errorf("expected Genesis to be unknown, got %v", err)
+	}
+	if last, err := c.LastVerse("Rev", 22); err != nil || last != 21 {
+		t.Errorf("expected the verse index of Revelation, got %d, %v", last, err)
+	}
+	if order, err := c.ChronologicalOrder(); err != nil || len(order) != 260 {
+		t.Errorf("expected the chronology of the 260 chapters, got %d, %v", len(order), err)
+	}
+	if _, err := c.Topic("faith"); err != nil {
+		t.Errorf("expected the New Testament passages of a topic to resolve, got %v", err)
+	}
+
+	if _, err := PackBooks(&bytes.Buffer{}, fsys, []string{"Matt", "Thomas"}); err == nil {
+		t.Error("expected a book not in the canon to fail")
+	}
+	if _, err := PackBooks(&bytes.Buffer{}, fsys, nil); err == nil {
+		t.Error("expected packing no books to fail")
+	}
+}
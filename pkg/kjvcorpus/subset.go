@@ -0,0 +1,265 @@
+package kjvcorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// subset is the part of a canon PackBooks keeps: the documents of some of its books, and its index
+// files rewritten to list only those books
+type subset struct {
+	books   map[string]bool   // OSIS IDs of the books kept
+	objects map[string]bool   // names of the objects holding their chapters
+	index   map[string][]byte // rewritten index files by path, such as "index/books.json"
+}
+
+// newSubset reads the index files of fsys and rewrites those that list books to list only the
+// given ones. Index files it does not know, such as normalization.json, are kept as they are.
+func newSubset(fsys fs.FS, books []string) (*subset, error) {
+	if len(books) == 0 {
+		return nil, fmt.Errorf("no books to pack")
+	}
+	data, err := fs.ReadFile(fsys, "index/books.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read books.json: %w", err)
+	}
+	var booksData model.BooksData
+	if err := json.Unmarshal(data, &booksData); err != nil {
+		return nil, fmt.Errorf("failed to parse books.json: %w", err)
+	}
+
+	// Topics name their passages as people write them, so they are parsed against every book
+	table, err := subsetTable(booksData.Books)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &subset{books: make(map[string]bool, len(books)), objects: make(map[string]bool), index: make(map[string][]byte)}
+	wanted := make(map[string]bool, len(books))
+	for _, osis := range books {
+		wanted[model.CanonicalOSIS(osis)] = true
+	}
+	booksData.Books = slices.DeleteFunc(booksData.Books, func(book model.BookMetadata) bool {
+		id := model.CanonicalOSIS(book.OSIS)
+		if wanted[id] {
+			s.books[book.OSIS] = true
+			delete(wanted, id)
+		}
+		return !s.books[book.OSIS]
+	})
+	if len(wanted) > 0 {
+		missing := slices.Sorted(maps.Keys(wanted))
+		return nil, fmt.Errorf("books not in the canon: %s", strings.Join(missing, ", "))
+	}
+	if err := s.setIndex("books.json", booksData); err != nil {
+		return nil, err
+	}
+
+	// objects.json comes first, as filemap.json of a content-addressed canon lists objects
+	steps := []func() error{
+		func() error {
+			var objects model.ObjectIndex
+			return s.rewrite(fsys, "objects.json", &objects, func() {
+				maps.DeleteFunc(objects.Chapters, func(key, name string) bool {
+					osis := key[:max(strings.LastIndex(key, "."), 0)]
+					if !s.books[osis] {
+						return true
+					}
+					s.objects[name] = true
+					return false
+				})
+			})
+		},
+		func() error {
+			// ParseFileMap upgrades a legacy filemap.json, which rewrite could not read
+			data, err := fs.ReadFile(fsys, "index/filemap.json")
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("failed to read filemap.json: %w", err)
+			}
+			fileMap, err := model.ParseFileMap(data)
+			if err != nil {
+				return err
+			}
+			maps.DeleteFunc(fileMap.Files, func(_ string, entry model.FileMapEntry) bool {
+				return !s.keeps(filepath.ToSlash(entry.Output))
+			})
+			return s.setIndex("filemap.json", fileMap)
+		},
+		func() error {
+			var osis map[string]string
+			return s.rewrite(fsys, "osis.json", &osis, func() { deleteBooks(s, osis) })
+		},
+		func() error {
+			var aliases model.AliasesData
+			return s.rewrite(fsys, "aliases.json", &aliases, func() { deleteBooks(s, aliases) })
+		},
+		func() error {
+			var verses model.VerseIndex
+			return s.rewrite(fsys, "verses.json", &verses, func() {
+				deleteBooks(s, verses.Books)
+				deleteBooks(s, verses.Maps)
+			})
+		},
+		func() error {
+			var chronology model.Chronology
+			return s.rewrite(fsys, "chronological.json", &chronology, func() {
+				chronology.Passages = slices.DeleteFunc(chronology.Passages, func(span model.ChapterSpan) bool {
+					return !s.books[span.OSIS]
+				})
+			})
+		},
+		func() error {
+			var parallels model.Parallels
+			return s.rewrite(fsys, "parallels.json", &parallels, func() {
+				parallels.Pairs = slices.DeleteFunc(parallels.Pairs, func(pair model.ParallelPair) bool {
+					return !s.books[pair.A.OSIS] || !s.books[pair.B.OSIS]
+				})
+			})
+		},
+		func() error {
+			var versification model.Versification
+			return s.rewrite(fsys, "versification.json", &versification, func() {
+				for name, scheme := range versification.Schemes {
+					scheme.Mappings = slices.DeleteFunc(scheme.Mappings, func(m model.VersificationMapping) bool {
+						return !s.books[m.OSIS]
+					})
+					versification.Schemes[name] = scheme
+				}
+			})
+		},
+		func() error {
+			var topics model.Topics
+			return s.rewrite(fsys, "topics.json", &topics, func() {
+				for key, topic := range topics.Topics {
+					// A reference that does not parse is kept, to fail as it would have in the full canon
+					topic.Refs = slices.DeleteFunc(topic.Refs, func(ref string) bool {
+						parsed, err := bibleref.Parse(ref, table)
+						return err == nil && !s.books[parsed.OSIS]
+					})
+					if len(topic.Refs) == 0 {
+						delete(topics.Topics, key)
+					} else {
+						topics.Topics[key] = topic
+					}
+				}
+			})
+		},
+		func() error {
+			entries, err := fs.ReadDir(fsys, "index/locales")
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("failed to read locales: %w", err)
+			}
+			for _, entry := range entries {
+				if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+					continue
+				}
+				var locale model.Locale
+				if err := s.rewrite(fsys, "locales/"+entry.Name(), &locale, func() { deleteBooks(s, locale.Books) }); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// subsetTable builds the books table of books.json, as loadSnapshot does
+func subsetTable(books []model.BookMetadata) (*bibleref.Table, error) {
+	biblerefBooks := make([]bibleref.Book, len(books))
+	for i, book := range books {
+		biblerefBooks[i] = bibleref.Book{
+			OSIS:      book.OSIS,
+			Name:      book.Name,
+			Aliases:   book.Aliases,
+			Testament: book.Testament,
+			Order:     book.Order,
+			Chapters:  book.Chapters,
+		}
+	}
+	table, err := bibleref.NewTable(biblerefBooks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bibleref table: %w", err)
+	}
+	addBookVariants(table, books)
+	return table, nil
+}
+
+// rewrite reads index/{name} into doc, lets edit drop the books not kept, and records the result.
+// A canon without the file is left without it.
+func (s *subset) rewrite(fsys fs.FS, name string, doc any, edit func()) error {
+	data, err := fs.ReadFile(fsys, path.Join("index", name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	edit()
+	return s.setIndex(name, doc)
+}
+
+// setIndex records the rewritten index/{name}, indented as the canon writes its index files
+func (s *subset) setIndex(name string, doc any) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	s.index[path.Join("index", name)] = append(data, '\n')
+	return nil
+}
+
+// deleteBooks deletes the entries of the books not kept from a map keyed by OSIS ID
+func deleteBooks[V any](s *subset, m map[string]V) {
+	maps.DeleteFunc(m, func(osis string, _ V) bool { return !s.books[osis] })
+}
+
+// document returns the rewritten contents of an index file, or false for any other document
+func (s *subset) document(name string) ([]byte, bool) {
+	if s == nil {
+		return nil, false
+	}
+	data, ok := s.index[name]
+	return data, ok
+}
+
+// keeps reports whether the document at a slash-separated path of the canon belongs to a kept
+// book: books/{OSIS}/..., align/{OSIS}/..., editions/{edition}/{OSIS}/..., or one of their
+// objects. Every other document is kept.
+func (s *subset) keeps(name string) bool {
+	if s == nil {
+		return true
+	}
+	parts := strings.Split(name, "/")
+	switch {
+	case (parts[0] == "books" || parts[0] == "align") && len(parts) > 2:
+		return s.books[parts[1]]
+	case parts[0] == "editions" && len(parts) > 3:
+		return s.books[parts[2]]
+	case parts[0] == "objects" && len(parts) > 1:
+		return s.objects[path.Base(name)]
+	}
+	return true
+}
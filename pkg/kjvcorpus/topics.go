@@ -0,0 +1,111 @@
+package kjvcorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Topics returns the identifiers of the topics in index/topics.json, sorted. A canon without
+// topics.json has none.
+func (c *Corpus) Topics() ([]string, error) {
+	topics, err := c.snap.Load().loadTopics(c.store)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(topics.Topics))
+	for name := range topics.Topics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Topic resolves every reference listed under a topic in index/topics.json, in the order they
+// are listed. Topic identifiers are matched case-insensitively.
+func (c *Corpus) Topic(name string) ([]*Resolved, error) {
+	snap := c.snap.Load()
+	topics, err := snap.loadTopics(c.store)
+	if err != nil {
+		return nil, err
+	}
+
+	topic, exists := topics.Topics[strings.ToLower(strings.TrimSpace(name))]
+	if !exists {
+		msg := fmt.Sprintf("unknown topic: %s", name)
+		return nil, &CorpusError{
+			Kind:    RangeError,
+			Message: &msg,
+			Err:     ErrUnknownTopic,
+		}
+	}
+
+	resolved := make([]*Resolved, 0, len(topic.Refs))
+	for _, s := range topic.Refs {
+		ref, err := bibleref.Parse(s, snap.books)
+		if err != nil {
+			msg := fmt.Sprintf("topic %s lists an invalid reference: %s", name, s)
+			return nil, &CorpusError{
+				Kind:    ParseError,
+				Message: &msg,
+				Err:     err,
+			}
+		}
+		r, err := c.Resolve(ref)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, r)
+	}
+	return resolved, nil
+}
+
+// loadTopics loads topics.json into the snapshot on first use
+func (s *snapshot) loadTopics(store ChapterStore) (*model.Topics, error) {
+	s.mu.RLock()
+	if s.topics != nil {
+		s.mu.RUnlock()
+		return s.topics, nil
+	}
+	s.mu.RUnlock()
+
+	topics := &model.Topics{Schema: model.TopicsSchema}
+	data, err := store.ReadIndex("topics.json")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, &CorpusError{
+			Kind: FileError,
+			Err:  fmt.Errorf("failed to read topics.json: %w", err),
+		}
+	default:
+		if err := json.Unmarshal(data, topics); err != nil {
+			return nil, &CorpusError{
+				Kind: ParseError,
+				Err:  fmt.Errorf("failed to parse topics.json: %w", err),
+			}
+		}
+		if topics.Schema != model.TopicsSchema {
+			return nil, &CorpusError{
+				Kind: ParseError,
+				Err:  fmt.Errorf("unsupported topics.json schema version %d", topics.Schema),
+			}
+		}
+	}
+
+	s.mu.Lock()
+	if s.topics == nil {
+		s.topics = topics
+	}
+	topics = s.topics
+	s.mu.Unlock()
+	return topics, nil
+}
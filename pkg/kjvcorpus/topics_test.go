@@ -0,0 +1,41 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTopic(t *testing.T) {
+	corpus := openCanon(t)
+
+	names, err := corpus.Topics()
+	if err != nil {
+		t.Fatalf("Topics failed: %v", err)
+	}
+	if len(names) == 0 || names[0] != "creation" {
+		t.Errorf("expected sorted topics starting with creation, got %v", names)
+	}
+
+	for _, name := range names {
+		resolved, err := corpus.Topic(name)
+		if err != nil {
+			t.Errorf("topic %s: %v", name, err)
+			continue
+		}
+		if len(resolved) == 0 {
+			t.Errorf("topic %s resolved no references", name)
+		}
+	}
+
+	love, err := corpus.Topic(" Love ")
+	if err != nil {
+		t.Fatalf("Topic failed: %v", err)
+	}
+	if love[0].Citation() != "John 3:16 (KJV)" || love[3].Reference() != "1 Corinthians 13:4–7" {
+		t.Errorf("unexpected love references: %s, %s", love[0].Citation(), love[3].Reference())
+	}
+
+	if _, err := corpus.Topic("nope"); !errors.Is(err, ErrUnknownTopic) {
+		t.Errorf("expected ErrUnknownTopic, got %v", err)
+	}
+}
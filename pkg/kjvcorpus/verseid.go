@@ -0,0 +1,170 @@
+package kjvcorpus
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+// verseIDTable numbers every verse in index/verses.json for VerseID and RefFromID
+type verseIDTable struct {
+	chapters  []verseIDChapter   // in verse ID order
+	byChapter map[chapterKey]int // chapter -> index into chapters
+	total     int                // highest verse ID
+}
+
+// verseIDChapter is a chapter's run of verse IDs
+type verseIDChapter struct {
+	key    chapterKey
+	first  int   // verse ID of the chapter's first verse
+	count  int   // number of verses
+	verses []int // verse numbers from the chapter's verse map, nil when they run from 1 to count
+}
+
+// VerseID returns the absolute verse ID of the first verse of ref, a number from 1 that counts
+// every verse of the canon: the Old Testament, then the New, then the Apocrypha, each book in
+// books.json order. Genesis 1:1 is 1, Revelation 22:21 is 31102, and the Apocrypha follows, so IDs
+// of the protocanonical books match the common 31,102-verse numbering. A whole chapter or a range
+// gives the ID of its first verse.
+//
+// IDs are derived from index/verses.json and stay the same as long as ingest produces the same
+// chapters. A canon without verses.json fails with ErrNoVerseIndex.
+func (c *Corpus) VerseID(ref Ref) (int, error) {
+	snap := c.snap.Load()
+	osis := snap.bookID(ref.OSIS)
+	chapter := max(ref.Chapter, 1)
+	book, err := snap.checkChapter(osis, chapter)
+	if err != nil {
+		return 0, err
+	}
+	table, err := snap.loadVerseIDs(c.store)
+	if err != nil {
+		return 0, err
+	}
+
+	i, exists := table.byChapter[chapterKey{osis: osis, chapter: chapter}]
+	if !exists {
+		msg := fmt.Sprintf("chapter %d of %s is not in verses.json", chapter, book.Name)
+		return 0, &CorpusError{
+			Kind:    RangeError,
+			Message: &msg,
+			Err:     ErrChapterNotFound,
+		}
+	}
+	ch := table.chapters[i]
+	if ref.Verses == nil {
+		return ch.first, nil
+	}
+
+	offset := ref.Verses.Start - 1
+	if ch.verses != nil {
+		offset = slices.Index(ch.verses, ref.Verses.Start)
+	}
+	if offset < 0 || offset >= ch.count {
+		msg := fmt.Sprintf("verse %d out of range for %s %d", ref.Verses.Start, book.Name, chapter)
+		return 0, &CorpusError{
+			Kind:    RangeError,
+			Message: &msg,
+			Err:     ErrVerseOutOfRange,
+		}
+	}
+	return ch.first + offset, nil
+}
+
+// RefFromID returns a reference to the verse with the given absolute verse ID, as VerseID numbers
+// them. An ID beyond the last verse fails with ErrVerseOutOfRange.
+func (c *Corpus) RefFromID(id int) (Ref, error) {
+	table, err := c.snap.Load().loadVerseIDs(c.store)
+	if err != nil {
+		return Ref{}, err
+	}
+	if id < 1 || id > table.total {
+		msg := fmt.Sprintf("verse ID %d out of range (1-%d)", id, table.total)
+		return Ref{}, &CorpusError{
+			Kind:    RangeError,
+			Message: &msg,
+			Err:     ErrVerseOutOfRange,
+		}
+	}
+
+	// The last chapter whose first verse is at or before id holds it
+	i := sort.Search(len(table.chapters), func(i int) bool { return table.chapters[i].first > id }) - 1
+	ch := table.chapters[i]
+	verse := id - ch.first + 1
+	if ch.verses != nil {
+		verse = ch.verses[id-ch.first]
+	}
+	return Ref{OSIS: ch.key.osis, Chapter: ch.key.chapter, Verses: &VerseRange{Start: verse}}, nil
+}
+
+// loadVerseIDs numbers the verses of verses.json into the snapshot on first use
+func (s *snapshot) loadVerseIDs(store ChapterStore) (*verseIDTable, error) {
+	s.mu.RLock()
+	if s.verseIDs != nil {
+		s.mu.RUnlock()
+		return s.verseIDs, nil
+	}
+	s.mu.RUnlock()
+
+	verses, err := s.loadVerseIndex(store)
+	if err != nil {
+		return nil, err
+	}
+	if len(verses.Books) == 0 {
+		return nil, &CorpusError{
+			Kind: FileError,
+			Err:  ErrNoVerseIndex,
+		}
+	}
+
+	// Old Testament, New Testament, then Apocrypha, each in books.json order
+	rank := map[testament.Testament]int{testament.OT: 0, testament.NT: 1, testament.AP: 2}
+	books := make([]string, 0, len(s.booksByID))
+	for osis := range s.booksByID {
+		books = append(books, osis)
+	}
+	sectionOf := func(osis string) int {
+		t, ok := testament.Of(osis)
+		if !ok {
+			t = testament.Testament(s.booksByID[osis].Testament)
+		}
+		if r, known := rank[t]; known {
+			return r
+		}
+		return len(rank)
+	}
+	sort.Slice(books, func(i, j int) bool {
+		if a, b := sectionOf(books[i]), sectionOf(books[j]); a != b {
+			return a < b
+		}
+		return s.booksByID[books[i]].Order < s.booksByID[books[j]].Order
+	})
+
+	table := &verseIDTable{byChapter: make(map[chapterKey]int)}
+	for _, osis := range books {
+		for chapter := 1; chapter <= s.booksByID[osis].Chapters; chapter++ {
+			last, ok := verses.LastVerse(osis, chapter)
+			if !ok {
+				continue
+			}
+			ch := verseIDChapter{key: chapterKey{osis: osis, chapter: chapter}, first: table.total + 1, count: last}
+			if verseMap, mapped := verses.VerseMap(osis, chapter); mapped {
+				ch.verses = verseMap
+				ch.count = len(verseMap)
+			}
+			table.byChapter[ch.key] = len(table.chapters)
+			table.chapters = append(table.chapters, ch)
+			table.total += ch.count
+		}
+	}
+
+	s.mu.Lock()
+	if s.verseIDs == nil {
+		s.verseIDs = table
+	}
+	t := s.verseIDs
+	s.mu.Unlock()
+	return t, nil
+}
@@ -0,0 +1,68 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerseID(t *testing.T) {
+	corpus := openCanon(t)
+
+	for s, want := range map[string]int{
+		"Gen 1:1":   1,
+		"Gen 1":     1,
+		"Gen 1:2-5": 2,
+		"Mal 4:6":   23145,
+		"Matt 1:1":  23146,
+		"John 3:16": 26137,
+		"Rev 22:21": 31102,
+		"Tob 1:1":   31103,
+	} {
+		ref, err := corpus.ParseRef(s)
+		if err != nil {
+			t.Fatalf("ParseRef(%q) failed: %v", s, err)
+		}
+		if id, err := corpus.VerseID(ref); err != nil || id != want {
+			t.Errorf("VerseID(%s) = %d, %v; want %d", s, id, err, want)
+		}
+	}
+
+	// Every ID converts back to the reference it was given for, including AddEsth 10, whose
+	// verse map starts at verse 4
+	table, err := corpus.snap.Load().loadVerseIDs(corpus.store)
+	if err != nil {
+		t.Fatalf("failed to number verses: %v", err)
+	}
+	for id := 1; id <= table.total; id++ {
+		ref, err := corpus.RefFromID(id)
+		if err != nil {
+			t.Fatalf("RefFromID(%d) failed: %v", id, err)
+		}
+		if back, err := corpus.VerseID(ref); err != nil || back != id {
+			t.Fatalf("VerseID(RefFromID(%d) = %s) = %d, %v", id, ref, back, err)
+		}
+	}
+	ref, err := corpus.ParseRef("AddEsth 10:4")
+	if err != nil {
+		t.Fatalf("ParseRef failed: %v", err)
+	}
+	id, err := corpus.VerseID(ref)
+	if err != nil {
+		t.Fatalf("VerseID failed: %v", err)
+	}
+	if prev, _ := corpus.RefFromID(id - 1); prev.String() != "Jdt 16:25" {
+		t.Errorf("expected Jdt 16:25 before AddEsth 10:4, the only chapter of the book ingested, got %s", prev)
+	}
+
+	if _, err := corpus.VerseID(Ref{OSIS: "AddEsth", Chapter: 10, Verses: &VerseRange{Start: 1}}); !errors.Is(err, ErrVerseOutOfRange) {
+		t.Errorf("expected ErrVerseOutOfRange for a verse outside the verse map, got %v", err)
+	}
+	if _, err := corpus.VerseID(Ref{OSIS: "Nope", Chapter: 1}); !errors.Is(err, ErrUnknownBook) {
+		t.Errorf("expected ErrUnknownBook, got %v", err)
+	}
+	for _, id := range []int{0, table.total + 1} {
+		if _, err := corpus.RefFromID(id); !errors.Is(err, ErrVerseOutOfRange) {
+			t.Errorf("RefFromID(%d): expected ErrVerseOutOfRange, got %v", id, err)
+		}
+	}
+}
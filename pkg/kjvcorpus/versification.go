@@ -0,0 +1,258 @@
+package kjvcorpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/julianstephens/canonref/bibleref"
+	"github.com/julianstephens/canonref/util"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// versification is versification.json with its spans parsed
+type versification struct {
+	base    string
+	schemes map[string][]schemeMapping
+}
+
+// schemeMapping is a parsed model.VersificationMapping
+type schemeMapping struct {
+	osis        string
+	base, other span
+}
+
+// span is a range of chapters, or of verses within one chapter when startVerse is set
+type span struct {
+	startChapter, endChapter int
+	startVerse, endVerse     int
+}
+
+// MapRef converts a reference numbered in the from scheme to the to scheme, using the schemes in
+// index/versification.json. The canon's own scheme ("kjv") is always available. A reference to a
+// whole chapter maps to the chapter holding its first verse; a verse range that the schemes split
+// across chapters fails with ErrUnmappableRange.
+func (c *Corpus) MapRef(ref *bibleref.BibleRef, from, to string) (*bibleref.BibleRef, error) {
+	snap := c.snap.Load()
+	osis := snap.bookID(ref.OSIS)
+	if _, exists := snap.booksByID[osis]; !exists {
+		msg := fmt.Sprintf("unknown book: %s", ref.OSIS)
+		return nil, &CorpusError{
+			Kind:    RangeError,
+			Message: &msg,
+			Err:     ErrUnknownBook,
+		}
+	}
+
+	v, err := snap.loadVersification(c.store)
+	if err != nil {
+		return nil, err
+	}
+	for _, scheme := range []string{from, to} {
+		if _, known := v.schemes[scheme]; !known && scheme != v.base {
+			msg := fmt.Sprintf("unknown versification scheme: %s", scheme)
+			return nil, &CorpusError{
+				Kind:    RangeError,
+				Message: &msg,
+				Err:     ErrUnknownScheme,
+			}
+		}
+	}
+
+	point := func(chapter, verse int) (int, int) {
+		chapter, verse = mapPoint(v.schemes[from], osis, chapter, verse, false)
+		return mapPoint(v.schemes[to], osis, chapter, verse, true)
+	}
+
+	mapped := &bibleref.BibleRef{OSIS: ref.OSIS}
+	if ref.Verse == nil {
+		mapped.Chapter, _ = point(ref.Chapter, 1)
+		return mapped, nil
+	}
+
+	chapter, start := point(ref.Chapter, ref.Verse.StartVerse)
+	mapped.Chapter = chapter
+	mapped.Verse = &util.VerseRange{StartVerse: start}
+	if ref.Verse.EndVerse != nil {
+		endChapter, end := point(ref.Chapter, *ref.Verse.EndVerse)
+		if endChapter != chapter {
+			msg := fmt.Sprintf("%s maps to chapters %d and %d of %s", ref.String(), chapter, endChapter, to)
+			return nil, &CorpusError{
+				Kind:    RangeError,
+				Message: &msg,
+				Err:     ErrUnmappableRange,
+			}
+		}
+		mapped.Verse.EndVerse = &end
+	}
+	return mapped, nil
+}
+
+// Schemes returns the names of the versification schemes MapRef accepts, the canon's own first
+func (c *Corpus) Schemes() ([]string, error) {
+	v, err := c.snap.Load().loadVersification(c.store)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{v.base}
+	for name := range v.schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names, nil
+}
+
+// mapPoint maps a chapter and verse between the base scheme and the scheme with the given
+// mappings, towards the other scheme if toOther is set and towards the base otherwise
+func mapPoint(mappings []schemeMapping, osis string, chapter, verse int, toOther bool) (int, int) {
+	for _, m := range mappings {
+		if m.osis != osis {
+			continue
+		}
+		src, dst := m.other, m.base
+		if toOther {
+			src, dst = m.base, m.other
+		}
+		if src.startVerse == 0 {
+			if chapter >= src.startChapter && chapter <= src.endChapter {
+				return dst.startChapter + chapter - src.startChapter, verse
+			}
+			continue
+		}
+		if chapter == src.startChapter && verse >= src.startVerse && verse <= src.endVerse {
+			return dst.startChapter, dst.startVerse + verse - src.startVerse
+		}
+	}
+	return chapter, verse
+}
+
+// loadVersification reads and parses versification.json into the snapshot, once. A canon without
+// one only offers its own scheme.
+func (s *snapshot) loadVersification(store ChapterStore) (*versification, error) {
+	s.mu.RLock()
+	if s.schemes != nil {
+		s.mu.RUnlock()
+		return s.schemes, nil
+	}
+	s.mu.RUnlock()
+
+	v := &versification{base: defaultScheme, schemes: make(map[string][]schemeMapping)}
+	data, err := store.ReadIndex("versification.json")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, &CorpusError{
+			Kind: FileError,
+			Err:  fmt.Errorf("failed to read versification.json: %w", err),
+		}
+	default:
+		if v, err = parseVersification(data); err != nil {
+			return nil, &CorpusError{
+				Kind: ParseError,
+				Err:  fmt.Errorf("failed to parse versification.json: %w", err),
+			}
+		}
+	}
+
+	s.mu.Lock()
+	if s.schemes == nil {
+		s.schemes = v
+	}
+	v = s.schemes
+	s.mu.Unlock()
+	return v, nil
+}
+
+// defaultScheme names the canon's own versification when versification.json does not
+const defaultScheme = "kjv"
+
+// parseVersification parses versification.json and checks that both sides of every mapping are
+// the same length
+func parseVersification(data []byte) (*versification, error) {
+	var doc model.Versification
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Schema != model.VersificationSchema {
+		return nil, fmt.Errorf("unsupported schema version %d", doc.Schema)
+	}
+
+	v := &versification{base: doc.Base, schemes: make(map[string][]schemeMapping, len(doc.Schemes))}
+	if v.base == "" {
+		v.base = defaultScheme
+	}
+	for name, scheme := range doc.Schemes {
+		if name == v.base {
+			return nil, fmt.Errorf("scheme %q is the base scheme", name)
+		}
+		mappings := make([]schemeMapping, 0, len(scheme.Mappings))
+		for _, m := range scheme.Mappings {
+			base, err := parseSpan(m.Base)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", name, m.OSIS, err)
+			}
+			other, err := parseSpan(m.Other)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", name, m.OSIS, err)
+			}
+			if base.length() != other.length() || (base.startVerse == 0) != (other.startVerse == 0) {
+				return nil, fmt.Errorf("%s %s: %s and %s differ in length", name, m.OSIS, m.Base, m.Other)
+			}
+			mappings = append(mappings, schemeMapping{osis: m.OSIS, base: base, other: other})
+		}
+		v.schemes[name] = mappings
+	}
+	return v, nil
+}
+
+// parseSpan parses "C", "C1-C2", "C:V", or "C:V1-V2"
+func parseSpan(s string) (span, error) {
+	chapterPart, versePart, hasVerses := strings.Cut(s, ":")
+	if !hasVerses {
+		start, end, err := parseRange(chapterPart)
+		if err != nil {
+			return span{}, fmt.Errorf("invalid span %q: %w", s, err)
+		}
+		return span{startChapter: start, endChapter: end}, nil
+	}
+
+	chapter, err := strconv.Atoi(chapterPart)
+	if err != nil || chapter < 1 {
+		return span{}, fmt.Errorf("invalid span %q: bad chapter", s)
+	}
+	start, end, err := parseRange(versePart)
+	if err != nil {
+		return span{}, fmt.Errorf("invalid span %q: %w", s, err)
+	}
+	return span{startChapter: chapter, endChapter: chapter, startVerse: start, endVerse: end}, nil
+}
+
+// parseRange parses "N" or "N-M" with 1 <= N <= M
+func parseRange(s string) (int, int, error) {
+	startPart, endPart, isRange := strings.Cut(s, "-")
+	start, err := strconv.Atoi(startPart)
+	if err != nil || start < 1 {
+		return 0, 0, fmt.Errorf("bad number %q", startPart)
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(endPart); err != nil || end < start {
+			return 0, 0, fmt.Errorf("bad range end %q", endPart)
+		}
+	}
+	return start, end, nil
+}
+
+// length returns the number of chapters or verses in a span
+func (s span) length() int {
+	if s.startVerse == 0 {
+		return s.endChapter - s.startChapter + 1
+	}
+	return s.endVerse - s.startVerse + 1
+}
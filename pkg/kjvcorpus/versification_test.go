@@ -0,0 +1,117 @@
+package kjvcorpus
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/julianstephens/canonref/bibleref"
+	"github.com/julianstephens/canonref/util"
+)
+
+func TestMapRef(t *testing.T) {
+	corpus := openCanon(t)
+
+	verse := func(osis string, chapter, start int, end ...int) *bibleref.BibleRef {
+		ref := &bibleref.BibleRef{OSIS: osis, Chapter: chapter, Verse: &util.VerseRange{StartVerse: start}}
+		if len(end) > 0 {
+			ref.Verse.EndVerse = &end[0]
+		}
+		return ref
+	}
+
+	tests := []struct {
+		name     string
+		ref      *bibleref.BibleRef
+		from, to string
+		want     string
+	}{
+		{"psalm chapter offset", verse("Ps", 23, 1, 6), "kjv", "lxx", "Ps 22:1–6"},
+		{"psalm back to kjv", verse("Ps", 22, 1), "lxx", "kjv", "Ps 23:1"},
+		{"split psalm", verse("Ps", 10, 1), "kjv", "lxx", "Ps 9:21"},
+		{"joined psalms", verse("Ps", 113, 9, 12), "lxx", "kjv", "Ps 115:1–4"},
+		{"unchanged psalm", verse("Ps", 1, 1), "kjv", "lxx", "Ps 1:1"},
+		{"whole chapter", &bibleref.BibleRef{OSIS: "Ps", Chapter: 51}, "kjv", "lxx", "Ps 50"},
+		{"malachi 4", verse("Mal", 4, 5), "kjv", "mt", "Mal 3:23"},
+		{"joel 3 to 4", verse("Joel", 3, 1, 21), "kjv", "mt", "Joel 4:1–21"},
+		{"joel 2 tail", verse("Joel", 2, 28), "kjv", "mt", "Joel 3:1"},
+		{"hebrew to kjv", verse("Jonah", 2, 1), "mt", "kjv", "Jonah 1:17"},
+		{"between other schemes", verse("Ps", 22, 1), "lxx", "mt", "Ps 23:1"},
+		{"same scheme", verse("Gen", 1, 1), "kjv", "kjv", "Gen 1:1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := corpus.MapRef(tt.ref, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("MapRef failed: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	errs := []struct {
+		name     string
+		ref      *bibleref.BibleRef
+		from, to string
+		want     error
+	}{
+		{"unknown scheme", verse("Gen", 1, 1), "kjv", "nrsv", ErrUnknownScheme},
+		{"unknown book", verse("Nope", 1, 1), "kjv", "lxx", ErrUnknownBook},
+		{"range across boundary", verse("Joel", 2, 27, 29), "kjv", "mt", ErrUnmappableRange},
+	}
+	for _, tt := range errs {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := corpus.MapRef(tt.ref, tt.from, tt.to); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	schemes, err := corpus.Schemes()
+	if err != nil {
+		t.Fatalf("Schemes failed: %v", err)
+	}
+	if len(schemes) != 3 || schemes[0] != "kjv" || schemes[1] != "lxx" || schemes[2] != "mt" {
+		t.Errorf("expected [kjv lxx mt], got %v", schemes)
+	}
+}
+
+func TestParseVersification(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"schema", `{"schema": 2, "base": "kjv", "schemes": {}}`},
+		{"bad span", `{"schema": 1, "schemes": {"x": {"mappings": [{"osis": "Ps", "base": "10:a", "other": "9:1"}]}}}`},
+		{"length mismatch", `{"schema": 1, "schemes": {"x": {"mappings": [{"osis": "Ps", "base": "10:1-18", "other": "9:21-39"}]}}}`},
+		{"chapter against verses", `{"schema": 1, "schemes": {"x": {"mappings": [{"osis": "Joel", "base": "3", "other": "4:1"}]}}}`},
+		{"base scheme", `{"schema": 1, "base": "kjv", "schemes": {"kjv": {"mappings": []}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseVersification([]byte(tt.data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	// A canon without versification.json only offers its own scheme
+	store := NewFSStore(fstest.MapFS{
+		"index/books.json": &fstest.MapFile{Data: []byte(`{"schema": 1, "work": "KJV", "books": [{"osis": "Gen", "abbr": "GEN", "name": "Genesis", "aliases": ["Genesis"], "testament": "OT", "order": 1, "chapters": 50}]}`)},
+	})
+	corpus, err := Open("", WithStore(store))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	ref := &bibleref.BibleRef{OSIS: "Gen", Chapter: 1}
+	if got, err := corpus.MapRef(ref, "kjv", "kjv"); err != nil || got.String() != "Gen 1" {
+		t.Errorf("expected Gen 1, got %v, %v", got, err)
+	}
+	if _, err := corpus.MapRef(ref, "kjv", "lxx"); !errors.Is(err, ErrUnknownScheme) {
+		t.Errorf("expected ErrUnknownScheme, got %v", err)
+	}
+}
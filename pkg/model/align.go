@@ -0,0 +1,55 @@
+package model
+
+import "strings"
+
+// AlignmentSchema is the current schema version of alignment documents
+const AlignmentSchema = 1
+
+// Alignment is the structure of an alignment sidecar, align/{OSIS}/chNN.json: the original-language
+// words of a chapter, each aligned to the KJV words that translate it
+type Alignment struct {
+	Schema   int              `json:"schema"`
+	OSIS     string           `json:"osis"`
+	Chapter  int              `json:"chapter"`
+	Source   string           `json:"source"`   // where the alignment came from, such as "macula-hebrew"
+	Language string           `json:"language"` // ISO 639-3 code of the original language, such as "hbo" or "grc"
+	Verses   []VerseAlignment `json:"verses"`
+}
+
+// VerseAlignment aligns the original-language words of one verse. KJVWords is the number of KJV
+// words the alignment was made against, so an alignment left behind by a change to the text is
+// detected rather than pointing at the wrong words.
+type VerseAlignment struct {
+	V        int           `json:"v"`
+	KJVWords int           `json:"kjv_words"`
+	Words    []AlignedWord `json:"words"`
+}
+
+// AlignedWord is an original-language word in source order. KJV holds the 0-based positions, in
+// Verse.Words, of the KJV words it is rendered by; it is empty for words the KJV leaves untranslated.
+type AlignedWord struct {
+	Text  string `json:"text"`
+	Lemma string `json:"lemma,omitempty"` // dictionary form or Strong's number, such as "H7225"
+	Morph string `json:"morph,omitempty"` // morphology code in the source's scheme
+	KJV   []int  `json:"kjv"`
+}
+
+// Words splits a verse's tokens into the KJV words alignments refer to: runs of non-space text,
+// punctuation included, with the ¶ paragraph mark dropped. Added words and divine names are
+// words like any other.
+func (v Verse) Words() []string {
+	var text strings.Builder
+	for _, token := range v.Tokens {
+		text.WriteString(token.Text)
+		text.WriteString(token.Add)
+		text.WriteString(token.ND)
+	}
+
+	var words []string
+	for _, word := range strings.Fields(text.String()) {
+		if word != "¶" {
+			words = append(words, strings.TrimPrefix(word, "¶"))
+		}
+	}
+	return words
+}
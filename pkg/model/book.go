@@ -0,0 +1,38 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// BookManifestSchema is the current schema version of books/{OSIS}/book.json
+const BookManifestSchema = 1
+
+// BookManifest is the structure of books/{OSIS}/book.json: the chapter files ingest wrote for one
+// book, in chapter order, so a single book can be verified without walking the whole canon
+type BookManifest struct {
+	Schema   int           `json:"schema"`
+	Work     string        `json:"work"`
+	OSIS     string        `json:"osis"`
+	Chapters []BookChapter `json:"chapters"`
+}
+
+// BookChapter is one chapter file listed in a BookManifest
+type BookChapter struct {
+	Chapter int    `json:"chapter"`
+	File    string `json:"file"`   // file name within the book directory, e.g. ch01.json
+	Verses  int    `json:"verses"` // number of verses in the chapter
+	SHA256  string `json:"sha256"` // checksum of the chapter file
+}
+
+// ParseBookManifest parses book.json
+func ParseBookManifest(data []byte) (BookManifest, error) {
+	var bm BookManifest
+	if err := json.Unmarshal(data, &bm); err != nil {
+		return bm, fmt.Errorf("failed to parse book manifest: %w", err)
+	}
+	if bm.Schema != BookManifestSchema {
+		return bm, fmt.Errorf("unsupported book manifest schema version %d", bm.Schema)
+	}
+	return bm, nil
+}
@@ -0,0 +1,19 @@
+package model
+
+import "sort"
+
+// Verse returns the verse numbered n, if the chapter has one.
+// Verses are expected in ascending order, as ingest writes them; other orders fall back to a scan.
+func (c *Chapter) Verse(n int) (*Verse, bool) {
+	i := sort.Search(len(c.Verses), func(i int) bool { return c.Verses[i].V >= n })
+	if i < len(c.Verses) && c.Verses[i].V == n {
+		return &c.Verses[i], true
+	}
+
+	for i := range c.Verses {
+		if c.Verses[i].V == n {
+			return &c.Verses[i], true
+		}
+	}
+	return nil, false
+}
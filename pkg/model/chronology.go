@@ -0,0 +1,19 @@
+package model
+
+// ChronologySchema is the current schema version of chronological.json
+const ChronologySchema = 1
+
+// Chronology is the structure of chronological.json: every chapter of the canon once, as runs of
+// chapters in the order the events they record took place or the books were written, such as
+// Job after Genesis 11 or each psalm beside the events of its title
+type Chronology struct {
+	Schema   int           `json:"schema"`
+	Passages []ChapterSpan `json:"passages"`
+}
+
+// ChapterSpan is the chapters Start through End, inclusive, of one book
+type ChapterSpan struct {
+	OSIS  string `json:"osis"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
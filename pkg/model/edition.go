@@ -0,0 +1,28 @@
+package model
+
+// EditionSchema is the current schema version of edition layers
+const EditionSchema = 1
+
+// Editions of the KJV text. The canon is the 1769 Oxford text edited by Benjamin Blayney, the one
+// in print today; other editions are stored as layers keyed to its verse structure.
+const (
+	EditionBlayney = "1769"
+	Edition1611    = "1611"
+)
+
+// Edition is the structure of an edition layer, editions/{edition}/{OSIS}/chNN.json: the text of
+// one chapter in another edition of the KJV, such as the original 1611 spellings, verse for verse
+type Edition struct {
+	Schema  int            `json:"schema"`
+	Edition string         `json:"edition"`
+	OSIS    string         `json:"osis"`
+	Chapter int            `json:"chapter"`
+	Source  string         `json:"source"` // where the edition's text came from
+	Verses  []EditionVerse `json:"verses"`
+}
+
+// EditionVerse is the plain text of one verse in an edition
+type EditionVerse struct {
+	V     int    `json:"v"`
+	Plain string `json:"plain"`
+}
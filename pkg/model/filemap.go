@@ -0,0 +1,101 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// FileMapSchema is the current filemap.json schema version. Version 1 was a flat raw -> output string map.
+const FileMapSchema = 2
+
+// FileMap tracks source to output file mappings, keyed by raw path
+type FileMap struct {
+	Schema int                     `json:"schema"`
+	Files  map[string]FileMapEntry `json:"files"`
+}
+
+// FileMapEntry records one raw source file and the output produced from it
+type FileMapEntry struct {
+	Raw          string    `json:"raw"`
+	RawSHA256    string    `json:"raw_sha256"`
+	Output       string    `json:"output"`
+	OutputSHA256 string    `json:"output_sha256"`
+	IngestedAt   time.Time `json:"ingested_at"`
+}
+
+// NewFileMap creates an empty filemap at the current schema version
+func NewFileMap() FileMap {
+	return FileMap{
+		Schema: FileMapSchema,
+		Files:  make(map[string]FileMapEntry),
+	}
+}
+
+// ParseFileMap parses filemap.json, upgrading the legacy flat raw -> output map
+// to the current schema (legacy entries carry no checksums or timestamps)
+func ParseFileMap(data []byte) (FileMap, error) {
+	var fm FileMap
+	if err := json.Unmarshal(data, &fm); err == nil && fm.Schema > 0 {
+		if fm.Schema > FileMapSchema {
+			return fm, fmt.Errorf("unsupported filemap schema version %d", fm.Schema)
+		}
+		if fm.Files == nil {
+			fm.Files = make(map[string]FileMapEntry)
+		}
+		return fm, nil
+	}
+
+	var legacy map[string]string
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return fm, fmt.Errorf("failed to parse filemap: %w", err)
+	}
+
+	fm = NewFileMap()
+	for raw, output := range legacy {
+		fm.Add(FileMapEntry{Raw: raw, Output: output})
+	}
+	return fm, nil
+}
+
+// Add records an entry, replacing any existing entry for the same raw path
+func (fm *FileMap) Add(entry FileMapEntry) {
+	if fm.Files == nil {
+		fm.Files = make(map[string]FileMapEntry)
+	}
+	fm.Files[entry.Raw] = entry
+}
+
+// Merge copies all entries from another filemap, replacing entries for the same raw path
+func (fm *FileMap) Merge(other FileMap) {
+	for _, entry := range other.Files {
+		fm.Add(entry)
+	}
+}
+
+// ByRaw returns the entry for a raw source path
+func (fm FileMap) ByRaw(raw string) (FileMapEntry, bool) {
+	entry, exists := fm.Files[raw]
+	return entry, exists
+}
+
+// ByOutput returns the entry that produced an output path
+func (fm FileMap) ByOutput(output string) (FileMapEntry, bool) {
+	for _, entry := range fm.Files {
+		if entry.Output == output {
+			return entry, true
+		}
+	}
+	return FileMapEntry{}, false
+}
+
+// RawPaths returns the raw paths of all entries in sorted order
+func (fm FileMap) RawPaths() []string {
+	keys := make([]string, 0, len(fm.Files))
+	for k := range fm.Files {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,49 @@
+package model
+
+import "strings"
+
+// footnoteLanguages are the abbreviations that introduce a footnote's literal rendering of the
+// original language, as the 1769 text writes them, with the language each names
+var footnoteLanguages = []struct {
+	prefix   string
+	language string
+}{
+	{"Heb. ", "Hebrew"},
+	{"Heb ", "Hebrew"}, // a few notes leave out the period
+	{"Gr. ", "Greek"},
+	{"Chaldee, ", "Chaldee"},
+}
+
+// FootnoteTerm is the original-language reading a footnote gives for words of the text, parsed
+// from a note such as "equity: Heb. equities"
+type FootnoteTerm struct {
+	Term     string `json:"term"`     // the words of the text the note is on, such as "equity"
+	Language string `json:"language"` // "Hebrew", "Greek", or "Chaldee"
+	Gloss    string `json:"gloss"`    // the literal rendering of the original, such as "equities"
+}
+
+// ParseFootnoteTerm parses the text of an original-language footnote. Alternative renderings may
+// come between the term and the reading, as in "arose: or, continued: Heb. stood". Other notes,
+// such as "tent: or, covering" or "Moses: that is, Drawn out", report false and are best shown as
+// they are.
+func ParseFootnoteTerm(text string) (FootnoteTerm, bool) {
+	term, rest, ok := strings.Cut(text, ": ")
+	if !ok || strings.TrimSpace(term) == "" {
+		return FootnoteTerm{}, false
+	}
+	for {
+		for _, lang := range footnoteLanguages {
+			if gloss, found := strings.CutPrefix(rest, lang.prefix); found && strings.TrimSpace(gloss) != "" {
+				return FootnoteTerm{Term: term, Language: lang.language, Gloss: gloss}, true
+			}
+		}
+		if _, rest, ok = strings.Cut(rest, ": "); !ok {
+			return FootnoteTerm{}, false
+		}
+	}
+}
+
+// Term parses the footnote's text as an original-language note (see ParseFootnoteTerm)
+func (f Footnote) Term() (FootnoteTerm, bool) {
+	return ParseFootnoteTerm(f.Text)
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// LexiconSchema is the current schema version of lexicon.json
+const LexiconSchema = 1
+
+// Lexicon is the structure of lexicon.json: definitions and pronunciations keyed by Strong's
+// number ("H7225", "G3056") or by KJV word ("selah", "melchizedek")
+type Lexicon struct {
+	Schema  int                     `json:"schema"`
+	Source  string                  `json:"source,omitempty"` // where the entries came from, such as "strongs"
+	Entries map[string]LexiconEntry `json:"entries"`
+}
+
+// LexiconEntry is one lexicon entry. Every field is optional, so a lexicon may carry only
+// pronunciations of proper names or only definitions.
+type LexiconEntry struct {
+	Lemma           string `json:"lemma,omitempty"`           // original-language headword, such as "רֵאשִׁית"
+	Transliteration string `json:"transliteration,omitempty"` // headword in Latin letters, such as "rê'shîyth"
+	Pronunciation   string `json:"pronunciation,omitempty"`   // spoken form, such as "ray-sheeth'"
+	Definition      string `json:"definition,omitempty"`
+}
+
+// strongsKey matches a Strong's number with optional leading zeros and letter suffix
+var strongsKey = regexp.MustCompile(`^([HhGg])0*(\d+)([A-Za-z]?)$`)
+
+// LexiconKey normalizes a lexicon key: Strong's numbers are upper-cased without leading zeros
+// ("h07225" becomes "H7225"), and words are lower-cased with surrounding punctuation removed, so
+// a KJV word such as "Selah." finds the entry "selah"
+func LexiconKey(s string) string {
+	s = strings.TrimSpace(s)
+	if m := strongsKey.FindStringSubmatch(s); m != nil {
+		return strings.ToUpper(m[1]) + m[2] + strings.ToLower(m[3])
+	}
+	return strings.ToLower(strings.TrimFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}))
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LocaleSchema is the current schema version of index/locales/{lang}.json
+const LocaleSchema = 1
+
+// Locale is the structure of index/locales/{lang}.json: the names of the books in another
+// language, keyed by OSIS code, for display. The text itself is not translated.
+type Locale struct {
+	Schema int               `json:"schema"`
+	Lang   string            `json:"lang"` // language tag matching the file name, such as "es"
+	Name   string            `json:"name"` // the language's name in itself, such as "Español"
+	Books  map[string]string `json:"books"`
+}
+
+// ParseLocale parses a locale file
+func ParseLocale(data []byte) (Locale, error) {
+	var locale Locale
+	if err := json.Unmarshal(data, &locale); err != nil {
+		return locale, fmt.Errorf("failed to parse locale: %w", err)
+	}
+	if locale.Schema != LocaleSchema {
+		return locale, fmt.Errorf("unsupported locale schema version %d", locale.Schema)
+	}
+	return locale, nil
+}
@@ -0,0 +1,95 @@
+// Package model defines the canon file formats: the chapter and introduction documents under
+// books/, the alignment sidecars under align/, and the books.json, aliases.json,
+// chronological.json, filemap.json, lexicon.json, parallels.json, topics.json, verses.json, and
+// versification.json indexes under index/.
+//
+// The types are the public contract of the canon and follow semantic versioning with the module.
+// Within a major version, fields and their JSON names are not removed, renamed, or retyped; new
+// fields may be added, and are optional, so programs that unmarshal canon files with these types
+// keep working as the canon grows. A change that existing readers could not parse is released
+// with a new schema version in the document's "schema" field, and readers should reject schema
+// versions newer than they know.
+package model
+
+// ChapterSchema is the current schema version of chapter documents
+const ChapterSchema = 1
+
+// IntroSchema is the current schema version of book introduction documents
+const IntroSchema = 1
+
+// BooksSchema is the current schema version of books.json
+const BooksSchema = 1
+
+// BookMetadata represents book information from books.json
+type BookMetadata struct {
+	OSIS      string   `json:"osis"`
+	Abbr      string   `json:"abbr"`
+	Name      string   `json:"name"`
+	Aliases   []string `json:"aliases"`
+	Testament string   `json:"testament"`
+	Order     int      `json:"order"`
+	Chapters  int      `json:"chapters"`
+}
+
+// BooksData is the structure of books.json
+type BooksData struct {
+	Schema int            `json:"schema"`
+	Work   string         `json:"work"`
+	Books  []BookMetadata `json:"books"`
+}
+
+// AliasChapters represents the chapter mapping for a book
+type AliasChapters struct {
+	SourceAbbr string            `json:"source_abbr"`
+	Chapters   map[string]string `json:"chapters"`
+}
+
+// AliasesData is the structure of aliases.json (map of OSIS -> AliasChapters)
+type AliasesData map[string]AliasChapters
+
+// Token represents a single token in a verse (text, added word, divine name, etc.)
+type Token struct {
+	Text string `json:"t,omitempty"`
+	Add  string `json:"add,omitempty"`
+	ND   string `json:"nd,omitempty"`
+}
+
+// Verse represents a single verse with tokenized content
+type Verse struct {
+	V      int     `json:"v"`
+	Plain  string  `json:"plain,omitempty"`
+	Tokens []Token `json:"tokens"`
+}
+
+// Footnote represents a biblical footnote
+type Footnote struct {
+	ID   string `json:"id"`
+	Mark string `json:"mark"`
+	At   struct {
+		V int `json:"v"`
+	} `json:"at"`
+	Text string `json:"text"`
+}
+
+// Chapter represents a complete chapter with verses and footnotes
+type Chapter struct {
+	Schema    int        `json:"schema"`
+	Work      string     `json:"work"`
+	OSIS      string     `json:"osis"`
+	Abbr      string     `json:"abbr"`
+	Chapter   int        `json:"chapter"`
+	Verses    []Verse    `json:"verses"`
+	Footnotes []Footnote `json:"footnotes,omitempty"`
+	// Provenance notes changes made to the chapter after ingest, such as plain text regenerated from tokens
+	Provenance []string `json:"provenance,omitempty"`
+}
+
+// BookIntro represents a book introduction parsed from a chapter 00 source file
+type BookIntro struct {
+	Schema     int      `json:"schema"`
+	Work       string   `json:"work"`
+	OSIS       string   `json:"osis"`
+	Abbr       string   `json:"abbr"`
+	Title      string   `json:"title,omitempty"`
+	Paragraphs []string `json:"paragraphs"`
+}
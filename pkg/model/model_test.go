@@ -0,0 +1,238 @@
+package model
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// findRoot returns the project root, which holds canon/kjv
+func findRoot(t *testing.T) string {
+	t.Helper()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			return cwd
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+}
+
+// TestCanonRoundTrip checks that committed canon files unmarshal into the model and marshal back unchanged
+func TestCanonRoundTrip(t *testing.T) {
+	canon := filepath.Join(findRoot(t), "canon", "kjv")
+
+	tests := []struct {
+		file string
+		v    interface{}
+	}{
+		{filepath.Join("books", "Gen", "ch01.json"), &Chapter{}},
+		{filepath.Join("index", "books.json"), &BooksData{}},
+		{filepath.Join("index", "aliases.json"), &AliasesData{}},
+		{filepath.Join("index", "chronological.json"), &Chronology{}},
+		{filepath.Join("index", "filemap.json"), &FileMap{}},
+		{filepath.Join("index", "parallels.json"), &Parallels{}},
+		{filepath.Join("index", "topics.json"), &Topics{}},
+		{filepath.Join("index", "verses.json"), &VerseIndex{}},
+		{filepath.Join("index", "versification.json"), &Versification{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(canon, tt.file)) // nolint: gosec
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", tt.file, err)
+			}
+			if err := json.Unmarshal(data, tt.v); err != nil {
+				t.Fatalf("failed to unmarshal %s: %v", tt.file, err)
+			}
+
+			var want, got interface{}
+			if err := json.Unmarshal(data, &want); err != nil {
+				t.Fatal(err)
+			}
+			encoded, err := json.Marshal(tt.v)
+			if err != nil {
+				t.Fatalf("failed to marshal %s: %v", tt.file, err)
+			}
+			if err := json.Unmarshal(encoded, &got); err != nil {
+				t.Fatal(err)
+			}
+			if string(mustJSON(t, want)) != string(mustJSON(t, got)) {
+				t.Errorf("%s changed in a round trip through the model", tt.file)
+			}
+		})
+	}
+
+	var chapter Chapter
+	data, _ := os.ReadFile(filepath.Join(canon, "books", "Gen", "ch01.json")) // nolint: gosec
+	if err := json.Unmarshal(data, &chapter); err != nil {
+		t.Fatal(err)
+	}
+	if chapter.Schema != ChapterSchema || len(chapter.Verses) != 31 {
+		t.Errorf("unexpected Gen 1: schema %d, %d verses", chapter.Schema, len(chapter.Verses))
+	}
+}
+
+// mustJSON re-encodes a decoded document so maps compare in key order
+func mustJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestVerseWords(t *testing.T) {
+	verse := Verse{V: 3, Tokens: []Token{
+		{Text: "¶ And God said, Let there be light: and there was light. And the "},
+		{ND: "LORD"},
+		{Text: "'s "},
+		{Add: "word"},
+	}}
+	want := []string{"And", "God", "said,", "Let", "there", "be", "light:", "and", "there", "was", "light.", "And", "the", "LORD's", "word"}
+
+	got := verse.Words()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d words, got %q", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("word %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func TestCanonicalOSIS(t *testing.T) {
+	tests := map[string]string{
+		"Gen":      "Gen",
+		"1Macc":    "1Macc",
+		"1 Macc":   "1Macc",
+		"Add Esth": "AddEsth",
+		"Sg Three": "SgThree",
+		" Pr  Man": "PrMan",
+	}
+	for code, want := range tests {
+		if got := CanonicalOSIS(code); got != want {
+			t.Errorf("CanonicalOSIS(%q) = %q, want %q", code, got, want)
+		}
+		if IsCanonicalOSIS(code) != (code == want) {
+			t.Errorf("IsCanonicalOSIS(%q) = %v", code, code != want)
+		}
+	}
+	if IsCanonicalOSIS("") {
+		t.Error("expected an empty code not to be canonical")
+	}
+}
+
+func TestVerseMap(t *testing.T) {
+	vi := NewVerseIndex()
+	vi.SetVerses("Gen", 1, []int{1, 2, 3})
+	vi.SetVerses("AddEsth", 10, []int{4, 5, 6})
+
+	if last, _ := vi.LastVerse("AddEsth", 10); last != 6 {
+		t.Errorf("expected last verse 6, got %d", last)
+	}
+	if _, ok := vi.VerseMap("Gen", 1); ok {
+		t.Error("expected no verse map for a chapter numbered from 1")
+	}
+	if verses, ok := vi.VerseMap("AddEsth", 10); !ok || len(verses) != 3 || verses[0] != 4 {
+		t.Errorf("expected verse map [4 5 6], got %v", verses)
+	}
+
+	// Re-ingesting a chapter with contiguous numbering drops its map
+	vi.SetVerses("AddEsth", 10, []int{1, 2})
+	if _, ok := vi.VerseMap("AddEsth", 10); ok {
+		t.Error("expected the verse map to be cleared")
+	}
+
+	other := NewVerseIndex()
+	other.SetVerses("AddEsth", 10, []int{4, 6})
+	vi.Merge(other)
+	if verses, ok := vi.VerseMap("AddEsth", 10); !ok || len(verses) != 2 {
+		t.Errorf("expected Merge to carry the verse map, got %v", verses)
+	}
+
+	// MergeChapters replaces only the chapters the other index records
+	partial := NewVerseIndex()
+	partial.SetVerses("Gen", 2, []int{1, 2})
+	partial.SetVerses("AddEsth", 10, []int{1, 2, 3})
+	vi.MergeChapters(partial)
+	if last, _ := vi.LastVerse("Gen", 1); last != 3 {
+		t.Errorf("expected MergeChapters to keep Gen 1, got last verse %d", last)
+	}
+	if last, _ := vi.LastVerse("Gen", 2); last != 2 {
+		t.Errorf("expected MergeChapters to add Gen 2, got last verse %d", last)
+	}
+	if _, ok := vi.VerseMap("AddEsth", 10); ok {
+		t.Error("expected MergeChapters to clear the verse map")
+	}
+}
+
+func TestParseFootnoteTerm(t *testing.T) {
+	tests := []struct {
+		text string
+		want FootnoteTerm
+		ok   bool
+	}{
+		{"equity: Heb. equities", FootnoteTerm{Term: "equity", Language: "Hebrew", Gloss: "equities"}, true},
+		{"Let there…: Heb. Let the work be heavy upon the men", FootnoteTerm{Term: "Let there…", Language: "Hebrew", Gloss: "Let the work be heavy upon the men"}, true},
+		{"as much…: Heb according to all thy need", FootnoteTerm{Term: "as much…", Language: "Hebrew", Gloss: "according to all thy need"}, true},
+		{"Molech: Gr. Moloch", FootnoteTerm{Term: "Molech", Language: "Greek", Gloss: "Moloch"}, true},
+		{"make: Chaldee, build", FootnoteTerm{Term: "make", Language: "Chaldee", Gloss: "build"}, true},
+		{"bands: or, captains, or, men: Heb. heads", FootnoteTerm{Term: "bands", Language: "Hebrew", Gloss: "heads"}, true},
+		{"tent: or, covering", FootnoteTerm{}, false},
+		{"Moses: that is, Drawn out", FootnoteTerm{}, false},
+		{"O king…: (Chaldee, to the end of chapter seven)", FootnoteTerm{}, false},
+		{"Heb. equities", FootnoteTerm{}, false},
+		{"equity: Heb. ", FootnoteTerm{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := Footnote{Text: tt.text}.Term()
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("Term(%q) = %+v, %v, want %+v, %v", tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestObjectIndex(t *testing.T) {
+	name := ObjectName([]byte("{}"), ".zst")
+	if name != "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a.json.zst" {
+		t.Errorf("unexpected object name %q", name)
+	}
+	if got := ObjectPath(name); got != "objects/44/"+name {
+		t.Errorf("unexpected object path %q", got)
+	}
+
+	ix := NewObjectIndex("KJV")
+	ix.Chapters[ObjectKey("1Sam", 3)] = name
+	data, err := json.Marshal(ix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseObjectIndex(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := parsed.Object("1Sam", 3); !ok || got != name {
+		t.Errorf("expected 1Sam 3 to be stored in %s, got %q", name, got)
+	}
+	if _, ok := parsed.Object("1Sam", 4); ok {
+		t.Error("expected no object for 1Sam 4")
+	}
+
+	if _, err := ParseObjectIndex([]byte(`{"schema": 2}`)); err == nil {
+		t.Error("expected an error for an unsupported schema")
+	}
+}
@@ -0,0 +1,14 @@
+package model
+
+// NormalizationSchema is the current schema version of normalization.json
+const NormalizationSchema = 1
+
+// Normalization is the structure of normalization.json: how early modern English words in the
+// text are normalized to modern English, so modern-language searches and word statistics match
+// the KJV. Words are lowercase.
+type Normalization struct {
+	Schema    int               `json:"schema"`
+	Words     map[string]string `json:"words"`     // whole words, such as "thou" to "you" and "knowest" to "know"
+	Spellings map[string]string `json:"spellings"` // older spellings replaced at the start of a word, such as "shew" to "show"
+	Keep      []string          `json:"keep"`      // words left as they are, such as names ending in -eth like "nazareth"
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+)
+
+// ObjectIndexSchema is the current schema version of index/objects.json
+const ObjectIndexSchema = 1
+
+// ObjectIndex is the structure of index/objects.json, the reference index of a canon written in
+// the content-addressed layout. Each chapter's JSON is stored once under objects/, named by the
+// SHA-256 of its contents, so canons and editions that share identical chapters share their
+// objects, and a sync tool need only copy the objects it does not have and then the index.
+type ObjectIndex struct {
+	Schema   int               `json:"schema"`
+	Work     string            `json:"work"`
+	Chapters map[string]string `json:"chapters"` // ObjectKey -> object name, e.g. "Gen.1" -> "3fa9...e1.json"
+}
+
+// NewObjectIndex returns an empty object index of the current schema
+func NewObjectIndex(work string) ObjectIndex {
+	return ObjectIndex{Schema: ObjectIndexSchema, Work: work, Chapters: make(map[string]string)}
+}
+
+// ParseObjectIndex parses objects.json
+func ParseObjectIndex(data []byte) (ObjectIndex, error) {
+	var ix ObjectIndex
+	if err := json.Unmarshal(data, &ix); err != nil {
+		return ix, fmt.Errorf("failed to parse object index: %w", err)
+	}
+	if ix.Schema != ObjectIndexSchema {
+		return ix, fmt.Errorf("unsupported object index schema version %d", ix.Schema)
+	}
+	if ix.Chapters == nil {
+		ix.Chapters = make(map[string]string)
+	}
+	return ix, nil
+}
+
+// ObjectKey returns the key of a chapter in an ObjectIndex, e.g. "Gen.1"
+func ObjectKey(osis string, chapter int) string {
+	return fmt.Sprintf("%s.%d", osis, chapter)
+}
+
+// Object returns the name of the object holding a chapter
+func (ix ObjectIndex) Object(osis string, chapter int) (string, bool) {
+	name, ok := ix.Chapters[ObjectKey(osis, chapter)]
+	return name, ok
+}
+
+// ObjectName returns the content-addressed name of a chapter object: the SHA-256 of data, the
+// chapter's file as written, followed by ".json" and ext, the extension of its compression if any
+func ObjectName(data []byte, ext string) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + ".json" + ext
+}
+
+// ObjectPath returns the slash-separated path of an object relative to the canon root, fanned
+// out by the first two characters of its name, e.g. objects/3f/3fa9...e1.json
+func ObjectPath(name string) string {
+	if len(name) < 2 {
+		return path.Join("objects", name)
+	}
+	return path.Join("objects", name[:2], name)
+}
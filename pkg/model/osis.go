@@ -0,0 +1,15 @@
+package model
+
+import "strings"
+
+// CanonicalOSIS returns the canonical OSIS book ID for a book code. OSIS IDs never contain spaces,
+// but canons ingested before IDs were normalized use codes such as "1 Macc" and "Add Esth"; those
+// become "1Macc" and "AddEsth". Canonical IDs are returned unchanged.
+func CanonicalOSIS(code string) string {
+	return strings.Join(strings.Fields(code), "")
+}
+
+// IsCanonicalOSIS reports whether a book code is already a canonical OSIS ID
+func IsCanonicalOSIS(code string) bool {
+	return code != "" && CanonicalOSIS(code) == code
+}
@@ -0,0 +1,30 @@
+package model
+
+// ParallelsSchema is the current schema version of parallels.json
+const ParallelsSchema = 1
+
+// Parallels is the structure of parallels.json: candidate parallel passages across books, such
+// as Kings and Chronicles or the synoptic gospels, found by comparing verse text
+type Parallels struct {
+	Schema    int            `json:"schema"`
+	Shingle   int            `json:"shingle"`   // words per shingle
+	Hashes    int            `json:"hashes"`    // MinHash signature length
+	Threshold float64        `json:"threshold"` // minimum Jaccard similarity of matched verses
+	Pairs     []ParallelPair `json:"pairs"`
+}
+
+// ParallelPair is two passages whose verses match in order. A precedes B in canonical order.
+type ParallelPair struct {
+	A          Passage `json:"a"`
+	B          Passage `json:"b"`
+	Verses     int     `json:"verses"`     // matched verse pairs
+	Similarity float64 `json:"similarity"` // mean Jaccard similarity of the matched verses
+}
+
+// Passage is a verse range within one chapter
+type Passage struct {
+	OSIS    string `json:"osis"`
+	Chapter int    `json:"chapter"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+}
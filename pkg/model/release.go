@@ -0,0 +1,89 @@
+package model
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Names of the release files at the root of a release artifact built by package release
+const (
+	ReleaseManifestName = "corpus.json"
+	ChecksumsName       = "SHA256SUMS"
+	SignatureName       = "SHA256SUMS.sig"
+	PackName            = "canon.zip" // the packed canon of a ReleasePacked release
+)
+
+// ReleaseSchema is the current schema version of corpus.json
+const ReleaseSchema = 1
+
+// ReleaseFormat is how a release carries the canon
+type ReleaseFormat string
+
+const (
+	ReleaseCanon  ReleaseFormat = "canon"  // the canon directory as is
+	ReleasePacked ReleaseFormat = "packed" // canon.zip for kjvcorpus.OpenPacked, and index/
+)
+
+// ReleaseManifest is the structure of corpus.json, which describes a release
+type ReleaseManifest struct {
+	Schema   int           `json:"schema"`
+	Name     string        `json:"name"`
+	Version  string        `json:"version"`
+	Work     string        `json:"work"`
+	Format   ReleaseFormat `json:"format"`
+	Created  string        `json:"created,omitempty"` // RFC 3339
+	Books    int           `json:"books"`
+	Chapters int           `json:"chapters"`
+	Files    int           `json:"files"`  // files of the canon in the release, not counting the release files
+	KeyID    string        `json:"key_id"` // KeyID of the key that signed SHA256SUMS
+}
+
+// ParseReleaseManifest parses corpus.json
+func ParseReleaseManifest(data []byte) (ReleaseManifest, error) {
+	var manifest ReleaseManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse %s: %w", ReleaseManifestName, err)
+	}
+	if manifest.Schema != ReleaseSchema {
+		return manifest, fmt.Errorf("unsupported %s schema version %d", ReleaseManifestName, manifest.Schema)
+	}
+	return manifest, nil
+}
+
+// ReleaseSignature is the structure of SHA256SUMS.sig, the detached signature of SHA256SUMS
+type ReleaseSignature struct {
+	Algorithm string `json:"algorithm"` // always "ed25519"
+	KeyID     string `json:"key_id"`
+	Signature string `json:"signature"` // hex
+}
+
+// KeyID returns the identifier of a release signing key: the first 16 hex digits of the SHA-256
+// of its public key
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ParseChecksums parses SHA256SUMS, one "{hex}  {path}" line per file as sha256sum writes them,
+// into a map of slash-separated path to checksum
+func ParseChecksums(data []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		sum, name, ok := strings.Cut(scanner.Text(), "  ")
+		if _, err := hex.DecodeString(sum); !ok || err != nil || len(sum) != sha256.Size*2 || name == "" {
+			return nil, fmt.Errorf("malformed %s line %q", ChecksumsName, scanner.Text())
+		}
+		sums[name] = sum
+	}
+	if len(sums) == 0 {
+		return nil, fmt.Errorf("%s lists no files", ChecksumsName)
+	}
+	return sums, nil
+}
@@ -0,0 +1,18 @@
+package model
+
+// TopicsSchema is the current schema version of topics.json
+const TopicsSchema = 1
+
+// Topics is the structure of topics.json: a topical index of references, keyed by a lowercase
+// topic identifier such as "faith"
+type Topics struct {
+	Schema int              `json:"schema"`
+	Topics map[string]Topic `json:"topics"`
+}
+
+// Topic is one entry of the topical index. Refs are references in any form bibleref.Parse
+// accepts, such as "John 3:16" or "1 Cor 13:4-7", listed in the order they should be shown.
+type Topic struct {
+	Name string   `json:"name"`
+	Refs []string `json:"refs"`
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// VerseIndexSchema is the current schema version of verses.json
+const VerseIndexSchema = 1
+
+// VerseIndex is the structure of verses.json: the last verse number of every chapter ingest
+// produced, keyed by OSIS code. Books[osis][n-1] is the last verse of chapter n, or 0 when
+// ingest did not produce that chapter. Maps[osis][n] lists the verse numbers of chapter n in
+// order when they are not 1 to the last verse, as in AddEsth 10, which starts at verse 4.
+type VerseIndex struct {
+	Schema int                      `json:"schema"`
+	Books  map[string][]int         `json:"books"`
+	Maps   map[string]map[int][]int `json:"maps,omitempty"`
+}
+
+// NewVerseIndex creates an empty verse index at the current schema version
+func NewVerseIndex() VerseIndex {
+	return VerseIndex{
+		Schema: VerseIndexSchema,
+		Books:  make(map[string][]int),
+	}
+}
+
+// ParseVerseIndex parses verses.json
+func ParseVerseIndex(data []byte) (VerseIndex, error) {
+	var vi VerseIndex
+	if err := json.Unmarshal(data, &vi); err != nil {
+		return vi, fmt.Errorf("failed to parse verse index: %w", err)
+	}
+	if vi.Schema != VerseIndexSchema {
+		return vi, fmt.Errorf("unsupported verse index schema version %d", vi.Schema)
+	}
+	if vi.Books == nil {
+		vi.Books = make(map[string][]int)
+	}
+	return vi, nil
+}
+
+// Set records the last verse of a chapter, growing the book's list as needed
+func (vi *VerseIndex) Set(osis string, chapter, lastVerse int) {
+	if chapter < 1 {
+		return
+	}
+	if vi.Books == nil {
+		vi.Books = make(map[string][]int)
+	}
+	counts := vi.Books[osis]
+	if len(counts) < chapter {
+		counts = append(counts, make([]int, chapter-len(counts))...)
+	}
+	counts[chapter-1] = lastVerse
+	vi.Books[osis] = counts
+}
+
+// SetVerses records the last verse of a chapter from its verse numbers, in order, and records
+// them as the chapter's verse map unless they run from 1 to the last verse
+func (vi *VerseIndex) SetVerses(osis string, chapter int, verses []int) {
+	if chapter < 1 || len(verses) == 0 {
+		return
+	}
+	vi.Set(osis, chapter, verses[len(verses)-1])
+
+	if IsContiguous(verses) {
+		if vi.Maps[osis] != nil {
+			delete(vi.Maps[osis], chapter)
+			if len(vi.Maps[osis]) == 0 {
+				delete(vi.Maps, osis)
+			}
+		}
+		return
+	}
+	if vi.Maps == nil {
+		vi.Maps = make(map[string]map[int][]int)
+	}
+	if vi.Maps[osis] == nil {
+		vi.Maps[osis] = make(map[int][]int)
+	}
+	vi.Maps[osis][chapter] = verses
+}
+
+// VerseMap returns the verse numbers of a chapter whose numbering does not run from 1 to its last
+// verse, reporting false for chapters numbered normally or not recorded
+func (vi VerseIndex) VerseMap(osis string, chapter int) ([]int, bool) {
+	verses, ok := vi.Maps[osis][chapter]
+	return verses, ok
+}
+
+// IsContiguous reports whether verse numbers run from 1 without gaps
+func IsContiguous(verses []int) bool {
+	for i, v := range verses {
+		if v != i+1 {
+			return false
+		}
+	}
+	return true
+}
+
+// LastVerse returns the last verse of a chapter, reporting false when the index does not record it
+func (vi VerseIndex) LastVerse(osis string, chapter int) (int, bool) {
+	counts := vi.Books[osis]
+	if chapter < 1 || chapter > len(counts) || counts[chapter-1] == 0 {
+		return 0, false
+	}
+	return counts[chapter-1], true
+}
+
+// Merge copies every book from another index, replacing books already present along with their
+// verse maps
+func (vi *VerseIndex) Merge(other VerseIndex) {
+	for osis, counts := range other.Books {
+		if vi.Books == nil {
+			vi.Books = make(map[string][]int)
+		}
+		vi.Books[osis] = counts
+		delete(vi.Maps, osis)
+	}
+	for osis, maps := range other.Maps {
+		if vi.Maps == nil {
+			vi.Maps = make(map[string]map[int][]int)
+		}
+		vi.Maps[osis] = maps
+	}
+}
+
+// MergeChapters copies only the chapters another index records, keeping the other chapters of
+// books already present, as when some chapters of a book are ingested again
+func (vi *VerseIndex) MergeChapters(other VerseIndex) {
+	for osis, counts := range other.Books {
+		for i, last := range counts {
+			if last == 0 {
+				continue
+			}
+			verses, ok := other.VerseMap(osis, i+1)
+			if !ok {
+				verses = make([]int, last)
+				for v := range verses {
+					verses[v] = v + 1
+				}
+			}
+			vi.SetVerses(osis, i+1, verses)
+		}
+	}
+}
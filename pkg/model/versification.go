@@ -0,0 +1,29 @@
+package model
+
+// VersificationSchema is the current schema version of versification.json
+const VersificationSchema = 1
+
+// Versification is the structure of versification.json: where other numbering schemes differ from
+// the canon's own, keyed by scheme name
+type Versification struct {
+	Schema  int                            `json:"schema"`
+	Base    string                         `json:"base"` // name of the canon's own scheme, such as "kjv"
+	Schemes map[string]VersificationScheme `json:"schemes"`
+}
+
+// VersificationScheme lists the spans a numbering scheme numbers differently from the base scheme.
+// Spans not listed are numbered the same in both.
+type VersificationScheme struct {
+	Name     string                 `json:"name"`
+	Mappings []VersificationMapping `json:"mappings"`
+}
+
+// VersificationMapping maps a span of one book from the base scheme to the other scheme. Spans
+// are either chapter ranges such as "11-113", whose verse numbers carry over, or verse ranges
+// within one chapter such as "10:1-18"; a single chapter or verse may omit the range. Both sides
+// of a mapping must be the same length.
+type VersificationMapping struct {
+	OSIS  string `json:"osis"`
+	Base  string `json:"base"`
+	Other string `json:"other"`
+}
@@ -0,0 +1,211 @@
+// Package normalize maps early modern English words to modern English, so that modern-language
+// searches and word statistics match the KJV: "thou" becomes "you", "knowest" becomes "know",
+// "sheweth" becomes "shows", and so on. The mapping is data, normalization.json in the canon's
+// index, which Corpus.Normalization loads; a Normalizer built from it can be extended with
+// further words and spellings.
+package normalize
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Normalizer normalizes words with a normalization table. The third person -eth of verbs not in
+// the table is folded by rule. A Normalizer may be used by several goroutines once it is no longer
+// being extended.
+type Normalizer struct {
+	words     map[string]string
+	spellings map[string]string
+	keep      map[string]bool
+}
+
+// New creates a normalizer from a table, such as the one Corpus.Normalization returns. A nil
+// table normalizes by rule alone.
+func New(table *model.Normalization) *Normalizer {
+	n := &Normalizer{
+		words:     make(map[string]string),
+		spellings: make(map[string]string),
+		keep:      make(map[string]bool),
+	}
+	if table != nil {
+		n.Merge(table)
+	}
+	return n
+}
+
+// Merge adds the words, spellings, and kept words of another table, replacing those already
+// present
+func (n *Normalizer) Merge(table *model.Normalization) {
+	for archaic, modern := range table.Words {
+		n.Add(archaic, modern)
+	}
+	for old, modern := range table.Spellings {
+		n.AddSpelling(old, modern)
+	}
+	for _, word := range table.Keep {
+		n.Keep(word)
+	}
+}
+
+// Add normalizes archaic to modern, as for "thou" and "you", replacing any earlier mapping of it
+func (n *Normalizer) Add(archaic, modern string) {
+	archaic = strings.ToLower(archaic)
+	delete(n.keep, archaic)
+	n.words[archaic] = strings.ToLower(modern)
+}
+
+// AddSpelling replaces the older spelling old with modern wherever a word starts with it, as
+// "shew" in "shewed" and "shewbread"
+func (n *Normalizer) AddSpelling(old, modern string) {
+	n.spellings[strings.ToLower(old)] = strings.ToLower(modern)
+}
+
+// Keep leaves word as it is, for words the -eth rule would otherwise fold, such as names
+func (n *Normalizer) Keep(word string) {
+	word = strings.ToLower(word)
+	delete(n.words, word)
+	n.keep[word] = true
+}
+
+// Table returns the normalizer's table, with any words added since it was created, to save with
+// data built using it
+func (n *Normalizer) Table() *model.Normalization {
+	keep := slices.Sorted(maps.Keys(n.keep))
+	return &model.Normalization{
+		Schema:    model.NormalizationSchema,
+		Words:     maps.Clone(n.words),
+		Spellings: maps.Clone(n.spellings),
+		Keep:      keep,
+	}
+}
+
+// Word returns the modern form of a lowercase word. A word in the table is replaced as the table
+// says; otherwise a third person -eth becomes -s or -es, as in "loveth" to "loves" and
+// "teacheth" to "teaches", and the longest older spelling the word starts with is modernized. Words
+// the table keeps, and modern words, are returned unchanged.
+func (n *Normalizer) Word(word string) string {
+	if modern, ok := n.words[word]; ok {
+		return modern
+	}
+	if n.keep[word] {
+		return word
+	}
+	word = foldEth(word)
+	longest := ""
+	for old := range n.spellings {
+		if len(old) > len(longest) && strings.HasPrefix(word, old) {
+			longest = old
+		}
+	}
+	if longest != "" {
+		return n.spellings[longest] + word[len(longest):]
+	}
+	return word
+}
+
+// foldEth turns a third person -eth verb into its modern -s form. The silent e the -eth replaced
+// is restored by the usual spelling patterns, which irregular stems can defeat; the table lists
+// the verbs of the text they miss.
+func foldEth(word string) string {
+	if len(word) < 5 || !strings.HasSuffix(word, "eth") || strings.HasSuffix(word, "tieth") ||
+		strings.ContainsAny(word, "'’-") {
+		return word
+	}
+	if strings.HasSuffix(word, "eeth") {
+		return word[:len(word)-2] + "s" // seeth, fleeth
+	}
+
+	base := word[:len(word)-3]
+	last := base[len(base)-1]
+	switch {
+	case strings.ContainsRune("iosxz", rune(last)) || strings.HasSuffix(base, "ch") || strings.HasSuffix(base, "sh"):
+		return base + "es" // crieth, goeth, passeth, teacheth
+	case len(base) >= 2 && base[len(base)-2] == last && !strings.ContainsRune("aeioudfl", rune(last)):
+		return base[:len(base)-1] + "s" // sitteth
+	case silentE(base):
+		return base + "es" // loveth, maketh, troubleth
+	}
+	return base + "s"
+}
+
+// unstressedEndings are the endings of longer stems that do not take a silent e, as in "delivereth"
+var unstressedEndings = []string{"al", "el", "en", "er", "et", "it", "on"}
+
+// silentE reports whether the verb stem left by removing -eth ends in a silent e, as "lov",
+// "mak", and "troubl" do but "keep" and "deliver" do not
+func silentE(base string) bool {
+	n := len(base)
+	switch base[n-1] {
+	case 'c', 'u', 'v':
+		return true
+	case 'l':
+		// troubl, but not call or whirl
+		if n >= 2 && consonant(base, n-2) && base[n-2] != 'l' && base[n-2] != 'r' {
+			return true
+		}
+	}
+	for _, suffix := range []string{"dg", "rg", "th"} {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+
+	if !cvc(base) {
+		return false
+	}
+	if measure(base) == 1 {
+		return true
+	}
+	for _, suffix := range unstressedEndings {
+		if strings.HasSuffix(base, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// consonant reports whether s[i] is a consonant. y is a consonant unless it follows one.
+func consonant(s string, i int) bool {
+	switch s[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !consonant(s, i-1)
+	}
+	return true
+}
+
+// cvc reports whether s ends consonant-vowel-consonant and the last is not w, x, or y, as "hop"
+// does but "snow" does not
+func cvc(s string) bool {
+	i := len(s) - 1
+	if i < 2 || !consonant(s, i) || consonant(s, i-1) || !consonant(s, i-2) {
+		return false
+	}
+	return !strings.ContainsRune("wxy", rune(s[i]))
+}
+
+// measure returns the number of vowel-consonant sequences in s after any leading consonants, as
+// the Porter stemmer counts them: 0 for "tr", 1 for "trouble", 2 for "troubles"
+func measure(s string) int {
+	n, i := 0, 0
+	for i < len(s) && consonant(s, i) {
+		i++
+	}
+	for i < len(s) {
+		for i < len(s) && !consonant(s, i) {
+			i++
+		}
+		if i == len(s) {
+			break
+		}
+		n++
+		for i < len(s) && consonant(s, i) {
+			i++
+		}
+	}
+	return n
+}
@@ -0,0 +1,123 @@
+package normalize
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// openCorpus opens canon/kjv from the project root
+func openCorpus(t *testing.T) *kjvcorpus.Corpus {
+	t.Helper()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	corpus, err := kjvcorpus.Open(filepath.Join(cwd, "canon", "kjv"))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	return corpus
+}
+
+func TestWord(t *testing.T) {
+	table, err := openCorpus(t).Normalization()
+	if err != nil {
+		t.Fatalf("Normalization failed: %v", err)
+	}
+	n := New(table)
+
+	for word, want := range map[string]string{
+		// From the table
+		"thou":      "you",
+		"ye":        "you",
+		"thine":     "your",
+		"hath":      "has",
+		"knowest":   "know",
+		"lovedst":   "loved",
+		"camest":    "came",
+		"changeth":  "changes",
+		"shewbread": "showbread",
+		"nazareth":  "nazareth",
+		"teeth":     "teeth",
+		// By rule
+		"sheweth":    "shows",
+		"loveth":     "loves",
+		"teacheth":   "teaches",
+		"goeth":      "goes",
+		"crieth":     "cries",
+		"seeth":      "sees",
+		"sitteth":    "sits",
+		"calleth":    "calls",
+		"keepeth":    "keeps",
+		"troubleth":  "troubles",
+		"delivereth": "delivers",
+		"forsaketh":  "forsakes",
+		"twentieth":  "twentieth",
+		"beth-el":    "beth-el",
+		"greatest":   "greatest",
+		"love":       "love",
+	} {
+		if got := n.Word(word); got != want {
+			t.Errorf("Word(%q): expected %q, got %q", word, want, got)
+		}
+	}
+
+	// Without a table only the rule applies
+	if got := New(nil).Word("nazareth"); got != "nazares" {
+		t.Errorf("expected the rule alone to fold nazareth, got %q", got)
+	}
+}
+
+func TestExtend(t *testing.T) {
+	n := New(&model.Normalization{
+		Schema:    model.NormalizationSchema,
+		Words:     map[string]string{"ye": "you"},
+		Spellings: map[string]string{"shew": "show"},
+	})
+	n.Add("Gat", "Got")
+	n.AddSpelling("stedfast", "steadfast")
+	n.Keep("japheth")
+	n.Merge(&model.Normalization{Words: map[string]string{"ye": "y'all"}, Keep: []string{"seth"}})
+
+	for word, want := range map[string]string{
+		"gat":          "got",
+		"ye":           "y'all",
+		"stedfastness": "steadfastness",
+		"shewed":       "showed",
+		"japheth":      "japheth",
+		"seth":         "seth",
+	} {
+		if got := n.Word(word); got != want {
+			t.Errorf("Word(%q): expected %q, got %q", word, want, got)
+		}
+	}
+
+	table := n.Table()
+	if table.Schema != model.NormalizationSchema || table.Words["gat"] != "got" || table.Spellings["stedfast"] != "steadfast" ||
+		!slices.Equal(table.Keep, []string{"japheth", "seth"}) {
+		t.Errorf("unexpected table: %+v", table)
+	}
+
+	// A word added after being kept is normalized again
+	n.Add("japheth", "japhs")
+	if got := n.Word("japheth"); got != "japhs" {
+		t.Errorf("expected the added word to replace the kept one, got %q", got)
+	}
+}
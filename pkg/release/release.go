@@ -0,0 +1,270 @@
+// Package release builds and verifies versioned release artifacts of the canon, so downstream apps
+// can download a corpus release and check that it is complete and was signed by its publisher
+// before using it.
+//
+// An artifact is a zstd-compressed tar archive, {name}-{version}.tar.zst, holding:
+//
+//   - corpus.json, the Manifest describing the release
+//   - the canon: every file of canon/kjv for FormatCanon, or canon.zip, the canon packed by
+//     kjvcorpus.Pack, with a copy of index/ for FormatPacked
+//   - SHA256SUMS, the SHA-256 of every other file in the format of sha256sum, so an extracted
+//     release can also be checked with sha256sum -c
+//   - SHA256SUMS.sig, the Ed25519 signature of SHA256SUMS
+//
+// Artifacts built from the same canon with the same options are identical.
+package release
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Names of the release files in an artifact
+const (
+	ManifestName  = model.ReleaseManifestName
+	ChecksumsName = model.ChecksumsName
+	SignatureName = model.SignatureName
+	PackName      = model.PackName // the packed canon of a FormatPacked artifact
+)
+
+// ManifestSchema is the current schema version of corpus.json
+const ManifestSchema = model.ReleaseSchema
+
+// Format selects how an artifact carries the canon
+type Format = model.ReleaseFormat
+
+const (
+	FormatCanon  = model.ReleaseCanon  // the canon directory as is
+	FormatPacked = model.ReleasePacked // canon.zip for kjvcorpus.OpenPacked, and index/
+)
+
+// Manifest is the structure of corpus.json
+type Manifest = model.ReleaseManifest
+
+// Signature is the structure of SHA256SUMS.sig
+type Signature = model.ReleaseSignature
+
+// Options configures Build
+type Options struct {
+	Name    string             // artifact name, such as "kjv"
+	Version string             // semantic version of the release, such as "1.2.0"
+	Format  Format             // "" is FormatCanon
+	Created time.Time          // recorded in corpus.json and as the time of every entry; zero omits it
+	Key     ed25519.PrivateKey // signs SHA256SUMS
+}
+
+var (
+	namePattern    = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	versionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+)
+
+// FileName returns the file name of the artifact of a release, {name}-{version}.tar.zst
+func FileName(name, version string) string {
+	return fmt.Sprintf("%s-%s.tar.zst", name, strings.TrimPrefix(version, "v"))
+}
+
+// KeyID returns the identifier of a public key recorded in corpus.json and SHA256SUMS.sig
+func KeyID(pub ed25519.PublicKey) string {
+	return model.KeyID(pub)
+}
+
+// file is a file of an artifact
+type file struct {
+	name string
+	data []byte
+}
+
+// Build writes the release artifact of the canon in fsys, laid out as canon/kjv, to w and returns
+// its manifest
+func Build(w io.Writer, fsys fs.FS, opts Options) (*Manifest, error) {
+	if opts.Format == "" {
+		opts.Format = FormatCanon
+	}
+	opts.Version = strings.TrimPrefix(opts.Version, "v")
+	if !namePattern.MatchString(opts.Name) {
+		return nil, fmt.Errorf("invalid release name %q", opts.Name)
+	}
+	if !versionPattern.MatchString(opts.Version) {
+		return nil, fmt.Errorf("invalid release version %q (expected a semantic version such as 1.2.0)", opts.Version)
+	}
+	if len(opts.Key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("a release must be signed with an ed25519 private key")
+	}
+
+	data, err := fs.ReadFile(fsys, "index/books.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read books.json: %w", err)
+	}
+	var books model.BooksData
+	if err := json.Unmarshal(data, &books); err != nil {
+		return nil, fmt.Errorf("failed to parse books.json: %w", err)
+	}
+
+	var files []file
+	switch opts.Format {
+	case FormatCanon:
+		files, err = readFiles(fsys, ".")
+	case FormatPacked:
+		var pack bytes.Buffer
+		if _, err = kjvcorpus.Pack(&pack, fsys); err != nil {
+			return nil, err
+		}
+		if files, err = readFiles(fsys, "index"); err == nil {
+			files = append(files, file{name: PackName, data: pack.Bytes()})
+		}
+	default:
+		return nil, fmt.Errorf("unknown release format %q (available: canon, packed)", opts.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pub, _ := opts.Key.Public().(ed25519.PublicKey)
+	manifest := &Manifest{
+		Schema:  ManifestSchema,
+		Name:    opts.Name,
+		Version: opts.Version,
+		Work:    books.Work,
+		Format:  opts.Format,
+		Books:   len(books.Books),
+		Files:   len(files),
+		KeyID:   KeyID(pub),
+	}
+	for _, book := range books.Books {
+		manifest.Chapters += book.Chapters
+	}
+	if !opts.Created.IsZero() {
+		manifest.Created = opts.Created.UTC().Format(time.RFC3339)
+	}
+	if data, err = json.MarshalIndent(manifest, "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", ManifestName, err)
+	}
+	files = append(files, file{name: ManifestName, data: data})
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+
+	sums := checksums(files)
+	sig, err := json.MarshalIndent(Signature{
+		Algorithm: "ed25519",
+		KeyID:     manifest.KeyID,
+		Signature: hex.EncodeToString(ed25519.Sign(opts.Key, sums)),
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", SignatureName, err)
+	}
+	files = append(files, file{name: ChecksumsName, data: sums}, file{name: SignatureName, data: sig})
+
+	archive, err := writeTar(files, opts.Created)
+	if err != nil {
+		return nil, err
+	}
+	if archive, err = compression.Zstd.Compress(archive); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(archive); err != nil {
+		return nil, fmt.Errorf("failed to write artifact: %w", err)
+	}
+	return manifest, nil
+}
+
+// readFiles reads the regular files beneath dir of fsys, skipping hidden files such as the .lock
+// of a running ingest
+func readFiles(fsys fs.FS, dir string) ([]file, error) {
+	var files []file
+	err := fs.WalkDir(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && name != "." {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if isReleaseFile(name) {
+			return fmt.Errorf("%s would be overwritten by the release file of the same name", name)
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		files = append(files, file{name: name, data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// isReleaseFile reports whether name is one of the files Build adds to an artifact
+func isReleaseFile(name string) bool {
+	switch name {
+	case ManifestName, ChecksumsName, SignatureName, PackName:
+		return true
+	}
+	return false
+}
+
+// checksums returns SHA256SUMS for files, which are in name order
+func checksums(files []file) []byte {
+	var buf bytes.Buffer
+	for _, f := range files {
+		sum := sha256.Sum256(f.data)
+		fmt.Fprintf(&buf, "%s  %s\n", hex.EncodeToString(sum[:]), f.name)
+	}
+	return buf.Bytes()
+}
+
+// writeTar returns the tar archive of files, with their parent directories, owned by root and
+// timestamped with created, or the Unix epoch
+func writeTar(files []file, created time.Time) ([]byte, error) {
+	if created.IsZero() {
+		created = time.Unix(0, 0)
+	}
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	dirs := make(map[string]bool)
+	for _, f := range files {
+		var parents []string
+		for dir := path.Dir(f.name); dir != "." && !dirs[dir]; dir = path.Dir(dir) {
+			dirs[dir] = true
+			parents = append(parents, dir)
+		}
+		for i := len(parents) - 1; i >= 0; i-- {
+			header := &tar.Header{Typeflag: tar.TypeDir, Name: parents[i] + "/", Mode: 0755, ModTime: created}
+			if err := tw.WriteHeader(header); err != nil {
+				return nil, fmt.Errorf("failed to write archive: %w", err)
+			}
+		}
+		header := &tar.Header{Typeflag: tar.TypeReg, Name: f.name, Mode: 0644, Size: int64(len(f.data)), ModTime: created}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, fmt.Errorf("failed to write archive: %w", err)
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return nil, fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
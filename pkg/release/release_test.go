@@ -0,0 +1,246 @@
+package release
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/julianstephens/kjv-sources/pkg/compression"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+func testCanon() fstest.MapFS {
+	books := `{"schema":1,"work":"KJV","books":[{"osis":"Obad","abbr":"OBA","name":"Obadiah",` +
+		`"aliases":["Obadiah"],"testament":"OT","order":1,"chapters":1}]}`
+	return fstest.MapFS{
+		"index/books.json":     {Data: []byte(books)},
+		"books/Obad/ch01.json": {Data: []byte(`{"schema":1,"work":"KJV","osis":"Obad","abbr":"OBA","chapter":1,"verses":[{"v":1,"tokens":[{"t":"The vision of Obadiah."}]}]}`)},
+		".lock":                {Data: []byte(`{"command":"package"}`)},
+	}
+}
+
+func testKeys(t *testing.T) (Options, []byte) {
+	t.Helper()
+	private, public, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ParsePrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Options{Name: "kjv", Version: "v1.2.0", Created: time.Unix(1700000000, 0), Key: key}, public
+}
+
+// rewrite returns artifact with its entries passed through edit, keeping SHA256SUMS and its
+// signature as they were
+func rewrite(t *testing.T, artifact []byte, edit func([]file) []file) []byte {
+	t.Helper()
+	data, err := compression.Decompress(artifact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files []file
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file{name: header.Name, data: data})
+	}
+	data, err = writeTar(edit(files), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err = compression.Zstd.Compress(data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestBuildAndVerify(t *testing.T) {
+	opts, public := testKeys(t)
+	pub, err := ParsePublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var artifact bytes.Buffer
+	manifest, err := Build(&artifact, testCanon(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Version != "1.2.0" || manifest.Work != "KJV" || manifest.Books != 1 || manifest.Chapters != 1 ||
+		manifest.Files != 2 || manifest.Created != "2023-11-14T22:13:20Z" || manifest.KeyID != KeyID(pub) {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+	if name := FileName(manifest.Name, opts.Version); name != "kjv-1.2.0.tar.zst" {
+		t.Errorf("unexpected file name %s", name)
+	}
+
+	var again bytes.Buffer
+	if _, err := Build(&again, testCanon(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(artifact.Bytes(), again.Bytes()) {
+		t.Error("expected the same artifact from the same canon and options")
+	}
+
+	verified, err := Verify(bytes.NewReader(artifact.Bytes()), pub)
+	if err != nil {
+		t.Fatalf("failed to verify artifact: %v", err)
+	}
+	if *verified != *manifest {
+		t.Errorf("verified manifest %+v differs from built %+v", verified, manifest)
+	}
+
+	other, _ := testKeys(t)
+	if _, err := Verify(bytes.NewReader(artifact.Bytes()), other.Key.Public().(ed25519.PublicKey)); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature for another key, got %v", err)
+	}
+
+	for name, edit := range map[string]func([]file) []file{
+		"checksum mismatch for books/Obad/ch01.json": func(files []file) []file {
+			for i := range files {
+				if files[i].name == "books/Obad/ch01.json" {
+					files[i].data = bytes.Replace(files[i].data, []byte("vision"), []byte("word"), 1)
+				}
+			}
+			return files
+		},
+		"artifact is missing books/Obad/ch01.json": func(files []file) []file {
+			var kept []file
+			for _, f := range files {
+				if f.name != "books/Obad/ch01.json" {
+					kept = append(kept, f)
+				}
+			}
+			return kept
+		},
+		"artifact holds books/Obad/ch02.json, which is not in SHA256SUMS": func(files []file) []file {
+			return append(files, file{name: "books/Obad/ch02.json", data: []byte("{}")})
+		},
+		"release signature does not match": func(files []file) []file {
+			for i := range files {
+				if files[i].name == ChecksumsName {
+					files[i].data = append(files[i].data, "0000000000000000000000000000000000000000000000000000000000000000  extra.json\n"...)
+				}
+			}
+			return files
+		},
+	} {
+		_, err := Verify(bytes.NewReader(rewrite(t, artifact.Bytes(), edit)), pub)
+		if err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("expected %q, got %v", name, err)
+		}
+	}
+}
+
+func TestBuildPacked(t *testing.T) {
+	opts, public := testKeys(t)
+	pub, _ := ParsePublicKey(public)
+	opts.Format = FormatPacked
+
+	var artifact bytes.Buffer
+	manifest, err := Build(&artifact, testCanon(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Format != FormatPacked || manifest.Files != 2 {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+	if _, err := Verify(bytes.NewReader(artifact.Bytes()), pub); err != nil {
+		t.Fatalf("failed to verify packed artifact: %v", err)
+	}
+
+	rewrite(t, artifact.Bytes(), func(files []file) []file {
+		for _, f := range files {
+			if f.name != PackName {
+				continue
+			}
+			corpus, err := kjvcorpus.OpenPacked(f.data)
+			if err != nil {
+				t.Fatalf("failed to open canon.zip: %v", err)
+			}
+			if _, ok := corpus.Table().ByOsis["Obad"]; !ok {
+				t.Error("expected Obadiah in canon.zip")
+			}
+		}
+		return files
+	})
+}
+
+func TestBuildOptions(t *testing.T) {
+	opts, _ := testKeys(t)
+	for name, edit := range map[string]func(*Options){
+		"invalid release version": func(o *Options) { o.Version = "latest" },
+		"invalid release name":    func(o *Options) { o.Name = "../kjv" },
+		"unknown release format":  func(o *Options) { o.Format = "zip" },
+		"must be signed":          func(o *Options) { o.Key = nil },
+	} {
+		o := opts
+		edit(&o)
+		if _, err := Build(io.Discard, testCanon(), o); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("expected %q, got %v", name, err)
+		}
+	}
+
+	canon := testCanon()
+	canon["corpus.json"] = &fstest.MapFile{Data: []byte("{}")}
+	if _, err := Build(io.Discard, canon, opts); err == nil {
+		t.Error("expected an error for a canon holding corpus.json")
+	}
+}
+
+func TestOpenVerifiedRelease(t *testing.T) {
+	opts, public := testKeys(t)
+	pub, _ := ParsePublicKey(public)
+
+	for _, format := range []Format{FormatCanon, FormatPacked} {
+		opts.Format = format
+		var artifact bytes.Buffer
+		if _, err := Build(&artifact, testCanon(), opts); err != nil {
+			t.Fatal(err)
+		}
+		extracted := fstest.MapFS{}
+		rewrite(t, artifact.Bytes(), func(files []file) []file {
+			for _, f := range files {
+				extracted[f.name] = &fstest.MapFile{Data: f.data}
+			}
+			return files
+		})
+
+		corpus, err := kjvcorpus.OpenFS(extracted, kjvcorpus.WithSignatureVerification(pub), kjvcorpus.WithStrictScan())
+		if err != nil {
+			t.Fatalf("%s: failed to open the release: %v", format, err)
+		}
+		if release := corpus.Release(); release == nil || release.Format != format || release.Version != "1.2.0" {
+			t.Errorf("%s: unexpected release manifest: %+v", format, release)
+		}
+
+		data := extracted[PackName]
+		if format == FormatCanon {
+			data = extracted["books/Obad/ch01.json"]
+		}
+		data.Data = bytes.Replace(data.Data, []byte("Obad"), []byte("Obxd"), 1)
+		if _, err := kjvcorpus.OpenFS(extracted, kjvcorpus.WithSignatureVerification(pub), kjvcorpus.WithStrictScan()); err == nil {
+			t.Errorf("%s: expected a changed release to fail to open", format)
+		}
+	}
+}
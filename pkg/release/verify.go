@@ -0,0 +1,109 @@
+package release
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// ErrBadSignature is returned by Verify for an artifact whose SHA256SUMS was not signed by the key
+// it was verified with, or was changed after signing
+var ErrBadSignature = kjvcorpus.ErrSignatureMismatch
+
+// maxReleaseFile is the size above which Verify does not buffer corpus.json, SHA256SUMS, or
+// SHA256SUMS.sig
+const maxReleaseFile = 16 << 20
+
+// Verify reads the artifact in r and checks that SHA256SUMS was signed by pub and that the
+// artifact holds exactly the files it lists, with their checksums. It returns the verified
+// manifest. The artifact is read once, so an app can verify a download before extracting it.
+func Verify(r io.Reader, pub ed25519.PublicKey) (*Manifest, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("a release must be verified with an ed25519 public key")
+	}
+	zr, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	defer zr.Close()
+
+	sums := make(map[string]string)
+	release := make(map[string][]byte)
+	tr := tar.NewReader(zr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read artifact: %w", err)
+		}
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("artifact entry %s is not a regular file", header.Name)
+		}
+		if _, ok := sums[header.Name]; ok {
+			return nil, fmt.Errorf("artifact holds %s more than once", header.Name)
+		}
+
+		hash := sha256.New()
+		var w io.Writer = hash
+		var buf bytes.Buffer
+		keep := header.Name == ManifestName || header.Name == ChecksumsName || header.Name == SignatureName
+		if keep {
+			if header.Size > maxReleaseFile {
+				return nil, fmt.Errorf("%s is too large", header.Name)
+			}
+			w = io.MultiWriter(hash, &buf)
+		}
+		if _, err := io.Copy(w, tr); err != nil { // nolint: gosec
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		sums[header.Name] = hex.EncodeToString(hash.Sum(nil))
+		if keep {
+			release[header.Name] = buf.Bytes()
+		}
+	}
+
+	for _, name := range []string{ManifestName, ChecksumsName, SignatureName} {
+		if _, ok := release[name]; !ok {
+			return nil, fmt.Errorf("artifact has no %s", name)
+		}
+	}
+
+	listed, err := kjvcorpus.VerifyChecksums(release[ChecksumsName], release[SignatureName], pub)
+	if err != nil {
+		return nil, err
+	}
+	for name, want := range listed {
+		got, ok := sums[name]
+		if !ok {
+			return nil, fmt.Errorf("artifact is missing %s", name)
+		}
+		if got != want {
+			return nil, fmt.Errorf("checksum mismatch for %s", name)
+		}
+	}
+	for name := range sums {
+		if _, ok := listed[name]; !ok && name != ChecksumsName && name != SignatureName {
+			return nil, fmt.Errorf("artifact holds %s, which is not in %s", name, ChecksumsName)
+		}
+	}
+
+	manifest, err := model.ParseReleaseManifest(release[ManifestName])
+	if err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,114 @@
+package search
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+	"github.com/julianstephens/kjv-sources/pkg/normalize"
+)
+
+// Analyzer is how an index reduces words to the terms it stores. Queries are reduced the same
+// way, so an index records its analyzer in its header and searches with it, whatever the caller
+// would build with.
+type Analyzer struct {
+	Fold      bool     `json:"fold,omitempty"`       // fold early modern English forms to modern ones, such as "sheweth" to "shows"
+	Stem      bool     `json:"stem,omitempty"`       // reduce words to their Porter stems, such as "loved" and "loving" to "love"
+	StopWords []string `json:"stop_words,omitempty"` // words left out of the index and of queries, in lowercase
+
+	// Normalization is the table Fold normalizes words with. Build uses the corpus's
+	// normalization.json when it is nil, and records the table used, so an index read back folds
+	// queries the same way even if the canon's table has since changed.
+	Normalization *model.Normalization `json:"normalization,omitempty"`
+}
+
+// String describes the analyzer for display, such as "fold, stem, 12 stop words"
+func (a Analyzer) String() string {
+	var parts []string
+	if a.Fold {
+		parts = append(parts, "fold")
+	}
+	if a.Stem {
+		parts = append(parts, "stem")
+	}
+	if len(a.StopWords) > 0 {
+		parts = append(parts, fmt.Sprintf("%d stop words", len(a.StopWords)))
+	}
+	if len(parts) == 0 {
+		return "exact words"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// analysis is an analyzer ready to reduce words
+type analysis struct {
+	Analyzer
+	stop       map[string]bool
+	normalizer *normalize.Normalizer
+}
+
+func newAnalysis(a Analyzer) analysis {
+	stop := make(map[string]bool, len(a.StopWords))
+	for _, word := range a.StopWords {
+		stop[strings.ToLower(word)] = true
+	}
+	return analysis{Analyzer: a, stop: stop, normalizer: normalize.New(a.Normalization)}
+}
+
+// term returns the term a lowercase word is indexed and searched as, or "" for a stop word. A
+// word is a stop word if it is in the list as written or once folded.
+func (a analysis) term(word string) string {
+	if a.stop[word] {
+		return ""
+	}
+	if a.Fold {
+		word = a.normalizer.Word(word)
+		if a.stop[word] {
+			return ""
+		}
+	}
+	if a.Stem {
+		word = stem(word)
+	}
+	return word
+}
+
+// stopWordLists are the stop-word lists StopWords returns by name
+var stopWordLists = map[string][]string{
+	"english": {
+		"a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "from", "he", "her", "him",
+		"his", "i", "if", "in", "into", "is", "it", "its", "me", "my", "not", "of", "on", "or",
+		"our", "she", "so", "that", "the", "their", "them", "then", "there", "they", "this", "to",
+		"was", "we", "were", "which", "who", "will", "with", "you", "your",
+	},
+	"kjv": {
+		"doth", "hast", "hath", "shall", "shalt", "thee", "thine", "thou", "thy", "unto", "upon",
+		"ye",
+	},
+}
+
+// StopWordLists returns the names of the built-in stop-word lists, in order
+func StopWordLists() []string {
+	names := make([]string, 0, len(stopWordLists))
+	for name := range stopWordLists {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// StopWords returns a built-in stop-word list: "english" for common English function words, or
+// "kjv" for those and the archaic pronouns and auxiliaries of the KJV, such as "thee" and "hath".
+// It reports false for an unknown list.
+func StopWords(name string) ([]string, bool) {
+	words, ok := stopWordLists[name]
+	if !ok {
+		return nil, false
+	}
+	if name == "kjv" {
+		words = append(slices.Clone(stopWordLists["english"]), words...)
+		slices.Sort(words)
+	}
+	return slices.Clone(words), true
+}
@@ -0,0 +1,88 @@
+package search
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestStem(t *testing.T) {
+	for word, want := range map[string]string{
+		"caresses":        "caress",
+		"ponies":          "poni",
+		"hopping":         "hop",
+		"filing":          "file",
+		"agreed":          "agre",
+		"relational":      "relat",
+		"generalizations": "gener",
+		"adjustable":      "adjust",
+		"controlling":     "control",
+		"loved":           "love",
+		"loving":          "love",
+		"man's":           "man's",
+		"is":              "is",
+	} {
+		if got := stem(word); got != want {
+			t.Errorf("stem(%q): expected %q, got %q", word, want, got)
+		}
+	}
+}
+
+func TestStopWords(t *testing.T) {
+	english, ok := StopWords("english")
+	if !ok || !slices.Contains(english, "the") || slices.Contains(english, "thee") {
+		t.Errorf("unexpected english list: %v", english)
+	}
+	kjv, ok := StopWords("kjv")
+	if !ok || !slices.Contains(kjv, "the") || !slices.Contains(kjv, "thee") || !slices.IsSorted(kjv) {
+		t.Errorf("unexpected kjv list: %v", kjv)
+	}
+	if _, ok := StopWords("latin"); ok {
+		t.Error("expected an unknown list to be reported")
+	}
+	if !slices.Equal(StopWordLists(), []string{"english", "kjv"}) {
+		t.Errorf("unexpected lists: %v", StopWordLists())
+	}
+}
+
+func TestAnalyzer(t *testing.T) {
+	stop, _ := StopWords("kjv")
+	ix := buildIndex(t, Analyzer{Fold: true, Stem: true, StopWords: stop})
+
+	// "shows" is folded and stemmed as "sheweth" and "shewed" are
+	result, err := ix.Search("shows knowledge", Options{})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	var found bool
+	for _, hit := range result.Hits {
+		if hit.Reference == "Psalms 19:2" {
+			found = true
+			if got := hit.Highlight("[", "]"); got != "Day unto day uttereth speech, and night unto night [sheweth] [knowledge]." {
+				t.Errorf("unexpected highlight: %q", got)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected Psalms 19:2 among %d hits", result.Total)
+	}
+
+	// Stop words are left out of the query, and a query of nothing else has no words
+	with, err := ix.Search("the love of thy God", Options{})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	without, err := ix.Search("love God", Options{})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if with.Total == 0 || with.Total != without.Total {
+		t.Errorf("expected stop words ignored, got %d and %d hits", with.Total, without.Total)
+	}
+	if _, err := ix.Search("unto thee", Options{}); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("expected ErrEmptyQuery, got %v", err)
+	}
+	if got := ix.Analyzer().String(); got != "fold, stem, 61 stop words" {
+		t.Errorf("unexpected analyzer description: %q", got)
+	}
+}
@@ -0,0 +1,153 @@
+package search
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+// IndexSchema is the current schema version of index files written by Index.Write
+const IndexSchema = 1
+
+// indexMagic starts every index file, so other files are rejected before decoding
+const indexMagic = "kjvsearch-index\n"
+
+// ErrInvalidIndex is returned when an index file is not one Index.Write wrote
+var ErrInvalidIndex = errors.New("invalid search index")
+
+// Header describes an index file. It is written on a line of its own before the index, so
+// ReadHeader can report how an index was built without decoding it.
+type Header struct {
+	Schema   int      `json:"schema"`
+	Analyzer Analyzer `json:"analyzer"`
+	Verses   int      `json:"verses"`
+	Terms    int      `json:"terms"`
+}
+
+// Header returns the header Write writes for the index
+func (ix *Index) Header() Header {
+	return Header{
+		Schema:   IndexSchema,
+		Analyzer: ix.analysis.Analyzer,
+		Verses:   len(ix.verses),
+		Terms:    len(ix.postings),
+	}
+}
+
+// indexBody is the index after the header: the verses in canonical order, and postings holding
+// indices into them
+type indexBody struct {
+	Verses   []verseKey       `json:"verses"`
+	Postings map[string][]int `json:"postings"`
+}
+
+// MarshalJSON encodes a verse compactly as [osis, chapter, verse]
+func (k verseKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{k.osis, k.chapter, k.verse})
+}
+
+// UnmarshalJSON decodes a verse encoded by MarshalJSON
+func (k *verseKey) UnmarshalJSON(data []byte) error {
+	var triple [3]json.RawMessage
+	if err := json.Unmarshal(data, &triple); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(triple[0], &k.osis); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(triple[1], &k.chapter); err != nil {
+		return err
+	}
+	return json.Unmarshal(triple[2], &k.verse)
+}
+
+// Write writes the index to w with its header, for Read to load without indexing the corpus
+// again. The index is tied to the canon it was built from; rebuild it when the canon changes.
+func (ix *Index) Write(w io.Writer) error {
+	header, err := json.Marshal(ix.Header())
+	if err != nil {
+		return fmt.Errorf("failed to encode index header: %w", err)
+	}
+	data, err := json.Marshal(&indexBody{Verses: ix.verses, Postings: ix.postings})
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+
+	out := append([]byte(indexMagic), header...)
+	out = append(append(out, '\n'), data...)
+	if _, err := w.Write(out); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
+
+// ReadHeader reads the header of an index file written by Index.Write, failing with
+// ErrInvalidIndex for anything else
+func ReadHeader(r io.Reader) (*Header, error) {
+	header, _, err := readHeader(bufio.NewReader(r))
+	return header, err
+}
+
+// readHeader reads the magic and header lines, leaving br at the index body
+func readHeader(br *bufio.Reader) (*Header, *bufio.Reader, error) {
+	magic, err := br.ReadString('\n')
+	if err != nil || magic != indexMagic {
+		return nil, nil, fmt.Errorf("%w: not a search index", ErrInvalidIndex)
+	}
+	line, err := br.ReadBytes('\n')
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: missing header: %w", ErrInvalidIndex, err)
+	}
+	var header Header
+	if err := json.Unmarshal(line, &header); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidIndex, err)
+	}
+	if header.Schema != IndexSchema {
+		return nil, nil, fmt.Errorf("%w: unsupported schema version %d", ErrInvalidIndex, header.Schema)
+	}
+	return &header, br, nil
+}
+
+// Read loads an index written by Index.Write for searching corpus, analyzing queries with the
+// analyzer its header records. Verses are checked against the corpus, so an index of another
+// canon fails with ErrInvalidIndex.
+func Read(r io.Reader, corpus *kjvcorpus.Corpus) (*Index, error) {
+	header, br, err := readHeader(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+	var body indexBody
+	if err := json.NewDecoder(br).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIndex, err)
+	}
+
+	ix := &Index{
+		corpus:   corpus,
+		analysis: newAnalysis(header.Analyzer),
+		verses:   body.Verses,
+		postings: body.Postings,
+	}
+	if ix.postings == nil {
+		ix.postings = make(map[string][]int)
+	}
+	if len(ix.verses) != header.Verses {
+		return nil, fmt.Errorf("%w: header lists %d verses, found %d", ErrInvalidIndex, header.Verses, len(ix.verses))
+	}
+	for _, key := range ix.verses {
+		if !corpus.HasChapter(key.osis, key.chapter) {
+			return nil, fmt.Errorf("%w: %s %d is not in the canon", ErrInvalidIndex, key.osis, key.chapter)
+		}
+	}
+	for term, list := range ix.postings {
+		for _, n := range list {
+			if n < 0 || n >= len(ix.verses) {
+				return nil, fmt.Errorf("%w: term %q lists verse %d of %d", ErrInvalidIndex, term, n, len(ix.verses))
+			}
+		}
+	}
+	return ix, nil
+}
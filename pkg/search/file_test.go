@@ -0,0 +1,61 @@
+package search
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteRead(t *testing.T) {
+	corpus := openCorpus(t)
+	ix, err := Build(corpus, Analyzer{Fold: true, Stem: true})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ix.Write(&buf); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	header, err := ReadHeader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadHeader failed: %v", err)
+	}
+	if !reflect.DeepEqual(*header, ix.Header()) || header.Schema != IndexSchema || !header.Analyzer.Fold || !header.Analyzer.Stem {
+		t.Errorf("unexpected header: %+v", header)
+	}
+
+	// The analyzer comes from the header, so queries are folded and stemmed as the verses were
+	read, err := Read(bytes.NewReader(buf.Bytes()), corpus)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !reflect.DeepEqual(read.Analyzer(), ix.Analyzer()) || read.Verses() != ix.Verses() {
+		t.Errorf("expected the index read back, got %+v with %d verses", read.Analyzer(), read.Verses())
+	}
+	want, err := ix.Search("sheweth", Options{Context: 1})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	got, err := read.Search("sheweth", Options{Context: 1})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if want.Total == 0 || !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the same %d hits from the index read back, got %d", want.Total, got.Total)
+	}
+
+	for name, data := range map[string]string{
+		"not an index": "{}",
+		"bad schema":   indexMagic + `{"schema":99}` + "\n{}",
+		"bad verse":    indexMagic + `{"schema":1,"verses":1}` + "\n" + `{"verses":[["Nope",1,1]],"postings":{}}`,
+		"bad posting":  indexMagic + `{"schema":1,"verses":1}` + "\n" + `{"verses":[["Gen",1,1]],"postings":{"god":[4]}}`,
+		"bad count":    indexMagic + `{"schema":1,"verses":2}` + "\n" + `{"verses":[["Gen",1,1]],"postings":{}}`,
+	} {
+		if _, err := Read(strings.NewReader(data), corpus); !errors.Is(err, ErrInvalidIndex) {
+			t.Errorf("%s: expected ErrInvalidIndex, got %v", name, err)
+		}
+	}
+}
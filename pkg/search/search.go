@@ -0,0 +1,281 @@
+// Package search is a full-text index of the canon's verses. Index.Search finds the verses that
+// contain every word of a query, with the spans of the matching words for highlighting and the
+// verses around each hit for context. An Analyzer chosen when the index is built can fold early
+// modern English forms, stem words, and leave out stop words; it is saved with the index, so
+// queries are always analyzed as the verses were.
+package search
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// ErrEmptyQuery is returned by Search for a query without any words
+var ErrEmptyQuery = errors.New("query has no words to search for")
+
+// Index is an inverted index of the words of every verse of a corpus, in canonical order
+type Index struct {
+	corpus   *kjvcorpus.Corpus
+	analysis analysis
+	verses   []verseKey       // verse number in the index -> verse
+	postings map[string][]int // word -> ascending verse numbers in the index
+}
+
+// verseKey identifies an indexed verse
+type verseKey struct {
+	osis    string
+	chapter int
+	verse   int
+}
+
+// Options controls what Search returns
+type Options struct {
+	Context int // verses of context on each side of a hit, within its chapter
+	Limit   int // most hits returned; 0 returns every hit
+}
+
+// Result is the verses matching a query
+type Result struct {
+	Query string `json:"query"`
+	Total int    `json:"total"` // verses matching, which may be more than Hits when Limit is set
+	Hits  []Hit  `json:"hits"`
+}
+
+// Hit is a verse matching a query, with the spans of its matching words and its context
+type Hit struct {
+	Ref       kjvcorpus.Ref  `json:"-"`
+	Reference string         `json:"reference"` // such as "John 3:16"
+	Slug      string         `json:"slug"`      // such as "john/3/16"
+	Text      string         `json:"text"`      // plain text of the verse without its paragraph mark
+	Spans     []Span         `json:"spans"`     // matching words in Text, in order
+	Before    []ContextVerse `json:"before,omitempty"`
+	After     []ContextVerse `json:"after,omitempty"`
+}
+
+// Span is a matching word in a hit's text, as byte offsets from Start up to End
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// ContextVerse is a verse before or after a hit
+type ContextVerse struct {
+	V    int    `json:"v"`
+	Text string `json:"text"`
+}
+
+// Build indexes every verse of the corpus, reducing words to terms with analyzer. The zero
+// Analyzer indexes every word as written, ignoring case.
+func Build(corpus *kjvcorpus.Corpus, analyzer Analyzer) (*Index, error) {
+	if analyzer.Fold && analyzer.Normalization == nil {
+		table, err := corpus.Normalization()
+		if err != nil {
+			return nil, err
+		}
+		analyzer.Normalization = table
+	}
+	ix := &Index{corpus: corpus, analysis: newAnalysis(analyzer), postings: make(map[string][]int)}
+	for ch, err := range corpus.Chapters() {
+		if err != nil {
+			return nil, fmt.Errorf("failed to index: %w", err)
+		}
+		for _, verse := range ch.Verses {
+			n := len(ix.verses)
+			ix.verses = append(ix.verses, verseKey{osis: ch.OSIS, chapter: ch.Chapter, verse: verse.V})
+			for _, word := range words(verseText(verse)) {
+				term := ix.analysis.term(word.term)
+				if term == "" {
+					continue
+				}
+				list := ix.postings[term]
+				if len(list) == 0 || list[len(list)-1] != n {
+					ix.postings[term] = append(list, n)
+				}
+			}
+		}
+	}
+	return ix, nil
+}
+
+// Verses returns the number of verses indexed
+func (ix *Index) Verses() int {
+	return len(ix.verses)
+}
+
+// Analyzer returns the analyzer the index was built with, which Search analyzes queries with
+func (ix *Index) Analyzer() Analyzer {
+	return ix.analysis.Analyzer
+}
+
+// Search finds the verses containing every word of query, ignoring case and punctuation, in
+// canonical order. Words are analyzed with the index's analyzer, so with folding and stemming
+// "loved" also finds "loveth", and stop words in the query are ignored. Each hit carries the spans of the words
+// that matched and, with opts.Context, the verses around it; context does not run past the hit's
+// chapter. A query without words, or with only stop words, fails with ErrEmptyQuery.
+func (ix *Index) Search(query string, opts Options) (*Result, error) {
+	terms := make(map[string]bool)
+	for _, word := range words(query) {
+		if term := ix.analysis.term(word.term); term != "" {
+			terms[term] = true
+		}
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrEmptyQuery, query)
+	}
+
+	var matches []int
+	first := true
+	for term := range terms {
+		if first {
+			matches = ix.postings[term]
+			first = false
+			continue
+		}
+		matches = intersect(matches, ix.postings[term])
+	}
+
+	result := &Result{Query: query, Total: len(matches), Hits: []Hit{}}
+	if opts.Limit > 0 && len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+
+	// Hits are in canonical order, so each chapter is resolved once for all its hits
+	var chapter *kjvcorpus.Resolved
+	for _, n := range matches {
+		key := ix.verses[n]
+		if chapter == nil || chapter.Chapter.OSIS != key.osis || chapter.Chapter.Chapter != key.chapter {
+			resolved, err := ix.corpus.ResolveRef(kjvcorpus.Ref{OSIS: key.osis, Chapter: key.chapter})
+			if err != nil {
+				return nil, err
+			}
+			chapter = resolved
+		}
+		result.Hits = append(result.Hits, ix.newHit(chapter, key.verse, terms, opts.Context))
+	}
+	return result, nil
+}
+
+// newHit builds the hit for a verse of a resolved chapter
+func (ix *Index) newHit(chapter *kjvcorpus.Resolved, v int, terms map[string]bool, context int) Hit {
+	at := 0
+	for i, verse := range chapter.Verses {
+		if verse.V == v {
+			at = i
+			break
+		}
+	}
+
+	text := verseText(chapter.Verses[at])
+	ref := kjvcorpus.Ref{OSIS: chapter.Chapter.OSIS, Chapter: chapter.Chapter.Chapter, Verses: &kjvcorpus.VerseRange{Start: v}}
+	hit := Hit{
+		Ref:       ref,
+		Reference: fmt.Sprintf("%s %d:%d", chapter.BookName, ref.Chapter, v),
+		Slug:      ref.Slug(),
+		Text:      text,
+		Spans:     []Span{},
+	}
+	for _, word := range words(text) {
+		if terms[ix.analysis.term(word.term)] {
+			hit.Spans = append(hit.Spans, word.span)
+		}
+	}
+	for i := max(at-context, 0); i < at; i++ {
+		hit.Before = append(hit.Before, ContextVerse{V: chapter.Verses[i].V, Text: verseText(chapter.Verses[i])})
+	}
+	for i := at + 1; i <= at+context && i < len(chapter.Verses); i++ {
+		hit.After = append(hit.After, ContextVerse{V: chapter.Verses[i].V, Text: verseText(chapter.Verses[i])})
+	}
+	return hit
+}
+
+// Highlight returns the hit's text with each matching word between open and close, such as
+// "==" and "==" for text or "<mark>" and "</mark>" for HTML. The text is not escaped; use
+// HighlightHTML for HTML.
+func (h Hit) Highlight(open, close string) string {
+	return h.highlight(open, close, func(s string) string { return s })
+}
+
+// HighlightHTML returns the hit's text escaped for HTML with each matching word in a <mark> element
+func (h Hit) HighlightHTML() string {
+	return h.highlight("<mark>", "</mark>", html.EscapeString)
+}
+
+// highlight wraps the spans of the hit's text, passing the text between them through escape
+func (h Hit) highlight(open, close string, escape func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, span := range h.Spans {
+		b.WriteString(escape(h.Text[last:span.Start]))
+		b.WriteString(open)
+		b.WriteString(escape(h.Text[span.Start:span.End]))
+		b.WriteString(close)
+		last = span.End
+	}
+	b.WriteString(escape(h.Text[last:]))
+	return b.String()
+}
+
+// word is a word of a verse: its indexed form and where it is in the text
+type word struct {
+	term string
+	span Span
+}
+
+// words splits text into words as analyze.Words does: runs of letters, keeping apostrophes and
+// hyphens inside a word, as in "man's" and "beer-sheba", indexed in lowercase
+func words(text string) []word {
+	var result []word
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && (r == '\'' || r == '’' || r == '-') {
+			next, _ := utf8.DecodeRuneInString(text[i+utf8.RuneLen(r):])
+			if unicode.IsLetter(next) {
+				continue
+			}
+		}
+		if start >= 0 {
+			result = append(result, word{term: strings.ToLower(text[start:i]), span: Span{Start: start, End: i}})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		result = append(result, word{term: strings.ToLower(text[start:]), span: Span{Start: start, End: len(text)}})
+	}
+	return result
+}
+
+// verseText returns a verse's plain text without its paragraph mark
+func verseText(verse model.Verse) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(verse.Plain), "¶"))
+}
+
+// intersect returns the verse numbers in both ascending lists
+func intersect(a, b []int) []int {
+	var result []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
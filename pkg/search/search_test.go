@@ -0,0 +1,122 @@
+package search
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+// openCorpus opens canon/kjv from the project root
+func openCorpus(t *testing.T) *kjvcorpus.Corpus {
+	t.Helper()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+
+	corpus, err := kjvcorpus.Open(filepath.Join(cwd, "canon", "kjv"))
+	if err != nil {
+		t.Fatalf("failed to open corpus: %v", err)
+	}
+	return corpus
+}
+
+// buildIndex indexes canon/kjv with analyzer
+func buildIndex(t *testing.T, analyzer Analyzer) *Index {
+	t.Helper()
+
+	ix, err := Build(openCorpus(t), analyzer)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return ix
+}
+
+func TestSearch(t *testing.T) {
+	ix := buildIndex(t, Analyzer{})
+	if ix.Verses() < 31102 {
+		t.Errorf("expected every verse indexed, got %d", ix.Verses())
+	}
+
+	// Every word is required, in any case and order
+	result, err := ix.Search("WEPT jesus", Options{Context: 1})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if result.Total != 3 || len(result.Hits) != 3 || result.Hits[0].Reference != "Matthew 26:75" {
+		t.Fatalf("expected three hits from Matthew 26:75, got %+v", result)
+	}
+	hit := result.Hits[2]
+	if hit.Reference != "John 11:35" || hit.Slug != "john/11/35" || hit.Text != "Jesus wept." {
+		t.Errorf("unexpected hit: %+v", hit)
+	}
+	if !reflect.DeepEqual(hit.Spans, []Span{{0, 5}, {6, 10}}) {
+		t.Errorf("unexpected spans: %+v", hit.Spans)
+	}
+	if len(hit.Before) != 1 || hit.Before[0].V != 34 || len(hit.After) != 1 || hit.After[0].V != 36 {
+		t.Errorf("expected one verse of context on each side, got %+v and %+v", hit.Before, hit.After)
+	}
+	if got := hit.Highlight("==", "=="); got != "==Jesus== ==wept==." {
+		t.Errorf("unexpected highlight: %q", got)
+	}
+
+	// Context stops at the chapter's edges
+	result, err = ix.Search("in the beginning God created", Options{Context: 2})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(result.Hits) == 0 || result.Hits[0].Reference != "Genesis 1:1" || len(result.Hits[0].Before) != 0 || len(result.Hits[0].After) != 2 {
+		t.Errorf("expected Genesis 1:1 first with context after it only, got %+v", result.Hits)
+	}
+
+	// Limit caps the hits but not the total
+	result, err = ix.Search("Lord", Options{Limit: 5})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(result.Hits) != 5 || result.Total <= 5 || result.Hits[0].Reference != "Genesis 2:4" {
+		t.Errorf("expected 5 hits from Genesis 2:4 of more, got %d of %d starting %s", len(result.Hits), result.Total, result.Hits[0].Reference)
+	}
+
+	if result, err := ix.Search("zzyzx", Options{}); err != nil || result.Total != 0 || result.Hits == nil {
+		t.Errorf("expected an empty result, got %+v, %v", result, err)
+	}
+	if _, err := ix.Search(" ... ", Options{}); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("expected ErrEmptyQuery, got %v", err)
+	}
+}
+
+func TestWords(t *testing.T) {
+	text := "¶ And Abram's <wife>, Beer-sheba; 'tis—Lord’s"
+	var got []string
+	for _, w := range words(text) {
+		got = append(got, w.term)
+		if text[w.span.Start:w.span.End] == "" {
+			t.Errorf("empty span for %q", w.term)
+		}
+	}
+	want := []string{"and", "abram's", "wife", "beer-sheba", "tis", "lord’s"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	hit := Hit{Text: "a < b", Spans: []Span{{0, 1}, {4, 5}}}
+	if got := hit.HighlightHTML(); got != "<mark>a</mark> &lt; <mark>b</mark>" {
+		t.Errorf("unexpected HTML highlight: %q", got)
+	}
+}
@@ -0,0 +1,58 @@
+package testament
+
+import (
+	"slices"
+	"strings"
+)
+
+// groups lists the books of each traditional category within the testaments, in canonical order
+var groups = map[string][]string{
+	"law":              oldTestament[0:5],
+	"history":          oldTestament[5:17],
+	"poetry":           oldTestament[17:22],
+	"major-prophets":   oldTestament[22:27],
+	"minor-prophets":   oldTestament[27:39],
+	"prophets":         oldTestament[22:39],
+	"gospels":          newTestament[0:4],
+	"pauline-epistles": newTestament[5:18],
+	"general-epistles": newTestament[18:26],
+	"epistles":         newTestament[5:26],
+	"deuterocanon": slices.DeleteFunc(slices.Clone(apocrypha), func(osis string) bool {
+		return !deuterocanon[osis]
+	}),
+}
+
+// groupAliases maps other common names of groups to their names in groups
+var groupAliases = map[string]string{
+	"pentateuch": "law",
+	"torah":      "law",
+	"wisdom":     "poetry",
+	"pauline":    "pauline-epistles",
+	"general":    "general-epistles",
+	"catholic":   "general-epistles",
+}
+
+// Group returns the OSIS codes of a named category of books, such as "gospels" or "minor-prophets",
+// in canonical order. Names are matched case-insensitively, and "pentateuch" and "torah" name the
+// law, "wisdom" poetry, and "pauline" and "general" the epistles.
+func Group(name string) ([]string, bool) {
+	name = strings.ToLower(name)
+	if alias, ok := groupAliases[name]; ok {
+		name = alias
+	}
+	books, ok := groups[name]
+	if !ok {
+		return nil, false
+	}
+	return slices.Clone(books), true
+}
+
+// Groups returns the names of all book groups in sorted order
+func Groups() []string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,48 @@
+package testament
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	tests := []struct {
+		name        string
+		count       int
+		first, last string
+	}{
+		{"law", 5, "Gen", "Deut"},
+		{"Pentateuch", 5, "Gen", "Deut"},
+		{"history", 12, "Josh", "Esth"},
+		{"wisdom", 5, "Job", "Song"},
+		{"major-prophets", 5, "Isa", "Dan"},
+		{"minor-prophets", 12, "Hos", "Mal"},
+		{"prophets", 17, "Isa", "Mal"},
+		{"gospels", 4, "Matt", "John"},
+		{"pauline-epistles", 13, "Rom", "Phlm"},
+		{"general", 8, "Heb", "Jude"},
+		{"epistles", 21, "Rom", "Jude"},
+		{"deuterocanon", 14, "Tob", "PrAzar"},
+	}
+	for _, tt := range tests {
+		books, ok := Group(tt.name)
+		if !ok || len(books) != tt.count || books[0] != tt.first || books[len(books)-1] != tt.last {
+			t.Errorf("Group(%q) = %v, %v; want %d books from %s to %s", tt.name, books, ok, tt.count, tt.first, tt.last)
+		}
+	}
+
+	if _, ok := Group("apostles"); ok {
+		t.Error("expected no group named apostles")
+	}
+
+	// Callers may modify the books they are given
+	books, _ := Group("gospels")
+	books[0] = "Thomas"
+	if again, _ := Group("gospels"); again[0] != "Matt" {
+		t.Errorf("Group shares its backing array: %v", again)
+	}
+
+	if names := Groups(); !slices.IsSorted(names) || !slices.Contains(names, "gospels") || slices.Contains(names, "wisdom") {
+		t.Errorf("unexpected group names: %v", names)
+	}
+}
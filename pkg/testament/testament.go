@@ -0,0 +1,139 @@
+// Package testament classifies books by OSIS code into the Old Testament, the Apocrypha, and the
+// New Testament, and identifies the deuterocanonical books among the Apocrypha. Books are listed by
+// canonical OSIS ID, such as "1Sam", and codes in the older spaced form, such as "1 Sam", are
+// classified the same.
+package testament
+
+import (
+	"fmt"
+
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+// Testament is a section of the canon, as recorded in books.json
+type Testament string
+
+const (
+	OT Testament = "OT" // Old Testament
+	AP Testament = "AP" // Apocrypha
+	NT Testament = "NT" // New Testament
+)
+
+// All lists the testaments in canonical order
+var All = []Testament{OT, AP, NT}
+
+// oldTestament lists the Old Testament books in canonical order
+var oldTestament = []string{
+	"Gen", "Exod", "Lev", "Num", "Deut", "Josh", "Judg", "Ruth", "1Sam", "2Sam",
+	"1Kgs", "2Kgs", "1Chr", "2Chr", "Ezra", "Neh", "Esth", "Job", "Ps", "Prov",
+	"Eccl", "Song", "Isa", "Jer", "Lam", "Ezek", "Dan", "Hos", "Joel", "Amos",
+	"Obad", "Jonah", "Mic", "Nah", "Hab", "Zeph", "Hag", "Zech", "Mal",
+}
+
+// apocrypha lists the books of the KJV Apocrypha in canonical order, followed by the other
+// apocryphal books OSIS defines that the KJV does not carry
+var apocrypha = []string{
+	"Tob", "Jdt", "AddEsth", "Wis", "Sir", "Bar", "SgThree", "Sus", "Bel",
+	"1Macc", "2Macc", "1Esd", "PrMan", "2Esd",
+	"EpJer", "AddDan", "PrAzar", "3Macc", "4Macc", "Ps151",
+}
+
+// newTestament lists the New Testament books in canonical order
+var newTestament = []string{
+	"Matt", "Mark", "Luke", "John", "Acts", "Rom", "1Cor", "2Cor", "Gal", "Eph",
+	"Phil", "Col", "1Thess", "2Thess", "1Tim", "2Tim", "Titus", "Phlm", "Heb", "Jas",
+	"1Pet", "2Pet", "1John", "2John", "3John", "Jude", "Rev",
+}
+
+// deuterocanon is the set of apocryphal books received as canonical by the Roman Catholic Church.
+// 1 and 2 Esdras, the Prayer of Manasses, 3 and 4 Maccabees, and Psalm 151 are not among them.
+var deuterocanon = map[string]bool{
+	"Tob": true, "Jdt": true, "AddEsth": true, "Wis": true, "Sir": true, "Bar": true, "EpJer": true,
+	"AddDan": true, "PrAzar": true, "SgThree": true, "Sus": true, "Bel": true, "1Macc": true, "2Macc": true,
+}
+
+// byOSIS maps every classified OSIS code to its testament
+var byOSIS = func() map[string]Testament {
+	m := make(map[string]Testament)
+	for t, books := range map[Testament][]string{OT: oldTestament, AP: apocrypha, NT: newTestament} {
+		for _, osis := range books {
+			m[osis] = t
+		}
+	}
+	return m
+}()
+
+// Parse returns the testament for a books.json testament code
+func Parse(code string) (Testament, error) {
+	t := Testament(code)
+	if !t.Valid() {
+		return "", fmt.Errorf("unknown testament %q (want OT, AP, or NT)", code)
+	}
+	return t, nil
+}
+
+// Valid reports whether t is one of OT, AP, or NT
+func (t Testament) Valid() bool {
+	return t == OT || t == AP || t == NT
+}
+
+// Name returns the English name of the testament
+func (t Testament) Name() string {
+	switch t {
+	case OT:
+		return "Old Testament"
+	case AP:
+		return "Apocrypha"
+	case NT:
+		return "New Testament"
+	default:
+		return string(t)
+	}
+}
+
+// Of returns the testament of a book, or false if the OSIS code is not classified
+func Of(osis string) (Testament, bool) {
+	t, ok := byOSIS[model.CanonicalOSIS(osis)]
+	return t, ok
+}
+
+// Books returns the OSIS codes classified under t, in canonical order
+func Books(t Testament) []string {
+	switch t {
+	case OT:
+		return append([]string(nil), oldTestament...)
+	case AP:
+		return append([]string(nil), apocrypha...)
+	case NT:
+		return append([]string(nil), newTestament...)
+	default:
+		return nil
+	}
+}
+
+// IsOldTestament reports whether the book belongs to the Old Testament
+func IsOldTestament(osis string) bool {
+	return byOSIS[model.CanonicalOSIS(osis)] == OT
+}
+
+// IsApocryphal reports whether the book belongs to the Apocrypha
+func IsApocryphal(osis string) bool {
+	return byOSIS[model.CanonicalOSIS(osis)] == AP
+}
+
+// IsNewTestament reports whether the book belongs to the New Testament
+func IsNewTestament(osis string) bool {
+	return byOSIS[model.CanonicalOSIS(osis)] == NT
+}
+
+// IsDeuterocanonical reports whether the book is one of the apocryphal books received as
+// canonical by the Roman Catholic Church
+func IsDeuterocanonical(osis string) bool {
+	return deuterocanon[model.CanonicalOSIS(osis)]
+}
+
+// IsProtocanonical reports whether the book belongs to the Old or New Testament
+func IsProtocanonical(osis string) bool {
+	t := byOSIS[model.CanonicalOSIS(osis)]
+	return t == OT || t == NT
+}
@@ -0,0 +1,80 @@
+package testament
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		osis           string
+		want           Testament
+		apocryphal     bool
+		deuterocanon   bool
+		protocanonical bool
+	}{
+		{"Gen", OT, false, false, true},
+		{"Mal", OT, false, false, true},
+		{"Tob", AP, true, true, false},
+		{"AddEsth", AP, true, true, false},
+		{"1Esd", AP, true, false, false},
+		{"PrMan", AP, true, false, false},
+		{"1Cor", NT, false, false, true},
+		// Codes from canons ingested before OSIS IDs were normalized classify the same
+		{"Add Esth", AP, true, true, false},
+		{"1 Cor", NT, false, false, true},
+		{"Matt", NT, false, false, true},
+		{"Rev", NT, false, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.osis, func(t *testing.T) {
+			got, ok := Of(tt.osis)
+			if !ok || got != tt.want {
+				t.Errorf("Of(%q) = %q, %v; want %q", tt.osis, got, ok, tt.want)
+			}
+			if IsApocryphal(tt.osis) != tt.apocryphal {
+				t.Errorf("IsApocryphal(%q) = %v", tt.osis, !tt.apocryphal)
+			}
+			if IsDeuterocanonical(tt.osis) != tt.deuterocanon {
+				t.Errorf("IsDeuterocanonical(%q) = %v", tt.osis, !tt.deuterocanon)
+			}
+			if IsProtocanonical(tt.osis) != tt.protocanonical {
+				t.Errorf("IsProtocanonical(%q) = %v", tt.osis, !tt.protocanonical)
+			}
+		})
+	}
+
+	if _, ok := Of("Nope"); ok {
+		t.Error("expected an unknown book to be unclassified")
+	}
+	if IsOldTestament("Nope") || IsApocryphal("Nope") || IsNewTestament("Nope") || IsProtocanonical("Nope") {
+		t.Error("expected an unknown book to belong to no testament")
+	}
+}
+
+func TestBooks(t *testing.T) {
+	if n := len(Books(OT)); n != 39 {
+		t.Errorf("expected 39 Old Testament books, got %d", n)
+	}
+	if n := len(Books(NT)); n != 27 {
+		t.Errorf("expected 27 New Testament books, got %d", n)
+	}
+	if Books("XX") != nil {
+		t.Error("expected no books for an unknown testament")
+	}
+
+	books := Books(OT)
+	books[0] = "changed"
+	if Books(OT)[0] != "Gen" {
+		t.Error("expected Books to return a copy")
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, code := range []string{"OT", "AP", "NT"} {
+		if _, err := Parse(code); err != nil {
+			t.Errorf("Parse(%q) failed: %v", code, err)
+		}
+	}
+	if _, err := Parse("ot"); err == nil {
+		t.Error("expected Parse to reject a lowercase code")
+	}
+}
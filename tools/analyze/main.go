@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/julianstephens/kjv-sources/internal/analyze"
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func main() {
+	util.RunCLI(&analyze.Cmd{}, util.CLIOptions{
+		Name:        "kjv-analyze",
+		Description: "KJV Text Analysis",
+		Config:      "analyze",
+		Spinners:    map[string]string{"words": "Analyzing", "parallels": "Analyzing", "divine-names": "Analyzing", "added-words": "Analyzing"},
+	})
+}
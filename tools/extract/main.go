@@ -1,30 +1,39 @@
 package main
 
 import (
-	"flag"
+	"os"
+	"strings"
 
+	"github.com/julianstephens/kjv-sources/internal/extract"
 	"github.com/julianstephens/kjv-sources/internal/util"
 )
 
 func main() {
+	os.Args = append(os.Args[:1], legacyArgs(os.Args[1:])...)
 
-	subcommand := flag.String("cmd", "", "Subcommand to run (e.g. 'books', 'aliases')")
-	flag.Parse()
-
-	stop := make(chan bool)
-
-	switch *subcommand {
-	case "books":
-		go util.Spinner("Extracting books", stop)
-		MainBooks(stop)
-	case "aliases":
-		go util.Spinner("Extracting aliases", stop)
-		MainAliases(stop)
-	default:
-		println("Please provide a valid subcommand using -cmd flag (e.g. -cmd=books or -cmd=aliases)")
-	}
+	util.RunCLI(&extract.Cmd{}, util.CLIOptions{
+		Name:        "kjv-extract",
+		Description: "KJV Index Extraction Tool",
+		Config:      "extract",
+		Spinners:    map[string]string{"books": "Extracting books", "aliases": "Extracting aliases"},
+		Deprecated:  "kjvsrc extract",
+	})
+}
 
-	if _, ok := <-stop; ok {
-		close(stop)
+// legacyArgs rewrites the old -cmd=NAME / -cmd NAME flag into the NAME subcommand
+func legacyArgs(args []string) []string {
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := strings.TrimPrefix(args[i], "-")
+		switch {
+		case strings.HasPrefix(arg, "cmd="):
+			out = append([]string{strings.TrimPrefix(arg, "cmd=")}, out...)
+		case arg == "cmd" && i+1 < len(args):
+			out = append([]string{args[i+1]}, out...)
+			i++
+		default:
+			out = append(out, args[i])
+		}
 	}
+	return out
 }
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+type AlignCmd struct {
+	Import AlignImportCmd `cmd:"" help:"Import verse-aligned original-language words as align/ sidecars"`
+}
+
+type AlignImportCmd struct {
+	File     string `arg:""             help:"Tab-separated alignment file (ref, text, lemma, morph, kjv)" type:"existingfile"`
+	Canon    string `type:"existingdir" help:"The canon directory containing index/ and books/"                                  default:"./canon/kjv"`
+	Source   string `                   help:"Name of the alignment's source, recorded in each sidecar"                           required:""`
+	Language string `                   help:"ISO 639-3 code of the original language (hbo, grc, ...)"                            required:""`
+}
+
+func (a *AlignImportCmd) Run(ctx context.Context) error {
+	paths, err := a.importFile()
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Chapters Aligned: %d\n", len(paths))
+	fmt.Printf("Output: %s\n", filepath.Join(a.Canon, "align"))
+	fmt.Printf("========================================\n")
+	return nil
+}
+
+// importFile reads the alignment file, validates every chapter it covers against the canon, and
+// only then writes the sidecars, so a bad file leaves align/ untouched
+func (a *AlignImportCmd) importFile() ([]string, error) {
+	corpus, err := kjvcorpus.Open(a.Canon)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	f, err := os.Open(a.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open alignment file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	alignments, err := readAlignments(f, corpus, a.Source, a.Language)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", a.File, err)
+	}
+
+	var paths []string
+	for _, alignment := range alignments {
+		data, err := json.MarshalIndent(alignment, "", "  ")
+		if err != nil {
+			return paths, fmt.Errorf("failed to marshal alignment: %w", err)
+		}
+		path := filepath.Join(a.Canon, filepath.FromSlash(kjvcorpus.AlignmentPath(alignment.OSIS, alignment.Chapter)))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return paths, fmt.Errorf("failed to create alignment directory: %w", err)
+		}
+		if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+			return paths, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// readAlignments parses tab-separated alignment rows into one validated Alignment per chapter, in
+// canonical order. Each row is one original-language word in source order: a verse reference, the
+// word, its lemma, its morphology, and the comma-separated 0-based positions of the KJV words it is
+// rendered by. Blank lines and lines starting with # are skipped.
+func readAlignments(r io.Reader, corpus *kjvcorpus.Corpus, source, language string) ([]*model.Alignment, error) {
+	type key struct {
+		order   int
+		chapter int
+	}
+	chapters := make(map[key]*model.Alignment)
+	verseAt := make(map[key]map[int]int) // verse number -> index into the alignment's Verses
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("line %d: expected 5 tab-separated fields, got %d", line, len(fields))
+		}
+		ref, err := bibleref.Parse(fields[0], corpus.Table())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid reference %q: %w", line, fields[0], err)
+		}
+		if ref.Verse == nil || (ref.Verse.EndVerse != nil && *ref.Verse.EndVerse != ref.Verse.StartVerse) {
+			return nil, fmt.Errorf("line %d: %q must name a single verse", line, fields[0])
+		}
+		positions, err := parsePositions(fields[4])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		k := key{order: corpus.Table().ByOsis[ref.OSIS].Order, chapter: ref.Chapter}
+		alignment, exists := chapters[k]
+		if !exists {
+			alignment = &model.Alignment{
+				Schema:   model.AlignmentSchema,
+				OSIS:     ref.OSIS,
+				Chapter:  ref.Chapter,
+				Source:   source,
+				Language: language,
+			}
+			chapters[k] = alignment
+			verseAt[k] = make(map[int]int)
+		}
+		i, exists := verseAt[k][ref.Verse.StartVerse]
+		if !exists {
+			i = len(alignment.Verses)
+			verseAt[k][ref.Verse.StartVerse] = i
+			alignment.Verses = append(alignment.Verses, model.VerseAlignment{V: ref.Verse.StartVerse})
+		}
+		alignment.Verses[i].Words = append(alignment.Verses[i].Words, model.AlignedWord{
+			Text:  fields[1],
+			Lemma: fields[2],
+			Morph: fields[3],
+			KJV:   positions,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read alignment file: %w", err)
+	}
+
+	keys := make([]key, 0, len(chapters))
+	for k := range chapters {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].order != keys[j].order {
+			return keys[i].order < keys[j].order
+		}
+		return keys[i].chapter < keys[j].chapter
+	})
+
+	alignments := make([]*model.Alignment, 0, len(keys))
+	for _, k := range keys {
+		alignment := chapters[k]
+		resolved, err := corpus.Resolve(&bibleref.BibleRef{OSIS: alignment.OSIS, Chapter: alignment.Chapter})
+		if err != nil {
+			return nil, err
+		}
+		words := make(map[int]int, len(resolved.Verses))
+		for _, verse := range resolved.Verses {
+			words[verse.V] = len(verse.Words())
+		}
+
+		sort.Slice(alignment.Verses, func(i, j int) bool { return alignment.Verses[i].V < alignment.Verses[j].V })
+		for i := range alignment.Verses {
+			alignment.Verses[i].KJVWords = words[alignment.Verses[i].V]
+		}
+		if err := kjvcorpus.ValidateAlignment(&resolved.Chapter, alignment); err != nil {
+			return nil, err
+		}
+		alignments = append(alignments, alignment)
+	}
+	return alignments, nil
+}
+
+// parsePositions parses a comma-separated list of KJV word positions; an empty field is none
+func parsePositions(field string) ([]int, error) {
+	positions := []int{}
+	if strings.TrimSpace(field) == "" {
+		return positions, nil
+	}
+	for _, s := range strings.Split(field, ",") {
+		position, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid KJV word position %q", s)
+		}
+		positions = append(positions, position)
+	}
+	return positions, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/julianstephens/kjv-sources/internal/clidoc"
+)
+
+type CompletionsCmd struct {
+	Shell string `arg:"" enum:"bash,zsh,fish" help:"Shell to generate completions for (bash, zsh, fish)"`
+}
+
+// Run writes the completion script for the selected shell to stdout
+func (c *CompletionsCmd) Run(ctx *kong.Context) error {
+	return clidoc.Completion(ctx.Stdout, ctx.Model, c.Shell)
+}
+
+type DocsCmd struct {
+	Out string `help:"Directory to write man pages into" default:"./man"`
+}
+
+// Run writes a man page for kjvsrc and each of its subcommands
+func (c *DocsCmd) Run(ctx *kong.Context) error {
+	written, err := clidoc.WriteManPages(c.Out, ctx.Model, manDate())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d man pages to %s\n", len(written), c.Out)
+	return nil
+}
+
+// manDate returns the date stamped on generated man pages, honouring SOURCE_DATE_EPOCH for reproducible builds
+func manDate() string {
+	date := time.Now()
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		date = time.Unix(epoch, 0)
+	}
+	return date.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/internal/normalize"
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+type EditionCmd struct {
+	Import EditionImportCmd `cmd:"" help:"Import another edition of the text, such as the 1611 spellings, as editions/ layers"`
+	Diff   EditionDiffCmd   `cmd:"" help:"Compare a passage word by word in two editions"`
+}
+
+type EditionImportCmd struct {
+	File    string `arg:""             help:"Tab-separated edition file (ref, text)"               type:"existingfile"`
+	Canon   string `type:"existingdir" help:"The canon directory containing index/ and books/"     default:"./canon/kjv"`
+	Edition string `                   help:"The edition the file holds, such as 1611"             default:"1611"`
+	Source  string `                   help:"Name of the edition's source, recorded in each layer" required:""`
+}
+
+type EditionDiffCmd struct {
+	Ref   string `arg:""             help:"Passage to compare, such as \"Gen 1\" or \"John 3:16\""`
+	Canon string `type:"existingdir" help:"The canon directory containing index/ and books/"       default:"./canon/kjv"`
+	From  string `                   help:"The edition to compare from"                            default:"1769"`
+	To    string `                   help:"The edition to compare to"                              default:"1611"`
+}
+
+func (e *EditionImportCmd) Run(ctx context.Context) error {
+	paths, err := e.importFile()
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Chapters Imported: %d\n", len(paths))
+	fmt.Printf("Output: %s\n", filepath.Join(e.Canon, "editions", e.Edition))
+	fmt.Printf("========================================\n")
+	return nil
+}
+
+// importFile reads the edition file, validates every chapter it covers against the canon, and only
+// then writes the layers, so a bad file leaves editions/ untouched
+func (e *EditionImportCmd) importFile() ([]string, error) {
+	if e.Edition == model.EditionBlayney {
+		return nil, fmt.Errorf("the %s edition is the canon itself; re-run ingest to replace it", e.Edition)
+	}
+
+	corpus, err := kjvcorpus.Open(e.Canon)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	f, err := os.Open(e.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open edition file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	layers, err := readEdition(f, corpus, e.Edition, e.Source)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e.File, err)
+	}
+
+	var paths []string
+	for _, layer := range layers {
+		data, err := json.MarshalIndent(layer, "", "  ")
+		if err != nil {
+			return paths, fmt.Errorf("failed to marshal edition layer: %w", err)
+		}
+		path := filepath.Join(e.Canon, filepath.FromSlash(kjvcorpus.EditionPath(layer.Edition, layer.OSIS, layer.Chapter)))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return paths, fmt.Errorf("failed to create edition directory: %w", err)
+		}
+		if err := atomicfile.WriteFile(path, data, 0600); err != nil {
+			return paths, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// readEdition parses tab-separated rows of a verse reference and its text into one validated layer
+// per chapter, in canonical order. Every chapter the file covers must be given in full, verse for
+// verse as the canon numbers it. The text is normalized as ingest normalizes plain text. Blank
+// lines and lines starting with # are skipped.
+func readEdition(r io.Reader, corpus *kjvcorpus.Corpus, edition, source string) ([]*model.Edition, error) {
+	type key struct {
+		order   int
+		chapter int
+	}
+	chapters := make(map[key]*model.Edition)
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected 2 tab-separated fields, got %d", line, len(fields))
+		}
+		ref, err := bibleref.Parse(fields[0], corpus.Table())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid reference %q: %w", line, fields[0], err)
+		}
+		if ref.Verse == nil || (ref.Verse.EndVerse != nil && *ref.Verse.EndVerse != ref.Verse.StartVerse) {
+			return nil, fmt.Errorf("line %d: %q must name a single verse", line, fields[0])
+		}
+
+		k := key{order: corpus.Table().ByOsis[ref.OSIS].Order, chapter: ref.Chapter}
+		layer, exists := chapters[k]
+		if !exists {
+			layer = &model.Edition{
+				Schema:  model.EditionSchema,
+				Edition: edition,
+				OSIS:    ref.OSIS,
+				Chapter: ref.Chapter,
+				Source:  source,
+			}
+			chapters[k] = layer
+		}
+		layer.Verses = append(layer.Verses, model.EditionVerse{V: ref.Verse.StartVerse, Plain: normalize.Text(fields[1])})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read edition file: %w", err)
+	}
+
+	keys := make([]key, 0, len(chapters))
+	for k := range chapters {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].order != keys[j].order {
+			return keys[i].order < keys[j].order
+		}
+		return keys[i].chapter < keys[j].chapter
+	})
+
+	layers := make([]*model.Edition, 0, len(keys))
+	for _, k := range keys {
+		layer := chapters[k]
+		resolved, err := corpus.Resolve(&bibleref.BibleRef{OSIS: layer.OSIS, Chapter: layer.Chapter})
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(layer.Verses, func(i, j int) bool { return layer.Verses[i].V < layer.Verses[j].V })
+		if err := kjvcorpus.ValidateEdition(&resolved.Chapter, layer); err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+func (e *EditionDiffCmd) Run(ctx context.Context) error {
+	util.StopProgress(ctx)
+
+	corpus, err := kjvcorpus.Open(e.Canon)
+	if err != nil {
+		return fmt.Errorf("failed to open canon: %w", err)
+	}
+	ref, err := bibleref.Parse(e.Ref, corpus.Table())
+	if err != nil {
+		return fmt.Errorf("invalid reference %q: %w", e.Ref, err)
+	}
+	resolved, err := corpus.Resolve(ref)
+	if err != nil {
+		return err
+	}
+	diffs, err := corpus.CompareEditions(kjvcorpus.RefFrom(ref), e.From, e.To)
+	if err != nil {
+		return err
+	}
+
+	for _, diff := range diffs {
+		fmt.Printf("%s %d:%d: %s\n", resolved.BookName, resolved.Chapter.Chapter, diff.V, diff)
+	}
+	fmt.Printf("========================================\n")
+	fmt.Printf("Passage: %s (%s against %s)\n", resolved.Reference(), e.To, e.From)
+	fmt.Printf("Verses Differing: %d of %d\n", len(diffs), len(resolved.Verses))
+	fmt.Printf("========================================\n")
+	return nil
+}
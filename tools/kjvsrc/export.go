@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/export"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+type ExportCmd struct {
+	Canon          string   `type:"existingdir" help:"The canon directory containing index/ and books/"                             default:"./canon/kjv"`
+	Out            string   `                   help:"Directory to write exported files beneath"                                    default:"./export"`
+	Format         []string `                   help:"Comma-separated export formats (usfm, osis, markdown, ...)"                   required:""`
+	Book           []string `                   help:"Books to export, by OSIS code or name (default: all)"                         xor:"select"`
+	Passage        []string `                   help:"Passages to export instead of whole books, such as \"Rom 8:28-39\""           xor:"select"`
+	Work           string   `                   help:"The work identifier"                                                          default:"KJV"`
+	VerseNumbers   string   `                   help:"Verse number style in rendered formats (markup, unicode, bracketed, omitted)" default:"markup"      enum:"markup,unicode,bracketed,omitted"`
+	ChapterHeaders bool     `                   help:"Head each chapter with \"Chapter N\" in rendered formats"`
+	DropCap        bool     `                   help:"Mark the first letter of each chapter as a drop cap in rendered formats"`
+	Stylesheet     bool     `                   help:"Embed the default stylesheet in each html chapter"`
+	Fsync          bool     `                   help:"Flush every written file to disk before renaming it into place"`
+	ForceUnlock    bool     `                   help:"Take the lock on --out even from a run holding it, such as a hung one"`
+}
+
+// ExportStats summarises an export run
+type ExportStats struct {
+	Books    int
+	Chapters int
+}
+
+func (e *ExportCmd) Run(ctx context.Context) error {
+	atomicfile.SetSync(e.Fsync)
+	if err := os.MkdirAll(e.Out, 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", e.Out, err)
+	}
+	lock, err := util.AcquireLock(e.Out, "export", e.ForceUnlock)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Release() }()
+
+	stats, err := e.export()
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Books Exported: %d\n", stats.Books)
+	fmt.Printf("Chapters Exported: %d\n", stats.Chapters)
+	fmt.Printf("Output: %s\n", e.Out)
+	fmt.Printf("========================================\n")
+	return nil
+}
+
+// export feeds every chapter of the selected books, in canonical order, to the chosen exporters
+func (e *ExportCmd) export() (ExportStats, error) {
+	var stats ExportStats
+
+	corpus, err := kjvcorpus.Open(e.Canon)
+	if err != nil {
+		return stats, fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	books, err := selectBooks(corpus, e.Book)
+	if err != nil {
+		return stats, err
+	}
+
+	var exporter export.Multi
+	for _, format := range e.Format {
+		ex, err := export.New(format, e.Out)
+		if err != nil {
+			return stats, err
+		}
+		exporter = append(exporter, ex)
+	}
+	err = export.Configure(exporter, export.RenderOptions{
+		VerseNumbers:   export.VerseNumberStyle(e.VerseNumbers),
+		ChapterHeaders: e.ChapterHeaders,
+		DropCap:        e.DropCap,
+		Stylesheet:     e.Stylesheet,
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	if err := exporter.Begin(e.Work); err != nil {
+		return stats, fmt.Errorf("failed to start exporters: %w", err)
+	}
+
+	if len(e.Passage) > 0 {
+		if err := e.exportPassages(corpus, exporter, &stats); err != nil {
+			return stats, err
+		}
+		if err := exporter.Finish(); err != nil {
+			return stats, fmt.Errorf("failed to finish exporters: %w", err)
+		}
+		return stats, nil
+	}
+
+	for _, book := range books {
+		exported := false
+		for chapter := 1; chapter <= book.Chapters; chapter++ {
+			resolved, err := corpus.Resolve(&bibleref.BibleRef{OSIS: book.OSIS, Chapter: chapter})
+			if errors.Is(err, kjvcorpus.ErrChapterNotFound) {
+				// Books such as Additions to Esther only carry some of their chapters
+				continue
+			}
+			if err != nil {
+				return stats, err
+			}
+
+			if err := exporter.WriteChapter(&resolved.Chapter); err != nil {
+				return stats, fmt.Errorf("failed to export %s %d: %w", book.OSIS, chapter, err)
+			}
+			stats.Chapters++
+			exported = true
+		}
+		if exported {
+			stats.Books++
+		}
+	}
+
+	if err := exporter.Finish(); err != nil {
+		return stats, fmt.Errorf("failed to finish exporters: %w", err)
+	}
+	return stats, nil
+}
+
+// exportPassages writes each passage as a chapter holding only its verses and their footnotes.
+// Passages are written in canonical order, so book-per-file formats such as docx write one file
+// per book with every passage selected from it.
+func (e *ExportCmd) exportPassages(corpus *kjvcorpus.Corpus, exporter export.Exporter, stats *ExportStats) error {
+	passages := make([]*kjvcorpus.Resolved, 0, len(e.Passage))
+	for _, s := range e.Passage {
+		ref, err := bibleref.Parse(s, corpus.Table())
+		if err != nil {
+			return fmt.Errorf("invalid passage %q: %w", s, err)
+		}
+		resolved, err := corpus.Resolve(ref)
+		if err != nil {
+			return err
+		}
+		passages = append(passages, resolved)
+	}
+	sort.SliceStable(passages, func(i, j int) bool {
+		return corpus.RefLess(kjvcorpus.RefFrom(passages[i].Ref), kjvcorpus.RefFrom(passages[j].Ref))
+	})
+
+	for i, p := range passages {
+		ch := p.Chapter
+		ch.Verses = p.Verses
+		ch.Footnotes = p.Footnotes
+		if err := exporter.WriteChapter(&ch); err != nil {
+			return fmt.Errorf("failed to export %s: %w", p.Ref, err)
+		}
+		stats.Chapters++
+		if i == 0 || passages[i-1].Chapter.OSIS != p.Chapter.OSIS {
+			stats.Books++
+		}
+	}
+	return nil
+}
+
+// selectBooks returns the named books, or all books when names is empty, in canonical order.
+// Books may be named as ParseRef accepts them, such as "1macc" or "Genesis".
+func selectBooks(corpus *kjvcorpus.Corpus, names []string) ([]bibleref.Book, error) {
+	var books []bibleref.Book
+	if len(names) == 0 {
+		for _, book := range corpus.Table().ByOsis {
+			books = append(books, book)
+		}
+	} else {
+		for _, name := range names {
+			book, ok := corpus.LookupBook(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown book: %s", name)
+			}
+			books = append(books, book)
+		}
+	}
+
+	sort.Slice(books, func(i, j int) bool { return books[i].Order < books[j].Order })
+	return books, nil
+}
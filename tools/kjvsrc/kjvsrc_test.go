@@ -0,0 +1,592 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/internal/clidoc"
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/annotations"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus/httpstore"
+	"github.com/julianstephens/kjv-sources/pkg/search"
+)
+
+// findCanon returns the path to canon/kjv from the project root
+func findCanon(t *testing.T) string {
+	t.Helper()
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get cwd: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(cwd, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(cwd)
+		if parent == cwd {
+			t.Fatal("could not find project root (go.mod)")
+		}
+		cwd = parent
+	}
+	return filepath.Join(cwd, "canon", "kjv")
+}
+
+func TestExport(t *testing.T) {
+	out := t.TempDir()
+	cmd := &ExportCmd{
+		Canon:  findCanon(t),
+		Out:    out,
+		Format: []string{"usfm", "markdown"},
+		Book:   []string{"Obad", "Add Esth"},
+		Work:   "KJV",
+	}
+
+	stats, err := cmd.export()
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if stats.Books != 2 || stats.Chapters < 2 {
+		t.Errorf("expected 2 books and at least 2 chapters, got %+v", stats)
+	}
+
+	for _, path := range []string{"usfm/OBA.usfm", "usfm/ESG.usfm", "markdown/Obad/ch01.md"} {
+		if _, err := os.Stat(filepath.Join(out, filepath.FromSlash(path))); err != nil {
+			t.Errorf("expected %s to be written: %v", path, err)
+		}
+	}
+
+	cmd.Book = []string{"Nope"}
+	if _, err := cmd.export(); err == nil {
+		t.Error("expected error for unknown book")
+	}
+
+	cmd.Book = nil
+	cmd.Format = []string{"markdown", "docx"}
+	cmd.Passage = []string{"Rom 8:28-30", "Gen 1:1-3", "Rom 1:1"}
+	stats, err = cmd.export()
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if stats.Books != 2 || stats.Chapters != 3 {
+		t.Errorf("expected 2 books and 3 chapters, got %+v", stats)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "markdown", "Rom", "ch08.md")) // nolint: gosec
+	if err != nil {
+		t.Fatalf("failed to read passage: %v", err)
+	}
+	if !strings.Contains(string(data), "<sup>28</sup>And we know") || strings.Contains(string(data), "<sup>31</sup>") {
+		t.Errorf("expected only Rom 8:28-30, got:\n%s", data)
+	}
+	for _, path := range []string{"docx/GEN.docx", "docx/ROM.docx"} {
+		if _, err := os.Stat(filepath.Join(out, filepath.FromSlash(path))); err != nil {
+			t.Errorf("expected %s to be written: %v", path, err)
+		}
+	}
+
+	cmd.Passage = []string{"Rom 99:1"}
+	if _, err := cmd.export(); err == nil {
+		t.Error("expected error for an invalid passage")
+	}
+}
+
+func TestQuote(t *testing.T) {
+	corpus, err := kjvcorpus.Open(findCanon(t))
+	if err != nil {
+		t.Fatalf("failed to open canon: %v", err)
+	}
+
+	tests := []struct {
+		cmd  QuoteCmd
+		want string
+	}{
+		{
+			cmd:  QuoteCmd{Style: "flow"},
+			want: "1 In the beginning God created the heaven and the earth. 2 And the earth was without form",
+		},
+		{
+			cmd:  QuoteCmd{Style: "flow", NoNumbers: true},
+			want: "In the beginning God created the heaven and the earth. And the earth",
+		},
+		{
+			cmd:  QuoteCmd{Style: "lines"},
+			want: "1 In the beginning God created the heaven and the earth.\n2 And the earth",
+		},
+		{
+			cmd:  QuoteCmd{Style: "poetry", NoNumbers: true},
+			want: "the face of the waters.\n— Genesis 1:1–2 (KJV)",
+		},
+		{
+			cmd:  QuoteCmd{Style: "flow", DropAdded: true},
+			want: "2 And the earth was without form, and void; and darkness upon the face of the deep.",
+		},
+	}
+	for _, tt := range tests {
+		got, err := tt.cmd.quote(corpus, "Gen 1:1-2")
+		if err != nil {
+			t.Fatalf("quote failed: %v", err)
+		}
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("%+v: expected %q in:\n%s", tt.cmd, tt.want, got)
+		}
+	}
+
+	flow := QuoteCmd{Style: "flow", NoCitation: true}
+	if got, _ := flow.quote(corpus, "John 11:35"); got != "35 Jesus wept." {
+		t.Errorf("expected the bare verse, got %q", got)
+	}
+	if got, _ := (&QuoteCmd{Style: "flow"}).quote(corpus, "John 11:35"); !strings.HasSuffix(got, " — John 11:35 (KJV)") {
+		t.Errorf("expected the citation after the text, got %q", got)
+	}
+	if _, err := (&QuoteCmd{Style: "lines", NoNumbers: true}).quote(corpus, "John 11:35"); err == nil {
+		t.Error("expected lines without numbers to fail")
+	}
+	if _, err := (&QuoteCmd{Style: "flow"}).quote(corpus, "Nope 1:1"); err == nil {
+		t.Error("expected an invalid reference to fail")
+	}
+}
+
+func TestMemorize(t *testing.T) {
+	words := strings.Fields("In the beginning God created the heaven and the earth.")
+	tests := []struct {
+		typed   string
+		correct int
+		missed  string
+	}{
+		{"in the beginning god created the heaven and the earth", 10, ""},
+		{"In the beginning, God created the earth!", 7, "heaven and the"},
+		{"in the very beginning god made the heaven and the earth", 9, "created"},
+		{"", 0, "In the beginning God created the heaven and the earth."},
+	}
+	for _, tt := range tests {
+		score := scoreRecall(words, tt.typed)
+		if score.Correct != tt.correct || score.Total != 10 || strings.Join(score.Missed, " ") != tt.missed {
+			t.Errorf("%q: expected %d of 10 missing %q, got %+v", tt.typed, tt.correct, tt.missed, score)
+		}
+	}
+	if got := blankWord("world,"); got != "_____," {
+		t.Errorf("expected the punctuation kept, got %q", got)
+	}
+
+	corpus, err := kjvcorpus.Open(findCanon(t))
+	if err != nil {
+		t.Fatalf("failed to open canon: %v", err)
+	}
+	ref, err := corpus.ParseRef("John 11:35")
+	if err != nil {
+		t.Fatalf("failed to parse reference: %v", err)
+	}
+	resolved, err := corpus.ResolveRef(ref)
+	if err != nil {
+		t.Fatalf("failed to resolve: %v", err)
+	}
+
+	var out strings.Builder
+	cmd := MemorizeCmd{Rounds: 2, Seed: 1}
+	if err := cmd.practice(resolved, strings.NewReader("\nJesus wept.\nJesus\n"), &out); err != nil {
+		t.Fatalf("practice failed: %v", err)
+	}
+	for _, want := range []string{
+		"Round 1 of 2 (1 of 2 words hidden)",
+		"Round 2 of 2 (2 of 2 words hidden)\n_____ ____.",
+		"Recalled 2 of 2 words (100%)",
+		"Recalled 1 of 2 words (50%)\nMissed: wept.",
+		"Final Recall: 50%",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	result := &search.Result{Hits: []search.Hit{
+		{Reference: "John 11:35", Text: "Jesus wept.", Spans: []search.Span{{Start: 0, End: 5}, {Start: 6, End: 10}},
+			Before: []search.ContextVerse{{V: 34, Text: "And said, Where have ye laid him?"}}},
+		{Reference: "John 11:36", Text: "Then said the Jews, Behold how he loved him!", Spans: []search.Span{{Start: 34, End: 39}}},
+	}}
+
+	var out strings.Builder
+	printHits(&out, result)
+	want := "  34 And said, Where have ye laid him?\n" +
+		"John 11:35 ==Jesus== ==wept==.\n" +
+		"John 11:36 Then said the Jews, Behold how he ==loved== him!\n"
+	if out.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, out.String())
+	}
+}
+
+func TestReadAlignments(t *testing.T) {
+	corpus, err := kjvcorpus.Open(findCanon(t))
+	if err != nil {
+		t.Fatalf("failed to open canon: %v", err)
+	}
+
+	tsv := strings.Join([]string{
+		"# ref\ttext\tlemma\tmorph\tkjv",
+		"John 1:1\tἘν\tG1722\tPREP\t0",
+		"John 1:1\tἀρχῇ\tG746\tN-DSF\t1,2",
+		"",
+		"Genesis 1:1\tבְּרֵאשִׁית\tH7225\tHR/Ncfsa\t0,1,2",
+		"Genesis 1:1\tאֵת\tH853\tHTo\t",
+	}, "\n")
+	alignments, err := readAlignments(strings.NewReader(tsv), corpus, "test", "mixed")
+	if err != nil {
+		t.Fatalf("readAlignments failed: %v", err)
+	}
+	if len(alignments) != 2 || alignments[0].OSIS != "Gen" || alignments[1].OSIS != "John" {
+		t.Fatalf("expected Genesis then John, got %d alignments", len(alignments))
+	}
+	gen := alignments[0].Verses[0]
+	if gen.KJVWords != 10 || len(gen.Words) != 2 || len(gen.Words[1].KJV) != 0 {
+		t.Errorf("unexpected alignment of Gen 1:1: %+v", gen)
+	}
+
+	invalid := []string{
+		"John 1:1\tἘν\tG1722\tPREP",        // missing a field
+		"John 1:1-2\tἘν\tG1722\tPREP\t0",   // not a single verse
+		"John 1:1\tἘν\tG1722\tPREP\tfirst", // not a position
+		"John 1:1\tἘν\tG1722\tPREP\t99",    // past the end of the verse
+		"John 1:99\tἘν\tG1722\tPREP\t0",    // no such verse
+	}
+	for _, line := range invalid {
+		if _, err := readAlignments(strings.NewReader(line), corpus, "test", "grc"); err == nil {
+			t.Errorf("expected %q to be rejected", line)
+		}
+	}
+}
+
+func TestReadEdition(t *testing.T) {
+	corpus, err := kjvcorpus.Open(findCanon(t))
+	if err != nil {
+		t.Fatalf("failed to open canon: %v", err)
+	}
+
+	tsv := strings.Join([]string{
+		"# ref\ttext",
+		"Psalms 117:2\tFor his mercifull kindnesse is great toward vs: and the trueth of the LORD endureth for euer. Praise ye the LORD.",
+		"Psalms 117:1\tO praise the LORD, all yee nations:  praise him, all yee people.",
+	}, "\n")
+	layers, err := readEdition(strings.NewReader(tsv), corpus, "1611", "test")
+	if err != nil {
+		t.Fatalf("readEdition failed: %v", err)
+	}
+	if len(layers) != 1 || layers[0].OSIS != "Ps" || layers[0].Chapter != 117 || len(layers[0].Verses) != 2 {
+		t.Fatalf("expected one layer for Ps 117, got %+v", layers)
+	}
+	if v := layers[0].Verses[0]; v.V != 1 || v.Plain != "O praise the LORD, all yee nations: praise him, all yee people." {
+		t.Errorf("expected verses in order with normalized text, got %+v", v)
+	}
+
+	invalid := []string{
+		"Psalms 117:1\tO praise the LORD",                      // the chapter is incomplete
+		"Psalms 117:1-2\tO praise the LORD",                    // not a single verse
+		"Psalms 117:1",                                         // missing the text
+		"Psalms 117:3\tAmen\nPsalm 117:1\tO\nPsalm 117:2\tFor", // no such verse
+	}
+	for _, line := range invalid {
+		if _, err := readEdition(strings.NewReader(line), corpus, "1611", "test"); err == nil {
+			t.Errorf("expected %q to be rejected", line)
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	handler, err := newServeHandler(findCanon(t), nil, "")
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Documents carry an ETag and honour If-None-Match
+	resp, err := http.Get(server.URL + "/books/1Sam/ch03.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with an ETag, got %s %q", resp.Status, etag)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/books/1Sam/ch03.json", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("expected 304, got %s", resp.Status)
+	}
+
+	for _, path := range []string{"/books/Gen/ch99.json", "/books/../go.mod", "/books/Gen/", "/SHA256SUMS"} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %s", path, resp.Status)
+		}
+	}
+
+	// The reference API
+	resp, err = http.Get(server.URL + "/api/resolve?ref=John+3:16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body struct {
+		OSIS     string `json:"osis"`
+		Citation string `json:"citation"`
+		Verses   []struct {
+			Text string `json:"text"`
+		} `json:"verses"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	_ = resp.Body.Close()
+	if body.OSIS != "John" || body.Citation != "John 3:16 (KJV)" || len(body.Verses) != 1 || !strings.Contains(body.Verses[0].Text, "God so loved") {
+		t.Errorf("unexpected response: %+v", body)
+	}
+
+	// Several references in one request, in the order given
+	resp, err = http.Get(server.URL + "/api/resolve-multi?ref=" + url.QueryEscape("John 3:16; Rom 3:23; Eph 2:8-9"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var multi []struct {
+		Reference string `json:"reference"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&multi); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	_ = resp.Body.Close()
+	if len(multi) != 3 || multi[0].Reference != "John 3:16" || multi[1].Reference != "Romans 3:23" || multi[2].Reference != "Ephesians 2:8–9" {
+		t.Errorf("unexpected response: %+v", multi)
+	}
+	for ref, status := range map[string]int{"John 3:16; Nope 1:1": http.StatusBadRequest, "John 3:16; Rom 3:99": http.StatusNotFound} {
+		resp, err := http.Get(server.URL + "/api/resolve-multi?ref=" + url.QueryEscape(ref))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("%s: expected %d, got %s", ref, status, resp.Status)
+		}
+	}
+
+	// Self-contained quotations with their footnotes
+	resp, err = http.Get(server.URL + "/api/quote?footnotes=true&ref=" + url.QueryEscape("Gen 1:6"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	quoted, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "waters.[1] — Genesis 1:6 (KJV)\n\n[1] firmament: Heb. expansion\n"; !strings.HasSuffix(string(quoted), want) || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Errorf("expected a plain-text quotation ending %q, got %q", want, quoted)
+	}
+	for query, status := range map[string]int{"ref=Nope+1:1": http.StatusBadRequest, "ref=Gen+1:99": http.StatusNotFound, "ref=Gen+1:1&style=nope": http.StatusBadRequest} {
+		resp, err := http.Get(server.URL + "/api/quote?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("%s: expected %d, got %s", query, status, resp.Status)
+		}
+	}
+
+	resp, err = http.Get(server.URL + "/api/search?q=jesus+wept&context=1&limit=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found struct {
+		Total int `json:"total"`
+		Hits  []struct {
+			Reference string `json:"reference"`
+			HTML      string `json:"html"`
+			Before    []struct {
+				V int `json:"v"`
+			} `json:"before"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&found); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	_ = resp.Body.Close()
+	if found.Total != 3 || len(found.Hits) != 1 {
+		t.Fatalf("expected 1 of 3 hits, got %+v", found)
+	}
+	if hit := found.Hits[0]; hit.Reference != "Matthew 26:75" || len(hit.Before) != 1 || hit.Before[0].V != 74 ||
+		!strings.Contains(hit.HTML, "<mark>Jesus</mark>") {
+		t.Errorf("unexpected hit: %+v", hit)
+	}
+	for _, query := range []string{"q=", "q=jesus&context=x", "q=jesus&limit=-1"} {
+		resp, err := http.Get(server.URL + "/api/search?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %s", query, resp.Status)
+		}
+	}
+
+	resp, err = http.Get(server.URL + "/api/occurrences?phrase=holy,+holy,+holy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var occurrences kjvcorpus.PhraseOccurrences
+	if err := json.NewDecoder(resp.Body).Decode(&occurrences); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	_ = resp.Body.Close()
+	if occurrences.Phrase != "holy holy holy" || occurrences.Total != 2 || len(occurrences.Books) != 2 ||
+		occurrences.Verses[1].Slug != "rev/4/8" {
+		t.Errorf("unexpected occurrences: %+v", occurrences)
+	}
+	resp, err = http.Get(server.URL + "/api/occurrences?phrase=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for an empty phrase, got %s", resp.Status)
+	}
+
+	// Permalinks
+	resp, err = http.Get(server.URL + "/api/passage/john/3/16-17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var passage struct {
+		Slug   string            `json:"slug"`
+		Verses []json.RawMessage `json:"verses"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&passage); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	_ = resp.Body.Close()
+	if passage.Slug != "john/3/16-17" || len(passage.Verses) != 2 {
+		t.Errorf("unexpected passage: %+v", passage)
+	}
+	for path, status := range map[string]int{"/api/passage/john/x": http.StatusBadRequest, "/api/passage/john/3/99": http.StatusNotFound} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("%s: expected %d, got %s", path, status, resp.Status)
+		}
+	}
+
+	resp, err = http.Get(server.URL + "/api/resolve?ref=Nope+1:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown book, got %s", resp.Status)
+	}
+
+	// httpstore clients can use the server as their corpus
+	store, err := httpstore.New(server.URL, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	corpus, err := kjvcorpus.Open("", kjvcorpus.WithStore(store))
+	if err != nil {
+		t.Fatalf("failed to open corpus over HTTP: %v", err)
+	}
+	if _, err := corpus.Resolve(&bibleref.BibleRef{OSIS: "1 Sam", Chapter: 3}); err != nil {
+		t.Errorf("failed to resolve over HTTP: %v", err)
+	}
+}
+
+func TestServeAnnotations(t *testing.T) {
+	notes, err := annotations.Open(filepath.Join(t.TempDir(), "annotations.json"))
+	if err != nil {
+		t.Fatalf("failed to open annotations: %v", err)
+	}
+	if _, err := notes.Add(&bibleref.BibleRef{OSIS: "John", Chapter: 3}, annotations.Annotation{Kind: annotations.KindNote, Text: "Nicodemus"}); err != nil {
+		t.Fatal(err)
+	}
+
+	handler, err := newServeHandler(findCanon(t), notes, "")
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/annotations?ref=John+3:16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body struct {
+		Reference   string                   `json:"reference"`
+		Annotations []annotations.Annotation `json:"annotations"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	_ = resp.Body.Close()
+	if len(body.Annotations) != 1 || body.Annotations[0].Text != "Nicodemus" {
+		t.Errorf("unexpected response: %+v", body)
+	}
+
+	// Without a store the endpoint is not registered
+	handler, err = newServeHandler(findCanon(t), nil, "")
+	if err != nil {
+		t.Fatalf("failed to create handler: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/annotations?ref=John+3:16", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without annotations, got %d", rec.Code)
+	}
+}
+
+func TestDocs(t *testing.T) {
+	parser, err := util.NewParser(&CLI{}, options)
+	if err != nil {
+		t.Fatalf("failed to build parser: %v", err)
+	}
+
+	dir := t.TempDir()
+	if _, err := clidoc.WriteManPages(dir, parser.Model, "2025-01-02"); err != nil {
+		t.Fatalf("WriteManPages failed: %v", err)
+	}
+	for _, name := range []string{"kjvsrc.1", "kjvsrc-verify-canon.1", "kjvsrc-site-feed.1", "kjvsrc-completions.1"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to be written: %v", name, err)
+		}
+	}
+
+	for _, shell := range clidoc.Shells {
+		var b strings.Builder
+		if err := clidoc.Completion(&b, parser.Model, shell); err != nil {
+			t.Fatalf("Completion(%s) failed: %v", shell, err)
+		}
+		if !strings.Contains(b.String(), "partial-book") {
+			t.Errorf("expected %s completions to include verify canon flags", shell)
+		}
+	}
+}
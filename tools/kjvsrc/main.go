@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/julianstephens/kjv-sources/internal/analyze"
+	"github.com/julianstephens/kjv-sources/internal/extract"
+	"github.com/julianstephens/kjv-sources/internal/ingest"
+	"github.com/julianstephens/kjv-sources/internal/migrate"
+	"github.com/julianstephens/kjv-sources/internal/packaging"
+	"github.com/julianstephens/kjv-sources/internal/site"
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/internal/verify"
+)
+
+type CLI struct {
+	Ingest   ingest.Cmd    `cmd:"" help:"Process raw HTML chapter files into the canon"`
+	Verify   verify.Cmd    `cmd:"" help:"Verify the raw sources, the canon, and the upstream archive"`
+	Extract  extract.Cmd   `cmd:"" help:"Extract index metadata from the raw sources"`
+	Export   ExportCmd     `cmd:"" help:"Export the canon to other formats without re-ingesting"`
+	Serve    ServeCmd      `cmd:"" help:"Serve the canon over HTTP for httpstore clients"`
+	Site     site.Cmd      `cmd:"" help:"Render the canon as a static site and write reading feeds"`
+	Analyze  analyze.Cmd   `cmd:"" help:"Write word statistics and find parallel passages"`
+	Align    AlignCmd      `cmd:"" help:"Import original-language alignments into the canon"`
+	Edition  EditionCmd    `cmd:"" help:"Import and compare other editions of the text"`
+	Quote    QuoteCmd      `cmd:"" help:"Print or copy passages formatted for quotation"`
+	Memorize MemorizeCmd   `cmd:"" help:"Practice reciting a passage with progressively more words hidden"`
+	Search   SearchCmd     `cmd:"" help:"Find the verses containing every word of a query"`
+	Snapshot SnapshotCmd   `cmd:"" help:"Write an index snapshot for fast kjvcorpus cold starts"`
+	Pack     PackCmd       `cmd:"" help:"Pack the canon into one zip archive for in-memory and browser use"`
+	Package  packaging.Cmd `cmd:"" help:"Build and verify signed, versioned release artifacts of the canon"`
+	Migrate  migrate.Cmd   `cmd:"" help:"Upgrade an existing canon directory in place"`
+
+	Completions CompletionsCmd `cmd:"" help:"Print a shell completion script for kjvsrc"`
+	Docs        DocsCmd        `cmd:"" help:"Write man pages for kjvsrc and its subcommands"`
+}
+
+// options describes kjvsrc to util.RunCLI and util.NewParser
+var options = util.CLIOptions{
+	Name:        "kjvsrc",
+	Description: "KJV source processing tools",
+	Spinners: map[string]string{
+		"ingest":                  "Processing",
+		"extract books":           "Extracting books",
+		"extract aliases":         "Extracting aliases",
+		"export":                  "Exporting",
+		"site build":              "Rendering",
+		"site feed":               "Rendering",
+		"analyze words":           "Analyzing",
+		"analyze parallels":       "Analyzing",
+		"analyze divine-names":    "Analyzing",
+		"analyze added-words":     "Analyzing",
+		"align import":            "Importing",
+		"edition import":          "Importing",
+		"pack":                    "Packing",
+		"package build <version>": "Packaging",
+		"search":                  "Indexing",
+		"migrate osis":            "Migrating",
+		"migrate manifests":       "Migrating",
+	},
+}
+
+func main() {
+	util.RunCLI(&CLI{}, options)
+}
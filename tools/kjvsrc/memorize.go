@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+type MemorizeCmd struct {
+	Ref    string `arg:""             help:"Passage to memorize, such as \"Ps 23:1-3\""`
+	Canon  string `type:"existingdir" help:"The canon directory containing index/ and books/" default:"./canon/kjv"`
+	Rounds int    `                   help:"Rounds of practice; each hides more words, and the last hides all" default:"4"`
+	Seed   int64  `                   help:"Seed for choosing the words to hide, for repeatable practice; 0 picks a new order each run"`
+}
+
+// recall is the score of one round: the passage's words typed back in order
+type recall struct {
+	Correct int
+	Total   int
+	Missed  []string // words of the passage not typed, in passage order
+}
+
+// Percent returns the share of the passage recalled, rounded down
+func (r recall) Percent() int {
+	if r.Total == 0 {
+		return 100
+	}
+	return r.Correct * 100 / r.Total
+}
+
+func (m *MemorizeCmd) Run(ctx context.Context) error {
+	util.StopProgress(ctx)
+
+	corpus, err := kjvcorpus.Open(m.Canon)
+	if err != nil {
+		return fmt.Errorf("failed to open canon: %w", err)
+	}
+	ref, err := corpus.ParseRef(m.Ref)
+	if err != nil {
+		return fmt.Errorf("invalid reference %q: %w", m.Ref, err)
+	}
+	resolved, err := corpus.ResolveRef(ref)
+	if err != nil {
+		return err
+	}
+	return m.practice(resolved, os.Stdin, os.Stdout)
+}
+
+// practice shows the passage, then for each round shows it with a growing share of its words
+// blanked and scores the passage typed back on one line of in
+func (m *MemorizeCmd) practice(resolved *kjvcorpus.Resolved, in io.Reader, out io.Writer) error {
+	if m.Rounds < 1 {
+		return fmt.Errorf("--rounds must be at least 1")
+	}
+	full, words, err := blankPassage(resolved, nil)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("%s has no words to memorize", resolved.Reference())
+	}
+
+	seed := m.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	order := rand.New(rand.NewSource(seed)).Perm(len(words)) // nolint: gosec
+
+	input := bufio.NewScanner(in)
+	input.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	_, _ = fmt.Fprintf(out, "%s\n— %s\n\nRead the passage, then press Enter to begin.\n", full, resolved.Citation())
+	if !input.Scan() {
+		return input.Err()
+	}
+
+	var scores []recall
+	for round := 1; round <= m.Rounds; round++ {
+		hidden := make(map[int]bool)
+		for _, i := range order[:(len(words)*round+m.Rounds-1)/m.Rounds] {
+			hidden[i] = true
+		}
+		blanked, _, err := blankPassage(resolved, hidden)
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(out, "\nRound %d of %d (%d of %d words hidden)\n%s\n— %s\n> ", round, m.Rounds, len(hidden), len(words), blanked, resolved.Citation())
+		if !input.Scan() {
+			if err := input.Err(); err != nil {
+				return err
+			}
+			break
+		}
+
+		score := scoreRecall(words, input.Text())
+		scores = append(scores, score)
+		_, _ = fmt.Fprintf(out, "Recalled %d of %d words (%d%%)\n", score.Correct, score.Total, score.Percent())
+		if len(score.Missed) > 0 {
+			_, _ = fmt.Fprintf(out, "Missed: %s\n", strings.Join(score.Missed, " "))
+		}
+	}
+
+	if len(scores) == 0 {
+		return nil
+	}
+	_, _ = fmt.Fprintln(out, "========================================")
+	_, _ = fmt.Fprintf(out, "Passage: %s\n", resolved.Reference())
+	_, _ = fmt.Fprintf(out, "Rounds: %d\n", len(scores))
+	_, _ = fmt.Fprintf(out, "Final Recall: %d%%\n", scores[len(scores)-1].Percent())
+	_, _ = fmt.Fprintln(out, "========================================")
+	return nil
+}
+
+// blankPassage formats the passage as prose with the words whose positions are in hidden replaced
+// by underscores, keeping their punctuation, and returns the passage's words in order
+func blankPassage(resolved *kjvcorpus.Resolved, hidden map[int]bool) (string, []string, error) {
+	var words []string
+	text, err := resolved.FormatWith(kjvcorpus.PresetParagraph, func(_ model.Verse, text string) string {
+		fields := strings.Fields(text)
+		for i, word := range fields {
+			if hidden[len(words)] {
+				fields[i] = blankWord(word)
+			}
+			words = append(words, word)
+		}
+		return strings.Join(fields, " ")
+	})
+	return text, words, err
+}
+
+// blankWord replaces the letters and digits of a word with underscores
+func blankWord(word string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return '_'
+		}
+		return r
+	}, word)
+}
+
+// recallKey normalizes a word for scoring, ignoring case and surrounding punctuation
+func recallKey(word string) string {
+	return strings.ToLower(strings.TrimFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}))
+}
+
+// scoreRecall scores typed against the passage's words: a word counts when it is typed in order,
+// as the longest common subsequence of the two, so a skipped or extra word costs only itself
+func scoreRecall(words []string, typed string) recall {
+	var want, got, shown []string
+	for _, word := range words {
+		if key := recallKey(word); key != "" {
+			want = append(want, key)
+			shown = append(shown, word)
+		}
+	}
+	for _, word := range strings.Fields(typed) {
+		if key := recallKey(word); key != "" {
+			got = append(got, key)
+		}
+	}
+
+	// lcs[i][j] is the longest common subsequence of want[i:] and got[j:]
+	lcs := make([][]int, len(want)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(got)+1)
+	}
+	for i := len(want) - 1; i >= 0; i-- {
+		for j := len(got) - 1; j >= 0; j-- {
+			if want[i] == got[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	score := recall{Correct: lcs[0][0], Total: len(want)}
+	for i, j := 0, 0; i < len(want); {
+		switch {
+		case j < len(got) && want[i] == got[j]:
+			i++
+			j++
+		case j < len(got) && lcs[i][j+1] >= lcs[i+1][j]:
+			j++
+		default:
+			score.Missed = append(score.Missed, shown[i])
+			i++
+		}
+	}
+	return score
+}
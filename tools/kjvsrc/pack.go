@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/testament"
+)
+
+type PackCmd struct {
+	Output    string   `arg:""             help:"Zip archive to write the packed canon to"`
+	Canon     string   `type:"existingdir" help:"The canon directory containing index/ and books/"              default:"./canon/kjv"`
+	Testament []string `                   help:"Pack only the books of these testaments (OT, AP, NT); repeatable" enum:"OT,AP,NT"`
+}
+
+func (p *PackCmd) Run(ctx context.Context) error {
+	var books []string
+	for _, t := range p.Testament {
+		books = append(books, testament.Books(testament.Testament(t))...)
+	}
+
+	var buf bytes.Buffer
+	var count int
+	var err error
+	if len(books) > 0 {
+		count, err = kjvcorpus.PackBooks(&buf, os.DirFS(p.Canon), books)
+	} else {
+		count, err = kjvcorpus.Pack(&buf, os.DirFS(p.Canon))
+	}
+	if err == nil {
+		err = atomicfile.WriteFile(p.Output, buf.Bytes(), 0600)
+	}
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Documents Packed: %d\n", count)
+	fmt.Printf("Size: %d bytes\n", buf.Len())
+	fmt.Printf("Output: %s\n", p.Output)
+	fmt.Printf("========================================\n")
+	return nil
+}
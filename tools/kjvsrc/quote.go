@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+)
+
+type QuoteCmd struct {
+	Refs       []string `arg:""             help:"References to quote, such as \"Rom 8:28-30\""                                 name:"ref"`
+	Canon      string   `type:"existingdir" help:"The canon directory containing index/ and books/"                             default:"./canon/kjv"`
+	Style      string   `                   help:"Layout: flow (running prose), lines (one numbered line per verse), or poetry" default:"flow"        enum:"flow,lines,poetry"`
+	NoNumbers  bool     `                   help:"Leave verse numbers out"`
+	NoCitation bool     `                   help:"Leave the citation off"`
+	Copy       bool     `                   help:"Copy the quotation to the clipboard instead of printing it"`
+	Edition    string   `                   help:"Edition to quote, such as 1611, if the canon has a layer for it"                default:"1769"`
+	Locale     string   `                   help:"Language to cite book names in, such as es, from index/locales/"`
+	Footnotes  bool     `                   help:"Mark verses with numbered footnotes and list the notes beneath the quotation"`
+	DropAdded  bool     `                   help:"Leave out the added words the source sets in italics, for comparative study"`
+}
+
+// quoteStyles maps each --style to the kjvcorpus preset it is formatted with
+var quoteStyles = map[string]kjvcorpus.Preset{
+	"flow":   kjvcorpus.PresetParagraph,
+	"lines":  kjvcorpus.PresetLines,
+	"poetry": kjvcorpus.PresetPoetry,
+}
+
+func (q *QuoteCmd) Run(ctx context.Context) error {
+	util.StopProgress(ctx)
+
+	var opts []kjvcorpus.Option
+	if q.Locale != "" {
+		opts = append(opts, kjvcorpus.WithLocale(q.Locale))
+	}
+	corpus, err := kjvcorpus.Open(q.Canon, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to open canon: %w", err)
+	}
+
+	quotes := make([]string, 0, len(q.Refs))
+	for _, s := range q.Refs {
+		text, err := q.quote(corpus, s)
+		if err != nil {
+			return err
+		}
+		quotes = append(quotes, text)
+	}
+	text := strings.Join(quotes, "\n\n")
+
+	if !q.Copy {
+		fmt.Println(text)
+		return nil
+	}
+	if err := copyToClipboard(text); err != nil {
+		return err
+	}
+	fmt.Printf("Copied %d passage(s) to the clipboard\n", len(quotes))
+	return nil
+}
+
+// quote resolves one reference and formats it in the chosen style. In the flow style the citation
+// follows the text, as in Resolved.Snippet; in the others it goes on a line of its own. With
+// --footnotes, the notes are listed after a blank line.
+func (q *QuoteCmd) quote(corpus *kjvcorpus.Corpus, s string) (string, error) {
+	preset, ok := quoteStyles[q.Style]
+	if !ok {
+		return "", fmt.Errorf("unknown quote style %q", q.Style)
+	}
+	if preset == kjvcorpus.PresetLines && q.NoNumbers {
+		return "", errors.New("--style=lines always numbers verses; use --style=poetry for one verse per line without numbers")
+	}
+
+	ref, err := corpus.ParseRef(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid reference %q: %w", s, err)
+	}
+	resolved, err := corpus.ResolveWith(ref, kjvcorpus.ResolveOptions{Edition: q.Edition, DropAdded: q.DropAdded})
+	if err != nil {
+		return "", err
+	}
+
+	// PresetLines numbers verses itself; the other presets are numbered through the hook
+	var hook kjvcorpus.VerseHook
+	if preset != kjvcorpus.PresetLines && !q.NoNumbers {
+		hook = func(verse model.Verse, text string) string {
+			return fmt.Sprintf("%d %s", verse.V, text)
+		}
+	}
+	var text string
+	var notes []kjvcorpus.Note
+	if q.Footnotes {
+		text, notes, err = resolved.FormatWithNotes(preset, hook)
+	} else {
+		text, err = resolved.FormatWith(preset, hook)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case q.NoCitation:
+	case preset == kjvcorpus.PresetParagraph:
+		text = fmt.Sprintf("%s — %s", text, resolved.Citation())
+	default:
+		text = fmt.Sprintf("%s\n— %s", text, resolved.Citation())
+	}
+	if len(notes) > 0 {
+		text += "\n\n" + kjvcorpus.FormatNotes(notes)
+	}
+	return text, nil
+}
+
+// clipboardCommands are the commands tried, in order, to copy text to the clipboard on each OS
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+	"linux":   {{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}},
+}
+
+// copyToClipboard pipes text to the first clipboard command installed on this system
+func copyToClipboard(text string) error {
+	for _, args := range clipboardCommands[runtime.GOOS] {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(args[0], args[1:]...) // nolint: gosec
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to copy with %s: %w", args[0], err)
+		}
+		return nil
+	}
+	return fmt.Errorf("no clipboard command found for %s; run without --copy and pipe the output instead", runtime.GOOS)
+}
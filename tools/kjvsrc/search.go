@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/annotations"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/search"
+)
+
+type SearchCmd struct {
+	Query     []string `arg:""             help:"Words every matching verse contains, such as \"living water\""`
+	Canon     string   `type:"existingdir" help:"The canon directory containing index/ and books/"                                default:"./canon/kjv"`
+	Context   int      `                   help:"Verses of context to print on each side of a hit"                                default:"0"           short:"C"`
+	Limit     int      `                   help:"Most hits to print; 0 prints every hit"                                          default:"20"`
+	Fold      bool     `                   help:"Fold early modern English forms to modern ones, so \"shows\" finds \"sheweth\""`
+	Stem      bool     `                   help:"Match words by their stems, so \"love\" finds \"loved\" and \"loving\""`
+	StopWords string   `                   help:"Stop-word list to leave out of the index and the query: none, english, or kjv"   default:"none"        enum:"none,english,kjv"`
+	Index     string   `                   help:"Index file to search; built with the options above and saved there when missing"`
+}
+
+func (s *SearchCmd) Run(ctx context.Context) error {
+	corpus, err := kjvcorpus.Open(s.Canon)
+	if err != nil {
+		util.StopProgress(ctx)
+		return fmt.Errorf("failed to open canon: %w", err)
+	}
+	ix, err := loadSearchIndex(corpus, s.Index, s.analyzer())
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := ix.Search(strings.Join(s.Query, " "), search.Options{Context: s.Context, Limit: s.Limit})
+	if err != nil {
+		return err
+	}
+	printHits(os.Stdout, result)
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Query: %s\n", result.Query)
+	fmt.Printf("Analysis: %s\n", ix.Analyzer())
+	fmt.Printf("Verses Matching: %d\n", result.Total)
+	if len(result.Hits) < result.Total {
+		fmt.Printf("Shown: %d (raise --limit to see more)\n", len(result.Hits))
+	}
+	fmt.Printf("========================================\n")
+	return nil
+}
+
+// analyzer returns the analyzer the options select
+func (s *SearchCmd) analyzer() search.Analyzer {
+	analyzer := search.Analyzer{Fold: s.Fold, Stem: s.Stem}
+	if s.StopWords != "none" {
+		analyzer.StopWords, _ = search.StopWords(s.StopWords)
+	}
+	return analyzer
+}
+
+// loadSearchIndex reads the search index at path, or builds one with analyzer and writes it there
+// when the file does not exist. Without a path the index is built and not saved.
+func loadSearchIndex(corpus *kjvcorpus.Corpus, path string, analyzer search.Analyzer) (*search.Index, error) {
+	if path == "" {
+		return search.Build(corpus, analyzer)
+	}
+
+	f, err := os.Open(path) // nolint: gosec
+	if err == nil {
+		defer func() { _ = f.Close() }()
+		ix, err := search.Read(f, corpus)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return ix, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	ix, err := search.Build(corpus, analyzer)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err := ix.Write(&b); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := atomicfile.WriteFile(path, b.Bytes(), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return ix, nil
+}
+
+// printHits writes each hit as its reference and its text with matching words between the
+// annotation highlight marks. Context verses are printed by number around the hit,
+// with a blank line between hits that have context.
+func printHits(w io.Writer, result *search.Result) {
+	for i, hit := range result.Hits {
+		withContext := len(hit.Before) > 0 || len(hit.After) > 0
+		if withContext && i > 0 {
+			fmt.Fprintln(w)
+		}
+		for _, verse := range hit.Before {
+			fmt.Fprintf(w, "  %d %s\n", verse.V, verse.Text)
+		}
+		fmt.Fprintf(w, "%s %s\n", hit.Reference, hit.Highlight(annotations.HighlightOpen, annotations.HighlightClose))
+		for _, verse := range hit.After {
+			fmt.Fprintf(w, "  %d %s\n", verse.V, verse.Text)
+		}
+	}
+}
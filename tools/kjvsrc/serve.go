@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/julianstephens/canonref/bibleref"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/annotations"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+	"github.com/julianstephens/kjv-sources/pkg/model"
+	"github.com/julianstephens/kjv-sources/pkg/search"
+)
+
+// searchLimit is the most hits /api/search returns when the request does not set limit
+const searchLimit = 50
+
+type ServeCmd struct {
+	Canon       string `type:"existingdir" help:"The canon directory containing index/ and books/"                 default:"./canon/kjv"`
+	Addr        string `                   help:"Address to listen on"                                             default:"localhost:8080"`
+	Annotations string `                   help:"Annotations file to serve at /api/annotations (empty to disable)"`
+	SearchIndex string `                   help:"Index file for /api/search, as kjvsrc search --index writes it; built in memory when empty"`
+}
+
+func (s *ServeCmd) Run(ctx context.Context) error {
+	var notes *annotations.Store
+	if s.Annotations != "" {
+		var err error
+		if notes, err = annotations.Open(s.Annotations); err != nil {
+			util.StopProgress(ctx)
+			return err
+		}
+	}
+
+	handler, err := newServeHandler(s.Canon, notes, s.SearchIndex)
+	util.StopProgress(ctx)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              s.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	fmt.Printf("Serving %s on http://%s/\n", s.Canon, s.Addr)
+	return server.ListenAndServe()
+}
+
+// newServeHandler serves the canon layout (index/, books/, and objects/) and, for a canon extracted
+// from a release, its release files with content-hash ETags, which httpstore uses to revalidate
+// its cache, and resolves references at /api/resolve?ref=... and
+// permalinks at /api/passage/{slug}, such as /api/passage/john/3/16-18. /api/quote?ref=... returns
+// a passage as plain text formatted as kjvsrc quote formats it, and /api/search?q=... searches the
+// text with the index at searchIndex, or one built in memory, loaded on the first search.
+// /api/occurrences?phrase=... lists the verses containing an exact phrase, with counts by book.
+// With an annotations store, the annotations on a reference are served at /api/annotations?ref=...
+func newServeHandler(canonDir string, notes *annotations.Store, searchIndex string) (http.Handler, error) {
+	corpus, err := kjvcorpus.Open(canonDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open canon: %w", err)
+	}
+	fsys := os.DirFS(canonDir)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /index/", func(w http.ResponseWriter, r *http.Request) {
+		serveDocument(w, r, fsys)
+	})
+	mux.HandleFunc("GET /books/", func(w http.ResponseWriter, r *http.Request) {
+		serveDocument(w, r, fsys)
+	})
+	mux.HandleFunc("GET /objects/", func(w http.ResponseWriter, r *http.Request) {
+		serveDocument(w, r, fsys)
+	})
+	for name := range releaseTypes {
+		mux.HandleFunc("GET /"+name, func(w http.ResponseWriter, r *http.Request) {
+			serveDocument(w, r, fsys)
+		})
+	}
+	mux.HandleFunc("GET /api/resolve", func(w http.ResponseWriter, r *http.Request) {
+		serveResolve(w, r, corpus)
+	})
+	mux.HandleFunc("GET /api/resolve-multi", func(w http.ResponseWriter, r *http.Request) {
+		serveResolveMulti(w, r, corpus)
+	})
+	mux.HandleFunc("GET /api/passage/{slug...}", func(w http.ResponseWriter, r *http.Request) {
+		servePassage(w, r, corpus)
+	})
+	mux.HandleFunc("GET /api/quote", func(w http.ResponseWriter, r *http.Request) {
+		serveQuote(w, r, corpus)
+	})
+	index := sync.OnceValues(func() (*search.Index, error) {
+		return loadSearchIndex(corpus, searchIndex, search.Analyzer{})
+	})
+	mux.HandleFunc("GET /api/search", func(w http.ResponseWriter, r *http.Request) {
+		serveSearch(w, r, index)
+	})
+	mux.HandleFunc("GET /api/occurrences", func(w http.ResponseWriter, r *http.Request) {
+		serveOccurrences(w, r, corpus)
+	})
+	if notes != nil {
+		mux.HandleFunc("GET /api/annotations", func(w http.ResponseWriter, r *http.Request) {
+			serveAnnotations(w, r, corpus, notes)
+		})
+	}
+	return mux, nil
+}
+
+// documentTypes are the content types of the documents serveDocument serves, by extension; the
+// chapter files of a compressed canon are served as they are, for httpstore to decompress
+var documentTypes = map[string]string{
+	".json": "application/json",
+	".gz":   "application/gzip",
+	".zst":  "application/zstd",
+}
+
+// releaseTypes are the content types of the release files at the root of a canon extracted from a
+// release artifact, which httpstore reads for kjvcorpus.WithSignatureVerification
+var releaseTypes = map[string]string{
+	model.ReleaseManifestName: "application/json",
+	model.ChecksumsName:       "text/plain; charset=utf-8",
+	model.SignatureName:       "application/json",
+}
+
+// serveDocument serves a JSON document or release file from the canon with an ETag of its SHA-256
+// hash
+func serveDocument(w http.ResponseWriter, r *http.Request, fsys fs.FS) {
+	name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+	contentType, ok := documentTypes[path.Ext(name)]
+	if release, isRelease := releaseTypes[name]; isRelease {
+		contentType, ok = release, true
+	}
+	if !fs.ValidPath(name) || !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	info, err := fs.Stat(fsys, name)
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		http.Error(w, "failed to read document", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", fmt.Sprintf(`"%x"`, sha256.Sum256(data)))
+	w.Header().Set("Content-Type", contentType)
+	http.ServeContent(w, r, name, info.ModTime(), bytes.NewReader(data))
+}
+
+// serveResolve resolves the ref query parameter and returns its verses and footnotes as JSON
+func serveResolve(w http.ResponseWriter, r *http.Request, corpus *kjvcorpus.Corpus) {
+	ref, err := corpus.ParseRef(r.URL.Query().Get("ref"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid reference: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	resolved, err := corpus.ResolveRef(ref)
+	writeResolved(w, resolved, err)
+}
+
+// serveResolveMulti resolves the semicolon-separated references of the ref query parameter, such
+// as "John 3:16; Rom 3:23", and returns them as a JSON array in the order given
+func serveResolveMulti(w http.ResponseWriter, r *http.Request, corpus *kjvcorpus.Corpus) {
+	refs, err := corpus.ParseRefs(r.URL.Query().Get("ref"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid reference: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	resolved, err := corpus.ResolveRefs(refs)
+	writeResolved(w, resolved, err)
+}
+
+// servePassage resolves the slug in the path, as written by kjvcorpus.Ref.Slug, and returns it as
+// serveResolve does
+func servePassage(w http.ResponseWriter, r *http.Request, corpus *kjvcorpus.Corpus) {
+	ref, err := corpus.ParseSlug(r.PathValue("slug"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid passage: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	resolved, err := corpus.ResolveRef(ref)
+	writeResolved(w, resolved, err)
+}
+
+// serveQuote formats the ref query parameter as kjvsrc quote does, taking its style, edition,
+// footnotes, and drop_added options from the query, and returns it as plain text
+func serveQuote(w http.ResponseWriter, r *http.Request, corpus *kjvcorpus.Corpus) {
+	query := r.URL.Query()
+	q := QuoteCmd{
+		Style:     query.Get("style"),
+		Edition:   query.Get("edition"),
+		Footnotes: query.Get("footnotes") == "true" || query.Get("footnotes") == "1",
+		DropAdded: query.Get("drop_added") == "true" || query.Get("drop_added") == "1",
+	}
+	if q.Style == "" {
+		q.Style = "flow"
+	}
+
+	text, err := q.quote(corpus, query.Get("ref"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, kjvcorpus.ErrUnknownBook) || errors.Is(err, kjvcorpus.ErrChapterNotFound) || errors.Is(err, kjvcorpus.ErrVerseOutOfRange) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err := fmt.Fprintln(w, text); err != nil {
+		fmt.Printf("Error writing response: %v\n", err)
+	}
+}
+
+// searchHit is a search hit as /api/search returns it, with its text highlighted for HTML
+type searchHit struct {
+	search.Hit
+	HTML string `json:"html"`
+}
+
+// serveSearch searches for the words of the q query parameter and returns the hits as JSON, with
+// context verses and a limit from the context and limit parameters
+func serveSearch(w http.ResponseWriter, r *http.Request, index func() (*search.Index, error)) {
+	query := r.URL.Query()
+	opts := search.Options{Limit: searchLimit}
+	for name, value := range map[string]*int{"context": &opts.Context, "limit": &opts.Limit} {
+		if s := query.Get(name); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				http.Error(w, fmt.Sprintf("invalid %s %q", name, s), http.StatusBadRequest)
+				return
+			}
+			*value = n
+		}
+	}
+
+	ix, err := index()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	result, err := ix.Search(query.Get("q"), opts)
+	if errors.Is(err, search.ErrEmptyQuery) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	out := struct {
+		Query string      `json:"query"`
+		Total int         `json:"total"`
+		Hits  []searchHit `json:"hits"`
+	}{Query: result.Query, Total: result.Total, Hits: make([]searchHit, len(result.Hits))}
+	for i, hit := range result.Hits {
+		out.Hits[i] = searchHit{Hit: hit, HTML: hit.HighlightHTML()}
+	}
+	writeResolved(w, out, nil)
+}
+
+// serveOccurrences returns the occurrences of the phrase query parameter as JSON
+func serveOccurrences(w http.ResponseWriter, r *http.Request, corpus *kjvcorpus.Corpus) {
+	found, err := corpus.Occurrences(r.URL.Query().Get("phrase"))
+	if errors.Is(err, kjvcorpus.ErrEmptyPhrase) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeResolved(w, found, err)
+}
+
+// writeResolved writes one or more resolved passages as JSON, or the error resolving them, with 404
+// for passages outside the canon
+func writeResolved(w http.ResponseWriter, resolved any, err error) {
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, kjvcorpus.ErrUnknownBook) || errors.Is(err, kjvcorpus.ErrChapterNotFound) || errors.Is(err, kjvcorpus.ErrVerseOutOfRange) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resolved); err != nil {
+		fmt.Printf("Error writing response: %v\n", err)
+	}
+}
+
+// serveAnnotations returns the annotations on any verse of the ref query parameter
+func serveAnnotations(w http.ResponseWriter, r *http.Request, corpus *kjvcorpus.Corpus, notes *annotations.Store) {
+	ref, err := bibleref.Parse(r.URL.Query().Get("ref"), corpus.Books)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid reference: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	list := notes.List(ref)
+	if list == nil {
+		list = []annotations.Annotation{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"reference": ref.String(), "annotations": list}); err != nil {
+		fmt.Printf("Error writing response: %v\n", err)
+	}
+}
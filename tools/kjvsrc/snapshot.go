@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/julianstephens/kjv-sources/internal/util"
+	"github.com/julianstephens/kjv-sources/pkg/atomicfile"
+	"github.com/julianstephens/kjv-sources/pkg/kjvcorpus"
+)
+
+type SnapshotCmd struct {
+	Output string `arg:""             help:"File to write the snapshot to"`
+	Canon  string `type:"existingdir" help:"The canon directory containing index/ and books/" default:"./canon/kjv"`
+}
+
+func (s *SnapshotCmd) Run(ctx context.Context) error {
+	util.StopProgress(ctx)
+
+	corpus, err := kjvcorpus.Open(s.Canon)
+	if err != nil {
+		return fmt.Errorf("failed to open canon: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := corpus.WriteSnapshot(&buf); err != nil {
+		return err
+	}
+	if err := atomicfile.WriteFile(s.Output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.Output, err)
+	}
+
+	fmt.Printf("========================================\n")
+	fmt.Printf("Books: %d\n", len(corpus.Table().ByOsis))
+	fmt.Printf("Size: %d bytes\n", buf.Len())
+	fmt.Printf("Output: %s\n", s.Output)
+	fmt.Printf("========================================\n")
+	return nil
+}
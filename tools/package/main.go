@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/julianstephens/kjv-sources/internal/packaging"
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func main() {
+	util.RunCLI(&packaging.Cmd{}, util.CLIOptions{
+		Name:        "kjv-package",
+		Description: "KJV Release Packaging Tool",
+		Config:      "package",
+		Spinners:    map[string]string{"build <version>": "Packaging"},
+	})
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"github.com/julianstephens/kjv-sources/internal/site"
+	"github.com/julianstephens/kjv-sources/internal/util"
+)
+
+func main() {
+	util.RunCLI(&site.Cmd{}, util.CLIOptions{
+		Name:        "kjv-site",
+		Description: "KJV Static Site Generator",
+		Config:      "site",
+		Spinners:    map[string]string{"build": "Rendering", "feed": "Rendering"},
+		Deprecated:  "kjvsrc site",
+	})
+}